@@ -0,0 +1,260 @@
+package eorm
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AggregateConfig defines a named pre-computed aggregate: the result of SQL is stored in Target
+type AggregateConfig struct {
+	SQL      string        // SELECT statement producing the aggregate rows
+	Args     []interface{} // Arguments for SQL
+	Target   string        // Table holding the pre-computed rows, replaced on every refresh
+	Columns  []string      // Target columns in SELECT order, empty means INSERT INTO target SELECT ...
+	Interval time.Duration // Refresh interval of the background scheduler, 0 means refresh on demand only
+}
+
+// aggregateEntry is a registered aggregate and its scheduler
+type aggregateEntry struct {
+	config      AggregateConfig
+	stop        chan struct{}
+	refreshMu   sync.Mutex // Serializes refreshes of the same aggregate
+	lastRefresh time.Time
+}
+
+// aggregateRegistry stores named aggregates per database
+type aggregateRegistry struct {
+	entries map[string]*aggregateEntry // name -> aggregate
+	mu      sync.RWMutex
+}
+
+// newAggregateRegistry creates a new aggregate registry
+func newAggregateRegistry() *aggregateRegistry {
+	return &aggregateRegistry{
+		entries: make(map[string]*aggregateEntry),
+	}
+}
+
+// --- Global Functions (for default database) ---
+
+// RegisterAggregate registers a named aggregate whose rows are pre-computed into config.Target.
+// With config.Interval > 0 the aggregate is refreshed in the background on that interval;
+// RefreshAggregate refreshes it on demand. Registering an existing name replaces it.
+// Example:
+//
+//	eorm.RegisterAggregate("daily_sales", eorm.AggregateConfig{
+//	    SQL:      "SELECT DATE(created_at) AS day, SUM(amount) AS total FROM orders GROUP BY DATE(created_at)",
+//	    Target:   "daily_sales",
+//	    Columns:  []string{"day", "total"},
+//	    Interval: 10 * time.Minute,
+//	})
+func RegisterAggregate(name string, config AggregateConfig) error {
+	db, err := defaultDB()
+	if err != nil {
+		return err
+	}
+	return db.RegisterAggregate(name, config)
+}
+
+// RemoveAggregate removes a named aggregate and stops its scheduler, the target table is kept
+func RemoveAggregate(name string) {
+	db, err := defaultDB()
+	if err != nil {
+		return
+	}
+	db.RemoveAggregate(name)
+}
+
+// RefreshAggregate recomputes a named aggregate and returns the number of rows written
+func RefreshAggregate(name string) (int64, error) {
+	db, err := defaultDB()
+	if err != nil {
+		return 0, err
+	}
+	return db.RefreshAggregate(name)
+}
+
+// AggregateLastRefresh returns the time of the last successful refresh (zero if never refreshed)
+func AggregateLastRefresh(name string) time.Time {
+	db, err := defaultDB()
+	if err != nil {
+		return time.Time{}
+	}
+	return db.AggregateLastRefresh(name)
+}
+
+// --- DB Methods ---
+
+// RegisterAggregate registers a named aggregate for this database
+func (db *DB) RegisterAggregate(name string, config AggregateConfig) error {
+	if db.lastErr != nil {
+		return db.lastErr
+	}
+	if name == "" {
+		return fmt.Errorf("eorm: aggregate name is required")
+	}
+	if strings.TrimSpace(config.SQL) == "" {
+		return fmt.Errorf("eorm: aggregate %q requires SQL", name)
+	}
+	if err := validateIdentifier(config.Target); err != nil {
+		return err
+	}
+	for _, col := range config.Columns {
+		if err := validateIdentifier(col); err != nil {
+			return err
+		}
+	}
+	db.dbMgr.setAggregate(name, config)
+	return nil
+}
+
+// RemoveAggregate removes a named aggregate and stops its scheduler
+func (db *DB) RemoveAggregate(name string) *DB {
+	if db.lastErr != nil {
+		return db
+	}
+	db.dbMgr.removeAggregate(name)
+	return db
+}
+
+// RefreshAggregate recomputes a named aggregate in a transaction:
+// the target rows are deleted and re-inserted from SQL, so readers see either the old or the new rows
+func (db *DB) RefreshAggregate(name string) (int64, error) {
+	if db.lastErr != nil {
+		return 0, db.lastErr
+	}
+	return db.dbMgr.refreshAggregate(name)
+}
+
+// AggregateLastRefresh returns the time of the last successful refresh (zero if never refreshed)
+func (db *DB) AggregateLastRefresh(name string) time.Time {
+	if db.lastErr != nil || db.dbMgr.aggregates == nil {
+		return time.Time{}
+	}
+	entry := db.dbMgr.aggregates.get(name)
+	if entry == nil {
+		return time.Time{}
+	}
+	entry.refreshMu.Lock()
+	defer entry.refreshMu.Unlock()
+	return entry.lastRefresh
+}
+
+// --- dbManager Methods ---
+
+// get returns a registered aggregate
+func (r *aggregateRegistry) get(name string) *aggregateEntry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.entries[name]
+}
+
+// setAggregate registers an aggregate and (re)starts its scheduler
+func (mgr *dbManager) setAggregate(name string, config AggregateConfig) {
+	if mgr.aggregates == nil {
+		mgr.aggregates = newAggregateRegistry()
+	}
+	r := mgr.aggregates
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if old, ok := r.entries[name]; ok && old.stop != nil {
+		close(old.stop)
+	}
+	entry := &aggregateEntry{config: config}
+	if config.Interval > 0 {
+		entry.stop = make(chan struct{})
+		go mgr.runAggregateScheduler(name, config.Interval, entry.stop)
+	}
+	r.entries[name] = entry
+}
+
+// removeAggregate removes an aggregate and stops its scheduler
+func (mgr *dbManager) removeAggregate(name string) {
+	if mgr.aggregates == nil {
+		return
+	}
+	r := mgr.aggregates
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if entry, ok := r.entries[name]; ok {
+		if entry.stop != nil {
+			close(entry.stop)
+		}
+		delete(r.entries, name)
+	}
+}
+
+// stopAggregateSchedulers stops all aggregate schedulers of the database
+func (mgr *dbManager) stopAggregateSchedulers() {
+	if mgr.aggregates == nil {
+		return
+	}
+	r := mgr.aggregates
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, entry := range r.entries {
+		if entry.stop != nil {
+			close(entry.stop)
+			entry.stop = nil
+		}
+	}
+}
+
+// runAggregateScheduler periodically refreshes an aggregate until stopped
+func (mgr *dbManager) runAggregateScheduler(name string, interval time.Duration, stop chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if _, err := mgr.refreshAggregate(name); err != nil {
+				LogWarn("聚合刷新失败", NewRecord().
+					Set("db", mgr.name).
+					Set("aggregate", name).
+					Set("error", err.Error()))
+			}
+		}
+	}
+}
+
+// refreshAggregate replaces the target rows with the aggregate result inside a transaction
+func (mgr *dbManager) refreshAggregate(name string) (affected int64, err error) {
+	var entry *aggregateEntry
+	if mgr.aggregates != nil {
+		entry = mgr.aggregates.get(name)
+	}
+	if entry == nil {
+		return 0, fmt.Errorf("eorm: aggregate %q is not registered", name)
+	}
+	entry.refreshMu.Lock()
+	defer entry.refreshMu.Unlock()
+
+	config := entry.config
+	insertSQL := fmt.Sprintf("INSERT INTO %s %s", config.Target, config.SQL)
+	if len(config.Columns) > 0 {
+		insertSQL = fmt.Sprintf("INSERT INTO %s (%s) %s", config.Target, joinStrings(config.Columns), config.SQL)
+	}
+
+	db := &DB{dbMgr: mgr}
+	err = db.Transaction(func(tx *Tx) error {
+		if _, err := tx.Exec(fmt.Sprintf("DELETE FROM %s", config.Target)); err != nil {
+			return err
+		}
+		result, err := tx.Exec(insertSQL, config.Args...)
+		if err != nil {
+			return err
+		}
+		affected, _ = result.RowsAffected()
+		return nil
+	})
+	mgr.afterTableWrite(nil, config.Target, err)
+	if err != nil {
+		return 0, fmt.Errorf("eorm: refresh aggregate %q failed: %v", name, err)
+	}
+	entry.lastRefresh = time.Now()
+	return affected, nil
+}
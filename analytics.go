@@ -0,0 +1,149 @@
+package eorm
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// HistogramBucket 直方图的一个区间 [Lower, Upper)，首个区间的 Lower 为 -Inf，最后一个区间的 Upper 为 +Inf
+type HistogramBucket struct {
+	Lower float64
+	Upper float64
+	Count int64
+}
+
+// aggregateClone 复制构建器用于聚合查询：保留条件、JOIN 和作用域，去掉排序、分页和分组
+func (qb *QueryBuilder) aggregateClone() *QueryBuilder {
+	c := qb.Clone()
+	c.orderBy = ""
+	c.orderTerms = nil
+	c.limit = 0
+	c.offset = 0
+	c.groupBy = ""
+	c.havingSql = nil
+	c.havingArgs = nil
+	c.orHavingSql = nil
+	c.orHavingArgs = nil
+	return c
+}
+
+// Percentile 计算列在当前条件下的连续百分位数（与 PERCENTILE_CONT 相同的线性插值），p 取值 0~1，NULL 值不参与计算
+// PostgreSQL、Oracle 使用 PERCENTILE_CONT ... WITHIN GROUP，SQL Server 使用 PERCENTILE_CONT ... OVER ()，
+// MySQL、SQLite 没有百分位函数，先计数再按位置读取相邻两行插值
+// 没有非 NULL 值时返回 sql.ErrNoRows
+// 示例:
+//
+//	p95, err := eorm.Table("orders").Where("created_at >= ?", since).Percentile("amount", 0.95)
+func (qb *QueryBuilder) Percentile(column string, p float64) (float64, error) {
+	if qb.lastErr != nil {
+		return 0, qb.lastErr
+	}
+	if p < 0 || p > 1 || math.IsNaN(p) {
+		return 0, fmt.Errorf("eorm: percentile must be between 0 and 1, got %v", p)
+	}
+	if err := validateIdentifier(column); err != nil {
+		return 0, err
+	}
+	pText := strconv.FormatFloat(p, 'f', -1, 64)
+
+	var selectSQL string
+	switch qb.getDriverType() {
+	case PostgreSQL, Oracle:
+		selectSQL = fmt.Sprintf("PERCENTILE_CONT(%s) WITHIN GROUP (ORDER BY %s) AS eorm_value", pText, column)
+	case SQLServer:
+		selectSQL = fmt.Sprintf("DISTINCT PERCENTILE_CONT(%s) WITHIN GROUP (ORDER BY %s) OVER () AS eorm_value", pText, column)
+	default:
+		return qb.percentileByOffset(column, p)
+	}
+
+	records, err := qb.aggregateClone().Select(selectSQL).Query()
+	if err != nil {
+		return 0, err
+	}
+	if len(records) == 0 || records[0].Get("eorm_value") == nil {
+		return 0, sql.ErrNoRows
+	}
+	return records[0].GetFloat("eorm_value"), nil
+}
+
+// percentileByOffset 不支持百分位函数时：计数后读取第 floor((n-1)*p) 和下一行做线性插值
+func (qb *QueryBuilder) percentileByOffset(column string, p float64) (float64, error) {
+	n, err := qb.aggregateClone().WhereNotNull(column).Count()
+	if err != nil {
+		return 0, err
+	}
+	if n == 0 {
+		return 0, sql.ErrNoRows
+	}
+	pos := float64(n-1) * p
+	lower := int(math.Floor(pos))
+	records, err := qb.aggregateClone().WhereNotNull(column).
+		Select(column + " AS eorm_value").OrderBy(column).Offset(lower).Limit(2).Query()
+	if err != nil {
+		return 0, err
+	}
+	if len(records) == 0 {
+		return 0, sql.ErrNoRows
+	}
+	value := records[0].GetFloat("eorm_value")
+	if frac := pos - float64(lower); frac > 0 && len(records) > 1 {
+		value += (records[1].GetFloat("eorm_value") - value) * frac
+	}
+	return value, nil
+}
+
+// Histogram 按升序边界统计列值的分布，n 个边界生成 n+1 个区间：(-Inf, b0)、[b0, b1) ... [bn-1, +Inf)，NULL 值不计入
+// 使用 SUM(CASE WHEN ...) 一次查询完成，适用于全部数据库
+// 示例:
+//
+//	buckets, err := eorm.Table("users").Histogram("age", []float64{18, 30, 45, 60})
+func (qb *QueryBuilder) Histogram(column string, bounds []float64) ([]HistogramBucket, error) {
+	if qb.lastErr != nil {
+		return nil, qb.lastErr
+	}
+	if err := validateIdentifier(column); err != nil {
+		return nil, err
+	}
+	if len(bounds) == 0 {
+		return nil, fmt.Errorf("eorm: histogram requires at least one bucket boundary")
+	}
+	for i, b := range bounds {
+		if math.IsNaN(b) || math.IsInf(b, 0) {
+			return nil, fmt.Errorf("eorm: histogram boundary %d is not a finite number", i)
+		}
+		if i > 0 && b <= bounds[i-1] {
+			return nil, fmt.Errorf("eorm: histogram boundaries must be strictly ascending")
+		}
+	}
+
+	buckets := make([]HistogramBucket, len(bounds)+1)
+	parts := make([]string, len(buckets))
+	for i := range buckets {
+		lower, upper := math.Inf(-1), math.Inf(1)
+		var conds []string
+		if i > 0 {
+			lower = bounds[i-1]
+			conds = append(conds, fmt.Sprintf("%s >= %s", column, strconv.FormatFloat(lower, 'f', -1, 64)))
+		}
+		if i < len(bounds) {
+			upper = bounds[i]
+			conds = append(conds, fmt.Sprintf("%s < %s", column, strconv.FormatFloat(upper, 'f', -1, 64)))
+		}
+		buckets[i] = HistogramBucket{Lower: lower, Upper: upper}
+		parts[i] = fmt.Sprintf("SUM(CASE WHEN %s THEN 1 ELSE 0 END) AS eorm_b%d", strings.Join(conds, " AND "), i)
+	}
+
+	records, err := qb.aggregateClone().Select(strings.Join(parts, ", ")).Query()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) > 0 {
+		for i := range buckets {
+			buckets[i].Count = records[0].GetInt64(fmt.Sprintf("eorm_b%d", i))
+		}
+	}
+	return buckets, nil
+}
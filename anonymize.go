@@ -0,0 +1,252 @@
+package eorm
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+// AnonymizeStrategy defines how a column value is rewritten
+type AnonymizeStrategy int
+
+const (
+	// AnonymizeFakeName replaces the value with a fake full name derived from the primary key
+	AnonymizeFakeName AnonymizeStrategy = iota
+	// AnonymizeFakeEmail replaces the value with a fake email address derived from the primary key
+	AnonymizeFakeEmail
+	// AnonymizeHash replaces the value with a salted SHA-256 hex digest of the original value,
+	// equal values stay equal so joins on the column still match
+	AnonymizeHash
+	// AnonymizeNullify sets the value to NULL
+	AnonymizeNullify
+	// AnonymizeShuffle permutes the column values between the rows of each batch
+	AnonymizeShuffle
+	// AnonymizeCustom uses AnonymizeColumn.Func
+	AnonymizeCustom
+)
+
+// AnonymizeColumn configures one column of an anonymization plan
+type AnonymizeColumn struct {
+	Column   string
+	Strategy AnonymizeStrategy
+	Func     func(value interface{}, row *Record) interface{} // Used by AnonymizeCustom
+}
+
+// AnonymizeTable configures the columns to rewrite in one table
+type AnonymizeTable struct {
+	Table   string
+	Columns []AnonymizeColumn
+	Where   string        // Optional filter, empty means all rows
+	Args    []interface{} // Arguments for Where
+}
+
+// AnonymizePlan describes an anonymization run
+type AnonymizePlan struct {
+	Tables    []AnonymizeTable
+	BatchSize int    // Rows read and updated per transaction, <= 0 uses DefaultBatchSize
+	Salt      string // Salt for AnonymizeHash and the fake value generators
+}
+
+var (
+	anonymizeFirstNames = []string{"James", "Mary", "John", "Linda", "Robert", "Susan", "Michael", "Karen", "David", "Lisa", "Daniel", "Emma", "Wei", "Fang", "Lei", "Na"}
+	anonymizeLastNames  = []string{"Smith", "Johnson", "Brown", "Taylor", "Miller", "Wilson", "Moore", "Clark", "Lewis", "Walker", "Wang", "Li", "Zhang", "Liu", "Chen", "Yang"}
+)
+
+// Anonymize rewrites the configured columns of the default database and returns the number of rows updated.
+// Tables are walked in primary key order (a single-column primary key is required) and updated in
+// batches, each batch in its own transaction. Soft-deleted, expired and globally scoped rows are included.
+// Example:
+//
+//	n, err := eorm.Anonymize(eorm.AnonymizePlan{
+//	    Salt: "staging",
+//	    Tables: []eorm.AnonymizeTable{{
+//	        Table: "users",
+//	        Columns: []eorm.AnonymizeColumn{
+//	            {Column: "name", Strategy: eorm.AnonymizeFakeName},
+//	            {Column: "email", Strategy: eorm.AnonymizeFakeEmail},
+//	            {Column: "phone", Strategy: eorm.AnonymizeNullify},
+//	        },
+//	    }},
+//	})
+func Anonymize(plan AnonymizePlan) (int64, error) {
+	db, err := defaultDB()
+	if err != nil {
+		return 0, err
+	}
+	return db.Anonymize(plan)
+}
+
+// Anonymize rewrites the configured columns of this database, see Anonymize
+func (db *DB) Anonymize(plan AnonymizePlan) (int64, error) {
+	if db.lastErr != nil {
+		return 0, db.lastErr
+	}
+	batchSize := plan.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	var total int64
+	for _, t := range plan.Tables {
+		n, err := db.anonymizeTable(t, batchSize, plan.Salt)
+		total += n
+		if err != nil {
+			return total, fmt.Errorf("eorm: anonymize table %s failed: %v", t.Table, err)
+		}
+	}
+	return total, nil
+}
+
+// anonymizeTable rewrites one table in primary key order
+func (db *DB) anonymizeTable(t AnonymizeTable, batchSize int, salt string) (int64, error) {
+	if err := validateIdentifier(t.Table); err != nil {
+		return 0, err
+	}
+	if len(t.Columns) == 0 {
+		return 0, nil
+	}
+	columns := make([]string, 0, len(t.Columns))
+	for _, c := range t.Columns {
+		if err := validateIdentifier(c.Column); err != nil {
+			return 0, err
+		}
+		if c.Strategy == AnonymizeCustom && c.Func == nil {
+			return 0, fmt.Errorf("column %s uses AnonymizeCustom without Func", c.Column)
+		}
+		columns = append(columns, c.Column)
+	}
+
+	sdb, err := db.dbMgr.getDB()
+	if err != nil {
+		return 0, err
+	}
+	pks, err := db.dbMgr.getPrimaryKeys(sdb, t.Table)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get primary keys: %v", err)
+	}
+	if len(pks) != 1 {
+		return 0, fmt.Errorf("table %s must have a single-column primary key", t.Table)
+	}
+	pk := pks[0]
+
+	var total int64
+	var lastID interface{}
+	for {
+		qb := db.Table(t.Table).
+			Select(pk + ", " + strings.Join(columns, ", ")).
+			WithTrashed().
+			WithoutGlobalScopes().
+			WithExpired().
+			OrderBy(pk).
+			Limit(batchSize)
+		if t.Where != "" {
+			qb.Where("("+t.Where+")", t.Args...)
+		}
+		if lastID != nil {
+			qb.Where(pk+" > ?", lastID)
+		}
+		rows, err := qb.Find()
+		if err != nil {
+			return total, err
+		}
+		if len(rows) == 0 {
+			return total, nil
+		}
+
+		updates := anonymizeRows(rows, pk, t.Columns, salt)
+		err = db.Transaction(func(tx *Tx) error {
+			for i, record := range updates {
+				if _, err := tx.dbMgr.updateRecordFast(tx.ctx, tx.tx, t.Table, record, pk+" = ?", rows[i].Get(pk)); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return total, err
+		}
+		total += int64(len(rows))
+
+		if len(rows) < batchSize {
+			return total, nil
+		}
+		lastID = rows[len(rows)-1].Get(pk)
+	}
+}
+
+// anonymizeRows builds the update record of each row
+func anonymizeRows(rows []*Record, pk string, columns []AnonymizeColumn, salt string) []*Record {
+	updates := make([]*Record, len(rows))
+	for i := range rows {
+		updates[i] = NewRecord()
+	}
+
+	for _, c := range columns {
+		if c.Strategy == AnonymizeShuffle {
+			values := make([]interface{}, len(rows))
+			for i, row := range rows {
+				values[i] = row.Get(c.Column)
+			}
+			rand.Shuffle(len(values), func(i, j int) { values[i], values[j] = values[j], values[i] })
+			for i := range rows {
+				updates[i].Set(c.Column, values[i])
+			}
+			continue
+		}
+
+		for i, row := range rows {
+			id := fmt.Sprint(row.Get(pk))
+			var value interface{}
+			switch c.Strategy {
+			case AnonymizeFakeName:
+				value = fakeName(salt + ":" + id)
+			case AnonymizeFakeEmail:
+				value = fakeEmail(salt + ":" + id)
+			case AnonymizeHash:
+				if v := row.Get(c.Column); v != nil {
+					value = anonymizeHash(salt, v)
+				}
+			case AnonymizeNullify:
+				value = nil
+			case AnonymizeCustom:
+				value = c.Func(row.Get(c.Column), row)
+			}
+			updates[i].Set(c.Column, value)
+		}
+	}
+	return updates
+}
+
+// anonymizeHash returns the salted SHA-256 hex digest of a value
+func anonymizeHash(salt string, value interface{}) string {
+	var s string
+	if b, ok := value.([]byte); ok {
+		s = string(b)
+	} else {
+		s = fmt.Sprint(value)
+	}
+	sum := sha256.Sum256([]byte(salt + s))
+	return hex.EncodeToString(sum[:])
+}
+
+// fakeSeed derives a stable number from a seed string
+func fakeSeed(seed string) uint64 {
+	sum := sha256.Sum256([]byte(seed))
+	return binary.BigEndian.Uint64(sum[:8])
+}
+
+// fakeName returns a stable fake full name for a seed
+func fakeName(seed string) string {
+	n := fakeSeed(seed)
+	first := anonymizeFirstNames[n%uint64(len(anonymizeFirstNames))]
+	last := anonymizeLastNames[(n/uint64(len(anonymizeFirstNames)))%uint64(len(anonymizeLastNames))]
+	return first + " " + last
+}
+
+// fakeEmail returns a stable, unique-per-seed fake email address
+func fakeEmail(seed string) string {
+	return fmt.Sprintf("user_%016x@example.com", fakeSeed(seed))
+}
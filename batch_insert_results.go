@@ -0,0 +1,178 @@
+package eorm
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// batchInsertSavepoint 事务中逐条插入时使用的保存点名称
+const batchInsertSavepoint = "eorm_batch_insert"
+
+// BatchInsertOptions BatchInsertRecordWithResults 的选项
+type BatchInsertOptions struct {
+	BatchSize   int  // 每条 INSERT 语句包含的记录数（默认 DefaultBatchSize）
+	SkipInvalid bool // 跳过插入失败的记录继续处理其余记录（默认遇到第一条失败的记录即停止）
+	ReturnIDs   bool // 逐条插入以取得每条记录生成的 ID（较慢）；为 false 时只有失败批次中逐条重试的记录有 ID
+}
+
+// BatchInsertResult 单条记录的插入结果
+type BatchInsertResult struct {
+	Index int   // 记录在输入切片中的下标
+	ID    int64 // 生成的自增 ID（逐条插入且数据库支持时有值）
+	Error error // 插入失败的原因，成功时为 nil
+}
+
+// IsSuccess 判断该记录是否插入成功
+func (r BatchInsertResult) IsSuccess() bool {
+	return r.Error == nil
+}
+
+// BatchInsertError 部分记录插入失败
+type BatchInsertError struct {
+	Total    int                 // 输入的记录数
+	Inserted int                 // 成功插入的记录数
+	Failed   []BatchInsertResult // 失败的记录
+}
+
+// Error 实现 error 接口
+func (e *BatchInsertError) Error() string {
+	first := e.Failed[0]
+	return fmt.Sprintf("eorm: %d of %d records failed to insert (first failure at index %d: %v)", len(e.Failed), e.Total, first.Index, first.Error)
+}
+
+// Unwrap 返回第一条失败记录的错误
+func (e *BatchInsertError) Unwrap() error {
+	return e.Failed[0].Error
+}
+
+// batchInsertWithResults 按批插入记录；批次失败时逐条重试定位失败的记录
+// 事务中每次尝试都包裹在保存点中，失败的语句不会使整个事务失效（PostgreSQL）
+func (mgr *dbManager) batchInsertWithResults(ctx context.Context, executor sqlExecutor, table string, records []*Record, opts *BatchInsertOptions) ([]BatchInsertResult, error) {
+	if err := validateIdentifier(table); err != nil {
+		return nil, err
+	}
+	if opts == nil {
+		opts = &BatchInsertOptions{}
+	}
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	inTx := true
+	switch executor.(type) {
+	case *sql.DB, *serialWriteExecutor:
+		inTx = false
+	}
+	attempt := func(fn func() error) error {
+		if !inTx {
+			return fn()
+		}
+		if err := mgr.execSavepoint(ctx, executor, savepointCreate, batchInsertSavepoint); err != nil {
+			return err
+		}
+		if err := fn(); err != nil {
+			if rbErr := mgr.execSavepoint(ctx, executor, savepointRollback, batchInsertSavepoint); rbErr != nil {
+				return fmt.Errorf("%v (rollback to savepoint failed: %w)", err, rbErr)
+			}
+			return err
+		}
+		return mgr.execSavepoint(ctx, executor, savepointRelease, batchInsertSavepoint)
+	}
+
+	results := make([]BatchInsertResult, 0, len(records))
+	var failed []BatchInsertResult
+	for start := 0; start < len(records); start += batchSize {
+		end := start + batchSize
+		if end > len(records) {
+			end = len(records)
+		}
+		chunk := records[start:end]
+
+		if !opts.ReturnIDs && !containsNilRecord(chunk) {
+			err := attempt(func() error {
+				_, err := mgr.batchInsertRecord(ctx, executor, table, chunk, len(chunk))
+				return err
+			})
+			if err == nil {
+				for i := range chunk {
+					results = append(results, BatchInsertResult{Index: start + i})
+				}
+				continue
+			}
+		}
+
+		// 逐条插入，定位失败的记录并取得生成的 ID
+		for i, record := range chunk {
+			result := BatchInsertResult{Index: start + i}
+			if record == nil {
+				result.Error = fmt.Errorf("eorm: record is nil")
+			} else {
+				result.Error = attempt(func() error {
+					id, err := mgr.insertRecord(ctx, executor, table, record)
+					result.ID = id
+					return err
+				})
+			}
+			results = append(results, result)
+			if result.Error != nil {
+				failed = append(failed, result)
+				if !opts.SkipInvalid {
+					return results, &BatchInsertError{Total: len(records), Inserted: len(results) - len(failed), Failed: failed}
+				}
+			}
+		}
+	}
+	if len(failed) > 0 {
+		return results, &BatchInsertError{Total: len(records), Inserted: len(results) - len(failed), Failed: failed}
+	}
+	return results, nil
+}
+
+// containsNilRecord 判断批次中是否有 nil 记录（需要逐条处理）
+func containsNilRecord(records []*Record) bool {
+	for _, r := range records {
+		if r == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// BatchInsertRecordWithResults 批量插入记录并返回每条记录的结果（下标、生成的 ID、错误）
+// 批次插入失败时逐条重试以定位失败的记录；SkipInvalid 为 true 时跳过失败的记录继续插入其余记录
+// 存在失败记录时错误为 *BatchInsertError，其中列出全部失败的记录
+// 注意：非事务调用时，停止前已插入的记录不会撤销；需要全部成功或全部失败时请在事务中调用
+// 示例:
+//
+//	results, err := eorm.BatchInsertRecordWithResults("users", records, &eorm.BatchInsertOptions{SkipInvalid: true})
+//	var batchErr *eorm.BatchInsertError
+//	if errors.As(err, &batchErr) {
+//	    for _, f := range batchErr.Failed {
+//	        log.Printf("row %d: %v", f.Index, f.Error)
+//	    }
+//	}
+func BatchInsertRecordWithResults(table string, records []*Record, opts *BatchInsertOptions) ([]BatchInsertResult, error) {
+	db, err := defaultDB()
+	if err != nil {
+		return nil, err
+	}
+	return db.BatchInsertRecordWithResults(table, records, opts)
+}
+
+// BatchInsertRecordWithResults 批量插入记录并返回每条记录的结果，见 BatchInsertRecordWithResults
+func (db *DB) BatchInsertRecordWithResults(table string, records []*Record, opts *BatchInsertOptions) ([]BatchInsertResult, error) {
+	if db.lastErr != nil {
+		return nil, db.lastErr
+	}
+	executor, err := db.getExecutor()
+	if err != nil {
+		return nil, err
+	}
+	return db.dbMgr.batchInsertWithResults(contextOrBackground(db.ctx), executor, table, records, opts)
+}
+
+// BatchInsertRecordWithResults 在事务中批量插入记录并返回每条记录的结果，失败的记录回滚到保存点后跳过
+func (tx *Tx) BatchInsertRecordWithResults(table string, records []*Record, opts *BatchInsertOptions) ([]BatchInsertResult, error) {
+	return tx.dbMgr.batchInsertWithResults(contextOrBackground(tx.ctx), tx.tx, table, records, opts)
+}
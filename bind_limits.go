@@ -0,0 +1,125 @@
+package eorm
+
+import (
+	"fmt"
+	"time"
+)
+
+// 各数据库单条语句的绑定参数上限
+const (
+	maxBindParamsDefault   = 65535 // MySQL 预编译语句、PostgreSQL 协议、Oracle
+	maxBindParamsSQLServer = 2098  // SQL Server 上限 2100，保留 2 个给驱动内部使用
+	maxBindParamsSQLite    = 32766 // SQLite 3.32+ 的 SQLITE_MAX_VARIABLE_NUMBER
+	maxValuesRowsSQLServer = 1000  // SQL Server 单条 INSERT ... VALUES 最多 1000 行
+	packetSampleRows       = 10    // 估算行大小时采样的记录数
+)
+
+// maxBindParams 返回当前数据库单条语句允许的绑定参数数量
+func (mgr *dbManager) maxBindParams() int {
+	switch mgr.config.Load().Driver {
+	case SQLServer:
+		return maxBindParamsSQLServer
+	case SQLite3:
+		return maxBindParamsSQLite
+	default:
+		return maxBindParamsDefault
+	}
+}
+
+// maxPacketBytes 返回 MySQL 的 max_allowed_packet（首次调用时查询并缓存），其他数据库返回 0 表示不限制
+func (mgr *dbManager) maxPacketBytes() int64 {
+	if mgr.config.Load().Driver != MySQL {
+		return 0
+	}
+	mgr.packetOnce.Do(func() {
+		db, err := mgr.getDB()
+		if err != nil {
+			return
+		}
+		var packet int64
+		if err := db.QueryRow("SELECT @@max_allowed_packet").Scan(&packet); err == nil {
+			mgr.maxPacket = packet
+		}
+	})
+	return mgr.maxPacket
+}
+
+// safeBatchSize 根据参数数量上限和 MySQL 包大小计算不会超限的批次大小，小于 requested 时记录警告日志
+// paramsPerRow 为每行占用的绑定参数数量，sample 用于估算每行的字节数（可为 nil）
+func (mgr *dbManager) safeBatchSize(op, table string, requested, paramsPerRow int, sample []*Record, multiRowValues bool) int {
+	size := requested
+	reason := ""
+	if paramsPerRow > 0 {
+		if limit := mgr.maxBindParams() / paramsPerRow; size > limit {
+			size, reason = limit, fmt.Sprintf("bind parameter limit %d", mgr.maxBindParams())
+		}
+	}
+	if multiRowValues && mgr.config.Load().Driver == SQLServer && size > maxValuesRowsSQLServer {
+		size, reason = maxValuesRowsSQLServer, fmt.Sprintf("VALUES row limit %d", maxValuesRowsSQLServer)
+	}
+	if packet := mgr.maxPacketBytes(); packet > 0 && len(sample) > 0 {
+		// 预留 20% 给 SQL 文本和协议开销
+		if rowBytes := estimateRowBytes(sample); rowBytes > 0 {
+			if limit := int(packet * 8 / 10 / rowBytes); size > limit {
+				size, reason = limit, fmt.Sprintf("max_allowed_packet %d bytes", packet)
+			}
+		}
+	}
+	if size < 1 {
+		size = 1
+	}
+	if size < requested {
+		LogWarn("批量操作的批次大小超出数据库限制，已自动调整", NewRecord().
+			Set("database", mgr.name).
+			Set("operation", op).
+			Set("table", table).
+			Set("requested", requested).
+			Set("adjusted", size).
+			Set("reason", reason))
+	}
+	return size
+}
+
+// estimateRowBytes 按前几条记录估算每行的字节数，取其中最大的一行以留出余量
+func estimateRowBytes(records []*Record) int64 {
+	n := len(records)
+	if n > packetSampleRows {
+		n = packetSampleRows
+	}
+	var maxBytes int64
+	for _, r := range records[:n] {
+		if r == nil {
+			continue
+		}
+		var rowBytes int64
+		r.mu.RLock()
+		for _, v := range r.columns {
+			rowBytes += estimateValueBytes(v)
+		}
+		r.mu.RUnlock()
+		if rowBytes > maxBytes {
+			maxBytes = rowBytes
+		}
+	}
+	return maxBytes
+}
+
+// estimateValueBytes 估算单个值在协议中的大小
+func estimateValueBytes(v interface{}) int64 {
+	switch val := v.(type) {
+	case nil:
+		return 4
+	case string:
+		return int64(len(val)) + 4
+	case []byte:
+		return int64(len(val)) + 4
+	case time.Time:
+		return 32
+	case bool, int8, uint8:
+		return 4
+	case int, int16, int32, int64, uint, uint16, uint32, uint64, float32, float64:
+		return 12
+	default:
+		return int64(len(fmt.Sprint(val))) + 4
+	}
+}
@@ -1,6 +1,7 @@
 package eorm
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
@@ -31,14 +32,21 @@ type QueryBuilder struct {
 	orWhereSql          []string      // OR conditions
 	orWhereArgs         []interface{} // OR condition arguments
 	orderBy             string
+	orderTerms          []orderTerm   // ORDER BY items, rendered into orderBy
+	samplePercent       float64       // TABLESAMPLE percent, 0 disables
+	asOfSQL             string        // AsOf history source replacing the table in FROM
+	asOfArgs            []interface{} // AsOf source arguments
 	groupBy             string        // GROUP BY clause
 	havingSql           []string      // HAVING conditions
 	havingArgs          []interface{} // HAVING arguments
+	orHavingSql         []string      // OR HAVING conditions
+	orHavingArgs        []interface{} // OR HAVING arguments
 	limit               int
 	offset              int
 	cacheRepositoryName string
 	cacheTTL            time.Duration
 	cacheProvider       CacheProvider // 指定的缓存提供者（nil 表示使用默认缓存）
+	cacheEmptyTTL       time.Duration // 空结果缓存时间（0 表示使用仓库配置，<0 表示不缓存空结果）
 	timeout             time.Duration
 	countCacheTTL       time.Duration // 分页计数缓存时间
 	lastErr             error
@@ -49,6 +57,16 @@ type QueryBuilder struct {
 	subqueryTable       *Subquery        // FROM subquery
 	subqueryAlias       string           // FROM subquery alias
 	selectSubqueries    []SelectSubquery // SELECT subqueries
+	comment             string           // SQL comment appended as /* ... */
+	prefixMapping       PrefixMapping    // JOIN column prefix -> nested struct field mapping
+	withoutGlobalScopes []string         // Global scopes skipped by name
+	skipGlobalScopes    bool             // Skip all global scopes
+	withExpired         bool             // Include rows expired by row TTL
+	cacheRefresh        bool             // Skip cache reads but still store the result
+	cacheMaxStale       time.Duration    // Stale-while-revalidate window, 0 disables
+	queryInfo           *QueryInfo       // Filled by FindWithInfo/FindFirstWithInfo, nil otherwise
+	jsonKeys            *JsonKeyOptions  // JSON key mapping attached to result records
+	jsonAutoPrefix      bool             // Strip table name and alias prefixes from JSON keys
 }
 
 // validateQueryBuilderState 验证 QueryBuilder 的状态是否有效
@@ -70,10 +88,10 @@ func (qb *QueryBuilder) validateQueryBuilderState() error {
 // 用于生成数据库特定的 SQL 语法
 func (qb *QueryBuilder) getDriverType() DriverType {
 	if qb.tx != nil && qb.tx.dbMgr != nil {
-		return qb.tx.dbMgr.config.Driver
+		return qb.tx.dbMgr.config.Load().Driver
 	}
 	if qb.db != nil && qb.db.dbMgr != nil {
-		return qb.db.dbMgr.config.Driver
+		return qb.db.dbMgr.config.Load().Driver
 	}
 	return MySQL // 默认返回 MySQL（不应该到达这里）
 }
@@ -97,7 +115,7 @@ func Table(name string) *QueryBuilder {
 
 	return &QueryBuilder{
 		db:        db,
-		table:     name,
+		table:     db.dbMgr.resolveTableName(name),
 		selectSql: "*",
 	}
 }
@@ -116,11 +134,12 @@ func (db *DB) Table(name string) *QueryBuilder {
 
 	return &QueryBuilder{
 		db:                  db,
-		table:               name,
+		table:               db.dbMgr.resolveTableName(name),
 		selectSql:           "*",
 		cacheRepositoryName: db.cacheRepositoryName,
 		cacheTTL:            db.cacheTTL,
 		cacheProvider:       db.cacheProvider, // 继承 DB 的缓存提供者
+		cacheEmptyTTL:       db.cacheEmptyTTL,
 		lastErr:             db.lastErr,
 	}
 }
@@ -138,14 +157,74 @@ func (tx *Tx) Table(name string) *QueryBuilder {
 
 	return &QueryBuilder{
 		tx:                  tx,
-		table:               name,
+		table:               tx.dbMgr.resolveTableName(name),
 		selectSql:           "*",
 		cacheRepositoryName: tx.cacheRepositoryName,
 		cacheTTL:            tx.cacheTTL,
 		cacheProvider:       tx.cacheProvider, // 继承 Tx 的缓存提供者
+		cacheEmptyTTL:       tx.cacheEmptyTTL,
 	}
 }
 
+// Clone returns an independent copy of the builder
+// 条件、参数、JOIN 等切片都会复制，对副本的修改不会影响原构建器，适合把公共条件构建一次后派生出计数、列表、导出等查询
+// 示例:
+//
+//	base := eorm.Table("orders").Where("tenant_id = ?", tid).Where("status = ?", "paid")
+//	total, _ := base.Clone().Count()
+//	list, _ := base.Clone().OrderBy("id DESC").Limit(20).Find()
+func (qb *QueryBuilder) Clone() *QueryBuilder {
+	if qb == nil {
+		return nil
+	}
+	c := *qb
+	c.queryInfo = nil
+	c.whereSql = cloneSlice(qb.whereSql)
+	c.whereArgs = cloneSlice(qb.whereArgs)
+	c.orWhereSql = cloneSlice(qb.orWhereSql)
+	c.orWhereArgs = cloneSlice(qb.orWhereArgs)
+	c.havingSql = cloneSlice(qb.havingSql)
+	c.havingArgs = cloneSlice(qb.havingArgs)
+	c.orHavingSql = cloneSlice(qb.orHavingSql)
+	c.orHavingArgs = cloneSlice(qb.orHavingArgs)
+	c.orderTerms = cloneSlice(qb.orderTerms)
+	c.asOfArgs = cloneSlice(qb.asOfArgs)
+	c.selectSubqueries = cloneSlice(qb.selectSubqueries)
+	c.withoutGlobalScopes = cloneSlice(qb.withoutGlobalScopes)
+	if qb.joins != nil {
+		c.joins = make([]JoinClause, len(qb.joins))
+		for i, j := range qb.joins {
+			j.args = cloneSlice(j.args)
+			c.joins[i] = j
+		}
+	}
+	if qb.prefixMapping != nil {
+		c.prefixMapping = make(PrefixMapping, len(qb.prefixMapping))
+		for k, v := range qb.prefixMapping {
+			c.prefixMapping[k] = v
+		}
+	}
+	if qb.jsonKeys != nil {
+		keys := JsonKeyOptions{StripPrefixes: cloneSlice(qb.jsonKeys.StripPrefixes)}
+		if qb.jsonKeys.KeyMap != nil {
+			keys.KeyMap = make(map[string]string, len(qb.jsonKeys.KeyMap))
+			for k, v := range qb.jsonKeys.KeyMap {
+				keys.KeyMap[k] = v
+			}
+		}
+		c.jsonKeys = &keys
+	}
+	return &c
+}
+
+// cloneSlice 复制切片，nil 保持为 nil
+func cloneSlice[T any](s []T) []T {
+	if s == nil {
+		return nil
+	}
+	return append(make([]T, 0, len(s)), s...)
+}
+
 // Select specifies the columns to select
 func (qb *QueryBuilder) Select(columns string) *QueryBuilder {
 	if qb.lastErr != nil {
@@ -259,6 +338,10 @@ func (qb *QueryBuilder) OrderBy(orderBy string) *QueryBuilder {
 		qb.lastErr = err
 		return qb
 	}
+	qb.orderTerms = nil
+	if orderBy != "" {
+		qb.orderTerms = []orderTerm{{expr: orderBy, raw: true}}
+	}
 	qb.orderBy = orderBy
 	return qb
 }
@@ -386,6 +469,66 @@ func (qb *QueryBuilder) WhereNotInValues(column string, values []interface{}) *Q
 	return qb
 }
 
+// WhereInTuples adds a WHERE (a, b) IN ((?, ?), (?, ?)) clause for composite-key lookups
+// SQL Server 不支持行值构造器，展开为 ((a = ? AND b = ?) OR (a = ? AND b = ?))
+// 示例:
+//
+//	eorm.Table("order_items").WhereInTuples([]string{"order_id", "product_id"}, [][]interface{}{{1, 10}, {2, 20}}).Find()
+func (qb *QueryBuilder) WhereInTuples(columns []string, tuples [][]interface{}) *QueryBuilder {
+	if qb.lastErr != nil {
+		return qb
+	}
+	if len(columns) == 0 {
+		qb.lastErr = fmt.Errorf("eorm: WhereInTuples requires at least one column")
+		return qb
+	}
+	if len(tuples) == 0 {
+		// 空集合不匹配任何行
+		qb.whereSql = append(qb.whereSql, "1 = 0")
+		return qb
+	}
+	for i, tuple := range tuples {
+		if len(tuple) != len(columns) {
+			qb.lastErr = fmt.Errorf("eorm: WhereInTuples tuple %d has %d values, expected %d", i, len(tuple), len(columns))
+			return qb
+		}
+	}
+
+	if qb.getDriverType() == SQLServer {
+		conds := make([]string, len(tuples))
+		for i, tuple := range tuples {
+			parts := make([]string, len(columns))
+			for j, col := range columns {
+				parts[j] = col + " = ?"
+			}
+			conds[i] = "(" + strings.Join(parts, " AND ") + ")"
+			qb.whereArgs = append(qb.whereArgs, tuple...)
+		}
+		qb.whereSql = append(qb.whereSql, "("+strings.Join(conds, " OR ")+")")
+		return qb
+	}
+
+	group := "(" + strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ") + ")"
+	groups := make([]string, len(tuples))
+	for i, tuple := range tuples {
+		groups[i] = group
+		qb.whereArgs = append(qb.whereArgs, tuple...)
+	}
+	qb.whereSql = append(qb.whereSql, fmt.Sprintf("(%s) IN (%s)", strings.Join(columns, ", "), strings.Join(groups, ", ")))
+	return qb
+}
+
+// WhereAny adds a WHERE ? = ANY(column) clause, matching rows whose PostgreSQL array column contains value
+// 示例: eorm.Table("posts").WhereAny("tags", "go").Find()
+func (qb *QueryBuilder) WhereAny(column string, value interface{}) *QueryBuilder {
+	if qb.lastErr != nil {
+		return qb
+	}
+	qb.whereSql = append(qb.whereSql, fmt.Sprintf("? = ANY(%s)", column))
+	qb.whereArgs = append(qb.whereArgs, value)
+	return qb
+}
+
 // WhereBetween adds a WHERE column BETWEEN ? AND ? clause
 func (qb *QueryBuilder) WhereBetween(column string, min, max interface{}) *QueryBuilder {
 	if qb.lastErr != nil {
@@ -448,6 +591,122 @@ func (qb *QueryBuilder) Having(condition string, args ...interface{}) *QueryBuil
 	return qb
 }
 
+// HavingRaw adds a raw HAVING condition, e.g. aggregate expressions with arguments (same as Having)
+// 示例: eorm.Table("orders").Select("user_id, SUM(amount) AS total").GroupBy("user_id").HavingRaw("SUM(amount) > ? AND COUNT(*) >= ?", 1000, 3).Find()
+func (qb *QueryBuilder) HavingRaw(condition string, args ...interface{}) *QueryBuilder {
+	return qb.Having(condition, args...)
+}
+
+// OrHaving adds an OR HAVING condition: HAVING ... OR condition
+func (qb *QueryBuilder) OrHaving(condition string, args ...interface{}) *QueryBuilder {
+	if qb.lastErr != nil {
+		return qb
+	}
+	qb.orHavingSql = append(qb.orHavingSql, condition)
+	qb.orHavingArgs = append(qb.orHavingArgs, args...)
+	return qb
+}
+
+// HavingGroupFunc is a function type for building grouped HAVING conditions
+type HavingGroupFunc func(qb *QueryBuilder) *QueryBuilder
+
+// HavingGroup adds a grouped AND condition: HAVING ... AND (grouped conditions)
+// fn 中使用 Having/OrHaving 等方法构建括号内的条件
+// 示例:
+//
+//	eorm.Table("orders").Select("user_id, COUNT(*) AS cnt").GroupBy("user_id").
+//	    Having("COUNT(*) > ?", 5).
+//	    HavingGroup(func(q *eorm.QueryBuilder) *eorm.QueryBuilder {
+//	        return q.Having("SUM(amount) > ?", 1000).OrHaving("MAX(amount) > ?", 500)
+//	    }).Find()
+//	// HAVING COUNT(*) > ? AND (SUM(amount) > ? OR MAX(amount) > ?)
+func (qb *QueryBuilder) HavingGroup(fn HavingGroupFunc) *QueryBuilder {
+	if qb.lastErr != nil {
+		return qb
+	}
+	condition, args, err := buildGroupedHaving(qb, fn)
+	if err != nil {
+		qb.lastErr = err
+		return qb
+	}
+	if condition != "" {
+		qb.havingSql = append(qb.havingSql, "("+condition+")")
+		qb.havingArgs = append(qb.havingArgs, args...)
+	}
+	return qb
+}
+
+// OrHavingGroup adds a grouped OR condition: HAVING ... OR (grouped conditions)
+func (qb *QueryBuilder) OrHavingGroup(fn HavingGroupFunc) *QueryBuilder {
+	if qb.lastErr != nil {
+		return qb
+	}
+	condition, args, err := buildGroupedHaving(qb, fn)
+	if err != nil {
+		qb.lastErr = err
+		return qb
+	}
+	if condition != "" {
+		qb.orHavingSql = append(qb.orHavingSql, "("+condition+")")
+		qb.orHavingArgs = append(qb.orHavingArgs, args...)
+	}
+	return qb
+}
+
+// buildGroupedHaving collects the HAVING conditions built by fn on a temporary QueryBuilder
+func buildGroupedHaving(qb *QueryBuilder, fn HavingGroupFunc) (string, []interface{}, error) {
+	tempQb := &QueryBuilder{db: qb.db, tx: qb.tx, table: qb.table, selectSql: "*"}
+	fn(tempQb)
+	if tempQb.lastErr != nil {
+		return "", nil, tempQb.lastErr
+	}
+	args := append(append([]interface{}{}, tempQb.havingArgs...), tempQb.orHavingArgs...)
+	return joinHavingConditions(tempQb.havingSql, tempQb.orHavingSql, false), args, nil
+}
+
+// joinHavingConditions combines AND and OR HAVING conditions: (a AND b) OR c OR d
+// wrapAnd 为 true 时在同时存在 OR 条件的情况下给 AND 部分加括号
+func joinHavingConditions(andConds, orConds []string, wrapAnd bool) string {
+	andPart := strings.Join(andConds, " AND ")
+	if len(orConds) == 0 {
+		return andPart
+	}
+	orPart := strings.Join(orConds, " OR ")
+	if andPart == "" {
+		return orPart
+	}
+	if wrapAnd {
+		andPart = "(" + andPart + ")"
+	}
+	return andPart + " OR " + orPart
+}
+
+// HavingIn adds a HAVING expr IN (?, ?, ...) clause for aggregate filtering
+// 示例: eorm.Table("orders").Select("status, COUNT(*) AS cnt").GroupBy("status").HavingIn("COUNT(*)", []interface{}{1, 2, 3}).Find()
+func (qb *QueryBuilder) HavingIn(expr string, values []interface{}) *QueryBuilder {
+	if qb.lastErr != nil {
+		return qb
+	}
+	if len(values) == 0 {
+		return qb
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(values)), ", ")
+	qb.havingSql = append(qb.havingSql, fmt.Sprintf("%s IN (%s)", expr, placeholders))
+	qb.havingArgs = append(qb.havingArgs, values...)
+	return qb
+}
+
+// HavingBetween adds a HAVING expr BETWEEN ? AND ? clause for aggregate filtering
+// 示例: eorm.Table("orders").Select("user_id, SUM(amount) AS total").GroupBy("user_id").HavingBetween("SUM(amount)", 100, 1000).Find()
+func (qb *QueryBuilder) HavingBetween(expr string, min, max interface{}) *QueryBuilder {
+	if qb.lastErr != nil {
+		return qb
+	}
+	qb.havingSql = append(qb.havingSql, fmt.Sprintf("%s BETWEEN ? AND ?", expr))
+	qb.havingArgs = append(qb.havingArgs, min, max)
+	return qb
+}
+
 // TableSubquery sets a subquery as the FROM source
 func (qb *QueryBuilder) TableSubquery(sub *Subquery, alias string) *QueryBuilder {
 	if qb.lastErr != nil {
@@ -477,6 +736,13 @@ func (qb *QueryBuilder) SelectSubquery(sub *Subquery, alias string) *QueryBuilde
 	return qb
 }
 
+// Comment 为生成的 SQL 追加注释，便于 DBA 在慢查询日志中定位来源
+// 示例: Table("orders").Comment("service:orders api:list").Find()
+func (qb *QueryBuilder) Comment(comment string) *QueryBuilder {
+	qb.comment = comment
+	return qb
+}
+
 // Cache enables caching for the query
 func (qb *QueryBuilder) Cache(cacheRepositoryName string, ttl ...time.Duration) *QueryBuilder {
 	qb.cacheRepositoryName = cacheRepositoryName
@@ -522,6 +788,13 @@ func (qb *QueryBuilder) RedisCache(cacheRepositoryName string, ttl ...time.Durat
 	return qb
 }
 
+// CacheEmpty 设置空结果的缓存时间，需配合 Cache/LocalCache/RedisCache 使用
+// ttl > 0 表示以该时间缓存空结果，NoStoreEmpty 表示不缓存空结果
+func (qb *QueryBuilder) CacheEmpty(ttl time.Duration) *QueryBuilder {
+	qb.cacheEmptyTTL = ttl
+	return qb
+}
+
 // Timeout sets the query timeout
 func (qb *QueryBuilder) Timeout(d time.Duration) *QueryBuilder {
 	qb.timeout = d
@@ -552,8 +825,79 @@ func (qb *QueryBuilder) getEffectiveCache() CacheProvider {
 	return GetCache()
 }
 
-// buildSelectSql constructs the final SELECT SQL string
+// buildSelectSql constructs the final SELECT SQL string, including the optional comment
 func (qb *QueryBuilder) buildSelectSql() (string, []interface{}) {
+	sql, args := qb.buildSelectBody()
+	return appendSQLComment(sql, qb.comment), args
+}
+
+// buildSelectBody constructs the SELECT SQL string without the trailing comment
+// 同一形状（表、子句、分页、驱动）的查询复用缓存的 SQL 字符串，只重新收集参数
+func (qb *QueryBuilder) buildSelectBody() (string, []interface{}) {
+	whereClauses, scopeArgs := qb.collectSelectConditions()
+	if len(qb.selectSubqueries) > 0 || qb.subqueryTable != nil || qb.asOfSQL != "" {
+		return qb.buildSelectBodyWith(whereClauses, scopeArgs)
+	}
+	key, ok := qb.builderShapeKey(whereClauses)
+	if !ok {
+		return qb.buildSelectBodyWith(whereClauses, scopeArgs)
+	}
+	if cached, hit := builderCacheGet(key); hit {
+		return cached, qb.collectSelectArgs(scopeArgs)
+	}
+	querySQL, args := qb.buildSelectBodyWith(whereClauses, scopeArgs)
+	builderCachePut(key, querySQL)
+	return querySQL, args
+}
+
+// collectSelectConditions 收集 WHERE 中的 AND 条件：用户条件、软删除、全局作用域、行 TTL、行级过滤
+// 返回的参数为追加在用户条件参数之后的作用域参数
+func (qb *QueryBuilder) collectSelectConditions() ([]string, []interface{}) {
+	whereClauses := make([]string, 0, len(qb.whereSql)+1)
+	whereClauses = append(whereClauses, qb.whereSql...)
+
+	// Add soft delete filter if applicable
+	softDeleteCondition := qb.getSoftDeleteCondition()
+	if softDeleteCondition != "" {
+		whereClauses = append(whereClauses, softDeleteCondition)
+	}
+
+	// Add table global scopes
+	scopeConditions, scopeArgs := qb.getGlobalScopeConditions()
+	whereClauses = append(whereClauses, scopeConditions...)
+
+	// Add row TTL filter if applicable
+	if ttlCondition, ttlArgs := qb.getRowTTLCondition(); ttlCondition != "" {
+		whereClauses = append(whereClauses, ttlCondition)
+		scopeArgs = append(scopeArgs, ttlArgs...)
+	}
+
+	// Add row-level filter if applicable
+	if filterCondition, filterArgs := qb.getRowFilterCondition(); filterCondition != "" {
+		whereClauses = append(whereClauses, "("+filterCondition+")")
+		scopeArgs = append(scopeArgs, filterArgs...)
+	}
+	return whereClauses, scopeArgs
+}
+
+// collectSelectArgs 按 buildSelectBodyWith 的顺序收集参数（不含子查询参数）
+func (qb *QueryBuilder) collectSelectArgs(scopeArgs []interface{}) []interface{} {
+	var allArgs []interface{}
+	for _, join := range qb.joins {
+		allArgs = append(allArgs, join.args...)
+	}
+	allArgs = append(allArgs, qb.whereArgs...)
+	allArgs = append(allArgs, scopeArgs...)
+	allArgs = append(allArgs, qb.orWhereArgs...)
+	if len(qb.havingSql) > 0 || len(qb.orHavingSql) > 0 {
+		allArgs = append(allArgs, qb.havingArgs...)
+		allArgs = append(allArgs, qb.orHavingArgs...)
+	}
+	return allArgs
+}
+
+// buildSelectBodyWith constructs the SELECT SQL string from the collected WHERE conditions
+func (qb *QueryBuilder) buildSelectBodyWith(whereClauses []string, scopeArgs []interface{}) (string, []interface{}) {
 	var sb strings.Builder
 	var allArgs []interface{}
 
@@ -580,8 +924,11 @@ func (qb *QueryBuilder) buildSelectSql() (string, []interface{}) {
 		subSQL, subArgs := qb.subqueryTable.ToSQL()
 		fromPart = fmt.Sprintf("(%s) AS %s", subSQL, qb.subqueryAlias)
 		allArgs = append(allArgs, subArgs...)
+	} else if qb.asOfSQL != "" {
+		fromPart = fmt.Sprintf("(%s) %s", qb.asOfSQL, qb.table)
+		allArgs = append(allArgs, qb.asOfArgs...)
 	} else {
-		fromPart = qb.table
+		fromPart = qb.sampledFrom(qb.table)
 	}
 
 	sb.WriteString(fmt.Sprintf("SELECT %s FROM %s", selectPart, fromPart))
@@ -592,16 +939,6 @@ func (qb *QueryBuilder) buildSelectSql() (string, []interface{}) {
 		allArgs = append(allArgs, join.args...)
 	}
 
-	// Collect all where conditions
-	whereClauses := make([]string, 0, len(qb.whereSql)+1)
-	whereClauses = append(whereClauses, qb.whereSql...)
-
-	// Add soft delete filter if applicable
-	softDeleteCondition := qb.getSoftDeleteCondition()
-	if softDeleteCondition != "" {
-		whereClauses = append(whereClauses, softDeleteCondition)
-	}
-
 	// Build WHERE clause with AND and OR conditions
 	if len(whereClauses) > 0 || len(qb.orWhereSql) > 0 {
 		sb.WriteString(" WHERE ")
@@ -628,8 +965,9 @@ func (qb *QueryBuilder) buildSelectSql() (string, []interface{}) {
 		}
 	}
 
-	// Append WHERE args after JOIN args (AND args first, then OR args)
+	// Append WHERE args after JOIN args (AND args first, then global scope args, then OR args)
 	allArgs = append(allArgs, qb.whereArgs...)
+	allArgs = append(allArgs, scopeArgs...)
 	allArgs = append(allArgs, qb.orWhereArgs...)
 
 	// Add GROUP BY clause
@@ -639,10 +977,11 @@ func (qb *QueryBuilder) buildSelectSql() (string, []interface{}) {
 	}
 
 	// Add HAVING clause
-	if len(qb.havingSql) > 0 {
+	if len(qb.havingSql) > 0 || len(qb.orHavingSql) > 0 {
 		sb.WriteString(" HAVING ")
-		sb.WriteString(strings.Join(qb.havingSql, " AND "))
+		sb.WriteString(joinHavingConditions(qb.havingSql, qb.orHavingSql, true))
 		allArgs = append(allArgs, qb.havingArgs...)
+		allArgs = append(allArgs, qb.orHavingArgs...)
 	}
 
 	if qb.orderBy != "" {
@@ -772,43 +1111,53 @@ func (qb *QueryBuilder) getSoftDeleteCondition() string {
 }
 
 // Query executes the query and returns a slice of Records
-func (qb *QueryBuilder) Query() ([]*Record, error) {
+func (qb *QueryBuilder) Query() (records []*Record, err error) {
 	if qb.lastErr != nil {
 		return nil, qb.lastErr
 	}
+	defer func() { records = qb.withJsonKeys(records) }()
+	if err := qb.applyPaginationLimits(); err != nil {
+		return nil, err
+	}
 	sql, args := qb.buildSelectSql()
 
 	// Handle caching
 	if qb.cacheRepositoryName != "" && qb.tx == nil {
 		cache := qb.getEffectiveCache()
 		cacheKey := qb.generateCacheKey(sql, args)
-		if val, ok := cache.CacheGet(qb.cacheRepositoryName, cacheKey); ok {
+		if val, ok := qb.cacheLookup(cache, cacheKey); ok {
 			if records, ok := val.([]*Record); ok {
+				qb.noteCacheResult(cache, cacheKey, true)
+				qb.revalidateIfStale(cache, cacheKey, func(c *QueryBuilder) error {
+					_, err := c.Query()
+					return err
+				})
 				return records, nil
 			}
 		}
 		// If not in cache, query and store
+		qb.noteCacheResult(cache, cacheKey, false)
 		db := qb.db
 		if qb.timeout > 0 {
-			db = &DB{dbMgr: qb.db.dbMgr, timeout: qb.timeout}
+			db = &DB{dbMgr: qb.db.dbMgr, timeout: qb.timeout, ctx: qb.db.ctx}
 		}
 		records, err := db.Query(sql, args...)
 		if err == nil {
-			cache.CacheSet(qb.cacheRepositoryName, cacheKey, records, qb.cacheTTL)
+			qb.cacheStore(cache, cacheKey, records, len(records) == 0)
 		}
 		return records, err
 	}
 
 	if qb.tx != nil {
 		if qb.timeout > 0 {
-			tx := &Tx{tx: qb.tx.tx, dbMgr: qb.tx.dbMgr, timeout: qb.timeout}
+			tx := &Tx{tx: qb.tx.tx, dbMgr: qb.tx.dbMgr, timeout: qb.timeout, ctx: qb.tx.ctx}
 			return tx.Query(sql, args...)
 		}
 		return qb.tx.Query(sql, args...)
 	}
 
 	if qb.timeout > 0 {
-		db := &DB{dbMgr: qb.db.dbMgr, timeout: qb.timeout}
+		db := &DB{dbMgr: qb.db.dbMgr, timeout: qb.timeout, ctx: qb.db.ctx}
 		return db.Query(sql, args...)
 	}
 	return qb.db.Query(sql, args...)
@@ -817,12 +1166,13 @@ func (qb *QueryBuilder) Query() ([]*Record, error) {
 // generateCacheKey creates a unique key for the query and its arguments
 func (qb *QueryBuilder) generateCacheKey(sql string, args []interface{}) string {
 	dbName := ""
+	var ctx context.Context
 	if qb.db != nil {
-		dbName = qb.db.dbMgr.name
+		dbName, ctx = qb.db.dbMgr.name, qb.db.ctx
 	} else if qb.tx != nil {
-		dbName = qb.tx.dbMgr.name
+		dbName, ctx = qb.tx.dbMgr.name, qb.tx.ctx
 	}
-	return GenerateCacheKey(dbName, sql, args...)
+	return GenerateCacheKeyContext(ctx, dbName, sql, args...)
 }
 
 // Find is an alias for Query
@@ -836,7 +1186,10 @@ func (qb *QueryBuilder) FindToDbModel(dest interface{}) error {
 	if err != nil {
 		return err
 	}
-	return ToStructs(records, dest)
+	if len(qb.prefixMapping) > 0 {
+		return ToStructsWithPrefix(records, dest, qb.prefixMapping)
+	}
+	return toStructsWith(records, dest, qb.namingStrategy())
 }
 
 func (qb *QueryBuilder) QueryToDbModel(dest interface{}) error {
@@ -844,51 +1197,83 @@ func (qb *QueryBuilder) QueryToDbModel(dest interface{}) error {
 	if err != nil {
 		return err
 	}
-	return ToStructs(records, dest)
+	if len(qb.prefixMapping) > 0 {
+		return ToStructsWithPrefix(records, dest, qb.prefixMapping)
+	}
+	return toStructsWith(records, dest, qb.namingStrategy())
 }
 
 // QueryFirst executes the query and returns the first Record
-func (qb *QueryBuilder) QueryFirst() (*Record, error) {
+func (qb *QueryBuilder) QueryFirst() (record *Record, err error) {
 	if qb.lastErr != nil {
 		return nil, qb.lastErr
 	}
+	defer func() {
+		if record != nil {
+			record = qb.withJsonKeys([]*Record{record})[0]
+		}
+	}()
+	if err := qb.applyPaginationLimits(); err != nil {
+		return nil, err
+	}
 	// Temporarily set limit to 1 if not set or set to something else
 	oldLimit := qb.limit
 	qb.limit = 1
 	sql, args := qb.buildSelectSql()
 	qb.limit = oldLimit
 
+	// 请求级工作单元的身份映射
+	if uow := qb.unitOfWork(); uow != nil {
+		return uow.queryFirst(qb, sql, args)
+	}
+
+	// 按主键查询时使用实体缓存
+	if key, ttl, ok := qb.entityCacheKey(); ok {
+		return qb.queryFirstEntity(key, ttl, sql, args)
+	}
+
 	// Handle caching
 	if qb.cacheRepositoryName != "" && qb.tx == nil {
 		cache := qb.getEffectiveCache()
 		cacheKey := qb.generateCacheKey(sql, args) + "_first"
-		if val, ok := cache.CacheGet(qb.cacheRepositoryName, cacheKey); ok {
-			if record, ok := val.(*Record); ok {
+		if val, ok := qb.cacheLookup(cache, cacheKey); ok {
+			if isCacheEmptyMarker(val) {
+				qb.noteCacheResult(cache, cacheKey, true)
+				return nil, nil
+			}
+			var record *Record
+			if convertCacheValue(val, &record) {
+				qb.noteCacheResult(cache, cacheKey, true)
+				qb.revalidateIfStale(cache, cacheKey, func(c *QueryBuilder) error {
+					_, err := c.QueryFirst()
+					return err
+				})
 				return record, nil
 			}
 		}
 		// If not in cache, query and store
+		qb.noteCacheResult(cache, cacheKey, false)
 		db := qb.db
 		if qb.timeout > 0 {
-			db = &DB{dbMgr: qb.db.dbMgr, timeout: qb.timeout}
+			db = &DB{dbMgr: qb.db.dbMgr, timeout: qb.timeout, ctx: qb.db.ctx}
 		}
 		record, err := db.QueryFirst(sql, args...)
-		if err == nil && record != nil {
-			cache.CacheSet(qb.cacheRepositoryName, cacheKey, record, qb.cacheTTL)
+		if err == nil {
+			qb.cacheStore(cache, cacheKey, record, record == nil)
 		}
 		return record, err
 	}
 
 	if qb.tx != nil {
 		if qb.timeout > 0 {
-			tx := &Tx{tx: qb.tx.tx, dbMgr: qb.tx.dbMgr, timeout: qb.timeout}
+			tx := &Tx{tx: qb.tx.tx, dbMgr: qb.tx.dbMgr, timeout: qb.timeout, ctx: qb.tx.ctx}
 			return tx.QueryFirst(sql, args...)
 		}
 		return qb.tx.QueryFirst(sql, args...)
 	}
 
 	if qb.timeout > 0 {
-		db := &DB{dbMgr: qb.db.dbMgr, timeout: qb.timeout}
+		db := &DB{dbMgr: qb.db.dbMgr, timeout: qb.timeout, ctx: qb.db.ctx}
 		return db.QueryFirst(sql, args...)
 	}
 	return qb.db.QueryFirst(sql, args...)
@@ -908,14 +1293,21 @@ func (qb *QueryBuilder) FindFirstToDbModel(dest interface{}) error {
 	if record == nil {
 		return fmt.Errorf("eorm: no record found")
 	}
-	return ToStruct(record, dest)
+	return toStructWith(record, dest, qb.namingStrategy())
 }
 
 // Paginate executes the query with pagination and returns a Page object
-func (qb *QueryBuilder) Paginate(pageNumber, pageSize int) (*Page[*Record], error) {
+func (qb *QueryBuilder) Paginate(pageNumber, pageSize int) (pageObj *Page[*Record], err error) {
 	if qb.lastErr != nil {
 		return nil, qb.lastErr
 	}
+	defer func() {
+		if pageObj != nil && qb.jsonKeys != nil {
+			p := *pageObj
+			p.List = qb.withJsonKeys(p.List)
+			pageObj = &p
+		}
+	}()
 
 	// 构建完整的SQL语句（不包含LIMIT和OFFSET，因为分页逻辑会处理）
 	sql, args := qb.buildSelectSql()
@@ -927,9 +1319,13 @@ func (qb *QueryBuilder) Paginate(pageNumber, pageSize int) (*Page[*Record], erro
 	if qb.cacheRepositoryName != "" && qb.tx == nil {
 		cache := qb.getEffectiveCache()
 		cacheKey := qb.generateCacheKey(sql, args) + fmt.Sprintf("_p%d_s%d", pageNumber, pageSize)
-		if val, ok := cache.CacheGet(qb.cacheRepositoryName, cacheKey); ok {
+		if val, ok := qb.cacheLookup(cache, cacheKey); ok {
 			var pageObj *Page[*Record]
 			if convertCacheValue(val, &pageObj) {
+				qb.revalidateIfStale(cache, cacheKey, func(c *QueryBuilder) error {
+					_, err := c.Paginate(pageNumber, pageSize)
+					return err
+				})
 				return pageObj, nil
 			}
 		}
@@ -951,14 +1347,14 @@ func (qb *QueryBuilder) Paginate(pageNumber, pageSize int) (*Page[*Record], erro
 			if qb.timeout > 0 {
 				db = db.Timeout(qb.timeout)
 			}
-			if qb.countCacheTTL > 0 {
+			if qb.countCacheTTL > 0 && !qb.cacheRefresh {
 				db = db.WithCountCache(qb.countCacheTTL)
 			}
 			pageObj, err = db.Paginate(pageNumber, pageSize, sql, args...)
 		}
 
 		if err == nil {
-			cache.CacheSet(qb.cacheRepositoryName, cacheKey, pageObj, qb.cacheTTL)
+			qb.cacheStore(cache, cacheKey, pageObj, false)
 		}
 		return pageObj, err
 	}
@@ -1044,6 +1440,22 @@ func (qb *QueryBuilder) Count() (int64, error) {
 		whereClauses = append(whereClauses, softDeleteCondition)
 	}
 
+	// Add table global scopes
+	scopeConditions, scopeArgs := qb.getGlobalScopeConditions()
+	whereClauses = append(whereClauses, scopeConditions...)
+
+	// Add row TTL filter if applicable
+	if ttlCondition, ttlArgs := qb.getRowTTLCondition(); ttlCondition != "" {
+		whereClauses = append(whereClauses, ttlCondition)
+		scopeArgs = append(scopeArgs, ttlArgs...)
+	}
+
+	// Row-level filter is applied by db.Count/tx.Count
+	whereArgs := qb.whereArgs
+	if len(scopeArgs) > 0 {
+		whereArgs = append(cloneSlice(qb.whereArgs), scopeArgs...)
+	}
+
 	whereSql := ""
 	if len(whereClauses) > 0 {
 		whereSql = strings.Join(whereClauses, " AND ")
@@ -1054,24 +1466,28 @@ func (qb *QueryBuilder) Count() (int64, error) {
 		cache := qb.getEffectiveCache()
 		sql, args := qb.buildSelectSql()
 		cacheKey := qb.generateCacheKey(sql, args) + "_count"
-		if val, ok := cache.CacheGet(qb.cacheRepositoryName, cacheKey); ok {
+		if val, ok := qb.cacheLookup(cache, cacheKey); ok {
 			if count, ok := val.(int64); ok {
+				qb.revalidateIfStale(cache, cacheKey, func(c *QueryBuilder) error {
+					_, err := c.Count()
+					return err
+				})
 				return count, nil
 			}
 		}
 
 		// If not in cache, query and store
-		count, err := qb.db.Count(qb.table, whereSql, qb.whereArgs...)
+		count, err := qb.db.Count(qb.table, whereSql, whereArgs...)
 		if err == nil {
-			cache.CacheSet(qb.cacheRepositoryName, cacheKey, count, qb.cacheTTL)
+			qb.cacheStore(cache, cacheKey, count, false)
 		}
 		return count, err
 	}
 
 	if qb.tx != nil {
-		return qb.tx.Count(qb.table, whereSql, qb.whereArgs...)
+		return qb.tx.Count(qb.table, whereSql, whereArgs...)
 	}
-	return qb.db.Count(qb.table, whereSql, qb.whereArgs...)
+	return qb.db.Count(qb.table, whereSql, whereArgs...)
 }
 
 // WithTrashed includes soft-deleted records in the query results
@@ -0,0 +1,178 @@
+package eorm
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultBuilderCacheSize QueryBuilder SQL 缓存的默认最大条目数
+const DefaultBuilderCacheSize = 4096
+
+// BuilderCacheStats QueryBuilder 生成 SQL 的缓存统计
+type BuilderCacheStats struct {
+	Hits             int64 // 命中缓存、直接复用 SQL 字符串的次数
+	Misses           int64 // 未命中、重新构建 SQL 的次数
+	Size             int   // 当前缓存的形状数
+	MaxSize          int   // 最大条目数，0 表示已禁用
+	IdentifierHits   int64 // 表名/列名校验命中缓存的次数
+	IdentifierMisses int64 // 表名/列名需要正则校验的次数
+}
+
+// builderCache 按查询形状缓存生成的 SQL 字符串：相同的表、子句、分页和驱动生成相同的 SQL，参数每次重新收集
+// 达到最大条目数后不再加入新形状，已缓存的形状继续命中
+var builderCache = struct {
+	mu      sync.RWMutex
+	entries map[string]string
+	maxSize int
+	hits    int64
+	misses  int64
+}{entries: make(map[string]string), maxSize: DefaultBuilderCacheSize}
+
+// validIdentifiers 已通过校验的标识符，避免 Table/Select 等重复进行正则匹配
+var (
+	validIdentifiers      sync.Map
+	validIdentifierCount  int64
+	identifierCacheHits   int64
+	identifierCacheMisses int64
+)
+
+// SetBuilderCacheSize 设置 QueryBuilder SQL 缓存的最大条目数并清空缓存，size <= 0 禁用缓存
+// 动态拼接条件（如每次不同的 IN 列表长度）会产生大量形状，可调小或禁用
+func SetBuilderCacheSize(size int) {
+	if size < 0 {
+		size = 0
+	}
+	builderCache.mu.Lock()
+	defer builderCache.mu.Unlock()
+	builderCache.maxSize = size
+	builderCache.entries = make(map[string]string)
+}
+
+// ClearBuilderCache 清空 QueryBuilder SQL 缓存和标识符校验缓存，并重置统计
+func ClearBuilderCache() {
+	builderCache.mu.Lock()
+	builderCache.entries = make(map[string]string)
+	builderCache.mu.Unlock()
+	atomic.StoreInt64(&builderCache.hits, 0)
+	atomic.StoreInt64(&builderCache.misses, 0)
+
+	validIdentifiers.Range(func(k, _ interface{}) bool {
+		validIdentifiers.Delete(k)
+		return true
+	})
+	atomic.StoreInt64(&validIdentifierCount, 0)
+	atomic.StoreInt64(&identifierCacheHits, 0)
+	atomic.StoreInt64(&identifierCacheMisses, 0)
+}
+
+// GetBuilderCacheStats 返回 QueryBuilder SQL 缓存统计，可用于确认相同模式的查询是否复用了 SQL
+func GetBuilderCacheStats() BuilderCacheStats {
+	builderCache.mu.RLock()
+	size, maxSize := len(builderCache.entries), builderCache.maxSize
+	builderCache.mu.RUnlock()
+	return BuilderCacheStats{
+		Hits:             atomic.LoadInt64(&builderCache.hits),
+		Misses:           atomic.LoadInt64(&builderCache.misses),
+		Size:             size,
+		MaxSize:          maxSize,
+		IdentifierHits:   atomic.LoadInt64(&identifierCacheHits),
+		IdentifierMisses: atomic.LoadInt64(&identifierCacheMisses),
+	}
+}
+
+// builderCacheGet 查找形状对应的 SQL
+func builderCacheGet(key string) (string, bool) {
+	builderCache.mu.RLock()
+	querySQL, ok := builderCache.entries[key]
+	builderCache.mu.RUnlock()
+	if ok {
+		atomic.AddInt64(&builderCache.hits, 1)
+	} else {
+		atomic.AddInt64(&builderCache.misses, 1)
+	}
+	return querySQL, ok
+}
+
+// builderCachePut 缓存形状对应的 SQL，超过最大条目数时忽略
+func builderCachePut(key, querySQL string) {
+	builderCache.mu.Lock()
+	defer builderCache.mu.Unlock()
+	if len(builderCache.entries) < builderCache.maxSize {
+		builderCache.entries[key] = querySQL
+	}
+}
+
+// builderShapeKey 返回构建器当前形状的缓存键，缓存被禁用时 ok 为 false
+// 键包含影响 SQL 文本的全部输入（参数值除外），各部分以 \x00 分隔
+func (qb *QueryBuilder) builderShapeKey(whereClauses []string) (key string, ok bool) {
+	builderCache.mu.RLock()
+	disabled := builderCache.maxSize == 0
+	builderCache.mu.RUnlock()
+	if disabled {
+		return "", false
+	}
+
+	var sb strings.Builder
+	sb.WriteString(string(qb.getDriverType()))
+	sb.WriteByte(0)
+	sb.WriteString(qb.selectSql)
+	sb.WriteByte(0)
+	sb.WriteString(qb.table)
+	sb.WriteString("\x00T")
+	sb.WriteString(strconv.FormatFloat(qb.samplePercent, 'f', -1, 64))
+	for _, join := range qb.joins {
+		sb.WriteString("\x00J")
+		sb.WriteString(join.joinType)
+		sb.WriteByte(0)
+		sb.WriteString(join.table)
+		sb.WriteByte(0)
+		sb.WriteString(join.condition)
+	}
+	for _, c := range whereClauses {
+		sb.WriteString("\x00W")
+		sb.WriteString(c)
+	}
+	for _, c := range qb.orWhereSql {
+		sb.WriteString("\x00O")
+		sb.WriteString(c)
+	}
+	sb.WriteString("\x00G")
+	sb.WriteString(qb.groupBy)
+	for _, c := range qb.havingSql {
+		sb.WriteString("\x00H")
+		sb.WriteString(c)
+	}
+	for _, c := range qb.orHavingSql {
+		sb.WriteString("\x00h")
+		sb.WriteString(c)
+	}
+	sb.WriteString("\x00S")
+	sb.WriteString(qb.orderBy)
+	sb.WriteString("\x00L")
+	sb.WriteString(strconv.Itoa(qb.limit))
+	sb.WriteString("\x00F")
+	sb.WriteString(strconv.Itoa(qb.offset))
+	return sb.String(), true
+}
+
+// identifierValidated 判断标识符是否已通过校验
+func identifierValidated(name string) bool {
+	if _, ok := validIdentifiers.Load(name); ok {
+		atomic.AddInt64(&identifierCacheHits, 1)
+		return true
+	}
+	atomic.AddInt64(&identifierCacheMisses, 1)
+	return false
+}
+
+// rememberIdentifier 记录通过校验的标识符，数量受 DefaultBuilderCacheSize 限制
+func rememberIdentifier(name string) {
+	if atomic.LoadInt64(&validIdentifierCount) >= DefaultBuilderCacheSize {
+		return
+	}
+	if _, loaded := validIdentifiers.LoadOrStore(name, struct{}{}); !loaded {
+		atomic.AddInt64(&validIdentifierCount, 1)
+	}
+}
@@ -0,0 +1,198 @@
+package eorm
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// BulkLoadOptions LOAD DATA LOCAL INFILE 的数据格式选项
+type BulkLoadOptions struct {
+	Columns            []string // 数据对应的列（为空表示按表的列顺序）
+	FieldsTerminatedBy string   // 字段分隔符（默认 ","）
+	EnclosedBy         string   // 字段包围符（默认 `"`，设为 "-" 表示不使用）
+	LinesTerminatedBy  string   // 行分隔符（默认 "\n"）
+	IgnoreLines        int      // 跳过开头的行数（如 CSV 表头）
+	CharacterSet       string   // 数据文件字符集，如 "utf8mb4"
+	Replace            bool     // 主键/唯一键冲突时替换已有行
+	Ignore             bool     // 主键/唯一键冲突时跳过该行
+}
+
+// mysqlReaderRegistry MySQL 驱动的 Reader 注册函数
+// eorm 不直接依赖 MySQL 驱动，需要通过 SetMySQLReaderRegistry 注入
+var mysqlReaderRegistry struct {
+	sync.RWMutex
+	register   func(name string, handler func() io.Reader)
+	deregister func(name string)
+}
+
+// bulkLoadSeq 生成唯一的 Reader 名称
+var bulkLoadSeq uint64
+
+// SetMySQLReaderRegistry 设置 MySQL 驱动的 Reader 注册函数，BulkLoad 使用前需调用一次
+// 示例:
+//
+//	import "github.com/go-sql-driver/mysql"
+//	eorm.SetMySQLReaderRegistry(mysql.RegisterReaderHandler, mysql.DeregisterReaderHandler)
+func SetMySQLReaderRegistry(register func(name string, handler func() io.Reader), deregister func(name string)) {
+	mysqlReaderRegistry.Lock()
+	defer mysqlReaderRegistry.Unlock()
+	mysqlReaderRegistry.register = register
+	mysqlReaderRegistry.deregister = deregister
+}
+
+// bulkLoad 使用 LOAD DATA LOCAL INFILE 'Reader::name' 导入数据（仅 MySQL/MariaDB）
+func (mgr *dbManager) bulkLoad(executor sqlExecutor, table string, reader io.Reader, opts *BulkLoadOptions) (_ int64, err error) {
+	defer func() { mgr.afterTableWrite(executor, table, err) }()
+	if err := validateIdentifier(table); err != nil {
+		return 0, err
+	}
+	if mgr.config.Load().Driver != MySQL {
+		return 0, fmt.Errorf("eorm: BulkLoad is only supported for MySQL/MariaDB, current driver is %s", mgr.config.Load().Driver)
+	}
+	if reader == nil {
+		return 0, fmt.Errorf("eorm: BulkLoad reader is nil")
+	}
+	if opts == nil {
+		opts = &BulkLoadOptions{}
+	}
+	if err := validateBulkLoadOptions(opts); err != nil {
+		return 0, err
+	}
+
+	mysqlReaderRegistry.RLock()
+	register, deregister := mysqlReaderRegistry.register, mysqlReaderRegistry.deregister
+	mysqlReaderRegistry.RUnlock()
+	if register == nil || deregister == nil {
+		return 0, fmt.Errorf("eorm: BulkLoad requires eorm.SetMySQLReaderRegistry(mysql.RegisterReaderHandler, mysql.DeregisterReaderHandler)")
+	}
+
+	name := fmt.Sprintf("eorm_bulk_%d", atomic.AddUint64(&bulkLoadSeq, 1))
+	register(name, func() io.Reader { return reader })
+	defer deregister(name)
+
+	querySQL := buildBulkLoadSQL(name, table, opts)
+	start := time.Now()
+	result, err := executor.Exec(querySQL)
+	mgr.logTrace(start, querySQL, nil, err)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// validateBulkLoadOptions 校验会直接拼入 SQL 的选项：列名和字符集必须是合法标识符
+func validateBulkLoadOptions(opts *BulkLoadOptions) error {
+	if opts.Replace && opts.Ignore {
+		return fmt.Errorf("eorm: BulkLoad options Replace and Ignore are mutually exclusive")
+	}
+	for _, col := range opts.Columns {
+		if err := validateIdentifier(col); err != nil {
+			return err
+		}
+	}
+	if opts.CharacterSet != "" {
+		if err := validateIdentifier(opts.CharacterSet); err != nil {
+			return fmt.Errorf("eorm: invalid BulkLoad character set: %w", err)
+		}
+	}
+	return nil
+}
+
+// buildBulkLoadSQL 构造 LOAD DATA LOCAL INFILE 语句
+func buildBulkLoadSQL(name, table string, opts *BulkLoadOptions) string {
+	fieldsTerminated := opts.FieldsTerminatedBy
+	if fieldsTerminated == "" {
+		fieldsTerminated = ","
+	}
+	enclosedBy := opts.EnclosedBy
+	if enclosedBy == "" {
+		enclosedBy = `"`
+	}
+	linesTerminated := opts.LinesTerminatedBy
+	if linesTerminated == "" {
+		linesTerminated = "\n"
+	}
+
+	var sb strings.Builder
+	sb.WriteString("LOAD DATA LOCAL INFILE ")
+	sb.WriteString(quoteMySQLString("Reader::" + name))
+	if opts.Replace {
+		sb.WriteString(" REPLACE")
+	} else if opts.Ignore {
+		sb.WriteString(" IGNORE")
+	}
+	sb.WriteString(" INTO TABLE ")
+	sb.WriteString(table)
+	if opts.CharacterSet != "" {
+		sb.WriteString(" CHARACTER SET ")
+		sb.WriteString(opts.CharacterSet)
+	}
+	sb.WriteString(" FIELDS TERMINATED BY ")
+	sb.WriteString(quoteMySQLString(fieldsTerminated))
+	if enclosedBy != "-" {
+		sb.WriteString(" OPTIONALLY ENCLOSED BY ")
+		sb.WriteString(quoteMySQLString(enclosedBy))
+	}
+	sb.WriteString(" LINES TERMINATED BY ")
+	sb.WriteString(quoteMySQLString(linesTerminated))
+	if opts.IgnoreLines > 0 {
+		sb.WriteString(fmt.Sprintf(" IGNORE %d LINES", opts.IgnoreLines))
+	}
+	if len(opts.Columns) > 0 {
+		sb.WriteString(" (")
+		sb.WriteString(strings.Join(opts.Columns, ", "))
+		sb.WriteString(")")
+	}
+	return sb.String()
+}
+
+// quoteMySQLString 将字符串转为 MySQL 字符串常量
+func quoteMySQLString(s string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `'`, `\'`, "\n", `\n`, "\r", `\r`, "\t", `\t`, "\x00", `\0`)
+	return "'" + replacer.Replace(s) + "'"
+}
+
+// BulkLoad 使用 LOAD DATA LOCAL INFILE 从 reader 批量导入数据（仅 MySQL/MariaDB），大批量导入时比多行 INSERT 快一个数量级
+// 需要服务端开启 local_infile，并预先调用 SetMySQLReaderRegistry 注册驱动的 Reader 处理函数
+// opts 为 nil 时按 CSV 格式（逗号分隔、双引号包围、\n 换行）导入
+// 示例:
+//
+//	f, _ := os.Open("users.csv")
+//	defer f.Close()
+//	rows, err := eorm.BulkLoad("users", f, &eorm.BulkLoadOptions{Columns: []string{"name", "age"}, IgnoreLines: 1})
+func BulkLoad(table string, reader io.Reader, opts *BulkLoadOptions) (int64, error) {
+	db, err := defaultDB()
+	if err != nil {
+		return 0, err
+	}
+	return db.BulkLoad(table, reader, opts)
+}
+
+// BulkLoad 使用 LOAD DATA LOCAL INFILE 批量导入数据
+func (db *DB) BulkLoad(table string, reader io.Reader, opts *BulkLoadOptions) (int64, error) {
+	if db.lastErr != nil {
+		return 0, db.lastErr
+	}
+	executor, err := db.getExecutor()
+	if err != nil {
+		return 0, err
+	}
+	rows, err := db.dbMgr.bulkLoad(executor, table, reader, opts)
+	if err == nil && db.cacheRepositoryName != "" {
+		db.ClearCache(db.cacheRepositoryName)
+	}
+	return rows, err
+}
+
+// BulkLoad 在事务中使用 LOAD DATA LOCAL INFILE 批量导入数据
+func (tx *Tx) BulkLoad(table string, reader io.Reader, opts *BulkLoadOptions) (int64, error) {
+	rows, err := tx.dbMgr.bulkLoad(tx.tx, table, reader, opts)
+	if err == nil && tx.cacheRepositoryName != "" {
+		tx.ClearCache(tx.cacheRepositoryName)
+	}
+	return rows, err
+}
@@ -0,0 +1,46 @@
+package eorm
+
+import "testing"
+
+func TestValidateBulkLoadOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    BulkLoadOptions
+		wantErr bool
+	}{
+		{"Defaults", BulkLoadOptions{}, false},
+		{"CharacterSet", BulkLoadOptions{CharacterSet: "utf8mb4", Columns: []string{"id", "name"}}, false},
+		{"InjectedCharacterSet", BulkLoadOptions{CharacterSet: "utf8 FIELDS TERMINATED BY ';'"}, true},
+		{"InjectedColumn", BulkLoadOptions{Columns: []string{"id, @x"}}, true},
+		{"ReplaceAndIgnore", BulkLoadOptions{Replace: true, Ignore: true}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateBulkLoadOptions(&tt.opts); (err != nil) != tt.wantErr {
+				t.Fatalf("validateBulkLoadOptions = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBuildBulkLoadSQL(t *testing.T) {
+	tests := []struct {
+		name string
+		opts BulkLoadOptions
+		want string
+	}{
+		{"Defaults", BulkLoadOptions{},
+			`LOAD DATA LOCAL INFILE 'Reader::r1' INTO TABLE users FIELDS TERMINATED BY ',' OPTIONALLY ENCLOSED BY '"' LINES TERMINATED BY '\n'`},
+		{"AllOptions", BulkLoadOptions{Replace: true, CharacterSet: "utf8mb4", FieldsTerminatedBy: "\t", EnclosedBy: "-", LinesTerminatedBy: "\r\n", IgnoreLines: 1, Columns: []string{"id", "name"}},
+			`LOAD DATA LOCAL INFILE 'Reader::r1' REPLACE INTO TABLE users CHARACTER SET utf8mb4 FIELDS TERMINATED BY '\t' LINES TERMINATED BY '\r\n' IGNORE 1 LINES (id, name)`},
+		{"EscapedTerminator", BulkLoadOptions{Ignore: true, FieldsTerminatedBy: `'`, EnclosedBy: `\`},
+			`LOAD DATA LOCAL INFILE 'Reader::r1' IGNORE INTO TABLE users FIELDS TERMINATED BY '\'' OPTIONALLY ENCLOSED BY '\\' LINES TERMINATED BY '\n'`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := buildBulkLoadSQL("r1", "users", &tt.opts); got != tt.want {
+				t.Fatalf("buildBulkLoadSQL =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
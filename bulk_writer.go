@@ -0,0 +1,314 @@
+package eorm
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BulkWriterOptions BulkWriter 的批量、重试和流控选项
+type BulkWriterOptions struct {
+	BatchSize         int                                // 每批写入的记录数（默认 DefaultBatchSize）
+	FlushInterval     time.Duration                      // 后台定时写出未满的批次，0 表示只在批次写满、Flush、Close 时写出
+	MaxRetries        int                                // 瞬时错误的最大重试次数（默认 3，< 0 表示不重试）
+	RetryBackoff      time.Duration                      // 首次重试等待时间，之后每次翻倍（默认 100ms）
+	MaxBackoff        time.Duration                      // 重试和流控的最大等待时间（默认 5s）
+	PoolWaitThreshold time.Duration                      // 一批写入期间连接池累计等待超过该值时视为数据库繁忙并减速（默认 10ms，< 0 关闭流控）
+	IsRetryable       func(err error) bool               // 判断错误是否可重试（默认识别连接断开、死锁、锁等待超时等）
+	OnError           func(records []*Record, err error) // 批次重试后仍失败时调用，可用于写入死信队列
+}
+
+// BulkWriterStats BulkWriter 的写入统计
+type BulkWriterStats struct {
+	Added            int64         // Add 的记录数
+	Written          int64         // 成功写入的记录数
+	Failed           int64         // 重试后仍写入失败的记录数
+	Pending          int           // 缓冲区中尚未写出的记录数
+	Batches          int64         // 成功写入的批次数
+	Retries          int64         // 重试次数
+	Throttles        int64         // 因数据库繁忙而减速的次数
+	ThrottleDuration time.Duration // 减速累计等待时间
+	LastFlushAt      time.Time     // 最近一次成功写出的时间
+	LastFlushTook    time.Duration // 最近一次成功写出的耗时（含重试）
+	LastError        string        // 最近一次写入失败的错误
+}
+
+// BulkWriter 面向数据采集场景的批量写入器：Add 累积记录，攒满一批后写入；
+// 写入在调用方 goroutine 中同步执行，数据库变慢时 Add 随之变慢，形成背压；
+// 瞬时错误按指数退避重试，连接池等待时间升高时主动减速
+// BulkWriter 可被多个 goroutine 并发使用，使用完毕需调用 Close 写出剩余记录
+type BulkWriter struct {
+	db    *DB
+	table string
+	opts  BulkWriterOptions
+
+	mu      sync.Mutex // 保护 buffer 和 stats
+	buffer  []*Record
+	stats   BulkWriterStats
+	closed  bool
+	writeMu sync.Mutex    // 串行化批次写入
+	delay   time.Duration // 当前流控等待时间
+	stop    chan struct{}
+	done    chan struct{}
+	lastErr error
+}
+
+// NewBulkWriter 为默认数据库的表创建批量写入器
+// 示例:
+//
+//	w := eorm.NewBulkWriter("events", &eorm.BulkWriterOptions{BatchSize: 500, FlushInterval: time.Second})
+//	defer w.Close()
+//	for e := range events {
+//	    if err := w.Add(eorm.NewRecord().Set("type", e.Type).Set("payload", e.Payload)); err != nil {
+//	        log.Println(err)
+//	    }
+//	}
+//	fmt.Printf("%+v\n", w.Stats())
+func NewBulkWriter(table string, opts *BulkWriterOptions) *BulkWriter {
+	db, err := defaultDB()
+	if err != nil {
+		return &BulkWriter{table: table, lastErr: err, closed: true}
+	}
+	return db.NewBulkWriter(table, opts)
+}
+
+// NewBulkWriter 为当前数据库的表创建批量写入器，见 NewBulkWriter
+func (db *DB) NewBulkWriter(table string, opts *BulkWriterOptions) *BulkWriter {
+	w := &BulkWriter{db: db, table: table}
+	if db.lastErr != nil {
+		w.lastErr, w.closed = db.lastErr, true
+		return w
+	}
+	if err := validateIdentifier(table); err != nil {
+		w.lastErr, w.closed = err, true
+		return w
+	}
+	if opts != nil {
+		w.opts = *opts
+	}
+	if w.opts.BatchSize <= 0 {
+		w.opts.BatchSize = DefaultBatchSize
+	}
+	if w.opts.MaxRetries == 0 {
+		w.opts.MaxRetries = 3
+	}
+	if w.opts.RetryBackoff <= 0 {
+		w.opts.RetryBackoff = 100 * time.Millisecond
+	}
+	if w.opts.MaxBackoff <= 0 {
+		w.opts.MaxBackoff = 5 * time.Second
+	}
+	if w.opts.PoolWaitThreshold == 0 {
+		w.opts.PoolWaitThreshold = 10 * time.Millisecond
+	}
+	if w.opts.IsRetryable == nil {
+		w.opts.IsRetryable = isTransientError
+	}
+	w.buffer = make([]*Record, 0, w.opts.BatchSize)
+	if w.opts.FlushInterval > 0 {
+		w.stop = make(chan struct{})
+		w.done = make(chan struct{})
+		go w.runFlusher()
+	}
+	return w
+}
+
+// Add 添加一条记录，缓冲区攒满一批时在当前 goroutine 中写入并返回该批的写入错误
+func (w *BulkWriter) Add(record *Record) error {
+	if record == nil {
+		return nil
+	}
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		if w.lastErr != nil {
+			return w.lastErr
+		}
+		return fmt.Errorf("eorm: bulk writer for %s is closed", w.table)
+	}
+	w.buffer = append(w.buffer, record)
+	w.stats.Added++
+	var batch []*Record
+	if len(w.buffer) >= w.opts.BatchSize {
+		batch = w.takeBuffer()
+	}
+	w.mu.Unlock()
+
+	if batch == nil {
+		return nil
+	}
+	return w.write(batch)
+}
+
+// Flush 立即写出缓冲区中的记录
+func (w *BulkWriter) Flush() error {
+	if w.db == nil {
+		return w.lastErr
+	}
+	w.mu.Lock()
+	batch := w.takeBuffer()
+	w.mu.Unlock()
+	if len(batch) == 0 {
+		return nil
+	}
+	return w.write(batch)
+}
+
+// Close 停止后台定时写出并写出剩余记录，之后 Add 返回错误
+func (w *BulkWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return w.lastErr
+	}
+	w.closed = true
+	w.mu.Unlock()
+
+	if w.stop != nil {
+		close(w.stop)
+		<-w.done
+	}
+	return w.Flush()
+}
+
+// Stats 返回写入统计
+func (w *BulkWriter) Stats() BulkWriterStats {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	stats := w.stats
+	stats.Pending = len(w.buffer)
+	return stats
+}
+
+// takeBuffer 取出缓冲区中的记录（调用方持有 w.mu）
+func (w *BulkWriter) takeBuffer() []*Record {
+	if len(w.buffer) == 0 {
+		return nil
+	}
+	batch := w.buffer
+	w.buffer = make([]*Record, 0, w.opts.BatchSize)
+	return batch
+}
+
+// runFlusher 定时写出未满的批次
+func (w *BulkWriter) runFlusher() {
+	defer close(w.done)
+	ticker := time.NewTicker(w.opts.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			if err := w.Flush(); err != nil {
+				LogWarn("批量写入定时写出失败", NewRecord().
+					Set("table", w.table).
+					Set("error", err.Error()))
+			}
+		}
+	}
+}
+
+// write 写入一批记录：瞬时错误按指数退避重试，写入后根据连接池等待情况流控
+func (w *BulkWriter) write(batch []*Record) error {
+	w.writeMu.Lock()
+	defer w.writeMu.Unlock()
+
+	started := time.Now()
+	before := w.db.dbMgr.poolStats()
+	backoff := w.opts.RetryBackoff
+	var err error
+	var retries int64
+	for attempt := 0; ; attempt++ {
+		_, err = w.db.BatchInsertRecord(w.table, batch, len(batch))
+		if err == nil || attempt >= w.opts.MaxRetries || !w.opts.IsRetryable(err) {
+			break
+		}
+		retries++
+		time.Sleep(backoff)
+		backoff *= 2
+		if backoff > w.opts.MaxBackoff {
+			backoff = w.opts.MaxBackoff
+		}
+	}
+	took := time.Since(started)
+
+	w.mu.Lock()
+	w.stats.Retries += retries
+	if err != nil {
+		w.stats.Failed += int64(len(batch))
+		w.stats.LastError = err.Error()
+	} else {
+		w.stats.Written += int64(len(batch))
+		w.stats.Batches++
+		w.stats.LastFlushAt = time.Now()
+		w.stats.LastFlushTook = took
+	}
+	w.mu.Unlock()
+
+	if err != nil {
+		err = fmt.Errorf("eorm: bulk write %d records into %s failed: %v", len(batch), w.table, err)
+		if w.opts.OnError != nil {
+			w.opts.OnError(batch, err)
+		}
+	}
+	w.throttle(before, w.db.dbMgr.poolStats())
+	return err
+}
+
+// throttle 连接池在本批写入期间的等待时间超过阈值时等待一段时间再返回，
+// 繁忙持续时等待时间翻倍（不超过 MaxBackoff），恢复后逐步减半（调用方持有 w.writeMu）
+func (w *BulkWriter) throttle(before, after *PoolStats) {
+	if w.opts.PoolWaitThreshold < 0 || before == nil || after == nil {
+		return
+	}
+	if after.WaitDuration-before.WaitDuration > w.opts.PoolWaitThreshold {
+		if w.delay == 0 {
+			w.delay = w.opts.RetryBackoff
+		} else {
+			w.delay *= 2
+		}
+		if w.delay > w.opts.MaxBackoff {
+			w.delay = w.opts.MaxBackoff
+		}
+	} else {
+		w.delay /= 2
+		if w.delay < time.Millisecond {
+			w.delay = 0
+		}
+	}
+	if w.delay == 0 {
+		return
+	}
+
+	w.mu.Lock()
+	w.stats.Throttles++
+	w.stats.ThrottleDuration += w.delay
+	w.mu.Unlock()
+	time.Sleep(w.delay)
+}
+
+// isTransientError 判断错误是否为可重试的瞬时错误（连接断开、超时、死锁、锁等待超时、连接数过多等）
+func isTransientError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	msg := strings.ToLower(err.Error())
+	for _, s := range []string{
+		"deadlock", "lock wait timeout", "database is locked", "could not serialize",
+		"too many connections", "connection reset", "connection refused", "broken pipe",
+		"bad connection", "i/o timeout", "ora-00060", "ora-03113", "ora-03114",
+	} {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -1,6 +1,7 @@
 package eorm
 
 import (
+	"context"
 	"crypto/md5"
 	"database/sql"
 	"encoding/hex"
@@ -35,6 +36,7 @@ func (e cacheEntry) isExpired() bool {
 // localCache implements CacheProvider using in-memory storage
 type localCache struct {
 	stores          sync.Map // map[string]*sync.Map (cacheRepositoryName -> map[key]cacheEntry)
+	limiters        sync.Map // map[string]*cacheLimiter 配置了容量限制的仓库
 	cleanupInterval time.Duration
 }
 
@@ -75,7 +77,7 @@ func (lc *localCache) cleanupExpired() {
 						stmt.Close()
 					}
 				}
-				s.Delete(key)
+				lc.limitedDelete(name.(string), s, key)
 			}
 			return true
 		})
@@ -88,10 +90,13 @@ func (lc *localCache) CacheGet(cacheRepositoryName, key string) (interface{}, bo
 		if entry, ok := store.(*sync.Map).Load(key); ok {
 			e := entry.(cacheEntry)
 			if !e.isExpired() {
+				if cl, ok := lc.limiters.Load(cacheRepositoryName); ok {
+					cl.(*cacheLimiter).touch(key)
+				}
 				return e.value, true
 			}
 			// 过期了，顺手删掉
-			store.(*sync.Map).Delete(key)
+			lc.limitedDelete(cacheRepositoryName, store.(*sync.Map), key)
 		}
 	}
 	return nil, false
@@ -103,11 +108,17 @@ func (lc *localCache) CacheSet(cacheRepositoryName, key string, value interface{
 	if ttl > 0 {
 		expiration = time.Now().Add(ttl)
 	}
-	store.(*sync.Map).Store(key, cacheEntry{
+	entry := cacheEntry{
 		value:      value,
 		expiration: expiration,
 		createdAt:  time.Now(),
-	})
+	}
+	// 配置了容量限制的仓库需要跟踪容量并淘汰
+	if cl := lc.getLimiter(cacheRepositoryName); cl != nil {
+		lc.limitedSet(cl, cacheRepositoryName, store.(*sync.Map), key, entry)
+		return
+	}
+	store.(*sync.Map).Store(key, entry)
 }
 
 func (lc *localCache) CacheDelete(cacheRepositoryName, key string) {
@@ -115,17 +126,14 @@ func (lc *localCache) CacheDelete(cacheRepositoryName, key string) {
 		return // 忽略空参数
 	}
 	if store, ok := lc.stores.Load(cacheRepositoryName); ok {
-		s := store.(*sync.Map)
-		if cacheRepositoryName == StmtCacheRepository {
-			if v, ok := s.Load(key); ok {
-				if entry, ok := v.(cacheEntry); ok {
-					if stmt, ok := entry.value.(*sql.Stmt); ok {
-						stmt.Close()
-					}
+		v, ok := lc.limitedDelete(cacheRepositoryName, store.(*sync.Map), key)
+		if ok && cacheRepositoryName == StmtCacheRepository {
+			if entry, ok := v.(cacheEntry); ok {
+				if stmt, ok := entry.value.(*sql.Stmt); ok {
+					stmt.Close()
 				}
 			}
 		}
-		s.Delete(key)
 	}
 }
 
@@ -147,6 +155,7 @@ func (lc *localCache) CacheClearRepository(cacheRepositoryName string) {
 		}
 	}
 	lc.stores.Delete(cacheRepositoryName)
+	lc.limitedClear(cacheRepositoryName)
 }
 
 // ClearAll 清空所有缓存存储库
@@ -165,6 +174,7 @@ func (lc *localCache) ClearAll() {
 			}
 		}
 		lc.stores.Delete(key)
+		lc.limitedClear(key.(string))
 		return true
 	})
 }
@@ -196,6 +206,13 @@ func (lc *localCache) Status() map[string]interface{} {
 	stats["estimated_memory_bytes"] = totalMemory
 	stats["estimated_memory_human"] = formatBytes(totalMemory)
 
+	// 容量限制与淘汰统计
+	limitedRepos, evictions := lc.limiterStats()
+	stats["evictions"] = evictions
+	if len(limitedRepos) > 0 {
+		stats["limited_repositories"] = limitedRepos
+	}
+
 	return stats
 }
 
@@ -570,19 +587,24 @@ func RedisCache(cacheRepositoryName string, ttl ...time.Duration) *DB {
 }
 
 // GenerateCacheKey creates a unique key for a query
+// 缓存键由 SetCacheKeyBuilder 设置的生成器生成，默认为 DefaultCacheKeyBuilder；生成器收到的 ctx 为 context.Background()
 func GenerateCacheKey(dbName, sql string, args ...interface{}) string {
-	hash := md5.New()
-	hash.Write([]byte(dbName))
-	hash.Write([]byte(sql))
-	if len(args) > 0 {
-		hash.Write([]byte(fmt.Sprintf("%v", args)))
-	}
-	return hex.EncodeToString(hash.Sum(nil))
+	return GenerateCacheKeyContext(context.Background(), dbName, sql, args...)
+}
+
+// GenerateCacheKeyContext 与 GenerateCacheKey 相同，但把查询的上下文传给缓存键生成器
+func GenerateCacheKeyContext(ctx context.Context, dbName, sql string, args ...interface{}) string {
+	return GetCacheKeyBuilder().BuildCacheKey(contextOrBackground(ctx), dbName, sql, args)
 }
 
 // GeneratePaginationCacheKey 为分页查询生成专门的缓存键
 // 基于解析后的SQL结构生成更精确的缓存键，确保缓存键的唯一性和一致性
 func GeneratePaginationCacheKey(dbName string, parsedSQL *ParsedSQL, page, pageSize int, args ...interface{}) string {
+	return generatePaginationCacheKey(context.Background(), dbName, parsedSQL, page, pageSize, args...)
+}
+
+// generatePaginationCacheKey 见 GeneratePaginationCacheKey，ctx 传给缓存键生成器
+func generatePaginationCacheKey(ctx context.Context, dbName string, parsedSQL *ParsedSQL, page, pageSize int, args ...interface{}) string {
 	hash := md5.New()
 
 	// 数据库名称
@@ -645,18 +667,18 @@ func GeneratePaginationCacheKey(dbName string, parsedSQL *ParsedSQL, page, pageS
 		hash.Write([]byte("JOIN:"))
 	}
 
-	// 参数
-	if len(args) > 0 {
-		hash.Write([]byte("ARGS:"))
-		hash.Write([]byte(fmt.Sprintf("%v", args)))
-	}
-
-	return hex.EncodeToString(hash.Sum(nil))
+	// SQL 结构摘要和参数交给缓存键生成器，以便自定义生成器统一规范化参数
+	return GenerateCacheKeyContext(ctx, dbName, "PAGINATE_STRUCT:"+hex.EncodeToString(hash.Sum(nil)), args...)
 }
 
 // GenerateCountCacheKey 为计数查询生成专门的缓存键
 // 基于解析后的SQL结构生成计数查询的缓存键
 func GenerateCountCacheKey(dbName string, parsedSQL *ParsedSQL, args ...interface{}) string {
+	return generateCountCacheKey(context.Background(), dbName, parsedSQL, args...)
+}
+
+// generateCountCacheKey 见 GenerateCountCacheKey，ctx 传给缓存键生成器
+func generateCountCacheKey(ctx context.Context, dbName string, parsedSQL *ParsedSQL, args ...interface{}) string {
 	hash := md5.New()
 
 	// 数据库名称
@@ -703,13 +725,8 @@ func GenerateCountCacheKey(dbName string, parsedSQL *ParsedSQL, args ...interfac
 		hash.Write([]byte("JOIN:"))
 	}
 
-	// 参数
-	if len(args) > 0 {
-		hash.Write([]byte("ARGS:"))
-		hash.Write([]byte(fmt.Sprintf("%v", args)))
-	}
-
-	return hex.EncodeToString(hash.Sum(nil))
+	// SQL 结构摘要和参数交给缓存键生成器，以便自定义生成器统一规范化参数
+	return GenerateCacheKeyContext(ctx, dbName, "COUNT_STRUCT:"+hex.EncodeToString(hash.Sum(nil)), args...)
 }
 
 func getEffectiveTTL(cacheRepositoryName string, customTTL time.Duration) time.Duration {
@@ -0,0 +1,77 @@
+package eorm
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// CacheValueDecoder 由缓存提供者返回的延迟解码值
+// 使用非 JSON 编解码器（如 msgpack、gob）的缓存提供者可以在 CacheGet 中返回实现该接口的值，
+// convertCacheValue 会调用 DecodeInto 直接解码到目标类型，从而保留 int64、time.Time 等类型信息
+type CacheValueDecoder interface {
+	DecodeInto(dest interface{}) error
+}
+
+func init() {
+	// 注册 Record 列值中常见的动态类型，供 gob 编码 interface{} 时使用
+	gob.Register(time.Time{})
+	gob.Register([]interface{}{})
+	gob.Register(map[string]interface{}{})
+	gob.Register(&Record{})
+}
+
+// recordBinary Record 的二进制序列化结构，按插入顺序保存键和值
+type recordBinary struct {
+	Keys   []string
+	Values []interface{}
+}
+
+// MarshalBinary 实现 encoding.BinaryMarshaler 接口
+// 与 JSON 不同，二进制格式保留列值的原始类型（int64 不会变成 float64，time.Time 不会变成字符串），
+// gob 与 msgpack 编解码器都会使用该方法序列化 Record
+func (r *Record) MarshalBinary() ([]byte, error) {
+	var data recordBinary
+	if r != nil {
+		r.mu.RLock()
+		data.Keys = make([]string, len(r.keys))
+		data.Values = make([]interface{}, len(r.keys))
+		for i, k := range r.keys {
+			data.Keys[i] = k
+			data.Values[i] = r.columns[k]
+		}
+		r.mu.RUnlock()
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(&data); err != nil {
+		return nil, fmt.Errorf("eorm: record binary marshal failed: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary 实现 encoding.BinaryUnmarshaler 接口
+func (r *Record) UnmarshalBinary(b []byte) error {
+	var data recordBinary
+	if err := gob.NewDecoder(bytes.NewReader(b)).Decode(&data); err != nil {
+		return fmt.Errorf("eorm: record binary unmarshal failed: %v", err)
+	}
+	if len(data.Keys) != len(data.Values) {
+		return fmt.Errorf("eorm: record binary unmarshal failed: %d keys but %d values", len(data.Keys), len(data.Values))
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.columns = make(map[string]interface{}, len(data.Keys))
+	r.lowerKeyMap = make(map[string]string, len(data.Keys))
+	r.keys = make([]string, 0, len(data.Keys))
+	for i, k := range data.Keys {
+		r.columns[k] = data.Values[i]
+		r.lowerKeyMap[strings.ToLower(k)] = k
+		r.keys = append(r.keys, k)
+	}
+	return nil
+}
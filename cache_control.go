@@ -0,0 +1,51 @@
+package eorm
+
+// Refresh 强制刷新本次查询的缓存：跳过缓存读取直接查询数据库，并用结果覆盖缓存
+// 需配合 Cache/LocalCache/RedisCache 使用，适用于后台操作后只刷新某个缓存列表，而不必清空整个缓存库
+// 配合 WithCountCache 分页时，本次同样不读取计数缓存
+// 示例: eorm.Table("products").Where("status = ?", 1).Cache("product_list").Refresh().Find()
+func (qb *QueryBuilder) Refresh() *QueryBuilder {
+	qb.cacheRefresh = true
+	if qb.cacheRepositoryName != "" && qb.cacheProvider == nil {
+		// 固定继承的缓存提供者，去掉 DB/Tx 的缓存设置后仍写回原缓存
+		qb.cacheProvider = qb.getEffectiveCache()
+	}
+	qb.stripInheritedCache()
+	return qb
+}
+
+// NoCache 禁用本次查询的缓存，包括从 eorm.Cache(...)/LocalCache(...) 等继承的缓存设置
+// 示例: eorm.Cache("user_cache").Table("users").NoCache().Find()
+func (qb *QueryBuilder) NoCache() *QueryBuilder {
+	qb.cacheRepositoryName = ""
+	qb.cacheProvider = nil
+	qb.cacheRefresh = false
+	qb.stripInheritedCache()
+	return qb
+}
+
+// cacheLookup 读取查询缓存，Refresh 时视为未命中
+func (qb *QueryBuilder) cacheLookup(cache CacheProvider, key string) (interface{}, bool) {
+	if qb.cacheRefresh {
+		logCacheKey(qb.cacheRepositoryName, key, false)
+		return nil, false
+	}
+	return cacheGet(cache, qb.cacheRepositoryName, key)
+}
+
+// stripInheritedCache 使用去掉缓存设置的 DB/Tx 副本执行查询，
+// 避免底层 Query/Paginate/Count 再次读取继承自 DB/Tx 的缓存
+func (qb *QueryBuilder) stripInheritedCache() {
+	if qb.db != nil && qb.db.cacheRepositoryName != "" {
+		db := *qb.db
+		db.cacheRepositoryName = ""
+		db.cacheProvider = nil
+		qb.db = &db
+	}
+	if qb.tx != nil && qb.tx.cacheRepositoryName != "" {
+		tx := *qb.tx
+		tx.cacheRepositoryName = ""
+		tx.cacheProvider = nil
+		qb.tx = &tx
+	}
+}
@@ -0,0 +1,228 @@
+package eorm
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// maxTableCacheDependencies 单个表最多跟踪的缓存键数量
+// 超过后该表降级为按缓存仓库整体失效，避免只读表的依赖记录无限增长
+const maxTableCacheDependencies = 10000
+
+// cacheKeyRef 唯一标识某个缓存提供者中的一个缓存键
+type cacheKeyRef struct {
+	provider CacheProvider
+	repo     string
+	key      string
+}
+
+// cacheRepoRef 唯一标识某个缓存提供者中的一个缓存仓库
+type cacheRepoRef struct {
+	provider CacheProvider
+	repo     string
+}
+
+// tableCacheDeps 记录依赖某个表的缓存
+type tableCacheDeps struct {
+	keys  map[cacheKeyRef]struct{}
+	repos map[cacheRepoRef]struct{} // 键数量超限后降级为仓库级失效
+}
+
+// tableCacheDependencyRegistry 表 -> 依赖缓存 的注册表
+// 通过 eorm 执行写操作时，会自动失效依赖该表的缓存
+type tableCacheDependencyRegistry struct {
+	deps map[string]*tableCacheDeps // table(lower) -> deps
+	mu   sync.Mutex
+}
+
+var tableCacheDependencies = &tableCacheDependencyRegistry{
+	deps: make(map[string]*tableCacheDeps),
+}
+
+// register 记录缓存键依赖的表
+func (r *tableCacheDependencyRegistry) register(tables []string, provider CacheProvider, repo, key string) {
+	if len(tables) == 0 || provider == nil || repo == "" || key == "" {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, table := range tables {
+		table = normalizeDependencyTable(table)
+		if table == "" {
+			continue
+		}
+		d, ok := r.deps[table]
+		if !ok {
+			d = &tableCacheDeps{
+				keys:  make(map[cacheKeyRef]struct{}),
+				repos: make(map[cacheRepoRef]struct{}),
+			}
+			r.deps[table] = d
+		}
+		repoRef := cacheRepoRef{provider: provider, repo: repo}
+		if _, ok := d.repos[repoRef]; ok {
+			continue
+		}
+		if len(d.keys) >= maxTableCacheDependencies {
+			// 依赖过多，降级为仓库级失效，并释放该仓库已跟踪的键
+			d.repos[repoRef] = struct{}{}
+			for ref := range d.keys {
+				if ref.provider == provider && ref.repo == repo {
+					delete(d.keys, ref)
+				}
+			}
+			continue
+		}
+		d.keys[cacheKeyRef{provider: provider, repo: repo, key: key}] = struct{}{}
+	}
+}
+
+// invalidate 失效依赖指定表的全部缓存
+func (r *tableCacheDependencyRegistry) invalidate(table string) {
+	table = normalizeDependencyTable(table)
+	if table == "" {
+		return
+	}
+	r.mu.Lock()
+	d, ok := r.deps[table]
+	if ok {
+		delete(r.deps, table)
+	}
+	r.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	for ref := range d.repos {
+		ref.provider.CacheClearRepository(ref.repo)
+	}
+	for ref := range d.keys {
+		ref.provider.CacheDelete(ref.repo, ref.key)
+	}
+}
+
+// isEmpty 快速判断是否存在任何依赖，避免无依赖时解析 SQL
+func (r *tableCacheDependencyRegistry) isEmpty() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.deps) == 0
+}
+
+// normalizeDependencyTable 统一表名格式（去掉 schema 前缀和引号，转小写）
+func normalizeDependencyTable(table string) string {
+	table = strings.TrimSpace(table)
+	table = strings.Trim(table, "`\"[]")
+	if idx := strings.LastIndex(table, "."); idx >= 0 {
+		table = table[idx+1:]
+	}
+	return strings.ToLower(strings.Trim(table, "`\"[]"))
+}
+
+// writeTargetTableRe 匹配写操作 SQL 的目标表
+var writeTargetTableRe = regexp.MustCompile(`(?i)^\s*(?:INSERT\s+(?:IGNORE\s+)?INTO|REPLACE\s+INTO|MERGE\s+INTO|UPDATE|DELETE\s+FROM|TRUNCATE\s+TABLE|TRUNCATE)\s+([\w.` + "`" + `"\[\]]+)`)
+
+// extractWriteTargetTable 从写操作 SQL 中提取目标表名，非写操作返回空字符串
+func extractWriteTargetTable(querySQL string) string {
+	matches := writeTargetTableRe.FindStringSubmatch(querySQL)
+	if len(matches) < 2 {
+		return ""
+	}
+	return matches[1]
+}
+
+// afterTableWrite 写操作成功后的统一处理：失效依赖该表的缓存和该表全部的实体缓存
+// 事务中的写操作在语句执行成功后立即失效缓存，并在事务提交后再失效一次，
+// 防止并发读取在提交前把旧数据重新写回缓存
+func (mgr *dbManager) afterTableWrite(executor sqlExecutor, table string, err error) {
+	if err != nil {
+		return
+	}
+	mgr.invalidateAfterWrite(executor, func() {
+		mgr.invalidateTableCaches(table)
+		if table == "" {
+			// 无法确定目标表时清空全部实体缓存
+			mgr.clearEntities("")
+			return
+		}
+		mgr.evictEntities(table, "", nil)
+	})
+}
+
+// afterKeyedWrite 按条件写操作成功后的处理，条件为主键等值时只失效对应的实体缓存
+func (mgr *dbManager) afterKeyedWrite(executor sqlExecutor, table, where string, whereArgs []interface{}, err error) {
+	if err != nil {
+		return
+	}
+	mgr.invalidateAfterWrite(executor, func() {
+		mgr.invalidateTableCaches(table)
+		mgr.evictEntities(table, where, whereArgs)
+	})
+}
+
+// afterRecordsWrite 按记录写操作成功后的处理，按记录中的主键值失效实体缓存（插入传入 nil）
+func (mgr *dbManager) afterRecordsWrite(executor sqlExecutor, table string, records []*Record, err error) {
+	if err != nil {
+		return
+	}
+	mgr.invalidateAfterWrite(executor, func() {
+		mgr.invalidateTableCaches(table)
+		mgr.evictEntityRecords(table, records)
+	})
+}
+
+// afterIdsWrite 按主键值列表写操作成功后的处理
+func (mgr *dbManager) afterIdsWrite(executor sqlExecutor, table string, ids []interface{}, err error) {
+	if err != nil {
+		return
+	}
+	mgr.invalidateAfterWrite(executor, func() {
+		mgr.invalidateTableCaches(table)
+		mgr.evictEntityIds(table, ids)
+	})
+}
+
+// invalidateAfterWrite 立即执行缓存失效，在 eorm 开启的事务中时提交后再执行一次
+func (mgr *dbManager) invalidateAfterWrite(executor sqlExecutor, invalidate func()) {
+	invalidate()
+	mgr.afterCommit(executor, invalidate)
+}
+
+// invalidateTableCaches 失效依赖该表的查询缓存
+func (mgr *dbManager) invalidateTableCaches(table string) {
+	atomic.AddUint64(&mgr.writeGen, 1)
+	if table == "" {
+		return
+	}
+	tableCacheDependencies.invalidate(table)
+}
+
+// afterSQLWrite 原始 SQL 写操作成功后的处理，从 SQL 中解析目标表
+func (mgr *dbManager) afterSQLWrite(executor sqlExecutor, querySQL string, err error) {
+	if err != nil {
+		return
+	}
+	if tableCacheDependencies.isEmpty() && mgr.entityCaches == nil {
+		// 既没有依赖登记也没有实体缓存时无需解析 SQL
+		atomic.AddUint64(&mgr.writeGen, 1)
+		return
+	}
+	mgr.afterTableWrite(executor, extractWriteTargetTable(querySQL), nil)
+}
+
+// RegisterCacheDependency 手动登记缓存键依赖的表
+// 之后通过 eorm 对这些表执行写操作时，该缓存键会被自动删除
+func RegisterCacheDependency(provider CacheProvider, cacheRepositoryName, key string, tables ...string) {
+	if provider == nil {
+		provider = GetCache()
+	}
+	tableCacheDependencies.register(tables, provider, cacheRepositoryName, key)
+}
+
+// InvalidateTableCache 手动失效依赖指定表的全部缓存（例如数据被 eorm 之外的程序修改后）
+func InvalidateTableCache(tables ...string) {
+	for _, table := range tables {
+		tableCacheDependencies.invalidate(table)
+	}
+}
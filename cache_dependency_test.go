@@ -0,0 +1,35 @@
+package eorm
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTableCacheDependencyInvalidatedAgainAtCommit(t *testing.T) {
+	db := openTestDB(t, "CREATE TABLE cd_items (id INTEGER PRIMARY KEY, name TEXT)")
+	mustExec(t, db, "INSERT INTO cd_items (id, name) VALUES (1, 'a')")
+	cache := newLocalCache(time.Minute)
+	fill := func() {
+		cache.CacheSet("cd_repo", "items", "stale", time.Minute)
+		RegisterCacheDependency(cache, "cd_repo", "items", "cd_items")
+	}
+	fill()
+
+	err := db.Transaction(func(tx *Tx) error {
+		if _, err := tx.Exec("UPDATE cd_items SET name = 'b' WHERE id = 1"); err != nil {
+			return err
+		}
+		if _, ok := cache.CacheGet("cd_repo", "items"); ok {
+			t.Error("dependent cache kept after the write")
+		}
+		// 提交前的并发读取重新填充缓存
+		fill()
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Transaction: %v", err)
+	}
+	if _, ok := cache.CacheGet("cd_repo", "items"); ok {
+		t.Fatal("dependent cache refilled before commit survived the commit")
+	}
+}
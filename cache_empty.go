@@ -0,0 +1,82 @@
+package eorm
+
+import (
+	"reflect"
+	"sync"
+	"time"
+)
+
+// NoStoreEmpty 表示不缓存空结果（默认策略）
+// 可用于 SetCacheEmptyTTL 或各构建器的 CacheEmpty 方法
+const NoStoreEmpty time.Duration = -1
+
+// cacheEmptyMarker 空结果（未找到记录）的缓存占位值
+// 使用字符串以便 LocalCache 与 RedisCache 都能原样存取
+const cacheEmptyMarker = "__eorm_empty__"
+
+// cacheEmptyConfigs 按缓存仓库配置的空结果缓存时间 map[cacheRepositoryName]time.Duration
+var cacheEmptyConfigs sync.Map
+
+// SetCacheEmptyTTL 为缓存仓库配置空结果缓存（负缓存）
+// ttl > 0 时空结果（空列表、未找到记录）以该时间缓存，通常应短于正常结果的 TTL
+// ttl <= 0（或 NoStoreEmpty）时不缓存空结果
+// 示例: eorm.SetCacheEmptyTTL("user_cache", 10*time.Second)
+func SetCacheEmptyTTL(cacheRepositoryName string, ttl time.Duration) {
+	if ttl <= 0 {
+		cacheEmptyConfigs.Delete(cacheRepositoryName)
+		return
+	}
+	cacheEmptyConfigs.Store(cacheRepositoryName, ttl)
+}
+
+// getEffectiveEmptyTTL 获取空结果的有效缓存时间，返回值 <= 0 表示不缓存空结果
+// customTTL: 0 表示使用仓库配置，> 0 表示使用指定时间，< 0 表示不缓存
+func getEffectiveEmptyTTL(cacheRepositoryName string, customTTL time.Duration) time.Duration {
+	if customTTL != 0 {
+		return customTTL
+	}
+	if configTTL, ok := cacheEmptyConfigs.Load(cacheRepositoryName); ok {
+		return configTTL.(time.Duration)
+	}
+	return NoStoreEmpty
+}
+
+// isCacheEmptyMarker 判断缓存值是否为空结果占位值
+func isCacheEmptyMarker(val interface{}) bool {
+	switch v := val.(type) {
+	case string:
+		return v == cacheEmptyMarker
+	case []byte:
+		return string(v) == cacheEmptyMarker
+	}
+	return false
+}
+
+// storeCacheResult 写入查询结果缓存，空结果按负缓存策略处理
+// value 为 nil 或 nil 指针时（例如 QueryFirst 未找到记录返回的 (*Record)(nil)）写入占位值
+// 返回是否实际写入了缓存
+func storeCacheResult(cache CacheProvider, cacheRepositoryName, key string, value interface{}, empty bool, ttl, emptyTTL time.Duration) bool {
+	if !empty {
+		cacheSet(cache, cacheRepositoryName, key, value, ttl)
+		return true
+	}
+	emptyTTL = getEffectiveEmptyTTL(cacheRepositoryName, emptyTTL)
+	if emptyTTL <= 0 {
+		return false
+	}
+	if isNilCacheValue(value) {
+		value = cacheEmptyMarker
+	}
+	cacheSet(cache, cacheRepositoryName, key, value, emptyTTL)
+	return true
+}
+
+// isNilCacheValue 判断缓存值是否为 nil 或带类型的 nil 指针
+// 带类型的 nil 指针经 RedisCache 序列化后为 "null"，读取时无法与未命中区分，必须换成占位值
+func isNilCacheValue(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
@@ -0,0 +1,127 @@
+package eorm
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+)
+
+// jsonCache 模拟 RedisCache 的存取行为：字符串和字节数组原样存储，其他值序列化为 JSON，读取时返回字节数组
+type jsonCache struct {
+	mu   sync.Mutex
+	data map[string][]byte
+}
+
+func newJSONCache() *jsonCache {
+	return &jsonCache{data: make(map[string][]byte)}
+}
+
+func (c *jsonCache) CacheGet(cacheRepositoryName, key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	val, ok := c.data[cacheRepositoryName+":"+key]
+	return val, ok
+}
+
+func (c *jsonCache) CacheSet(cacheRepositoryName, key string, value interface{}, ttl time.Duration) {
+	var data []byte
+	switch v := value.(type) {
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		var err error
+		if data, err = json.Marshal(v); err != nil {
+			return
+		}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.data[cacheRepositoryName+":"+key] = data
+}
+
+func (c *jsonCache) CacheDelete(cacheRepositoryName, key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.data, cacheRepositoryName+":"+key)
+}
+
+func (c *jsonCache) CacheClearRepository(cacheRepositoryName string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.data = make(map[string][]byte)
+}
+
+func (c *jsonCache) Status() map[string]interface{} {
+	return map[string]interface{}{"type": "json"}
+}
+
+func TestQueryFirstCachesEmptyResult(t *testing.T) {
+	providers := map[string]func() CacheProvider{
+		"local": func() CacheProvider { return newLocalCache(time.Minute) },
+		"json":  func() CacheProvider { return newJSONCache() },
+	}
+	for name, newProvider := range providers {
+		t.Run(name, func(t *testing.T) {
+			db := openTestDB(t, "CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT)")
+			cache := newProvider()
+			prev := GetCache()
+			SetDefaultCache(cache)
+			t.Cleanup(func() { SetDefaultCache(prev) })
+
+			repo := "empty_" + name
+			querySQL := "SELECT * FROM users WHERE name = ?"
+			record, err := db.Cache(repo, time.Minute).CacheEmpty(time.Minute).QueryFirst(querySQL, "alice")
+			if err != nil || record != nil {
+				t.Fatalf("first QueryFirst = %v, %v; want nil, nil", record, err)
+			}
+			key := GenerateCacheKey(db.dbMgr.name, querySQL, "alice")
+			if val, ok := cache.CacheGet(repo, key); !ok || !isCacheEmptyMarker(val) {
+				t.Fatalf("cached value = %v (found %v), want empty marker", val, ok)
+			}
+
+			builderRecord, err := db.Table("users").Where("name = ?", "alice").Cache(repo, time.Minute).CacheEmpty(time.Minute).QueryFirst()
+			if err != nil || builderRecord != nil {
+				t.Fatalf("first builder QueryFirst = %v, %v; want nil, nil", builderRecord, err)
+			}
+
+			// 直接写入底层连接，绕过缓存失效，命中负缓存时仍应返回 nil
+			rawExec(t, db, "INSERT INTO users (id, name) VALUES (1, 'alice')")
+			record, err = db.Cache(repo, time.Minute).CacheEmpty(time.Minute).QueryFirst(querySQL, "alice")
+			if err != nil || record != nil {
+				t.Fatalf("cached QueryFirst = %v, %v; want nil, nil", record, err)
+			}
+			builderRecord, err = db.Table("users").Where("name = ?", "alice").Cache(repo, time.Minute).CacheEmpty(time.Minute).QueryFirst()
+			if err != nil || builderRecord != nil {
+				t.Fatalf("cached builder QueryFirst = %v, %v; want nil, nil", builderRecord, err)
+			}
+		})
+	}
+}
+
+func TestBuilderQueryFirstReadsSerializedCache(t *testing.T) {
+	db := openTestDB(t, "CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT)")
+	mustExec(t, db, "INSERT INTO users (id, name) VALUES (1, 'alice')")
+	cache := newJSONCache()
+	prev := GetCache()
+	SetDefaultCache(cache)
+	t.Cleanup(func() { SetDefaultCache(prev) })
+
+	query := func() *Record {
+		t.Helper()
+		record, err := db.Table("users").Where("name = ?", "alice").Cache("serialized", time.Minute).QueryFirst()
+		if err != nil {
+			t.Fatalf("QueryFirst: %v", err)
+		}
+		return record
+	}
+	if record := query(); record == nil || record.GetString("name") != "alice" {
+		t.Fatalf("first QueryFirst = %v, want alice", record)
+	}
+	rawExec(t, db, "DELETE FROM users")
+	if record := query(); record == nil || record.GetString("name") != "alice" {
+		t.Fatalf("cached QueryFirst = %v, want alice from cache", record)
+	}
+}
@@ -0,0 +1,323 @@
+package eorm
+
+import (
+	"container/heap"
+	"database/sql"
+	"sync"
+	"sync/atomic"
+)
+
+// CacheEvictionPolicy 本地缓存容量超限时的淘汰策略
+type CacheEvictionPolicy int
+
+const (
+	// EvictLRU 淘汰最久未访问的缓存项（默认）
+	EvictLRU CacheEvictionPolicy = iota
+	// EvictLFU 淘汰访问次数最少的缓存项，访问次数相同时淘汰最久未访问的
+	EvictLFU
+)
+
+// String 返回淘汰策略名称
+func (p CacheEvictionPolicy) String() string {
+	if p == EvictLFU {
+		return "LFU"
+	}
+	return "LRU"
+}
+
+// LocalCacheLimit 本地缓存仓库的容量限制
+// MaxItems 和 MaxBytes 任意一项 <= 0 表示不限制该项；两项都不限制时等同于移除限制
+// MaxBytes 按 estimateSize 估算，只用于约束数量级，不等于实际占用的堆内存
+type LocalCacheLimit struct {
+	MaxItems int
+	MaxBytes int64
+	Policy   CacheEvictionPolicy
+}
+
+func (l LocalCacheLimit) enabled() bool {
+	return l.MaxItems > 0 || l.MaxBytes > 0
+}
+
+// localCacheLimits 按缓存仓库配置的容量限制 map[cacheRepositoryName]LocalCacheLimit
+var localCacheLimits sync.Map
+
+// SetLocalCacheLimit 为本地缓存仓库设置容量上限和淘汰策略
+// 超出上限时按策略淘汰缓存项，淘汰次数可通过 LocalCacheStatus 查看
+// 示例: eorm.SetLocalCacheLimit("user_cache", eorm.LocalCacheLimit{MaxItems: 10000, MaxBytes: 64 << 20, Policy: eorm.EvictLRU})
+func SetLocalCacheLimit(cacheRepositoryName string, limit LocalCacheLimit) {
+	if cacheRepositoryName == "" {
+		return
+	}
+	if limit.enabled() {
+		localCacheLimits.Store(cacheRepositoryName, limit)
+	} else {
+		localCacheLimits.Delete(cacheRepositoryName)
+	}
+	if lc, ok := GetLocalCacheInstance().(*localCache); ok {
+		lc.applyLimit(cacheRepositoryName)
+	}
+}
+
+// getLocalCacheLimit 获取缓存仓库的容量限制
+func getLocalCacheLimit(cacheRepositoryName string) (LocalCacheLimit, bool) {
+	if v, ok := localCacheLimits.Load(cacheRepositoryName); ok {
+		return v.(LocalCacheLimit), true
+	}
+	return LocalCacheLimit{}, false
+}
+
+// evictionItem 被跟踪的缓存项
+type evictionItem struct {
+	key        string
+	size       int64
+	hits       int64
+	lastAccess int64 // 逻辑时钟，越大表示越近访问
+	index      int   // 在堆中的位置
+}
+
+// evictionHeap 按淘汰优先级排列的最小堆，堆顶为下一个被淘汰的缓存项
+type evictionHeap struct {
+	items  []*evictionItem
+	policy CacheEvictionPolicy
+}
+
+func (h *evictionHeap) Len() int { return len(h.items) }
+
+func (h *evictionHeap) Less(i, j int) bool {
+	a, b := h.items[i], h.items[j]
+	if h.policy == EvictLFU && a.hits != b.hits {
+		return a.hits < b.hits
+	}
+	return a.lastAccess < b.lastAccess
+}
+
+func (h *evictionHeap) Swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+	h.items[i].index = i
+	h.items[j].index = j
+}
+
+func (h *evictionHeap) Push(x interface{}) {
+	item := x.(*evictionItem)
+	item.index = len(h.items)
+	h.items = append(h.items, item)
+}
+
+func (h *evictionHeap) Pop() interface{} {
+	n := len(h.items)
+	item := h.items[n-1]
+	h.items[n-1] = nil
+	h.items = h.items[:n-1]
+	item.index = -1
+	return item
+}
+
+// cacheLimiter 跟踪单个缓存仓库的容量并执行淘汰
+// 仅在仓库配置了容量限制时创建，未配置的仓库不产生任何额外开销
+type cacheLimiter struct {
+	mu        sync.Mutex
+	limit     LocalCacheLimit
+	items     map[string]*evictionItem
+	heap      evictionHeap
+	bytes     int64
+	clock     int64
+	evictions int64 // 原子操作
+}
+
+func newCacheLimiter(limit LocalCacheLimit) *cacheLimiter {
+	return &cacheLimiter{
+		limit: limit,
+		items: make(map[string]*evictionItem),
+		heap:  evictionHeap{policy: limit.Policy},
+	}
+}
+
+// setLimit 更新容量限制，策略变化时重建堆
+func (cl *cacheLimiter) setLimit(limit LocalCacheLimit) {
+	cl.limit = limit
+	if cl.heap.policy != limit.Policy {
+		cl.heap.policy = limit.Policy
+		heap.Init(&cl.heap)
+	}
+}
+
+// track 记录写入的缓存项（调用方持有 cl.mu）
+func (cl *cacheLimiter) track(key string, size int64) {
+	cl.clock++
+	if item, ok := cl.items[key]; ok {
+		cl.bytes += size - item.size
+		item.size = size
+		item.hits++
+		item.lastAccess = cl.clock
+		heap.Fix(&cl.heap, item.index)
+		return
+	}
+	item := &evictionItem{key: key, size: size, hits: 1, lastAccess: cl.clock}
+	cl.items[key] = item
+	cl.bytes += size
+	heap.Push(&cl.heap, item)
+}
+
+// touch 记录一次缓存命中
+func (cl *cacheLimiter) touch(key string) {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	if item, ok := cl.items[key]; ok {
+		cl.clock++
+		item.hits++
+		item.lastAccess = cl.clock
+		heap.Fix(&cl.heap, item.index)
+	}
+}
+
+// untrack 移除缓存项的跟踪记录（调用方持有 cl.mu）
+func (cl *cacheLimiter) untrack(key string) {
+	if item, ok := cl.items[key]; ok {
+		heap.Remove(&cl.heap, item.index)
+		delete(cl.items, key)
+		cl.bytes -= item.size
+	}
+}
+
+// reset 清空全部跟踪记录（调用方持有 cl.mu）
+func (cl *cacheLimiter) reset() {
+	cl.items = make(map[string]*evictionItem)
+	cl.heap.items = nil
+	cl.bytes = 0
+}
+
+// overLimit 判断是否超出容量限制（调用方持有 cl.mu）
+func (cl *cacheLimiter) overLimit() bool {
+	if cl.limit.MaxItems > 0 && len(cl.items) > cl.limit.MaxItems {
+		return true
+	}
+	return cl.limit.MaxBytes > 0 && cl.bytes > cl.limit.MaxBytes
+}
+
+// evict 按策略淘汰缓存项直到满足容量限制（调用方持有 cl.mu）
+func (cl *cacheLimiter) evict(store *sync.Map, isStmtCache bool) {
+	for cl.overLimit() && cl.heap.Len() > 0 {
+		item := heap.Pop(&cl.heap).(*evictionItem)
+		delete(cl.items, item.key)
+		cl.bytes -= item.size
+		if v, ok := store.LoadAndDelete(item.key); ok && isStmtCache {
+			if stmt, ok := v.(cacheEntry).value.(*sql.Stmt); ok {
+				stmt.Close()
+			}
+		}
+		atomic.AddInt64(&cl.evictions, 1)
+	}
+}
+
+// stats 返回容量统计信息
+func (cl *cacheLimiter) stats() map[string]interface{} {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	return map[string]interface{}{
+		"items":     len(cl.items),
+		"bytes":     cl.bytes,
+		"max_items": cl.limit.MaxItems,
+		"max_bytes": cl.limit.MaxBytes,
+		"policy":    cl.limit.Policy.String(),
+		"evictions": atomic.LoadInt64(&cl.evictions),
+	}
+}
+
+// getLimiter 获取缓存仓库的容量跟踪器，未配置容量限制时返回 nil
+func (lc *localCache) getLimiter(cacheRepositoryName string) *cacheLimiter {
+	limit, ok := getLocalCacheLimit(cacheRepositoryName)
+	if !ok {
+		if v, ok := lc.limiters.Load(cacheRepositoryName); ok {
+			// 限制已被移除，停止跟踪
+			lc.limiters.Delete(cacheRepositoryName)
+			cl := v.(*cacheLimiter)
+			cl.mu.Lock()
+			cl.reset()
+			cl.mu.Unlock()
+		}
+		return nil
+	}
+	if v, ok := lc.limiters.Load(cacheRepositoryName); ok {
+		return v.(*cacheLimiter)
+	}
+	v, _ := lc.limiters.LoadOrStore(cacheRepositoryName, newCacheLimiter(limit))
+	return v.(*cacheLimiter)
+}
+
+// applyLimit 应用最新的容量限制：跟踪仓库中已有的缓存项并立即淘汰超出部分
+func (lc *localCache) applyLimit(cacheRepositoryName string) {
+	cl := lc.getLimiter(cacheRepositoryName)
+	if cl == nil {
+		return
+	}
+	limit, _ := getLocalCacheLimit(cacheRepositoryName)
+	store, _ := lc.stores.LoadOrStore(cacheRepositoryName, &sync.Map{})
+	s := store.(*sync.Map)
+
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	cl.setLimit(limit)
+	s.Range(func(key, value interface{}) bool {
+		k, ok := key.(string)
+		if !ok {
+			return true
+		}
+		if _, tracked := cl.items[k]; !tracked {
+			cl.track(k, estimateSize(k)+estimateSize(value.(cacheEntry).value))
+		}
+		return true
+	})
+	cl.evict(s, cacheRepositoryName == StmtCacheRepository)
+}
+
+// limitedSet 在容量限制下写入缓存项，必要时淘汰旧缓存项
+func (lc *localCache) limitedSet(cl *cacheLimiter, cacheRepositoryName string, store *sync.Map, key string, entry cacheEntry) {
+	limit, _ := getLocalCacheLimit(cacheRepositoryName)
+	size := estimateSize(key) + estimateSize(entry.value)
+
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	cl.setLimit(limit)
+	store.Store(key, entry)
+	cl.track(key, size)
+	cl.evict(store, cacheRepositoryName == StmtCacheRepository)
+}
+
+// limitedDelete 删除缓存项，仓库受容量限制时同时移除跟踪记录
+// 返回被删除的缓存项
+func (lc *localCache) limitedDelete(cacheRepositoryName string, store *sync.Map, key interface{}) (interface{}, bool) {
+	v, ok := lc.limiters.Load(cacheRepositoryName)
+	if !ok {
+		return store.LoadAndDelete(key)
+	}
+	cl := v.(*cacheLimiter)
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	if k, ok := key.(string); ok {
+		cl.untrack(k)
+	}
+	return store.LoadAndDelete(key)
+}
+
+// limitedClear 清空仓库的跟踪记录（保留淘汰计数）
+func (lc *localCache) limitedClear(cacheRepositoryName string) {
+	if v, ok := lc.limiters.Load(cacheRepositoryName); ok {
+		cl := v.(*cacheLimiter)
+		cl.mu.Lock()
+		cl.reset()
+		cl.mu.Unlock()
+	}
+}
+
+// limiterStats 汇总所有受限仓库的容量统计
+func (lc *localCache) limiterStats() (map[string]interface{}, int64) {
+	repos := make(map[string]interface{})
+	var totalEvictions int64
+	lc.limiters.Range(func(name, v interface{}) bool {
+		st := v.(*cacheLimiter).stats()
+		totalEvictions += st["evictions"].(int64)
+		repos[name.(string)] = st
+		return true
+	})
+	return repos, totalEvictions
+}
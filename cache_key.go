@@ -0,0 +1,99 @@
+package eorm
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// CacheKeyBuilder 查询缓存键生成器
+// 所有基于 SQL 和参数的查询缓存（Cache/LocalCache/RedisCache、QueryBuilder、SqlTemplate、缓存预热）都通过它生成缓存键，
+// 自定义实现可用于规范化缓存键，例如忽略与结果无关的参数（请求追踪 ID）、统一 SQL 空白、拼入租户 ID 等
+// ctx 为执行查询的上下文（通过 WithContext 设置，未设置时为 context.Background()），可从中读取租户等请求信息
+type CacheKeyBuilder interface {
+	BuildCacheKey(ctx context.Context, dbName, sql string, args []interface{}) string
+}
+
+// CacheKeyBuilderFunc 函数形式的 CacheKeyBuilder
+type CacheKeyBuilderFunc func(ctx context.Context, dbName, sql string, args []interface{}) string
+
+// BuildCacheKey 实现 CacheKeyBuilder
+func (f CacheKeyBuilderFunc) BuildCacheKey(ctx context.Context, dbName, sql string, args []interface{}) string {
+	return f(ctx, dbName, sql, args)
+}
+
+// DefaultCacheKeyBuilder 默认缓存键生成器：对数据库名、SQL 和参数做 MD5
+// 自定义生成器可在规范化参数后委托给它
+type DefaultCacheKeyBuilder struct{}
+
+// BuildCacheKey 实现 CacheKeyBuilder，不使用 ctx
+func (DefaultCacheKeyBuilder) BuildCacheKey(ctx context.Context, dbName, sql string, args []interface{}) string {
+	hash := md5.New()
+	hash.Write([]byte(dbName))
+	hash.Write([]byte(sql))
+	if len(args) > 0 {
+		hash.Write([]byte(fmt.Sprintf("%v", args)))
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+var (
+	cacheKeyBuilderMu sync.RWMutex
+	cacheKeyBuilder   CacheKeyBuilder = DefaultCacheKeyBuilder{}
+	debugCacheKeys    int32
+)
+
+// SetCacheKeyBuilder 设置全局缓存键生成器，传入 nil 恢复默认实现
+// 更换生成器后旧缓存键不再命中，已有缓存会在过期后自然淘汰
+// 示例（忽略最后一个追踪参数并按租户隔离）:
+//
+//	eorm.SetCacheKeyBuilder(eorm.CacheKeyBuilderFunc(func(ctx context.Context, dbName, sql string, args []interface{}) string {
+//	    if len(args) > 0 {
+//	        args = args[:len(args)-1]
+//	    }
+//	    return tenantFromContext(ctx) + ":" + eorm.DefaultCacheKeyBuilder{}.BuildCacheKey(ctx, dbName, sql, args)
+//	}))
+func SetCacheKeyBuilder(builder CacheKeyBuilder) {
+	if builder == nil {
+		builder = DefaultCacheKeyBuilder{}
+	}
+	cacheKeyBuilderMu.Lock()
+	cacheKeyBuilder = builder
+	cacheKeyBuilderMu.Unlock()
+}
+
+// GetCacheKeyBuilder 返回当前的全局缓存键生成器
+func GetCacheKeyBuilder() CacheKeyBuilder {
+	cacheKeyBuilderMu.RLock()
+	defer cacheKeyBuilderMu.RUnlock()
+	return cacheKeyBuilder
+}
+
+// SetDebugCacheKeys 开启后每次查询缓存读取都会以 Info 级别输出最终缓存键（含分页/First/Count 等后缀）、
+// 缓存库名称及是否命中，便于排查缓存键不一致导致的未命中
+func SetDebugCacheKeys(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&debugCacheKeys, v)
+}
+
+// IsDebugCacheKeysEnabled 返回是否开启了缓存键调试输出
+func IsDebugCacheKeysEnabled() bool {
+	return atomic.LoadInt32(&debugCacheKeys) == 1
+}
+
+// logCacheKey 在缓存键调试模式下输出一次缓存读取
+func logCacheKey(cacheRepositoryName, key string, hit bool) {
+	if !IsDebugCacheKeysEnabled() {
+		return
+	}
+	LogInfo("缓存键", NewRecord().
+		Set("repository", cacheRepositoryName).
+		Set("key", key).
+		Set("hit", hit))
+}
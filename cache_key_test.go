@@ -0,0 +1,50 @@
+package eorm
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type cacheKeyTenant struct{}
+
+func TestCacheKeyBuilderReceivesQueryContext(t *testing.T) {
+	db := openTestDB(t, "CREATE TABLE ck_items (id INTEGER PRIMARY KEY, name TEXT)")
+	mustExec(t, db, "INSERT INTO ck_items (id, name) VALUES (1, 'a')")
+	prevCache := GetCache()
+	SetDefaultCache(newLocalCache(time.Minute))
+	t.Cleanup(func() { SetDefaultCache(prevCache) })
+
+	prevBuilder := GetCacheKeyBuilder()
+	SetCacheKeyBuilder(CacheKeyBuilderFunc(func(ctx context.Context, dbName, sql string, args []interface{}) string {
+		tenant, _ := ctx.Value(cacheKeyTenant{}).(string)
+		return tenant + ":" + DefaultCacheKeyBuilder{}.BuildCacheKey(ctx, dbName, sql, args)
+	}))
+	t.Cleanup(func() { SetCacheKeyBuilder(prevBuilder) })
+
+	query := func(tenant string) string {
+		t.Helper()
+		ctx := context.WithValue(context.Background(), cacheKeyTenant{}, tenant)
+		record, err := Use(db.dbMgr.name).WithContext(ctx).Cache("ck_repo", time.Minute).QueryFirst("SELECT name FROM ck_items WHERE id = ?", 1)
+		if err != nil || record == nil {
+			t.Fatalf("QueryFirst for %s: %v, %v", tenant, record, err)
+		}
+		return record.GetString("name")
+	}
+	if got := query("t1"); got != "a" {
+		t.Fatalf("t1 name = %q, want a", got)
+	}
+	rawExec(t, db, "UPDATE ck_items SET name = 'b' WHERE id = 1")
+	if got := query("t1"); got != "a" {
+		t.Fatalf("cached t1 name = %q, want a", got)
+	}
+	if got := query("t2"); got != "b" {
+		t.Fatalf("t2 name = %q, want b: the builder must see the query context and give t2 its own key", got)
+	}
+
+	builderKey := Use(db.dbMgr.name).WithContext(context.WithValue(context.Background(), cacheKeyTenant{}, "t3")).
+		Table("ck_items").Where("id = ?", 1).generateCacheKey("SELECT 1", nil)
+	if builderKey[:3] != "t3:" {
+		t.Fatalf("query builder key = %q, want the t3 prefix", builderKey)
+	}
+}
@@ -0,0 +1,165 @@
+package eorm
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// MultiCacheProvider 支持批量读写的缓存提供者（可选接口）
+// 未实现该接口的缓存提供者会退化为逐个调用 CacheGet/CacheSet
+type MultiCacheProvider interface {
+	// CacheGetMulti 批量获取缓存值，只返回命中的键
+	CacheGetMulti(cacheRepositoryName string, keys []string) map[string]interface{}
+	// CacheSetMulti 批量写入缓存值
+	CacheSetMulti(cacheRepositoryName string, items map[string]interface{}, ttl time.Duration)
+}
+
+// CacheLoader 批量回源函数，参数为未命中的缓存键，返回键对应的值
+// 返回结果中不存在的键视为数据不存在，不会写入缓存
+type CacheLoader func(missingKeys []string) (map[string]interface{}, error)
+
+// cacheGetMulti 使用指定缓存提供者批量获取缓存值
+func cacheGetMulti(cache CacheProvider, cacheRepositoryName string, keys []string) map[string]interface{} {
+	if len(keys) == 0 {
+		return map[string]interface{}{}
+	}
+	if mc, ok := cache.(MultiCacheProvider); ok {
+		return mc.CacheGetMulti(cacheRepositoryName, keys)
+	}
+	result := make(map[string]interface{}, len(keys))
+	for _, key := range keys {
+		if val, ok := cache.CacheGet(cacheRepositoryName, key); ok {
+			result[key] = val
+		}
+	}
+	return result
+}
+
+// cacheSetMulti 使用指定缓存提供者批量写入缓存值
+func cacheSetMulti(cache CacheProvider, cacheRepositoryName string, items map[string]interface{}, ttl time.Duration) {
+	if len(items) == 0 {
+		return
+	}
+	if mc, ok := cache.(MultiCacheProvider); ok {
+		mc.CacheSetMulti(cacheRepositoryName, items, ttl)
+		return
+	}
+	for key, val := range items {
+		cache.CacheSet(cacheRepositoryName, key, val, ttl)
+	}
+}
+
+// CacheGetMulti 从默认缓存中批量获取值，只返回命中的键
+func CacheGetMulti(cacheRepositoryName string, keys []string) map[string]interface{} {
+	return cacheGetMulti(GetCache(), cacheRepositoryName, keys)
+}
+
+// CacheSetMulti 向默认缓存批量写入值
+func CacheSetMulti(cacheRepositoryName string, items map[string]interface{}, ttl ...time.Duration) {
+	expiration := getEffectiveTTL(cacheRepositoryName, -1)
+	if len(ttl) > 0 {
+		expiration = ttl[0]
+	}
+	cacheSetMulti(GetCache(), cacheRepositoryName, items, expiration)
+}
+
+// CacheGetOrLoadMulti 批量获取缓存值，未命中的键通过 loader 一次性回源并写回缓存
+// 整个过程只有一次批量读缓存、一次回源（通常是一条 IN 查询）和一次批量写缓存
+// 命中的值按缓存提供者返回的原样给出（RedisCache 为序列化后的字节或延迟解码值），
+// 需要得到具体类型时使用 CacheGetOrLoadMultiAs
+// 示例:
+//
+//	users, err := eorm.CacheGetOrLoadMulti("user_cache", []string{"1", "2", "3"}, func(missing []string) (map[string]interface{}, error) {
+//	    records, err := loadUsersByIDs(missing) // 一条 SELECT ... WHERE id IN (...) 查询
+//	    if err != nil {
+//	        return nil, err
+//	    }
+//	    loaded := make(map[string]interface{}, len(records))
+//	    for _, r := range records {
+//	        loaded[r.GetString("id")] = r
+//	    }
+//	    return loaded, nil
+//	})
+func CacheGetOrLoadMulti(cacheRepositoryName string, keys []string, loader CacheLoader, ttl ...time.Duration) (map[string]interface{}, error) {
+	return CacheGetOrLoadMultiAs[interface{}](cacheRepositoryName, keys, loader, ttl...)
+}
+
+// CacheGetOrLoadMultiAs 与 CacheGetOrLoadMulti 相同，但返回指定类型的值
+// 命中的值通过缓存提供者的编解码器解码为 T（与查询缓存相同，见 CacheValueDecoder），无法解码的值视为未命中并重新回源
+// 示例:
+//
+//	users, err := eorm.CacheGetOrLoadMultiAs("user_cache", ids, func(missing []string) (map[string]*eorm.Record, error) {
+//	    return loadUsersByIDs(missing)
+//	})
+//	users["1"].GetString("name")
+func CacheGetOrLoadMultiAs[T any](cacheRepositoryName string, keys []string, loader func(missingKeys []string) (map[string]T, error), ttl ...time.Duration) (map[string]T, error) {
+	cache := GetCache()
+	hits := cacheGetMulti(cache, cacheRepositoryName, keys)
+
+	result := make(map[string]T, len(keys))
+	var missing []string
+	seen := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		if _, dup := seen[key]; dup {
+			continue
+		}
+		seen[key] = struct{}{}
+		if val, ok := hits[key]; ok {
+			if v, ok := val.(T); ok {
+				result[key] = v
+				continue
+			}
+			var v T
+			if convertCacheValue(val, &v) {
+				result[key] = v
+				continue
+			}
+		}
+		missing = append(missing, key)
+	}
+
+	c := getRepoCacheCounters(cacheRepositoryName)
+	atomic.AddInt64(&c.hits, int64(len(seen)-len(missing)))
+	atomic.AddInt64(&c.misses, int64(len(missing)))
+	if len(missing) == 0 || loader == nil {
+		return result, nil
+	}
+
+	loaded, err := loader(missing)
+	if err != nil {
+		return result, err
+	}
+	items := make(map[string]interface{}, len(loaded))
+	for _, key := range missing {
+		if val, ok := loaded[key]; ok {
+			items[key] = val
+			result[key] = val
+		}
+	}
+
+	expiration := getEffectiveTTL(cacheRepositoryName, -1)
+	if len(ttl) > 0 {
+		expiration = ttl[0]
+	}
+	cacheSetMulti(cache, cacheRepositoryName, items, expiration)
+	atomic.AddInt64(&c.fills, int64(len(items)))
+	return result, nil
+}
+
+// CacheGetMulti 批量获取缓存值
+func (lc *localCache) CacheGetMulti(cacheRepositoryName string, keys []string) map[string]interface{} {
+	result := make(map[string]interface{}, len(keys))
+	for _, key := range keys {
+		if val, ok := lc.CacheGet(cacheRepositoryName, key); ok {
+			result[key] = val
+		}
+	}
+	return result
+}
+
+// CacheSetMulti 批量写入缓存值
+func (lc *localCache) CacheSetMulti(cacheRepositoryName string, items map[string]interface{}, ttl time.Duration) {
+	for key, val := range items {
+		lc.CacheSet(cacheRepositoryName, key, val, ttl)
+	}
+}
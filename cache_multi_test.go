@@ -0,0 +1,42 @@
+package eorm
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCacheGetOrLoadMultiAsDecodesSerializedHits(t *testing.T) {
+	cache := newJSONCache()
+	prev := GetCache()
+	SetDefaultCache(cache)
+	t.Cleanup(func() { SetDefaultCache(prev) })
+
+	const repo = "multi_as"
+	var loads [][]string
+	loader := func(missing []string) (map[string]*Record, error) {
+		loads = append(loads, missing)
+		loaded := make(map[string]*Record, len(missing))
+		for _, key := range missing {
+			loaded[key] = NewRecord().Set("id", key).Set("name", "user"+key)
+		}
+		return loaded, nil
+	}
+
+	if _, err := CacheGetOrLoadMultiAs(repo, []string{"1", "2"}, loader, time.Minute); err != nil {
+		t.Fatalf("first load: %v", err)
+	}
+	cache.CacheSet(repo, "3", "not a record", time.Minute)
+
+	users, err := CacheGetOrLoadMultiAs(repo, []string{"1", "2", "3"}, loader, time.Minute)
+	if err != nil {
+		t.Fatalf("second load: %v", err)
+	}
+	for _, key := range []string{"1", "2", "3"} {
+		if users[key] == nil || users[key].GetString("name") != "user"+key {
+			t.Fatalf("users[%s] = %v, want a decoded record named user%s", key, users[key], key)
+		}
+	}
+	if len(loads) != 2 || len(loads[1]) != 1 || loads[1][0] != "3" {
+		t.Fatalf("loader calls = %v, want [[1 2] [3]]: cached hits must be decoded, undecodable values reloaded", loads)
+	}
+}
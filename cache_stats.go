@@ -0,0 +1,207 @@
+package eorm
+
+import (
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RepositoryCacheStats 单个缓存仓库的命中统计
+type RepositoryCacheStats struct {
+	Name        string        // 缓存仓库名称
+	Hits        int64         // 命中次数
+	Misses      int64         // 未命中次数
+	HitRate     float64       // 命中率（0~1），无请求时为 0
+	Fills       int64         // 写入缓存的次数
+	AvgFillTime time.Duration // 未命中到写入缓存的平均耗时（即回源耗时）
+	MaxFillTime time.Duration // 未命中到写入缓存的最大耗时
+	Entries     int64         // 本地缓存中该仓库的条目数（仅统计本地缓存）
+}
+
+// maxPendingCacheFills 每个仓库最多记录的未完成回源数量，超过后不再统计新的回源耗时
+const maxPendingCacheFills = 1024
+
+// repoCacheCounters 单个缓存仓库的计数器
+type repoCacheCounters struct {
+	hits          int64 // 原子操作
+	misses        int64 // 原子操作
+	fills         int64 // 原子操作
+	fillNanos     int64 // 原子操作，累计回源耗时
+	fillSamples   int64 // 原子操作，回源耗时样本数
+	maxFillNanos  int64 // 原子操作
+	lastWarnTotal int64 // 原子操作，上次检查命中率时的请求总数
+
+	pendingMu sync.Mutex
+	pending   map[string]time.Time // 缓存键 -> 未命中时间
+}
+
+var (
+	// repoCacheStats 按缓存仓库统计 map[cacheRepositoryName]*repoCacheCounters
+	repoCacheStats sync.Map
+
+	// cacheHitRateThreshold 命中率告警阈值（以 math.Float64bits 存储），0 表示关闭
+	cacheHitRateThreshold uint64
+	// cacheHitRateMinRequests 每累计多少次请求检查一次命中率
+	cacheHitRateMinRequests int64 = 1000
+)
+
+// SetCacheHitRateWarning 设置缓存命中率告警
+// 每个缓存仓库每累计 minRequests 次请求检查一次命中率，低于 threshold（0~1）时输出警告日志
+// threshold <= 0 表示关闭告警
+// 示例: eorm.SetCacheHitRateWarning(0.5, 1000)
+func SetCacheHitRateWarning(threshold float64, minRequests int64) {
+	if minRequests <= 0 {
+		minRequests = 1000
+	}
+	atomic.StoreInt64(&cacheHitRateMinRequests, minRequests)
+	atomic.StoreUint64(&cacheHitRateThreshold, math.Float64bits(threshold))
+}
+
+// CacheRepositoryStats 获取缓存仓库的命中统计
+func CacheRepositoryStats(cacheRepositoryName string) RepositoryCacheStats {
+	stats := RepositoryCacheStats{Name: cacheRepositoryName, Entries: localCacheEntryCount(cacheRepositoryName)}
+	v, ok := repoCacheStats.Load(cacheRepositoryName)
+	if !ok {
+		return stats
+	}
+	c := v.(*repoCacheCounters)
+	stats.Hits = atomic.LoadInt64(&c.hits)
+	stats.Misses = atomic.LoadInt64(&c.misses)
+	if total := stats.Hits + stats.Misses; total > 0 {
+		stats.HitRate = float64(stats.Hits) / float64(total)
+	}
+	stats.Fills = atomic.LoadInt64(&c.fills)
+	if samples := atomic.LoadInt64(&c.fillSamples); samples > 0 {
+		stats.AvgFillTime = time.Duration(atomic.LoadInt64(&c.fillNanos) / samples)
+	}
+	stats.MaxFillTime = time.Duration(atomic.LoadInt64(&c.maxFillNanos))
+	return stats
+}
+
+// AllCacheRepositoryStats 获取所有已产生请求的缓存仓库的命中统计
+func AllCacheRepositoryStats() map[string]RepositoryCacheStats {
+	result := make(map[string]RepositoryCacheStats)
+	repoCacheStats.Range(func(name, _ interface{}) bool {
+		result[name.(string)] = CacheRepositoryStats(name.(string))
+		return true
+	})
+	return result
+}
+
+// ResetCacheRepositoryStats 重置缓存仓库的命中统计，不传参数时重置全部
+func ResetCacheRepositoryStats(cacheRepositoryNames ...string) {
+	if len(cacheRepositoryNames) == 0 {
+		repoCacheStats.Range(func(name, _ interface{}) bool {
+			repoCacheStats.Delete(name)
+			return true
+		})
+		return
+	}
+	for _, name := range cacheRepositoryNames {
+		repoCacheStats.Delete(name)
+	}
+}
+
+// getRepoCacheCounters 获取或创建缓存仓库的计数器
+func getRepoCacheCounters(cacheRepositoryName string) *repoCacheCounters {
+	if v, ok := repoCacheStats.Load(cacheRepositoryName); ok {
+		return v.(*repoCacheCounters)
+	}
+	v, _ := repoCacheStats.LoadOrStore(cacheRepositoryName, &repoCacheCounters{pending: make(map[string]time.Time)})
+	return v.(*repoCacheCounters)
+}
+
+// cacheGet 读取查询结果缓存并记录命中统计
+func cacheGet(cache CacheProvider, cacheRepositoryName, key string) (interface{}, bool) {
+	val, ok := cache.CacheGet(cacheRepositoryName, key)
+	logCacheKey(cacheRepositoryName, key, ok)
+	c := getRepoCacheCounters(cacheRepositoryName)
+	if ok {
+		atomic.AddInt64(&c.hits, 1)
+	} else {
+		atomic.AddInt64(&c.misses, 1)
+		c.pendingMu.Lock()
+		if len(c.pending) >= maxPendingCacheFills {
+			// 清理长时间未完成的回源记录（例如查询失败后不会写入缓存）
+			for k, missAt := range c.pending {
+				if time.Since(missAt) > time.Minute {
+					delete(c.pending, k)
+				}
+			}
+		}
+		if len(c.pending) < maxPendingCacheFills {
+			c.pending[key] = time.Now()
+		}
+		c.pendingMu.Unlock()
+	}
+	c.checkHitRate(cacheRepositoryName)
+	return val, ok
+}
+
+// cacheSet 写入查询结果缓存并记录回源耗时
+func cacheSet(cache CacheProvider, cacheRepositoryName, key string, value interface{}, ttl time.Duration) {
+	cache.CacheSet(cacheRepositoryName, key, value, ttl)
+	c := getRepoCacheCounters(cacheRepositoryName)
+	atomic.AddInt64(&c.fills, 1)
+
+	c.pendingMu.Lock()
+	missAt, ok := c.pending[key]
+	if ok {
+		delete(c.pending, key)
+	}
+	c.pendingMu.Unlock()
+	if !ok {
+		return
+	}
+	elapsed := int64(time.Since(missAt))
+	atomic.AddInt64(&c.fillNanos, elapsed)
+	atomic.AddInt64(&c.fillSamples, 1)
+	for {
+		old := atomic.LoadInt64(&c.maxFillNanos)
+		if elapsed <= old || atomic.CompareAndSwapInt64(&c.maxFillNanos, old, elapsed) {
+			break
+		}
+	}
+}
+
+// checkHitRate 每累计一定请求数检查一次命中率，低于阈值时输出警告
+func (c *repoCacheCounters) checkHitRate(cacheRepositoryName string) {
+	threshold := math.Float64frombits(atomic.LoadUint64(&cacheHitRateThreshold))
+	if threshold <= 0 {
+		return
+	}
+	hits := atomic.LoadInt64(&c.hits)
+	total := hits + atomic.LoadInt64(&c.misses)
+	last := atomic.LoadInt64(&c.lastWarnTotal)
+	if total-last < atomic.LoadInt64(&cacheHitRateMinRequests) || !atomic.CompareAndSwapInt64(&c.lastWarnTotal, last, total) {
+		return
+	}
+
+	if hitRate := float64(hits) / float64(total); hitRate < threshold {
+		LogWarn("缓存命中率过低", NewRecord().
+			Set("cache_repository", cacheRepositoryName).
+			Set("hit_rate", hitRate).
+			Set("threshold", threshold).
+			Set("hits", hits).
+			Set("requests", total))
+	}
+}
+
+// localCacheEntryCount 统计本地缓存中指定仓库的条目数，本地缓存被替换为自定义实现时返回 -1
+func localCacheEntryCount(cacheRepositoryName string) int64 {
+	lc, ok := GetLocalCacheInstance().(*localCache)
+	if !ok {
+		return -1
+	}
+	store, ok := lc.stores.Load(cacheRepositoryName)
+	if !ok {
+		return 0
+	}
+	var count int64
+	store.(*sync.Map).Range(func(_, _ interface{}) bool {
+		count++
+		return true
+	})
+	return count
+}
@@ -0,0 +1,105 @@
+package eorm
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// cacheFreshSuffix 新鲜度标记的缓存键后缀
+// 开启 StaleWhileRevalidate 时，结果以 ttl+maxStale 缓存，另写入一个以 ttl 过期的标记键，
+// 标记键不存在即表示结果已过期但仍可返回，这样任意 CacheProvider（包括 Redis）都无需改变值的存储格式
+const cacheFreshSuffix = "__eorm_fresh"
+
+// swrRefreshing 正在后台刷新的缓存键，保证同一个键同时只有一个刷新任务
+var swrRefreshing sync.Map
+
+// StaleWhileRevalidate 开启过期后继续服务（SWR）模式，需配合 Cache/LocalCache/RedisCache 使用
+// 结果过期后的 maxStale 时间内，查询直接返回过期的缓存结果，同时在后台异步重新查询并覆盖缓存，
+// 避免热点键过期瞬间大量请求同时回源造成延迟尖刺；超过 maxStale 后按普通缓存未命中处理
+// 缓存时间取 Cache 指定的 ttl，未指定时取缓存库配置（CreateCacheRepository）或默认 TTL，
+// 永不过期的缓存不受影响；空结果缓存（CacheEmpty）不使用 SWR
+// 示例: eorm.Table("products").Where("hot = ?", 1).Cache("product_list", time.Minute).StaleWhileRevalidate(5*time.Minute).Find()
+func (qb *QueryBuilder) StaleWhileRevalidate(maxStale time.Duration) *QueryBuilder {
+	qb.cacheMaxStale = maxStale
+	return qb
+}
+
+// swrTTL 返回 SWR 模式下的新鲜时间和实际缓存时间，未开启或缓存永不过期时 ok 为 false
+func (qb *QueryBuilder) swrTTL() (fresh, hard time.Duration, ok bool) {
+	if qb.cacheMaxStale <= 0 {
+		return 0, 0, false
+	}
+	fresh = qb.cacheTTL
+	if fresh <= 0 {
+		fresh = defaultTTL
+		if configTTL, found := cacheConfigs.Load(qb.cacheRepositoryName); found {
+			fresh = configTTL.(time.Duration)
+		}
+	}
+	if fresh <= 0 {
+		return 0, 0, false
+	}
+	return fresh, fresh + qb.cacheMaxStale, true
+}
+
+// cacheStore 写入查询结果缓存，SWR 模式下同时写入新鲜度标记
+func (qb *QueryBuilder) cacheStore(cache CacheProvider, key string, value interface{}, empty bool) {
+	fresh, hard, ok := qb.swrTTL()
+	if !ok {
+		storeCacheResult(cache, qb.cacheRepositoryName, key, value, empty, qb.cacheTTL, qb.cacheEmptyTTL)
+		return
+	}
+	if empty {
+		// 空结果按负缓存策略存储，标记与结果同时过期，不会返回过期的空结果
+		emptyTTL := getEffectiveEmptyTTL(qb.cacheRepositoryName, qb.cacheEmptyTTL)
+		if storeCacheResult(cache, qb.cacheRepositoryName, key, value, true, qb.cacheTTL, qb.cacheEmptyTTL) {
+			cache.CacheSet(qb.cacheRepositoryName, key+cacheFreshSuffix, true, emptyTTL)
+		}
+		return
+	}
+	cacheSet(cache, qb.cacheRepositoryName, key, value, hard)
+	cache.CacheSet(qb.cacheRepositoryName, key+cacheFreshSuffix, true, fresh)
+}
+
+// revalidateIfStale 缓存命中但新鲜度标记已过期时，在后台重新执行查询并刷新缓存
+// run 在构建器副本上执行与本次相同的查询方法
+func (qb *QueryBuilder) revalidateIfStale(cache CacheProvider, key string, run func(c *QueryBuilder) error) {
+	if _, _, ok := qb.swrTTL(); !ok {
+		return
+	}
+	if _, fresh := cache.CacheGet(qb.cacheRepositoryName, key+cacheFreshSuffix); fresh {
+		return
+	}
+	flightKey := qb.cacheRepositoryName + "\x00" + key
+	if _, running := swrRefreshing.LoadOrStore(flightKey, struct{}{}); running {
+		return
+	}
+
+	c := qb.Clone()
+	c.cacheRefresh = true
+	c.cacheProvider = cache
+	if c.db != nil && c.db.ctx != nil {
+		// 请求结束后上下文会被取消，后台刷新只保留其中的值
+		db := *c.db
+		db.ctx = context.WithoutCancel(db.ctx)
+		c.db = &db
+	}
+
+	go func() {
+		defer swrRefreshing.Delete(flightKey)
+		defer func() {
+			if r := recover(); r != nil {
+				LogError("缓存后台刷新异常", NewRecord().
+					Set("repository", qb.cacheRepositoryName).
+					Set("panic", fmt.Sprint(r)))
+			}
+		}()
+		if err := run(c); err != nil {
+			LogWarn("缓存后台刷新失败", NewRecord().
+				Set("repository", qb.cacheRepositoryName).
+				Set("error", err.Error()))
+		}
+	}()
+}
@@ -0,0 +1,75 @@
+package eorm
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStaleWhileRevalidateServesStaleAndRefreshes(t *testing.T) {
+	db := openTestDB(t, "CREATE TABLE swr_items (id INTEGER PRIMARY KEY, name TEXT)")
+	mustExec(t, db, "INSERT INTO swr_items (id, name) VALUES (1, 'v1')")
+	prev := GetCache()
+	SetDefaultCache(newLocalCache(time.Minute))
+	t.Cleanup(func() { SetDefaultCache(prev) })
+
+	const fresh = 50 * time.Millisecond
+	query := func() string {
+		t.Helper()
+		record, err := db.Table("swr_items").Where("id = ?", 1).Cache("swr_repo", fresh).StaleWhileRevalidate(time.Minute).QueryFirst()
+		if err != nil || record == nil {
+			t.Fatalf("QueryFirst: %v, %v", record, err)
+		}
+		return record.GetString("name")
+	}
+
+	if got := query(); got != "v1" {
+		t.Fatalf("first query = %s, want v1", got)
+	}
+	rawExec(t, db, "UPDATE swr_items SET name = 'v2' WHERE id = 1")
+	if got := query(); got != "v1" {
+		t.Fatalf("fresh cached query = %s, want v1", got)
+	}
+
+	time.Sleep(2 * fresh)
+	if got := query(); got != "v1" {
+		t.Fatalf("stale query = %s, want the stale v1 served while revalidating", got)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for query() != "v2" {
+		if time.Now().After(deadline) {
+			t.Fatal("cache was not refreshed in the background")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// 刷新后结果重新变为新鲜，新鲜期内不再回源
+	rawExec(t, db, "UPDATE swr_items SET name = 'v3' WHERE id = 1")
+	if got := query(); got != "v2" {
+		t.Fatalf("query after refresh = %s, want the refreshed v2", got)
+	}
+}
+
+func TestStaleWhileRevalidateExpiresAfterMaxStale(t *testing.T) {
+	db := openTestDB(t, "CREATE TABLE swr_items (id INTEGER PRIMARY KEY, name TEXT)")
+	mustExec(t, db, "INSERT INTO swr_items (id, name) VALUES (1, 'v1')")
+	prev := GetCache()
+	SetDefaultCache(newLocalCache(time.Minute))
+	t.Cleanup(func() { SetDefaultCache(prev) })
+
+	query := func() string {
+		t.Helper()
+		records, err := db.Table("swr_items").Cache("swr_expire", 20*time.Millisecond).StaleWhileRevalidate(20 * time.Millisecond).Query()
+		if err != nil || len(records) != 1 {
+			t.Fatalf("Query: %v, %v", records, err)
+		}
+		return records[0].GetString("name")
+	}
+	if got := query(); got != "v1" {
+		t.Fatalf("first query = %s, want v1", got)
+	}
+	rawExec(t, db, "UPDATE swr_items SET name = 'v2' WHERE id = 1")
+	time.Sleep(100 * time.Millisecond)
+	if got := query(); got != "v2" {
+		t.Fatalf("query after ttl+maxStale = %s, want a cache miss returning v2", got)
+	}
+}
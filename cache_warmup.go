@@ -0,0 +1,171 @@
+package eorm
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// WarmQuery 缓存预热查询
+type WarmQuery struct {
+	SQL    string
+	Args   []interface{}
+	TTL    time.Duration // > 0 时使用该缓存时间，否则使用缓存仓库配置的 TTL
+	First  bool          // true 表示预热 QueryFirst 的结果，false 表示预热 Query 的结果
+	DbName string        // 为空时使用调用方的数据库
+}
+
+// WarmCache 使用默认缓存预热查询结果
+// 预热会直接查询数据库并覆盖已有缓存，之后以相同 SQL 和参数调用 Cache(repo).Query/QueryFirst 即可命中
+// 单条查询失败不会中断其余查询，所有错误合并后返回
+// 示例:
+//
+//	err := eorm.WarmCache("user_cache", []eorm.WarmQuery{
+//	    {SQL: "SELECT * FROM users WHERE status = ?", Args: []interface{}{1}},
+//	    {SQL: "SELECT * FROM configs WHERE id = ?", Args: []interface{}{1}, First: true, TTL: time.Hour},
+//	})
+func WarmCache(cacheRepositoryName string, queries []WarmQuery) error {
+	return Cache(cacheRepositoryName).WarmCache(queries)
+}
+
+// WarmCache 使用当前缓存设置预热查询结果
+// 示例: eorm.Use("main").RedisCache("user_cache").WarmCache(queries)
+func (db *DB) WarmCache(queries []WarmQuery) error {
+	if db.lastErr != nil {
+		return db.lastErr
+	}
+	if db.cacheRepositoryName == "" {
+		return fmt.Errorf("eorm: cache repository is not set, call Cache/LocalCache/RedisCache before WarmCache")
+	}
+
+	var errs []error
+	for i, q := range queries {
+		if err := db.warmQuery(q); err != nil {
+			errs = append(errs, fmt.Errorf("eorm: warm query #%d failed: %w", i, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// warmQuery 执行单条预热查询并写入缓存
+func (db *DB) warmQuery(q WarmQuery) error {
+	if q.SQL == "" {
+		return fmt.Errorf("empty SQL")
+	}
+	target := db
+	if q.DbName != "" && (db.dbMgr == nil || q.DbName != db.dbMgr.name) {
+		other, err := UseWithError(q.DbName)
+		if err != nil {
+			return err
+		}
+		other.cacheRepositoryName = db.cacheRepositoryName
+		other.cacheProvider = db.cacheProvider
+		other.cacheTTL = db.cacheTTL
+		other.cacheEmptyTTL = db.cacheEmptyTTL
+		other.timeout = db.timeout
+		target = other
+	}
+	if target.dbMgr == nil {
+		return fmt.Errorf("database is not initialized")
+	}
+
+	ctx, cancel := target.getContext()
+	defer cancel()
+	executor, err := target.getExecutor()
+	if err != nil {
+		return err
+	}
+
+	ttl := target.cacheTTL
+	if q.TTL > 0 {
+		ttl = q.TTL
+	}
+	ttl = getEffectiveTTL(target.cacheRepositoryName, ttl)
+	cache := target.getEffectiveCache()
+	key := GenerateCacheKeyContext(target.ctx, target.dbMgr.name, q.SQL, q.Args...)
+
+	if q.First {
+		result, err := target.dbMgr.queryFirstWithContext(ctx, executor, q.SQL, q.Args...)
+		if err != nil {
+			return err
+		}
+		storeCacheResult(cache, target.cacheRepositoryName, key, result, result == nil, ttl, target.cacheEmptyTTL)
+		return nil
+	}
+	results, err := target.dbMgr.queryWithContext(ctx, executor, q.SQL, q.Args...)
+	if err != nil {
+		return err
+	}
+	storeCacheResult(cache, target.cacheRepositoryName, key, results, len(results) == 0, ttl, target.cacheEmptyTTL)
+	return nil
+}
+
+// CacheWarmer 定时缓存预热任务
+type CacheWarmer struct {
+	db       *DB
+	queries  []WarmQuery
+	interval time.Duration
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// ScheduleCacheWarmup 使用默认缓存启动定时预热任务
+// 启动时立即预热一次，之后每隔 interval 重新预热（interval <= 0 时只预热一次）
+// 预热失败只记录警告日志，不会停止任务；应用退出前调用 Stop 停止任务
+// 示例:
+//
+//	warmer := eorm.ScheduleCacheWarmup("user_cache", queries, 5*time.Minute)
+//	defer warmer.Stop()
+func ScheduleCacheWarmup(cacheRepositoryName string, queries []WarmQuery, interval time.Duration) *CacheWarmer {
+	return Cache(cacheRepositoryName).ScheduleCacheWarmup(queries, interval)
+}
+
+// ScheduleCacheWarmup 使用当前缓存设置启动定时预热任务
+func (db *DB) ScheduleCacheWarmup(queries []WarmQuery, interval time.Duration) *CacheWarmer {
+	w := &CacheWarmer{
+		db:       db,
+		queries:  append([]WarmQuery(nil), queries...),
+		interval: interval,
+		stopCh:   make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+// run 预热任务主循环
+func (w *CacheWarmer) run() {
+	w.warm()
+	if w.interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			w.warm()
+		case <-w.stopCh:
+			return
+		}
+	}
+}
+
+// warm 执行一轮预热
+func (w *CacheWarmer) warm() {
+	if err := w.db.WarmCache(w.queries); err != nil {
+		LogWarn("缓存预热失败", NewRecord().
+			Set("cache_repository", w.db.cacheRepositoryName).
+			Set("error", err.Error()))
+	}
+}
+
+// Stop 停止定时预热任务，可重复调用
+func (w *CacheWarmer) Stop() {
+	if w == nil {
+		return
+	}
+	w.stopOnce.Do(func() {
+		close(w.stopCh)
+	})
+}
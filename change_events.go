@@ -0,0 +1,485 @@
+package eorm
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ChangeOp 实体变更的操作类型
+type ChangeOp string
+
+const (
+	ChangeInsert ChangeOp = "insert" // 插入
+	ChangeUpdate ChangeOp = "update" // 更新（包括恢复软删除）
+	ChangeDelete ChangeOp = "delete" // 删除（包括软删除）
+)
+
+// ChangeEvent 通过 eorm 执行的一行数据变更
+type ChangeEvent struct {
+	Database   string    // 数据库名称
+	Table      string    // 表名
+	Op         ChangeOp  // 操作类型
+	PrimaryKey *Record   // 主键列和值（表没有主键或插入时无法取得主键时为 nil）
+	Old        *Record   // 变更前的行（插入时为 nil）
+	New        *Record   // 变更后的行（删除时为 nil；插入时为写入的记录）
+	Time       time.Time // 写操作执行的时间
+}
+
+// changeSubscriber 一个变更订阅者
+type changeSubscriber struct {
+	id uint64
+	fn func(ChangeEvent)
+}
+
+// pendingChanges 事务中尚未提交的变更事件
+type pendingChanges struct {
+	events []ChangeEvent
+	marks  map[string]int // 保存点名称 -> 创建保存点时的事件数量
+}
+
+// changeBus 实体变更事件的订阅者，以及 eorm 开启的事务中等待提交的事件
+type changeBus struct {
+	mu      sync.Mutex
+	subs    map[string][]changeSubscriber // table(lower) -> 订阅者，"*" 表示所有表
+	nextID  uint64
+	pending map[*sql.Tx]*pendingChanges // 只包含 eorm 开启且尚未结束的事务
+}
+
+// newChangeBus creates a new change bus
+func newChangeBus() *changeBus {
+	return &changeBus{
+		subs:    make(map[string][]changeSubscriber),
+		pending: make(map[*sql.Tx]*pendingChanges),
+	}
+}
+
+// subscribe 添加订阅者，返回取消订阅的函数
+func (b *changeBus) subscribe(table string, fn func(ChangeEvent)) func() {
+	key := changeTableKey(table)
+	b.mu.Lock()
+	b.nextID++
+	id := b.nextID
+	b.subs[key] = append(b.subs[key], changeSubscriber{id: id, fn: fn})
+	b.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			subs := b.subs[key]
+			for i, sub := range subs {
+				if sub.id == id {
+					b.subs[key] = append(subs[:i:i], subs[i+1:]...)
+					break
+				}
+			}
+			if len(b.subs[key]) == 0 {
+				delete(b.subs, key)
+			}
+		})
+	}
+}
+
+// hasSubscribers 表是否有订阅者
+func (b *changeBus) hasSubscribers(table string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subs[changeTableKey(table)]) > 0 || len(b.subs["*"]) > 0
+}
+
+// subscribers 返回表当前的订阅者（先表级后全局）
+func (b *changeBus) subscribers(table string) []changeSubscriber {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	tableSubs, allSubs := b.subs[changeTableKey(table)], b.subs["*"]
+	subs := make([]changeSubscriber, 0, len(tableSubs)+len(allSubs))
+	return append(append(subs, tableSubs...), allSubs...)
+}
+
+// begin 登记 eorm 开启的事务，其中的变更事件暂存到事务结束
+func (b *changeBus) begin(tx *sql.Tx) {
+	b.mu.Lock()
+	b.pending[tx] = &pendingChanges{}
+	b.mu.Unlock()
+}
+
+// publish 非事务的写操作立即投递；eorm 开启的事务中的写操作暂存到提交时投递
+// 外部事务（WithExecutor 传入的 *sql.Tx）的提交 eorm 无法感知，事件同非事务写操作一样立即投递
+func (b *changeBus) publish(executor sqlExecutor, events []ChangeEvent) {
+	if len(events) == 0 {
+		return
+	}
+	var p *pendingChanges
+	b.mu.Lock()
+	if tx, inTx := executor.(*sql.Tx); inTx {
+		if p = b.pending[tx]; p != nil {
+			p.events = append(p.events, events...)
+		}
+	}
+	b.mu.Unlock()
+	if p == nil {
+		b.deliver(events)
+	}
+}
+
+// finish 事务结束时投递（committed 为 true）或丢弃暂存的事件
+func (b *changeBus) finish(tx *sql.Tx, committed bool) {
+	b.mu.Lock()
+	p := b.pending[tx]
+	delete(b.pending, tx)
+	b.mu.Unlock()
+	if p != nil && committed {
+		b.deliver(p.events)
+	}
+}
+
+// mark 记录保存点创建时已暂存的事件数量
+func (b *changeBus) mark(tx *sql.Tx, name string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	p := b.pending[tx]
+	if p == nil {
+		return
+	}
+	if p.marks == nil {
+		p.marks = make(map[string]int)
+	}
+	p.marks[name] = len(p.events)
+}
+
+// rollbackTo 丢弃保存点之后暂存的事件
+func (b *changeBus) rollbackTo(tx *sql.Tx, name string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	p := b.pending[tx]
+	if p == nil {
+		return
+	}
+	if n, ok := p.marks[name]; ok && n < len(p.events) {
+		p.events = p.events[:n]
+	}
+}
+
+// deliver 按顺序把事件交给订阅者，订阅者中的 panic 被记录日志而不影响其他订阅者
+func (b *changeBus) deliver(events []ChangeEvent) {
+	for _, evt := range events {
+		for _, sub := range b.subscribers(evt.Table) {
+			runChangeSubscriber(sub.fn, evt)
+		}
+	}
+}
+
+// runChangeSubscriber 执行单个订阅者并捕获 panic
+func runChangeSubscriber(fn func(ChangeEvent), evt ChangeEvent) {
+	defer func() {
+		if r := recover(); r != nil {
+			LogError("变更事件订阅者异常", NewRecord().
+				Set("db", evt.Database).
+				Set("table", evt.Table).
+				Set("op", string(evt.Op)).
+				Set("panic", fmt.Sprint(r)))
+		}
+	}()
+	fn(evt)
+}
+
+// changeTableKey 订阅表名的规范形式
+func changeTableKey(table string) string {
+	if strings.TrimSpace(table) == "*" {
+		return "*"
+	}
+	return normalizeDependencyTable(table)
+}
+
+// --- Global Functions (for default database) ---
+
+// Subscribe 订阅表的数据变更：通过 eorm 执行的插入、更新、删除（包括批量操作和软删除）在成功后通知订阅者
+// SaveRecord 按记录是否已存在发布插入或更新事件，InsertRecordIgnore 只在实际插入时发布，
+// DeleteLimit/BatchDeleteWhere 先确定被删除的行再按主键删除（表没有主键时不产生事件）
+// 事务中的变更在提交后按执行顺序投递，回滚（包括 RollbackTo 撤销的部分）不会投递；非事务写操作在语句执行后立即投递
+// 通过 WithExecutor 在外部事务（如 GORM 事务）中执行的写操作，eorm 无法感知其提交或回滚，事件在语句执行后立即投递
+// 更新和删除会在写之前读取受影响的行作为 Old，更新后按主键重新读取作为 New，因此只在表有订阅者时才产生额外查询
+// 订阅者在写操作所在的 goroutine 中同步执行，耗时的处理（如更新搜索索引）应自行异步化
+// 原始 SQL（Exec、ExecScript 等）和 eorm 之外的写操作不会产生事件；table 为 "*" 时订阅所有表
+// 返回取消订阅的函数
+// 示例:
+//
+//	unsubscribe := eorm.Subscribe("users", func(evt eorm.ChangeEvent) {
+//	    switch evt.Op {
+//	    case eorm.ChangeDelete:
+//	        searchIndex.Delete(evt.PrimaryKey.GetInt64("id"))
+//	    default:
+//	        searchIndex.Put(evt.New)
+//	    }
+//	})
+//	defer unsubscribe()
+func Subscribe(table string, fn func(ChangeEvent)) func() {
+	db, err := defaultDB()
+	if err != nil {
+		return func() {}
+	}
+	return db.Subscribe(table, fn)
+}
+
+// --- DB Methods ---
+
+// Subscribe 订阅当前数据库中表的数据变更，见 Subscribe
+func (db *DB) Subscribe(table string, fn func(ChangeEvent)) func() {
+	if db.lastErr != nil || db.dbMgr == nil || fn == nil {
+		return func() {}
+	}
+	if table != "*" {
+		if err := validateIdentifier(table); err != nil {
+			db.lastErr = err
+			return func() {}
+		}
+	}
+	return db.dbMgr.getChangeBus(true).subscribe(table, fn)
+}
+
+// --- dbManager Methods ---
+
+// getChangeBus 返回变更事件总线，create 为 false 且尚未创建时返回 nil
+func (mgr *dbManager) getChangeBus(create bool) *changeBus {
+	mgr.mu.RLock()
+	bus := mgr.changes
+	mgr.mu.RUnlock()
+	if bus != nil || !create {
+		return bus
+	}
+	mgr.mu.Lock()
+	defer mgr.mu.Unlock()
+	if mgr.changes == nil {
+		mgr.changes = newChangeBus()
+	}
+	return mgr.changes
+}
+
+// hasChangeSubscribers 表是否有变更订阅者
+func (mgr *dbManager) hasChangeSubscribers(table string) bool {
+	bus := mgr.getChangeBus(false)
+	return bus != nil && bus.hasSubscribers(table)
+}
+
+// beginChanges 登记 eorm 开启的事务，事务中的变更事件在提交后投递
+func (mgr *dbManager) beginChanges(tx *sql.Tx) {
+	mgr.getChangeBus(true).begin(tx)
+}
+
+// finishChanges 事务结束时投递或丢弃该事务暂存的变更事件
+func (mgr *dbManager) finishChanges(tx *sql.Tx, committed bool) {
+	if bus := mgr.getChangeBus(false); bus != nil {
+		bus.finish(tx, committed)
+	}
+}
+
+// savepointChanges 创建保存点时记录暂存事件的位置，回滚到保存点时丢弃之后的事件
+func (mgr *dbManager) savepointChanges(executor sqlExecutor, action savepointAction, name string) {
+	tx, ok := executor.(*sql.Tx)
+	bus := mgr.getChangeBus(false)
+	if !ok || bus == nil {
+		return
+	}
+	switch action {
+	case savepointCreate:
+		bus.mark(tx, name)
+	case savepointRollback:
+		bus.rollbackTo(tx, name)
+	}
+}
+
+// changeCapture 写操作前读取的受影响行
+type changeCapture struct {
+	table string
+	op    ChangeOp
+	pks   []string
+	old   []*Record
+	delta *Record // 更新写入的列，表没有主键时用于合成 New
+}
+
+// captureChanges 在更新或删除前读取 where 匹配的行；表没有订阅者时返回 nil
+func (mgr *dbManager) captureChanges(executor sqlExecutor, table string, op ChangeOp, delta *Record, where string, whereArgs []interface{}) (*changeCapture, error) {
+	if !mgr.hasChangeSubscribers(table) {
+		return nil, nil
+	}
+	pks, err := mgr.getPrimaryKeys(executor, table)
+	if err != nil {
+		return nil, err
+	}
+	querySQL := "SELECT * FROM " + table
+	if where != "" {
+		querySQL += " WHERE " + where
+	}
+	old, err := mgr.queryInternal(executor, querySQL, whereArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("eorm: failed to read rows for change events on %s: %w", table, err)
+	}
+	return &changeCapture{table: table, op: op, pks: pks, old: old, delta: delta}, nil
+}
+
+// captureChangesForRecords 按记录中的主键值读取受影响的行，用于批量更新和删除
+func (mgr *dbManager) captureChangesForRecords(executor sqlExecutor, table string, op ChangeOp, records []*Record) (*changeCapture, error) {
+	if !mgr.hasChangeSubscribers(table) {
+		return nil, nil
+	}
+	pks, err := mgr.getPrimaryKeys(executor, table)
+	if err != nil {
+		return nil, err
+	}
+	c := &changeCapture{table: table, op: op, pks: pks}
+	if len(pks) == 0 {
+		return c, nil
+	}
+	c.old, err = mgr.queryByRecordKeys(executor, table, pks, records)
+	if err != nil {
+		return nil, fmt.Errorf("eorm: failed to read rows for change events on %s: %w", table, err)
+	}
+	return c, nil
+}
+
+// queryByRecordKeys 按记录的主键值分批查询行
+func (mgr *dbManager) queryByRecordKeys(executor sqlExecutor, table string, pks []string, records []*Record) ([]*Record, error) {
+	var rows []*Record
+	for start := 0; start < len(records); start += DefaultBatchSize {
+		end := start + DefaultBatchSize
+		if end > len(records) {
+			end = len(records)
+		}
+		where, args := recordKeyConditions(pks, records[start:end])
+		if where == "" {
+			continue
+		}
+		batch, err := mgr.queryInternal(executor, fmt.Sprintf("SELECT * FROM %s WHERE %s", table, where), args...)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, batch...)
+	}
+	return rows, nil
+}
+
+// recordKeyConditions 生成按主键匹配一组记录的条件：(pk1 = ? AND pk2 = ?) OR ...
+func recordKeyConditions(pks []string, records []*Record) (string, []interface{}) {
+	var conds []string
+	var args []interface{}
+	for _, record := range records {
+		if record == nil {
+			continue
+		}
+		parts := make([]string, len(pks))
+		for i, pk := range pks {
+			parts[i] = pk + " = ?"
+			args = append(args, record.Get(pk))
+		}
+		conds = append(conds, "("+strings.Join(parts, " AND ")+")")
+	}
+	return strings.Join(conds, " OR "), args
+}
+
+// publishChanges 写操作成功后生成并发布事件，更新操作按主键重新读取变更后的行
+func (mgr *dbManager) publishChanges(executor sqlExecutor, c *changeCapture) {
+	if c == nil || len(c.old) == 0 {
+		return
+	}
+	bus := mgr.getChangeBus(false)
+	if bus == nil {
+		return
+	}
+	var current map[string]*Record
+	if c.op == ChangeUpdate && len(c.pks) > 0 {
+		rows, err := mgr.queryByRecordKeys(executor, c.table, c.pks, c.old)
+		if err != nil {
+			LogWarn("读取变更后的行失败，变更事件中 New 为 nil", NewRecord().
+				Set("db", mgr.name).
+				Set("table", c.table).
+				Set("error", err.Error()))
+		}
+		current = make(map[string]*Record, len(rows))
+		for _, row := range rows {
+			current[recordKeyString(c.pks, row)] = row
+		}
+	}
+
+	now := time.Now()
+	events := make([]ChangeEvent, 0, len(c.old))
+	for _, old := range c.old {
+		evt := ChangeEvent{
+			Database:   mgr.name,
+			Table:      c.table,
+			Op:         c.op,
+			PrimaryKey: recordPrimaryKey(c.pks, old),
+			Old:        old,
+			Time:       now,
+		}
+		if c.op == ChangeUpdate {
+			if current != nil {
+				evt.New = current[recordKeyString(c.pks, old)]
+			} else if c.delta != nil {
+				evt.New = old.Clone()
+				for _, col := range c.delta.Keys() {
+					evt.New.Set(col, c.delta.Get(col))
+				}
+			}
+		}
+		events = append(events, evt)
+	}
+	bus.publish(executor, events)
+}
+
+// publishInserts 插入成功后发布事件；id 为单条插入返回的自增主键（未知时为 0）
+func (mgr *dbManager) publishInserts(executor sqlExecutor, table string, records []*Record, id int64) {
+	bus := mgr.getChangeBus(false)
+	if bus == nil || !bus.hasSubscribers(table) {
+		return
+	}
+	pks, _ := mgr.getPrimaryKeys(executor, table)
+	now := time.Now()
+	events := make([]ChangeEvent, 0, len(records))
+	for _, record := range records {
+		if record == nil {
+			continue
+		}
+		row := record.Clone()
+		if id > 0 && len(pks) == 1 && row.Get(pks[0]) == nil && mgr.isInt64PrimaryKey(table, pks[0]) {
+			row.Set(pks[0], id)
+		}
+		events = append(events, ChangeEvent{
+			Database:   mgr.name,
+			Table:      table,
+			Op:         ChangeInsert,
+			PrimaryKey: recordPrimaryKey(pks, row),
+			New:        row,
+			Time:       now,
+		})
+	}
+	bus.publish(executor, events)
+}
+
+// recordPrimaryKey 提取记录的主键列，主键值缺失时返回 nil
+func recordPrimaryKey(pks []string, row *Record) *Record {
+	if len(pks) == 0 || row == nil {
+		return nil
+	}
+	key := NewRecord()
+	for _, pk := range pks {
+		v := row.Get(pk)
+		if v == nil {
+			return nil
+		}
+		key.Set(pk, v)
+	}
+	return key
+}
+
+// recordKeyString 主键值的文本形式，用于匹配变更前后的行
+func recordKeyString(pks []string, row *Record) string {
+	parts := make([]string, len(pks))
+	for i, pk := range pks {
+		parts[i] = fmt.Sprint(row.Get(pk))
+	}
+	return strings.Join(parts, "\x00")
+}
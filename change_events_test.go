@@ -0,0 +1,161 @@
+package eorm
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+// changeRecorder 收集订阅到的变更事件
+type changeRecorder struct {
+	mu     sync.Mutex
+	events []ChangeEvent
+}
+
+func (r *changeRecorder) record(evt ChangeEvent) {
+	r.mu.Lock()
+	r.events = append(r.events, evt)
+	r.mu.Unlock()
+}
+
+// ops 返回已收到事件的操作类型序列，并清空已收到的事件
+func (r *changeRecorder) ops() []ChangeOp {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ops := make([]ChangeOp, len(r.events))
+	for i, evt := range r.events {
+		ops[i] = evt.Op
+	}
+	r.events = nil
+	return ops
+}
+
+func subscribeChanges(t *testing.T, db *DB, table string) *changeRecorder {
+	t.Helper()
+	r := &changeRecorder{}
+	t.Cleanup(db.Subscribe(table, r.record))
+	return r
+}
+
+func assertOps(t *testing.T, got []ChangeOp, want ...ChangeOp) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("events = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("events = %v, want %v", got, want)
+		}
+	}
+}
+
+const changeEventsDDL = "CREATE TABLE ce_items (id INTEGER PRIMARY KEY, name TEXT)"
+
+func TestChangeEventsForWritePaths(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, db *DB, events *changeRecorder)
+	}{
+		{"SaveRecord", func(t *testing.T, db *DB, events *changeRecorder) {
+			if _, err := db.SaveRecord("ce_items", NewRecord().Set("id", 10).Set("name", "a")); err != nil {
+				t.Fatal(err)
+			}
+			assertOps(t, events.ops(), ChangeInsert)
+			if _, err := db.SaveRecord("ce_items", NewRecord().Set("id", 10).Set("name", "b")); err != nil {
+				t.Fatal(err)
+			}
+			assertOps(t, events.ops(), ChangeUpdate)
+		}},
+		{"InsertRecordIgnore", func(t *testing.T, db *DB, events *changeRecorder) {
+			for i := 0; i < 2; i++ {
+				if _, err := db.InsertRecordIgnore("ce_items", NewRecord().Set("id", 10).Set("name", "a")); err != nil {
+					t.Fatal(err)
+				}
+			}
+			assertOps(t, events.ops(), ChangeInsert)
+		}},
+		{"DeleteLimit", func(t *testing.T, db *DB, events *changeRecorder) {
+			mustExec(t, db, "INSERT INTO ce_items (id, name) VALUES (1, 'x'), (2, 'x'), (3, 'x')")
+			events.ops()
+			if _, err := db.Table("ce_items").Where("name = ?", "x").OrderBy("id").DeleteLimit(2); err != nil {
+				t.Fatal(err)
+			}
+			assertOps(t, events.ops(), ChangeDelete, ChangeDelete)
+			if n := countRows(t, db, "ce_items", "id = 3"); n != 1 {
+				t.Fatalf("DeleteLimit removed rows outside the limit")
+			}
+		}},
+		{"BatchDeleteWhere", func(t *testing.T, db *DB, events *changeRecorder) {
+			mustExec(t, db, "INSERT INTO ce_items (id, name) VALUES (1, 'x'), (2, 'x'), (3, 'x')")
+			events.ops()
+			n, err := db.BatchDeleteWhere("ce_items", "name = ?", []interface{}{"x"}, 2)
+			if err != nil || n != 3 {
+				t.Fatalf("deleted %d rows, err %v", n, err)
+			}
+			assertOps(t, events.ops(), ChangeDelete, ChangeDelete, ChangeDelete)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openTestDB(t, changeEventsDDL)
+			tt.run(t, db, subscribeChanges(t, db, "ce_items"))
+		})
+	}
+}
+
+func TestChangeEventsTransactionDelivery(t *testing.T) {
+	db := openTestDB(t, changeEventsDDL)
+	events := subscribeChanges(t, db, "ce_items")
+
+	rollback := errors.New("rollback")
+	err := db.Transaction(func(tx *Tx) error {
+		if _, err := tx.InsertRecord("ce_items", NewRecord().Set("id", 1).Set("name", "a")); err != nil {
+			return err
+		}
+		return rollback
+	})
+	if !errors.Is(err, rollback) {
+		t.Fatal(err)
+	}
+	assertOps(t, events.ops())
+
+	err = db.Transaction(func(tx *Tx) error {
+		if _, err := tx.InsertRecord("ce_items", NewRecord().Set("id", 1).Set("name", "a")); err != nil {
+			return err
+		}
+		if got := events.ops(); len(got) != 0 {
+			t.Errorf("events delivered before commit: %v", got)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertOps(t, events.ops(), ChangeInsert)
+}
+
+func TestChangeEventsExternalTransaction(t *testing.T) {
+	db := openTestDB(t, changeEventsDDL)
+	events := subscribeChanges(t, db, "ce_items")
+
+	sqlTx, err := db.SqlDB().Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sqlTx.Rollback()
+	if _, err := db.WithExecutor(sqlTx).InsertRecord("ce_items", NewRecord().Set("id", 1).Set("name", "a")); err != nil {
+		t.Fatal(err)
+	}
+	assertOps(t, events.ops(), ChangeInsert)
+	if err := sqlTx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	bus := db.dbMgr.getChangeBus(false)
+	bus.mu.Lock()
+	pending := len(bus.pending)
+	bus.mu.Unlock()
+	if pending != 0 {
+		t.Fatalf("external transaction left %d pending entries", pending)
+	}
+}
@@ -0,0 +1,341 @@
+package eorm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ChangeMessage 发布到消息队列的一条变更消息
+type ChangeMessage struct {
+	Topic   string            // 主题（Kafka topic、NATS subject、RabbitMQ routing key）
+	Key     string            // 排序键：表名 + 主键值，相同键的消息按变更顺序发布；用作 Kafka 分区键可保证消费顺序
+	Value   []byte            // JSON 格式的 ChangePayload
+	Headers map[string]string // 消息头：eorm-event-id、eorm-table、eorm-op
+}
+
+// ChangePayload 变更消息的内容
+type ChangePayload struct {
+	ID       string    `json:"id"` // 事件 ID，重复投递时相同，消费者可据此去重
+	Database string    `json:"database"`
+	Table    string    `json:"table"`
+	Op       ChangeOp  `json:"op"`
+	Key      *Record   `json:"key,omitempty"`
+	Before   *Record   `json:"before,omitempty"`
+	After    *Record   `json:"after,omitempty"`
+	Time     time.Time `json:"ts"`
+}
+
+// Producer 消息队列生产者，实现该接口即可接入 Kafka、NATS、RabbitMQ 等
+// Publish 返回 nil 表示整批消息已被队列确认；返回错误时整批消息会被重试，因此可能重复
+type Producer interface {
+	Publish(ctx context.Context, msgs []ChangeMessage) error
+}
+
+// ChangePublisherOptions 变更发布的选项
+type ChangePublisherOptions struct {
+	Tables        []string                              // 发布这些表的变更（为空表示所有表）
+	Topic         string                                // 主题模板，{db} 和 {table} 会被替换（默认 "eorm.{db}.{table}"）
+	TopicFunc     func(evt ChangeEvent) string          // 自定义主题，设置后忽略 Topic
+	Workers       int                                   // 并发发布的通道数，同一排序键始终由同一通道发布（默认 1）
+	BufferSize    int                                   // 每个通道缓冲的消息数（默认 10000）
+	DropWhenFull  bool                                  // 缓冲区满时丢弃消息而不是阻塞写操作（默认阻塞）
+	BatchSize     int                                   // 每次 Publish 的最大消息数（默认 100）
+	FlushInterval time.Duration                         // 未达到 BatchSize 时等待更多消息的时间（默认 100ms）
+	MaxRetries    int                                   // 发布失败后的重试次数（默认 5，<0 表示不重试）
+	RetryBackoff  time.Duration                         // 首次重试的等待时间，之后每次翻倍，最长 30 秒（默认 200ms）
+	Timeout       time.Duration                         // 单次 Publish 的超时时间（默认 30 秒）
+	OnError       func(msgs []ChangeMessage, err error) // 重试耗尽后的回调，这批消息不再发布
+}
+
+// ChangePublisherStats 变更发布的统计
+type ChangePublisherStats struct {
+	Published uint64 // 已确认的消息数
+	Failed    uint64 // 重试耗尽后放弃的消息数
+	Dropped   uint64 // 缓冲区满时丢弃的消息数
+	Pending   int    // 缓冲区中等待发布的消息数
+}
+
+// ChangePublisher 将表的变更事件发布到消息队列，由 StartChangePublisher 创建
+type ChangePublisher struct {
+	mgr         *dbManager
+	producer    Producer
+	opts        ChangePublisherOptions
+	lanes       []chan ChangeMessage
+	unsubscribe []func()
+	wg          sync.WaitGroup
+
+	mu     sync.RWMutex // 保护 closed，避免向已关闭的通道发送
+	closed bool
+
+	seq       uint64
+	published uint64
+	failed    uint64
+	dropped   uint64
+}
+
+// StartChangePublisher 订阅表的变更事件（见 Subscribe）并发布到消息队列，下游服务据此响应数据变化
+//
+// 投递保证：
+//   - 事件在事务提交后进入内存缓冲区，由后台协程批量发布；回滚的变更不会发布
+//   - Producer 返回错误时按指数退避重试，整批重发，因此是“至少一次”：消费者应按 eorm-event-id 去重
+//   - 缓冲区中的消息只在内存中，进程崩溃或在 Close 之前退出会丢失尚未发布的消息；
+//     需要严格不丢失时应在同一事务中写入 outbox 表，由独立进程发布
+//   - 同一表同一主键的消息始终由同一通道按提交顺序发布，失败重试时阻塞该通道，因此同一行的变更不会乱序；
+//     不同主键之间不保证顺序（Workers 为 1 时全局有序）
+//   - 原始 SQL 和 eorm 之外的写操作不会产生消息
+//
+// 示例（Kafka，以 segmentio/kafka-go 为例）:
+//
+//	type kafkaProducer struct{ w *kafka.Writer }
+//
+//	func (p kafkaProducer) Publish(ctx context.Context, msgs []eorm.ChangeMessage) error {
+//	    out := make([]kafka.Message, len(msgs))
+//	    for i, m := range msgs {
+//	        out[i] = kafka.Message{Topic: m.Topic, Key: []byte(m.Key), Value: m.Value}
+//	    }
+//	    return p.w.WriteMessages(ctx, out...)
+//	}
+//
+//	publisher, err := eorm.StartChangePublisher(kafkaProducer{w}, &eorm.ChangePublisherOptions{
+//	    Tables: []string{"orders", "users"},
+//	    Topic:  "app.{table}.changes",
+//	})
+//	defer publisher.Close()
+//
+// NATS JetStream 可在 Publish 中对每条消息调用 js.Publish(m.Topic, m.Value)；
+// RabbitMQ 可使用 publisher confirms，以 m.Topic 作为 routing key
+func StartChangePublisher(producer Producer, opts *ChangePublisherOptions) (*ChangePublisher, error) {
+	db, err := defaultDB()
+	if err != nil {
+		return nil, err
+	}
+	return db.StartChangePublisher(producer, opts)
+}
+
+// StartChangePublisher 发布当前数据库中表的变更事件，见 StartChangePublisher
+func (db *DB) StartChangePublisher(producer Producer, opts *ChangePublisherOptions) (*ChangePublisher, error) {
+	if db.lastErr != nil {
+		return nil, db.lastErr
+	}
+	if producer == nil {
+		return nil, fmt.Errorf("eorm: StartChangePublisher requires a producer")
+	}
+	p := &ChangePublisher{mgr: db.dbMgr, producer: producer}
+	if opts != nil {
+		p.opts = *opts
+	}
+	if p.opts.Topic == "" {
+		p.opts.Topic = "eorm.{db}.{table}"
+	}
+	if p.opts.Workers <= 0 {
+		p.opts.Workers = 1
+	}
+	if p.opts.BufferSize <= 0 {
+		p.opts.BufferSize = 10000
+	}
+	if p.opts.BatchSize <= 0 {
+		p.opts.BatchSize = DefaultBatchSize
+	}
+	if p.opts.FlushInterval <= 0 {
+		p.opts.FlushInterval = 100 * time.Millisecond
+	}
+	if p.opts.MaxRetries == 0 {
+		p.opts.MaxRetries = 5
+	}
+	if p.opts.RetryBackoff <= 0 {
+		p.opts.RetryBackoff = 200 * time.Millisecond
+	}
+	if p.opts.Timeout <= 0 {
+		p.opts.Timeout = 30 * time.Second
+	}
+	tables := p.opts.Tables
+	if len(tables) == 0 {
+		tables = []string{"*"}
+	}
+	for _, table := range tables {
+		if table == "*" {
+			continue
+		}
+		if err := validateIdentifier(table); err != nil {
+			return nil, err
+		}
+	}
+
+	p.lanes = make([]chan ChangeMessage, p.opts.Workers)
+	for i := range p.lanes {
+		p.lanes[i] = make(chan ChangeMessage, p.opts.BufferSize)
+		p.wg.Add(1)
+		go p.runLane(p.lanes[i])
+	}
+	for _, table := range tables {
+		p.unsubscribe = append(p.unsubscribe, db.Subscribe(table, p.onChange))
+	}
+	return p, nil
+}
+
+// Close 取消订阅，等待缓冲区中的消息发布完成（或重试耗尽）后返回
+func (p *ChangePublisher) Close() error {
+	for _, unsubscribe := range p.unsubscribe {
+		unsubscribe()
+	}
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	for _, lane := range p.lanes {
+		close(lane)
+	}
+	p.mu.Unlock()
+	p.wg.Wait()
+	return nil
+}
+
+// Stats 返回发布统计
+func (p *ChangePublisher) Stats() ChangePublisherStats {
+	pending := 0
+	for _, lane := range p.lanes {
+		pending += len(lane)
+	}
+	return ChangePublisherStats{
+		Published: atomic.LoadUint64(&p.published),
+		Failed:    atomic.LoadUint64(&p.failed),
+		Dropped:   atomic.LoadUint64(&p.dropped),
+		Pending:   pending,
+	}
+}
+
+// onChange 把变更事件编码为消息并放入排序键对应的通道
+func (p *ChangePublisher) onChange(evt ChangeEvent) {
+	msg, err := p.newMessage(evt)
+	if err != nil {
+		LogError("变更消息编码失败", NewRecord().
+			Set("db", evt.Database).
+			Set("table", evt.Table).
+			Set("error", err.Error()))
+		return
+	}
+	h := fnv.New32a()
+	h.Write([]byte(msg.Key))
+	lane := p.lanes[int(h.Sum32()%uint32(len(p.lanes)))]
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return
+	}
+	if !p.opts.DropWhenFull {
+		lane <- msg
+		return
+	}
+	select {
+	case lane <- msg:
+	default:
+		if atomic.AddUint64(&p.dropped, 1) == 1 {
+			LogWarn("变更消息缓冲区已满，消息被丢弃", NewRecord().
+				Set("db", evt.Database).
+				Set("table", evt.Table))
+		}
+	}
+}
+
+// newMessage 生成变更消息
+func (p *ChangePublisher) newMessage(evt ChangeEvent) (ChangeMessage, error) {
+	payload := ChangePayload{
+		ID:       fmt.Sprintf("%d-%d", evt.Time.UnixNano(), atomic.AddUint64(&p.seq, 1)),
+		Database: evt.Database,
+		Table:    evt.Table,
+		Op:       evt.Op,
+		Key:      evt.PrimaryKey,
+		Before:   evt.Old,
+		After:    evt.New,
+		Time:     evt.Time,
+	}
+	value, err := json.Marshal(payload)
+	if err != nil {
+		return ChangeMessage{}, err
+	}
+	topic := ""
+	if p.opts.TopicFunc != nil {
+		topic = p.opts.TopicFunc(evt)
+	} else {
+		topic = strings.NewReplacer("{db}", evt.Database, "{table}", evt.Table).Replace(p.opts.Topic)
+	}
+	key := evt.Table
+	if evt.PrimaryKey != nil {
+		for _, col := range evt.PrimaryKey.Keys() {
+			key += ":" + fmt.Sprint(evt.PrimaryKey.Get(col))
+		}
+	}
+	return ChangeMessage{
+		Topic: topic,
+		Key:   key,
+		Value: value,
+		Headers: map[string]string{
+			"eorm-event-id": payload.ID,
+			"eorm-table":    evt.Table,
+			"eorm-op":       string(evt.Op),
+		},
+	}, nil
+}
+
+// runLane 从通道读取消息，凑满 BatchSize 或等待 FlushInterval 后发布
+func (p *ChangePublisher) runLane(lane chan ChangeMessage) {
+	defer p.wg.Done()
+	batch := make([]ChangeMessage, 0, p.opts.BatchSize)
+	for msg := range lane {
+		batch = append(batch[:0], msg)
+		timer := time.NewTimer(p.opts.FlushInterval)
+	collect:
+		for len(batch) < p.opts.BatchSize {
+			select {
+			case next, ok := <-lane:
+				if !ok {
+					break collect
+				}
+				batch = append(batch, next)
+			case <-timer.C:
+				break collect
+			}
+		}
+		timer.Stop()
+		p.publish(batch)
+	}
+}
+
+// publish 发布一批消息，失败时按指数退避重试
+func (p *ChangePublisher) publish(batch []ChangeMessage) {
+	backoff := p.opts.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), p.opts.Timeout)
+		err := p.producer.Publish(ctx, batch)
+		cancel()
+		if err == nil {
+			atomic.AddUint64(&p.published, uint64(len(batch)))
+			return
+		}
+		if attempt >= p.opts.MaxRetries {
+			atomic.AddUint64(&p.failed, uint64(len(batch)))
+			LogError("变更消息发布失败", NewRecord().
+				Set("db", p.mgr.name).
+				Set("messages", len(batch)).
+				Set("attempts", attempt+1).
+				Set("error", err.Error()))
+			if p.opts.OnError != nil {
+				p.opts.OnError(append([]ChangeMessage(nil), batch...), err)
+			}
+			return
+		}
+		time.Sleep(backoff)
+		if backoff *= 2; backoff > 30*time.Second {
+			backoff = 30 * time.Second
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package eorm
+
+import (
+	"strings"
+	"sync"
+)
+
+// columnDefaultRegistry stores insert defaults per table
+type columnDefaultRegistry struct {
+	defaults map[string]map[string]interface{} // table -> column -> default value
+	mu       sync.RWMutex
+}
+
+// newColumnDefaultRegistry creates a new column default registry
+func newColumnDefaultRegistry() *columnDefaultRegistry {
+	return &columnDefaultRegistry{
+		defaults: make(map[string]map[string]interface{}),
+	}
+}
+
+// set merges defaults for a table, later values override earlier ones for the same column
+func (r *columnDefaultRegistry) set(table string, defaults map[string]interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := strings.ToLower(table)
+	merged := make(map[string]interface{}, len(r.defaults[key])+len(defaults))
+	for col, v := range r.defaults[key] {
+		merged[col] = v
+	}
+	for col, v := range defaults {
+		merged[col] = v
+	}
+	r.defaults[key] = merged
+}
+
+// get returns the defaults for a table (read-only)
+func (r *columnDefaultRegistry) get(table string) map[string]interface{} {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.defaults[strings.ToLower(table)]
+}
+
+// remove removes defaults for a table
+func (r *columnDefaultRegistry) remove(table string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.defaults, strings.ToLower(table))
+}
+
+// --- Global Functions (for default database) ---
+
+// ConfigColumnDefaults 配置表的插入默认值：InsertRecord、InsertRecordIgnore、BatchInsertRecord 插入的记录中没有某列时自动填入默认值
+// 记录中已有的列（包括显式设置为 nil 的列）保持不变；值为 func() interface{} 时每次插入调用一次取得默认值
+// 多次调用会合并，同名列以后一次为准；SaveRecord 只在插入新行时使用默认值，更新已有行时不使用，避免覆盖已有数据
+// 示例:
+//
+//	eorm.ConfigColumnDefaults("users", map[string]interface{}{
+//	    "status": "active",
+//	    "token":  func() interface{} { return uuid.NewString() },
+//	})
+//	eorm.InsertRecord("users", eorm.NewRecord().Set("name", "Tom")) // status = 'active'
+func ConfigColumnDefaults(table string, defaults map[string]interface{}) {
+	db, err := defaultDB()
+	if err != nil {
+		return
+	}
+	db.ConfigColumnDefaults(table, defaults)
+}
+
+// ConfigDefaultRecord 以一条记录作为表的插入默认值模板，等同于 ConfigColumnDefaults
+// 示例: eorm.ConfigDefaultRecord("users", eorm.NewRecord().Set("status", "active").Set("level", 1))
+func ConfigDefaultRecord(table string, template *Record) {
+	db, err := defaultDB()
+	if err != nil {
+		return
+	}
+	db.ConfigDefaultRecord(table, template)
+}
+
+// RemoveColumnDefaults 移除表的插入默认值
+func RemoveColumnDefaults(table string) {
+	db, err := defaultDB()
+	if err != nil {
+		return
+	}
+	db.RemoveColumnDefaults(table)
+}
+
+// --- DB Methods ---
+
+// ConfigColumnDefaults 配置表的插入默认值，见 ConfigColumnDefaults
+func (db *DB) ConfigColumnDefaults(table string, defaults map[string]interface{}) *DB {
+	if db.lastErr != nil || db.dbMgr == nil {
+		return db
+	}
+	if err := validateIdentifier(table); err != nil {
+		db.lastErr = err
+		return db
+	}
+	for col := range defaults {
+		if err := validateIdentifier(col); err != nil {
+			db.lastErr = err
+			return db
+		}
+	}
+	db.dbMgr.setColumnDefaults(table, defaults)
+	return db
+}
+
+// ConfigDefaultRecord 以一条记录作为表的插入默认值模板
+func (db *DB) ConfigDefaultRecord(table string, template *Record) *DB {
+	if template == nil {
+		return db
+	}
+	defaults := make(map[string]interface{})
+	for _, col := range template.Keys() {
+		defaults[col] = template.Get(col)
+	}
+	return db.ConfigColumnDefaults(table, defaults)
+}
+
+// RemoveColumnDefaults 移除表的插入默认值
+func (db *DB) RemoveColumnDefaults(table string) *DB {
+	if db.lastErr != nil || db.dbMgr == nil {
+		return db
+	}
+	if db.dbMgr.columnDefaults != nil {
+		db.dbMgr.columnDefaults.remove(table)
+	}
+	return db
+}
+
+// --- dbManager Methods ---
+
+// setColumnDefaults sets insert defaults for a table
+func (mgr *dbManager) setColumnDefaults(table string, defaults map[string]interface{}) {
+	if mgr.columnDefaults == nil {
+		mgr.columnDefaults = newColumnDefaultRegistry()
+	}
+	mgr.columnDefaults.set(table, defaults)
+}
+
+// hasColumnDefaults reports whether the table has insert defaults
+func (mgr *dbManager) hasColumnDefaults(table string) bool {
+	return mgr.columnDefaults != nil && len(mgr.columnDefaults.get(table)) > 0
+}
+
+// applyColumnDefaults fills absent columns of the records from the table's insert defaults
+func (mgr *dbManager) applyColumnDefaults(table string, records ...*Record) {
+	if mgr.columnDefaults == nil {
+		return
+	}
+	defaults := mgr.columnDefaults.get(table)
+	if len(defaults) == 0 {
+		return
+	}
+	for _, record := range records {
+		if record == nil {
+			continue
+		}
+		for col, v := range defaults {
+			if record.Has(col) {
+				continue
+			}
+			if fn, ok := v.(func() interface{}); ok {
+				v = fn()
+			}
+			record.Set(col, v)
+		}
+	}
+}
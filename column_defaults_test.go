@@ -0,0 +1,45 @@
+package eorm
+
+import "testing"
+
+func TestColumnDefaultsOnInsertPaths(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func(db *DB) error
+	}{
+		{"InsertRecordIgnore", func(db *DB) error {
+			_, err := db.InsertRecordIgnore("cd_users", NewRecord().Set("id", 1).Set("name", "a"))
+			return err
+		}},
+		{"SaveRecord", func(db *DB) error {
+			_, err := db.SaveRecord("cd_users", NewRecord().Set("id", 1).Set("name", "a"))
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openTestDB(t, "CREATE TABLE cd_users (id INTEGER PRIMARY KEY, name TEXT, status TEXT)")
+			db.ConfigColumnDefaults("cd_users", map[string]interface{}{"status": "active"})
+
+			if err := tt.run(db); err != nil {
+				t.Fatal(err)
+			}
+			if n := countRows(t, db, "cd_users", "id = 1 AND status = 'active'"); n != 1 {
+				t.Fatalf("default status was not applied")
+			}
+		})
+	}
+}
+
+func TestSaveRecordKeepsExistingColumnsOverDefaults(t *testing.T) {
+	db := openTestDB(t, "CREATE TABLE cd_users (id INTEGER PRIMARY KEY, name TEXT, status TEXT)")
+	mustExec(t, db, "INSERT INTO cd_users (id, name, status) VALUES (1, 'a', 'banned')")
+	db.ConfigColumnDefaults("cd_users", map[string]interface{}{"status": "active"})
+
+	if _, err := db.SaveRecord("cd_users", NewRecord().Set("id", 1).Set("name", "b")); err != nil {
+		t.Fatal(err)
+	}
+	if n := countRows(t, db, "cd_users", "id = 1 AND name = 'b' AND status = 'banned'"); n != 1 {
+		t.Fatalf("SaveRecord update overwrote an existing column with its default")
+	}
+}
@@ -0,0 +1,110 @@
+package eorm
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+)
+
+// initConnector 包装驱动的 Connector，每个新建的物理连接在交给连接池之前执行初始化
+type initConnector struct {
+	base   driver.Connector
+	config *Config
+}
+
+// dsnConnector 驱动未实现 driver.DriverContext 时使用的 Connector
+type dsnConnector struct {
+	dsn string
+	drv driver.Driver
+}
+
+func (c dsnConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.drv.Open(c.dsn)
+}
+
+func (c dsnConnector) Driver() driver.Driver {
+	return c.drv
+}
+
+// Connect 建立连接并依次执行 InitSQL 和 OnConnect，任一失败时关闭连接并返回错误
+func (c *initConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.base.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, stmt := range c.config.InitSQL {
+		if err := execDriverConn(ctx, conn, stmt); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("eorm: connection init SQL %q failed: %w", stmt, err)
+		}
+	}
+	if c.config.OnConnect != nil {
+		if err := c.config.OnConnect(ctx, conn); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("eorm: OnConnect failed: %w", err)
+		}
+	}
+	return conn, nil
+}
+
+func (c *initConnector) Driver() driver.Driver {
+	return c.base.Driver()
+}
+
+// openWithConnInit 打开在每个新连接上执行初始化的连接池
+func openWithConnInit(driverName, dsn string, config *Config) (*sql.DB, error) {
+	// sql.Open 不会建立连接，这里只用于取得已注册的驱动
+	probe, err := sql.Open(driverName, dsn)
+	if err != nil {
+		return nil, err
+	}
+	drv := probe.Driver()
+	probe.Close()
+
+	var base driver.Connector = dsnConnector{dsn: dsn, drv: drv}
+	if dc, ok := drv.(driver.DriverContext); ok {
+		if base, err = dc.OpenConnector(dsn); err != nil {
+			return nil, err
+		}
+	}
+	return sql.OpenDB(&initConnector{base: base, config: config}), nil
+}
+
+// ExecOnConn 在 OnConnect 收到的驱动连接上执行不带参数的 SQL
+// 示例:
+//
+//	config.OnConnect = func(ctx context.Context, conn driver.Conn) error {
+//	    return eorm.ExecOnConn(ctx, conn, "SET search_path TO tenant_a, public")
+//	}
+func ExecOnConn(ctx context.Context, conn driver.Conn, query string) error {
+	return execDriverConn(ctx, conn, query)
+}
+
+// execDriverConn 按驱动支持的接口执行 SQL
+func execDriverConn(ctx context.Context, conn driver.Conn, query string) error {
+	if execer, ok := conn.(driver.ExecerContext); ok {
+		_, err := execer.ExecContext(ctx, query, nil)
+		if err != driver.ErrSkip {
+			return err
+		}
+	}
+
+	var stmt driver.Stmt
+	var err error
+	if preparer, ok := conn.(driver.ConnPrepareContext); ok {
+		stmt, err = preparer.PrepareContext(ctx, query)
+	} else {
+		stmt, err = conn.Prepare(query)
+	}
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	if execer, ok := stmt.(driver.StmtExecContext); ok {
+		_, err = execer.ExecContext(ctx, nil)
+		return err
+	}
+	_, err = stmt.Exec(nil)
+	return err
+}
@@ -27,7 +27,15 @@ type ConnectionMonitor struct {
 	ticker         *time.Ticker  // 定时器
 	stopCh         chan struct{} // 停止信号
 	lastHealthy    bool          // 上次检查的健康状态（用于状态变化检测）
+	paused         bool          // 是否已暂停检查
+	lastCheck      time.Time     // 最近一次执行检查的时间
+	failures       int           // 连续失败次数
+	lastErr        error         // 最近一次检查的错误
+	wakeCh         chan struct{} // 暂停/恢复或调整间隔时唤醒主循环
 	mu             sync.RWMutex  // 读写锁
+
+	// failover 检查失败后的故障转移处理，返回是否已切换到新连接
+	failover func(failures int) bool
 }
 
 // 全局监控器管理
@@ -106,6 +114,13 @@ func (cm *ConnectionMonitor) checkConnection() bool {
 
 	// 只在状态变化时记录日志
 	cm.mu.Lock()
+	cm.lastCheck = time.Now()
+	cm.lastErr = err
+	if isHealthy {
+		cm.failures = 0
+	} else {
+		cm.failures++
+	}
 	if cm.lastHealthy != isHealthy {
 		if isHealthy {
 			LogConnectionRecovered(cm.dbName)
@@ -147,9 +162,8 @@ func (cm *ConnectionMonitor) run() {
 	}()
 
 	// 使用正常间隔开始检查
-	currentInterval := cm.normalInterval
-
 	cm.mu.Lock()
+	currentInterval := cm.normalInterval
 	cm.ticker = time.NewTicker(currentInterval)
 	cm.mu.Unlock()
 
@@ -165,28 +179,46 @@ func (cm *ConnectionMonitor) run() {
 		select {
 		case <-cm.stopCh:
 			return
+		case <-cm.wakeCh:
+			// 暂停/恢复或间隔调整后，按最新的间隔重置定时器
 		case <-ticker.C:
-			isHealthy := cm.checkConnection()
-
-			// 根据连接状态调整检查间隔
-			var newInterval time.Duration
-			if isHealthy {
-				newInterval = cm.normalInterval
-			} else {
-				newInterval = cm.errorInterval
+			cm.mu.RLock()
+			paused := cm.paused
+			cm.mu.RUnlock()
+			if paused {
+				continue
 			}
-
-			// 如果间隔需要调整，重置定时器
-			if newInterval != currentInterval {
-				cm.mu.Lock()
-				if cm.ticker != nil {
-					cm.ticker.Stop()
-					cm.ticker = time.NewTicker(newInterval)
-					currentInterval = newInterval
+			if !cm.checkConnection() && cm.failover != nil {
+				cm.mu.RLock()
+				failures := cm.failures
+				cm.mu.RUnlock()
+				if cm.failover(failures) {
+					// 已切换到新连接，重新累计失败次数，下次检查成功时记录恢复日志
+					cm.mu.Lock()
+					cm.failures = 0
+					cm.mu.Unlock()
 				}
-				cm.mu.Unlock()
 			}
 		}
+
+		// 根据连接状态调整检查间隔
+		cm.mu.RLock()
+		newInterval := cm.normalInterval
+		if !cm.lastHealthy {
+			newInterval = cm.errorInterval
+		}
+		cm.mu.RUnlock()
+
+		// 如果间隔需要调整，重置定时器
+		if newInterval != currentInterval {
+			cm.mu.Lock()
+			if cm.ticker != nil {
+				cm.ticker.Stop()
+				cm.ticker = time.NewTicker(newInterval)
+				currentInterval = newInterval
+			}
+			cm.mu.Unlock()
+		}
 	}
 }
 
@@ -28,10 +28,14 @@ type structCacheInfo struct {
 
 // getStructCacheInfo 获取或创建结构体的缓存信息
 // 使用 localCache 缓存结构体的反射信息，避免重复解析
-func getStructCacheInfo(structType reflect.Type) *structCacheInfo {
+// naming 为 nil 时未写 tag 的字段使用小写字段名作为列名
+func getStructCacheInfo(structType reflect.Type, naming NamingStrategy) *structCacheInfo {
 	// 使用 Type 的字符串表示作为缓存键
 	// 这样可以自动处理多数据库同名表的问题（不同包的同名结构体有不同的 Type）
 	cacheKey := structType.String()
+	if naming != nil {
+		cacheKey += fmt.Sprintf("|%T:%v", naming, naming)
+	}
 
 	// 尝试从本地缓存获取
 	if cached, ok := LocalCacheGet(structCacheRepository, cacheKey); ok {
@@ -60,7 +64,11 @@ func getStructCacheInfo(structType reflect.Type) *structCacheInfo {
 			continue
 		}
 		if colName == "" {
-			colName = strings.ToLower(field.Name)
+			if naming != nil {
+				colName = naming.ColumnName(field.Name)
+			} else {
+				colName = strings.ToLower(field.Name)
+			}
 		}
 
 		// 处理逗号分隔的 tag（如 json:"id,omitempty"）
@@ -91,6 +99,11 @@ func getStructCacheInfo(structType reflect.Type) *structCacheInfo {
 // ToStruct converts a single Record to a struct.
 // dest must be a pointer to a struct.
 func ToStruct(r *Record, dest interface{}) error {
+	return toStructWith(r, dest, defaultNamingStrategy())
+}
+
+// toStructWith 按命名策略将 Record 转换为结构体
+func toStructWith(r *Record, dest interface{}, naming NamingStrategy) error {
 	if r == nil {
 		return fmt.Errorf("eorm: record is nil")
 	}
@@ -103,11 +116,16 @@ func ToStruct(r *Record, dest interface{}) error {
 		return fmt.Errorf("eorm: dest must be a pointer to a struct")
 	}
 
-	return setStructFromRecord(val.Elem(), r)
+	return setStructFromRecord(val.Elem(), r, naming)
 }
 
 // FromStruct populates a Record from a struct.
 func FromStruct(src interface{}, r *Record) error {
+	return fromStructWith(src, r, defaultNamingStrategy())
+}
+
+// fromStructWith 按命名策略将结构体字段写入 Record
+func fromStructWith(src interface{}, r *Record, naming NamingStrategy) error {
 	if src == nil {
 		return fmt.Errorf("eorm: src is nil")
 	}
@@ -124,21 +142,26 @@ func FromStruct(src interface{}, r *Record) error {
 		return fmt.Errorf("eorm: src must be a struct or pointer to struct")
 	}
 
-	return setRecordFromStruct(r, val)
+	return setRecordFromStruct(r, val, naming)
 }
 
 // ToRecord converts a struct to a new Record.
 func ToRecord(src interface{}) *Record {
+	return toRecordWith(src, defaultNamingStrategy())
+}
+
+// toRecordWith 按命名策略将结构体转换为新的 Record
+func toRecordWith(src interface{}, naming NamingStrategy) *Record {
 	r := NewRecord()
-	_ = FromStruct(src, r)
+	_ = fromStructWith(src, r, naming)
 	return r
 }
 
-func setStructFromRecord(structVal reflect.Value, r *Record) error {
+func setStructFromRecord(structVal reflect.Value, r *Record, naming NamingStrategy) error {
 	structType := structVal.Type()
 
 	// 获取缓存的结构体信息（首次调用会解析并缓存，后续直接使用缓存）
-	cacheInfo := getStructCacheInfo(structType)
+	cacheInfo := getStructCacheInfo(structType, naming)
 
 	// 使用缓存的字段信息，避免重复反射解析
 	for _, fieldInfo := range cacheInfo.fields {
@@ -163,11 +186,11 @@ func setStructFromRecord(structVal reflect.Value, r *Record) error {
 	return nil
 }
 
-func setRecordFromStruct(r *Record, structVal reflect.Value) error {
+func setRecordFromStruct(r *Record, structVal reflect.Value, naming NamingStrategy) error {
 	structType := structVal.Type()
 
 	// 获取缓存的结构体信息（首次调用会解析并缓存，后续直接使用缓存）
-	cacheInfo := getStructCacheInfo(structType)
+	cacheInfo := getStructCacheInfo(structType, naming)
 
 	// 使用缓存的字段信息，避免重复反射解析
 	for _, fieldInfo := range cacheInfo.fields {
@@ -282,6 +305,11 @@ func setFieldValue(field reflect.Value, value interface{}) error {
 // ToStructs converts a slice of Records to a slice of structs or struct pointers.
 // dest must be a pointer to a slice.
 func ToStructs(records []*Record, dest interface{}) error {
+	return toStructsWith(records, dest, defaultNamingStrategy())
+}
+
+// toStructsWith 按命名策略将多条 Record 转换为结构体切片
+func toStructsWith(records []*Record, dest interface{}, naming NamingStrategy) error {
 	if dest == nil {
 		return fmt.Errorf("eorm: dest cannot be nil")
 	}
@@ -307,7 +335,7 @@ func ToStructs(records []*Record, dest interface{}) error {
 
 	for i := range records {
 		newElem := reflect.New(baseType)
-		if err := ToStruct(records[i], newElem.Interface()); err != nil {
+		if err := toStructWith(records[i], newElem.Interface(), naming); err != nil {
 			return err
 		}
 
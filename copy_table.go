@@ -0,0 +1,292 @@
+package eorm
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ConflictStrategy 复制数据时目标表主键/唯一键冲突的处理方式
+type ConflictStrategy int
+
+const (
+	ConflictError  ConflictStrategy = iota // 冲突时返回错误（默认，批量插入）
+	ConflictSkip                           // 冲突时跳过该行（同 InsertRecordIgnore）
+	ConflictUpdate                         // 冲突时更新已有行（同 SaveRecord）
+)
+
+// CopyTableOptions CopyTable 的选项
+type CopyTableOptions struct {
+	DestTable  string                  // 目标表名（默认与源表相同）
+	Columns    []string                // 复制的源列（默认全部列）
+	ColumnMap  map[string]string       // 源列名 -> 目标列名（未列出的列名称不变）
+	Where      string                  // 源数据过滤条件
+	Args       []interface{}           // Where 的参数
+	OrderBy    string                  // 源数据读取顺序，如 "id"（默认不排序）
+	ChunkSize  int                     // 每批写入的行数，每批一个事务（默认 DefaultBatchSize）
+	Conflict   ConflictStrategy        // 冲突处理方式
+	Transform  func(*Record) error     // 写入前修改每行数据，返回错误时中止复制
+	OnProgress func(CopyTableProgress) // 每批写入后回调
+}
+
+// CopyTableProgress CopyTable 的进度和结果
+type CopyTableProgress struct {
+	Table   string        // 目标表名
+	Read    int64         // 已读取的行数
+	Written int64         // 已写入（插入或更新）的行数
+	Skipped int64         // 因冲突跳过的行数
+	Chunks  int64         // 已完成的批次数
+	Elapsed time.Duration // 已用时间
+}
+
+// CopyTable 将 srcDB 中的表数据以流式方式复制到 dstDB（可以是不同类型的数据库），目标表需已存在
+// 源数据逐行读取、按 ChunkSize 分批写入，每批在目标库的一个事务中完成；写入前按目标表的列类型转换基本类型的值
+// （如 SQLite 的文本日期转为 time.Time、MySQL 的 0/1 转为 bool），目标表不存在的列被忽略
+// 出错时已提交的批次不会回滚，返回值中的进度可用于确定断点（配合 Where/OrderBy 续传）
+// 示例:
+//
+//	progress, err := eorm.CopyTable(eorm.Use("mysql"), eorm.Use("postgres"), "users", &eorm.CopyTableOptions{
+//	    ChunkSize:  1000,
+//	    OrderBy:    "id",
+//	    Conflict:   eorm.ConflictUpdate,
+//	    OnProgress: func(p eorm.CopyTableProgress) { log.Printf("copied %d rows", p.Written) },
+//	})
+func CopyTable(srcDB, dstDB *DB, table string, opts *CopyTableOptions) (CopyTableProgress, error) {
+	if opts == nil {
+		opts = &CopyTableOptions{}
+	}
+	destTable := opts.DestTable
+	if destTable == "" {
+		destTable = table
+	}
+	progress := CopyTableProgress{Table: destTable}
+	if srcDB == nil || dstDB == nil || srcDB.dbMgr == nil || dstDB.dbMgr == nil {
+		return progress, fmt.Errorf("eorm: CopyTable requires both source and destination databases")
+	}
+	if srcDB.lastErr != nil {
+		return progress, srcDB.lastErr
+	}
+	if dstDB.lastErr != nil {
+		return progress, dstDB.lastErr
+	}
+	if err := validateIdentifier(table); err != nil {
+		return progress, err
+	}
+	if err := validateIdentifier(destTable); err != nil {
+		return progress, err
+	}
+	for _, col := range opts.Columns {
+		if err := validateIdentifier(col); err != nil {
+			return progress, err
+		}
+	}
+	if opts.OrderBy != "" {
+		if err := validateSafeSQL(opts.OrderBy); err != nil {
+			return progress, err
+		}
+	}
+	chunkSize := opts.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = DefaultBatchSize
+	}
+
+	// 目标表的列类型，用于类型转换和过滤不存在的列
+	dstColumns, err := dstDB.dbMgr.getTableColumns(destTable)
+	if err != nil {
+		return progress, err
+	}
+	if len(dstColumns) == 0 {
+		return progress, fmt.Errorf("eorm: CopyTable destination table '%s' not found or has no columns", destTable)
+	}
+	goTypes := make(map[string]string, len(dstColumns))
+	for _, col := range dstColumns {
+		goTypes[strings.ToLower(col.Name)] = dbTypeToGoType(col.Type, false, false)
+	}
+
+	selectCols := "*"
+	if len(opts.Columns) > 0 {
+		selectCols = strings.Join(opts.Columns, ", ")
+	}
+	querySQL := fmt.Sprintf("SELECT %s FROM %s", selectCols, table)
+	where, args := applyRowFilter(srcDB.ctx, table, opts.Where, opts.Args, false)
+	if where != "" {
+		querySQL += " WHERE " + where
+	}
+	if opts.OrderBy != "" {
+		querySQL += " ORDER BY " + opts.OrderBy
+	}
+
+	ctx := contextOrBackground(srcDB.ctx)
+	executor, err := srcDB.getExecutor()
+	if err != nil {
+		return progress, err
+	}
+	querySQL, args = srcDB.dbMgr.prepareQuerySQL(querySQL, args...)
+	var rows *sql.Rows
+	if ce, ok := executor.(sqlExecutorContext); ok {
+		rows, err = ce.QueryContext(ctx, querySQL, args...)
+	} else {
+		rows, err = executor.Query(querySQL, args...)
+	}
+	if err != nil {
+		return progress, err
+	}
+	defer rows.Close()
+
+	start := time.Now()
+	flush := func(chunk []*Record) error {
+		written, skipped, err := copyChunk(dstDB, destTable, chunk, opts.Conflict)
+		if err != nil {
+			return err
+		}
+		progress.Written += written
+		progress.Skipped += skipped
+		progress.Chunks++
+		progress.Elapsed = time.Since(start)
+		if opts.OnProgress != nil {
+			opts.OnProgress(progress)
+		}
+		return nil
+	}
+
+	err = scanRecordStream(rows, func(record *Record) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		progress.Read++
+		out, err := mapCopyRecord(record, opts.ColumnMap, goTypes)
+		if err != nil {
+			return err
+		}
+		if opts.Transform != nil {
+			if err := opts.Transform(out); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, chunkSize, flush)
+	progress.Elapsed = time.Since(start)
+	return progress, err
+}
+
+// scanRecordStream 逐行扫描结果集，每行交给 onRow 处理后累积，累积满 chunkSize 行时调用 flush
+// onRow 可原地修改传入的 Record，返回错误时停止扫描
+func scanRecordStream(rows *sql.Rows, onRow func(*Record) error, chunkSize int, flush func([]*Record) error) error {
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return err
+	}
+	dbTypes := make([]string, len(columns))
+	for i := range columnTypes {
+		dbTypes[i] = strings.ToUpper(columnTypes[i].DatabaseTypeName())
+	}
+	cols := newScanColumns(columns, dbTypes)
+
+	values := make([]interface{}, len(columns))
+	valuePtrs := make([]interface{}, len(columns))
+	for i := range columns {
+		valuePtrs[i] = &values[i]
+	}
+
+	chunk := make([]*Record, 0, chunkSize)
+	for rows.Next() {
+		if err := rows.Scan(valuePtrs...); err != nil {
+			return err
+		}
+		record := NewRecord()
+		for i, col := range cols.names {
+			record.setDirectLower(col, cols.lowers[i], processDBValue(values[i], cols.dbTypes[i]))
+		}
+		if err := onRow(record); err != nil {
+			return err
+		}
+		chunk = append(chunk, record)
+		if len(chunk) >= chunkSize {
+			if err := flush(chunk); err != nil {
+				return err
+			}
+			chunk = make([]*Record, 0, chunkSize)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(chunk) > 0 {
+		return flush(chunk)
+	}
+	return nil
+}
+
+// mapCopyRecord 按 ColumnMap 重命名列、去掉目标表不存在的列，并按目标列类型转换值（结果写回 record）
+func mapCopyRecord(record *Record, columnMap map[string]string, goTypes map[string]string) (*Record, error) {
+	keys := record.Keys()
+	values := make([]interface{}, len(keys))
+	for i, key := range keys {
+		values[i] = record.Get(key)
+	}
+	record.Clear()
+	for i, key := range keys {
+		target := key
+		if mapped, ok := columnMap[key]; ok {
+			target = mapped
+		}
+		goType, ok := goTypes[strings.ToLower(target)]
+		if !ok {
+			continue
+		}
+		value := values[i]
+		if b, isBytes := value.([]byte); isBytes && strings.Contains(goType, "string") {
+			value = string(b)
+		}
+		if value != nil && isBasicType(value) {
+			if converted, err := coerceValue(value, goType); err == nil {
+				value = converted
+			}
+		}
+		record.Set(target, value)
+	}
+	if len(record.Keys()) == 0 {
+		return nil, fmt.Errorf("eorm: CopyTable found no source columns matching the destination table")
+	}
+	return record, nil
+}
+
+// copyChunk 在目标库的一个事务中写入一批记录，返回写入和跳过的行数
+func copyChunk(dstDB *DB, table string, chunk []*Record, conflict ConflictStrategy) (written, skipped int64, err error) {
+	err = dstDB.Transaction(func(tx *Tx) error {
+		written, skipped = 0, 0
+		switch conflict {
+		case ConflictSkip:
+			for _, record := range chunk {
+				inserted, err := tx.InsertRecordIgnore(table, record)
+				if err != nil {
+					return err
+				}
+				if inserted {
+					written++
+				} else {
+					skipped++
+				}
+			}
+		case ConflictUpdate:
+			for _, record := range chunk {
+				if _, err := tx.SaveRecord(table, record); err != nil {
+					return err
+				}
+				written++
+			}
+		default:
+			if _, err := tx.BatchInsertRecord(table, chunk, len(chunk)); err != nil {
+				return err
+			}
+			written = int64(len(chunk))
+		}
+		return nil
+	})
+	return written, skipped, err
+}
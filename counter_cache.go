@@ -0,0 +1,272 @@
+package eorm
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CounterCacheConfig holds a counter cache definition: ParentTable.CounterColumn
+// stores the number of ChildTable rows whose ForeignKey references the parent
+type CounterCacheConfig struct {
+	ParentTable   string // Parent table, e.g., "users"
+	CounterColumn string // Counter column on the parent, e.g., "orders_count"
+	ChildTable    string // Child table, e.g., "orders"
+	ForeignKey    string // Child column referencing the parent primary key, e.g., "user_id"
+}
+
+// counterCacheRegistry stores counter cache configurations per database
+type counterCacheRegistry struct {
+	configs map[string][]*CounterCacheConfig // child table -> configs
+	mu      sync.RWMutex
+}
+
+// newCounterCacheRegistry creates a new counter cache registry
+func newCounterCacheRegistry() *counterCacheRegistry {
+	return &counterCacheRegistry{
+		configs: make(map[string][]*CounterCacheConfig),
+	}
+}
+
+// set adds or replaces the config identified by parent table and counter column
+func (r *counterCacheRegistry) set(config *CounterCacheConfig) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := strings.ToLower(config.ChildTable)
+	configs := r.configs[key]
+	for i, c := range configs {
+		if strings.EqualFold(c.ParentTable, config.ParentTable) && strings.EqualFold(c.CounterColumn, config.CounterColumn) {
+			configs[i] = config
+			return
+		}
+	}
+	r.configs[key] = append(configs, config)
+}
+
+// remove removes the config identified by parent table and counter column
+func (r *counterCacheRegistry) remove(parentTable, counterColumn string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for key, configs := range r.configs {
+		kept := configs[:0:0]
+		for _, c := range configs {
+			if !strings.EqualFold(c.ParentTable, parentTable) || !strings.EqualFold(c.CounterColumn, counterColumn) {
+				kept = append(kept, c)
+			}
+		}
+		if len(kept) == 0 {
+			delete(r.configs, key)
+		} else {
+			r.configs[key] = kept
+		}
+	}
+}
+
+// get returns the configs whose child is the given table
+func (r *counterCacheRegistry) get(childTable string) []*CounterCacheConfig {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.configs[strings.ToLower(childTable)]
+}
+
+// --- Global Functions (for default database) ---
+
+// ConfigCounterCache keeps parentTable.counterColumn in sync with the number of childTable rows.
+// Insert/InsertRecord/BatchInsert on the child increment the parent counter and physical deletes
+// (Delete, BatchDeleteRecord and BatchDeleteByIds without soft delete, ForceDelete) decrement it,
+// in the same transaction as the write:
+// inside a Tx the Tx is used, otherwise a transaction is started for the write and the counter update.
+// Soft-deleted rows still count, and changing the foreign key with Update is not tracked.
+// Example: eorm.ConfigCounterCache("users", "orders_count", "orders", "user_id")
+func ConfigCounterCache(parentTable, counterColumn, childTable, foreignKey string) {
+	db, err := defaultDB()
+	if err != nil {
+		return
+	}
+	db.ConfigCounterCache(parentTable, counterColumn, childTable, foreignKey)
+}
+
+// RemoveCounterCache removes the counter cache of parentTable.counterColumn
+func RemoveCounterCache(parentTable, counterColumn string) {
+	db, err := defaultDB()
+	if err != nil {
+		return
+	}
+	db.RemoveCounterCache(parentTable, counterColumn)
+}
+
+// --- DB Methods ---
+
+// ConfigCounterCache keeps parentTable.counterColumn in sync with the number of childTable rows
+func (db *DB) ConfigCounterCache(parentTable, counterColumn, childTable, foreignKey string) *DB {
+	if db.lastErr != nil || db.dbMgr == nil {
+		return db
+	}
+	for _, name := range []string{parentTable, counterColumn, childTable, foreignKey} {
+		if err := validateIdentifier(name); err != nil {
+			db.lastErr = err
+			return db
+		}
+	}
+	if !db.dbMgr.checkTableColumn(parentTable, counterColumn) {
+		LogWarn(fmt.Sprintf("计数缓存配置警告: 表 '%s' 中不存在字段 '%s'", parentTable, counterColumn), NewRecord().
+			Set("db", db.dbMgr.name).
+			Set("table", parentTable).
+			Set("field", counterColumn))
+	}
+	if !db.dbMgr.checkTableColumn(childTable, foreignKey) {
+		LogWarn(fmt.Sprintf("计数缓存配置警告: 表 '%s' 中不存在字段 '%s'", childTable, foreignKey), NewRecord().
+			Set("db", db.dbMgr.name).
+			Set("table", childTable).
+			Set("field", foreignKey))
+	}
+
+	if db.dbMgr.counterCaches == nil {
+		db.dbMgr.counterCaches = newCounterCacheRegistry()
+	}
+	db.dbMgr.counterCaches.set(&CounterCacheConfig{
+		ParentTable:   parentTable,
+		CounterColumn: counterColumn,
+		ChildTable:    childTable,
+		ForeignKey:    foreignKey,
+	})
+	return db
+}
+
+// RemoveCounterCache removes the counter cache of parentTable.counterColumn
+func (db *DB) RemoveCounterCache(parentTable, counterColumn string) *DB {
+	if db.lastErr != nil || db.dbMgr == nil {
+		return db
+	}
+	if db.dbMgr.counterCaches != nil {
+		db.dbMgr.counterCaches.remove(parentTable, counterColumn)
+	}
+	return db
+}
+
+// --- dbManager Methods ---
+
+// getCounterCacheConfigs returns counter caches maintained by writes on the child table
+func (mgr *dbManager) getCounterCacheConfigs(childTable string) []*CounterCacheConfig {
+	if mgr.counterCaches == nil {
+		return nil
+	}
+	return mgr.counterCaches.get(childTable)
+}
+
+// withCounterCacheTx runs fn in a new transaction when the child table has counter caches
+// and executor is not already a transaction; handled reports whether fn was run
+func withCounterCacheTx[T any](mgr *dbManager, executor sqlExecutor, childTable string, fn func(executor sqlExecutor) (T, error)) (result T, handled bool, err error) {
+	if len(mgr.getCounterCacheConfigs(childTable)) == 0 {
+		return result, false, nil
+	}
+	return withWriteTx(mgr, executor, fn)
+}
+
+// counterDelta is the counter change for one parent row
+type counterDelta struct {
+	parentID interface{}
+	delta    int64
+}
+
+// collectCounterDeltas groups the foreign key values of records into per-parent deltas
+func collectCounterDeltas(foreignKey string, records []*Record, sign int64) []counterDelta {
+	var deltas []counterDelta
+	index := make(map[string]int)
+	for _, record := range records {
+		if record == nil {
+			continue
+		}
+		value := record.Get(foreignKey)
+		if value == nil {
+			continue
+		}
+		key := fmt.Sprintf("%T:%v", value, value)
+		if i, ok := index[key]; ok {
+			deltas[i].delta += sign
+			continue
+		}
+		index[key] = len(deltas)
+		deltas = append(deltas, counterDelta{parentID: value, delta: sign})
+	}
+	return deltas
+}
+
+// incrementCounterCaches increments parent counters for inserted child records
+func (mgr *dbManager) incrementCounterCaches(executor sqlExecutor, childTable string, records []*Record) error {
+	for _, config := range mgr.getCounterCacheConfigs(childTable) {
+		deltas := collectCounterDeltas(config.ForeignKey, records, 1)
+		if err := mgr.applyCounterDeltas(executor, config, deltas); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// countCounterCacheChildren counts, per counter cache and parent, the child rows matching where
+// so they can be decremented after the delete
+func (mgr *dbManager) countCounterCacheChildren(executor sqlExecutor, childTable, where string, whereArgs []interface{}) (map[*CounterCacheConfig][]counterDelta, error) {
+	configs := mgr.getCounterCacheConfigs(childTable)
+	if len(configs) == 0 {
+		return nil, nil
+	}
+
+	result := make(map[*CounterCacheConfig][]counterDelta, len(configs))
+	for _, config := range configs {
+		querySQL := fmt.Sprintf("SELECT %s AS eorm_fk, COUNT(*) AS eorm_cnt FROM %s WHERE (%s) AND %s IS NOT NULL GROUP BY %s",
+			config.ForeignKey, childTable, where, config.ForeignKey, config.ForeignKey)
+		records, err := mgr.queryInternal(executor, querySQL, cloneSlice(whereArgs)...)
+		if err != nil {
+			return nil, fmt.Errorf("eorm: failed to count %s rows for counter cache %s.%s: %v", childTable, config.ParentTable, config.CounterColumn, err)
+		}
+		deltas := make([]counterDelta, 0, len(records))
+		for _, r := range records {
+			deltas = append(deltas, counterDelta{parentID: r.Get("eorm_fk"), delta: -r.GetInt64("eorm_cnt")})
+		}
+		result[config] = deltas
+	}
+	return result, nil
+}
+
+// applyCounterDeltas updates the parent counter column by the given deltas
+func (mgr *dbManager) applyCounterDeltas(executor sqlExecutor, config *CounterCacheConfig, deltas []counterDelta) (err error) {
+	if len(deltas) == 0 {
+		return nil
+	}
+	defer func() { mgr.afterTableWrite(executor, config.ParentTable, err) }()
+
+	pks, err := mgr.getPrimaryKeys(executor, config.ParentTable)
+	if err != nil {
+		return fmt.Errorf("failed to get primary keys: %v", err)
+	}
+	if len(pks) != 1 {
+		return fmt.Errorf("eorm: counter cache requires table %s to have a single primary key", config.ParentTable)
+	}
+
+	querySQL := fmt.Sprintf("UPDATE %s SET %s = COALESCE(%s, 0) + ? WHERE %s = ?",
+		config.ParentTable, config.CounterColumn, config.CounterColumn, pks[0])
+	for _, d := range deltas {
+		if d.delta == 0 {
+			continue
+		}
+		sqlStr, args := mgr.prepareQuerySQL(querySQL, d.delta, d.parentID)
+		start := time.Now()
+		_, err = executor.Exec(sqlStr, args...)
+		mgr.logTrace(start, sqlStr, args, err)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decrementCounterCaches applies the deltas collected by countCounterCacheChildren
+func (mgr *dbManager) decrementCounterCaches(executor sqlExecutor, deltas map[*CounterCacheConfig][]counterDelta) error {
+	for config, d := range deltas {
+		if err := mgr.applyCounterDeltas(executor, config, d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package eorm
+
+import "testing"
+
+func TestCounterCacheStaysInSync(t *testing.T) {
+	ddl := []string{
+		"CREATE TABLE cc_posts (id INTEGER PRIMARY KEY, comments_count INTEGER NOT NULL DEFAULT 0)",
+		"CREATE TABLE cc_comments (id INTEGER PRIMARY KEY, post_id INTEGER, body TEXT)",
+	}
+	tests := []struct {
+		name string
+		run  func(t *testing.T, db *DB)
+		want int64
+	}{
+		{"SaveRecordInsertThenUpdate", func(t *testing.T, db *DB) {
+			for _, body := range []string{"a", "b"} {
+				if _, err := db.SaveRecord("cc_comments", NewRecord().Set("id", 10).Set("post_id", 1).Set("body", body)); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}, 1},
+		{"DeleteLimit", func(t *testing.T, db *DB) {
+			mustExec(t, db, "INSERT INTO cc_comments (id, post_id, body) VALUES (1, 1, 'x'), (2, 1, 'x'), (3, 1, 'x')")
+			mustExec(t, db, "UPDATE cc_posts SET comments_count = 3")
+			if _, err := db.Table("cc_comments").Where("body = ?", "x").OrderBy("id").DeleteLimit(2); err != nil {
+				t.Fatal(err)
+			}
+		}, 1},
+		{"BatchDeleteWhere", func(t *testing.T, db *DB) {
+			mustExec(t, db, "INSERT INTO cc_comments (id, post_id, body) VALUES (1, 1, 'x'), (2, 1, 'x'), (3, 1, 'y')")
+			mustExec(t, db, "UPDATE cc_posts SET comments_count = 3")
+			if _, err := db.BatchDeleteWhere("cc_comments", "body = ?", []interface{}{"x"}, 1); err != nil {
+				t.Fatal(err)
+			}
+		}, 1},
+		{"BatchDeleteRecord", func(t *testing.T, db *DB) {
+			mustExec(t, db, "INSERT INTO cc_comments (id, post_id, body) VALUES (1, 1, 'x'), (2, 1, 'x'), (3, 1, 'x')")
+			mustExec(t, db, "UPDATE cc_posts SET comments_count = 3")
+			records := []*Record{NewRecord().Set("id", 1), NewRecord().Set("id", 2), NewRecord().Set("id", 99)}
+			if _, err := db.BatchDeleteRecord("cc_comments", records, 2); err != nil {
+				t.Fatal(err)
+			}
+		}, 1},
+		{"BatchDeleteByIds", func(t *testing.T, db *DB) {
+			mustExec(t, db, "INSERT INTO cc_comments (id, post_id, body) VALUES (1, 1, 'x'), (2, 1, 'x'), (3, 1, 'x')")
+			mustExec(t, db, "UPDATE cc_posts SET comments_count = 3")
+			if _, err := db.BatchDeleteByIds("cc_comments", []interface{}{2, 3, 99}, 2); err != nil {
+				t.Fatal(err)
+			}
+		}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openTestDB(t, ddl...)
+			mustExec(t, db, "INSERT INTO cc_posts (id) VALUES (1)")
+			db.ConfigCounterCache("cc_posts", "comments_count", "cc_comments", "post_id")
+
+			tt.run(t, db)
+			post, err := db.QueryFirst("SELECT comments_count FROM cc_posts WHERE id = 1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := post.GetInt64("comments_count"); got != tt.want {
+				t.Fatalf("comments_count = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -3,12 +3,14 @@ package eorm
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"fmt"
 	"reflect"
 	"regexp"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 	// 推荐的数据库驱动（用户可根据需要导入）
 	// _ "github.com/go-sql-driver/mysql"           // MySQL驱动
@@ -63,8 +65,27 @@ type Config struct {
 	MonitorNormalInterval time.Duration // 正常检查间隔（默认60秒，0表示禁用监控）
 	MonitorErrorInterval  time.Duration // 故障检查间隔（默认10秒）
 
+	// 故障转移配置（依赖连接监控，MonitorNormalInterval 为 0 时不生效）
+	FailoverDSNs  []string            // 备用 DSN 列表，主连接连续检查失败后按顺序尝试并提升为新的主连接
+	FailoverAfter int                 // 连续失败多少次后触发故障转移（默认3次）
+	OnFailover    func(FailoverEvent) // 故障转移成功或全部备用连接失败时的回调（在监控协程中调用，不应阻塞）
+
+	// 连接初始化配置：连接池每新建一个物理连接都会执行，保证会话级设置对所有连接生效
+	InitSQL   []string                                          // 依次执行的初始化语句，如 "SET time_zone = '+08:00'"、"SET search_path TO app"
+	OnConnect func(ctx context.Context, conn driver.Conn) error // 在 InitSQL 之后调用，返回错误时丢弃该连接（可使用 ExecOnConn 执行 SQL）
+
+	// 命名约定配置：作用于 Table()、代码生成器以及 ToRecord/ToStruct
+	TablePrefix    string         // 表名前缀，如 "app_"，Table("users") 实际查询 app_users（已带前缀的名称不重复添加）
+	NamingStrategy NamingStrategy // 表名、列名和结构体名的转换规则（nil 表示默认映射，如 SnakeNamingStrategy）
+
 	// 预编译语句缓存配置（新增）
 	StmtCacheSize int // 预编译语句缓存大小（默认0表示关闭，大于0表示启用并指定大小）
+
+	// SQLite 配置（仅 SQLite3 有效，通过 DSN 参数作用于每个连接）
+	SQLiteBusyTimeout     time.Duration // 数据库被锁定时的等待时间（busy_timeout），超时后才返回 SQLITE_BUSY
+	SQLiteJournalMode     string        // 日志模式，如 "WAL"（读写并发）、"DELETE"（默认）
+	SQLiteForeignKeys     bool          // 启用外键约束（SQLite 默认关闭）
+	SQLiteSerializeWrites bool          // 进程内串行化非事务写操作，事务以 IMMEDIATE 模式开始，避免并发写入时的 SQLITE_BUSY（事务不经过进程内互斥锁，只依靠 IMMEDIATE 等待写锁）
 }
 
 // SupportedDrivers returns a list of all supported database drivers
@@ -95,10 +116,12 @@ type DB struct {
 	lastErr             error
 	cacheRepositoryName string
 	cacheTTL            time.Duration
-	timeout             time.Duration // Query timeout for this instance
-	cacheProvider       CacheProvider // 指定的缓存提供者（nil 表示使用默认缓存）
-	countCacheTTL       time.Duration // 分页计数缓存时间（-1 表示不使用，0 表示不缓存，>0 表示使用指定时间）
-	executor            SqlExecutor   // 指定的执行器（用于事务支持）
+	cacheEmptyTTL       time.Duration   // 空结果缓存时间（0 表示使用仓库配置，<0 表示不缓存空结果）
+	timeout             time.Duration   // Query timeout for this instance
+	cacheProvider       CacheProvider   // 指定的缓存提供者（nil 表示使用默认缓存）
+	countCacheTTL       time.Duration   // 分页计数缓存时间（-1 表示不使用，0 表示不缓存，>0 表示使用指定时间）
+	executor            SqlExecutor     // 指定的执行器（用于事务支持）
+	ctx                 context.Context // 调用方上下文（行级过滤器、超时控制）
 }
 
 // WithExecutor 指定执行器（用于支持外部事务，如 GORM 事务）
@@ -113,7 +136,11 @@ func (db *DB) getExecutor() (SqlExecutor, error) {
 	if db.executor != nil {
 		return db.executor, nil
 	}
-	return db.dbMgr.getDB()
+	sdb, err := db.dbMgr.getDB()
+	if err != nil {
+		return nil, err
+	}
+	return db.dbMgr.executorFor(sdb), nil
 }
 
 // GetConfig returns the database configuration
@@ -129,19 +156,20 @@ func (db *DB) getTimeout() time.Duration {
 	if db.timeout > 0 {
 		return db.timeout
 	}
-	if db.dbMgr != nil && db.dbMgr.config != nil && db.dbMgr.config.QueryTimeout > 0 {
-		return db.dbMgr.config.QueryTimeout
+	if db.dbMgr != nil && db.dbMgr.config.Load() != nil && db.dbMgr.config.Load().QueryTimeout > 0 {
+		return db.dbMgr.config.Load().QueryTimeout
 	}
 	return 0
 }
 
 // getContext returns a context with timeout if configured
 func (db *DB) getContext() (context.Context, context.CancelFunc) {
+	ctx := contextOrBackground(db.ctx)
 	timeout := db.getTimeout()
 	if timeout > 0 {
-		return context.WithTimeout(context.Background(), timeout)
+		return context.WithTimeout(ctx, timeout)
 	}
-	return context.Background(), func() {}
+	return ctx, func() {}
 }
 
 // getEffectiveCache 获取当前有效的缓存提供者
@@ -164,13 +192,17 @@ func (db *DB) ClearCache(repoName string) *DB {
 // GetStmtCacheStats 获取预编译语句缓存的统计信息
 // 返回包含命中率、大小、淘汰次数等指标的 map
 func (db *DB) GetStmtCacheStats() map[string]interface{} {
-	if db.dbMgr == nil || db.dbMgr.stmtCache == nil {
+	var cache *stmtCache
+	if db.dbMgr != nil {
+		cache = db.dbMgr.stmtCache.Load()
+	}
+	if cache == nil {
 		return map[string]interface{}{
 			"enabled": false,
 			"error":   "cache not initialized",
 		}
 	}
-	return db.dbMgr.stmtCache.Stats()
+	return cache.Stats()
 }
 
 // Tx represents a database transaction with chainable methods
@@ -179,9 +211,15 @@ type Tx struct {
 	dbMgr               *dbManager
 	cacheRepositoryName string
 	cacheTTL            time.Duration
-	timeout             time.Duration // Query timeout for this transaction
-	cacheProvider       CacheProvider // 指定的缓存提供者（nil 表示使用默认缓存）
-	countCacheTTL       time.Duration // 分页计数缓存时间（-1 表示不使用，0 表示不缓存，>0 表示使用指定时间）
+	cacheEmptyTTL       time.Duration   // 空结果缓存时间（0 表示使用仓库配置，<0 表示不缓存空结果）
+	timeout             time.Duration   // Query timeout for this transaction
+	cacheProvider       CacheProvider   // 指定的缓存提供者（nil 表示使用默认缓存）
+	countCacheTTL       time.Duration   // 分页计数缓存时间（-1 表示不使用，0 表示不缓存，>0 表示使用指定时间）
+	continueOnError     bool            // BatchExec 遇到失败语句时通过保存点跳过并继续执行
+	ctx                 context.Context // 调用方上下文（行级过滤器、超时控制）
+	hooks               *txHooks        // AfterCommit/AfterRollback 回调
+	watchdog            *txWatchdog     // 长事务看门狗（未配置时为 nil）
+	leakID              uint64          // 泄漏检测跟踪 ID（未跟踪时为 0）
 }
 
 // getEffectiveCache 获取当前有效的缓存提供者
@@ -259,23 +297,48 @@ func (e *BatchExecError) Error() string {
 // dbManager manages database connections
 type dbManager struct {
 	name            string
-	config          *Config
-	db              *sql.DB
+	config          atomic.Pointer[Config] // 当前配置，SwapDatabase 时整体替换
+	db              atomic.Pointer[sql.DB] // 当前连接池，SwapDatabase 时整体替换
 	mu              sync.RWMutex
 	initMu          sync.Mutex // 用于初始化数据库连接的独立锁
+	writeMu         sync.Mutex // SQLiteSerializeWrites 启用时串行化写操作
 	drivers         map[string]bool
-	pkCache         map[string][]string     // Table name -> PK column names
-	identityCache   map[string]string       // Table name -> Identity column name
-	columnCache     map[string][]ColumnInfo // Table name -> Column info list (新增：列信息缓存)
-	softDeletes     *softDeleteRegistry     // Soft delete configurations
-	timestamps      *timestampRegistry      // Auto timestamp configurations
-	optimisticLocks *optimisticLockRegistry // Optimistic lock configurations
-	stmtCacheTTL    time.Duration           // 已废弃：保留用于向后兼容
-	stmtCache       *stmtCache              // 新的智能语句缓存
+	pkCache         map[string][]string       // Table name -> PK column names
+	identityCache   map[string]string         // Table name -> Identity column name
+	columnCache     map[string][]ColumnInfo   // Table name -> Column info list (新增：列信息缓存)
+	softDeletes     *softDeleteRegistry       // Soft delete configurations
+	timestamps      *timestampRegistry        // Auto timestamp configurations
+	optimisticLocks *optimisticLockRegistry   // Optimistic lock configurations
+	sequences       *sequenceRegistry         // Sequence configurations
+	columnDefaults  *columnDefaultRegistry    // Insert default values
+	entityCaches    *entityCacheRegistry      // Primary key entity cache configurations
+	activeUniques   *activeUniqueRegistry     // Insert-time unique checks over non-deleted rows
+	histories       *historyRegistry          // History table configurations
+	changes         *changeBus                // Entity change subscribers and pending events
+	rowTTLs         *rowTTLRegistry           // Row TTL configurations and reapers
+	counterCaches   *counterCacheRegistry     // Counter cache configurations
+	aggregates      *aggregateRegistry        // Named pre-computed aggregates
+	txWatchdog      *TxWatchdogConfig         // Long-running transaction watchdog (nil means disabled)
+	maxRows         *maxRowsLimit             // Result set row limit for Record queries (nil means unlimited)
+	pageLimits      *PaginationLimits         // Page size and offset guards (nil means clamp to MaxPageSize)
+	admission       *poolAdmission            // Priority admission control (nil when MaxOpen is unset)
+	indexAdvisor    *indexAdvisor             // Slow query collector for index suggestions (nil means disabled)
+	serverVersion   int                       // Server major version for pagination rewrite (0 means unknown)
+	versionOnce     sync.Once                 // Guards serverVersion detection
+	admissionOnce   sync.Once                 // Lazily creates admission
+	maxPacket       int64                     // MySQL max_allowed_packet (0 means unknown)
+	packetOnce      sync.Once                 // Guards maxPacket detection
+	writeGen        uint64                    // Incremented on every successful write, invalidates query memos
+	txHooks         sync.Map                  // *sql.Tx -> *txHooks for transactions begun by eorm
+	stmtCacheTTL    time.Duration             // 已废弃：保留用于向后兼容
+	stmtCache       atomic.Pointer[stmtCache] // 新的智能语句缓存，SwapDatabase 时整体替换
 	// Feature flags
 	enableTimestampCheck      bool // Enable auto timestamp check in Update (default: false)
 	enableOptimisticLockCheck bool // Enable optimistic lock check in Update (default: false)
 	enableSoftDeleteCheck     bool // Enable soft delete check in queries (default: false)
+	enableSchemaValidation    bool // Validate Record keys against table schema on write (default: false)
+	enableTypeCoercion        bool // Convert Record values to column types on write (default: false)
+	strictTypeCoercion        bool // Return an error when a value cannot be converted (default: false)
 
 	// 连接监控相关（默认启用）
 	monitor      *ConnectionMonitor // 连接监控器实例
@@ -347,11 +410,11 @@ func OpenDatabaseWithDBName(dbname string, driver DriverType, dsn string, maxOpe
 func OpenDatabaseWithConfig(dbname string, config *Config) (*DB, error) {
 	dbMgr := &dbManager{
 		name:          dbname,
-		config:        config,
 		pkCache:       make(map[string][]string),
 		identityCache: make(map[string]string),
 		columnCache:   make(map[string][]ColumnInfo), // 初始化列信息缓存
 	}
+	dbMgr.config.Store(config)
 
 	if err := dbMgr.initDB(); err != nil {
 		return nil, err
@@ -383,17 +446,17 @@ func OpenDatabaseWithDB(dbname string, driver DriverType, db *sql.DB) (*DB, erro
 
 	dbMgr := &dbManager{
 		name:          dbname,
-		config:        config,
-		db:            db,
 		pkCache:       make(map[string][]string),
 		identityCache: make(map[string]string),
 		columnCache:   make(map[string][]ColumnInfo),
 	}
+	dbMgr.config.Store(config)
+	dbMgr.db.Store(db)
 
 	// 初始化语句缓存，防止空指针
 	cacheConfig := DefaultStmtCacheConfig()
 	cacheConfig.Enabled = false // 默认关闭
-	dbMgr.stmtCache = newStmtCache(cacheConfig)
+	dbMgr.stmtCache.Store(newStmtCache(cacheConfig))
 
 	multiMgr.mu.Lock()
 	multiMgr.databases[dbname] = dbMgr
@@ -435,6 +498,10 @@ func UseWithError(dbname string) (*DB, error) {
 var (
 	// ErrNotInitialized is returned when an operation is performed on an uninitialized database
 	ErrNotInitialized = fmt.Errorf("eorm: database not initialized. Please call eorm.OpenDatabase() before using eorm operations")
+
+	// ErrQuestionEscapeUnsupported is returned when SQL for MySQL or SQLite contains the ?? escape.
+	// Both drivers bind every ? as a parameter, so a literal question mark can only appear inside a string literal
+	ErrQuestionEscapeUnsupported = fmt.Errorf("eorm: ?? literal question mark escape is only supported on PostgreSQL, SQL Server and Oracle; MySQL and SQLite bind every ? as a parameter")
 )
 
 // defaultDB returns the default DB object (first registered database or single database mode)
@@ -449,7 +516,9 @@ func defaultDB() (*DB, error) {
 // --- Internal Helper Methods on dbManager to unify DB and Tx logic ---
 
 func (mgr *dbManager) prepareQuerySQL(querySQL string, args ...interface{}) (string, []interface{}) {
-	driver := mgr.config.Driver
+	driver := mgr.config.Load().Driver
+	querySQL, args = expandSqlExprArgs(querySQL, args)
+	querySQL, args = expandSliceArgs(querySQL, args)
 	lowerSQL := strings.ToLower(querySQL)
 
 	// 处理 Oracle 和 SQL Server 的 LIMIT 语法
@@ -478,33 +547,34 @@ func (mgr *dbManager) prepareQuerySQL(querySQL string, args ...interface{}) (str
 	return querySQL, args
 }
 
-// getOrPrepareStmt 获取或创建预编译语句（内部方法）
+// getOrPrepareStmt 获取或创建预编译语句（内部方法），sdb 为执行语句的连接池
 // 返回值：stmt, fromCache, error
-func (mgr *dbManager) getOrPrepareStmt(sqlStr string) (*sql.Stmt, bool, error) {
+func (mgr *dbManager) getOrPrepareStmt(sdb *sql.DB, sqlStr string) (*sql.Stmt, bool, error) {
 	// 构造缓存键：数据库名称 + SQL 语句
 	cacheKey := mgr.name + ":" + sqlStr
+	cache := mgr.stmtCache.Load()
 
 	// 1. 尝试从新的智能缓存获取
-	if stmt, ok := mgr.stmtCache.Get(cacheKey); ok {
+	if stmt, ok := cache.Get(cacheKey); ok {
 		return stmt, true, nil // 从缓存获取（自动更新访问时间和计数）
 	}
 
 	// 2. 缓存未命中，创建新的预编译语句
-	stmt, err := mgr.db.Prepare(sqlStr)
+	stmt, err := sdb.Prepare(sqlStr)
 	if err != nil {
 		return nil, false, err
 	}
 
 	// 3. 存入智能缓存（自动处理容量限制和 LRU 淘汰）
-	mgr.stmtCache.Set(cacheKey, stmt, sqlStr)
+	cache.Set(cacheKey, stmt, sqlStr)
 
 	return stmt, false, nil // 新创建的
 }
 
 // clearStmtCache 清空预编译语句缓存（内部方法，用于数据库关闭时）
 func (mgr *dbManager) clearStmtCache() {
-	if mgr.stmtCache != nil {
-		mgr.stmtCache.Clear()
+	if cache := mgr.stmtCache.Load(); cache != nil {
+		cache.Clear()
 	}
 }
 
@@ -526,17 +596,50 @@ func (mgr *dbManager) query(executor sqlExecutor, querySQL string, args ...inter
 }
 
 func (mgr *dbManager) queryWithContext(ctx context.Context, executor sqlExecutor, querySQL string, args ...interface{}) ([]*Record, error) {
+	if err := mgr.waitRateLimit(ctx); err != nil {
+		return nil, err
+	}
+	release, admitErr := mgr.admit(ctx, executor)
+	if admitErr != nil {
+		return nil, admitErr
+	}
+	defer release()
+	if err := mgr.checkQuestionEscape(querySQL); err != nil {
+		return nil, err
+	}
 	querySQL, args = mgr.prepareQuerySQL(querySQL, args...)
+	querySQL = mgr.applyQueryCommentHook(ctx, querySQL)
+
+	// 请求级查询备忘录：相同查询只执行一次
+	if memo, key := mgr.queryMemoFor(ctx, executor, "records", querySQL, args); memo != nil {
+		entry, hit, err := memo.acquire(ctx, mgr, key)
+		if err != nil {
+			return nil, err
+		}
+		if !hit {
+			entry.records, err = mgr.queryRecordsDirect(ctx, executor, querySQL, args)
+			memo.complete(key, entry, err)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return cloneMemoRecords(entry.records), nil
+	}
+	return mgr.queryRecordsDirect(ctx, executor, querySQL, args)
+}
+
+// queryRecordsDirect 执行已处理好的查询并扫描为 Record
+func (mgr *dbManager) queryRecordsDirect(ctx context.Context, executor sqlExecutor, querySQL string, args []interface{}) ([]*Record, error) {
 	start := time.Now()
 
 	var rows *sql.Rows
 	var err error
 
-	// 只有当 executor 是 *sql.DB 时才使用预编译语句缓存
+	// 只有当 executor 是连接池（*sql.DB 或串行写执行器）时才使用预编译语句缓存
 	// 事务（*sql.Tx）不使用缓存，因为事务有自己的生命周期
-	if db, ok := executor.(*sql.DB); ok && db == mgr.db {
+	if db := poolDB(executor); db != nil && db == mgr.db.Load() {
 		// 使用缓存的预编译语句
-		stmt, fromCache, stmtErr := mgr.getOrPrepareStmt(querySQL)
+		stmt, fromCache, stmtErr := mgr.getOrPrepareStmt(db, querySQL)
 		if stmtErr != nil {
 			mgr.logTrace(start, querySQL, args, stmtErr)
 			return nil, stmtErr
@@ -550,7 +653,7 @@ func (mgr *dbManager) queryWithContext(ctx context.Context, executor sqlExecutor
 			// 新创建的语句出错，不需要特殊处理
 		} else if err != nil && isStmtInvalidError(err) {
 			cacheKey := mgr.name + ":" + querySQL
-			mgr.stmtCache.Delete(cacheKey) // 使用新的智能缓存删除
+			mgr.stmtCache.Load().Delete(cacheKey) // 使用新的智能缓存删除
 		}
 	} else {
 		// 事务或其他 executor，使用原有逻辑
@@ -568,7 +671,7 @@ func (mgr *dbManager) queryWithContext(ctx context.Context, executor sqlExecutor
 	}
 	defer rows.Close()
 
-	results, err := scanRecords(rows, mgr.config.Driver)
+	results, err := mgr.scanRecordsLimited(ctx, rows, querySQL)
 	if err != nil {
 		return nil, err
 	}
@@ -604,16 +707,49 @@ func (mgr *dbManager) queryMap(executor sqlExecutor, querySQL string, args ...in
 }
 
 func (mgr *dbManager) queryMapWithContext(ctx context.Context, executor sqlExecutor, querySQL string, args ...interface{}) ([]map[string]interface{}, error) {
+	if err := mgr.waitRateLimit(ctx); err != nil {
+		return nil, err
+	}
+	release, admitErr := mgr.admit(ctx, executor)
+	if admitErr != nil {
+		return nil, admitErr
+	}
+	defer release()
+	if err := mgr.checkQuestionEscape(querySQL); err != nil {
+		return nil, err
+	}
 	querySQL, args = mgr.prepareQuerySQL(querySQL, args...)
+	querySQL = mgr.applyQueryCommentHook(ctx, querySQL)
+
+	// 请求级查询备忘录：相同查询只执行一次
+	if memo, key := mgr.queryMemoFor(ctx, executor, "maps", querySQL, args); memo != nil {
+		entry, hit, err := memo.acquire(ctx, mgr, key)
+		if err != nil {
+			return nil, err
+		}
+		if !hit {
+			entry.maps, err = mgr.queryMapsDirect(ctx, executor, querySQL, args)
+			memo.complete(key, entry, err)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return cloneMemoMaps(entry.maps), nil
+	}
+	return mgr.queryMapsDirect(ctx, executor, querySQL, args)
+}
+
+// queryMapsDirect 执行已处理好的查询并扫描为 map
+func (mgr *dbManager) queryMapsDirect(ctx context.Context, executor sqlExecutor, querySQL string, args []interface{}) ([]map[string]interface{}, error) {
 	start := time.Now()
 
 	var rows *sql.Rows
 	var err error
 
-	// 只有当 executor 是 *sql.DB 时才使用预编译语句缓存
-	if db, ok := executor.(*sql.DB); ok && db == mgr.db {
+	// 只有当 executor 是连接池（*sql.DB 或串行写执行器）时才使用预编译语句缓存
+	if db := poolDB(executor); db != nil && db == mgr.db.Load() {
 		// 使用缓存的预编译语句
-		stmt, fromCache, stmtErr := mgr.getOrPrepareStmt(querySQL)
+		stmt, fromCache, stmtErr := mgr.getOrPrepareStmt(db, querySQL)
 		if stmtErr != nil {
 			mgr.logTrace(start, querySQL, args, stmtErr)
 			return nil, stmtErr
@@ -627,7 +763,7 @@ func (mgr *dbManager) queryMapWithContext(ctx context.Context, executor sqlExecu
 			// 新创建的语句出错，不需要特殊处理
 		} else if err != nil && isStmtInvalidError(err) {
 			cacheKey := mgr.name + ":" + querySQL
-			mgr.stmtCache.Delete(cacheKey)
+			mgr.stmtCache.Load().Delete(cacheKey)
 		}
 	} else {
 		// 事务或其他 executor，使用原有逻辑
@@ -645,7 +781,7 @@ func (mgr *dbManager) queryMapWithContext(ctx context.Context, executor sqlExecu
 	}
 	defer rows.Close()
 
-	results, err := scanMaps(rows, mgr.config.Driver)
+	results, err := scanMaps(rows, mgr.config.Load().Driver)
 	if err != nil {
 		return nil, err
 	}
@@ -653,7 +789,7 @@ func (mgr *dbManager) queryMapWithContext(ctx context.Context, executor sqlExecu
 }
 
 func (mgr *dbManager) addLimitOne(querySQL string) string {
-	driver := mgr.config.Driver
+	driver := mgr.config.Load().Driver
 	lowerSQL := strings.ToLower(strings.TrimSpace(querySQL))
 
 	// Check if already has limit
@@ -689,32 +825,53 @@ func (mgr *dbManager) exec(executor sqlExecutor, querySQL string, args ...interf
 	return mgr.execWithContext(context.Background(), executor, querySQL, args...)
 }
 
-func (mgr *dbManager) execWithContext(ctx context.Context, executor sqlExecutor, querySQL string, args ...interface{}) (sql.Result, error) {
-	querySQL = mgr.convertPlaceholder(querySQL, mgr.config.Driver)
+// prepareExecSQL 展开表达式和切片参数、转换占位符并清理参数，得到最终执行的写语句
+func (mgr *dbManager) prepareExecSQL(ctx context.Context, querySQL string, args []interface{}) (string, []interface{}) {
+	querySQL, args = expandSqlExprArgs(querySQL, args)
+	querySQL, args = expandSliceArgs(querySQL, args)
+	querySQL = mgr.convertPlaceholder(querySQL, mgr.config.Load().Driver)
 	args = mgr.sanitizeArgs(querySQL, args)
+	return mgr.applyQueryCommentHook(ctx, querySQL), args
+}
+
+func (mgr *dbManager) execWithContext(ctx context.Context, executor sqlExecutor, querySQL string, args ...interface{}) (sql.Result, error) {
+	if err := mgr.waitRateLimit(ctx); err != nil {
+		return nil, err
+	}
+	release, admitErr := mgr.admit(ctx, executor)
+	if admitErr != nil {
+		return nil, admitErr
+	}
+	defer release()
+	if err := mgr.checkQuestionEscape(querySQL); err != nil {
+		return nil, err
+	}
+	querySQL, args = mgr.prepareExecSQL(ctx, querySQL, args)
 	start := time.Now()
 
 	var result sql.Result
 	var err error
 
-	// 只有当 executor 是 *sql.DB 时才使用预编译语句缓存
-	if db, ok := executor.(*sql.DB); ok && db == mgr.db {
+	// 只有当 executor 是连接池（*sql.DB 或串行写执行器）时才使用预编译语句缓存
+	if db := poolDB(executor); db != nil && db == mgr.db.Load() {
 		// 使用缓存的预编译语句
-		stmt, fromCache, stmtErr := mgr.getOrPrepareStmt(querySQL)
+		stmt, fromCache, stmtErr := mgr.getOrPrepareStmt(db, querySQL)
 		if stmtErr != nil {
 			mgr.logTrace(start, querySQL, args, stmtErr)
 			return nil, stmtErr
 		}
 
-		// 执行命令（使用 context）
+		// 执行命令（使用 context），SQLiteSerializeWrites 时在写互斥锁内执行
+		unlock := lockWrites(executor)
 		result, err = stmt.ExecContext(ctx, args...)
+		unlock()
 
 		// 如果执行失败且可能是语句失效，从缓存移除
 		if err != nil && !fromCache {
 			// 新创建的语句出错，不需要特殊处理
 		} else if err != nil && isStmtInvalidError(err) {
 			cacheKey := mgr.name + ":" + querySQL
-			mgr.stmtCache.Delete(cacheKey)
+			mgr.stmtCache.Load().Delete(cacheKey)
 		}
 	} else {
 		// 事务或其他 executor，使用原有逻辑
@@ -726,6 +883,8 @@ func (mgr *dbManager) execWithContext(ctx context.Context, executor sqlExecutor,
 	}
 
 	mgr.logTrace(start, querySQL, args, err)
+	mgr.afterSQLWrite(executor, querySQL, err)
+	mgr.afterSQLDDL(querySQL, err)
 
 	if err != nil {
 		return nil, err
@@ -733,17 +892,47 @@ func (mgr *dbManager) execWithContext(ctx context.Context, executor sqlExecutor,
 	return result, nil
 }
 
+// execWrite 执行 Record 写操作（Insert/Update/Delete/Batch* 等）生成的语句，与 Exec 一样先咨询限流器并获取连接池准入许可
+func (mgr *dbManager) execWrite(ctx context.Context, executor sqlExecutor, querySQL string, args ...interface{}) (sql.Result, error) {
+	release, err := mgr.beginWrite(ctx, executor)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return executor.Exec(querySQL, args...)
+}
+
+// execWriteStmt 与 execWrite 相同，但使用在 executor 上准备好的预处理语句执行
+func (mgr *dbManager) execWriteStmt(ctx context.Context, executor sqlExecutor, stmt *sql.Stmt, args ...interface{}) (sql.Result, error) {
+	release, err := mgr.beginWrite(ctx, executor)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return stmt.Exec(args...)
+}
+
+// beginWrite 等待限流器和准入许可，返回释放许可的函数
+func (mgr *dbManager) beginWrite(ctx context.Context, executor sqlExecutor) (func(), error) {
+	if err := mgr.waitRateLimit(ctx); err != nil {
+		return nil, err
+	}
+	return mgr.admit(ctx, executor)
+}
+
 // batchExecWithContext 批量执行多个 SQL 语句的核心实现（带 context）
 // ctx: 上下文，用于超时控制
 // executor: SQL 执行器（*sql.DB 或 *sql.Tx）
 // sqls: SQL 语句列表
 // args: 每个 SQL 语句对应的参数列表（可以为 nil）
+// continueOnError: 事务模式下每条语句包裹在保存点中，失败时回滚到保存点并继续执行
 // 返回: 每个语句的执行结果列表和错误
 func (mgr *dbManager) batchExecWithContext(
 	ctx context.Context,
 	executor sqlExecutor,
 	sqls []string,
 	args [][]interface{},
+	continueOnError bool,
 ) ([]StatementResult, error) {
 	// 1. 参数验证
 	if len(sqls) == 0 {
@@ -765,6 +954,7 @@ func (mgr *dbManager) batchExecWithContext(
 	// 3. 记录批量执行开始
 	batchStart := time.Now()
 	mgr.logTrace(batchStart, fmt.Sprintf("BatchExec: starting %d statements (transaction mode: %v)", len(sqls), isInTransaction), nil, nil)
+	useSavepoint := isInTransaction && continueOnError
 
 	// 4. 初始化结果列表
 	statementResults := make([]StatementResult, 0, len(sqls))
@@ -784,6 +974,16 @@ func (mgr *dbManager) batchExecWithContext(
 			sqlArgs = args[i]
 		}
 
+		// 保存点模式：每条语句执行前创建保存点，保存点本身失败时无法安全继续
+		savepoint := fmt.Sprintf("eorm_batch_%d", i)
+		if useSavepoint {
+			if spErr := mgr.execSavepoint(ctx, executor, savepointCreate, savepoint); spErr != nil {
+				statementResults = append(statementResults, StatementResult{Index: i, SQL: sqlStr, Args: sqlArgs, Error: spErr})
+				failedCount++
+				break
+			}
+		}
+
 		// 记录单个语句执行开始
 		stmtStart := time.Now()
 
@@ -804,6 +1004,15 @@ func (mgr *dbManager) batchExecWithContext(
 			})
 			failedCount++
 
+			// 保存点模式：回滚失败语句的修改后继续执行
+			if useSavepoint {
+				if spErr := mgr.execSavepoint(ctx, executor, savepointRollback, savepoint); spErr != nil {
+					mgr.logTrace(batchStart, fmt.Sprintf("BatchExec: stopped at statement %d, rollback to savepoint failed", i), nil, spErr)
+					break
+				}
+				continue
+			}
+
 			// 事务模式：遇到错误立即停止
 			if isInTransaction {
 				mgr.logTrace(batchStart, fmt.Sprintf("BatchExec: stopped at statement %d due to error (transaction mode)", i), nil, err)
@@ -814,6 +1023,14 @@ func (mgr *dbManager) batchExecWithContext(
 			continue
 		}
 
+		if useSavepoint {
+			if spErr := mgr.execSavepoint(ctx, executor, savepointRelease, savepoint); spErr != nil {
+				statementResults = append(statementResults, StatementResult{Index: i, SQL: sqlStr, Args: sqlArgs, Result: result, Error: spErr})
+				failedCount++
+				break
+			}
+		}
+
 		// 记录成功结果（也包含 SQL 和参数，方便查看）
 		statementResults = append(statementResults, StatementResult{
 			Index:  i,
@@ -849,7 +1066,7 @@ func (mgr *dbManager) batchExec(
 	sqls []string,
 	args [][]interface{},
 ) ([]StatementResult, error) {
-	return mgr.batchExecWithContext(context.Background(), executor, sqls, args)
+	return mgr.batchExecWithContext(context.Background(), executor, sqls, args, false)
 }
 
 func (mgr *dbManager) getIdentityColumn(executor sqlExecutor, table string) string {
@@ -990,7 +1207,8 @@ func (mgr *dbManager) hasIdentityAlways(table string, insertCols []string) bool
 	return false
 }
 
-func (mgr *dbManager) saveRecord(executor sqlExecutor, table string, record *Record) (int64, error) {
+func (mgr *dbManager) saveRecord(ctx context.Context, executor sqlExecutor, table string, record *Record) (_ int64, err error) {
+	defer func() { mgr.afterRecordsWrite(executor, table, []*Record{record}, err) }()
 	if err := validateIdentifier(table); err != nil {
 		return 0, err
 	}
@@ -1001,7 +1219,7 @@ func (mgr *dbManager) saveRecord(executor sqlExecutor, table string, record *Rec
 	pks, _ := mgr.getPrimaryKeys(executor, table)
 	if len(pks) == 0 {
 		// 没有主键，直接执行插入
-		return mgr.insertRecord(executor, table, record)
+		return mgr.insertRecord(ctx, executor, table, record)
 	}
 
 	// 检查 Record 中是否包含所有主键字段
@@ -1059,16 +1277,17 @@ func (mgr *dbManager) saveRecord(executor sqlExecutor, table string, record *Rec
 					}
 				}
 				if len(updateRecord.columns) > 0 {
-					return mgr.update(executor, table, updateRecord, where, pkValues...)
+					return mgr.update(ctx, executor, table, updateRecord, where, pkValues...)
 				}
 				return 0, nil
 			}
 		}
 
 		// 如果是 MySQL, PostgreSQL, SQLite, Oracle, SQLServer，使用原生的 Upsert 语法
-		driver := mgr.config.Driver
-		if driver == MySQL || driver == PostgreSQL || driver == SQLite3 || driver == Oracle || driver == SQLServer {
-			return mgr.nativeUpsert(executor, table, record, pks)
+		// 原生 Upsert 无法区分插入和更新，表的写操作有依赖这一区分的副作用时改为先查询再更新或插入
+		driver := mgr.config.Load().Driver
+		if (driver == MySQL || driver == PostgreSQL || driver == SQLite3 || driver == Oracle || driver == SQLServer) && !mgr.upsertNeedsLookup(table) {
+			return mgr.nativeUpsert(ctx, executor, table, record, pks)
 		}
 
 		// 所有主键字段都存在，检查记录是否存在
@@ -1093,14 +1312,23 @@ func (mgr *dbManager) saveRecord(executor sqlExecutor, table string, record *Rec
 			}
 			// 如果除了主键还有其他字段，则执行更新
 			if len(updateRecord.columns) > 0 {
-				return mgr.update(executor, table, updateRecord, where, pkValues...)
+				return mgr.update(ctx, executor, table, updateRecord, where, pkValues...)
 			}
 			return 0, nil // 只有主键且已存在，无需更新
 		}
 	}
 
 	// 记录不存在或不包含完整主键，执行插入
-	return mgr.insertRecord(executor, table, record)
+	return mgr.insertRecord(ctx, executor, table, record)
+}
+
+// upsertNeedsLookup 判断 SaveRecord 是否需要先查询记录是否存在，而不是使用原生 Upsert
+// 表有变更订阅者时需要知道本次是插入还是更新，以发布对应的变更事件；
+// 表有计数缓存时只有插入才需要增加父表计数；表有插入默认值时默认值只能用于插入，不能覆盖已有行；
+// 表配置了 ConfigActiveUnique 时插入新行前需要检查未删除记录的唯一性
+func (mgr *dbManager) upsertNeedsLookup(table string) bool {
+	return mgr.hasChangeSubscribers(table) || len(mgr.getCounterCacheConfigs(table)) > 0 ||
+		mgr.hasColumnDefaults(table) || mgr.hasActiveUniques(table)
 }
 
 // getOrderedColumns 返回 Record 中的列名和对应的值，根据数据库类型决定是否排除自增列
@@ -1138,7 +1366,7 @@ func (mgr *dbManager) getOrderedColumns(record *Record, table string, executor s
 		// 这两个数据库不允许更新 IDENTITY 列
 		// MySQL/PostgreSQL/SQLite 允许更新自增列（虽然不推荐，但不会报错）
 		if identityCol != "" && strings.EqualFold(col, identityCol) {
-			driver := mgr.config.Driver
+			driver := mgr.config.Load().Driver
 			if driver == SQLServer || driver == Oracle {
 				continue // 跳过自增列
 			}
@@ -1204,12 +1432,12 @@ func (mgr *dbManager) getOrderedColumnsForInsert(record *Record, table string, e
 	return columns, values
 }
 
-func (mgr *dbManager) nativeUpsert(executor sqlExecutor, table string, record *Record, pks []string) (int64, error) {
-	driver := mgr.config.Driver
+func (mgr *dbManager) nativeUpsert(ctx context.Context, executor sqlExecutor, table string, record *Record, pks []string) (int64, error) {
+	driver := mgr.config.Load().Driver
 
 	// 如果是 Oracle 或 SQL Server，使用 MERGE 语句
 	if driver == Oracle || driver == SQLServer {
-		return mgr.mergeUpsert(executor, table, record, pks)
+		return mgr.mergeUpsert(ctx, executor, table, record, pks)
 	}
 
 	// Apply created_at timestamp for INSERT part of upsert
@@ -1298,6 +1526,7 @@ func (mgr *dbManager) nativeUpsert(executor sqlExecutor, table string, record *R
 		}
 	}
 
+	sqlStr, values = expandSqlExprArgs(sqlStr, values)
 	sqlStr = mgr.convertPlaceholder(sqlStr, driver)
 	values = mgr.sanitizeArgs(sqlStr, values)
 
@@ -1318,7 +1547,7 @@ func (mgr *dbManager) nativeUpsert(executor sqlExecutor, table string, record *R
 	}
 
 	start := time.Now()
-	res, err := executor.Exec(sqlStr, values...)
+	res, err := mgr.execWrite(ctx, executor, sqlStr, values...)
 	mgr.logTrace(start, sqlStr, values, err)
 	if err != nil {
 		return 0, err
@@ -1344,8 +1573,8 @@ func (mgr *dbManager) nativeUpsert(executor sqlExecutor, table string, record *R
 	return res.RowsAffected()
 }
 
-func (mgr *dbManager) mergeUpsert(executor sqlExecutor, table string, record *Record, pks []string) (int64, error) {
-	driver := mgr.config.Driver
+func (mgr *dbManager) mergeUpsert(ctx context.Context, executor sqlExecutor, table string, record *Record, pks []string) (int64, error) {
+	driver := mgr.config.Load().Driver
 
 	// Apply created_at timestamp for INSERT part of merge
 	// 为 merge 的 INSERT 部分应用 created_at 时间戳
@@ -1456,7 +1685,7 @@ func (mgr *dbManager) mergeUpsert(executor sqlExecutor, table string, record *Re
 	// 对于 SQL Server，如果我们需要获取生成的 ID，可以使用 OUTPUT 子句
 	// 但这会改变执行方式（从 Exec 变为 QueryRow），为了保持简单，我们先解决报错问题
 	start := time.Now()
-	res, err := executor.Exec(sqlStr, values...)
+	res, err := mgr.execWrite(ctx, executor, sqlStr, values...)
 	mgr.logTrace(start, sqlStr, values, err)
 	if err != nil {
 		return 0, err
@@ -1484,17 +1713,43 @@ func isTimeValue(v interface{}) bool {
 	return false
 }
 
-func (mgr *dbManager) insertRecord(executor sqlExecutor, table string, record *Record) (int64, error) {
-	return mgr.insertRecordWithOptions(executor, table, record, false)
+func (mgr *dbManager) insertRecord(ctx context.Context, executor sqlExecutor, table string, record *Record) (int64, error) {
+	return mgr.insertRecordWithOptions(ctx, executor, table, record, false)
 }
 
-func (mgr *dbManager) insertRecordWithOptions(executor sqlExecutor, table string, record *Record, skipTimestamps bool) (int64, error) {
+func (mgr *dbManager) insertRecordWithOptions(ctx context.Context, executor sqlExecutor, table string, record *Record, skipTimestamps bool) (id int64, err error) {
+	defer func() { mgr.afterRecordsWrite(executor, table, nil, err) }()
+	if id, handled, txErr := withCounterCacheTx(mgr, executor, table, func(tx sqlExecutor) (int64, error) {
+		return mgr.insertRecordWithOptions(ctx, tx, table, record, skipTimestamps)
+	}); handled {
+		return id, txErr
+	}
+	defer func() {
+		if err == nil {
+			mgr.publishInserts(executor, table, []*Record{record}, id)
+		}
+	}()
+	defer func() {
+		if err == nil {
+			err = mgr.incrementCounterCaches(executor, table, []*Record{record})
+		}
+	}()
 	if err := validateIdentifier(table); err != nil {
 		return 0, err
 	}
 	if record == nil || len(record.columns) == 0 {
 		return 0, fmt.Errorf("record is empty")
 	}
+	mgr.applyColumnDefaults(table, record)
+	if err := mgr.validateRecordColumns(table, record); err != nil {
+		return 0, err
+	}
+	if err := mgr.checkActiveUnique(executor, table, record); err != nil {
+		return 0, err
+	}
+	if err := mgr.coerceRecordTypes(table, record); err != nil {
+		return 0, err
+	}
 
 	// Apply created_at timestamp
 	mgr.applyCreatedAtTimestamp(table, record, skipTimestamps)
@@ -1502,7 +1757,12 @@ func (mgr *dbManager) insertRecordWithOptions(executor sqlExecutor, table string
 	// Apply version initialization for optimistic lock
 	mgr.applyVersionInit(table, record)
 
-	driver := mgr.config.Driver
+	// Fill primary key from the configured sequence
+	if err := mgr.applySequence(executor, table, record); err != nil {
+		return 0, err
+	}
+
+	driver := mgr.config.Load().Driver
 	columns, values := mgr.getOrderedColumnsForInsert(record, table, executor)
 	var placeholders []string
 
@@ -1520,6 +1780,8 @@ func (mgr *dbManager) insertRecordWithOptions(executor sqlExecutor, table string
 			placeholders = append(placeholders, "?")
 		}
 	}
+	// eorm.Expr 值直接写入 SQL
+	placeholders, values = expandSqlExprPlaceholders(placeholders, values)
 
 	querySQL := fmt.Sprintf("INSERT INTO %s (%s)", table, joinStrings(columns))
 
@@ -1544,7 +1806,7 @@ func (mgr *dbManager) insertRecordWithOptions(executor sqlExecutor, table string
 		querySQL = mgr.convertPlaceholder(querySQL, driver)
 		values = mgr.sanitizeArgs(querySQL, values)
 		start := time.Now()
-		res, err := executor.Exec(querySQL, values...)
+		res, err := mgr.execWrite(ctx, executor, querySQL, values...)
 		mgr.logTrace(start, querySQL, values, err)
 		if err != nil {
 			return 0, err
@@ -1579,7 +1841,7 @@ func (mgr *dbManager) insertRecordWithOptions(executor sqlExecutor, table string
 		querySQL = mgr.convertPlaceholder(querySQL, driver)
 		values = mgr.sanitizeArgs(querySQL, values)
 		start := time.Now()
-		res, err := executor.Exec(querySQL, values...)
+		res, err := mgr.execWrite(ctx, executor, querySQL, values...)
 		mgr.logTrace(start, querySQL, values, err)
 		if err != nil {
 			return 0, err
@@ -1601,7 +1863,7 @@ func (mgr *dbManager) insertRecordWithOptions(executor sqlExecutor, table string
 			querySQL = mgr.convertPlaceholder(querySQL, driver)
 			values = mgr.sanitizeArgs(querySQL, values)
 			start := time.Now()
-			_, err := executor.Exec(querySQL, values...)
+			_, err := mgr.execWrite(ctx, executor, querySQL, values...)
 			mgr.logTrace(start, querySQL, values, err)
 			if err != nil {
 				return 0, err
@@ -1618,7 +1880,7 @@ func (mgr *dbManager) insertRecordWithOptions(executor sqlExecutor, table string
 
 			var lastID int64
 			argsWithOut := append(valuesForReturning, sql.Out{Dest: &lastID})
-			_, err := executor.Exec(returningSql, argsWithOut...)
+			_, err := mgr.execWrite(ctx, executor, returningSql, argsWithOut...)
 			mgr.logTrace(start, returningSql, valuesForReturning, err)
 			if err == nil {
 				return lastID, nil
@@ -1630,7 +1892,7 @@ func (mgr *dbManager) insertRecordWithOptions(executor sqlExecutor, table string
 		querySQL = mgr.convertPlaceholder(querySQL, driver)
 		values = mgr.sanitizeArgs(querySQL, values)
 		start := time.Now()
-		res, err := executor.Exec(querySQL, values...)
+		res, err := mgr.execWrite(ctx, executor, querySQL, values...)
 		mgr.logTrace(start, querySQL, values, err)
 		if err != nil {
 			return 0, err
@@ -1642,7 +1904,7 @@ func (mgr *dbManager) insertRecordWithOptions(executor sqlExecutor, table string
 	querySQL = mgr.convertPlaceholder(querySQL, driver)
 	values = mgr.sanitizeArgs(querySQL, values)
 	start := time.Now()
-	result, err := executor.Exec(querySQL, values...)
+	result, err := mgr.execWrite(ctx, executor, querySQL, values...)
 	mgr.logTrace(start, querySQL, values, err)
 	if err != nil {
 		return 0, err
@@ -1650,29 +1912,53 @@ func (mgr *dbManager) insertRecordWithOptions(executor sqlExecutor, table string
 	return result.LastInsertId()
 }
 
-func (mgr *dbManager) update(executor sqlExecutor, table string, record *Record, where string, whereArgs ...interface{}) (int64, error) {
+func (mgr *dbManager) update(ctx context.Context, executor sqlExecutor, table string, record *Record, where string, whereArgs ...interface{}) (int64, error) {
 	// If both feature checks are disabled, use fast path
 	if !mgr.enableTimestampCheck && !mgr.enableOptimisticLockCheck {
-		return mgr.updateRecordFast(executor, table, record, where, whereArgs...)
+		return mgr.updateRecordFast(ctx, executor, table, record, where, whereArgs...)
 	}
 	// Feature checks enabled, use full path
-	return mgr.updateRecordWithOptions(executor, table, record, where, false, whereArgs...)
+	return mgr.updateRecordWithOptions(ctx, executor, table, record, where, false, whereArgs...)
 }
 
 // updateFast is a lightweight update that skips timestamp and optimistic lock checks for better performance
-func (mgr *dbManager) updateRecordFast(executor sqlExecutor, table string, record *Record, where string, whereArgs ...interface{}) (int64, error) {
+func (mgr *dbManager) updateRecordFast(ctx context.Context, executor sqlExecutor, table string, record *Record, where string, whereArgs ...interface{}) (_ int64, err error) {
+	defer func() { mgr.afterKeyedWrite(executor, table, where, whereArgs, err) }()
 	if err := validateIdentifier(table); err != nil {
 		return 0, err
 	}
 	if record == nil || len(record.columns) == 0 {
 		return 0, fmt.Errorf("record is empty")
 	}
+	if err := mgr.validateRecordColumns(table, record); err != nil {
+		return 0, err
+	}
+	if err := mgr.coerceRecordTypes(table, record); err != nil {
+		return 0, err
+	}
+	if n, handled, txErr := withHistoryTx(mgr, executor, table, func(tx sqlExecutor) (int64, error) {
+		return mgr.updateRecordFast(ctx, tx, table, record, where, whereArgs...)
+	}); handled {
+		return n, txErr
+	}
+	if err := mgr.archiveHistory(executor, table, where, whereArgs); err != nil {
+		return 0, err
+	}
+	changes, err := mgr.captureChanges(executor, table, ChangeUpdate, record, where, whereArgs)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err == nil {
+			mgr.publishChanges(executor, changes)
+		}
+	}()
 
 	columns, values := mgr.getOrderedColumns(record, table, executor)
 	var setClauses []string
 
 	// Oracle: 为日期类型字段使用 TO_DATE 函数
-	if mgr.config.Driver == Oracle {
+	if mgr.config.Load().Driver == Oracle {
 		for i, col := range columns {
 			if isTimeValue(values[i]) {
 				setClauses = append(setClauses, fmt.Sprintf("%s = TO_DATE(?, 'YYYY-MM-DD HH24:MI:SS')", col))
@@ -1695,10 +1981,11 @@ func (mgr *dbManager) updateRecordFast(executor sqlExecutor, table string, recor
 		querySQL = fmt.Sprintf("UPDATE %s SET %s", table, joinStrings(setClauses))
 	}
 
-	querySQL = mgr.convertPlaceholder(querySQL, mgr.config.Driver)
+	querySQL, values = expandSqlExprArgs(querySQL, values)
+	querySQL = mgr.convertPlaceholder(querySQL, mgr.config.Load().Driver)
 	values = mgr.sanitizeArgs(querySQL, values)
 	start := time.Now()
-	result, err := executor.Exec(querySQL, values...)
+	result, err := mgr.execWrite(ctx, executor, querySQL, values...)
 	mgr.logTrace(start, querySQL, values, err)
 	if err != nil {
 		return 0, err
@@ -1707,13 +1994,37 @@ func (mgr *dbManager) updateRecordFast(executor sqlExecutor, table string, recor
 	return result.RowsAffected()
 }
 
-func (mgr *dbManager) updateRecordWithOptions(executor sqlExecutor, table string, record *Record, where string, skipTimestamps bool, whereArgs ...interface{}) (int64, error) {
+func (mgr *dbManager) updateRecordWithOptions(ctx context.Context, executor sqlExecutor, table string, record *Record, where string, skipTimestamps bool, whereArgs ...interface{}) (_ int64, err error) {
+	defer func() { mgr.afterKeyedWrite(executor, table, where, whereArgs, err) }()
 	if err := validateIdentifier(table); err != nil {
 		return 0, err
 	}
 	if record == nil || len(record.columns) == 0 {
 		return 0, fmt.Errorf("record is empty")
 	}
+	if err := mgr.validateRecordColumns(table, record); err != nil {
+		return 0, err
+	}
+	if err := mgr.coerceRecordTypes(table, record); err != nil {
+		return 0, err
+	}
+	if n, handled, txErr := withHistoryTx(mgr, executor, table, func(tx sqlExecutor) (int64, error) {
+		return mgr.updateRecordWithOptions(ctx, tx, table, record, where, skipTimestamps, whereArgs...)
+	}); handled {
+		return n, txErr
+	}
+	if err := mgr.archiveHistory(executor, table, where, whereArgs); err != nil {
+		return 0, err
+	}
+	changes, err := mgr.captureChanges(executor, table, ChangeUpdate, record, where, whereArgs)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err == nil {
+			mgr.publishChanges(executor, changes)
+		}
+	}()
 
 	// Apply updated_at timestamp (only if feature is enabled)
 	if mgr.enableTimestampCheck {
@@ -1741,7 +2052,7 @@ func (mgr *dbManager) updateRecordWithOptions(executor sqlExecutor, table string
 	var setClauses []string
 
 	// Oracle: 为日期类型字段使用 TO_DATE 函数
-	if mgr.config.Driver == Oracle {
+	if mgr.config.Load().Driver == Oracle {
 		for i, col := range columns {
 			colName := strings.ToUpper(col)
 			if isTimeValue(values[i]) {
@@ -1754,7 +2065,7 @@ func (mgr *dbManager) updateRecordWithOptions(executor sqlExecutor, table string
 		for _, col := range columns {
 			// 根据数据库类型转换字段名大小写
 			colName := col
-			if mgr.config.Driver == Oracle {
+			if mgr.config.Load().Driver == Oracle {
 				colName = strings.ToUpper(col)
 			}
 			setClauses = append(setClauses, fmt.Sprintf("%s = ?", colName))
@@ -1765,7 +2076,7 @@ func (mgr *dbManager) updateRecordWithOptions(executor sqlExecutor, table string
 	if versionChecked && config != nil {
 		// 根据数据库类型转换字段名大小写
 		versionFieldName := config.VersionField
-		if mgr.config.Driver == Oracle {
+		if mgr.config.Load().Driver == Oracle {
 			versionFieldName = strings.ToUpper(versionFieldName)
 		}
 		setClauses = append(setClauses, fmt.Sprintf("%s = ?", versionFieldName))
@@ -1776,7 +2087,7 @@ func (mgr *dbManager) updateRecordWithOptions(executor sqlExecutor, table string
 	if versionChecked && config != nil {
 		// 根据数据库类型转换字段名大小写
 		versionFieldName := config.VersionField
-		if mgr.config.Driver == Oracle {
+		if mgr.config.Load().Driver == Oracle {
 			versionFieldName = strings.ToUpper(versionFieldName)
 		}
 		if where != "" {
@@ -1796,10 +2107,11 @@ func (mgr *dbManager) updateRecordWithOptions(executor sqlExecutor, table string
 		querySQL = fmt.Sprintf("UPDATE %s SET %s", table, joinStrings(setClauses))
 	}
 
-	querySQL = mgr.convertPlaceholder(querySQL, mgr.config.Driver)
+	querySQL, values = expandSqlExprArgs(querySQL, values)
+	querySQL = mgr.convertPlaceholder(querySQL, mgr.config.Load().Driver)
 	values = mgr.sanitizeArgs(querySQL, values)
 	start := time.Now()
-	result, err := executor.Exec(querySQL, values...)
+	result, err := mgr.execWrite(ctx, executor, querySQL, values...)
 	mgr.logTrace(start, querySQL, values, err)
 	if err != nil {
 		return 0, err
@@ -1818,7 +2130,8 @@ func (mgr *dbManager) updateRecordWithOptions(executor sqlExecutor, table string
 	return rowsAffected, nil
 }
 
-func (mgr *dbManager) delete(executor sqlExecutor, table string, where string, whereArgs ...interface{}) (int64, error) {
+func (mgr *dbManager) delete(ctx context.Context, executor sqlExecutor, table string, where string, whereArgs ...interface{}) (_ int64, err error) {
+	defer func() { mgr.afterKeyedWrite(executor, table, where, whereArgs, err) }()
 	if err := validateIdentifier(table); err != nil {
 		return 0, err
 	}
@@ -1826,26 +2139,58 @@ func (mgr *dbManager) delete(executor sqlExecutor, table string, where string, w
 		return 0, fmt.Errorf("where condition is required for delete")
 	}
 
+	if n, handled, txErr := withHistoryTx(mgr, executor, table, func(tx sqlExecutor) (int64, error) {
+		return mgr.delete(ctx, tx, table, where, whereArgs...)
+	}); handled {
+		return n, txErr
+	}
+	if err := mgr.archiveHistory(executor, table, where, whereArgs); err != nil {
+		return 0, err
+	}
+	changes, err := mgr.captureChanges(executor, table, ChangeDelete, nil, where, whereArgs)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err == nil {
+			mgr.publishChanges(executor, changes)
+		}
+	}()
+
 	// Check if soft delete is configured for this table
 	if mgr.hasSoftDelete(table) {
-		return mgr.softDelete(executor, table, where, whereArgs...)
+		return mgr.softDelete(ctx, executor, table, where, whereArgs...)
+	}
+
+	if n, handled, txErr := withCounterCacheTx(mgr, executor, table, func(tx sqlExecutor) (int64, error) {
+		return mgr.delete(ctx, tx, table, where, whereArgs...)
+	}); handled {
+		return n, txErr
+	}
+	counterDeltas, err := mgr.countCounterCacheChildren(executor, table, where, whereArgs)
+	if err != nil {
+		return 0, err
 	}
 
 	querySQL := fmt.Sprintf("DELETE FROM %s WHERE %s", table, where)
 	querySQL, whereArgs = mgr.prepareQuerySQL(querySQL, whereArgs...)
 
 	start := time.Now()
-	result, err := executor.Exec(querySQL, whereArgs...)
+	result, err := mgr.execWrite(ctx, executor, querySQL, whereArgs...)
 	mgr.logTrace(start, querySQL, whereArgs, err)
 	if err != nil {
 		return 0, err
 	}
-	return result.RowsAffected()
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return affected, mgr.decrementCounterCaches(executor, counterDeltas)
 }
 
 // deleteRecord 根据 Record 中的主键字段删除记录
 // 支持软删除特性
-func (mgr *dbManager) deleteRecord(executor sqlExecutor, table string, record *Record) (int64, error) {
+func (mgr *dbManager) deleteRecord(ctx context.Context, executor sqlExecutor, table string, record *Record) (int64, error) {
 	if err := validateIdentifier(table); err != nil {
 		return 0, err
 	}
@@ -1875,15 +2220,14 @@ func (mgr *dbManager) deleteRecord(executor sqlExecutor, table string, record *R
 	}
 
 	where := strings.Join(whereClauses, " AND ")
+	where, whereArgs = applyRowFilter(ctx, table, where, whereArgs, true)
 	// 使用支持软删除的 delete 方法
-	return mgr.delete(executor, table, where, whereArgs...)
+	return mgr.delete(ctx, executor, table, where, whereArgs...)
 }
 
 // updateRecord 根据 Record 中的主键字段更新记录
 // 支持自动时间戳和乐观锁特性
-// updateRecord 根据 Record 中的主键字段更新记录
-// 支持自动时间戳和乐观锁特性
-func (mgr *dbManager) updateRecord(executor sqlExecutor, table string, record *Record) (int64, error) {
+func (mgr *dbManager) updateRecord(ctx context.Context, executor sqlExecutor, table string, record *Record) (int64, error) {
 	if err := validateIdentifier(table); err != nil {
 		return 0, err
 	}
@@ -1935,13 +2279,13 @@ func (mgr *dbManager) updateRecord(executor sqlExecutor, table string, record *R
 		return 0, nil
 	}
 
-	where := strings.Join(pkClauses, " AND ")
+	where, pkValues := applyRowFilter(ctx, table, strings.Join(pkClauses, " AND "), pkValues, true)
 
 	// 如果特性检查都关闭，直接使用快速路径
 	if !mgr.enableTimestampCheck && !mgr.enableOptimisticLockCheck {
-		return mgr.updateRecordFast(executor, table, updateRecord, where, pkValues...)
+		return mgr.updateRecordFast(ctx, executor, table, updateRecord, where, pkValues...)
 	}
-	return mgr.update(executor, table, updateRecord, where, pkValues...)
+	return mgr.update(ctx, executor, table, updateRecord, where, pkValues...)
 }
 
 func (mgr *dbManager) count(executor sqlExecutor, table string, where string, whereArgs ...interface{}) (int64, error) {
@@ -1954,7 +2298,7 @@ func (mgr *dbManager) count(executor sqlExecutor, table string, where string, wh
 	} else {
 		querySQL = fmt.Sprintf("SELECT COUNT(*) FROM %s", table)
 	}
-	querySQL = mgr.convertPlaceholder(querySQL, mgr.config.Driver)
+	querySQL = mgr.convertPlaceholder(querySQL, mgr.config.Load().Driver)
 	whereArgs = mgr.sanitizeArgs(querySQL, whereArgs)
 
 	var count int64
@@ -1975,13 +2319,39 @@ func (mgr *dbManager) exists(executor sqlExecutor, table string, where string, w
 	return count > 0, nil
 }
 
-func (mgr *dbManager) batchInsertRecord(executor sqlExecutor, table string, records []*Record, batchSize int) (int64, error) {
+func (mgr *dbManager) batchInsertRecord(ctx context.Context, executor sqlExecutor, table string, records []*Record, batchSize int) (_ int64, err error) {
+	defer func() { mgr.afterRecordsWrite(executor, table, nil, err) }()
+	if n, handled, txErr := withCounterCacheTx(mgr, executor, table, func(tx sqlExecutor) (int64, error) {
+		return mgr.batchInsertRecord(ctx, tx, table, records, batchSize)
+	}); handled {
+		return n, txErr
+	}
+	defer func() {
+		if err == nil {
+			mgr.publishInserts(executor, table, records, 0)
+		}
+	}()
+	defer func() {
+		if err == nil {
+			err = mgr.incrementCounterCaches(executor, table, records)
+		}
+	}()
 	if err := validateIdentifier(table); err != nil {
 		return 0, err
 	}
 	if len(records) == 0 {
 		return 0, nil
 	}
+	mgr.applyColumnDefaults(table, records...)
+	if err := mgr.validateRecordsColumns(table, records); err != nil {
+		return 0, err
+	}
+	if err := mgr.checkActiveUnique(executor, table, records...); err != nil {
+		return 0, err
+	}
+	if err := mgr.coerceRecordsTypes(table, records); err != nil {
+		return 0, err
+	}
 	if batchSize <= 0 {
 		batchSize = DefaultBatchSize
 	}
@@ -1992,7 +2362,7 @@ func (mgr *dbManager) batchInsertRecord(executor sqlExecutor, table string, reco
 	}
 
 	var totalAffected int64
-	driver := mgr.config.Driver
+	driver := mgr.config.Load().Driver
 
 	// Extract and sort columns once for all batches
 	// 使用第一条记录获取列信息，并排除自增列的零值
@@ -2001,6 +2371,7 @@ func (mgr *dbManager) batchInsertRecord(executor sqlExecutor, table string, reco
 
 	numCols := len(columns)
 	colNamesJoined := joinStrings(columns)
+	batchSize = mgr.safeBatchSize("BatchInsert", table, batchSize, numCols, records, true)
 
 	// Pre-generate row placeholders for drivers that support multi-row INSERT
 	var rowPlaceholder string
@@ -2114,7 +2485,7 @@ func (mgr *dbManager) batchInsertRecord(executor sqlExecutor, table string, reco
 		}
 
 		start := time.Now()
-		result, err := executor.Exec(querySQL, flatArgs...)
+		result, err := mgr.execWrite(ctx, executor, querySQL, flatArgs...)
 		mgr.logTrace(start, querySQL, flatArgs, err)
 		if err != nil {
 			return totalAffected, err
@@ -2126,13 +2497,37 @@ func (mgr *dbManager) batchInsertRecord(executor sqlExecutor, table string, reco
 }
 
 // batchUpdate 批量更新记录（根据主键）
-func (mgr *dbManager) batchUpdateRecord(executor sqlExecutor, table string, records []*Record, batchSize int) (int64, error) {
+func (mgr *dbManager) batchUpdateRecord(ctx context.Context, executor sqlExecutor, table string, records []*Record, batchSize int) (_ int64, err error) {
+	defer func() { mgr.afterRecordsWrite(executor, table, records, err) }()
 	if err := validateIdentifier(table); err != nil {
 		return 0, err
 	}
 	if len(records) == 0 {
 		return 0, nil
 	}
+	if err := mgr.validateRecordsColumns(table, records); err != nil {
+		return 0, err
+	}
+	if err := mgr.coerceRecordsTypes(table, records); err != nil {
+		return 0, err
+	}
+	if n, handled, txErr := withHistoryTx(mgr, executor, table, func(tx sqlExecutor) (int64, error) {
+		return mgr.batchUpdateRecord(ctx, tx, table, records, batchSize)
+	}); handled {
+		return n, txErr
+	}
+	if err := mgr.archiveHistoryForRecords(executor, table, records); err != nil {
+		return 0, err
+	}
+	changes, err := mgr.captureChangesForRecords(executor, table, ChangeUpdate, records)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err == nil {
+			mgr.publishChanges(executor, changes)
+		}
+	}()
 	if batchSize <= 0 {
 		batchSize = DefaultBatchSize
 	}
@@ -2151,6 +2546,8 @@ func (mgr *dbManager) batchUpdateRecord(executor sqlExecutor, table string, reco
 		return 0, fmt.Errorf("table %s has no primary key, cannot use BatchUpdate", table)
 	}
 
+	filter := writeRowCondition(ctx, table)
+
 	// 检查是否启用了乐观锁
 	optimisticConfig := mgr.getOptimisticLockConfig(table)
 	hasOptimisticLock := optimisticConfig != nil && optimisticConfig.VersionField != ""
@@ -2185,7 +2582,7 @@ func (mgr *dbManager) batchUpdateRecord(executor sqlExecutor, table string, reco
 
 	// 如果启用了乐观锁，使用事务确保数据一致性
 	if hasOptimisticLock {
-		return mgr.batchUpdateWithOptimisticLockInTransaction(executor, table, records, batchSize, pks, updateCols, optimisticConfig)
+		return mgr.batchUpdateWithOptimisticLockInTransaction(ctx, executor, table, records, batchSize, pks, updateCols, optimisticConfig, filter)
 	}
 
 	// Build UPDATE SQL once
@@ -2197,15 +2594,16 @@ func (mgr *dbManager) batchUpdateRecord(executor sqlExecutor, table string, reco
 	for _, pk := range pks {
 		whereClauses = append(whereClauses, fmt.Sprintf("%s = ?", pk))
 	}
+	where, filterArgs := filter.and(strings.Join(whereClauses, " AND "), nil)
 
 	querySQL := fmt.Sprintf("UPDATE %s SET %s WHERE %s",
-		table, joinStrings(setClauses), joinStrings(whereClauses))
-	querySQL = mgr.convertPlaceholder(querySQL, mgr.config.Driver)
+		table, joinStrings(setClauses), where)
+	querySQL = mgr.convertPlaceholder(querySQL, mgr.config.Load().Driver)
 
 	var totalAffected int64
 	numUpdateCols := len(updateCols)
 	numPKs := len(pks)
-	numTotalArgs := numUpdateCols + numPKs
+	numTotalArgs := numUpdateCols + numPKs + len(filterArgs)
 
 	// Try to use prepared statement for all batches
 	var stmt *sql.Stmt
@@ -2238,15 +2636,16 @@ func (mgr *dbManager) batchUpdateRecord(executor sqlExecutor, table string, reco
 				values[numUpdateCols+j] = record.columns[pk]
 			}
 			record.mu.RUnlock()
+			copy(values[numUpdateCols+numPKs:], filterArgs)
 
 			start := time.Now()
 			var result sql.Result
 			var err error
 			if stmt != nil {
-				result, err = stmt.Exec(values...)
+				result, err = mgr.execWriteStmt(ctx, executor, stmt, values...)
 			} else {
 				sanitizedValues := mgr.sanitizeArgs(querySQL, values)
-				result, err = executor.Exec(querySQL, sanitizedValues...)
+				result, err = mgr.execWrite(ctx, executor, querySQL, sanitizedValues...)
 			}
 			mgr.logTrace(start, querySQL, values, err)
 			if err != nil {
@@ -2262,18 +2661,18 @@ func (mgr *dbManager) batchUpdateRecord(executor sqlExecutor, table string, reco
 
 // batchUpdateWithOptimisticLockInTransaction 在事务中进行带乐观锁的批量更新
 // 如果任何一条记录更新失败，整个事务会回滚
-func (mgr *dbManager) batchUpdateWithOptimisticLockInTransaction(executor sqlExecutor, table string, records []*Record, batchSize int, pks []string, updateCols []string, optimisticConfig *OptimisticLockConfig) (int64, error) {
+func (mgr *dbManager) batchUpdateWithOptimisticLockInTransaction(ctx context.Context, executor sqlExecutor, table string, records []*Record, batchSize int, pks []string, updateCols []string, optimisticConfig *OptimisticLockConfig, filter rowCondition) (int64, error) {
 	// 检查是否已经在事务中
 	if _, isTransaction := executor.(*sql.Tx); isTransaction {
 		// 已经在事务中，直接执行
-		return mgr.batchUpdateWithOptimisticLock(executor, table, records, batchSize, pks, updateCols, optimisticConfig)
+		return mgr.batchUpdateWithOptimisticLock(ctx, executor, table, records, batchSize, pks, updateCols, optimisticConfig, filter)
 	}
 
 	// 不在事务中，创建新事务
-	db, ok := executor.(*sql.DB)
+	db, ok := executor.(interface{ Begin() (*sql.Tx, error) })
 	if !ok {
 		// 如果不是 *sql.DB，回退到原有逻辑
-		return mgr.batchUpdateWithOptimisticLock(executor, table, records, batchSize, pks, updateCols, optimisticConfig)
+		return mgr.batchUpdateWithOptimisticLock(ctx, executor, table, records, batchSize, pks, updateCols, optimisticConfig, filter)
 	}
 
 	// 开始事务
@@ -2283,7 +2682,7 @@ func (mgr *dbManager) batchUpdateWithOptimisticLockInTransaction(executor sqlExe
 	}
 
 	// 在事务中执行批量更新
-	totalAffected, err := mgr.batchUpdateWithOptimisticLock(tx, table, records, batchSize, pks, updateCols, optimisticConfig)
+	totalAffected, err := mgr.batchUpdateWithOptimisticLock(ctx, tx, table, records, batchSize, pks, updateCols, optimisticConfig, filter)
 
 	if err != nil {
 		// 更新失败，回滚事务
@@ -2323,7 +2722,7 @@ func (mgr *dbManager) batchUpdateWithOptimisticLockInTransaction(executor sqlExe
 }
 
 // batchUpdateWithOptimisticLock 执行带乐观锁检查的批量更新（不处理事务）
-func (mgr *dbManager) batchUpdateWithOptimisticLock(executor sqlExecutor, table string, records []*Record, batchSize int, pks []string, updateCols []string, optimisticConfig *OptimisticLockConfig) (int64, error) {
+func (mgr *dbManager) batchUpdateWithOptimisticLock(ctx context.Context, executor sqlExecutor, table string, records []*Record, batchSize int, pks []string, updateCols []string, optimisticConfig *OptimisticLockConfig, filter rowCondition) (int64, error) {
 	var totalAffected int64
 
 	for i := 0; i < len(records); i += batchSize {
@@ -2368,10 +2767,12 @@ func (mgr *dbManager) batchUpdateWithOptimisticLock(executor sqlExecutor, table
 				}
 			}
 
+			where, whereValues := filter.and(strings.Join(whereClauses, " AND "), whereValues)
+
 			// 构建完整的UPDATE语句
 			querySQL := fmt.Sprintf("UPDATE %s SET %s WHERE %s",
-				table, strings.Join(setClauses, ", "), strings.Join(whereClauses, " AND "))
-			querySQL = mgr.convertPlaceholder(querySQL, mgr.config.Driver)
+				table, strings.Join(setClauses, ", "), where)
+			querySQL = mgr.convertPlaceholder(querySQL, mgr.config.Load().Driver)
 
 			// 合并参数
 			allValues := append(setValues, whereValues...)
@@ -2379,7 +2780,7 @@ func (mgr *dbManager) batchUpdateWithOptimisticLock(executor sqlExecutor, table
 
 			// 执行更新
 			start := time.Now()
-			result, err := executor.Exec(querySQL, allValues...)
+			result, err := mgr.execWrite(ctx, executor, querySQL, allValues...)
 			mgr.logTrace(start, querySQL, allValues, err)
 			if err != nil {
 				return totalAffected, err
@@ -2408,7 +2809,8 @@ func (mgr *dbManager) batchUpdateWithOptimisticLock(executor sqlExecutor, table
 }
 
 // batchDelete 批量删除记录（根据主键）
-func (mgr *dbManager) batchDeleteRecord(executor sqlExecutor, table string, records []*Record, batchSize int) (int64, error) {
+func (mgr *dbManager) batchDeleteRecord(ctx context.Context, executor sqlExecutor, table string, records []*Record, batchSize int) (_ int64, err error) {
+	defer func() { mgr.afterRecordsWrite(executor, table, records, err) }()
 	if err := validateIdentifier(table); err != nil {
 		return 0, err
 	}
@@ -2419,11 +2821,38 @@ func (mgr *dbManager) batchDeleteRecord(executor sqlExecutor, table string, reco
 		batchSize = DefaultBatchSize
 	}
 
+	if n, handled, txErr := withHistoryTx(mgr, executor, table, func(tx sqlExecutor) (int64, error) {
+		return mgr.batchDeleteRecord(ctx, tx, table, records, batchSize)
+	}); handled {
+		return n, txErr
+	}
+	if mgr.getSoftDeleteConfig(table) == nil {
+		if n, handled, txErr := withCounterCacheTx(mgr, executor, table, func(tx sqlExecutor) (int64, error) {
+			return mgr.batchDeleteRecord(ctx, tx, table, records, batchSize)
+		}); handled {
+			return n, txErr
+		}
+	}
+	if err := mgr.archiveHistoryForRecords(executor, table, records); err != nil {
+		return 0, err
+	}
+	changes, err := mgr.captureChangesForRecords(executor, table, ChangeDelete, records)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err == nil {
+			mgr.publishChanges(executor, changes)
+		}
+	}()
+
+	filter := writeRowCondition(ctx, table)
+
 	// 检查是否配置了软删除
 	softDeleteConfig := mgr.getSoftDeleteConfig(table)
 	if softDeleteConfig != nil {
 		// 使用软删除
-		return mgr.batchSoftDeleteRecord(executor, table, records, batchSize, softDeleteConfig)
+		return mgr.batchSoftDeleteRecord(ctx, executor, table, records, batchSize, softDeleteConfig, filter)
 	}
 
 	// 获取表的主键
@@ -2434,9 +2863,10 @@ func (mgr *dbManager) batchDeleteRecord(executor sqlExecutor, table string, reco
 	if len(pks) == 0 {
 		return 0, fmt.Errorf("table %s has no primary key, cannot use BatchDelete", table)
 	}
+	batchSize = mgr.safeBatchSize("BatchDelete", table, batchSize, len(pks)+1, nil, false)
 
 	var totalAffected int64
-	driver := mgr.config.Driver
+	driver := mgr.config.Load().Driver
 
 	// 分批处理
 	for i := 0; i < len(records); i += batchSize {
@@ -2453,47 +2883,49 @@ func (mgr *dbManager) batchDeleteRecord(executor sqlExecutor, table string, reco
 			var pkValues []interface{}
 			var placeholders []string
 
-			for idx, record := range batch {
+			for _, record := range batch {
 				pkVal := record.Get(pk)
 				if pkVal == nil {
 					continue
 				}
 				pkValues = append(pkValues, pkVal)
-				if driver == PostgreSQL {
-					placeholders = append(placeholders, fmt.Sprintf("$%d", idx+1))
-				} else if driver == SQLServer {
-					placeholders = append(placeholders, fmt.Sprintf("@p%d", idx+1))
-				} else if driver == Oracle {
-					placeholders = append(placeholders, fmt.Sprintf(":%d", idx+1))
-				} else {
-					placeholders = append(placeholders, "?")
-				}
+				placeholders = append(placeholders, "?")
 			}
 
 			if len(pkValues) == 0 {
 				continue
 			}
 
-			querySQL := fmt.Sprintf("DELETE FROM %s WHERE %s IN (%s)",
-				table, pk, strings.Join(placeholders, ", "))
+			where, args := filter.and(fmt.Sprintf("%s IN (%s)", pk, strings.Join(placeholders, ", ")), pkValues)
+			counterDeltas, err := mgr.countCounterCacheChildren(executor, table, where, args)
+			if err != nil {
+				return totalAffected, err
+			}
+			querySQL := fmt.Sprintf("DELETE FROM %s WHERE %s", table, where)
+			querySQL = mgr.convertPlaceholder(querySQL, driver)
+			pkValues = args
 
 			start := time.Now()
-			result, err := executor.Exec(querySQL, pkValues...)
+			result, err := mgr.execWrite(ctx, executor, querySQL, pkValues...)
 			mgr.logTrace(start, querySQL, pkValues, err)
 			if err != nil {
 				return totalAffected, err
 			}
 			affected, _ := result.RowsAffected()
 			totalAffected += affected
+			if err := mgr.decrementCounterCaches(executor, counterDeltas); err != nil {
+				return totalAffected, err
+			}
 		} else {
 			// 复合主键，使用预处理语句逐条删除
 			var whereClauses []string
 			for _, pk := range pks {
 				whereClauses = append(whereClauses, fmt.Sprintf("%s = ?", pk))
 			}
+			where, _ := filter.and(strings.Join(whereClauses, " AND "), nil)
+			hasCounterCaches := len(mgr.getCounterCacheConfigs(table)) > 0
 
-			querySQL := fmt.Sprintf("DELETE FROM %s WHERE %s",
-				table, strings.Join(whereClauses, " AND "))
+			querySQL := fmt.Sprintf("DELETE FROM %s WHERE %s", table, where)
 			querySQL = mgr.convertPlaceholder(querySQL, driver)
 
 			// 尝试使用预处理语句
@@ -2509,15 +2941,25 @@ func (mgr *dbManager) batchDeleteRecord(executor sqlExecutor, table string, reco
 						for _, pk := range pks {
 							pkValues = append(pkValues, record.Get(pk))
 						}
+						pkValues = append(pkValues, filter.args...)
+						var counterDeltas map[*CounterCacheConfig][]counterDelta
+						if hasCounterCaches {
+							if counterDeltas, err = mgr.countCounterCacheChildren(executor, table, where, pkValues); err != nil {
+								return totalAffected, err
+							}
+						}
 
 						start := time.Now()
-						result, err := stmt.Exec(pkValues...)
+						result, err := mgr.execWriteStmt(ctx, executor, stmt, pkValues...)
 						mgr.logTrace(start, querySQL, pkValues, err)
 						if err != nil {
 							return totalAffected, err
 						}
 						affected, _ := result.RowsAffected()
 						totalAffected += affected
+						if err := mgr.decrementCounterCaches(executor, counterDeltas); err != nil {
+							return totalAffected, err
+						}
 					}
 					continue
 				}
@@ -2529,15 +2971,25 @@ func (mgr *dbManager) batchDeleteRecord(executor sqlExecutor, table string, reco
 				for _, pk := range pks {
 					pkValues = append(pkValues, record.Get(pk))
 				}
+				pkValues = append(pkValues, filter.args...)
+				var counterDeltas map[*CounterCacheConfig][]counterDelta
+				if hasCounterCaches {
+					if counterDeltas, err = mgr.countCounterCacheChildren(executor, table, where, pkValues); err != nil {
+						return totalAffected, err
+					}
+				}
 
 				start := time.Now()
-				result, err := executor.Exec(querySQL, pkValues...)
+				result, err := mgr.execWrite(ctx, executor, querySQL, pkValues...)
 				mgr.logTrace(start, querySQL, pkValues, err)
 				if err != nil {
 					return totalAffected, err
 				}
 				affected, _ := result.RowsAffected()
 				totalAffected += affected
+				if err := mgr.decrementCounterCaches(executor, counterDeltas); err != nil {
+					return totalAffected, err
+				}
 			}
 		}
 	}
@@ -2546,7 +2998,8 @@ func (mgr *dbManager) batchDeleteRecord(executor sqlExecutor, table string, reco
 }
 
 // batchSoftDeleteRecord 批量软删除记录
-func (mgr *dbManager) batchSoftDeleteRecord(executor sqlExecutor, table string, records []*Record, batchSize int, config *SoftDeleteConfig) (int64, error) {
+func (mgr *dbManager) batchSoftDeleteRecord(ctx context.Context, executor sqlExecutor, table string, records []*Record, batchSize int, config *SoftDeleteConfig, filter rowCondition) (_ int64, err error) {
+	defer func() { mgr.afterRecordsWrite(executor, table, records, err) }()
 	// 获取表的主键
 	pks, err := mgr.getPrimaryKeys(executor, table)
 	if err != nil {
@@ -2555,9 +3008,10 @@ func (mgr *dbManager) batchSoftDeleteRecord(executor sqlExecutor, table string,
 	if len(pks) == 0 {
 		return 0, fmt.Errorf("table %s has no primary key, cannot use BatchDelete", table)
 	}
+	batchSize = mgr.safeBatchSize("BatchDelete", table, batchSize, len(pks)+1, nil, false)
 
 	var totalAffected int64
-	driver := mgr.config.Driver
+	driver := mgr.config.Load().Driver
 
 	// 准备软删除的SET子句
 	var setValue string
@@ -2586,37 +3040,29 @@ func (mgr *dbManager) batchSoftDeleteRecord(executor sqlExecutor, table string,
 			var pkValues []interface{}
 			var placeholders []string
 
-			for idx, record := range batch {
+			for _, record := range batch {
 				pkVal := record.Get(pk)
 				if pkVal == nil {
 					continue
 				}
 				pkValues = append(pkValues, pkVal)
-				if driver == PostgreSQL {
-					placeholders = append(placeholders, fmt.Sprintf("$%d", len(setArgs)+idx+1))
-				} else if driver == SQLServer {
-					placeholders = append(placeholders, fmt.Sprintf("@p%d", len(setArgs)+idx+1))
-				} else if driver == Oracle {
-					placeholders = append(placeholders, fmt.Sprintf(":%d", len(setArgs)+idx+1))
-				} else {
-					placeholders = append(placeholders, "?")
-				}
+				placeholders = append(placeholders, "?")
 			}
 
 			if len(pkValues) == 0 {
 				continue
 			}
 
-			querySQL := fmt.Sprintf("UPDATE %s SET %s WHERE %s IN (%s)",
-				table, setValue, pk, strings.Join(placeholders, ", "))
+			where, whereArgs := filter.and(fmt.Sprintf("%s IN (%s)", pk, strings.Join(placeholders, ", ")), pkValues)
+			querySQL := fmt.Sprintf("UPDATE %s SET %s WHERE %s", table, setValue, where)
 			querySQL = mgr.convertPlaceholder(querySQL, driver)
 
 			// 合并参数：SET参数 + WHERE参数
-			allArgs := append(setArgs, pkValues...)
+			allArgs := append(append([]interface{}{}, setArgs...), whereArgs...)
 			allArgs = mgr.sanitizeArgs(querySQL, allArgs)
 
 			start := time.Now()
-			result, err := executor.Exec(querySQL, allArgs...)
+			result, err := mgr.execWrite(ctx, executor, querySQL, allArgs...)
 			mgr.logTrace(start, querySQL, allArgs, err)
 			if err != nil {
 				return totalAffected, err
@@ -2630,8 +3076,8 @@ func (mgr *dbManager) batchSoftDeleteRecord(executor sqlExecutor, table string,
 				whereClauses = append(whereClauses, fmt.Sprintf("%s = ?", pk))
 			}
 
-			querySQL := fmt.Sprintf("UPDATE %s SET %s WHERE %s",
-				table, setValue, strings.Join(whereClauses, " AND "))
+			where, _ := filter.and(strings.Join(whereClauses, " AND "), nil)
+			querySQL := fmt.Sprintf("UPDATE %s SET %s WHERE %s", table, setValue, where)
 			querySQL = mgr.convertPlaceholder(querySQL, driver)
 
 			for _, record := range batch {
@@ -2639,13 +3085,14 @@ func (mgr *dbManager) batchSoftDeleteRecord(executor sqlExecutor, table string,
 				for _, pk := range pks {
 					pkValues = append(pkValues, record.Get(pk))
 				}
+				pkValues = append(pkValues, filter.args...)
 
 				// 合并参数：SET参数 + WHERE参数
-				allArgs := append(setArgs, pkValues...)
+				allArgs := append(append([]interface{}{}, setArgs...), pkValues...)
 				allArgs = mgr.sanitizeArgs(querySQL, allArgs)
 
 				start := time.Now()
-				result, err := executor.Exec(querySQL, allArgs...)
+				result, err := mgr.execWrite(ctx, executor, querySQL, allArgs...)
 				mgr.logTrace(start, querySQL, allArgs, err)
 				if err != nil {
 					return totalAffected, err
@@ -2660,7 +3107,8 @@ func (mgr *dbManager) batchSoftDeleteRecord(executor sqlExecutor, table string,
 }
 
 // batchDeleteByIds 根据主键ID列表批量删除
-func (mgr *dbManager) batchDeleteByIds(executor sqlExecutor, table string, ids []interface{}, batchSize int) (int64, error) {
+func (mgr *dbManager) batchDeleteByIds(ctx context.Context, executor sqlExecutor, table string, ids []interface{}, batchSize int) (_ int64, err error) {
+	defer func() { mgr.afterIdsWrite(executor, table, ids, err) }()
 	if err := validateIdentifier(table); err != nil {
 		return 0, err
 	}
@@ -2682,10 +3130,41 @@ func (mgr *dbManager) batchDeleteByIds(executor sqlExecutor, table string, ids [
 	if len(pks) > 1 {
 		return 0, fmt.Errorf("BatchDeleteByIds only supports single primary key tables")
 	}
+	batchSize = mgr.safeBatchSize("BatchDeleteByIds", table, batchSize, 1, nil, false)
+
+	if n, handled, txErr := withHistoryTx(mgr, executor, table, func(tx sqlExecutor) (int64, error) {
+		return mgr.batchDeleteByIds(ctx, tx, table, ids, batchSize)
+	}); handled {
+		return n, txErr
+	}
+	if n, handled, txErr := withCounterCacheTx(mgr, executor, table, func(tx sqlExecutor) (int64, error) {
+		return mgr.batchDeleteByIds(ctx, tx, table, ids, batchSize)
+	}); handled {
+		return n, txErr
+	}
+	if mgr.getHistoryConfig(table) != nil || mgr.hasChangeSubscribers(table) {
+		idRecords := make([]*Record, len(ids))
+		for i, id := range ids {
+			idRecords[i] = NewRecord().Set(pks[0], id)
+		}
+		if err := mgr.archiveHistoryForRecords(executor, table, idRecords); err != nil {
+			return 0, err
+		}
+		var changes *changeCapture
+		if changes, err = mgr.captureChangesForRecords(executor, table, ChangeDelete, idRecords); err != nil {
+			return 0, err
+		}
+		defer func() {
+			if err == nil {
+				mgr.publishChanges(executor, changes)
+			}
+		}()
+	}
 
 	pk := pks[0]
 	var totalAffected int64
-	driver := mgr.config.Driver
+	driver := mgr.config.Load().Driver
+	filter := writeRowCondition(ctx, table)
 
 	// 分批处理
 	for i := 0; i < len(ids); i += batchSize {
@@ -2694,38 +3173,35 @@ func (mgr *dbManager) batchDeleteByIds(executor sqlExecutor, table string, ids [
 			end = len(ids)
 		}
 
-		batch := ids[i:end]
-		var placeholders []string
-
-		for idx := range batch {
-			if driver == PostgreSQL {
-				placeholders = append(placeholders, fmt.Sprintf("$%d", idx+1))
-			} else if driver == SQLServer {
-				placeholders = append(placeholders, fmt.Sprintf("@p%d", idx+1))
-			} else if driver == Oracle {
-				placeholders = append(placeholders, fmt.Sprintf(":%d", idx+1))
-			} else {
-				placeholders = append(placeholders, "?")
-			}
+		placeholders := make([]string, end-i)
+		for idx := range placeholders {
+			placeholders[idx] = "?"
 		}
-
-		querySQL := fmt.Sprintf("DELETE FROM %s WHERE %s IN (%s)",
-			table, pk, strings.Join(placeholders, ", "))
+		where, batch := filter.and(fmt.Sprintf("%s IN (%s)", pk, strings.Join(placeholders, ", ")), ids[i:end])
+		counterDeltas, err := mgr.countCounterCacheChildren(executor, table, where, batch)
+		if err != nil {
+			return totalAffected, err
+		}
+		querySQL := fmt.Sprintf("DELETE FROM %s WHERE %s", table, where)
+		querySQL = mgr.convertPlaceholder(querySQL, driver)
 
 		start := time.Now()
-		result, err := executor.Exec(querySQL, batch...)
+		result, err := mgr.execWrite(ctx, executor, querySQL, batch...)
 		mgr.logTrace(start, querySQL, batch, err)
 		if err != nil {
 			return totalAffected, err
 		}
 		affected, _ := result.RowsAffected()
 		totalAffected += affected
+		if err := mgr.decrementCounterCaches(executor, counterDeltas); err != nil {
+			return totalAffected, err
+		}
 	}
 
 	return totalAffected, nil
 }
 
-func (mgr *dbManager) paginate(executor sqlExecutor, querySQL string, page, pageSize int, countCacheTTL time.Duration, args ...interface{}) ([]*Record, int64, error) {
+func (mgr *dbManager) paginate(ctx context.Context, executor sqlExecutor, querySQL string, page, pageSize int, countCacheTTL time.Duration, args ...interface{}) ([]*Record, int64, error) {
 	if page < 1 {
 		page = DefaultPage
 	}
@@ -2737,11 +3213,14 @@ func (mgr *dbManager) paginate(executor sqlExecutor, querySQL string, page, page
 		pageSize = MaxPageSize
 	}
 
-	driver := mgr.config.Driver
-	baseSQL := querySQL
-	if orderIdx := findKeywordIgnoringQuotes(querySQL, "ORDER BY", -1); orderIdx != -1 {
-		baseSQL = querySQL[:orderIdx]
+	if err := mgr.checkQuestionEscape(querySQL); err != nil {
+		return nil, 0, err
 	}
+	querySQL, args = expandSqlExprArgs(querySQL, args)
+	querySQL, args = expandSliceArgs(querySQL, args)
+
+	driver := mgr.config.Load().Driver
+	baseSQL, _ := splitTopLevelOrderBy(querySQL)
 
 	var countSQL string
 	// 尝试优化 COUNT 语句
@@ -2764,7 +3243,7 @@ func (mgr *dbManager) paginate(executor sqlExecutor, querySQL string, page, page
 	// 检查是否启用了计数缓存（countCacheTTL > 0 表示启用）
 	if countCacheTTL > 0 {
 		// 生成计数缓存键
-		countCacheKey := GenerateCacheKey(mgr.name, "COUNT:"+countSQL, args...)
+		countCacheKey := GenerateCacheKeyContext(ctx, mgr.name, "COUNT:"+countSQL, args...)
 		countCacheRepo := "__eorm_count_cache__"
 
 		// 尝试从缓存获取计数
@@ -2795,24 +3274,7 @@ func (mgr *dbManager) paginate(executor sqlExecutor, querySQL string, page, page
 	}
 
 	offset := (page - 1) * pageSize
-	var paginatedSQL string
-	hasOrderBy := findKeywordIgnoringQuotes(querySQL, "ORDER BY", -1) != -1
-	if driver == SQLServer {
-		if hasOrderBy {
-			paginatedSQL = fmt.Sprintf("%s OFFSET %d ROWS FETCH NEXT %d ROWS ONLY", querySQL, offset, pageSize)
-		} else {
-			paginatedSQL = fmt.Sprintf("%s ORDER BY (SELECT NULL) OFFSET %d ROWS FETCH NEXT %d ROWS ONLY", querySQL, offset, pageSize)
-		}
-	} else if driver == Oracle {
-		if hasOrderBy {
-			paginatedSQL = fmt.Sprintf("SELECT a.* FROM (SELECT a.*, ROWNUM eorm_rn FROM (%s) a WHERE ROWNUM <= %d) a WHERE eorm_rn > %d", querySQL, offset+pageSize, offset)
-		} else {
-			paginatedSQL = fmt.Sprintf("SELECT a.* FROM (SELECT a.*, ROWNUM eorm_rn FROM (%s ORDER BY 1) a WHERE ROWNUM <= %d) a WHERE eorm_rn > %d", querySQL, offset+pageSize, offset)
-		}
-	} else {
-		paginatedSQL = fmt.Sprintf("%s LIMIT %d OFFSET %d", querySQL, pageSize, offset)
-	}
-
+	paginatedSQL, withRowNum := mgr.buildPaginatedSQL(querySQL, offset, pageSize)
 	paginatedSQL = mgr.convertPlaceholder(paginatedSQL, driver)
 
 	startPaginate := time.Now()
@@ -2823,10 +3285,13 @@ func (mgr *dbManager) paginate(executor sqlExecutor, querySQL string, page, page
 	}
 	defer rows.Close()
 
-	results, err := scanRecords(rows, driver)
+	results, err := mgr.scanRecordsLimited(context.Background(), rows, paginatedSQL)
 	if err != nil {
 		return nil, total, err
 	}
+	if withRowNum {
+		removePaginationRowNum(results)
+	}
 	return results, total, nil
 }
 
@@ -3106,22 +3571,33 @@ func ScanRecords(rows *sql.Rows) ([]*Record, error) {
 // 2. 直接扫描到Record，避免中间map的内存分配
 // 3. 重用扫描缓冲区，减少每行的内存分配
 // 4. 零Map扩容，完全避免rehashing开销
-// 注意：由于需要返回Record值而非指针，这里直接创建精确容量的Record
-//
-//	对象池更适合用于临时操作的场景
+// 5. 列名小写形式和数据库类型每条语句只计算一次
+// 注意：默认直接创建精确容量的Record，开启 EnableRecordPooling 后从对象池获取，由调用方通过 ReleaseRecords 归还
 func scanRecords(rows *sql.Rows, driver DriverType) ([]*Record, error) {
+	results, _, err := scanRecordsMax(rows, driver, 0)
+	return results, err
+}
+
+// scanRecordsMax 扫描结果集，maxRows > 0 时最多扫描 maxRows 行，存在更多行时 exceeded 为 true
+func scanRecordsMax(rows *sql.Rows, driver DriverType, maxRows int) (results []*Record, exceeded bool, err error) {
 	columns, err := rows.Columns()
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
 	columnTypes, err := rows.ColumnTypes()
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
 	numCols := len(columns)
-	var results []*Record
+
+	// 列名、小写列名和数据库类型每条语句只计算一次，所有行共享
+	dbTypes := make([]string, numCols)
+	for i := range columnTypes {
+		dbTypes[i] = strings.ToUpper(columnTypes[i].DatabaseTypeName())
+	}
+	cols := newScanColumns(columns, dbTypes)
 
 	// 重用扫描缓冲区，避免每行都分配新的slice
 	values := make([]interface{}, numCols)
@@ -3131,35 +3607,33 @@ func scanRecords(rows *sql.Rows, driver DriverType) ([]*Record, error) {
 	}
 
 	for rows.Next() {
-		if err := rows.Scan(valuePtrs...); err != nil {
-			return nil, err
+		if maxRows > 0 && len(results) >= maxRows {
+			exceeded = true
+			break
 		}
-
-		// 直接创建结果Record，使用精确容量（避免不必要的对象池操作）
-		resultRecord := &Record{
-			columns:     make(map[string]interface{}, numCols),
-			lowerKeyMap: make(map[string]string, numCols),
+		if err := rows.Scan(valuePtrs...); err != nil {
+			return nil, false, err
 		}
 
-		for i, col := range columns {
-			val := values[i]
-			dbType := strings.ToUpper(columnTypes[i].DatabaseTypeName())
+		// 使用精确容量创建结果Record，开启 EnableRecordPooling 时从对象池获取
+		resultRecord := newScanRecord(numCols)
 
+		for i, col := range cols.names {
 			// 使用专门的函数处理数据库值转换
-			processedVal := processDBValue(val, dbType)
+			processedVal := processDBValue(values[i], cols.dbTypes[i])
 
-			// 使用 setDirect 直接设置，跳过 Set 方法的指针检查和加锁
-			resultRecord.setDirect(col, processedVal)
+			// 直接设置，跳过 Set 方法的指针检查和加锁
+			resultRecord.setDirectLower(col, cols.lowers[i], processedVal)
 		}
 
 		results = append(results, resultRecord)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
-	return results, nil
+	return results, exceeded, nil
 }
 
 // scanMaps is a helper function to scan sql.Rows into a slice of map
@@ -3174,8 +3648,8 @@ func (db *DB) GetDB() (*sql.DB, error) {
 
 // Close closes the database connection
 func (db *DB) Close() error {
-	if db.dbMgr.db != nil {
-		return db.dbMgr.db.Close()
+	if sdb := db.dbMgr.db.Load(); sdb != nil {
+		return sdb.Close()
 	}
 	return nil
 }
@@ -3233,7 +3707,7 @@ func (mgr *dbManager) GetConfig() (*Config, error) {
 	if mgr == nil {
 		return nil, fmt.Errorf("database manager is nil")
 	}
-	return mgr.config, nil
+	return mgr.config.Load(), nil
 }
 
 // GetDatabase returns the database manager by name
@@ -3295,13 +3769,15 @@ func Close() error {
 	for _, dbMgr := range multiMgr.databases {
 		// 停止连接监控
 		cleanupMonitor(dbMgr.name)
+		dbMgr.stopRowTTLReapers()
+		dbMgr.stopAggregateSchedulers()
 
 		// 清理预编译语句缓存
 		dbMgr.clearStmtCache()
 
 		// 关闭数据库连接
-		if dbMgr.db != nil {
-			dbMgr.db.Close()
+		if sdb := dbMgr.db.Load(); sdb != nil {
+			sdb.Close()
 		}
 	}
 	multiMgr.databases = make(map[string]*dbManager)
@@ -3320,14 +3796,15 @@ func CloseDB(dbname string) error {
 		if dbMgr, exists := multiMgr.databases[dbname]; exists {
 			// 停止连接监控
 			cleanupMonitor(dbname)
+			dbMgr.stopRowTTLReapers()
+			dbMgr.stopAggregateSchedulers()
 
 			// 清理预编译语句缓存
 			dbMgr.clearStmtCache()
 
 			// 关闭数据库连接
-			if dbMgr.db != nil {
-				dbMgr.db.Close()
-				dbMgr.db = nil
+			if sdb := dbMgr.db.Swap(nil); sdb != nil {
+				sdb.Close()
 			}
 			delete(multiMgr.databases, dbname)
 
@@ -3483,7 +3960,7 @@ func (db *DB) EnableSoftDeleteCheck() *DB {
 func (mgr *dbManager) initDB() error {
 	// 1. 第一层检查：使用 RLock 快速判断是否已初始化
 	mgr.mu.RLock()
-	if mgr.db != nil {
+	if mgr.db.Load() != nil {
 		mgr.mu.RUnlock()
 		return nil
 	}
@@ -3494,55 +3971,28 @@ func (mgr *dbManager) initDB() error {
 	defer mgr.initMu.Unlock()
 
 	// 3. 第二层检查：双重检查锁定 (Double-Checked Locking)
-	if mgr.db != nil {
+	if mgr.db.Load() != nil {
 		return nil
 	}
 
-	db, err := sql.Open(string(mgr.config.Driver), mgr.config.DSN)
-	if err != nil {
-		return err
-	}
-
-	// Configure connection pool
-	db.SetMaxOpenConns(mgr.config.MaxOpen)
-	db.SetMaxIdleConns(mgr.config.MaxIdle)
-	db.SetConnMaxLifetime(mgr.config.ConnMaxLifetime)
-
 	// 初始化智能语句缓存
-	cacheConfig := DefaultStmtCacheConfig()
-	if mgr.config.StmtCacheSize > 0 {
-		cacheConfig.Enabled = true
-		cacheConfig.MaxSize = mgr.config.StmtCacheSize
-	} else {
-		cacheConfig.Enabled = false
-	}
-
-	if mgr.config.ConnMaxLifetime > 0 {
-		// 基础 TTL 设为连接生命周期的 80%（比之前的 50% 更激进）
-		cacheConfig.BaseTTL = time.Duration(float64(mgr.config.ConnMaxLifetime) * 0.8)
-		mgr.stmtCacheTTL = mgr.config.ConnMaxLifetime / 2 // 保留用于向后兼容
+	mgr.stmtCache.Store(newStmtCache(stmtCacheConfigFor(mgr.config.Load())))
+	if mgr.config.Load().ConnMaxLifetime > 0 {
+		mgr.stmtCacheTTL = mgr.config.Load().ConnMaxLifetime / 2 // 保留用于向后兼容
 	} else {
-		cacheConfig.BaseTTL = 0 // 永不过期
 		mgr.stmtCacheTTL = 0
 	}
-	mgr.stmtCache = newStmtCache(cacheConfig)
 
-	// Verify connection
-	if err := db.Ping(); err != nil {
-		db.Close()
+	db, err := openConnectionPool(mgr.config.Load())
+	if err != nil {
 		return err
-	} else {
-		//启动表结构缓存预热
-
 	}
 
-	// 4. 将成功初始化的连接赋值给 mgr.db (持锁以保证原子性)
-	mgr.mu.Lock()
-	mgr.db = db
-	mgr.mu.Unlock()
+	// 4. 发布成功初始化的连接（原子写入，读取方无需加锁）
+	mgr.db.Store(db)
 
 	// 根据配置启用连接监控
-	if mgr.config.MonitorNormalInterval > 0 {
+	if mgr.config.Load().MonitorNormalInterval > 0 {
 		if err := mgr.startConnectionMonitoring(); err != nil {
 			// 监控启动失败不影响数据库连接，只记录警告日志
 			LogWarn("连接监控启动失败", NewRecord().
@@ -3554,20 +4004,72 @@ func (mgr *dbManager) initDB() error {
 	return nil
 }
 
+// openConnectionPool 按配置打开连接池并验证连接
+func openConnectionPool(config *Config) (*sql.DB, error) {
+	dsn := config.DSN
+	if config.Driver == SQLite3 {
+		dsn = sqliteDSN(config)
+	}
+	var db *sql.DB
+	var err error
+	if len(config.InitSQL) > 0 || config.OnConnect != nil {
+		db, err = openWithConnInit(string(config.Driver), dsn, config)
+	} else {
+		db, err = sql.Open(string(config.Driver), dsn)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Configure connection pool
+	db.SetMaxOpenConns(config.MaxOpen)
+	db.SetMaxIdleConns(config.MaxIdle)
+	db.SetConnMaxLifetime(config.ConnMaxLifetime)
+
+	// Verify connection
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// stmtCacheConfigFor 根据数据库配置生成语句缓存配置
+func stmtCacheConfigFor(config *Config) StmtCacheConfig {
+	cacheConfig := DefaultStmtCacheConfig()
+	if config.StmtCacheSize > 0 {
+		cacheConfig.Enabled = true
+		cacheConfig.MaxSize = config.StmtCacheSize
+	} else {
+		cacheConfig.Enabled = false
+	}
+
+	if config.ConnMaxLifetime > 0 {
+		// 基础 TTL 设为连接生命周期的 80%（比之前的 50% 更激进）
+		cacheConfig.BaseTTL = time.Duration(float64(config.ConnMaxLifetime) * 0.8)
+	} else {
+		cacheConfig.BaseTTL = 0 // 永不过期
+	}
+	return cacheConfig
+}
+
 // getDB returns the database connection, initializing if necessary
 func (mgr *dbManager) getDB() (*sql.DB, error) {
 	if mgr == nil {
 		return nil, fmt.Errorf("eorm: database manager is nil. Please call eorm.OpenDatabase()  before using eorm operations")
 		// panic("eorm: database manager is nil. Please call eorm.OpenDatabase()  before using eorm operations")
 	}
-	if mgr.db == nil {
-		if err := mgr.initDB(); err != nil {
-			//panic(fmt.Sprintf("eorm: failed to initialize database: %v", err))
-			return nil, fmt.Errorf("eorm: failed to initialize database: %w", err)
-		}
+	if sdb := mgr.db.Load(); sdb != nil {
+		return sdb, nil
 	}
-
-	return mgr.db, nil
+	if err := mgr.initDB(); err != nil {
+		//panic(fmt.Sprintf("eorm: failed to initialize database: %v", err))
+		return nil, fmt.Errorf("eorm: failed to initialize database: %w", err)
+	}
+	if sdb := mgr.db.Load(); sdb != nil {
+		return sdb, nil
+	}
+	return nil, fmt.Errorf("database not initialized")
 }
 
 // Ping checks if the database connection is alive
@@ -3580,13 +4082,16 @@ func (mgr *dbManager) PingContext(ctx context.Context) error {
 	if mgr == nil {
 		return fmt.Errorf("database manager not initialized. Please call eorm.OpenDatabase() before using eorm operations")
 	}
-	if mgr.db == nil {
+	sdb := mgr.db.Load()
+	if sdb == nil {
 		return fmt.Errorf("database not initialized")
 	}
-	return mgr.db.PingContext(ctx)
+	return sdb.PingContext(ctx)
 }
 
 // convertPlaceholder converts ? placeholders to $n for PostgreSQL, @param for SQL Server, or :n for Oracle
+// ?? is an escaped literal question mark (e.g. the PostgreSQL jsonb operators ??, ??| and ??&) and becomes a single ?
+// MySQL and SQLite SQL is returned unchanged: their drivers bind every ?, so the escape is rejected by checkQuestionEscape
 func (mgr *dbManager) convertPlaceholder(querySQL string, driver DriverType) string {
 	return mgr.convertPlaceholderWithOffset(querySQL, driver, 0)
 }
@@ -3641,6 +4146,12 @@ func (mgr *dbManager) convertPlaceholderWithOffset(querySQL string, driver Drive
 		}
 
 		if char == '?' && !inSingleQuote && !inDoubleQuote && !inBacktick {
+			// ?? 转义为字面量 ?
+			if i+1 < len(querySQL) && querySQL[i+1] == '?' {
+				builder.WriteByte('?')
+				i++
+				continue
+			}
 			switch driver {
 			case PostgreSQL:
 				builder.WriteString(fmt.Sprintf("$%d", paramIndex))
@@ -3659,6 +4170,39 @@ func (mgr *dbManager) convertPlaceholderWithOffset(querySQL string, driver Drive
 	return builder.String()
 }
 
+// checkQuestionEscape 检查 MySQL/SQLite 的 SQL 是否使用了 ?? 转义（引号内的内容除外）
+// 这两种驱动会把每个 ? 都当作参数绑定，无法表达字面量 ?，因此直接报错而不是悄悄改写成参数占位符
+func (mgr *dbManager) checkQuestionEscape(querySQL string) error {
+	driver := mgr.config.Load().Driver
+	if driver != MySQL && driver != SQLite3 {
+		return nil
+	}
+	if !strings.Contains(querySQL, "??") {
+		return nil
+	}
+	var quote byte
+	for i := 0; i < len(querySQL); i++ {
+		c := querySQL[i]
+		if quote != 0 {
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+			continue
+		}
+		switch c {
+		case '\'', '"', '`':
+			quote = c
+		case '?':
+			if i+1 < len(querySQL) && querySQL[i+1] == '?' {
+				return ErrQuestionEscapeUnsupported
+			}
+		}
+	}
+	return nil
+}
+
 // sanitizeArgs 自动清理不必要的参数。如果用户误传了参数，则根据 SQL 中的占位符数量进行截断或清理。
 func (mgr *dbManager) sanitizeArgs(querySQL string, args []interface{}) []interface{} {
 	if len(args) == 0 {
@@ -3666,7 +4210,7 @@ func (mgr *dbManager) sanitizeArgs(querySQL string, args []interface{}) []interf
 	}
 
 	placeholderCount := 0
-	switch mgr.config.Driver {
+	switch mgr.config.Load().Driver {
 	case PostgreSQL:
 		// 使用预编译正则精确匹配 $1, $2...，避免 $1 匹配到 $10
 		matches := postgresPlaceholderRe.FindAllStringSubmatch(querySQL, -1)
@@ -3745,7 +4289,7 @@ func (mgr *dbManager) sanitizeArgs(querySQL string, args []interface{}) []interf
 					// 解引用指针，获取实际值
 					actualValue := v.Elem().Interface()
 					// Oracle: 转换 time.Time 为字符串
-					if mgr.config.Driver == Oracle {
+					if mgr.config.Load().Driver == Oracle {
 						if t, ok := actualValue.(time.Time); ok {
 							cleanedArgs = append(cleanedArgs, mgr.formatOracleTime(t))
 							continue
@@ -3755,12 +4299,19 @@ func (mgr *dbManager) sanitizeArgs(querySQL string, args []interface{}) []interf
 				}
 			} else {
 				// Oracle: 转换 time.Time 为字符串
-				if mgr.config.Driver == Oracle {
+				if mgr.config.Load().Driver == Oracle {
 					if t, ok := arg.(time.Time); ok {
 						cleanedArgs = append(cleanedArgs, mgr.formatOracleTime(t))
 						continue
 					}
 				}
+				// PostgreSQL: Go 切片和 map 编码为数组/hstore 字面量
+				if mgr.config.Load().Driver == PostgreSQL {
+					if encoded, ok := encodePgValue(arg); ok {
+						cleanedArgs = append(cleanedArgs, encoded)
+						continue
+					}
+				}
 				// 保持原始类型
 				cleanedArgs = append(cleanedArgs, arg)
 			}
@@ -3783,6 +4334,10 @@ func (mgr *dbManager) logTrace(start time.Time, sql string, args []interface{},
 	} else {
 		LogSQL(mgr.name, sql, displayArgs, duration)
 	}
+	captureSQL(mgr.name, sql, cleanArgs, duration, err)
+	if err == nil {
+		mgr.recordSlowQuery(sql, args, duration)
+	}
 }
 
 // formatArgsForLog 格式化参数用于日志显示
@@ -3812,7 +4367,7 @@ func (mgr *dbManager) buildColumnQuery(table string) (string, []interface{}) {
 	var query string
 	var args []interface{}
 
-	switch mgr.config.Driver {
+	switch mgr.config.Load().Driver {
 	case MySQL:
 		// MySQL: 查询 EXTRA 字段以获取 auto_increment 信息
 		query = `SELECT
@@ -4006,7 +4561,8 @@ ORDER BY c.ORDINAL_POSITION
 
 // checkTableColumn 检查表中是否存在指定字段
 func (mgr *dbManager) checkTableColumn(table, column string) bool {
-	if mgr.db == nil {
+	sdb := mgr.db.Load()
+	if sdb == nil {
 		return false
 	}
 
@@ -4016,8 +4572,8 @@ func (mgr *dbManager) checkTableColumn(table, column string) bool {
 		return false
 	}
 
-	// 统一使用 mgr.query() 方法查询所有列
-	records, err := mgr.query(mgr.db, query, args...)
+	// 统一使用 mgr.queryInternal() 方法查询所有列
+	records, err := mgr.queryInternal(sdb, query, args...)
 	if err != nil {
 		return false
 	}
@@ -4027,7 +4583,7 @@ func (mgr *dbManager) checkTableColumn(table, column string) bool {
 		var columnName interface{}
 
 		// 根据不同数据库获取列名字段
-		switch mgr.config.Driver {
+		switch mgr.config.Load().Driver {
 		case SQLite3:
 			// SQLite PRAGMA table_info 返回的列名字段是 "name"
 			columnName = record.Get("name")
@@ -4075,7 +4631,7 @@ type PoolStats struct {
 
 // PoolStats returns the connection pool statistics for the DB instance
 func (db *DB) PoolStats() *PoolStats {
-	if db.lastErr != nil || db.dbMgr == nil || db.dbMgr.db == nil {
+	if db.lastErr != nil || db.dbMgr == nil || db.dbMgr.db.Load() == nil {
 		return nil
 	}
 	return db.dbMgr.poolStats()
@@ -4083,14 +4639,18 @@ func (db *DB) PoolStats() *PoolStats {
 
 // poolStats returns the connection pool statistics
 func (mgr *dbManager) poolStats() *PoolStats {
-	if mgr == nil || mgr.db == nil {
+	if mgr == nil {
+		return nil
+	}
+	sdb := mgr.db.Load()
+	if sdb == nil {
 		return nil
 	}
 
-	stats := mgr.db.Stats()
+	stats := sdb.Stats()
 	return &PoolStats{
 		DBName:             mgr.name,
-		Driver:             string(mgr.config.Driver),
+		Driver:             string(mgr.config.Load().Driver),
 		MaxOpenConnections: stats.MaxOpenConnections,
 		OpenConnections:    stats.OpenConnections,
 		InUse:              stats.InUse,
@@ -4128,7 +4688,7 @@ func AllPoolStats() map[string]*PoolStats {
 	defer multiMgr.mu.RUnlock()
 
 	for name, mgr := range multiMgr.databases {
-		if mgr != nil && mgr.db != nil {
+		if mgr != nil && mgr.db.Load() != nil {
 			result[name] = mgr.poolStats()
 		}
 	}
@@ -4237,10 +4797,12 @@ func (mgr *dbManager) startConnectionMonitoring() error {
 	monitor := &ConnectionMonitor{
 		pinger:         mgr, // dbManager 实现了 DBPinger 接口
 		dbName:         mgr.name,
-		normalInterval: mgr.config.MonitorNormalInterval,
-		errorInterval:  mgr.config.MonitorErrorInterval,
+		normalInterval: mgr.config.Load().MonitorNormalInterval,
+		errorInterval:  mgr.config.Load().MonitorErrorInterval,
 		stopCh:         make(chan struct{}),
+		wakeCh:         make(chan struct{}, 1),
 		lastHealthy:    true, // 假设初始状态为健康
+		failover:       mgr.failover,
 	}
 
 	monitorsMu.Lock()
@@ -4275,6 +4837,16 @@ func (db *DB) GetAllTables() ([]string, error) {
 	return db.dbMgr.getAllTables()
 }
 
+// GetTableColumns 获取表的所有列信息（表结构元数据与事务无关，使用数据库连接查询）
+func (tx *Tx) GetTableColumns(table string) ([]ColumnInfo, error) {
+	return tx.dbMgr.getTableColumns(table)
+}
+
+// GetAllTables 获取数据库中所有表名
+func (tx *Tx) GetAllTables() ([]string, error) {
+	return tx.dbMgr.getAllTables()
+}
+
 // formatOracleTime 将 time.Time 格式化为 Oracle 可识别的字符串
 // 使用标准 ISO 8601 格式: YYYY-MM-DD HH24:MI:SS
 //
@@ -4298,6 +4870,7 @@ func (mgr *dbManager) wrapOracleDatePlaceholders(querySQL string, args []interfa
 	placeholderIndex := 0
 	result := strings.Builder{}
 	inString := false
+	escapedQuestion := false
 	var quoteChar rune
 
 	for i, char := range querySQL {
@@ -4319,7 +4892,17 @@ func (mgr *dbManager) wrapOracleDatePlaceholders(querySQL string, args []interfa
 			continue
 		}
 
-		// 处理占位符
+		// 处理占位符（?? 是转义的字面量 ?，原样保留给 convertPlaceholder 处理）
+		if escapedQuestion {
+			escapedQuestion = false
+			result.WriteRune(char)
+			continue
+		}
+		if char == '?' && i+1 < len(querySQL) && querySQL[i+1] == '?' {
+			escapedQuestion = true
+			result.WriteRune(char)
+			continue
+		}
 		if char == '?' {
 			if placeholderIndex < len(args) {
 				// 检查对应的参数是否是 time.Time
@@ -4361,7 +4944,7 @@ func (mgr *dbManager) warmUpColumnCache() {
 	// 2. 遍历预热
 	for _, table := range tables {
 		// 检查数据库是否已关闭
-		if mgr.db == nil {
+		if mgr.db.Load() == nil {
 			return
 		}
 
@@ -0,0 +1,50 @@
+package eorm
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestConvertPlaceholderQuestionEscape(t *testing.T) {
+	mgr := &dbManager{}
+	tests := []struct {
+		driver DriverType
+		in     string
+		want   string
+	}{
+		{PostgreSQL, "SELECT * FROM t WHERE data ?? 'a' AND id = ?", "SELECT * FROM t WHERE data ? 'a' AND id = $1"},
+		{PostgreSQL, "SELECT * FROM t WHERE data ??| array['a'] AND id = ? AND name = '??'", "SELECT * FROM t WHERE data ?| array['a'] AND id = $1 AND name = '??'"},
+		{SQLServer, "SELECT ?? , ?", "SELECT ? , @p1"},
+		{MySQL, "SELECT * FROM t WHERE a ?? b", "SELECT * FROM t WHERE a ?? b"},
+		{SQLite3, "SELECT * FROM t WHERE id = ?", "SELECT * FROM t WHERE id = ?"},
+	}
+	for _, tt := range tests {
+		if got := mgr.convertPlaceholder(tt.in, tt.driver); got != tt.want {
+			t.Errorf("convertPlaceholder(%q, %v) = %q, want %q", tt.in, tt.driver, got, tt.want)
+		}
+	}
+}
+
+func TestQuestionEscapeRejectedOnSQLite(t *testing.T) {
+	db := openTestDB(t, "CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT)")
+	mustExec(t, db, "INSERT INTO users (id, name) VALUES (1, 'a?')")
+
+	if _, err := db.Query("SELECT * FROM users WHERE name = 'a' || ?? AND id = ?", 1); !errors.Is(err, ErrQuestionEscapeUnsupported) {
+		t.Fatalf("Query error = %v, want ErrQuestionEscapeUnsupported", err)
+	}
+	if _, err := db.Exec("UPDATE users SET name = ?? WHERE id = ?", 1); !errors.Is(err, ErrQuestionEscapeUnsupported) {
+		t.Fatalf("Exec error = %v, want ErrQuestionEscapeUnsupported", err)
+	}
+	if _, err := db.Paginate(1, 10, "SELECT * FROM users WHERE name = ??"); !errors.Is(err, ErrQuestionEscapeUnsupported) {
+		t.Fatalf("Paginate error = %v, want ErrQuestionEscapeUnsupported", err)
+	}
+	if n := countRows(t, db, "users", "name = ?", "a?"); n != 1 {
+		t.Fatalf("row changed by rejected update: count = %d", n)
+	}
+
+	// 引号内的 ?? 是普通字符串内容
+	record, err := db.QueryFirst("SELECT '??' AS q FROM users WHERE id = ?", 1)
+	if err != nil || record.GetString("q") != "??" {
+		t.Fatalf("QueryFirst = %v, %v; want q = ??", record, err)
+	}
+}
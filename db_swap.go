@@ -0,0 +1,91 @@
+package eorm
+
+import (
+	"fmt"
+	"time"
+)
+
+// SwapDatabase 在不中断服务的情况下替换指定数据库的连接池，用于凭据轮换或切换到备用主机
+// 流程：按 newConfig 在后台打开新连接池并 Ping 验证 -> 原子替换 Use(name) 使用的连接 -> 关闭旧连接池
+// 替换前新连接验证失败时返回错误，旧连接保持不变；已在旧连接池上开始的查询和事务会继续执行完毕，之后旧连接才真正关闭
+// 软删除、时间戳、乐观锁等表级配置以及已获取的 *DB 对象都会保留，新配置的驱动类型必须与原配置一致
+// 示例:
+//
+//	cfg := *oldConfig
+//	cfg.DSN = "app:newpassword@tcp(standby:3306)/app"
+//	if err := eorm.SwapDatabase("default", &cfg); err != nil {
+//	    log.Println("切换失败，继续使用旧连接:", err)
+//	}
+func SwapDatabase(dbname string, newConfig *Config) error {
+	if newConfig == nil {
+		return fmt.Errorf("eorm: SwapDatabase config cannot be nil")
+	}
+	mgr := GetDatabase(dbname)
+	if mgr == nil {
+		return fmt.Errorf("database '%s' not found", dbname)
+	}
+
+	mgr.mu.RLock()
+	oldDriver := mgr.config.Load().Driver
+	mgr.mu.RUnlock()
+	if newConfig.Driver != oldDriver {
+		return fmt.Errorf("eorm: SwapDatabase cannot change driver of database '%s' from %s to %s", dbname, oldDriver, newConfig.Driver)
+	}
+
+	// 新连接池在替换前完成打开和验证，失败时不影响当前连接
+	newDB, err := openConnectionPool(newConfig)
+	if err != nil {
+		return fmt.Errorf("eorm: SwapDatabase failed to open new connection for database '%s': %w", dbname, err)
+	}
+	newStmtCache := newStmtCache(stmtCacheConfigFor(newConfig))
+
+	// initMu 防止与延迟初始化并发执行；连接池、配置和语句缓存通过原子指针发布，
+	// 查询无需加锁即可读取，先发布语句缓存和配置，读到新连接池的查询一定使用新的语句缓存
+	mgr.initMu.Lock()
+	mgr.mu.Lock()
+	oldStmtCache := mgr.stmtCache.Swap(newStmtCache)
+	mgr.config.Store(newConfig)
+	oldDB := mgr.db.Swap(newDB)
+	if newConfig.ConnMaxLifetime > 0 {
+		mgr.stmtCacheTTL = newConfig.ConnMaxLifetime / 2
+	} else {
+		mgr.stmtCacheTTL = 0
+	}
+	mgr.mu.Unlock()
+	mgr.initMu.Unlock()
+
+	// 监控器通过 dbManager 检查连接，替换后自动检查新连接，这里只同步检查间隔
+	if newConfig.MonitorNormalInterval > 0 {
+		errorInterval := newConfig.MonitorErrorInterval
+		if errorInterval <= 0 {
+			errorInterval = 10 * time.Second
+		}
+		if _, err := lookupMonitor(dbname); err != nil {
+			if err := mgr.startConnectionMonitoring(); err != nil {
+				LogWarn("连接监控启动失败", NewRecord().
+					Set("database", dbname).
+					Set("error", err.Error()))
+			}
+		}
+		_ = SetMonitorIntervals(dbname, newConfig.MonitorNormalInterval, errorInterval)
+	} else {
+		cleanupMonitor(dbname)
+	}
+
+	// 关闭旧连接池：sql.DB.Close 拒绝新的请求，并在使用中的连接归还后关闭它们
+	if oldStmtCache != nil {
+		oldStmtCache.Clear()
+	}
+	if oldDB != nil {
+		if err := oldDB.Close(); err != nil {
+			LogWarn("关闭旧连接池失败", NewRecord().
+				Set("database", dbname).
+				Set("error", err.Error()))
+		}
+	}
+
+	LogInfo("数据库连接已切换", NewRecord().
+		Set("database", dbname).
+		Set("time", time.Now()))
+	return nil
+}
@@ -0,0 +1,99 @@
+package eorm
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSwapDatabaseDuringQueries(t *testing.T) {
+	db := openTestDB(t, "CREATE TABLE sw_items (id INTEGER PRIMARY KEY, name TEXT)")
+	mustExec(t, db, "INSERT INTO sw_items (id, name) VALUES (1, 'old')")
+
+	// 备用库：结构相同、数据不同，用于确认切换后查询走新连接池
+	standby := createDefaultConfig(SQLite3, filepath.Join(t.TempDir(), "standby.db"), 4)
+	standby.MonitorNormalInterval = 0
+	standby.StmtCacheSize = 16
+	seed, err := openConnectionPool(standby)
+	if err != nil {
+		t.Fatalf("open standby: %v", err)
+	}
+	if _, err := seed.Exec("CREATE TABLE sw_items (id INTEGER PRIMARY KEY, name TEXT)"); err != nil {
+		t.Fatalf("create standby table: %v", err)
+	}
+	if _, err := seed.Exec("INSERT INTO sw_items (id, name) VALUES (1, 'new')"); err != nil {
+		t.Fatalf("seed standby: %v", err)
+	}
+	seed.Close()
+
+	primary := *db.dbMgr.config.Load()
+	primary.StmtCacheSize = 16
+
+	var stop atomic.Bool
+	var queries atomic.Int64
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for !stop.Load() {
+				if _, err := db.Query("SELECT name FROM sw_items WHERE id = ?", 1); err != nil {
+					// 切换时旧连接池被关闭，正在获取连接的查询可能收到该错误
+					if !strings.Contains(err.Error(), "database is closed") {
+						select {
+						case errs <- err:
+						default:
+						}
+						return
+					}
+				}
+				queries.Add(1)
+			}
+		}()
+	}
+
+	// 每次切换前等待查询推进，保证切换与查询交错进行
+	waitQueries := func() {
+		target := queries.Load() + 20
+		deadline := time.Now().Add(5 * time.Second)
+		for queries.Load() < target && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+	}
+	for i := 0; i < 10; i++ {
+		waitQueries()
+		cfg := primary
+		if i%2 == 0 {
+			cfg = *standby
+		}
+		if err := SwapDatabase(db.dbMgr.name, &cfg); err != nil {
+			t.Fatalf("SwapDatabase #%d: %v", i, err)
+		}
+	}
+	stop.Store(true)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("query during swap: %v", err)
+	}
+	if queries.Load() == 0 {
+		t.Fatal("no queries ran during the swaps")
+	}
+
+	// 最后一次切换回主库
+	record, err := db.QueryFirst("SELECT name FROM sw_items WHERE id = ?", 1)
+	if err != nil || record.GetString("name") != "old" {
+		t.Fatalf("QueryFirst after swaps = %v, %v; want old", record, err)
+	}
+	if err := SwapDatabase(db.dbMgr.name, standby); err != nil {
+		t.Fatalf("SwapDatabase to standby: %v", err)
+	}
+	record, err = db.QueryFirst("SELECT name FROM sw_items WHERE id = ?", 1)
+	if err != nil || record.GetString("name") != "new" {
+		t.Fatalf("QueryFirst on standby = %v, %v; want new", record, err)
+	}
+}
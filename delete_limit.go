@@ -0,0 +1,210 @@
+package eorm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// deleteLimit 物理删除最多 limit 条满足条件的记录（可指定删除顺序）
+// 各数据库的实现：
+//   - MySQL: DELETE ... ORDER BY ... LIMIT n
+//   - PostgreSQL: DELETE ... WHERE ctid IN (SELECT ctid ... LIMIT n)
+//   - SQLite: DELETE ... WHERE rowid IN (SELECT rowid ... LIMIT n)
+//   - Oracle: DELETE ... WHERE ROWID IN (SELECT ROWID ... WHERE ROWNUM <= n)
+//   - SQL Server: DELETE TOP (n) ... 或带 ORDER BY 的 CTE
+func (mgr *dbManager) deleteLimit(ctx context.Context, executor sqlExecutor, table, where, orderBy string, limit int, whereArgs ...interface{}) (_ int64, err error) {
+	defer func() { mgr.afterTableWrite(executor, table, err) }()
+	if err := validateIdentifier(table); err != nil {
+		return 0, err
+	}
+	if where == "" {
+		return 0, fmt.Errorf("where condition is required for delete")
+	}
+	if limit <= 0 {
+		return 0, fmt.Errorf("eorm: delete limit must be greater than 0")
+	}
+	if mgr.hasSoftDelete(table) {
+		return 0, fmt.Errorf("eorm: table %s has soft delete configured, DeleteLimit/BatchDeleteWhere only support physical delete", table)
+	}
+	if mgr.deleteLimitNeedsKeys(table) {
+		if n, handled, keyErr := mgr.deleteLimitByKeys(ctx, executor, table, where, orderBy, limit, whereArgs); handled {
+			return n, keyErr
+		}
+	}
+
+	querySQL, err := mgr.buildDeleteLimitSQL(table, where, orderBy, limit)
+	if err != nil {
+		return 0, err
+	}
+	querySQL, whereArgs = mgr.prepareQuerySQL(querySQL, whereArgs...)
+
+	start := time.Now()
+	result, err := mgr.execWrite(ctx, executor, querySQL, whereArgs...)
+	mgr.logTrace(start, querySQL, whereArgs, err)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// deleteLimitNeedsKeys 判断限量删除是否需要先确定被删除的行
+// 表有变更订阅者时需要按行发布删除事件，表有计数缓存时需要按被删除行的外键减少父表计数
+func (mgr *dbManager) deleteLimitNeedsKeys(table string) bool {
+	return mgr.hasChangeSubscribers(table) || len(mgr.getCounterCacheConfigs(table)) > 0
+}
+
+// deleteLimitByKeys 先按条件和顺序查询最多 limit 行，再按主键删除这些行，
+// 删除经过 delete 的完整流程（变更事件、计数缓存等）；查询和删除在同一事务中执行
+// 表没有主键时 handled 为 false，由调用方直接执行限量删除（有计数缓存时返回错误）
+func (mgr *dbManager) deleteLimitByKeys(ctx context.Context, executor sqlExecutor, table, where, orderBy string, limit int, whereArgs []interface{}) (n int64, handled bool, err error) {
+	pks, err := mgr.getPrimaryKeys(executor, table)
+	if err != nil {
+		return 0, true, err
+	}
+	if len(pks) == 0 {
+		if len(mgr.getCounterCacheConfigs(table)) > 0 {
+			return 0, true, fmt.Errorf("eorm: DeleteLimit/BatchDeleteWhere on %s requires a primary key to maintain counter caches", table)
+		}
+		return 0, false, nil
+	}
+	run := func(tx sqlExecutor) (int64, error) {
+		querySQL := fmt.Sprintf("SELECT %s FROM %s WHERE %s", strings.Join(pks, ", "), table, where)
+		if orderBy != "" {
+			querySQL += " ORDER BY " + orderBy
+		}
+		querySQL += fmt.Sprintf(" LIMIT %d", limit)
+		rows, err := mgr.queryInternal(tx, querySQL, whereArgs...)
+		if err != nil {
+			return 0, err
+		}
+		keyWhere, keyArgs := recordKeyConditions(pks, rows)
+		if keyWhere == "" {
+			return 0, nil
+		}
+		return mgr.delete(ctx, tx, table, keyWhere, keyArgs...)
+	}
+	if n, handled, err := withWriteTx(mgr, executor, run); handled {
+		return n, true, err
+	}
+	n, err = run(executor)
+	return n, true, err
+}
+
+// buildDeleteLimitSQL 按数据库类型构造带数量限制的 DELETE 语句
+func (mgr *dbManager) buildDeleteLimitSQL(table, where, orderBy string, limit int) (string, error) {
+	orderClause := ""
+	if orderBy != "" {
+		orderClause = " ORDER BY " + orderBy
+	}
+
+	switch mgr.config.Load().Driver {
+	case MySQL:
+		return fmt.Sprintf("DELETE FROM %s WHERE %s%s LIMIT %d", table, where, orderClause, limit), nil
+	case PostgreSQL:
+		return fmt.Sprintf("DELETE FROM %s WHERE ctid IN (SELECT ctid FROM %s WHERE %s%s LIMIT %d)", table, table, where, orderClause, limit), nil
+	case SQLite3:
+		return fmt.Sprintf("DELETE FROM %s WHERE rowid IN (SELECT rowid FROM %s WHERE %s%s LIMIT %d)", table, table, where, orderClause, limit), nil
+	case Oracle:
+		if orderBy == "" {
+			return fmt.Sprintf("DELETE FROM %s WHERE (%s) AND ROWNUM <= %d", table, where, limit), nil
+		}
+		return fmt.Sprintf("DELETE FROM %s WHERE ROWID IN (SELECT rid FROM (SELECT ROWID AS rid FROM %s WHERE %s%s) WHERE ROWNUM <= %d)", table, table, where, orderClause, limit), nil
+	case SQLServer:
+		if orderBy == "" {
+			return fmt.Sprintf("DELETE TOP (%d) FROM %s WHERE %s", limit, table, where), nil
+		}
+		return fmt.Sprintf("WITH eorm_delete_cte AS (SELECT TOP (%d) * FROM %s WHERE %s%s) DELETE FROM eorm_delete_cte", limit, table, where, orderClause), nil
+	default:
+		return "", fmt.Errorf("eorm: DeleteLimit is not supported for driver %s", mgr.config.Load().Driver)
+	}
+}
+
+// batchDeleteWhere 分批物理删除满足条件的记录，每批最多 batchSize 条，直到没有可删除的记录
+func (mgr *dbManager) batchDeleteWhere(ctx context.Context, executor sqlExecutor, table, where string, batchSize int, whereArgs ...interface{}) (int64, error) {
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	var total int64
+	for {
+		affected, err := mgr.deleteLimit(ctx, executor, table, where, "", batchSize, whereArgs...)
+		total += affected
+		if err != nil {
+			return total, err
+		}
+		if affected < int64(batchSize) {
+			return total, nil
+		}
+	}
+}
+
+// DeleteLimit 物理删除最多 n 条满足条件的记录，使用构建器中的 OrderBy 决定删除顺序
+// 不支持配置了软删除的表
+// 示例: eorm.Table("logs").Where("created_at < ?", cutoff).OrderBy("id").DeleteLimit(1000)
+func (qb *QueryBuilder) DeleteLimit(n int) (int64, error) {
+	if qb.lastErr != nil {
+		return 0, qb.lastErr
+	}
+	if qb.table == "" {
+		return 0, fmt.Errorf("eorm: table name is required for DeleteLimit")
+	}
+	if len(qb.whereSql) == 0 {
+		return 0, fmt.Errorf("eorm: DeleteLimit operation requires at least one Where condition for safety")
+	}
+	if err := qb.validateQueryBuilderState(); err != nil {
+		return 0, err
+	}
+
+	whereSql := strings.Join(qb.whereSql, " AND ")
+	if qb.tx != nil {
+		whereSql, whereArgs := applyRowFilter(qb.tx.ctx, qb.table, whereSql, qb.whereArgs, true)
+		return qb.tx.dbMgr.deleteLimit(qb.tx.ctx, qb.tx.tx, qb.table, whereSql, qb.orderBy, n, whereArgs...)
+	}
+	executor, err := qb.db.getExecutor()
+	if err != nil {
+		return 0, err
+	}
+	whereSql, whereArgs := applyRowFilter(qb.db.ctx, qb.table, whereSql, qb.whereArgs, true)
+	return qb.db.dbMgr.deleteLimit(qb.db.ctx, executor, qb.table, whereSql, qb.orderBy, n, whereArgs...)
+}
+
+// BatchDeleteWhere 分批物理删除满足条件的记录，避免一次性大删除导致长时间锁表和巨大的 undo 日志
+// 每批最多删除 batchSize 条（<= 0 时使用 DefaultBatchSize），返回删除的总行数
+// 不支持配置了软删除的表
+// 示例: eorm.BatchDeleteWhere("logs", "created_at < ?", []interface{}{cutoff}, 5000)
+func BatchDeleteWhere(table, whereSql string, whereArgs []interface{}, batchSize int) (int64, error) {
+	db, err := defaultDB()
+	if err != nil {
+		return 0, err
+	}
+	return db.BatchDeleteWhere(table, whereSql, whereArgs, batchSize)
+}
+
+// BatchDeleteWhere 分批物理删除满足条件的记录
+// 每批在各自的语句中提交（非事务时），大表清理期间不会长时间持有锁
+func (db *DB) BatchDeleteWhere(table, whereSql string, whereArgs []interface{}, batchSize int) (int64, error) {
+	if db.lastErr != nil {
+		return 0, db.lastErr
+	}
+	whereSql, whereArgs = applyRowFilter(db.ctx, table, whereSql, whereArgs, true)
+	executor, err := db.getExecutor()
+	if err != nil {
+		return 0, err
+	}
+	rows, err := db.dbMgr.batchDeleteWhere(db.ctx, executor, table, whereSql, batchSize, whereArgs...)
+	if rows > 0 && db.cacheRepositoryName != "" {
+		db.ClearCache(db.cacheRepositoryName)
+	}
+	return rows, err
+}
+
+// BatchDeleteWhere 在事务中分批物理删除满足条件的记录
+func (tx *Tx) BatchDeleteWhere(table, whereSql string, whereArgs []interface{}, batchSize int) (int64, error) {
+	whereSql, whereArgs = applyRowFilter(tx.ctx, table, whereSql, whereArgs, true)
+	rows, err := tx.dbMgr.batchDeleteWhere(tx.ctx, tx.tx, table, whereSql, batchSize, whereArgs...)
+	if rows > 0 && tx.cacheRepositoryName != "" {
+		tx.ClearCache(tx.cacheRepositoryName)
+	}
+	return rows, err
+}
@@ -0,0 +1,465 @@
+package eorm
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+// ConnectorOptions WrapConnector 的选项
+type ConnectorOptions struct {
+	Name          string        // 日志、SQL 捕获和指标中的数据库名称（默认 "wrapped"）
+	StmtCacheSize int           // 每个连接缓存的预编译语句数，Query/Exec 自动复用（0 表示不缓存）
+	SlowThreshold time.Duration // 超过该耗时的语句记录警告日志（0 表示不记录）
+}
+
+// ConnectorStats 包装后连接上执行的语句统计
+type ConnectorStats struct {
+	Name          string        // 数据库名称
+	Queries       uint64        // 查询次数
+	Execs         uint64        // 写操作次数
+	Errors        uint64        // 失败次数
+	SlowQueries   uint64        // 慢语句次数
+	CacheHits     uint64        // 预编译语句缓存命中次数
+	CacheMisses   uint64        // 预编译语句缓存未命中次数
+	TotalDuration time.Duration // 累计执行耗时
+}
+
+// Connector 包装 driver.Connector，为直接使用 database/sql 的代码提供 eorm 的预编译语句缓存、SQL 日志、慢查询日志、SQL 捕获和指标
+type Connector struct {
+	inner driver.Connector
+	opts  ConnectorOptions
+
+	queries     uint64
+	execs       uint64
+	errors      uint64
+	slowQueries uint64
+	cacheHits   uint64
+	cacheMisses uint64
+	totalNanos  int64
+}
+
+// WrapConnector 包装 driver.Connector，返回的 Connector 可以交给 sql.OpenDB
+// 示例:
+//
+//	connector, _ := mysql.NewConnector(cfg)
+//	wrapped := eorm.WrapConnector(connector, &eorm.ConnectorOptions{Name: "legacy", StmtCacheSize: 200, SlowThreshold: 200 * time.Millisecond})
+//	db := sql.OpenDB(wrapped)   // 现有代码继续使用 *sql.DB
+//	log.Println(wrapped.Stats())
+func WrapConnector(connector driver.Connector, opts *ConnectorOptions) *Connector {
+	c := &Connector{inner: connector}
+	if opts != nil {
+		c.opts = *opts
+	}
+	if c.opts.Name == "" {
+		c.opts.Name = "wrapped"
+	}
+	return c
+}
+
+// OpenWrapped 按驱动名称和 DSN 打开 *sql.DB，连接经过 WrapConnector 包装
+// 示例: db, connector, err := eorm.OpenWrapped("mysql", dsn, &eorm.ConnectorOptions{StmtCacheSize: 100})
+func OpenWrapped(driverName, dsn string, opts *ConnectorOptions) (*sql.DB, *Connector, error) {
+	// sql.Open 不会建立连接，这里只用于取得已注册的驱动
+	probe, err := sql.Open(driverName, dsn)
+	if err != nil {
+		return nil, nil, err
+	}
+	drv := probe.Driver()
+	probe.Close()
+
+	var base driver.Connector = dsnConnector{dsn: dsn, drv: drv}
+	if dc, ok := drv.(driver.DriverContext); ok {
+		if base, err = dc.OpenConnector(dsn); err != nil {
+			return nil, nil, err
+		}
+	}
+	wrapped := WrapConnector(base, opts)
+	return sql.OpenDB(wrapped), wrapped, nil
+}
+
+// Connect 建立连接
+func (c *Connector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.inner.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.wrapConn(conn), nil
+}
+
+// Driver 返回包装后的驱动
+func (c *Connector) Driver() driver.Driver {
+	return wrappedDriver{c}
+}
+
+// Close 关闭被包装的 Connector（如果它实现了 io.Closer），由 sql.DB.Close 调用
+func (c *Connector) Close() error {
+	if closer, ok := c.inner.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// Stats 返回语句统计
+func (c *Connector) Stats() ConnectorStats {
+	return ConnectorStats{
+		Name:          c.opts.Name,
+		Queries:       atomic.LoadUint64(&c.queries),
+		Execs:         atomic.LoadUint64(&c.execs),
+		Errors:        atomic.LoadUint64(&c.errors),
+		SlowQueries:   atomic.LoadUint64(&c.slowQueries),
+		CacheHits:     atomic.LoadUint64(&c.cacheHits),
+		CacheMisses:   atomic.LoadUint64(&c.cacheMisses),
+		TotalDuration: time.Duration(atomic.LoadInt64(&c.totalNanos)),
+	}
+}
+
+// PrometheusMetrics returns Prometheus-compatible metrics string
+func (c *Connector) PrometheusMetrics() string {
+	s := c.Stats()
+	label := fmt.Sprintf(`db="%s"`, s.Name)
+	return fmt.Sprintf(`# HELP eorm_wrapped_queries_total The number of queries executed through the wrapped connector.
+# TYPE eorm_wrapped_queries_total counter
+eorm_wrapped_queries_total{%s} %d
+
+# HELP eorm_wrapped_execs_total The number of statements executed through the wrapped connector.
+# TYPE eorm_wrapped_execs_total counter
+eorm_wrapped_execs_total{%s} %d
+
+# HELP eorm_wrapped_errors_total The number of failed statements.
+# TYPE eorm_wrapped_errors_total counter
+eorm_wrapped_errors_total{%s} %d
+
+# HELP eorm_wrapped_slow_total The number of statements slower than the slow threshold.
+# TYPE eorm_wrapped_slow_total counter
+eorm_wrapped_slow_total{%s} %d
+
+# HELP eorm_wrapped_stmt_cache_hits_total The number of prepared statement cache hits.
+# TYPE eorm_wrapped_stmt_cache_hits_total counter
+eorm_wrapped_stmt_cache_hits_total{%s} %d
+
+# HELP eorm_wrapped_stmt_cache_misses_total The number of prepared statement cache misses.
+# TYPE eorm_wrapped_stmt_cache_misses_total counter
+eorm_wrapped_stmt_cache_misses_total{%s} %d
+
+# HELP eorm_wrapped_duration_seconds_total The total time spent executing statements.
+# TYPE eorm_wrapped_duration_seconds_total counter
+eorm_wrapped_duration_seconds_total{%s} %f
+`,
+		label, s.Queries,
+		label, s.Execs,
+		label, s.Errors,
+		label, s.SlowQueries,
+		label, s.CacheHits,
+		label, s.CacheMisses,
+		label, s.TotalDuration.Seconds(),
+	)
+}
+
+// observe 记录一条语句的日志、捕获和指标
+func (c *Connector) observe(start time.Time, query string, args []driver.NamedValue, isQuery bool, err error) {
+	if errors.Is(err, driver.ErrSkip) {
+		return
+	}
+	duration := time.Since(start)
+	if isQuery {
+		atomic.AddUint64(&c.queries, 1)
+	} else {
+		atomic.AddUint64(&c.execs, 1)
+	}
+	atomic.AddInt64(&c.totalNanos, int64(duration))
+
+	values := make([]interface{}, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+	if err != nil {
+		atomic.AddUint64(&c.errors, 1)
+		LogSQLError(c.opts.Name, query, formatArgsForLog(values), duration, err)
+	} else {
+		LogSQL(c.opts.Name, query, formatArgsForLog(values), duration)
+		if c.opts.SlowThreshold > 0 && duration >= c.opts.SlowThreshold {
+			atomic.AddUint64(&c.slowQueries, 1)
+			LogWarn("慢查询", NewRecord().
+				Set("db", c.opts.Name).
+				Set("sql", cleanSQL(query)).
+				Set("duration", duration.String()))
+		}
+	}
+	captureSQL(c.opts.Name, query, values, duration, err)
+}
+
+// wrappedDriver 包装后的驱动，Open 返回包装后的连接
+type wrappedDriver struct {
+	c *Connector
+}
+
+func (d wrappedDriver) Open(name string) (driver.Conn, error) {
+	conn, err := d.c.inner.Driver().Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return d.c.wrapConn(conn), nil
+}
+
+// cachedStmt 连接上缓存的预编译语句
+type cachedStmt struct {
+	stmt  driver.Stmt
+	query string
+	elem  *list.Element
+}
+
+// wrappedConn 包装的连接；database/sql 保证同一连接不会被并发使用，因此语句缓存不需要加锁
+type wrappedConn struct {
+	conn    driver.Conn
+	c       *Connector
+	stmts   map[string]*cachedStmt
+	lru     *list.List
+	retired []driver.Stmt // 被淘汰的语句，可能仍有未关闭的结果集，在连接复用前关闭
+}
+
+func (c *Connector) wrapConn(conn driver.Conn) *wrappedConn {
+	w := &wrappedConn{conn: conn, c: c}
+	if c.opts.StmtCacheSize > 0 {
+		w.stmts = make(map[string]*cachedStmt)
+		w.lru = list.New()
+	}
+	return w
+}
+
+// Prepare 预编译语句
+func (w *wrappedConn) Prepare(query string) (driver.Stmt, error) {
+	return w.PrepareContext(context.Background(), query)
+}
+
+// PrepareContext 预编译语句，返回的语句执行时同样记录日志和指标
+func (w *wrappedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	stmt, err := w.prepare(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return &wrappedStmt{stmt: stmt, query: query, c: w.c}, nil
+}
+
+func (w *wrappedConn) prepare(ctx context.Context, query string) (driver.Stmt, error) {
+	if p, ok := w.conn.(driver.ConnPrepareContext); ok {
+		return p.PrepareContext(ctx, query)
+	}
+	return w.conn.Prepare(query)
+}
+
+// Close 关闭缓存的语句和连接
+func (w *wrappedConn) Close() error {
+	w.closeRetired()
+	for _, cs := range w.stmts {
+		cs.stmt.Close()
+	}
+	w.stmts = nil
+	return w.conn.Close()
+}
+
+// Begin 开始事务
+func (w *wrappedConn) Begin() (driver.Tx, error) {
+	return w.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+// BeginTx 开始事务
+func (w *wrappedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if b, ok := w.conn.(driver.ConnBeginTx); ok {
+		return b.BeginTx(ctx, opts)
+	}
+	if opts.Isolation != driver.IsolationLevel(sql.LevelDefault) || opts.ReadOnly {
+		return nil, fmt.Errorf("eorm: driver does not support transaction options")
+	}
+	return w.conn.Begin() // 驱动未实现 ConnBeginTx 时的回退
+}
+
+// QueryContext 执行查询；开启语句缓存时复用预编译语句，否则交给驱动（驱动不支持时由 database/sql 回退到预编译）
+func (w *wrappedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	start := time.Now()
+	var rows driver.Rows
+	var err error
+	if stmt := w.cachedStmt(ctx, query); stmt != nil {
+		rows, err = stmtQuery(ctx, stmt, args)
+	} else if q, ok := w.conn.(driver.QueryerContext); ok {
+		rows, err = q.QueryContext(ctx, query, args)
+	} else {
+		return nil, driver.ErrSkip
+	}
+	w.c.observe(start, query, args, true, err)
+	return rows, err
+}
+
+// ExecContext 执行写操作，语句缓存规则同 QueryContext
+func (w *wrappedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	start := time.Now()
+	var result driver.Result
+	var err error
+	if stmt := w.cachedStmt(ctx, query); stmt != nil {
+		result, err = stmtExec(ctx, stmt, args)
+	} else if e, ok := w.conn.(driver.ExecerContext); ok {
+		result, err = e.ExecContext(ctx, query, args)
+	} else {
+		return nil, driver.ErrSkip
+	}
+	w.c.observe(start, query, args, false, err)
+	return result, err
+}
+
+// cachedStmt 返回缓存的预编译语句，未开启缓存或预编译失败时返回 nil
+func (w *wrappedConn) cachedStmt(ctx context.Context, query string) driver.Stmt {
+	if w.stmts == nil {
+		return nil
+	}
+	if cs, ok := w.stmts[query]; ok {
+		w.lru.MoveToFront(cs.elem)
+		atomic.AddUint64(&w.c.cacheHits, 1)
+		return cs.stmt
+	}
+	atomic.AddUint64(&w.c.cacheMisses, 1)
+	stmt, err := w.prepare(ctx, query)
+	if err != nil {
+		return nil
+	}
+	cs := &cachedStmt{stmt: stmt, query: query}
+	cs.elem = w.lru.PushFront(cs)
+	w.stmts[query] = cs
+	for w.lru.Len() > w.c.opts.StmtCacheSize {
+		oldest := w.lru.Remove(w.lru.Back()).(*cachedStmt)
+		delete(w.stmts, oldest.query)
+		w.retired = append(w.retired, oldest.stmt)
+	}
+	return stmt
+}
+
+// closeRetired 关闭被淘汰的语句
+func (w *wrappedConn) closeRetired() {
+	for _, stmt := range w.retired {
+		stmt.Close()
+	}
+	w.retired = nil
+}
+
+// Ping 检查连接
+func (w *wrappedConn) Ping(ctx context.Context) error {
+	if p, ok := w.conn.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+// ResetSession 连接复用前调用，此时连接上没有未关闭的结果集，可以安全关闭被淘汰的语句
+func (w *wrappedConn) ResetSession(ctx context.Context) error {
+	w.closeRetired()
+	if r, ok := w.conn.(driver.SessionResetter); ok {
+		return r.ResetSession(ctx)
+	}
+	return nil
+}
+
+// IsValid 连接是否可以继续使用
+func (w *wrappedConn) IsValid() bool {
+	if v, ok := w.conn.(driver.Validator); ok {
+		return v.IsValid()
+	}
+	return true
+}
+
+// CheckNamedValue 交给驱动转换参数，驱动未实现时使用 database/sql 的默认转换
+func (w *wrappedConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if c, ok := w.conn.(driver.NamedValueChecker); ok {
+		return c.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+// wrappedStmt 通过 Prepare 得到的语句，执行时记录日志和指标
+type wrappedStmt struct {
+	stmt  driver.Stmt
+	query string
+	c     *Connector
+}
+
+func (s *wrappedStmt) Close() error  { return s.stmt.Close() }
+func (s *wrappedStmt) NumInput() int { return s.stmt.NumInput() }
+
+func (s *wrappedStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), valuesToNamed(args))
+}
+
+func (s *wrappedStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), valuesToNamed(args))
+}
+
+func (s *wrappedStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	start := time.Now()
+	result, err := stmtExec(ctx, s.stmt, args)
+	s.c.observe(start, s.query, args, false, err)
+	return result, err
+}
+
+func (s *wrappedStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	start := time.Now()
+	rows, err := stmtQuery(ctx, s.stmt, args)
+	s.c.observe(start, s.query, args, true, err)
+	return rows, err
+}
+
+// CheckNamedValue 交给驱动的语句转换参数
+func (s *wrappedStmt) CheckNamedValue(nv *driver.NamedValue) error {
+	if c, ok := s.stmt.(driver.NamedValueChecker); ok {
+		return c.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+// stmtQuery 优先使用语句的 QueryContext
+func stmtQuery(ctx context.Context, stmt driver.Stmt, args []driver.NamedValue) (driver.Rows, error) {
+	if q, ok := stmt.(driver.StmtQueryContext); ok {
+		return q.QueryContext(ctx, args)
+	}
+	values, err := namedToValues(args)
+	if err != nil {
+		return nil, err
+	}
+	return stmt.Query(values) // 驱动未实现 StmtQueryContext 时的回退
+}
+
+// stmtExec 优先使用语句的 ExecContext
+func stmtExec(ctx context.Context, stmt driver.Stmt, args []driver.NamedValue) (driver.Result, error) {
+	if e, ok := stmt.(driver.StmtExecContext); ok {
+		return e.ExecContext(ctx, args)
+	}
+	values, err := namedToValues(args)
+	if err != nil {
+		return nil, err
+	}
+	return stmt.Exec(values) // 驱动未实现 StmtExecContext 时的回退
+}
+
+// namedToValues 转换为旧接口的参数，不支持命名参数
+func namedToValues(args []driver.NamedValue) ([]driver.Value, error) {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		if arg.Name != "" {
+			return nil, fmt.Errorf("eorm: driver does not support named parameters")
+		}
+		values[i] = arg.Value
+	}
+	return values, nil
+}
+
+// valuesToNamed 转换为按位置编号的参数
+func valuesToNamed(args []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, len(args))
+	for i, v := range args {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: v}
+	}
+	return named
+}
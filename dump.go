@@ -0,0 +1,547 @@
+package eorm
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DumpFormat 逻辑备份的格式
+type DumpFormat int
+
+const (
+	DumpSQL DumpFormat = iota // SQL 脚本：CREATE TABLE + 每行一条 INSERT，可用 RestoreDump 恢复
+	DumpCSV                   // ZIP 包：schema.sql + 每张表一个 <table>.csv（首行为列名，NULL 写为 \N，二进制值写为 \x 加十六进制，以 \ 开头的文本再加一个 \ 转义），可用 RestoreDumpCSV 恢复
+)
+
+// CSV 字段的转义：NULL 写为 csvNull，二进制值写为 csvBinaryPrefix 加十六进制，以 \ 开头的文本前再加一个 \
+const (
+	csvNull         = `\N`
+	csvBinaryPrefix = `\x`
+)
+
+// DumpOptions Dump 的选项
+type DumpOptions struct {
+	Format     DumpFormat // 输出格式（默认 DumpSQL）
+	SchemaOnly bool       // 只导出表结构
+	DataOnly   bool       // 只导出数据
+}
+
+// BackupTo 将默认数据库（SQLite）备份到文件，见 DB.BackupTo
+func BackupTo(path string) error {
+	db, err := defaultDB()
+	if err != nil {
+		return err
+	}
+	return db.BackupTo(path)
+}
+
+// BackupTo 使用 VACUUM INTO 生成 SQLite 数据库的一致性副本（需要 SQLite 3.27+），备份期间不阻塞读写
+// 目标文件必须不存在；其他数据库请使用 Dump 生成逻辑备份
+// 示例:
+//
+//	err := eorm.Use("local").BackupTo("backup/app-20260102.db")
+func (db *DB) BackupTo(path string) error {
+	if db.lastErr != nil {
+		return db.lastErr
+	}
+	if db.dbMgr.config.Load().Driver != SQLite3 {
+		return fmt.Errorf("eorm: BackupTo only supports SQLite, use Dump for %s", db.dbMgr.config.Load().Driver)
+	}
+	if path == "" {
+		return fmt.Errorf("eorm: BackupTo requires a file path")
+	}
+	_, err := db.Exec("VACUUM INTO " + quoteStandardString(path))
+	return err
+}
+
+// Dump 将表结构和数据导出为可移植的逻辑备份，tables 为空时导出全部表
+// 表结构：MySQL 使用 SHOW CREATE TABLE，SQLite 使用 sqlite_master 中的原始语句（含索引），
+// Oracle 使用 DBMS_METADATA.GET_DDL，PostgreSQL 和 SQL Server 根据列信息生成（只包含列、主键和自增，不含索引、外键和默认值）
+// 数据按表逐行读取写出，不会整表载入内存；导出不在同一事务中进行，写入频繁的库请在维护窗口执行
+// 示例:
+//
+//	f, _ := os.Create("backup.sql")
+//	defer f.Close()
+//	err := eorm.Dump(eorm.Use("default"), nil, f, nil)
+//	// 恢复: eorm.RestoreDump(eorm.Use("staging"), file)
+func Dump(db *DB, tables []string, w io.Writer, opts *DumpOptions) error {
+	if db == nil || db.dbMgr == nil {
+		return fmt.Errorf("eorm: Dump requires a database")
+	}
+	if db.lastErr != nil {
+		return db.lastErr
+	}
+	if opts == nil {
+		opts = &DumpOptions{}
+	}
+	mgr := db.dbMgr
+	if len(tables) == 0 {
+		all, err := mgr.getAllTables()
+		if err != nil {
+			return err
+		}
+		tables = all
+	}
+	for _, table := range tables {
+		if err := validateIdentifier(table); err != nil {
+			return err
+		}
+	}
+
+	if opts.Format == DumpCSV {
+		return mgr.dumpCSVBundle(tables, w, opts)
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "-- eorm dump of %s (%s) at %s\n", mgr.name, mgr.config.Load().Driver, time.Now().Format(time.RFC3339))
+	if !opts.DataOnly {
+		if err := mgr.dumpSchema(tables, bw); err != nil {
+			return err
+		}
+	}
+	if !opts.SchemaOnly {
+		for _, table := range tables {
+			fmt.Fprintf(bw, "\n-- data: %s\n", table)
+			err := mgr.dumpRows(table, func(columns []string, values []interface{}) error {
+				literals := make([]string, len(values))
+				for i, v := range values {
+					literals[i] = mgr.sqlLiteral(v)
+				}
+				_, err := fmt.Fprintf(bw, "INSERT INTO %s (%s) VALUES (%s);\n", table, strings.Join(columns, ", "), strings.Join(literals, ", "))
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("eorm: failed to dump table %s: %w", table, err)
+			}
+		}
+	}
+	return bw.Flush()
+}
+
+// RestoreDump 执行 Dump 生成的 SQL 脚本（DumpSQL 格式），所有语句在同一事务中执行
+// MySQL、Oracle 的 CREATE TABLE 会隐式提交，失败时已建的表不会回滚
+func RestoreDump(db *DB, r io.Reader) error {
+	if db == nil || db.dbMgr == nil {
+		return fmt.Errorf("eorm: RestoreDump requires a database")
+	}
+	return db.Transaction(func(tx *Tx) error {
+		_, err := tx.ExecScript(r)
+		return err
+	})
+}
+
+// RestoreDumpCSV 从 Dump 生成的 CSV 包（DumpCSV 格式）恢复数据，createTables 为 true 时先执行包中的 schema.sql
+// 每张表的数据在一个事务中按 CSV 首行的列逐行插入，\N 还原为 NULL，\x 开头的字段还原为二进制值，
+// 其他字段以文本写入（启用 EnableTypeCoercion 时按目标列类型转换）
+// 示例:
+//
+//	f, _ := os.Open("backup.zip")
+//	info, _ := f.Stat()
+//	err := eorm.RestoreDumpCSV(eorm.Use("staging"), f, info.Size(), true)
+func RestoreDumpCSV(db *DB, r io.ReaderAt, size int64, createTables bool) error {
+	if db == nil || db.dbMgr == nil {
+		return fmt.Errorf("eorm: RestoreDumpCSV requires a database")
+	}
+	if db.lastErr != nil {
+		return db.lastErr
+	}
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return fmt.Errorf("eorm: invalid CSV bundle: %w", err)
+	}
+	for _, f := range zr.File {
+		if f.Name != "schema.sql" || !createTables {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		_, err = db.ExecScript(rc)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	for _, f := range zr.File {
+		table, ok := strings.CutSuffix(f.Name, ".csv")
+		if !ok {
+			continue
+		}
+		if err := validateIdentifier(table); err != nil {
+			return err
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		err = restoreCSVTable(db, table, rc)
+		rc.Close()
+		if err != nil {
+			return fmt.Errorf("eorm: failed to restore table %s: %w", table, err)
+		}
+	}
+	return nil
+}
+
+// restoreCSVTable 读取一张表的 CSV 并在一个事务中逐行插入
+// 每行都写入首行列出的全部列，NULL 显式写入，不会被列默认值或时间戳替换
+func restoreCSVTable(db *DB, table string, r io.Reader) error {
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, col := range header {
+		if err := validateIdentifier(col); err != nil {
+			return err
+		}
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(header)), ", ")
+	insertSQL := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", table, strings.Join(header, ", "), placeholders)
+
+	return db.Transaction(func(tx *Tx) error {
+		for {
+			row, err := cr.Read()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			record := NewRecord()
+			for i, col := range header {
+				if i >= len(row) {
+					record.Set(col, nil)
+					continue
+				}
+				value, err := parseCSVField(row[i])
+				if err != nil {
+					return fmt.Errorf("column %s: %w", col, err)
+				}
+				record.Set(col, value)
+			}
+			if err := db.dbMgr.coerceRecordTypes(table, record); err != nil {
+				return err
+			}
+			args := make([]interface{}, len(header))
+			for i, col := range header {
+				args[i] = record.Get(col)
+			}
+			if _, err := tx.Exec(insertSQL, args...); err != nil {
+				return err
+			}
+		}
+	})
+}
+
+// dumpCSVBundle 写出 ZIP 格式的 CSV 包
+func (mgr *dbManager) dumpCSVBundle(tables []string, w io.Writer, opts *DumpOptions) error {
+	zw := zip.NewWriter(w)
+	if !opts.DataOnly {
+		f, err := zw.Create("schema.sql")
+		if err != nil {
+			return err
+		}
+		if err := mgr.dumpSchema(tables, f); err != nil {
+			return err
+		}
+	}
+	if !opts.SchemaOnly {
+		for _, table := range tables {
+			f, err := zw.Create(table + ".csv")
+			if err != nil {
+				return err
+			}
+			cw := csv.NewWriter(f)
+			wroteHeader := false
+			err = mgr.dumpRows(table, func(columns []string, values []interface{}) error {
+				if !wroteHeader {
+					if err := cw.Write(columns); err != nil {
+						return err
+					}
+					wroteHeader = true
+				}
+				fields := make([]string, len(values))
+				for i, v := range values {
+					fields[i] = csvField(v)
+				}
+				return cw.Write(fields)
+			})
+			if err != nil {
+				return fmt.Errorf("eorm: failed to dump table %s: %w", table, err)
+			}
+			cw.Flush()
+			if err := cw.Error(); err != nil {
+				return err
+			}
+		}
+	}
+	return zw.Close()
+}
+
+// dumpRows 逐行读取表中的数据
+func (mgr *dbManager) dumpRows(table string, fn func(columns []string, values []interface{}) error) error {
+	sqlDB, err := mgr.getDB()
+	if err != nil {
+		return err
+	}
+	rows, err := sqlDB.Query(fmt.Sprintf("SELECT * FROM %s", table))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return err
+	}
+	dbTypes := make([]string, len(columns))
+	for i := range columnTypes {
+		dbTypes[i] = strings.ToUpper(columnTypes[i].DatabaseTypeName())
+	}
+	cols := newScanColumns(columns, dbTypes)
+
+	values := make([]interface{}, len(columns))
+	valuePtrs := make([]interface{}, len(columns))
+	for i := range columns {
+		valuePtrs[i] = &values[i]
+	}
+	row := make([]interface{}, len(columns))
+	for rows.Next() {
+		if err := rows.Scan(valuePtrs...); err != nil {
+			return err
+		}
+		for i := range values {
+			row[i] = processDBValue(values[i], cols.dbTypes[i])
+		}
+		if err := fn(columns, row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// dumpSchema 写出各表的建表语句
+func (mgr *dbManager) dumpSchema(tables []string, w io.Writer) error {
+	for _, table := range tables {
+		ddl, err := mgr.tableDDL(table)
+		if err != nil {
+			return fmt.Errorf("eorm: failed to dump schema of %s: %w", table, err)
+		}
+		if _, err := fmt.Fprintf(w, "\n-- schema: %s\n%s\n", table, ddl); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tableDDL 返回一张表的建表语句（以分号结尾，SQLite 包含索引）
+func (mgr *dbManager) tableDDL(table string) (string, error) {
+	sqlDB, err := mgr.getDB()
+	if err != nil {
+		return "", err
+	}
+	switch mgr.config.Load().Driver {
+	case MySQL:
+		var name, ddl string
+		if err := sqlDB.QueryRow("SHOW CREATE TABLE "+table).Scan(&name, &ddl); err != nil {
+			return "", err
+		}
+		return ddl + ";", nil
+	case SQLite3:
+		records, err := mgr.queryInternal(sqlDB, "SELECT sql FROM sqlite_master WHERE tbl_name = ? AND sql IS NOT NULL ORDER BY CASE type WHEN 'table' THEN 0 ELSE 1 END", table)
+		if err != nil {
+			return "", err
+		}
+		statements := make([]string, 0, len(records))
+		for _, r := range records {
+			statements = append(statements, r.GetString("sql")+";")
+		}
+		return strings.Join(statements, "\n"), nil
+	case Oracle:
+		var ddl string
+		if err := sqlDB.QueryRow("SELECT DBMS_METADATA.GET_DDL('TABLE', UPPER(:1)) FROM DUAL", table).Scan(&ddl); err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(ddl) + ";", nil
+	default:
+		return mgr.generatedTableDDL(table)
+	}
+}
+
+// generatedTableDDL 根据列信息生成建表语句（PostgreSQL、SQL Server）
+func (mgr *dbManager) generatedTableDDL(table string) (string, error) {
+	columns, err := mgr.getTableColumns(table)
+	if err != nil {
+		return "", err
+	}
+	if len(columns) == 0 {
+		return "", fmt.Errorf("table %s has no columns", table)
+	}
+	var defs, pks []string
+	for _, col := range columns {
+		colType := col.Type
+		if mgr.config.Load().Driver == SQLServer {
+			switch strings.ToLower(colType) {
+			case "varchar", "nvarchar", "varbinary":
+				colType += "(MAX)"
+			case "decimal", "numeric":
+				colType += "(38, 10)"
+			}
+		}
+		def := col.Name + " " + colType
+		if col.IsAutoIncr {
+			if mgr.config.Load().Driver == SQLServer {
+				def += " IDENTITY(1,1)"
+			} else {
+				def += " GENERATED BY DEFAULT AS IDENTITY"
+			}
+		}
+		if !col.Nullable {
+			def += " NOT NULL"
+		}
+		defs = append(defs, def)
+		if col.IsPK {
+			pks = append(pks, col.Name)
+		}
+	}
+	if len(pks) > 0 {
+		defs = append(defs, "PRIMARY KEY ("+strings.Join(pks, ", ")+")")
+	}
+	return fmt.Sprintf("CREATE TABLE %s (\n  %s\n);", table, strings.Join(defs, ",\n  ")), nil
+}
+
+// sqlLiteral 将值格式化为当前数据库的 SQL 常量
+func (mgr *dbManager) sqlLiteral(v interface{}) string {
+	driver := mgr.config.Load().Driver
+	switch val := derefPointer(v).(type) {
+	case nil:
+		return "NULL"
+	case bool:
+		if driver == PostgreSQL {
+			return strings.ToUpper(strconv.FormatBool(val))
+		}
+		if val {
+			return "1"
+		}
+		return "0"
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(val)
+	case float32:
+		return strconv.FormatFloat(float64(val), 'g', -1, 32)
+	case float64:
+		return strconv.FormatFloat(val, 'g', -1, 64)
+	case time.Time:
+		text := "'" + val.Format("2006-01-02 15:04:05.999999") + "'"
+		if driver == Oracle {
+			return "TIMESTAMP " + text
+		}
+		return text
+	case []byte:
+		h := hex.EncodeToString(val)
+		switch driver {
+		case PostgreSQL:
+			return "decode('" + h + "', 'hex')"
+		case SQLServer:
+			return "0x" + h
+		case Oracle:
+			return "HEXTORAW('" + h + "')"
+		default:
+			return "X'" + h + "'"
+		}
+	case string:
+		return mgr.stringLiteral(val)
+	default:
+		return mgr.stringLiteral(fmt.Sprint(val))
+	}
+}
+
+// stringLiteral 将字符串格式化为 SQL 字符串常量
+// SplitSQLScript 把单引号内的反斜杠视为转义符，不支持反斜杠转义的数据库将反斜杠拆成 CHAR(92) 拼接，保证恢复时正确拆分语句
+func (mgr *dbManager) stringLiteral(s string) string {
+	switch mgr.config.Load().Driver {
+	case MySQL:
+		return quoteMySQLString(s)
+	case PostgreSQL:
+		if strings.Contains(s, `\`) {
+			return "E" + quoteStandardString(strings.ReplaceAll(s, `\`, `\\`))
+		}
+		return quoteStandardString(s)
+	}
+
+	prefix, concat, backslash := "", " || ", "CHAR(92)"
+	switch mgr.config.Load().Driver {
+	case SQLServer:
+		prefix, concat = "N", " + "
+	case Oracle:
+		backslash = "CHR(92)"
+	case SQLite3:
+		backslash = "char(92)"
+	}
+	parts := strings.Split(s, `\`)
+	quoted := make([]string, len(parts))
+	for i, part := range parts {
+		quoted[i] = prefix + quoteStandardString(part)
+	}
+	return strings.Join(quoted, concat+backslash+concat)
+}
+
+// quoteStandardString 使用标准 SQL 规则（单引号加倍）生成字符串常量
+func quoteStandardString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// csvField 将值格式化为 CSV 字段
+func csvField(v interface{}) string {
+	switch val := derefPointer(v).(type) {
+	case nil:
+		return csvNull
+	case []byte:
+		return csvBinaryPrefix + hex.EncodeToString(val)
+	case time.Time:
+		return val.Format("2006-01-02 15:04:05.999999")
+	case bool:
+		if val {
+			return "1"
+		}
+		return "0"
+	default:
+		s := fmt.Sprint(val)
+		if strings.HasPrefix(s, `\`) {
+			return `\` + s
+		}
+		return s
+	}
+}
+
+// parseCSVField 还原 csvField 写出的字段：\N 为 NULL，\x 开头为二进制值，\\ 开头去掉一个转义的 \
+func parseCSVField(field string) (interface{}, error) {
+	switch {
+	case field == csvNull:
+		return nil, nil
+	case strings.HasPrefix(field, csvBinaryPrefix):
+		data, err := hex.DecodeString(field[len(csvBinaryPrefix):])
+		if err != nil {
+			return nil, fmt.Errorf("invalid binary field: %w", err)
+		}
+		return data, nil
+	case strings.HasPrefix(field, `\\`):
+		return field[1:], nil
+	default:
+		return field, nil
+	}
+}
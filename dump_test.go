@@ -0,0 +1,59 @@
+package eorm
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDumpCSVRoundTrip(t *testing.T) {
+	const ddl = "CREATE TABLE dump_items (id INTEGER PRIMARY KEY, name TEXT, data BLOB)"
+	src := openNamedTestDB(t, "dump_src", ddl)
+	rows := []struct {
+		name interface{}
+		data interface{}
+	}{
+		{nil, nil},
+		{`\N`, []byte{0x00, 0xff, '\\', 'N'}},
+		{`\x41`, []byte(`\N`)},
+		{`\\share\path`, []byte{}},
+		{"plain, \"quoted\"\nline", []byte("abc")},
+	}
+	for i, r := range rows {
+		mustExec(t, src, "INSERT INTO dump_items (id, name, data) VALUES (?, ?, ?)", i+1, r.name, r.data)
+	}
+
+	var buf bytes.Buffer
+	if err := Dump(src, []string{"dump_items"}, &buf, &DumpOptions{Format: DumpCSV}); err != nil {
+		t.Fatalf("Dump: %v", err)
+	}
+	dst := openNamedTestDB(t, "dump_dst")
+	if err := RestoreDumpCSV(dst, bytes.NewReader(buf.Bytes()), int64(buf.Len()), true); err != nil {
+		t.Fatalf("RestoreDumpCSV: %v", err)
+	}
+
+	for i, r := range rows {
+		record, err := dst.QueryFirst("SELECT name, typeof(name) AS name_type, data, typeof(data) AS data_type FROM dump_items WHERE id = ?", i+1)
+		if err != nil || record == nil {
+			t.Fatalf("row %d: %v, %v", i+1, record, err)
+		}
+		if r.name == nil {
+			if got := record.GetString("name_type"); got != "null" {
+				t.Errorf("row %d name type = %s, want null", i+1, got)
+			}
+		} else if got := record.GetString("name"); got != r.name {
+			t.Errorf("row %d name = %q, want %q", i+1, got, r.name)
+		}
+		if r.data == nil {
+			if got := record.GetString("data_type"); got != "null" {
+				t.Errorf("row %d data type = %s, want null", i+1, got)
+			}
+			continue
+		}
+		if got := record.GetString("data_type"); got != "blob" {
+			t.Errorf("row %d data type = %s, want blob", i+1, got)
+		}
+		if got := record.GetBytes("data"); !bytes.Equal(got, r.data.([]byte)) {
+			t.Errorf("row %d data = %q, want %q", i+1, got, r.data)
+		}
+	}
+}
@@ -0,0 +1,375 @@
+package eorm
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// entityCacheRegistry stores entity cache TTLs per table
+type entityCacheRegistry struct {
+	tables map[string]time.Duration // table -> ttl
+	mu     sync.RWMutex
+}
+
+// newEntityCacheRegistry creates a new entity cache registry
+func newEntityCacheRegistry() *entityCacheRegistry {
+	return &entityCacheRegistry{
+		tables: make(map[string]time.Duration),
+	}
+}
+
+// set enables the entity cache for a table
+func (r *entityCacheRegistry) set(table string, ttl time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tables[strings.ToLower(table)] = ttl
+}
+
+// get returns the entity cache TTL for a table
+func (r *entityCacheRegistry) get(table string) (time.Duration, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	ttl, ok := r.tables[strings.ToLower(table)]
+	return ttl, ok
+}
+
+// remove disables the entity cache for a table
+func (r *entityCacheRegistry) remove(table string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.tables, strings.ToLower(table))
+}
+
+// list returns all tables with the entity cache enabled
+func (r *entityCacheRegistry) list() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	tables := make([]string, 0, len(r.tables))
+	for table := range r.tables {
+		tables = append(tables, table)
+	}
+	return tables
+}
+
+var (
+	// pkEqualityRe 匹配 "col = ?" 形式的条件，列名可带表名前缀
+	pkEqualityRe = regexp.MustCompile(`^\s*\(?\s*(?:[A-Za-z_][A-Za-z0-9_]*\.)?([A-Za-z_][A-Za-z0-9_]*)\s*=\s*\?\s*\)?\s*$`)
+	// whereAndRe、whereOrRe 用于拆分写操作的 WHERE 条件
+	whereAndRe = regexp.MustCompile(`(?i)\s+AND\s+`)
+	whereOrRe  = regexp.MustCompile(`(?i)\bOR\b`)
+)
+
+// --- Global Functions (for default database) ---
+
+// ConfigEntityCache 为表开启按主键缓存的实体缓存，ttl 为缓存时间（<= 0 时不过期）
+// QueryBuilder 的 FindFirst/QueryFirst（包括 FindFirstModel 和生成的 FindByID）在条件恰好为主键等值时读取实体缓存；
+// 通过 eorm 执行的更新、删除、Save 等写操作按主键精确失效，无法确定主键的写操作（原始 SQL、条件更新）失效整张表的实体缓存
+// 与查询缓存（Cache）相互独立，使用默认缓存提供者；事务内的查询不读写实体缓存，
+// 事务内的写操作除立即失效外，还会在事务提交后再失效一次
+// 示例:
+//
+//	eorm.ConfigEntityCache("users", 10*time.Minute)
+//	user, _ := eorm.Table("users").Where("id = ?", 1).FindFirst() // 第二次起从缓存读取
+//	eorm.Update("users", record, "id = ?", 1)                      // 只失效 users:1
+func ConfigEntityCache(table string, ttl time.Duration) {
+	db, err := defaultDB()
+	if err != nil {
+		return
+	}
+	db.ConfigEntityCache(table, ttl)
+}
+
+// RemoveEntityCache 关闭表的实体缓存并清空已缓存的实体
+func RemoveEntityCache(table string) {
+	db, err := defaultDB()
+	if err != nil {
+		return
+	}
+	db.RemoveEntityCache(table)
+}
+
+// ClearEntityCache 清空表已缓存的实体，用于绕过 eorm 修改数据后手动失效
+func ClearEntityCache(table string) {
+	db, err := defaultDB()
+	if err != nil {
+		return
+	}
+	db.ClearEntityCache(table)
+}
+
+// --- DB Methods ---
+
+// ConfigEntityCache 为表开启实体缓存，见 ConfigEntityCache
+func (db *DB) ConfigEntityCache(table string, ttl time.Duration) *DB {
+	if db.lastErr != nil || db.dbMgr == nil {
+		return db
+	}
+	if err := validateIdentifier(table); err != nil {
+		db.lastErr = err
+		return db
+	}
+	db.dbMgr.setEntityCache(table, ttl)
+	return db
+}
+
+// RemoveEntityCache 关闭表的实体缓存并清空已缓存的实体
+func (db *DB) RemoveEntityCache(table string) *DB {
+	if db.lastErr != nil || db.dbMgr == nil {
+		return db
+	}
+	if db.dbMgr.entityCaches != nil {
+		db.dbMgr.clearEntities(table)
+		db.dbMgr.entityCaches.remove(table)
+	}
+	return db
+}
+
+// ClearEntityCache 清空表已缓存的实体
+func (db *DB) ClearEntityCache(table string) *DB {
+	if db.lastErr != nil || db.dbMgr == nil {
+		return db
+	}
+	db.dbMgr.clearEntities(table)
+	return db
+}
+
+// --- dbManager Methods ---
+
+// setEntityCache enables the entity cache for a table
+func (mgr *dbManager) setEntityCache(table string, ttl time.Duration) {
+	if mgr.entityCaches == nil {
+		mgr.entityCaches = newEntityCacheRegistry()
+	}
+	mgr.entityCaches.set(table, ttl)
+}
+
+// entityCacheTTL returns the entity cache TTL for a table, ok is false when disabled
+func (mgr *dbManager) entityCacheTTL(table string) (time.Duration, bool) {
+	if mgr.entityCaches == nil {
+		return 0, false
+	}
+	return mgr.entityCaches.get(table)
+}
+
+// entityRepository returns the cache repository holding the entities of a table
+func (mgr *dbManager) entityRepository(table string) string {
+	return "eorm_entity:" + mgr.name + ":" + strings.ToLower(table)
+}
+
+// entityKeyFromWhere returns the entity key when the conditions are exactly one "pk = ?" per primary key column
+func (mgr *dbManager) entityKeyFromWhere(table string, conditions []string, args []interface{}) (string, bool) {
+	if len(conditions) == 0 || len(conditions) != len(args) {
+		return "", false
+	}
+	sdb, err := mgr.getDB()
+	if err != nil {
+		return "", false
+	}
+	pks, err := mgr.getPrimaryKeys(sdb, table)
+	if err != nil || len(pks) != len(conditions) {
+		return "", false
+	}
+	values := make(map[string]interface{}, len(conditions))
+	for i, cond := range conditions {
+		m := pkEqualityRe.FindStringSubmatch(cond)
+		if m == nil {
+			return "", false
+		}
+		values[strings.ToLower(m[1])] = args[i]
+	}
+	record := NewRecord()
+	for _, pk := range pks {
+		v, ok := values[strings.ToLower(pk)]
+		if !ok {
+			return "", false
+		}
+		record.Set(pk, v)
+	}
+	return entityKey(pks, record), true
+}
+
+// entityKey returns the cache key of a record, or "" when a primary key value is missing
+func entityKey(pks []string, record *Record) string {
+	parts := make([]string, len(pks))
+	for i, pk := range pks {
+		v := record.Get(pk)
+		if v == nil {
+			return ""
+		}
+		parts[i] = treeKey(v)
+	}
+	return strings.Join(parts, "\x00")
+}
+
+// evictEntities evicts the entity matched by a "pk = ?" where clause, otherwise clears the table's entities
+func (mgr *dbManager) evictEntities(table, where string, whereArgs []interface{}) {
+	if _, ok := mgr.entityCacheTTL(table); !ok {
+		return
+	}
+	if key := mgr.entityKeyInWhere(table, where, whereArgs); key != "" {
+		GetCache().CacheDelete(mgr.entityRepository(table), key)
+		return
+	}
+	mgr.clearEntities(table)
+}
+
+// entityKeyInWhere returns the entity key when every primary key column is bound by a top-level
+// "pk = ?" AND condition (e.g. "id = ? AND version = ?"), so at most that entity is affected
+func (mgr *dbManager) entityKeyInWhere(table, where string, whereArgs []interface{}) string {
+	if where == "" || whereOrRe.MatchString(where) || strings.Count(where, "?") != len(whereArgs) {
+		return ""
+	}
+	sdb, err := mgr.getDB()
+	if err != nil {
+		return ""
+	}
+	pks, err := mgr.getPrimaryKeys(sdb, table)
+	if err != nil || len(pks) == 0 {
+		return ""
+	}
+	values := make(map[string]interface{})
+	argIndex := 0
+	for _, cond := range whereAndRe.Split(where, -1) {
+		if m := pkEqualityRe.FindStringSubmatch(cond); m != nil {
+			values[strings.ToLower(m[1])] = whereArgs[argIndex]
+		}
+		argIndex += strings.Count(cond, "?")
+	}
+	record := NewRecord()
+	for _, pk := range pks {
+		v, ok := values[strings.ToLower(pk)]
+		if !ok {
+			return ""
+		}
+		record.Set(pk, v)
+	}
+	return entityKey(pks, record)
+}
+
+// evictEntityRecords evicts entities by the primary key values of records;
+// records without primary key values are new rows and have no cached entity
+func (mgr *dbManager) evictEntityRecords(table string, records []*Record) {
+	if len(records) == 0 {
+		return
+	}
+	if _, ok := mgr.entityCacheTTL(table); !ok {
+		return
+	}
+	sdb, err := mgr.getDB()
+	if err != nil {
+		mgr.clearEntities(table)
+		return
+	}
+	pks, err := mgr.getPrimaryKeys(sdb, table)
+	if err != nil || len(pks) == 0 {
+		mgr.clearEntities(table)
+		return
+	}
+	cache := GetCache()
+	repo := mgr.entityRepository(table)
+	for _, record := range records {
+		if record == nil {
+			continue
+		}
+		if key := entityKey(pks, record); key != "" {
+			cache.CacheDelete(repo, key)
+		}
+	}
+}
+
+// evictEntityIds evicts entities of a single primary key table by id values
+func (mgr *dbManager) evictEntityIds(table string, ids []interface{}) {
+	if _, ok := mgr.entityCacheTTL(table); !ok {
+		return
+	}
+	sdb, err := mgr.getDB()
+	if err != nil {
+		mgr.clearEntities(table)
+		return
+	}
+	pks, err := mgr.getPrimaryKeys(sdb, table)
+	if err != nil || len(pks) != 1 {
+		mgr.clearEntities(table)
+		return
+	}
+	records := make([]*Record, len(ids))
+	for i, id := range ids {
+		records[i] = NewRecord().Set(pks[0], id)
+	}
+	mgr.evictEntityRecords(table, records)
+}
+
+// clearEntities clears the cached entities of a table, or of all tables when table is empty
+func (mgr *dbManager) clearEntities(table string) {
+	if mgr.entityCaches == nil {
+		return
+	}
+	cache := GetCache()
+	if table != "" {
+		cache.CacheClearRepository(mgr.entityRepository(table))
+		return
+	}
+	for _, t := range mgr.entityCaches.list() {
+		cache.CacheClearRepository(mgr.entityRepository(t))
+	}
+}
+
+// --- QueryBuilder integration ---
+
+// entityCacheKey 返回 QueryFirst 可使用的实体缓存键：仅限事务外、条件恰好为主键等值、
+// 且没有行级过滤、默认作用域、行 TTL 等与上下文相关条件的单表 SELECT *
+func (qb *QueryBuilder) entityCacheKey() (key string, ttl time.Duration, ok bool) {
+	if qb.tx != nil || qb.db == nil || qb.db.dbMgr == nil || qb.queryInfo != nil {
+		return "", 0, false
+	}
+	mgr := qb.db.dbMgr
+	if ttl, ok = mgr.entityCacheTTL(qb.table); !ok {
+		return "", 0, false
+	}
+	if (qb.selectSql != "" && qb.selectSql != "*") || qb.withTrashed || qb.onlyTrashed || qb.offset > 0 ||
+		qb.subqueryTable != nil || qb.asOfSQL != "" || qb.samplePercent > 0 || qb.groupBy != "" ||
+		len(qb.joins) > 0 || len(qb.selectSubqueries) > 0 || len(qb.orWhereSql) > 0 ||
+		len(qb.havingSql) > 0 || len(qb.orHavingSql) > 0 {
+		return "", 0, false
+	}
+	if cond, _ := qb.getRowFilterCondition(); cond != "" {
+		return "", 0, false
+	}
+	if conds, _ := qb.getGlobalScopeConditions(); len(conds) > 0 {
+		return "", 0, false
+	}
+	if cond, _ := qb.getRowTTLCondition(); cond != "" {
+		return "", 0, false
+	}
+	key, ok = mgr.entityKeyFromWhere(qb.table, qb.whereSql, qb.whereArgs)
+	if !ok || key == "" {
+		return "", 0, false
+	}
+	return key, ttl, true
+}
+
+// queryFirstEntity 通过实体缓存执行 QueryFirst，未命中时查询并缓存找到的记录（不缓存空结果）
+func (qb *QueryBuilder) queryFirstEntity(key string, ttl time.Duration, querySQL string, args []interface{}) (*Record, error) {
+	mgr := qb.db.dbMgr
+	cache := GetCache()
+	repo := mgr.entityRepository(qb.table)
+	if val, ok := cacheGet(cache, repo, key); ok {
+		var record *Record
+		if convertCacheValue(val, &record) && record != nil {
+			return record.Clone(), nil
+		}
+	}
+
+	db := qb.db
+	if qb.timeout > 0 {
+		db = &DB{dbMgr: mgr, timeout: qb.timeout, ctx: qb.db.ctx}
+	}
+	record, err := db.QueryFirst(querySQL, args...)
+	if err != nil || record == nil {
+		return record, err
+	}
+	cacheSet(cache, repo, key, record.Clone(), ttl)
+	return record, nil
+}
@@ -0,0 +1,69 @@
+package eorm
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEntityCacheInvalidatedByRawWrites(t *testing.T) {
+	db := openTestDB(t, "CREATE TABLE ec_users (id INTEGER PRIMARY KEY, name TEXT)")
+	mustExec(t, db, "INSERT INTO ec_users (id, name) VALUES (1, 'a')")
+	db.ConfigEntityCache("ec_users", time.Minute)
+	t.Cleanup(func() { db.RemoveEntityCache("ec_users") })
+
+	findName := func() string {
+		t.Helper()
+		record, err := db.Table("ec_users").Where("id = ?", 1).FindFirst()
+		if err != nil || record == nil {
+			t.Fatalf("FindFirst = %v, %v", record, err)
+		}
+		return record.GetString("name")
+	}
+	if name := findName(); name != "a" {
+		t.Fatalf("name = %q, want a", name)
+	}
+
+	// 绕过 eorm 的写入不会失效缓存，说明读取确实命中了实体缓存
+	rawExec(t, db, "UPDATE ec_users SET name = 'x' WHERE id = 1")
+	if name := findName(); name != "a" {
+		t.Fatalf("name = %q, want cached a", name)
+	}
+
+	mustExec(t, db, "UPDATE ec_users SET name = 'b' WHERE id = 1")
+	if name := findName(); name != "b" {
+		t.Fatalf("name after raw update = %q, want b", name)
+	}
+}
+
+func TestEntityCacheInvalidatedAgainAtCommit(t *testing.T) {
+	db := openTestDB(t, "CREATE TABLE ec_orders (id INTEGER PRIMARY KEY, status TEXT)")
+	mustExec(t, db, "INSERT INTO ec_orders (id, status) VALUES (1, 'new')")
+	db.ConfigEntityCache("ec_orders", time.Minute)
+	t.Cleanup(func() { db.RemoveEntityCache("ec_orders") })
+
+	findStatus := func() string {
+		t.Helper()
+		record, err := db.Table("ec_orders").Where("id = ?", 1).FindFirst()
+		if err != nil || record == nil {
+			t.Fatalf("FindFirst = %v, %v", record, err)
+		}
+		return record.GetString("status")
+	}
+
+	err := db.Transaction(func(tx *Tx) error {
+		if _, err := tx.Update("ec_orders", NewRecord().Set("status", "paid"), "id = ?", 1); err != nil {
+			return err
+		}
+		// 提交前的并发读取把旧数据写回实体缓存
+		if status := findStatus(); status != "new" {
+			t.Errorf("status before commit = %q, want new", status)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Transaction: %v", err)
+	}
+	if status := findStatus(); status != "paid" {
+		t.Fatalf("status after commit = %q, want paid", status)
+	}
+}
@@ -0,0 +1,116 @@
+package eorm
+
+import "strings"
+
+// SqlExpr 原始 SQL 表达式，作为 Record 的列值或 Where 参数时会直接写入 SQL 而不是作为参数绑定
+// 表达式中可以包含 ? 占位符，对应的参数通过 Expr 的 args 传入
+type SqlExpr struct {
+	SQL  string
+	Args []interface{}
+}
+
+// Expr 创建原始 SQL 表达式
+// 支持 Insert/Update/Save 的 Record 值以及查询和 Exec 的参数；批量操作使用统一的预编译语句，不支持表达式值
+// 注意：SQL 部分会原样拼接到语句中，不要传入未经校验的用户输入，用户输入应通过 args 传入
+// 示例:
+//
+//	record.Set("updated_at", eorm.Expr("NOW()"))
+//	record.Set("stock", eorm.Expr("stock - ?", 1))
+//	record.Set("meta", eorm.Expr("jsonb_set(meta, '{level}', ?)", "3"))
+//	eorm.Table("orders").Where("created_at < ?", eorm.Expr("NOW() - INTERVAL 1 DAY")).Find()
+func Expr(sql string, args ...interface{}) *SqlExpr {
+	return &SqlExpr{SQL: sql, Args: args}
+}
+
+// String 返回表达式的 SQL 部分
+func (e *SqlExpr) String() string {
+	if e == nil {
+		return ""
+	}
+	return e.SQL
+}
+
+// hasSqlExprArg 判断参数中是否包含 SqlExpr
+func hasSqlExprArg(args []interface{}) bool {
+	for _, arg := range args {
+		if _, ok := arg.(*SqlExpr); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// expandSqlExprArgs 将参数中的 SqlExpr 展开到 SQL 中
+// 对应位置的 ? 占位符被替换为表达式 SQL，表达式自身的参数按顺序插入参数列表
+// 只处理 ? 风格的占位符，必须在 convertPlaceholder 之前调用
+func expandSqlExprArgs(querySQL string, args []interface{}) (string, []interface{}) {
+	if !hasSqlExprArg(args) {
+		return querySQL, args
+	}
+
+	var builder strings.Builder
+	builder.Grow(len(querySQL) + 32)
+	newArgs := make([]interface{}, 0, len(args))
+	argIndex := 0
+	inSingleQuote, inDoubleQuote, inBacktick := false, false, false
+
+	for i := 0; i < len(querySQL); i++ {
+		char := querySQL[i]
+		if i+1 < len(querySQL) && char == '\\' {
+			builder.WriteByte(char)
+			builder.WriteByte(querySQL[i+1])
+			i++
+			continue
+		}
+		switch {
+		case char == '\'' && !inDoubleQuote && !inBacktick:
+			inSingleQuote = !inSingleQuote
+		case char == '"' && !inSingleQuote && !inBacktick:
+			inDoubleQuote = !inDoubleQuote
+		case char == '`' && !inSingleQuote && !inDoubleQuote:
+			inBacktick = !inBacktick
+		case char == '?' && !inSingleQuote && !inDoubleQuote && !inBacktick:
+			// ?? 是转义的字面量 ?，不对应参数
+			if i+1 < len(querySQL) && querySQL[i+1] == '?' {
+				builder.WriteString("??")
+				i++
+				continue
+			}
+			if argIndex < len(args) {
+				arg := args[argIndex]
+				argIndex++
+				if expr, ok := arg.(*SqlExpr); ok && expr != nil {
+					builder.WriteString(expr.SQL)
+					newArgs = append(newArgs, expr.Args...)
+					continue
+				}
+				newArgs = append(newArgs, arg)
+			}
+		}
+		builder.WriteByte(char)
+	}
+
+	// 多余的参数原样保留，交给 sanitizeArgs 处理
+	if argIndex < len(args) {
+		newArgs = append(newArgs, args[argIndex:]...)
+	}
+	return builder.String(), newArgs
+}
+
+// expandSqlExprPlaceholders 将 INSERT 值列表中的 SqlExpr 替换为表达式 SQL
+// placeholders 与 values 一一对应，返回新的占位符列表和参数列表
+func expandSqlExprPlaceholders(placeholders []string, values []interface{}) ([]string, []interface{}) {
+	if !hasSqlExprArg(values) {
+		return placeholders, values
+	}
+	newArgs := make([]interface{}, 0, len(values))
+	for i, val := range values {
+		if expr, ok := val.(*SqlExpr); ok && expr != nil && i < len(placeholders) {
+			placeholders[i] = expr.SQL
+			newArgs = append(newArgs, expr.Args...)
+			continue
+		}
+		newArgs = append(newArgs, val)
+	}
+	return placeholders, newArgs
+}
@@ -0,0 +1,111 @@
+package eorm
+
+import (
+	"fmt"
+	"time"
+)
+
+// defaultFailoverAfter 未配置 FailoverAfter 时触发故障转移所需的连续失败次数
+const defaultFailoverAfter = 3
+
+// FailoverEvent 故障转移事件
+type FailoverEvent struct {
+	DBName   string    // 数据库名称
+	From     string    // 发生故障的 DSN
+	To       string    // 提升为主连接的 DSN，全部备用连接失败时为空
+	Failures int       // 触发时的连续失败次数
+	Success  bool      // 是否已切换到新的主连接
+	Err      error     // 全部备用连接失败时为最后一个错误
+	Time     time.Time // 事件时间
+}
+
+// failoverCandidates 返回按优先级排列的 DSN 列表（当前 DSN 在首位），去除空值和重复值
+func failoverCandidates(config *Config) []string {
+	seen := make(map[string]bool, len(config.FailoverDSNs)+1)
+	candidates := make([]string, 0, len(config.FailoverDSNs)+1)
+	for _, dsn := range append([]string{config.DSN}, config.FailoverDSNs...) {
+		if dsn == "" || seen[dsn] {
+			continue
+		}
+		seen[dsn] = true
+		candidates = append(candidates, dsn)
+	}
+	return candidates
+}
+
+// failover 连续失败次数达到阈值后依次尝试备用 DSN，成功的 DSN 提升为主连接
+// 原来的 DSN 移到备用列表末尾，恢复后可在下一次故障时切换回去
+func (mgr *dbManager) failover(failures int) bool {
+	mgr.mu.RLock()
+	config := mgr.config.Load()
+	mgr.mu.RUnlock()
+	if config == nil || len(config.FailoverDSNs) == 0 {
+		return false
+	}
+	threshold := config.FailoverAfter
+	if threshold <= 0 {
+		threshold = defaultFailoverAfter
+	}
+	if failures < threshold {
+		return false
+	}
+
+	candidates := failoverCandidates(config)
+	var lastErr error
+	for i := 1; i < len(candidates); i++ {
+		next := *config
+		next.DSN = candidates[i]
+		next.FailoverDSNs = append(append([]string{}, candidates[i+1:]...), candidates[:i]...)
+		if err := SwapDatabase(mgr.name, &next); err != nil {
+			lastErr = err
+			LogWarn("备用数据库连接失败", NewRecord().
+				Set("database", mgr.name).
+				Set("dsn_index", i).
+				Set("error", err.Error()))
+			continue
+		}
+		LogWarn("数据库已故障转移到备用连接", NewRecord().
+			Set("database", mgr.name).
+			Set("dsn_index", i).
+			Set("failures", failures))
+		notifyFailover(config, FailoverEvent{
+			DBName:   mgr.name,
+			From:     config.DSN,
+			To:       next.DSN,
+			Failures: failures,
+			Success:  true,
+			Time:     time.Now(),
+		})
+		return true
+	}
+
+	if lastErr == nil {
+		lastErr = fmt.Errorf("eorm: no failover DSN available for database '%s'", mgr.name)
+	}
+	LogError("数据库故障转移失败", NewRecord().
+		Set("database", mgr.name).
+		Set("error", lastErr.Error()))
+	notifyFailover(config, FailoverEvent{
+		DBName:   mgr.name,
+		From:     config.DSN,
+		Failures: failures,
+		Err:      lastErr,
+		Time:     time.Now(),
+	})
+	return false
+}
+
+// notifyFailover 调用故障转移回调，回调中的 panic 不影响监控协程
+func notifyFailover(config *Config, event FailoverEvent) {
+	if config.OnFailover == nil {
+		return
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			LogError("故障转移回调 panic", NewRecord().
+				Set("database", event.DBName).
+				Set("panic", fmt.Sprint(r)))
+		}
+	}()
+	config.OnFailover(event)
+}
@@ -0,0 +1,72 @@
+package eorm
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFailoverPromotesStandbyDSN(t *testing.T) {
+	db := openTestDB(t, "CREATE TABLE fo_items (id INTEGER PRIMARY KEY, name TEXT)")
+	mustExec(t, db, "INSERT INTO fo_items (id, name) VALUES (1, 'primary')")
+
+	standby := filepath.Join(t.TempDir(), "standby.db")
+	standbyConfig := createDefaultConfig(SQLite3, standby, 1)
+	seed, err := openConnectionPool(standbyConfig)
+	if err != nil {
+		t.Fatalf("open standby: %v", err)
+	}
+	if _, err := seed.Exec("CREATE TABLE fo_items (id INTEGER PRIMARY KEY, name TEXT)"); err != nil {
+		t.Fatalf("create standby table: %v", err)
+	}
+	if _, err := seed.Exec("INSERT INTO fo_items (id, name) VALUES (1, 'standby')"); err != nil {
+		t.Fatalf("seed standby: %v", err)
+	}
+	seed.Close()
+	// 目录不存在，SQLite 无法打开
+	broken := filepath.Join(t.TempDir(), "missing", "broken.db")
+
+	var events []FailoverEvent
+	config := *db.dbMgr.config.Load()
+	primary := config.DSN
+	config.FailoverDSNs = []string{broken, standby}
+	config.FailoverAfter = 2
+	config.OnFailover = func(event FailoverEvent) { events = append(events, event) }
+	if err := SwapDatabase(db.dbMgr.name, &config); err != nil {
+		t.Fatalf("SwapDatabase: %v", err)
+	}
+
+	if db.dbMgr.failover(1) || len(events) != 0 {
+		t.Fatalf("failover below the threshold switched or notified: events = %v", events)
+	}
+	if !db.dbMgr.failover(2) {
+		t.Fatal("failover(2) = false, want the standby DSN promoted")
+	}
+	if len(events) != 1 || !events[0].Success || events[0].From != primary || events[0].To != standby {
+		t.Fatalf("events = %+v, want one successful event from the primary to the standby", events)
+	}
+	current := db.dbMgr.config.Load()
+	if current.DSN != standby || !reflect.DeepEqual(current.FailoverDSNs, []string{primary, broken}) {
+		t.Fatalf("DSN = %s, FailoverDSNs = %v; want the standby promoted and the old DSN moved to the end", current.DSN, current.FailoverDSNs)
+	}
+	record, err := db.QueryFirst("SELECT name FROM fo_items WHERE id = ?", 1)
+	if err != nil || record == nil || record.GetString("name") != "standby" {
+		t.Fatalf("QueryFirst after failover = %v, %v; want the standby row", record, err)
+	}
+
+	config = *current
+	config.FailoverDSNs = []string{broken}
+	if err := SwapDatabase(db.dbMgr.name, &config); err != nil {
+		t.Fatalf("SwapDatabase: %v", err)
+	}
+	events = nil
+	if db.dbMgr.failover(2) {
+		t.Fatal("failover to a broken DSN = true, want false")
+	}
+	if len(events) != 1 || events[0].Success || events[0].Err == nil {
+		t.Fatalf("events = %+v, want one failed event with an error", events)
+	}
+	if got := db.dbMgr.config.Load().DSN; got != standby {
+		t.Fatalf("DSN after a failed failover = %s, want %s unchanged", got, standby)
+	}
+}
@@ -35,6 +35,11 @@ func GenerateDbModel(tablename, outPath, structName string) error {
 
 // GenerateDbModel generates a Go struct for the specified table and saves it to a file
 func (db *DB) GenerateDbModel(tablename, outPath, structName string) error {
+	return db.generateDbModel(tablename, outPath, structName, GenerateOptions{})
+}
+
+// generateDbModel generates the model file and, if requested, the repository file
+func (db *DB) generateDbModel(tablename, outPath, structName string, opts GenerateOptions) error {
 	if db.lastErr != nil {
 		return db.lastErr
 	}
@@ -52,6 +57,7 @@ func (db *DB) GenerateDbModel(tablename, outPath, structName string) error {
 	if len(columns) == 0 {
 		return fmt.Errorf("no columns found for table '%s'. please check if the table exists and you have access permissions", tablename)
 	}
+	readOnly := opts.ReadOnly || db.dbMgr.isView(tablename)
 
 	// 1. Handle path and package name
 	var pkgName string
@@ -89,8 +95,7 @@ func (db *DB) GenerateDbModel(tablename, outPath, structName string) error {
 	// 2. Determine struct name (if structName is empty, generate from table name)
 	finalStructName := structName
 	if finalStructName == "" {
-		camelBase := strings.ReplaceAll(tablename, ".", "_")
-		finalStructName = SnakeToCamel(camelBase)
+		finalStructName = db.dbMgr.modelNameForTable(tablename)
 	}
 
 	// 3. Build code content
@@ -116,9 +121,14 @@ func (db *DB) GenerateDbModel(tablename, outPath, structName string) error {
 	if pkgName != "eorm" {
 		sb.WriteString("\t\"github.com/zzguang83325/eorm\"\n")
 	}
+	writeCustomImportsRegion(&sb)
 	sb.WriteString(")\n\n")
 
-	sb.WriteString(fmt.Sprintf("// %s represents the %s table\n", finalStructName, tablename))
+	if readOnly {
+		sb.WriteString(fmt.Sprintf("// %s represents the %s view (read-only)\n", finalStructName, tablename))
+	} else {
+		sb.WriteString(fmt.Sprintf("// %s represents the %s table\n", finalStructName, tablename))
+	}
 	sb.WriteString(fmt.Sprintf("type %s struct {\n", finalStructName))
 	// 嵌入 ModelCache 以支持缓存功能，添加 column:"-" 标签防止映射到数据库列
 	sb.WriteString("\teorm.ModelCache `column:\"-\"`\n")
@@ -178,38 +188,41 @@ func (db *DB) GenerateDbModel(tablename, outPath, structName string) error {
 	sb.WriteString("\treturn eorm.ToJson(m)\n")
 	sb.WriteString("}\n\n")
 
-	// Add ActiveRecord member methods (Save, Insert, Update, Delete)
-	sb.WriteString(fmt.Sprintf("// Save saves the %s record (insert or update)\n", finalStructName))
-	sb.WriteString(fmt.Sprintf("func (m *%s) Save() (int64, error) {\n", finalStructName))
-	sb.WriteString("\treturn eorm.SaveDbModel(m)\n")
-	sb.WriteString("}\n\n")
-
-	sb.WriteString(fmt.Sprintf("// Insert inserts the %s record\n", finalStructName))
-	sb.WriteString(fmt.Sprintf("func (m *%s) Insert() (int64, error) {\n", finalStructName))
-	sb.WriteString("\treturn eorm.InsertDbModel(m)\n")
-	sb.WriteString("}\n\n")
-
-	sb.WriteString(fmt.Sprintf("// Update updates the %s record based on its primary key\n", finalStructName))
-	sb.WriteString(fmt.Sprintf("func (m *%s) Update() (int64, error) {\n", finalStructName))
-	sb.WriteString("\treturn eorm.UpdateDbModel(m)\n")
-	sb.WriteString("}\n\n")
-
-	sb.WriteString(fmt.Sprintf("// Delete deletes the %s record based on its primary key\n", finalStructName))
-	sb.WriteString(fmt.Sprintf("func (m *%s) Delete() (int64, error) {\n", finalStructName))
-	sb.WriteString("\treturn eorm.DeleteDbModel(m)\n")
-	sb.WriteString("}\n\n")
-
-	// Add ForceDelete method for soft delete support
-	sb.WriteString(fmt.Sprintf("// ForceDelete performs a physical delete, bypassing soft delete\n"))
-	sb.WriteString(fmt.Sprintf("func (m *%s) ForceDelete() (int64, error) {\n", finalStructName))
-	sb.WriteString("\treturn eorm.ForceDeleteModel(m)\n")
-	sb.WriteString("}\n\n")
-
-	// Add Restore method for soft delete support
-	sb.WriteString(fmt.Sprintf("// Restore restores a soft-deleted record\n"))
-	sb.WriteString(fmt.Sprintf("func (m *%s) Restore() (int64, error) {\n", finalStructName))
-	sb.WriteString("\treturn eorm.RestoreModel(m)\n")
-	sb.WriteString("}\n\n")
+	// 视图/物化视图生成只读模型，不生成写入方法
+	if !readOnly {
+		// Add ActiveRecord member methods (Save, Insert, Update, Delete)
+		sb.WriteString(fmt.Sprintf("// Save saves the %s record (insert or update)\n", finalStructName))
+		sb.WriteString(fmt.Sprintf("func (m *%s) Save() (int64, error) {\n", finalStructName))
+		sb.WriteString("\treturn eorm.SaveDbModel(m)\n")
+		sb.WriteString("}\n\n")
+
+		sb.WriteString(fmt.Sprintf("// Insert inserts the %s record\n", finalStructName))
+		sb.WriteString(fmt.Sprintf("func (m *%s) Insert() (int64, error) {\n", finalStructName))
+		sb.WriteString("\treturn eorm.InsertDbModel(m)\n")
+		sb.WriteString("}\n\n")
+
+		sb.WriteString(fmt.Sprintf("// Update updates the %s record based on its primary key\n", finalStructName))
+		sb.WriteString(fmt.Sprintf("func (m *%s) Update() (int64, error) {\n", finalStructName))
+		sb.WriteString("\treturn eorm.UpdateDbModel(m)\n")
+		sb.WriteString("}\n\n")
+
+		sb.WriteString(fmt.Sprintf("// Delete deletes the %s record based on its primary key\n", finalStructName))
+		sb.WriteString(fmt.Sprintf("func (m *%s) Delete() (int64, error) {\n", finalStructName))
+		sb.WriteString("\treturn eorm.DeleteDbModel(m)\n")
+		sb.WriteString("}\n\n")
+
+		// Add ForceDelete method for soft delete support
+		sb.WriteString(fmt.Sprintf("// ForceDelete performs a physical delete, bypassing soft delete\n"))
+		sb.WriteString(fmt.Sprintf("func (m *%s) ForceDelete() (int64, error) {\n", finalStructName))
+		sb.WriteString("\treturn eorm.ForceDeleteModel(m)\n")
+		sb.WriteString("}\n\n")
+
+		// Add Restore method for soft delete support
+		sb.WriteString(fmt.Sprintf("// Restore restores a soft-deleted record\n"))
+		sb.WriteString(fmt.Sprintf("func (m *%s) Restore() (int64, error) {\n", finalStructName))
+		sb.WriteString("\treturn eorm.RestoreModel(m)\n")
+		sb.WriteString("}\n\n")
+	}
 
 	// Use generic function to simplify FindFirst
 	sb.WriteString(fmt.Sprintf("// FindFirst finds the first %s record based on conditions\n", finalStructName))
@@ -249,6 +262,8 @@ func (db *DB) GenerateDbModel(tablename, outPath, structName string) error {
 	sb.WriteString(fmt.Sprintf("\treturn eorm.PaginateModel_FullSql[*%s](m, m.GetCache(), page, pageSize, fullSQL, args...)\n", finalStructName))
 	sb.WriteString("}\n\n")
 
+	writeCustomCodeRegion(&sb)
+
 	// 4. Write to file
 	// Ensure directory exists
 	dir := filepath.Dir(finalPath)
@@ -256,10 +271,13 @@ func (db *DB) GenerateDbModel(tablename, outPath, structName string) error {
 		return fmt.Errorf("failed to create directory: %v", err)
 	}
 
-	if err := os.WriteFile(finalPath, []byte(sb.String()), 0644); err != nil {
-		return fmt.Errorf("failed to write file: %v", err)
+	if err := writeGeneratedFile(finalPath, sb.String()); err != nil {
+		return err
 	}
 
+	if opts.Repository {
+		return db.writeRepositoryFile(tablename, finalPath, pkgName, finalStructName, columns)
+	}
 	return nil
 }
 
@@ -281,7 +299,7 @@ func (mgr *dbManager) getTableColumns(table string) ([]ColumnInfo, error) {
 
 	// 2. 缓存未命中，查询数据库
 	var columns []ColumnInfo
-	driver := mgr.config.Driver
+	driver := mgr.config.Load().Driver
 
 	switch driver {
 	case MySQL:
@@ -291,11 +309,11 @@ func (mgr *dbManager) getTableColumns(table string) ([]ColumnInfo, error) {
 		if err != nil {
 			return nil, err
 		}
-		records, err := mgr.query(db, query, args...)
+		records, err := mgr.queryInternal(db, query, args...)
 		if err != nil || len(records) == 0 {
 			// If failed or empty, try simple SHOW COLUMNS
 			query = fmt.Sprintf("SHOW COLUMNS FROM `%s`", table)
-			records, err = mgr.query(db, query)
+			records, err = mgr.queryInternal(db, query)
 			if err != nil {
 				return nil, err
 			}
@@ -336,7 +354,7 @@ func (mgr *dbManager) getTableColumns(table string) ([]ColumnInfo, error) {
 		if err != nil {
 			return nil, err
 		}
-		records, err := mgr.query(db, query, args...)
+		records, err := mgr.queryInternal(db, query, args...)
 		if err != nil {
 			return nil, err
 		}
@@ -400,7 +418,7 @@ func (mgr *dbManager) getTableColumns(table string) ([]ColumnInfo, error) {
 		if err != nil {
 			return nil, err
 		}
-		records, err := mgr.query(db, query, args...)
+		records, err := mgr.queryInternal(db, query, args...)
 		if err != nil {
 			return nil, err
 		}
@@ -429,7 +447,7 @@ func (mgr *dbManager) getTableColumns(table string) ([]ColumnInfo, error) {
 		if err != nil {
 			return nil, err
 		}
-		records, err := mgr.query(db, query, args...)
+		records, err := mgr.queryInternal(db, query, args...)
 		if err != nil {
 			return nil, err
 		}
@@ -454,7 +472,7 @@ func (mgr *dbManager) getTableColumns(table string) ([]ColumnInfo, error) {
 		if err != nil {
 			return nil, err
 		}
-		records, err := mgr.query(db, query, args...)
+		records, err := mgr.queryInternal(db, query, args...)
 		if err != nil {
 			return nil, err
 		}
@@ -558,7 +576,7 @@ func dbTypeToGoType(dbType string, nullable bool, isPK bool) string {
 // getAllTables 获取数据库中所有表名
 func (mgr *dbManager) getAllTables() ([]string, error) {
 	var tables []string
-	driver := mgr.config.Driver
+	driver := mgr.config.Load().Driver
 
 	db, err := mgr.getDB()
 	if err != nil {
@@ -569,7 +587,7 @@ func (mgr *dbManager) getAllTables() ([]string, error) {
 	case MySQL:
 		// MySQL: 查询当前数据库的所有表
 		query := "SELECT TABLE_NAME FROM INFORMATION_SCHEMA.TABLES WHERE TABLE_SCHEMA = DATABASE() AND TABLE_TYPE = 'BASE TABLE' ORDER BY TABLE_NAME"
-		records, err := mgr.query(db, query)
+		records, err := mgr.queryInternal(db, query)
 		if err != nil {
 			return nil, err
 		}
@@ -582,7 +600,7 @@ func (mgr *dbManager) getAllTables() ([]string, error) {
 	case PostgreSQL:
 		// PostgreSQL: 查询当前 schema 的所有表
 		query := "SELECT tablename FROM pg_catalog.pg_tables WHERE schemaname = current_schema() ORDER BY tablename"
-		records, err := mgr.query(db, query)
+		records, err := mgr.queryInternal(db, query)
 		if err != nil {
 			return nil, err
 		}
@@ -595,7 +613,7 @@ func (mgr *dbManager) getAllTables() ([]string, error) {
 	case SQLite3:
 		// SQLite3: 查询所有表（排除系统表）
 		query := "SELECT name FROM sqlite_master WHERE type='table' AND name NOT LIKE 'sqlite_%' ORDER BY name"
-		records, err := mgr.query(db, query)
+		records, err := mgr.queryInternal(db, query)
 		if err != nil {
 			return nil, err
 		}
@@ -608,7 +626,7 @@ func (mgr *dbManager) getAllTables() ([]string, error) {
 	case Oracle:
 		// Oracle: 查询当前用户的所有表
 		query := "SELECT TABLE_NAME FROM USER_TABLES ORDER BY TABLE_NAME"
-		records, err := mgr.query(db, query)
+		records, err := mgr.queryInternal(db, query)
 		if err != nil {
 			return nil, err
 		}
@@ -621,7 +639,7 @@ func (mgr *dbManager) getAllTables() ([]string, error) {
 	case SQLServer:
 		// SQL Server: 查询当前数据库的所有表
 		query := "SELECT TABLE_NAME FROM INFORMATION_SCHEMA.TABLES WHERE TABLE_TYPE = 'BASE TABLE' ORDER BY TABLE_NAME"
-		records, err := mgr.query(db, query)
+		records, err := mgr.queryInternal(db, query)
 		if err != nil {
 			return nil, err
 		}
@@ -653,6 +671,20 @@ func GenerateAllDbModel(outPath string) (int, error) {
 // outPath: 输出目录路径，如果为空则使用 "models" 目录
 // 返回生成的文件数量和错误信息
 func (db *DB) GenerateAllDbModel(outPath string) (int, error) {
+	return db.GenerateAllDbModelWithOptions(outPath, GenerateOptions{})
+}
+
+// GenerateAllDbModelWithOptions 生成数据库中所有表的 Model 代码（全局函数），支持生成选项
+func GenerateAllDbModelWithOptions(outPath string, opts GenerateOptions) (int, error) {
+	db, err := defaultDB()
+	if err != nil {
+		return 0, err
+	}
+	return db.GenerateAllDbModelWithOptions(outPath, opts)
+}
+
+// GenerateAllDbModelWithOptions 生成数据库中所有表的 Model 代码，支持生成选项
+func (db *DB) GenerateAllDbModelWithOptions(outPath string, opts GenerateOptions) (int, error) {
 	if db.lastErr != nil {
 		return 0, db.lastErr
 	}
@@ -663,6 +695,14 @@ func (db *DB) GenerateAllDbModel(outPath string) (int, error) {
 		return 0, fmt.Errorf("failed to get table list: %v", err)
 	}
 
+	if opts.IncludeViews {
+		views, err := db.dbMgr.getAllViews()
+		if err != nil {
+			return 0, fmt.Errorf("failed to get view list: %v", err)
+		}
+		tables = append(tables, views...)
+	}
+
 	if len(tables) == 0 {
 		return 0, fmt.Errorf("no tables found in database")
 	}
@@ -683,7 +723,7 @@ func (db *DB) GenerateAllDbModel(outPath string) (int, error) {
 
 	for _, table := range tables {
 		// 为每个表生成 Model，structName 为空表示自动生成
-		err := db.GenerateDbModel(table, outPath, "")
+		err := db.generateDbModel(table, outPath, "", opts)
 		if err != nil {
 			errors = append(errors, fmt.Sprintf("table '%s': %v", table, err))
 		} else {
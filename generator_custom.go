@@ -0,0 +1,84 @@
+package eorm
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// 生成代码中的自定义区域标记
+// 标记之间的内容由用户维护，重新生成模型时会原样保留，标记之外的内容会被覆盖
+const (
+	customImportsBegin = "// eorm:begin custom imports"
+	customImportsEnd   = "// eorm:end custom imports"
+	customCodeBegin    = "// eorm:begin custom"
+	customCodeEnd      = "// eorm:end custom"
+)
+
+// writeCustomImportsRegion 在 import 块中写入自定义导入区域
+func writeCustomImportsRegion(sb *strings.Builder) {
+	sb.WriteString("\n\t" + customImportsBegin + "\n")
+	sb.WriteString("\t" + customImportsEnd + "\n")
+}
+
+// writeCustomCodeRegion 在文件末尾写入自定义代码区域
+func writeCustomCodeRegion(sb *strings.Builder) {
+	sb.WriteString("// Code between the markers below is preserved when the file is regenerated\n")
+	sb.WriteString(customCodeBegin + "\n")
+	sb.WriteString(customCodeEnd + "\n")
+}
+
+// findCustomRegion 查找标记行之间的内容范围，标记需独占一行（忽略首尾空白）
+// 返回内容在 content 中的起止位置（不含标记行）
+func findCustomRegion(content, begin, end string) (int, int, bool) {
+	start := -1
+	pos := 0
+	for pos < len(content) {
+		lineEnd := strings.IndexByte(content[pos:], '\n')
+		next := len(content)
+		if lineEnd != -1 {
+			next = pos + lineEnd + 1
+		}
+		line := strings.TrimSpace(content[pos:next])
+		if start == -1 {
+			if line == begin {
+				start = next
+			}
+		} else if line == end {
+			return start, pos, true
+		}
+		pos = next
+	}
+	return 0, 0, false
+}
+
+// mergeCustomRegion 用旧文件中对应区域的内容替换新生成内容中的同名区域
+func mergeCustomRegion(generated, existing, begin, end string) string {
+	oldStart, oldEnd, ok := findCustomRegion(existing, begin, end)
+	if !ok {
+		return generated
+	}
+	newStart, newEnd, ok := findCustomRegion(generated, begin, end)
+	if !ok {
+		return generated
+	}
+	return generated[:newStart] + existing[oldStart:oldEnd] + generated[newEnd:]
+}
+
+// writeGeneratedFile 写入生成的代码文件
+// 目标文件已存在时保留其中 eorm:begin custom / eorm:end custom 标记之间的用户代码，
+// 因此表结构变更后可以直接重新生成而不丢失手写的方法；没有标记的旧文件会被整体覆盖
+func writeGeneratedFile(path, content string) error {
+	if existing, err := os.ReadFile(path); err == nil {
+		old := string(existing)
+		content = mergeCustomRegion(content, old, customImportsBegin, customImportsEnd)
+		content = mergeCustomRegion(content, old, customCodeBegin, customCodeEnd)
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read existing file: %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write file: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,327 @@
+package eorm
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// IndexInfo represents index metadata
+type IndexInfo struct {
+	Name    string   // 索引名
+	Columns []string // 索引列（按索引中的顺序）
+	Unique  bool     // 是否唯一索引
+}
+
+// GenerateOptions 代码生成选项
+type GenerateOptions struct {
+	Repository   bool // 同时生成 <Struct>Repository 数据访问层（FindByID、唯一索引的 FindByXxx、普通索引的 ListByXxx）
+	ReadOnly     bool // 生成只读模型（不生成 Save/Insert/Update/Delete 等写入方法），视图会自动按只读生成
+	IncludeViews bool // GenerateAllDbModelWithOptions 同时为视图和物化视图生成只读模型
+}
+
+// GenerateDbModelWithOptions generates a Go struct for the specified table with extra options
+func GenerateDbModelWithOptions(tablename, outPath, structName string, opts GenerateOptions) error {
+	db, err := defaultDB()
+	if err != nil {
+		return err
+	}
+	return db.GenerateDbModelWithOptions(tablename, outPath, structName, opts)
+}
+
+// GenerateDbModelWithOptions generates a Go struct for the specified table with extra options
+// opts.Repository 为 true 时在同一目录生成 <table>_repository.go
+func (db *DB) GenerateDbModelWithOptions(tablename, outPath, structName string, opts GenerateOptions) error {
+	return db.generateDbModel(tablename, outPath, structName, opts)
+}
+
+// GetTableIndexes 获取表的索引信息（包含主键约束对应的索引）
+func GetTableIndexes(table string) ([]IndexInfo, error) {
+	db, err := defaultDB()
+	if err != nil {
+		return nil, err
+	}
+	return db.GetTableIndexes(table)
+}
+
+// GetTableIndexes 获取表的索引信息
+func (db *DB) GetTableIndexes(table string) ([]IndexInfo, error) {
+	if db.lastErr != nil {
+		return nil, db.lastErr
+	}
+	return db.dbMgr.getTableIndexes(table)
+}
+
+// getTableIndexes fetches index information for a table
+func (mgr *dbManager) getTableIndexes(table string) ([]IndexInfo, error) {
+	if err := validateIdentifier(table); err != nil {
+		return nil, err
+	}
+	db, err := mgr.getDB()
+	if err != nil {
+		return nil, err
+	}
+
+	// 带 schema 的表名只取表名部分
+	tableName := table
+	if idx := strings.LastIndex(table, "."); idx != -1 {
+		tableName = table[idx+1:]
+	}
+
+	indexMap := make(map[string]*IndexInfo)
+	var order []string
+	addColumn := func(name, column string, unique bool) {
+		info, ok := indexMap[name]
+		if !ok {
+			info = &IndexInfo{Name: name, Unique: unique}
+			indexMap[name] = info
+			order = append(order, name)
+		}
+		info.Columns = append(info.Columns, column)
+	}
+
+	switch mgr.config.Load().Driver {
+	case MySQL:
+		query := "SELECT INDEX_NAME, COLUMN_NAME, NON_UNIQUE FROM INFORMATION_SCHEMA.STATISTICS WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? ORDER BY INDEX_NAME, SEQ_IN_INDEX"
+		records, err := mgr.queryInternal(db, query, tableName)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range records {
+			addColumn(r.GetString("INDEX_NAME"), r.GetString("COLUMN_NAME"), fmt.Sprintf("%v", r.Get("NON_UNIQUE")) == "0")
+		}
+
+	case PostgreSQL:
+		query := `SELECT i.relname AS index_name, a.attname AS column_name, ix.indisunique AS is_unique
+			FROM pg_class t
+			JOIN pg_index ix ON t.oid = ix.indrelid
+			JOIN pg_class i ON i.oid = ix.indexrelid
+			JOIN pg_attribute a ON a.attrelid = t.oid AND a.attnum = ANY(ix.indkey)
+			WHERE t.relname = ? AND t.relnamespace = to_regnamespace(current_schema())::oid
+			ORDER BY i.relname, array_position(ix.indkey::int2[], a.attnum)`
+		records, err := mgr.queryInternal(db, query, tableName)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range records {
+			addColumn(r.GetString("index_name"), r.GetString("column_name"), r.GetBool("is_unique"))
+		}
+
+	case SQLite3:
+		indexes, err := mgr.queryInternal(db, fmt.Sprintf("PRAGMA index_list(%s)", tableName))
+		if err != nil {
+			return nil, err
+		}
+		for _, idx := range indexes {
+			name := idx.GetString("name")
+			cols, err := mgr.queryInternal(db, fmt.Sprintf("PRAGMA index_info(%s)", quoteSQLiteIdentifier(name)))
+			if err != nil {
+				return nil, err
+			}
+			for _, c := range cols {
+				addColumn(name, c.GetString("name"), idx.GetInt("unique") == 1)
+			}
+		}
+
+	case Oracle:
+		query := `SELECT ic.INDEX_NAME, ic.COLUMN_NAME, i.UNIQUENESS
+			FROM USER_IND_COLUMNS ic JOIN USER_INDEXES i ON ic.INDEX_NAME = i.INDEX_NAME
+			WHERE ic.TABLE_NAME = ? ORDER BY ic.INDEX_NAME, ic.COLUMN_POSITION`
+		records, err := mgr.queryInternal(db, query, strings.ToUpper(tableName))
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range records {
+			addColumn(r.GetString("INDEX_NAME"), r.GetString("COLUMN_NAME"), r.GetString("UNIQUENESS") == "UNIQUE")
+		}
+
+	case SQLServer:
+		query := `SELECT i.name AS index_name, c.name AS column_name, i.is_unique
+			FROM sys.indexes i
+			JOIN sys.index_columns ic ON i.object_id = ic.object_id AND i.index_id = ic.index_id
+			JOIN sys.columns c ON ic.object_id = c.object_id AND ic.column_id = c.column_id
+			WHERE i.object_id = OBJECT_ID(?) AND i.name IS NOT NULL AND ic.is_included_column = 0
+			ORDER BY i.name, ic.key_ordinal`
+		records, err := mgr.queryInternal(db, query, table)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range records {
+			addColumn(r.GetString("index_name"), r.GetString("column_name"), r.GetBool("is_unique"))
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported driver: %s", mgr.config.Load().Driver)
+	}
+
+	indexes := make([]IndexInfo, 0, len(order))
+	for _, name := range order {
+		indexes = append(indexes, *indexMap[name])
+	}
+	return indexes, nil
+}
+
+// quoteSQLiteIdentifier 为 SQLite 标识符加双引号
+func quoteSQLiteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// repositoryFinder 仓库层的一个查询方法
+type repositoryFinder struct {
+	columns []ColumnInfo
+	unique  bool
+}
+
+// buildRepositoryFinders 根据主键和索引确定要生成的查询方法
+// 唯一索引生成 FindByXxx（返回单条），普通索引按前导列生成 ListByXxx（返回列表），重复的列组合只生成一次
+func buildRepositoryFinders(columns []ColumnInfo, indexes []IndexInfo) []repositoryFinder {
+	colMap := make(map[string]ColumnInfo, len(columns))
+	var pkCols []ColumnInfo
+	for _, col := range columns {
+		colMap[strings.ToLower(col.Name)] = col
+		if col.IsPK {
+			pkCols = append(pkCols, col)
+		}
+	}
+
+	seen := make(map[string]bool)
+	var finders []repositoryFinder
+	add := func(cols []ColumnInfo, unique bool) {
+		names := make([]string, len(cols))
+		for i, c := range cols {
+			names[i] = strings.ToLower(c.Name)
+		}
+		key := strings.Join(names, ",")
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		finders = append(finders, repositoryFinder{columns: cols, unique: unique})
+	}
+
+	if len(pkCols) > 0 {
+		add(pkCols, true)
+	}
+
+	// 唯一索引优先，保证同一列组合既有唯一索引又有普通索引时生成 FindBy
+	sorted := append([]IndexInfo(nil), indexes...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Unique && !sorted[j].Unique })
+	for _, idx := range sorted {
+		var cols []ColumnInfo
+		for _, name := range idx.Columns {
+			col, ok := colMap[strings.ToLower(name)]
+			if !ok {
+				cols = nil // 表达式索引等无法映射到列，跳过
+				break
+			}
+			cols = append(cols, col)
+		}
+		if len(cols) == 0 {
+			continue
+		}
+		if idx.Unique {
+			add(cols, true)
+		} else {
+			add(cols[:1], false)
+		}
+	}
+	return finders
+}
+
+// writeRepositoryFile 生成 <Struct>Repository 代码文件
+func (db *DB) writeRepositoryFile(tablename, modelPath, pkgName, structName string, columns []ColumnInfo) error {
+	indexes, err := db.dbMgr.getTableIndexes(tablename)
+	if err != nil {
+		return fmt.Errorf("failed to get indexes for table '%s': %v", tablename, err)
+	}
+	finders := buildRepositoryFinders(columns, indexes)
+
+	repoName := structName + "Repository"
+	var body strings.Builder
+	hasTime := false
+
+	body.WriteString(fmt.Sprintf("// %s provides typed data access for the %s table\n", repoName, tablename))
+	body.WriteString(fmt.Sprintf("type %s struct {\n", repoName))
+	body.WriteString("\tsession eorm.Session\n")
+	body.WriteString("}\n\n")
+
+	body.WriteString(fmt.Sprintf("// New%s creates a %s\n", repoName, repoName))
+	body.WriteString("// session 为 nil 时使用模型所属的数据库，传入 *eorm.Tx 即可在事务中使用\n")
+	body.WriteString(fmt.Sprintf("func New%s(session eorm.Session) *%s {\n", repoName, repoName))
+	body.WriteString(fmt.Sprintf("\treturn &%s{session: session}\n", repoName))
+	body.WriteString("}\n\n")
+
+	body.WriteString("// Table starts a query builder on the table\n")
+	body.WriteString(fmt.Sprintf("func (r *%s) Table() *eorm.QueryBuilder {\n", repoName))
+	body.WriteString("\tif r.session == nil {\n")
+	body.WriteString(fmt.Sprintf("\t\treturn eorm.Use(%q).Table(%q)\n", db.dbMgr.name, tablename))
+	body.WriteString("\t}\n")
+	body.WriteString(fmt.Sprintf("\treturn r.session.Table(%q)\n", tablename))
+	body.WriteString("}\n\n")
+
+	for _, f := range finders {
+		var nameParts, params, conds, args []string
+		for i, col := range f.columns {
+			fieldName := SnakeToCamel(col.Name)
+			goType := dbTypeToGoType(col.Type, false, true)
+			if strings.Contains(goType, "time.Time") {
+				hasTime = true
+			}
+			paramName := fmt.Sprintf("v%d", i)
+			if col.IsPK && len(f.columns) == 1 {
+				nameParts = append(nameParts, "ID")
+				paramName = "id"
+			} else {
+				nameParts = append(nameParts, fieldName)
+			}
+			params = append(params, paramName+" "+goType)
+			conds = append(conds, col.Name+" = ?")
+			args = append(args, paramName)
+		}
+		where := strings.Join(conds, " AND ")
+
+		if f.unique {
+			method := "FindBy" + strings.Join(nameParts, "And")
+			body.WriteString(fmt.Sprintf("// %s finds the %s matching %s, returns nil if not found\n", method, structName, where))
+			body.WriteString(fmt.Sprintf("func (r *%s) %s(%s) (*%s, error) {\n", repoName, method, strings.Join(params, ", "), structName))
+			body.WriteString(fmt.Sprintf("\trecord, err := r.Table().Where(%q, %s).FindFirst()\n", where, strings.Join(args, ", ")))
+			body.WriteString("\tif err != nil || record == nil {\n")
+			body.WriteString("\t\treturn nil, err\n")
+			body.WriteString("\t}\n")
+			body.WriteString(fmt.Sprintf("\tresult := &%s{}\n", structName))
+			body.WriteString("\tif err := eorm.ToStruct(record, result); err != nil {\n")
+			body.WriteString("\t\treturn nil, err\n")
+			body.WriteString("\t}\n")
+			body.WriteString("\treturn result, nil\n")
+			body.WriteString("}\n\n")
+		} else {
+			method := "ListBy" + strings.Join(nameParts, "And")
+			body.WriteString(fmt.Sprintf("// %s lists %s records matching %s\n", method, structName, where))
+			body.WriteString(fmt.Sprintf("func (r *%s) %s(%s) ([]*%s, error) {\n", repoName, method, strings.Join(params, ", "), structName))
+			body.WriteString(fmt.Sprintf("\tvar results []*%s\n", structName))
+			body.WriteString(fmt.Sprintf("\terr := r.Table().Where(%q, %s).FindToDbModel(&results)\n", where, strings.Join(args, ", ")))
+			body.WriteString("\treturn results, err\n")
+			body.WriteString("}\n\n")
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("package %s\n\n", pkgName))
+	sb.WriteString("import (\n")
+	if hasTime {
+		sb.WriteString("\t\"time\"\n\n")
+	}
+	sb.WriteString("\t\"github.com/zzguang83325/eorm\"\n")
+	writeCustomImportsRegion(&sb)
+	sb.WriteString(")\n\n")
+	sb.WriteString(body.String())
+	writeCustomCodeRegion(&sb)
+
+	repoPath := strings.TrimSuffix(modelPath, ".go") + "_repository.go"
+	if err := os.MkdirAll(filepath.Dir(repoPath), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %v", err)
+	}
+	return writeGeneratedFile(repoPath, sb.String())
+}
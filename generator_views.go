@@ -0,0 +1,86 @@
+package eorm
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GetAllViews 获取数据库中所有视图名（包含物化视图）
+func GetAllViews() ([]string, error) {
+	db, err := defaultDB()
+	if err != nil {
+		return nil, err
+	}
+	return db.GetAllViews()
+}
+
+// GetAllViews 获取数据库中所有视图名（包含物化视图）
+func (db *DB) GetAllViews() ([]string, error) {
+	if db.lastErr != nil {
+		return nil, db.lastErr
+	}
+	return db.dbMgr.getAllViews()
+}
+
+// getAllViews 获取数据库中所有视图和物化视图
+func (mgr *dbManager) getAllViews() ([]string, error) {
+	db, err := mgr.getDB()
+	if err != nil {
+		return nil, err
+	}
+
+	var query, column string
+	switch mgr.config.Load().Driver {
+	case MySQL:
+		query = "SELECT TABLE_NAME FROM INFORMATION_SCHEMA.VIEWS WHERE TABLE_SCHEMA = DATABASE() ORDER BY TABLE_NAME"
+		column = "TABLE_NAME"
+	case PostgreSQL:
+		// information_schema.views 不包含物化视图，需要合并 pg_matviews
+		query = `SELECT table_name AS view_name FROM information_schema.views WHERE table_schema = current_schema()
+			UNION SELECT matviewname AS view_name FROM pg_catalog.pg_matviews WHERE schemaname = current_schema()
+			ORDER BY view_name`
+		column = "view_name"
+	case SQLite3:
+		query = "SELECT name FROM sqlite_master WHERE type = 'view' ORDER BY name"
+		column = "name"
+	case Oracle:
+		// 物化视图在 USER_VIEWS 之外单独记录
+		query = "SELECT VIEW_NAME FROM USER_VIEWS UNION SELECT MVIEW_NAME AS VIEW_NAME FROM USER_MVIEWS ORDER BY VIEW_NAME"
+		column = "VIEW_NAME"
+	case SQLServer:
+		query = "SELECT TABLE_NAME FROM INFORMATION_SCHEMA.VIEWS ORDER BY TABLE_NAME"
+		column = "TABLE_NAME"
+	default:
+		return nil, fmt.Errorf("unsupported driver: %s", mgr.config.Load().Driver)
+	}
+
+	records, err := mgr.queryInternal(db, query)
+	if err != nil {
+		return nil, err
+	}
+	views := make([]string, 0, len(records))
+	for _, r := range records {
+		if name := r.GetString(column); name != "" {
+			views = append(views, name)
+		}
+	}
+	return views, nil
+}
+
+// isView 判断名称是否为视图或物化视图（查询失败时按普通表处理）
+func (mgr *dbManager) isView(table string) bool {
+	name := table
+	if idx := strings.LastIndex(table, "."); idx != -1 {
+		name = table[idx+1:]
+	}
+	views, err := mgr.getAllViews()
+	if err != nil {
+		return false
+	}
+	for _, v := range views {
+		if strings.EqualFold(v, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+package eorm
+
+import (
+	"strings"
+	"sync"
+)
+
+// globalScope 表级默认作用域
+type globalScope struct {
+	name      string
+	condition string
+	args      []interface{}
+}
+
+// globalScopeRegistry 表级默认作用域注册表 (table -> 按注册顺序排列的作用域)
+var globalScopeRegistry = struct {
+	sync.RWMutex
+	scopes map[string][]globalScope
+}{scopes: make(map[string][]globalScope)}
+
+// RegisterGlobalScope 为表注册默认作用域，Table(table) 构建的查询（Find/Query/Paginate/Count 等）会自动追加该条件
+// 与软删除过滤一样只作用于查询，不影响 Update/Delete；同名作用域会被覆盖
+// 使用 qb.WithoutGlobalScope(name) 或 qb.WithoutGlobalScopes() 跳过
+// 示例:
+//
+//	eorm.RegisterGlobalScope("products", "not_archived", "status <> ?", "archived")
+//	eorm.Table("products").Find()                                   // ... WHERE status <> 'archived'
+//	eorm.Table("products").WithoutGlobalScope("not_archived").Find() // 不过滤
+func RegisterGlobalScope(table, name, condition string, args ...interface{}) {
+	if table == "" || name == "" || condition == "" {
+		return
+	}
+	key := strings.ToLower(table)
+	scope := globalScope{name: name, condition: condition, args: args}
+
+	globalScopeRegistry.Lock()
+	defer globalScopeRegistry.Unlock()
+	scopes := globalScopeRegistry.scopes[key]
+	for i, s := range scopes {
+		if s.name == name {
+			scopes[i] = scope
+			return
+		}
+	}
+	globalScopeRegistry.scopes[key] = append(scopes, scope)
+}
+
+// RemoveGlobalScope 移除表的默认作用域
+func RemoveGlobalScope(table, name string) {
+	key := strings.ToLower(table)
+	globalScopeRegistry.Lock()
+	defer globalScopeRegistry.Unlock()
+	scopes := globalScopeRegistry.scopes[key]
+	for i, s := range scopes {
+		if s.name == name {
+			scopes = append(scopes[:i:i], scopes[i+1:]...)
+			break
+		}
+	}
+	if len(scopes) == 0 {
+		delete(globalScopeRegistry.scopes, key)
+		return
+	}
+	globalScopeRegistry.scopes[key] = scopes
+}
+
+// WithoutGlobalScope 跳过指定名称的表级默认作用域
+func (qb *QueryBuilder) WithoutGlobalScope(names ...string) *QueryBuilder {
+	qb.withoutGlobalScopes = append(qb.withoutGlobalScopes, names...)
+	return qb
+}
+
+// WithoutGlobalScopes 跳过所有表级默认作用域
+func (qb *QueryBuilder) WithoutGlobalScopes() *QueryBuilder {
+	qb.skipGlobalScopes = true
+	return qb
+}
+
+// getGlobalScopeConditions 返回当前表生效的默认作用域条件和参数
+func (qb *QueryBuilder) getGlobalScopeConditions() ([]string, []interface{}) {
+	if qb.skipGlobalScopes || qb.table == "" || qb.subqueryTable != nil {
+		return nil, nil
+	}
+
+	globalScopeRegistry.RLock()
+	scopes := globalScopeRegistry.scopes[strings.ToLower(qb.table)]
+	globalScopeRegistry.RUnlock()
+	if len(scopes) == 0 {
+		return nil, nil
+	}
+
+	var conditions []string
+	var args []interface{}
+	for _, s := range scopes {
+		skipped := false
+		for _, name := range qb.withoutGlobalScopes {
+			if name == s.name {
+				skipped = true
+				break
+			}
+		}
+		if skipped {
+			continue
+		}
+		conditions = append(conditions, s.condition)
+		args = append(args, s.args...)
+	}
+	return conditions, args
+}
@@ -2,4 +2,7 @@ module github.com/zzguang83325/eorm
 
 go 1.24.0
 
-require golang.org/x/text v0.14.0
+require (
+	github.com/mattn/go-sqlite3 v1.14.22
+	golang.org/x/text v0.14.0
+)
@@ -0,0 +1,141 @@
+package eorm
+
+import (
+	"fmt"
+	"strings"
+)
+
+// existsManyChunkSize ExistsMany 每条查询的最大键数量（Oracle IN 列表上限为 1000）
+const existsManyChunkSize = 1000
+
+// countGroupBy 按列分组统计行数
+func (mgr *dbManager) countGroupBy(executor sqlExecutor, table, groupColumn, where string, whereArgs ...interface{}) (map[interface{}]int64, error) {
+	if err := validateIdentifier(table); err != nil {
+		return nil, err
+	}
+	if err := validateIdentifier(groupColumn); err != nil {
+		return nil, err
+	}
+	querySQL := fmt.Sprintf("SELECT %s AS eorm_key, COUNT(*) AS eorm_count FROM %s", groupColumn, table)
+	if where != "" {
+		querySQL += " WHERE " + where
+	}
+	querySQL += " GROUP BY " + groupColumn
+	records, err := mgr.query(executor, querySQL, whereArgs...)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[interface{}]int64, len(records))
+	for _, r := range records {
+		result[r.Get("eorm_key")] = r.GetInt64("eorm_count")
+	}
+	return result, nil
+}
+
+// existsMany 一次查询判断多个键是否存在，结果以调用方传入的键为 key
+func (mgr *dbManager) existsMany(executor sqlExecutor, table, keyColumn string, keys []interface{}, where string, whereArgs ...interface{}) (map[interface{}]bool, error) {
+	if err := validateIdentifier(table); err != nil {
+		return nil, err
+	}
+	if err := validateIdentifier(keyColumn); err != nil {
+		return nil, err
+	}
+	result := make(map[interface{}]bool, len(keys))
+	// 数据库返回的键类型可能与传入的不同（如 int 与 int64），按文本形式匹配
+	byText := make(map[string][]interface{}, len(keys))
+	for _, k := range keys {
+		result[k] = false
+		text := fmt.Sprint(k)
+		byText[text] = append(byText[text], k)
+	}
+
+	for start := 0; start < len(keys); start += existsManyChunkSize {
+		end := start + existsManyChunkSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+		chunk := keys[start:end]
+		cond := fmt.Sprintf("%s IN (%s)", keyColumn, strings.TrimSuffix(strings.Repeat("?, ", len(chunk)), ", "))
+		args := append([]interface{}{}, chunk...)
+		if where != "" {
+			cond = "(" + where + ") AND " + cond
+			args = append(append([]interface{}{}, whereArgs...), chunk...)
+		}
+		querySQL := fmt.Sprintf("SELECT DISTINCT %s AS eorm_key FROM %s WHERE %s", keyColumn, table, cond)
+		records, err := mgr.query(executor, querySQL, args...)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range records {
+			for _, k := range byText[fmt.Sprint(r.Get("eorm_key"))] {
+				result[k] = true
+			}
+		}
+	}
+	return result, nil
+}
+
+// CountGroupBy 按 groupColumn 分组统计满足条件的行数，一次查询替代逐个 Count
+// 返回的 key 为数据库返回的分组值（类型取决于驱动，如 int64、string），没有行的分组不出现在结果中
+// 示例:
+//
+//	counts, err := eorm.CountGroupBy("orders", "user_id", "status = ?", "paid")
+//	for userID, n := range counts { ... }
+func CountGroupBy(table, groupColumn, whereSql string, whereArgs ...interface{}) (map[interface{}]int64, error) {
+	db, err := defaultDB()
+	if err != nil {
+		return nil, err
+	}
+	return db.CountGroupBy(table, groupColumn, whereSql, whereArgs...)
+}
+
+// CountGroupBy 按列分组统计行数，见 CountGroupBy
+func (db *DB) CountGroupBy(table, groupColumn, whereSql string, whereArgs ...interface{}) (map[interface{}]int64, error) {
+	if db.lastErr != nil {
+		return nil, db.lastErr
+	}
+	whereSql, whereArgs = applyRowFilter(db.ctx, table, whereSql, whereArgs, false)
+	executor, err := db.getExecutor()
+	if err != nil {
+		return nil, err
+	}
+	return db.dbMgr.countGroupBy(executor, table, groupColumn, whereSql, whereArgs...)
+}
+
+// CountGroupBy 在事务中按列分组统计行数
+func (tx *Tx) CountGroupBy(table, groupColumn, whereSql string, whereArgs ...interface{}) (map[interface{}]int64, error) {
+	whereSql, whereArgs = applyRowFilter(tx.ctx, table, whereSql, whereArgs, false)
+	return tx.dbMgr.countGroupBy(tx.tx, table, groupColumn, whereSql, whereArgs...)
+}
+
+// ExistsMany 一次查询判断多个键是否存在，返回以传入的键为 key 的结果（超过 1000 个键时分批查询）
+// 示例:
+//
+//	exists, err := eorm.ExistsMany("users", "id", []interface{}{1, 2, 3})
+//	if !exists[2] { ... }
+func ExistsMany(table, keyColumn string, keys []interface{}) (map[interface{}]bool, error) {
+	db, err := defaultDB()
+	if err != nil {
+		return nil, err
+	}
+	return db.ExistsMany(table, keyColumn, keys)
+}
+
+// ExistsMany 一次查询判断多个键是否存在，见 ExistsMany
+func (db *DB) ExistsMany(table, keyColumn string, keys []interface{}) (map[interface{}]bool, error) {
+	if db.lastErr != nil {
+		return nil, db.lastErr
+	}
+	whereSql, whereArgs := applyRowFilter(db.ctx, table, "", nil, false)
+	executor, err := db.getExecutor()
+	if err != nil {
+		return nil, err
+	}
+	return db.dbMgr.existsMany(executor, table, keyColumn, keys, whereSql, whereArgs...)
+}
+
+// ExistsMany 在事务中判断多个键是否存在
+func (tx *Tx) ExistsMany(table, keyColumn string, keys []interface{}) (map[interface{}]bool, error) {
+	whereSql, whereArgs := applyRowFilter(tx.ctx, table, "", nil, false)
+	return tx.dbMgr.existsMany(tx.tx, table, keyColumn, keys, whereSql, whereArgs...)
+}
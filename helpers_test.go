@@ -0,0 +1,85 @@
+package eorm
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// openTestDB 在临时目录中打开一个以测试名命名的 SQLite 数据库并执行建表语句，测试结束时自动关闭
+func openTestDB(t *testing.T, ddl ...string) *DB {
+	t.Helper()
+	return openNamedTestDB(t, strings.ReplaceAll(t.Name(), "/", "_"), ddl...)
+}
+
+// openNamedTestDB 与 openTestDB 相同，但使用指定的数据库名，用于同一测试中打开多个数据库
+func openNamedTestDB(t *testing.T, name string, ddl ...string) *DB {
+	t.Helper()
+	config := createDefaultConfig(SQLite3, filepath.Join(t.TempDir(), "test.db"), 4)
+	config.MonitorNormalInterval = 0
+	db, err := OpenDatabaseWithConfig(name, config)
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	t.Cleanup(func() { CloseDB(name) })
+	for _, stmt := range ddl {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("exec %q: %v", stmt, err)
+		}
+	}
+	return db
+}
+
+// mustExec 执行 SQL，失败时终止测试
+func mustExec(t *testing.T, db *DB, querySQL string, args ...interface{}) {
+	t.Helper()
+	if _, err := db.Exec(querySQL, args...); err != nil {
+		t.Fatalf("exec %q: %v", querySQL, err)
+	}
+}
+
+// countRows 使用原生 SQL 统计满足条件的行数（不经过行级过滤器）
+func countRows(t *testing.T, db *DB, table, where string, args ...interface{}) int64 {
+	t.Helper()
+	querySQL := "SELECT COUNT(*) AS n FROM " + table
+	if where != "" {
+		querySQL += " WHERE " + where
+	}
+	record, err := db.QueryFirst(querySQL, args...)
+	if err != nil {
+		t.Fatalf("count %s: %v", table, err)
+	}
+	return record.GetInt64("n")
+}
+
+// rawExec 直接在底层连接上执行 SQL，绕过缓存失效、变更事件等框架逻辑
+func rawExec(t *testing.T, db *DB, querySQL string, args ...interface{}) {
+	t.Helper()
+	sqlDB, err := db.dbMgr.getDB()
+	if err != nil {
+		t.Fatalf("get db: %v", err)
+	}
+	if _, err := sqlDB.Exec(querySQL, args...); err != nil {
+		t.Fatalf("exec %q: %v", querySQL, err)
+	}
+}
+
+// countRowsDirect 与 countRows 相同，但直接在底层连接上执行，不经过限流器和准入控制
+func countRowsDirect(t *testing.T, db *DB, table, where string) int64 {
+	t.Helper()
+	sqlDB, err := db.dbMgr.getDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	querySQL := "SELECT COUNT(*) FROM " + table
+	if where != "" {
+		querySQL += " WHERE " + where
+	}
+	var n int64
+	if err := sqlDB.QueryRow(querySQL).Scan(&n); err != nil {
+		t.Fatalf("count %s: %v", table, err)
+	}
+	return n
+}
@@ -0,0 +1,367 @@
+package eorm
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// HistoryConfig holds the history table configuration for a table
+type HistoryConfig struct {
+	HistoryTable   string // History table name, default "<table>_history"
+	ValidFromField string // Column holding when the archived version became current, default "valid_from"
+	ValidToField   string // Column holding when the archived version was replaced, default "valid_to"
+}
+
+// historyRegistry stores history configurations per database
+type historyRegistry struct {
+	configs map[string]*HistoryConfig // table -> config
+	mu      sync.RWMutex
+}
+
+// newHistoryRegistry creates a new history registry
+func newHistoryRegistry() *historyRegistry {
+	return &historyRegistry{
+		configs: make(map[string]*HistoryConfig),
+	}
+}
+
+// set configures history for a table
+func (r *historyRegistry) set(table string, config *HistoryConfig) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.configs[strings.ToLower(table)] = config
+}
+
+// get returns the history config for a table
+func (r *historyRegistry) get(table string) *HistoryConfig {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.configs[strings.ToLower(table)]
+}
+
+// remove removes history config for a table
+func (r *historyRegistry) remove(table string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.configs, strings.ToLower(table))
+}
+
+// --- Global Functions (for default database) ---
+
+// ConfigHistory 为表开启历史版本：Update、Delete（包括软删除和批量更新/删除）执行前，
+// 将受影响行的旧版本连同 valid_from/valid_to 复制到历史表，配合 QueryBuilder.AsOf 查询任意时刻的数据
+// 历史表不存在时按原表的列自动创建（另加 valid_from、valid_to 两列）；historyTable 省略时为 "<table>_history"
+// valid_from 取该行上一个历史版本的 valid_to，没有历史版本时取 ConfigTimestamps 配置的创建时间列（未配置时为 NULL）
+// 注意：只记录通过 eorm 执行的修改，Exec 执行的原始 SQL、SaveRecord/Upsert 的更新部分不会记录
+// 示例:
+//
+//	eorm.ConfigTimestamps("products")
+//	eorm.ConfigHistory("products")
+//	eorm.Table("products").Where("id = ?", 1).Update(eorm.NewRecord().Set("price", 99))
+//	old, _ := eorm.Table("products").AsOf(lastWeek).Where("id = ?", 1).FindFirst()
+func ConfigHistory(table string, historyTable ...string) error {
+	db, err := defaultDB()
+	if err != nil {
+		return err
+	}
+	return db.ConfigHistory(table, historyTable...)
+}
+
+// ConfigHistoryWithConfig 使用自定义表名和列名开启历史版本
+func ConfigHistoryWithConfig(table string, config *HistoryConfig) error {
+	db, err := defaultDB()
+	if err != nil {
+		return err
+	}
+	return db.ConfigHistoryWithConfig(table, config)
+}
+
+// RemoveHistory 停止记录表的历史版本，历史表保留
+func RemoveHistory(table string) {
+	db, err := defaultDB()
+	if err != nil {
+		return
+	}
+	db.RemoveHistory(table)
+}
+
+// --- DB Methods ---
+
+// ConfigHistory 为表开启历史版本，见 ConfigHistory
+func (db *DB) ConfigHistory(table string, historyTable ...string) error {
+	config := &HistoryConfig{}
+	if len(historyTable) > 0 {
+		config.HistoryTable = historyTable[0]
+	}
+	return db.ConfigHistoryWithConfig(table, config)
+}
+
+// ConfigHistoryWithConfig 使用自定义表名和列名开启历史版本
+func (db *DB) ConfigHistoryWithConfig(table string, config *HistoryConfig) error {
+	if db.lastErr != nil {
+		return db.lastErr
+	}
+	if err := validateIdentifier(table); err != nil {
+		return err
+	}
+	c := HistoryConfig{}
+	if config != nil {
+		c = *config
+	}
+	if c.HistoryTable == "" {
+		c.HistoryTable = table + "_history"
+	}
+	if c.ValidFromField == "" {
+		c.ValidFromField = "valid_from"
+	}
+	if c.ValidToField == "" {
+		c.ValidToField = "valid_to"
+	}
+	for _, name := range []string{c.HistoryTable, c.ValidFromField, c.ValidToField} {
+		if err := validateIdentifier(name); err != nil {
+			return err
+		}
+	}
+	if err := db.dbMgr.ensureHistoryTable(table, &c); err != nil {
+		return fmt.Errorf("eorm: cannot create history table %s: %w", c.HistoryTable, err)
+	}
+	if db.dbMgr.histories == nil {
+		db.dbMgr.histories = newHistoryRegistry()
+	}
+	db.dbMgr.histories.set(table, &c)
+	return nil
+}
+
+// RemoveHistory 停止记录表的历史版本
+func (db *DB) RemoveHistory(table string) *DB {
+	if db.lastErr != nil || db.dbMgr == nil {
+		return db
+	}
+	if db.dbMgr.histories != nil {
+		db.dbMgr.histories.remove(table)
+	}
+	return db
+}
+
+// --- QueryBuilder ---
+
+// AsOf 查询表在某一时刻的数据：已被修改或删除的行从历史表读取当时的版本，之后未变化的行从原表读取
+// 表必须已通过 ConfigHistory 开启历史版本；其他条件、排序、分页照常作用于该时刻的数据
+// 未配置创建时间列时，t 之后插入且从未修改过的行也会出现在结果中
+// 示例:
+//
+//	products, err := eorm.Table("products").AsOf(time.Now().Add(-24 * time.Hour)).Where("category = ?", "book").Find()
+func (qb *QueryBuilder) AsOf(t time.Time) *QueryBuilder {
+	if qb.lastErr != nil {
+		return qb
+	}
+	var mgr *dbManager
+	if qb.tx != nil {
+		mgr = qb.tx.dbMgr
+	} else if qb.db != nil {
+		mgr = qb.db.dbMgr
+	}
+	if mgr == nil {
+		qb.lastErr = fmt.Errorf("eorm: AsOf requires a database")
+		return qb
+	}
+	source, args, err := mgr.historyAsOfSource(qb.table, t)
+	if err != nil {
+		qb.lastErr = err
+		return qb
+	}
+	qb.asOfSQL, qb.asOfArgs = source, args
+	return qb
+}
+
+// --- dbManager Methods ---
+
+// getHistoryConfig gets history config for a table
+func (mgr *dbManager) getHistoryConfig(table string) *HistoryConfig {
+	if mgr.histories == nil {
+		return nil
+	}
+	return mgr.histories.get(table)
+}
+
+// ensureHistoryTable 历史表不存在时按原表的列创建（不含约束和自增属性），并添加 valid_from、valid_to 列
+func (mgr *dbManager) ensureHistoryTable(table string, config *HistoryConfig) error {
+	db, err := mgr.getDB()
+	if err != nil {
+		return err
+	}
+	probe := fmt.Sprintf("SELECT 1 FROM %s WHERE 1 = 0", config.HistoryTable)
+	if rows, err := db.Query(probe); err == nil {
+		rows.Close()
+		return nil
+	}
+
+	var statements []string
+	var timeType, addColumn string
+	switch mgr.config.Load().Driver {
+	case SQLServer:
+		// UNION ALL 让 SELECT INTO 不复制 IDENTITY 属性
+		statements = append(statements, fmt.Sprintf("SELECT * INTO %s FROM %s WHERE 1 = 0 UNION ALL SELECT * FROM %s WHERE 1 = 0", config.HistoryTable, table, table))
+		timeType, addColumn = "DATETIME2", "ALTER TABLE %s ADD %s %s"
+	case Oracle:
+		statements = append(statements, fmt.Sprintf("CREATE TABLE %s AS SELECT * FROM %s WHERE 1 = 0", config.HistoryTable, table))
+		timeType, addColumn = "TIMESTAMP", "ALTER TABLE %s ADD (%s %s)"
+	case MySQL:
+		statements = append(statements, fmt.Sprintf("CREATE TABLE %s AS SELECT * FROM %s WHERE 1 = 0", config.HistoryTable, table))
+		timeType, addColumn = "DATETIME(6)", "ALTER TABLE %s ADD COLUMN %s %s"
+	case PostgreSQL:
+		statements = append(statements, fmt.Sprintf("CREATE TABLE %s AS SELECT * FROM %s WHERE 1 = 0", config.HistoryTable, table))
+		timeType, addColumn = "TIMESTAMP", "ALTER TABLE %s ADD COLUMN %s %s"
+	default:
+		statements = append(statements, fmt.Sprintf("CREATE TABLE %s AS SELECT * FROM %s WHERE 1 = 0", config.HistoryTable, table))
+		timeType, addColumn = "DATETIME", "ALTER TABLE %s ADD COLUMN %s %s"
+	}
+	statements = append(statements,
+		fmt.Sprintf(addColumn, config.HistoryTable, config.ValidFromField, timeType),
+		fmt.Sprintf(addColumn, config.HistoryTable, config.ValidToField, timeType))
+	for _, stmt := range statements {
+		start := time.Now()
+		_, err := db.Exec(stmt)
+		mgr.logTrace(start, stmt, nil, err)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// historyColumns 返回原表的列名和主键
+func (mgr *dbManager) historyColumns(executor sqlExecutor, table string) ([]string, []string, error) {
+	columns, err := mgr.getTableColumns(table)
+	if err != nil {
+		return nil, nil, err
+	}
+	names := make([]string, len(columns))
+	for i, col := range columns {
+		names[i] = col.Name
+	}
+	pks, err := mgr.getPrimaryKeys(executor, table)
+	if err != nil {
+		return nil, nil, err
+	}
+	return names, pks, nil
+}
+
+// historyKeyMatch 生成历史表别名 h 与原表按主键关联的条件
+func historyKeyMatch(table string, pks []string) string {
+	conds := make([]string, len(pks))
+	for i, pk := range pks {
+		conds[i] = fmt.Sprintf("h.%s = %s.%s", pk, table, pk)
+	}
+	return strings.Join(conds, " AND ")
+}
+
+// archiveHistory 将 where 匹配的行复制到历史表，valid_to 为当前时间
+func (mgr *dbManager) archiveHistory(executor sqlExecutor, table, where string, whereArgs []interface{}) error {
+	config := mgr.getHistoryConfig(table)
+	if config == nil {
+		return nil
+	}
+	columns, pks, err := mgr.historyColumns(executor, table)
+	if err != nil {
+		return err
+	}
+
+	validFrom := "NULL"
+	if len(pks) > 0 {
+		validFrom = fmt.Sprintf("(SELECT MAX(h.%s) FROM %s h WHERE %s)", config.ValidToField, config.HistoryTable, historyKeyMatch(table, pks))
+	}
+	if ts := mgr.getTimestampConfig(table); ts != nil && ts.CreatedAtField != "" {
+		validFrom = fmt.Sprintf("COALESCE(%s, %s.%s)", validFrom, table, ts.CreatedAtField)
+	}
+
+	selected := make([]string, len(columns))
+	for i, col := range columns {
+		selected[i] = table + "." + col
+	}
+	querySQL := fmt.Sprintf("INSERT INTO %s (%s, %s, %s) SELECT %s, %s, ? FROM %s",
+		config.HistoryTable, strings.Join(columns, ", "), config.ValidFromField, config.ValidToField,
+		strings.Join(selected, ", "), validFrom, table)
+	if where != "" {
+		querySQL += " WHERE " + where
+	}
+	args := append([]interface{}{time.Now()}, whereArgs...)
+	querySQL, args = mgr.prepareQuerySQL(querySQL, args...)
+
+	start := time.Now()
+	_, err = executor.Exec(querySQL, args...)
+	mgr.logTrace(start, querySQL, args, err)
+	if err != nil {
+		return fmt.Errorf("eorm: failed to archive history for %s: %w", table, err)
+	}
+	return nil
+}
+
+// archiveHistoryForRecords 按记录中的主键值分批归档，用于批量更新和删除
+func (mgr *dbManager) archiveHistoryForRecords(executor sqlExecutor, table string, records []*Record) error {
+	if mgr.getHistoryConfig(table) == nil {
+		return nil
+	}
+	pks, err := mgr.getPrimaryKeys(executor, table)
+	if err != nil {
+		return err
+	}
+	if len(pks) == 0 {
+		return nil
+	}
+	for start := 0; start < len(records); start += DefaultBatchSize {
+		end := start + DefaultBatchSize
+		if end > len(records) {
+			end = len(records)
+		}
+		where, args := recordKeyConditions(pks, records[start:end])
+		if where == "" {
+			continue
+		}
+		if err := mgr.archiveHistory(executor, table, where, args); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// historyAsOfSource 生成表在时刻 t 的数据来源：历史表中当时有效的版本 + 原表中 t 之后未被修改的行
+func (mgr *dbManager) historyAsOfSource(table string, t time.Time) (string, []interface{}, error) {
+	config := mgr.getHistoryConfig(table)
+	if config == nil {
+		return "", nil, fmt.Errorf("eorm: AsOf requires ConfigHistory on table '%s'", table)
+	}
+	db, err := mgr.getDB()
+	if err != nil {
+		return "", nil, err
+	}
+	columns, pks, err := mgr.historyColumns(db, table)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(pks) == 0 {
+		return "", nil, fmt.Errorf("eorm: AsOf requires a primary key on table '%s'", table)
+	}
+	cols := strings.Join(columns, ", ")
+	args := []interface{}{t, t, t}
+	source := fmt.Sprintf("SELECT %s FROM %s WHERE (%s IS NULL OR %s <= ?) AND %s > ?",
+		cols, config.HistoryTable, config.ValidFromField, config.ValidFromField, config.ValidToField)
+	source += fmt.Sprintf(" UNION ALL SELECT %s FROM %s WHERE NOT EXISTS (SELECT 1 FROM %s h WHERE %s AND h.%s > ?)",
+		cols, table, config.HistoryTable, historyKeyMatch(table, pks), config.ValidToField)
+	if ts := mgr.getTimestampConfig(table); ts != nil && ts.CreatedAtField != "" {
+		source += fmt.Sprintf(" AND (%s IS NULL OR %s <= ?)", ts.CreatedAtField, ts.CreatedAtField)
+		args = append(args, t)
+	}
+	return source, args, nil
+}
+
+// withHistoryTx runs fn in a new transaction when the table keeps history
+// and executor is not already a transaction, so the archived rows and the write commit together
+func withHistoryTx[T any](mgr *dbManager, executor sqlExecutor, table string, fn func(executor sqlExecutor) (T, error)) (result T, handled bool, err error) {
+	if mgr.getHistoryConfig(table) == nil {
+		return result, false, nil
+	}
+	return withWriteTx(mgr, executor, fn)
+}
@@ -0,0 +1,220 @@
+package eorm
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultSyncStateTable 保存同步水位的表（位于目标库）
+const defaultSyncStateTable = "eorm_sync_state"
+
+// IncrementalSyncOptions IncrementalSync 的选项
+type IncrementalSyncOptions struct {
+	DestTable  string                  // 目标表名（默认与源表相同）
+	StateTable string                  // 目标库中保存水位的表（默认 eorm_sync_state，不存在时自动创建）
+	Columns    []string                // 同步的列（默认全部列）
+	Where      string                  // 额外的源数据过滤条件
+	Args       []interface{}           // Where 的参数
+	ChunkSize  int                     // 每批写入的行数（默认 DefaultBatchSize）
+	OnProgress func(CopyTableProgress) // 每批写入后回调
+}
+
+// IncrementalSyncResult 一次增量同步的结果
+type IncrementalSyncResult struct {
+	Progress      CopyTableProgress // 本次复制的进度
+	FromWatermark interface{}       // 本次开始时的水位（首次同步为 nil）
+	Watermark     interface{}       // 本次结束时的水位
+}
+
+// IncrementalSync 按水位列（如 updated_at 或自增 id）把源表中新增和修改的行同步到目标库，同步一轮后返回
+// 水位保存在目标库的状态表中，每批写入后更新，中断后再次调用从上次的位置继续；目标表按主键 upsert（ConflictUpdate）
+// 时间类型的水位使用 >= 比较，与上次水位相同时间戳的行会被重新写入（幂等），避免漏掉同一时刻提交的行；
+// 其他类型使用 > 比较。源表中删除的行不会同步
+// 示例:
+//
+//	result, err := eorm.IncrementalSync(eorm.Use("main"), eorm.Use("report"), "orders", "updated_at", nil)
+//	log.Printf("synced %d rows, watermark %v", result.Progress.Written, result.Watermark)
+func IncrementalSync(srcDB, dstDB *DB, table, watermarkColumn string, opts *IncrementalSyncOptions) (IncrementalSyncResult, error) {
+	var result IncrementalSyncResult
+	if opts == nil {
+		opts = &IncrementalSyncOptions{}
+	}
+	if srcDB == nil || dstDB == nil || srcDB.dbMgr == nil || dstDB.dbMgr == nil {
+		return result, fmt.Errorf("eorm: IncrementalSync requires both source and destination databases")
+	}
+	if err := validateIdentifier(watermarkColumn); err != nil {
+		return result, err
+	}
+	destTable := opts.DestTable
+	if destTable == "" {
+		destTable = table
+	}
+	stateTable := opts.StateTable
+	if stateTable == "" {
+		stateTable = defaultSyncStateTable
+	}
+	if err := validateIdentifier(stateTable); err != nil {
+		return result, err
+	}
+
+	goType, err := watermarkGoType(srcDB, table, watermarkColumn)
+	if err != nil {
+		return result, err
+	}
+	syncKey := fmt.Sprintf("%s:%s:%s", srcDB.dbMgr.name, table, destTable)
+	from, err := loadSyncWatermark(dstDB, stateTable, syncKey, goType)
+	if err != nil {
+		return result, err
+	}
+	result.FromWatermark, result.Watermark = from, from
+
+	where, args := opts.Where, opts.Args
+	if from != nil {
+		cmp := ">"
+		if goType == "time.Time" {
+			cmp = ">="
+		}
+		cond := fmt.Sprintf("%s %s ?", watermarkColumn, cmp)
+		if where != "" {
+			cond = "(" + where + ") AND " + cond
+		}
+		where = cond
+		args = append(append([]interface{}{}, args...), from)
+	}
+
+	// Transform 按读取顺序记录最后一行的水位，每批提交后写入状态表
+	var lastSeen interface{}
+	copyOpts := &CopyTableOptions{
+		DestTable: destTable,
+		Columns:   opts.Columns,
+		Where:     where,
+		Args:      args,
+		OrderBy:   watermarkColumn,
+		ChunkSize: opts.ChunkSize,
+		Conflict:  ConflictUpdate,
+		Transform: func(r *Record) error {
+			if v := r.Get(watermarkColumn); v != nil {
+				lastSeen = v
+			}
+			return nil
+		},
+		OnProgress: func(p CopyTableProgress) {
+			if lastSeen != nil {
+				if err := saveSyncWatermark(dstDB, stateTable, syncKey, lastSeen); err != nil {
+					LogError("保存同步水位失败", NewRecord().
+						Set("table", table).
+						Set("error", err.Error()))
+				} else {
+					result.Watermark = lastSeen
+				}
+			}
+			if opts.OnProgress != nil {
+				opts.OnProgress(p)
+			}
+		},
+	}
+	result.Progress, err = CopyTable(srcDB, dstDB, table, copyOpts)
+	return result, err
+}
+
+// RunIncrementalSync 每隔 interval 执行一次 IncrementalSync，直到 ctx 结束；单轮失败时记录错误日志并在下一轮重试
+// 示例:
+//
+//	go eorm.RunIncrementalSync(ctx, eorm.Use("main"), eorm.Use("report"), "orders", "updated_at", time.Minute, nil)
+func RunIncrementalSync(ctx context.Context, srcDB, dstDB *DB, table, watermarkColumn string, interval time.Duration, opts *IncrementalSyncOptions) error {
+	if interval <= 0 {
+		return fmt.Errorf("eorm: RunIncrementalSync interval must be positive, got %v", interval)
+	}
+	ctx = contextOrBackground(ctx)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := IncrementalSync(srcDB, dstDB, table, watermarkColumn, opts); err != nil {
+			LogError("增量同步失败", NewRecord().
+				Set("table", table).
+				Set("error", err.Error()))
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// watermarkGoType 返回源表水位列对应的 Go 类型
+func watermarkGoType(srcDB *DB, table, column string) (string, error) {
+	columns, err := srcDB.dbMgr.getTableColumns(table)
+	if err != nil {
+		return "", err
+	}
+	for _, col := range columns {
+		if strings.EqualFold(col.Name, column) {
+			return dbTypeToGoType(col.Type, false, false), nil
+		}
+	}
+	return "", fmt.Errorf("eorm: watermark column '%s' not found in table '%s'", column, table)
+}
+
+// loadSyncWatermark 读取保存的水位并转换为水位列的类型，状态表不存在时创建
+func loadSyncWatermark(dstDB *DB, stateTable, syncKey, goType string) (interface{}, error) {
+	querySQL := fmt.Sprintf("SELECT watermark FROM %s WHERE sync_key = ?", stateTable)
+	record, err := dstDB.QueryFirst(querySQL, syncKey)
+	if err != nil {
+		if createErr := createSyncStateTable(dstDB, stateTable); createErr != nil {
+			return nil, fmt.Errorf("eorm: cannot read sync state (%v) or create table %s: %w", err, stateTable, createErr)
+		}
+		return nil, nil
+	}
+	if record == nil || record.Get("watermark") == nil {
+		return nil, nil
+	}
+	text := record.GetString("watermark")
+	if goType == "time.Time" {
+		if t, err := time.Parse(time.RFC3339Nano, text); err == nil {
+			return t, nil
+		}
+	}
+	return coerceValue(text, goType)
+}
+
+// saveSyncWatermark 保存水位，时间以 RFC3339Nano 文本保存
+func saveSyncWatermark(dstDB *DB, stateTable, syncKey string, watermark interface{}) error {
+	var text string
+	switch v := watermark.(type) {
+	case time.Time:
+		text = v.Format(time.RFC3339Nano)
+	case []byte:
+		text = string(v)
+	case int64:
+		text = strconv.FormatInt(v, 10)
+	default:
+		text = fmt.Sprint(v)
+	}
+	record := NewRecord().
+		Set("sync_key", syncKey).
+		Set("watermark", text).
+		Set("updated_at", time.Now())
+	_, err := dstDB.SaveRecord(stateTable, record)
+	return err
+}
+
+// createSyncStateTable 在目标库创建状态表
+func createSyncStateTable(dstDB *DB, stateTable string) error {
+	var ddl string
+	switch dstDB.dbMgr.config.Load().Driver {
+	case SQLServer:
+		ddl = "CREATE TABLE %s (sync_key NVARCHAR(255) NOT NULL PRIMARY KEY, watermark NVARCHAR(255), updated_at DATETIME2)"
+	case Oracle:
+		ddl = "CREATE TABLE %s (sync_key VARCHAR2(255) NOT NULL PRIMARY KEY, watermark VARCHAR2(255), updated_at TIMESTAMP)"
+	case PostgreSQL:
+		ddl = "CREATE TABLE IF NOT EXISTS %s (sync_key VARCHAR(255) NOT NULL PRIMARY KEY, watermark VARCHAR(255), updated_at TIMESTAMP)"
+	default:
+		ddl = "CREATE TABLE IF NOT EXISTS %s (sync_key VARCHAR(255) NOT NULL PRIMARY KEY, watermark VARCHAR(255), updated_at DATETIME)"
+	}
+	_, err := dstDB.Exec(fmt.Sprintf(ddl, stateTable))
+	return err
+}
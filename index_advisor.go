@@ -0,0 +1,356 @@
+package eorm
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxAdvisorQueries 索引建议器最多收集的不同慢查询数
+const maxAdvisorQueries = 500
+
+// SlowQueryStat 收集到的一条慢查询（按 SQL 文本聚合）
+type SlowQueryStat struct {
+	SQL            string        // 慢查询 SQL（参数化后的文本）
+	Args           []interface{} // 最近一次执行的参数，用于 EXPLAIN
+	Count          int64         // 超过阈值的次数
+	TotalDuration  time.Duration // 累计耗时
+	MaxDuration    time.Duration // 最大耗时
+	FullScanTables []string      // EXPLAIN 检测到全表扫描的表（仅报告中填充）
+	ExplainError   string        // EXPLAIN 失败时的错误（仅报告中填充）
+}
+
+// IndexSuggestion 候选索引建议
+type IndexSuggestion struct {
+	Table     string        // 表名
+	Columns   []string      // 建议的索引列：等值条件列在前，其次为范围条件列和排序列
+	CreateSQL string        // 建议的建索引语句
+	Queries   []string      // 受益的慢查询
+	Count     int64         // 受益慢查询的累计次数
+	Duration  time.Duration // 受益慢查询的累计耗时
+}
+
+// IndexAdvisorReport 索引建议报告
+type IndexAdvisorReport struct {
+	GeneratedAt time.Time
+	Queries     []SlowQueryStat   // 按累计耗时降序排列
+	Suggestions []IndexSuggestion // 按受益慢查询累计耗时降序排列
+}
+
+// indexAdvisor 慢查询收集器
+type indexAdvisor struct {
+	threshold time.Duration
+	tables    map[string]bool // 关注的表（小写），为空表示全部
+	mu        sync.Mutex
+	queries   map[string]*SlowQueryStat
+}
+
+// selectSQLRe 匹配查询语句
+var selectSQLRe = regexp.MustCompile(`(?i)^\s*(?:SELECT|WITH)\b`)
+
+// EnableIndexAdvisor 为默认数据库开启索引建议器：收集耗时超过 threshold 的查询，threshold <= 0 关闭
+// tables 指定关注的表（为空表示全部），通过 GetIndexAdvisorReport 对收集到的慢查询执行 EXPLAIN，
+// 检测全表扫描并根据 WHERE/ORDER BY 列给出候选索引；支持 MySQL、PostgreSQL、SQLite
+// 示例:
+//
+//	eorm.EnableIndexAdvisor(200*time.Millisecond, "orders", "users")
+//	// ... 运行一段时间后
+//	report, _ := eorm.GetIndexAdvisorReport()
+//	for _, s := range report.Suggestions {
+//	    fmt.Println(s.CreateSQL, s.Count, s.Duration)
+//	}
+func EnableIndexAdvisor(threshold time.Duration, tables ...string) {
+	db, err := defaultDB()
+	if err != nil {
+		return
+	}
+	db.EnableIndexAdvisor(threshold, tables...)
+}
+
+// EnableIndexAdvisor 为当前数据库开启索引建议器，见 EnableIndexAdvisor
+func (db *DB) EnableIndexAdvisor(threshold time.Duration, tables ...string) *DB {
+	if db.lastErr != nil {
+		return db
+	}
+	db.dbMgr.mu.Lock()
+	defer db.dbMgr.mu.Unlock()
+	if threshold <= 0 {
+		db.dbMgr.indexAdvisor = nil
+		return db
+	}
+	advisor := &indexAdvisor{
+		threshold: threshold,
+		tables:    make(map[string]bool, len(tables)),
+		queries:   make(map[string]*SlowQueryStat),
+	}
+	for _, t := range tables {
+		advisor.tables[normalizeDDLTable(t)] = true
+	}
+	db.dbMgr.indexAdvisor = advisor
+	return db
+}
+
+// ResetIndexAdvisor 清空默认数据库已收集的慢查询
+func ResetIndexAdvisor() {
+	db, err := defaultDB()
+	if err != nil {
+		return
+	}
+	db.ResetIndexAdvisor()
+}
+
+// ResetIndexAdvisor 清空当前数据库已收集的慢查询
+func (db *DB) ResetIndexAdvisor() *DB {
+	if db.lastErr != nil {
+		return db
+	}
+	if advisor := db.dbMgr.getIndexAdvisor(); advisor != nil {
+		advisor.mu.Lock()
+		advisor.queries = make(map[string]*SlowQueryStat)
+		advisor.mu.Unlock()
+	}
+	return db
+}
+
+// GetIndexAdvisorReport 对默认数据库收集到的慢查询执行 EXPLAIN 并生成索引建议报告
+func GetIndexAdvisorReport() (*IndexAdvisorReport, error) {
+	db, err := defaultDB()
+	if err != nil {
+		return nil, err
+	}
+	return db.GetIndexAdvisorReport()
+}
+
+// GetIndexAdvisorReport 对当前数据库收集到的慢查询执行 EXPLAIN 并生成索引建议报告
+func (db *DB) GetIndexAdvisorReport() (*IndexAdvisorReport, error) {
+	if db.lastErr != nil {
+		return nil, db.lastErr
+	}
+	mgr := db.dbMgr
+	advisor := mgr.getIndexAdvisor()
+	if advisor == nil {
+		return nil, fmt.Errorf("eorm: index advisor is not enabled for database %s", mgr.name)
+	}
+	switch mgr.config.Load().Driver {
+	case MySQL, PostgreSQL, SQLite3:
+	default:
+		return nil, fmt.Errorf("eorm: index advisor does not support driver %s", mgr.config.Load().Driver)
+	}
+
+	advisor.mu.Lock()
+	queries := make([]SlowQueryStat, 0, len(advisor.queries))
+	for _, q := range advisor.queries {
+		queries = append(queries, *q)
+	}
+	advisor.mu.Unlock()
+	sort.Slice(queries, func(i, j int) bool { return queries[i].TotalDuration > queries[j].TotalDuration })
+
+	report := &IndexAdvisorReport{GeneratedAt: time.Now(), Queries: queries}
+	suggestions := make(map[string]*IndexSuggestion)
+	var order []string
+	for i := range queries {
+		q := &queries[i]
+		tables, err := mgr.explainFullScans(q.SQL, q.Args)
+		if err != nil {
+			q.ExplainError = err.Error()
+			continue
+		}
+		for _, table := range tables {
+			if len(advisor.tables) > 0 && !advisor.tables[normalizeDDLTable(table)] {
+				continue
+			}
+			q.FullScanTables = append(q.FullScanTables, table)
+			columns := mgr.candidateIndexColumns(table, q.SQL)
+			if len(columns) == 0 {
+				continue
+			}
+			key := strings.ToLower(table + "(" + strings.Join(columns, ",") + ")")
+			s, ok := suggestions[key]
+			if !ok {
+				s = &IndexSuggestion{
+					Table:     table,
+					Columns:   columns,
+					CreateSQL: fmt.Sprintf("CREATE INDEX idx_%s_%s ON %s (%s)", normalizeDDLTable(table), strings.Join(columns, "_"), table, strings.Join(columns, ", ")),
+				}
+				suggestions[key] = s
+				order = append(order, key)
+			}
+			s.Queries = append(s.Queries, q.SQL)
+			s.Count += q.Count
+			s.Duration += q.TotalDuration
+		}
+	}
+	for _, key := range order {
+		report.Suggestions = append(report.Suggestions, *suggestions[key])
+	}
+	sort.SliceStable(report.Suggestions, func(i, j int) bool {
+		return report.Suggestions[i].Duration > report.Suggestions[j].Duration
+	})
+	return report, nil
+}
+
+// getIndexAdvisor 返回索引建议器，未开启时返回 nil
+func (mgr *dbManager) getIndexAdvisor() *indexAdvisor {
+	mgr.mu.RLock()
+	defer mgr.mu.RUnlock()
+	return mgr.indexAdvisor
+}
+
+// recordSlowQuery 记录超过阈值的查询语句
+func (mgr *dbManager) recordSlowQuery(querySQL string, args []interface{}, duration time.Duration) {
+	advisor := mgr.getIndexAdvisor()
+	if advisor == nil || duration < advisor.threshold || !selectSQLRe.MatchString(querySQL) {
+		return
+	}
+	key := strings.Join(strings.Fields(querySQL), " ")
+	advisor.mu.Lock()
+	defer advisor.mu.Unlock()
+	q, ok := advisor.queries[key]
+	if !ok {
+		if len(advisor.queries) >= maxAdvisorQueries {
+			return
+		}
+		q = &SlowQueryStat{SQL: key}
+		advisor.queries[key] = q
+	}
+	q.Args = args
+	q.Count++
+	q.TotalDuration += duration
+	if duration > q.MaxDuration {
+		q.MaxDuration = duration
+	}
+}
+
+// sqliteScanRe 匹配 SQLite 查询计划中的全表扫描（SCAN t / SCAN TABLE t，不含使用索引的扫描）
+var sqliteScanRe = regexp.MustCompile(`(?i)^SCAN\s+(?:TABLE\s+)?(\w+)`)
+
+// postgresSeqScanRe 匹配 PostgreSQL 查询计划中的顺序扫描
+var postgresSeqScanRe = regexp.MustCompile(`(?i)Seq Scan on\s+(?:\w+\.)?(\w+)`)
+
+// explainFullScans 执行 EXPLAIN 并返回全表扫描的表
+func (mgr *dbManager) explainFullScans(querySQL string, args []interface{}) ([]string, error) {
+	db, err := mgr.getDB()
+	if err != nil {
+		return nil, err
+	}
+	var tables []string
+	seen := make(map[string]bool)
+	add := func(table string) {
+		if table != "" && !seen[strings.ToLower(table)] {
+			seen[strings.ToLower(table)] = true
+			tables = append(tables, table)
+		}
+	}
+
+	switch mgr.config.Load().Driver {
+	case MySQL:
+		rows, err := mgr.queryInternal(db, "EXPLAIN "+querySQL, args...)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range rows {
+			if strings.EqualFold(r.GetString("type"), "ALL") {
+				add(r.GetString("table"))
+			}
+		}
+	case PostgreSQL:
+		rows, err := mgr.queryInternal(db, "EXPLAIN "+querySQL, args...)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range rows {
+			if m := postgresSeqScanRe.FindStringSubmatch(r.GetString("QUERY PLAN")); len(m) == 2 {
+				add(m[1])
+			}
+		}
+	case SQLite3:
+		rows, err := mgr.queryInternal(db, "EXPLAIN QUERY PLAN "+querySQL, args...)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range rows {
+			detail := r.GetString("detail")
+			if strings.Contains(strings.ToUpper(detail), " USING ") {
+				continue
+			}
+			if m := sqliteScanRe.FindStringSubmatch(detail); len(m) == 2 {
+				add(m[1])
+			}
+		}
+	}
+	return tables, nil
+}
+
+var (
+	// advisorWhereRe 截取 WHERE 子句
+	advisorWhereRe = regexp.MustCompile(`(?is)\bWHERE\b(.*?)(?:\bGROUP\s+BY\b|\bORDER\s+BY\b|\bLIMIT\b|\bOFFSET\b|\bFETCH\b|$)`)
+	// advisorOrderRe 截取 ORDER BY 子句
+	advisorOrderRe = regexp.MustCompile(`(?is)\bORDER\s+BY\b(.*?)(?:\bLIMIT\b|\bOFFSET\b|\bFETCH\b|$)`)
+	// advisorEqualRe 匹配等值条件列
+	advisorEqualRe = regexp.MustCompile(`(?i)([\w.` + "`" + `"]+)\s*(?:=|\bIN\s*\(|\bIS\s+NULL\b)`)
+	// advisorRangeRe 匹配范围条件列
+	advisorRangeRe = regexp.MustCompile(`(?i)([\w.` + "`" + `"]+)\s*(?:<=|>=|<|>|\bBETWEEN\b|\bLIKE\s+'[^%_])`)
+)
+
+// candidateIndexColumns 根据 WHERE 等值列、范围列和 ORDER BY 列推断候选索引，只保留表中存在的列；
+// 已有索引以这些列为前缀时不再建议
+func (mgr *dbManager) candidateIndexColumns(table, querySQL string) []string {
+	columns, err := mgr.getTableColumns(table)
+	if err != nil {
+		return nil
+	}
+	known := make(map[string]string, len(columns))
+	for _, c := range columns {
+		known[strings.ToLower(c.Name)] = c.Name
+	}
+
+	var result []string
+	used := make(map[string]bool)
+	add := func(ref string) {
+		name := normalizeDDLTable(ref)
+		if col, ok := known[name]; ok && !used[name] {
+			used[name] = true
+			result = append(result, col)
+		}
+	}
+	if m := advisorWhereRe.FindStringSubmatch(querySQL); len(m) == 2 {
+		for _, e := range advisorEqualRe.FindAllStringSubmatch(m[1], -1) {
+			add(e[1])
+		}
+		for _, r := range advisorRangeRe.FindAllStringSubmatch(m[1], -1) {
+			add(r[1])
+		}
+	}
+	if m := advisorOrderRe.FindStringSubmatch(querySQL); len(m) == 2 {
+		for _, part := range strings.Split(m[1], ",") {
+			if fields := strings.Fields(part); len(fields) > 0 {
+				add(fields[0])
+			}
+		}
+	}
+	if len(result) == 0 {
+		return nil
+	}
+
+	if indexes, err := mgr.getTableIndexes(table); err == nil {
+		for _, idx := range indexes {
+			if len(idx.Columns) < len(result) {
+				continue
+			}
+			covered := true
+			for i, c := range result {
+				if !strings.EqualFold(idx.Columns[i], c) {
+					covered = false
+					break
+				}
+			}
+			if covered {
+				return nil
+			}
+		}
+	}
+	return result
+}
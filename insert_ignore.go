@@ -0,0 +1,174 @@
+package eorm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// insertRecordIgnore 插入记录，主键或唯一键冲突时忽略，返回是否实际插入了新行
+// MySQL: INSERT IGNORE
+// PostgreSQL/SQLite: INSERT ... ON CONFLICT DO NOTHING
+// Oracle/SQL Server: MERGE ... WHEN NOT MATCHED THEN INSERT（按主键判断，记录中需包含全部主键）
+func (mgr *dbManager) insertRecordIgnore(ctx context.Context, executor sqlExecutor, table string, record *Record) (inserted bool, err error) {
+	defer func() { mgr.afterTableWrite(executor, table, err) }()
+	if ok, handled, txErr := withCounterCacheTx(mgr, executor, table, func(tx sqlExecutor) (bool, error) {
+		return mgr.insertRecordIgnore(ctx, tx, table, record)
+	}); handled {
+		return ok, txErr
+	}
+	defer func() {
+		if err == nil && inserted {
+			mgr.publishInserts(executor, table, []*Record{record}, 0)
+		}
+	}()
+	defer func() {
+		if err == nil && inserted {
+			err = mgr.incrementCounterCaches(executor, table, []*Record{record})
+		}
+	}()
+	if err := validateIdentifier(table); err != nil {
+		return false, err
+	}
+	if record == nil || len(record.columns) == 0 {
+		return false, fmt.Errorf("record is empty")
+	}
+	mgr.applyColumnDefaults(table, record)
+	if err := mgr.validateRecordColumns(table, record); err != nil {
+		return false, err
+	}
+	if err := mgr.checkActiveUnique(executor, table, record); err != nil {
+		// 与未删除记录的唯一性冲突按唯一约束冲突处理，忽略本次插入
+		if errors.Is(err, ErrActiveUniqueViolation) {
+			return false, nil
+		}
+		return false, err
+	}
+	if err := mgr.coerceRecordTypes(table, record); err != nil {
+		return false, err
+	}
+
+	mgr.applyCreatedAtTimestamp(table, record, false)
+	mgr.applyVersionInit(table, record)
+	if err := mgr.applySequence(executor, table, record); err != nil {
+		return false, err
+	}
+
+	driver := mgr.config.Load().Driver
+	columns, values := mgr.getOrderedColumnsForInsert(record, table, executor)
+
+	var querySQL string
+	switch driver {
+	case MySQL, PostgreSQL, SQLite3:
+		placeholders := make([]string, len(columns))
+		for i := range placeholders {
+			placeholders[i] = "?"
+		}
+		if driver == MySQL {
+			querySQL = fmt.Sprintf("INSERT IGNORE INTO %s (%s) VALUES (%s)", table, joinStrings(columns), joinStrings(placeholders))
+		} else {
+			querySQL = fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) ON CONFLICT DO NOTHING", table, joinStrings(columns), joinStrings(placeholders))
+		}
+	case Oracle, SQLServer:
+		pks, _ := mgr.getPrimaryKeys(executor, table)
+		if !hasAllPrimaryKeys(record, pks) {
+			return false, fmt.Errorf("eorm: InsertRecordIgnore on %s requires all primary key values in the record", driver)
+		}
+		querySQL, values = mgr.buildMergeInsertIgnore(table, columns, values, pks)
+	default:
+		return false, fmt.Errorf("eorm: InsertRecordIgnore is not supported for driver %s", driver)
+	}
+
+	querySQL, values = expandSqlExprArgs(querySQL, values)
+	querySQL = mgr.convertPlaceholder(querySQL, driver)
+	values = mgr.sanitizeArgs(querySQL, values)
+	start := time.Now()
+	res, err := mgr.execWrite(ctx, executor, querySQL, values...)
+	mgr.logTrace(start, querySQL, values, err)
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// buildMergeInsertIgnore 构造只在主键不存在时插入的 MERGE 语句（Oracle/SQL Server）
+func (mgr *dbManager) buildMergeInsertIgnore(table string, columns []string, values []interface{}, pks []string) (string, []interface{}) {
+	driver := mgr.config.Load().Driver
+	selectCols := make([]string, len(columns))
+	insertVals := make([]string, len(columns))
+	for i, col := range columns {
+		selectCols[i] = "? AS " + col
+		insertVals[i] = "s." + col
+	}
+	usingSQL := "SELECT " + strings.Join(selectCols, ", ")
+	if driver == Oracle {
+		usingSQL += " FROM DUAL"
+	}
+
+	onClauses := make([]string, len(pks))
+	for i, pk := range pks {
+		onClauses[i] = fmt.Sprintf("t.%s = s.%s", pk, pk)
+	}
+
+	querySQL := fmt.Sprintf("MERGE INTO %s t USING (%s) s ON (%s) WHEN NOT MATCHED THEN INSERT (%s) VALUES (%s)",
+		table, usingSQL, strings.Join(onClauses, " AND "), strings.Join(columns, ", "), strings.Join(insertVals, ", "))
+	if driver == SQLServer {
+		querySQL += ";" // SQL Server 的 MERGE 语句必须以分号结束
+	}
+	return querySQL, values
+}
+
+// hasAllPrimaryKeys 判断记录是否包含全部主键且值不为空
+func hasAllPrimaryKeys(record *Record, pks []string) bool {
+	if len(pks) == 0 {
+		return false
+	}
+	for _, pk := range pks {
+		if record.Get(pk) == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// InsertRecordIgnore 插入记录，主键或唯一键冲突时忽略而不报错，返回是否实际插入了新行
+// 适合消息/事件的幂等写入；与 ConfigActiveUnique 配置的未删除记录唯一性冲突时同样忽略
+// 注意：MySQL 的 INSERT IGNORE 还会忽略数据截断等其他错误；Oracle/SQL Server 仅按主键判断冲突
+func InsertRecordIgnore(table string, record *Record) (bool, error) {
+	db, err := defaultDB()
+	if err != nil {
+		return false, err
+	}
+	return db.InsertRecordIgnore(table, record)
+}
+
+// InsertRecordIgnore 插入记录，冲突时忽略，返回是否实际插入了新行
+func (db *DB) InsertRecordIgnore(table string, record *Record) (bool, error) {
+	if db.lastErr != nil {
+		return false, db.lastErr
+	}
+	executor, err := db.getExecutor()
+	if err != nil {
+		return false, err
+	}
+	inserted, err := db.dbMgr.insertRecordIgnore(db.ctx, executor, table, record)
+	if err == nil && inserted && db.cacheRepositoryName != "" {
+		db.ClearCache(db.cacheRepositoryName)
+	}
+	return inserted, err
+}
+
+// InsertRecordIgnore 在事务中插入记录，冲突时忽略，返回是否实际插入了新行
+func (tx *Tx) InsertRecordIgnore(table string, record *Record) (bool, error) {
+	inserted, err := tx.dbMgr.insertRecordIgnore(tx.ctx, tx.tx, table, record)
+	if err == nil && inserted && tx.cacheRepositoryName != "" {
+		tx.ClearCache(tx.cacheRepositoryName)
+	}
+	return inserted, err
+}
@@ -0,0 +1,117 @@
+package eorm
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// FromSQLDB 使用已有的 *sql.DB 注册数据库（默认名称 "default"），与 GORM、sqlx 等共享同一个连接池
+// 连接池参数由创建 *sql.DB 的一方管理，eorm 不会修改；关闭 eorm 的 DB 会关闭共享的连接池
+// 示例:
+//
+//	sqlDB, _ := gormDB.DB()                   // GORM
+//	db, err := eorm.FromSQLDB(sqlDB, eorm.MySQL)
+//
+//	db, err := eorm.FromSQLDB(sqlxDB.DB, eorm.PostgreSQL, "reporting") // sqlx，指定数据库名称
+func FromSQLDB(sqlDB *sql.DB, driver DriverType, dbname ...string) (*DB, error) {
+	name := "default"
+	if len(dbname) > 0 && dbname[0] != "" {
+		name = dbname[0]
+	}
+	return OpenDatabaseWithDB(name, driver, sqlDB)
+}
+
+// SqlDB 返回当前数据库的 *sql.DB，数据库未打开时返回 nil
+// 示例: sqlxDB := sqlx.NewDb(eorm.SqlDB(), "mysql")
+func SqlDB() *sql.DB {
+	db, err := defaultDB()
+	if err != nil {
+		return nil
+	}
+	return db.SqlDB()
+}
+
+// SqlDB 返回底层的 *sql.DB，出错时返回 nil（需要错误信息时使用 GetDB）
+// 示例: gormDB, err := gorm.Open(mysql.New(mysql.Config{Conn: eorm.Use("main").SqlDB()}), &gorm.Config{})
+func (db *DB) SqlDB() *sql.DB {
+	if db.lastErr != nil || db.dbMgr == nil {
+		return nil
+	}
+	sqlDB, err := db.dbMgr.getDB()
+	if err != nil {
+		return nil
+	}
+	return sqlDB
+}
+
+// SqlTx 返回底层的 *sql.Tx，用于让 GORM、sqlx 的操作加入 eorm 开启的事务
+// 事务仍由 eorm 提交或回滚；通过 *sql.Tx 直接执行的写操作不会触发 eorm 的缓存失效和变更事件
+// 示例:
+//
+//	eorm.Transaction(func(tx *eorm.Tx) error {
+//	    sqlxTx := &sqlx.Tx{Tx: tx.SqlTx(), Mapper: sqlxDB.Mapper}
+//	    ...
+//	})
+func (tx *Tx) SqlTx() *sql.Tx {
+	return tx.tx
+}
+
+// ScanRecord 把 rows 的当前行扫描为 Record，在调用方自己的 rows.Next() 循环中使用
+// sqlx.Rows 内嵌 *sql.Rows，可传入 rows.Rows；需要读取全部行时使用 ScanRecords
+// 示例:
+//
+//	rows, _ := sqlxDB.Queryx("SELECT * FROM users")
+//	defer rows.Close()
+//	for rows.Next() {
+//	    record, err := eorm.ScanRecord(rows.Rows)
+//	    ...
+//	}
+func ScanRecord(rows *sql.Rows) (*Record, error) {
+	if rows == nil {
+		return nil, fmt.Errorf("eorm: rows cannot be nil")
+	}
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+	dbTypes := make([]string, len(columns))
+	for i := range columnTypes {
+		dbTypes[i] = strings.ToUpper(columnTypes[i].DatabaseTypeName())
+	}
+	cols := newScanColumns(columns, dbTypes)
+
+	values := make([]interface{}, len(columns))
+	valuePtrs := make([]interface{}, len(columns))
+	for i := range columns {
+		valuePtrs[i] = &values[i]
+	}
+	if err := rows.Scan(valuePtrs...); err != nil {
+		return nil, err
+	}
+	record := NewRecord()
+	for i, col := range cols.names {
+		record.setDirectLower(col, cols.lowers[i], processDBValue(values[i], cols.dbTypes[i]))
+	}
+	return record, nil
+}
+
+// RecordsToMaps 把一组 Record 转换为 map，用于交给 GORM 的 Create(map) 或 sqlx 的 NamedExec
+func RecordsToMaps(records []*Record) []map[string]interface{} {
+	maps := make([]map[string]interface{}, 0, len(records))
+	for _, record := range records {
+		if record != nil {
+			maps = append(maps, record.ToMap())
+		}
+	}
+	return maps
+}
+
+// RecordsFromMaps 把一组 map 转换为 Record，用于接收 GORM 的 Find(&[]map[string]interface{}) 或 sqlx 的 MapScan 结果
+func RecordsFromMaps(maps []map[string]interface{}) []*Record {
+	return convertMapSliceToRecords(maps)
+}
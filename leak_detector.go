@@ -0,0 +1,190 @@
+package eorm
+
+import (
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// HeldConnection 泄漏检测中一个仍未释放的连接持有者
+type HeldConnection struct {
+	Kind       string        // 持有者类型，如 "transaction"
+	DB         string        // 数据库名称
+	AcquiredAt time.Time     // 获取时间
+	HeldFor    time.Duration // 已持有时长
+	Stack      string        // 获取时的调用栈
+}
+
+// leakHandle 一个被跟踪的连接持有者
+type leakHandle struct {
+	id       uint64
+	kind     string
+	db       string
+	stack    string
+	acquired time.Time
+	reported bool
+}
+
+// leakDetector 连接泄漏检测器状态
+var leakDetector = struct {
+	mu        sync.Mutex
+	threshold time.Duration
+	handles   map[uint64]*leakHandle
+	nextID    uint64
+	stop      chan struct{}
+}{handles: make(map[uint64]*leakHandle)}
+
+// SetLeakDetection 开启连接泄漏检测（用于开发和测试环境），threshold <= 0 关闭
+// 开启后，手动管理的连接持有者（BeginTransaction 返回的事务）在获取时记录调用栈：
+// 持有超过 threshold 仍未释放（Commit/Rollback）时以 Warn 级别报告一次获取位置；
+// 未释放就被垃圾回收时以 Error 级别报告并回滚，归还连接
+// 示例: eorm.SetLeakDetection(30 * time.Second)
+func SetLeakDetection(threshold time.Duration) {
+	leakDetector.mu.Lock()
+	defer leakDetector.mu.Unlock()
+	if leakDetector.stop != nil {
+		close(leakDetector.stop)
+		leakDetector.stop = nil
+	}
+	leakDetector.threshold = threshold
+	if threshold <= 0 {
+		leakDetector.handles = make(map[uint64]*leakHandle)
+		return
+	}
+	interval := threshold / 2
+	if interval < time.Second {
+		interval = time.Second
+	}
+	leakDetector.stop = make(chan struct{})
+	go runLeakDetector(interval, leakDetector.stop)
+}
+
+// HeldConnections 返回泄漏检测中仍未释放的连接持有者，按持有时长从长到短排序
+// 未开启泄漏检测时返回空
+func HeldConnections() []HeldConnection {
+	leakDetector.mu.Lock()
+	defer leakDetector.mu.Unlock()
+	now := time.Now()
+	result := make([]HeldConnection, 0, len(leakDetector.handles))
+	for _, h := range leakDetector.handles {
+		result = append(result, HeldConnection{
+			Kind:       h.kind,
+			DB:         h.db,
+			AcquiredAt: h.acquired,
+			HeldFor:    now.Sub(h.acquired),
+			Stack:      h.stack,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].HeldFor > result[j].HeldFor })
+	return result
+}
+
+// trackLeak 开始跟踪一个连接持有者，未开启泄漏检测时返回 0
+func trackLeak(kind, db string) uint64 {
+	leakDetector.mu.Lock()
+	enabled := leakDetector.threshold > 0
+	leakDetector.mu.Unlock()
+	if !enabled {
+		return 0
+	}
+	stack := captureStack()
+
+	leakDetector.mu.Lock()
+	defer leakDetector.mu.Unlock()
+	id := atomic.AddUint64(&leakDetector.nextID, 1)
+	leakDetector.handles[id] = &leakHandle{
+		id:       id,
+		kind:     kind,
+		db:       db,
+		stack:    stack,
+		acquired: time.Now(),
+	}
+	return id
+}
+
+// releaseLeak 停止跟踪连接持有者，返回其是否仍在跟踪中
+func releaseLeak(id uint64) bool {
+	if id == 0 {
+		return false
+	}
+	leakDetector.mu.Lock()
+	defer leakDetector.mu.Unlock()
+	_, ok := leakDetector.handles[id]
+	delete(leakDetector.handles, id)
+	return ok
+}
+
+// leakStack 返回连接持有者获取时的调用栈
+func leakStack(id uint64) string {
+	leakDetector.mu.Lock()
+	defer leakDetector.mu.Unlock()
+	if h, ok := leakDetector.handles[id]; ok {
+		return h.stack
+	}
+	return ""
+}
+
+// runLeakDetector 定期报告持有时间超过阈值的连接持有者
+func runLeakDetector(interval time.Duration, stop chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			reportLeaks()
+		}
+	}
+}
+
+// reportLeaks 报告新超过阈值的连接持有者，每个只报告一次
+func reportLeaks() {
+	leakDetector.mu.Lock()
+	threshold := leakDetector.threshold
+	now := time.Now()
+	var overdue []leakHandle
+	for _, h := range leakDetector.handles {
+		if !h.reported && now.Sub(h.acquired) > threshold {
+			h.reported = true
+			overdue = append(overdue, *h)
+		}
+	}
+	leakDetector.mu.Unlock()
+
+	for _, h := range overdue {
+		LogWarn("疑似连接泄漏：持有时间超过阈值仍未释放", NewRecord().
+			Set("kind", h.kind).
+			Set("db", h.db).
+			Set("held_for", now.Sub(h.acquired).String()).
+			Set("threshold", threshold.String()).
+			Set("stack", h.stack))
+	}
+}
+
+// trackTxLeak 跟踪手动管理的事务，未释放就被回收时报告并回滚
+func trackTxLeak(tx *Tx) {
+	id := trackLeak("transaction", tx.dbMgr.name)
+	if id == 0 {
+		return
+	}
+	tx.leakID = id
+	sqlTx := tx.tx
+	mgr := tx.dbMgr
+	db := mgr.name
+	runtime.SetFinalizer(tx, func(*Tx) {
+		stack := leakStack(id)
+		if !releaseLeak(id) {
+			return
+		}
+		LogError("连接泄漏：事务未提交或回滚即被回收，已自动回滚", NewRecord().
+			Set("kind", "transaction").
+			Set("db", db).
+			Set("stack", stack))
+		_ = sqlTx.Rollback()
+		mgr.finishChanges(sqlTx, false)
+		mgr.releaseTxHooks(sqlTx)
+	})
+}
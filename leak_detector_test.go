@@ -0,0 +1,83 @@
+package eorm
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// beginTrackedTx 与 BeginTransaction 相同，但在指定数据库上开启事务
+func beginTrackedTx(t *testing.T, db *DB) *Tx {
+	t.Helper()
+	sdb, err := db.dbMgr.getDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlTx, watchdog, err := db.dbMgr.beginTx(sdb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := &Tx{tx: sqlTx, dbMgr: db.dbMgr, watchdog: watchdog}
+	trackTxLeak(tx)
+	return tx
+}
+
+// heldBy 返回指定数据库仍未释放的连接持有者
+func heldBy(dbName string) []HeldConnection {
+	var held []HeldConnection
+	for _, h := range HeldConnections() {
+		if h.DB == dbName {
+			held = append(held, h)
+		}
+	}
+	return held
+}
+
+func TestLeakDetectorTracksManualTransactions(t *testing.T) {
+	db := openTestDB(t, "CREATE TABLE ld_items (id INTEGER PRIMARY KEY)")
+	SetLeakDetection(time.Hour)
+	t.Cleanup(func() { SetLeakDetection(0) })
+
+	tx := beginTrackedTx(t, db)
+	held := heldBy(db.dbMgr.name)
+	if len(held) != 1 || held[0].Kind != "transaction" || !strings.Contains(held[0].Stack, "beginTrackedTx") {
+		t.Fatalf("held = %+v, want one transaction with the acquiring stack", held)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if held := heldBy(db.dbMgr.name); len(held) != 0 {
+		t.Fatalf("held after commit = %+v, want none", held)
+	}
+
+	// 写入过程中不提交就丢弃事务，回收时应自动回滚并归还连接
+	func() {
+		tx := beginTrackedTx(t, db)
+		if _, err := tx.Exec("INSERT INTO ld_items (id) VALUES (1)"); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	deadline := time.Now().Add(5 * time.Second)
+	for len(heldBy(db.dbMgr.name)) > 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("leaked transaction was not reclaimed by the finalizer")
+		}
+		runtime.GC()
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := countRows(t, db, "ld_items", ""); n != 0 {
+		t.Fatalf("rows = %d, want 0: the leaked transaction must be rolled back", n)
+	}
+	mustExec(t, db, "INSERT INTO ld_items (id) VALUES (2)")
+}
+
+func TestLeakDetectionDisabled(t *testing.T) {
+	db := openTestDB(t)
+	SetLeakDetection(0)
+	tx := beginTrackedTx(t, db)
+	defer tx.Rollback()
+	if tx.leakID != 0 || len(heldBy(db.dbMgr.name)) != 0 {
+		t.Fatalf("leakID = %d, held = %v; want nothing tracked when detection is off", tx.leakID, heldBy(db.dbMgr.name))
+	}
+}
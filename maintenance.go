@@ -0,0 +1,205 @@
+package eorm
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// truncate 清空表，按数据库类型生成对应语句
+// 各数据库的行为差异：
+//   - MySQL/SQL Server: TRUNCATE 总是重置自增列，不支持 cascade
+//   - PostgreSQL: 支持 RESTART IDENTITY 和 CASCADE
+//   - SQLite: 没有 TRUNCATE，使用 DELETE FROM，restartIdentity 时同时清理 sqlite_sequence
+//   - Oracle: 支持 CASCADE（12c+），不支持重置标识列
+func (mgr *dbManager) truncate(executor sqlExecutor, table string, restartIdentity, cascade bool) (err error) {
+	defer func() { mgr.afterTableWrite(executor, table, err) }()
+	if err := validateIdentifier(table); err != nil {
+		return err
+	}
+
+	driver := mgr.config.Load().Driver
+	var statements []string
+	switch driver {
+	case MySQL, SQLServer:
+		if cascade {
+			return fmt.Errorf("eorm: truncate cascade is not supported for driver %s", driver)
+		}
+		statements = append(statements, "TRUNCATE TABLE "+table)
+	case PostgreSQL:
+		querySQL := "TRUNCATE TABLE " + table
+		if restartIdentity {
+			querySQL += " RESTART IDENTITY"
+		}
+		if cascade {
+			querySQL += " CASCADE"
+		}
+		statements = append(statements, querySQL)
+	case SQLite3:
+		if cascade {
+			return fmt.Errorf("eorm: truncate cascade is not supported for driver %s", driver)
+		}
+		statements = append(statements, "DELETE FROM "+table)
+	case Oracle:
+		if restartIdentity {
+			return fmt.Errorf("eorm: truncate restart identity is not supported for driver %s", driver)
+		}
+		querySQL := "TRUNCATE TABLE " + table
+		if cascade {
+			querySQL += " CASCADE"
+		}
+		statements = append(statements, querySQL)
+	default:
+		return fmt.Errorf("eorm: truncate is not supported for driver %s", driver)
+	}
+
+	for _, querySQL := range statements {
+		if err := mgr.execMaintenance(executor, querySQL); err != nil {
+			return err
+		}
+	}
+
+	// SQLite 的自增序列保存在 sqlite_sequence 中（仅当表使用 AUTOINCREMENT 时存在）
+	if driver == SQLite3 && restartIdentity {
+		querySQL := "DELETE FROM sqlite_sequence WHERE name = ?"
+		start := time.Now()
+		_, seqErr := executor.Exec(querySQL, table)
+		mgr.logTrace(start, querySQL, []interface{}{table}, seqErr)
+		if seqErr != nil && !strings.Contains(strings.ToLower(seqErr.Error()), "no such table") {
+			return seqErr
+		}
+	}
+	return nil
+}
+
+// analyzeTable 更新表的统计信息，帮助优化器生成更好的执行计划
+func (mgr *dbManager) analyzeTable(executor sqlExecutor, table string) error {
+	if err := validateIdentifier(table); err != nil {
+		return err
+	}
+	var querySQL string
+	switch mgr.config.Load().Driver {
+	case MySQL:
+		querySQL = "ANALYZE TABLE " + table
+	case PostgreSQL, SQLite3:
+		querySQL = "ANALYZE " + table
+	case Oracle:
+		querySQL = fmt.Sprintf("BEGIN DBMS_STATS.GATHER_TABLE_STATS(USER, '%s'); END;", strings.ToUpper(table))
+	case SQLServer:
+		querySQL = "UPDATE STATISTICS " + table
+	default:
+		return fmt.Errorf("eorm: analyze table is not supported for driver %s", mgr.config.Load().Driver)
+	}
+	return mgr.execMaintenance(executor, querySQL)
+}
+
+// optimizeTable 整理表空间、回收已删除行占用的空间
+func (mgr *dbManager) optimizeTable(executor sqlExecutor, table string) error {
+	if err := validateIdentifier(table); err != nil {
+		return err
+	}
+	var querySQL string
+	switch mgr.config.Load().Driver {
+	case MySQL:
+		querySQL = "OPTIMIZE TABLE " + table
+	case PostgreSQL:
+		querySQL = "VACUUM " + table
+	case SQLite3:
+		// SQLite 的 VACUUM 只能作用于整个数据库
+		querySQL = "VACUUM"
+	case SQLServer:
+		querySQL = fmt.Sprintf("ALTER INDEX ALL ON %s REBUILD", table)
+	default:
+		return fmt.Errorf("eorm: optimize table is not supported for driver %s", mgr.config.Load().Driver)
+	}
+	return mgr.execMaintenance(executor, querySQL)
+}
+
+// execMaintenance 执行无参数的维护语句
+func (mgr *dbManager) execMaintenance(executor sqlExecutor, querySQL string) error {
+	start := time.Now()
+	_, err := executor.Exec(querySQL)
+	mgr.logTrace(start, querySQL, nil, err)
+	return err
+}
+
+// Truncate 清空表（比 DELETE 快且不产生逐行日志）
+// restartIdentity: 重置自增列（MySQL/SQL Server 总是重置；Oracle 不支持）
+// cascade: 同时清空通过外键引用该表的其他表（仅 PostgreSQL/Oracle 支持）
+// 示例: eorm.Truncate("temp_import", true, false)
+func Truncate(table string, restartIdentity, cascade bool) error {
+	db, err := defaultDB()
+	if err != nil {
+		return err
+	}
+	return db.Truncate(table, restartIdentity, cascade)
+}
+
+// Truncate 清空表
+func (db *DB) Truncate(table string, restartIdentity, cascade bool) error {
+	if db.lastErr != nil {
+		return db.lastErr
+	}
+	executor, err := db.getExecutor()
+	if err != nil {
+		return err
+	}
+	err = db.dbMgr.truncate(executor, table, restartIdentity, cascade)
+	if err == nil && db.cacheRepositoryName != "" {
+		db.ClearCache(db.cacheRepositoryName)
+	}
+	return err
+}
+
+// Truncate 在事务中清空表（MySQL/Oracle 的 TRUNCATE 会隐式提交事务）
+func (tx *Tx) Truncate(table string, restartIdentity, cascade bool) error {
+	err := tx.dbMgr.truncate(tx.tx, table, restartIdentity, cascade)
+	if err == nil && tx.cacheRepositoryName != "" {
+		tx.ClearCache(tx.cacheRepositoryName)
+	}
+	return err
+}
+
+// AnalyzeTable 更新表的统计信息
+// MySQL: ANALYZE TABLE；PostgreSQL/SQLite: ANALYZE；Oracle: DBMS_STATS.GATHER_TABLE_STATS；SQL Server: UPDATE STATISTICS
+func AnalyzeTable(table string) error {
+	db, err := defaultDB()
+	if err != nil {
+		return err
+	}
+	return db.AnalyzeTable(table)
+}
+
+// AnalyzeTable 更新表的统计信息
+func (db *DB) AnalyzeTable(table string) error {
+	if db.lastErr != nil {
+		return db.lastErr
+	}
+	executor, err := db.getExecutor()
+	if err != nil {
+		return err
+	}
+	return db.dbMgr.analyzeTable(executor, table)
+}
+
+// OptimizeTable 整理表空间、回收已删除行占用的空间
+// MySQL: OPTIMIZE TABLE；PostgreSQL: VACUUM（不能在事务中执行）；SQLite: VACUUM（整个数据库）；SQL Server: 重建索引；Oracle 不支持
+func OptimizeTable(table string) error {
+	db, err := defaultDB()
+	if err != nil {
+		return err
+	}
+	return db.OptimizeTable(table)
+}
+
+// OptimizeTable 整理表空间、回收已删除行占用的空间
+func (db *DB) OptimizeTable(table string) error {
+	if db.lastErr != nil {
+		return db.lastErr
+	}
+	executor, err := db.getExecutor()
+	if err != nil {
+		return err
+	}
+	return db.dbMgr.optimizeTable(executor, table)
+}
@@ -0,0 +1,110 @@
+package eorm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrMaxRowsExceeded 查询结果行数超过 MaxRows 限制
+var ErrMaxRowsExceeded = errors.New("eorm: result set exceeds max rows")
+
+// maxRowsLimit 结果集行数限制
+type maxRowsLimit struct {
+	n        int
+	truncate bool
+}
+
+// maxRowsKey 上下文中保存单次查询行数限制的键
+type maxRowsKey struct{}
+
+// SetMaxRows 为默认数据库设置 Record 查询（Query/Find/Paginate 等）结果集的最大行数，n <= 0 取消限制
+// 默认超过限制时中止查询并返回 ErrMaxRowsExceeded；truncate 为 true 时截断为前 n 行并记录 Warn 日志
+// 用于防止意外的无条件 SELECT 把整张大表读入内存；eorm 内部的读取（变更事件、计数缓存、恢复冲突检查等）不受限制
+// 示例: eorm.SetMaxRows(10000)
+func SetMaxRows(n int, truncate ...bool) {
+	db, err := defaultDB()
+	if err != nil {
+		return
+	}
+	db.SetMaxRows(n, truncate...)
+}
+
+// SetMaxRows 为当前数据库设置结果集的最大行数，见 SetMaxRows
+func (db *DB) SetMaxRows(n int, truncate ...bool) *DB {
+	if db.lastErr != nil {
+		return db
+	}
+	db.dbMgr.mu.Lock()
+	defer db.dbMgr.mu.Unlock()
+	if n <= 0 {
+		db.dbMgr.maxRows = nil
+		return db
+	}
+	db.dbMgr.maxRows = &maxRowsLimit{n: n, truncate: len(truncate) > 0 && truncate[0]}
+	return db
+}
+
+// WithMaxRows 返回携带单次查询行数限制的上下文，优先于数据库级别的 SetMaxRows，n <= 0 表示不限制
+// 示例: eorm.WithContext(eorm.WithMaxRows(ctx, 500, true)).Query("SELECT * FROM logs")
+func WithMaxRows(ctx context.Context, n int, truncate ...bool) context.Context {
+	return context.WithValue(contextOrBackground(ctx), maxRowsKey{}, maxRowsLimit{n: n, truncate: len(truncate) > 0 && truncate[0]})
+}
+
+// MaxRows 设置本次查询结果集的最大行数，优先于数据库级别的 SetMaxRows，n <= 0 表示不限制
+// 示例: eorm.Table("logs").Where("level = ?", "error").MaxRows(1000, true).Find()
+func (qb *QueryBuilder) MaxRows(n int, truncate ...bool) *QueryBuilder {
+	if qb.tx != nil {
+		tx := *qb.tx
+		tx.ctx = WithMaxRows(tx.ctx, n, truncate...)
+		qb.tx = &tx
+	} else if qb.db != nil {
+		db := *qb.db
+		db.ctx = WithMaxRows(db.ctx, n, truncate...)
+		qb.db = &db
+	}
+	return qb
+}
+
+// getMaxRows 返回查询适用的行数限制：上下文优先，其次为数据库配置，0 表示不限制
+func (mgr *dbManager) getMaxRows(ctx context.Context) maxRowsLimit {
+	if ctx != nil {
+		if limit, ok := ctx.Value(maxRowsKey{}).(maxRowsLimit); ok {
+			return limit
+		}
+	}
+	mgr.mu.RLock()
+	defer mgr.mu.RUnlock()
+	if mgr.maxRows != nil {
+		return *mgr.maxRows
+	}
+	return maxRowsLimit{}
+}
+
+// internalReadContext 不受 MaxRows 限制的上下文，WithMaxRows 的 0 表示不限制且优先于数据库配置
+var internalReadContext = WithMaxRows(context.Background(), 0)
+
+// queryInternal 执行 eorm 自身的内部读取（变更事件前像、计数缓存分组、恢复冲突检查、代码生成的元数据等）
+// MaxRows 只约束用户发起的查询，内部读取必须得到完整结果，否则写操作会失败或产生不完整的副作用
+func (mgr *dbManager) queryInternal(executor sqlExecutor, querySQL string, args ...interface{}) ([]*Record, error) {
+	return mgr.queryWithContext(internalReadContext, executor, querySQL, args...)
+}
+
+// scanRecordsLimited 按行数限制扫描结果集
+func (mgr *dbManager) scanRecordsLimited(ctx context.Context, rows *sql.Rows, querySQL string) ([]*Record, error) {
+	limit := mgr.getMaxRows(ctx)
+	results, exceeded, err := scanRecordsMax(rows, mgr.config.Load().Driver, limit.n)
+	if err != nil || !exceeded {
+		return results, err
+	}
+	if !limit.truncate {
+		return nil, fmt.Errorf("%w: limit %d, sql: %s", ErrMaxRowsExceeded, limit.n, strings.TrimSpace(querySQL))
+	}
+	LogWarn("查询结果超过最大行数，已截断", NewRecord().
+		Set("db", mgr.name).
+		Set("max_rows", limit.n).
+		Set("sql", querySQL))
+	return results, nil
+}
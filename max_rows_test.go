@@ -0,0 +1,34 @@
+package eorm
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMaxRowsOnlyLimitsUserQueries(t *testing.T) {
+	for _, truncate := range []bool{false, true} {
+		t.Run(map[bool]string{false: "Fail", true: "Truncate"}[truncate], func(t *testing.T) {
+			db := openTestDB(t, "CREATE TABLE mr_items (id INTEGER PRIMARY KEY, status TEXT)")
+			mustExec(t, db, "INSERT INTO mr_items (id, status) VALUES (1, 'a'), (2, 'a'), (3, 'a')")
+			events := subscribeChanges(t, db, "mr_items")
+			db.SetMaxRows(2, truncate)
+
+			rows, err := db.Query("SELECT * FROM mr_items")
+			if truncate {
+				if err != nil || len(rows) != 2 {
+					t.Fatalf("got %d rows, err %v", len(rows), err)
+				}
+			} else if !errors.Is(err, ErrMaxRowsExceeded) {
+				t.Fatalf("err = %v, want ErrMaxRowsExceeded", err)
+			}
+
+			n, err := db.Update("mr_items", NewRecord().Set("status", "b"), "status = ?", "a")
+			if err != nil || n != 3 {
+				t.Fatalf("updated %d rows, err %v", n, err)
+			}
+			if got := events.ops(); len(got) != 3 {
+				t.Fatalf("got %d change events, want 3", len(got))
+			}
+		})
+	}
+}
@@ -130,11 +130,11 @@ func PaginateModel_FullSql[T IDbModel](model T, cache *ModelCache, page, pageSiz
 
 // ForceDeleteModel performs a physical delete on a soft-delete enabled model
 func ForceDeleteModel(model IDbModel) (int64, error) {
-	record := ToRecord(model)
 	db, err := getDBForModel(model)
 	if err != nil {
 		return 0, err
 	}
+	record := db.ToRecord(model)
 
 	// Get primary keys
 
@@ -167,11 +167,11 @@ func ForceDeleteModel(model IDbModel) (int64, error) {
 
 // RestoreModel restores a soft-deleted model
 func RestoreModel(model IDbModel) (int64, error) {
-	record := ToRecord(model)
 	db, err := getDBForModel(model)
 	if err != nil {
 		return 0, err
 	}
+	record := db.ToRecord(model)
 	sdb, err := db.dbMgr.getDB()
 	if err != nil {
 		return 0, err
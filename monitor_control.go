@@ -0,0 +1,120 @@
+package eorm
+
+import (
+	"fmt"
+	"time"
+)
+
+// MonitorSnapshot 连接监控器在某一时刻的状态
+type MonitorSnapshot struct {
+	DBName              string        // 数据库名称
+	Paused              bool          // 是否已暂停检查
+	Healthy             bool          // 最近一次检查是否成功
+	LastCheck           time.Time     // 最近一次执行检查的时间，尚未检查时为零值
+	LastError           string        // 最近一次检查的错误，成功时为空
+	ConsecutiveFailures int           // 连续失败次数，检查成功后清零
+	NormalInterval      time.Duration // 正常检查间隔
+	ErrorInterval       time.Duration // 故障检查间隔
+}
+
+// lookupMonitor 返回指定数据库的连接监控器
+func lookupMonitor(dbName string) (*ConnectionMonitor, error) {
+	monitorsMu.RLock()
+	monitor, exists := monitors[dbName]
+	monitorsMu.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("eorm: no connection monitor for database '%s' (monitoring disabled or database not opened)", dbName)
+	}
+	return monitor, nil
+}
+
+// wake 通知主循环按最新设置重置定时器
+func (cm *ConnectionMonitor) wake() {
+	if cm.wakeCh == nil {
+		return
+	}
+	select {
+	case cm.wakeCh <- struct{}{}:
+	default:
+	}
+}
+
+// PauseMonitor 暂停指定数据库的连接检查，暂停期间不再 Ping 也不输出连接失败/恢复日志
+// 适用于计划内的维护窗口，避免数据库重启期间产生大量告警；监控协程保持运行，ResumeMonitor 后继续检查
+// 示例:
+//
+//	eorm.PauseMonitor("default")
+//	defer eorm.ResumeMonitor("default")
+//	// 执行数据库维护...
+func PauseMonitor(dbName string) error {
+	monitor, err := lookupMonitor(dbName)
+	if err != nil {
+		return err
+	}
+	monitor.mu.Lock()
+	monitor.paused = true
+	monitor.mu.Unlock()
+	return nil
+}
+
+// ResumeMonitor 恢复指定数据库的连接检查，下一次检查在一个完整间隔之后进行
+func ResumeMonitor(dbName string) error {
+	monitor, err := lookupMonitor(dbName)
+	if err != nil {
+		return err
+	}
+	monitor.mu.Lock()
+	monitor.paused = false
+	monitor.mu.Unlock()
+	monitor.wake()
+	return nil
+}
+
+// SetMonitorIntervals 在运行时调整连接检查间隔，normal 为连接正常时的间隔，errorInterval 为连接故障时的间隔
+// 新间隔立即生效，只影响当前监控器，不修改 Config（重新打开数据库后恢复为配置值）
+// 示例:
+//
+//	// 维护期间降低检查频率
+//	eorm.SetMonitorIntervals("default", 5*time.Minute, time.Minute)
+func SetMonitorIntervals(dbName string, normal, errorInterval time.Duration) error {
+	if normal <= 0 || errorInterval <= 0 {
+		return fmt.Errorf("eorm: monitor intervals must be positive, got normal=%v error=%v", normal, errorInterval)
+	}
+	monitor, err := lookupMonitor(dbName)
+	if err != nil {
+		return err
+	}
+	monitor.mu.Lock()
+	monitor.normalInterval = normal
+	monitor.errorInterval = errorInterval
+	monitor.mu.Unlock()
+	monitor.wake()
+	return nil
+}
+
+// MonitorStatus 返回指定数据库连接监控器的状态快照
+// 示例:
+//
+//	status, err := eorm.MonitorStatus("default")
+//	if err == nil && status.ConsecutiveFailures > 3 { ... }
+func MonitorStatus(dbName string) (*MonitorSnapshot, error) {
+	monitor, err := lookupMonitor(dbName)
+	if err != nil {
+		return nil, err
+	}
+	monitor.mu.RLock()
+	defer monitor.mu.RUnlock()
+	snapshot := &MonitorSnapshot{
+		DBName:              monitor.dbName,
+		Paused:              monitor.paused,
+		Healthy:             monitor.lastHealthy,
+		LastCheck:           monitor.lastCheck,
+		ConsecutiveFailures: monitor.failures,
+		NormalInterval:      monitor.normalInterval,
+		ErrorInterval:       monitor.errorInterval,
+	}
+	if monitor.lastErr != nil {
+		snapshot.LastError = monitor.lastErr.Error()
+	}
+	return snapshot, nil
+}
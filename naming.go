@@ -0,0 +1,216 @@
+package eorm
+
+import (
+	"strings"
+	"unicode"
+)
+
+// NamingStrategy 数据库级的命名约定，通过 Config.NamingStrategy 设置
+// 实现应为可比较的值类型（结构体反射信息按策略的值缓存）
+type NamingStrategy interface {
+	// TableName 将 Table() 收到的逻辑表名转换为实际表名（不含 TablePrefix）
+	TableName(name string) string
+	// ColumnName 将未写 column/db/json tag 的结构体字段名转换为列名
+	ColumnName(field string) string
+	// ModelName 将表名（已去掉 TablePrefix）转换为代码生成器使用的结构体名
+	ModelName(table string) string
+}
+
+// DefaultNamingStrategy 默认命名约定：表名原样使用，列名为小写字段名，结构体名为表名的驼峰形式
+type DefaultNamingStrategy struct{}
+
+func (DefaultNamingStrategy) TableName(name string) string   { return name }
+func (DefaultNamingStrategy) ColumnName(field string) string { return strings.ToLower(field) }
+func (DefaultNamingStrategy) ModelName(table string) string  { return SnakeToCamel(table) }
+
+// SnakeNamingStrategy 蛇形命名约定：UserName -> user_name，PluralTables 为 true 时表名使用复数（User -> users）
+// 示例:
+//
+//	config.TablePrefix = "app_"
+//	config.NamingStrategy = eorm.SnakeNamingStrategy{PluralTables: true}
+//	eorm.Table("OrderItem") // 查询 app_order_items
+type SnakeNamingStrategy struct {
+	PluralTables bool
+}
+
+func (s SnakeNamingStrategy) TableName(name string) string {
+	name = CamelToSnake(name)
+	if s.PluralTables {
+		name = pluralize(name)
+	}
+	return name
+}
+
+func (SnakeNamingStrategy) ColumnName(field string) string { return CamelToSnake(field) }
+
+func (s SnakeNamingStrategy) ModelName(table string) string {
+	if s.PluralTables {
+		table = singularize(table)
+	}
+	return SnakeToCamel(table)
+}
+
+// CamelToSnake 将驼峰命名转换为蛇形命名，连续大写视为一个缩写（UserID -> user_id，HTTPServer -> http_server）
+func CamelToSnake(s string) string {
+	runes := []rune(s)
+	var sb strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && runes[i-1] != '_' {
+				prevLower := unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])
+				nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+				if prevLower || (unicode.IsUpper(runes[i-1]) && nextLower) {
+					sb.WriteByte('_')
+				}
+			}
+			sb.WriteRune(unicode.ToLower(r))
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// pluralize 英文名词复数的简单规则，已以 s 结尾的名称视为复数保持不变
+func pluralize(name string) string {
+	lower := strings.ToLower(name)
+	switch {
+	case name == "" || strings.HasSuffix(lower, "s"):
+		return name
+	case strings.HasSuffix(lower, "x") || strings.HasSuffix(lower, "z") ||
+		strings.HasSuffix(lower, "ch") || strings.HasSuffix(lower, "sh"):
+		return name + "es"
+	case strings.HasSuffix(lower, "y") && len(lower) > 1 && !strings.ContainsRune("aeiou", rune(lower[len(lower)-2])):
+		return name[:len(name)-1] + "ies"
+	default:
+		return name + "s"
+	}
+}
+
+// singularize pluralize 的逆操作
+func singularize(name string) string {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, "ies") && len(lower) > 3:
+		return name[:len(name)-3] + "y"
+	case strings.HasSuffix(lower, "xes") || strings.HasSuffix(lower, "zes") ||
+		strings.HasSuffix(lower, "ches") || strings.HasSuffix(lower, "shes"):
+		return name[:len(name)-2]
+	case strings.HasSuffix(lower, "ss") || strings.HasSuffix(lower, "us"):
+		return name
+	case strings.HasSuffix(lower, "s") && len(lower) > 1:
+		return name[:len(name)-1]
+	default:
+		return name
+	}
+}
+
+// namingStrategy 返回数据库配置的命名策略，未配置时返回 nil（保持默认映射）
+func (mgr *dbManager) namingStrategy() NamingStrategy {
+	if mgr == nil || mgr.config.Load() == nil {
+		return nil
+	}
+	return mgr.config.Load().NamingStrategy
+}
+
+// defaultNamingStrategy 返回默认数据库的命名策略，供包级 ToRecord/ToStruct 使用
+func defaultNamingStrategy() NamingStrategy {
+	mgr, err := safeGetCurrentDB()
+	if err != nil {
+		return nil
+	}
+	return mgr.namingStrategy()
+}
+
+// resolveTableName 按 NamingStrategy 和 TablePrefix 将 Table() 收到的名称转换为实际表名
+// 已带前缀的名称、子查询和 schema 限定部分保持不变，别名保留（"User u" -> "app_users u"）
+func (mgr *dbManager) resolveTableName(name string) string {
+	if mgr == nil || mgr.config.Load() == nil {
+		return name
+	}
+	prefix, naming := mgr.config.Load().TablePrefix, mgr.config.Load().NamingStrategy
+	if prefix == "" && naming == nil {
+		return name
+	}
+	trimmed := strings.TrimSpace(name)
+	if trimmed == "" || strings.ContainsAny(trimmed, "(\"`[") {
+		return name
+	}
+
+	table, alias := trimmed, ""
+	if idx := strings.IndexAny(trimmed, " \t\n"); idx != -1 {
+		table, alias = trimmed[:idx], trimmed[idx:]
+	}
+	schema := ""
+	if dot := strings.LastIndex(table, "."); dot != -1 {
+		schema, table = table[:dot+1], table[dot+1:]
+	}
+
+	if prefix == "" || !strings.HasPrefix(strings.ToLower(table), strings.ToLower(prefix)) {
+		if naming != nil {
+			table = naming.TableName(table)
+		}
+		table = prefix + table
+	}
+	return schema + table + alias
+}
+
+// modelNameForTable 代码生成器根据表名生成结构体名：去掉 TablePrefix 后交给 NamingStrategy
+func (mgr *dbManager) modelNameForTable(tablename string) string {
+	table := strings.ReplaceAll(tablename, ".", "_")
+	if mgr != nil && mgr.config.Load() != nil {
+		base := tablename
+		if dot := strings.LastIndex(base, "."); dot != -1 {
+			base = base[dot+1:]
+		}
+		if prefix := mgr.config.Load().TablePrefix; prefix != "" && len(base) > len(prefix) &&
+			strings.HasPrefix(strings.ToLower(base), strings.ToLower(prefix)) {
+			table = base[len(prefix):]
+		}
+		if naming := mgr.config.Load().NamingStrategy; naming != nil {
+			return naming.ModelName(table)
+		}
+	}
+	return SnakeToCamel(table)
+}
+
+// ToRecord 按当前数据库的命名策略将结构体转换为 Record
+func (db *DB) ToRecord(src interface{}) *Record {
+	return toRecordWith(src, db.dbMgr.namingStrategy())
+}
+
+// ToStruct 按当前数据库的命名策略将 Record 转换为结构体
+func (db *DB) ToStruct(r *Record, dest interface{}) error {
+	return toStructWith(r, dest, db.dbMgr.namingStrategy())
+}
+
+// ToStructs 按当前数据库的命名策略将多条 Record 转换为结构体切片
+func (db *DB) ToStructs(records []*Record, dest interface{}) error {
+	return toStructsWith(records, dest, db.dbMgr.namingStrategy())
+}
+
+// ToRecord 按事务所属数据库的命名策略将结构体转换为 Record
+func (tx *Tx) ToRecord(src interface{}) *Record {
+	return toRecordWith(src, tx.dbMgr.namingStrategy())
+}
+
+// ToStruct 按事务所属数据库的命名策略将 Record 转换为结构体
+func (tx *Tx) ToStruct(r *Record, dest interface{}) error {
+	return toStructWith(r, dest, tx.dbMgr.namingStrategy())
+}
+
+// ToStructs 按事务所属数据库的命名策略将多条 Record 转换为结构体切片
+func (tx *Tx) ToStructs(records []*Record, dest interface{}) error {
+	return toStructsWith(records, dest, tx.dbMgr.namingStrategy())
+}
+
+// namingStrategy 返回构建器所属数据库的命名策略
+func (qb *QueryBuilder) namingStrategy() NamingStrategy {
+	if qb.tx != nil {
+		return qb.tx.dbMgr.namingStrategy()
+	}
+	if qb.db != nil {
+		return qb.db.dbMgr.namingStrategy()
+	}
+	return nil
+}
@@ -140,6 +140,11 @@ func (db *DB) HasOptimisticLock(table string) bool {
 	return db.dbMgr.hasOptimisticLock(table)
 }
 
+// HasOptimisticLock checks if a table has optimistic lock configured
+func (tx *Tx) HasOptimisticLock(table string) bool {
+	return tx.dbMgr.hasOptimisticLock(table)
+}
+
 // --- dbManager Methods ---
 
 // setOptimisticLockConfig sets optimistic lock config for a table
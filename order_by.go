@@ -0,0 +1,160 @@
+package eorm
+
+import (
+	"fmt"
+	"strings"
+)
+
+// orderTerm ORDER BY 中的一项
+type orderTerm struct {
+	expr   string // 列名或表达式
+	dir    string // ASC / DESC，为空表示未指定
+	nulls  string // FIRST / LAST，为空表示使用数据库默认顺序
+	raw    bool   // 由 OrderBy 传入的原始片段（可能包含多列和方向）
+	random bool   // 随机排序，expr 按数据库类型生成
+}
+
+// render 按数据库类型生成排序项
+// PostgreSQL、Oracle、SQLite 使用 NULLS FIRST/LAST，MySQL 和 SQL Server 不支持该语法，改为先按 CASE WHEN expr IS NULL 排序
+func (t orderTerm) render(driver DriverType) string {
+	if t.random {
+		return randomOrderExpr(driver)
+	}
+	term := t.expr
+	if t.dir != "" {
+		term += " " + t.dir
+	}
+	if t.nulls == "" {
+		return term
+	}
+	switch driver {
+	case MySQL, SQLServer:
+		nullRank := "1 ELSE 0"
+		if t.nulls == "FIRST" {
+			nullRank = "0 ELSE 1"
+		}
+		return fmt.Sprintf("CASE WHEN %s IS NULL THEN %s END, %s", t.expr, nullRank, term)
+	default:
+		return term + " NULLS " + t.nulls
+	}
+}
+
+// appendOrderTerm 追加排序项并重新生成 ORDER BY 子句
+func (qb *QueryBuilder) appendOrderTerm(term orderTerm) *QueryBuilder {
+	qb.orderTerms = append(qb.orderTerms, term)
+	qb.renderOrderBy()
+	return qb
+}
+
+// renderOrderBy 由排序项生成 qb.orderBy
+func (qb *QueryBuilder) renderOrderBy() {
+	driver := qb.getDriverType()
+	parts := make([]string, len(qb.orderTerms))
+	for i, t := range qb.orderTerms {
+		parts[i] = t.render(driver)
+	}
+	qb.orderBy = strings.Join(parts, ", ")
+}
+
+// normalizeOrderDirection 校验排序方向，为空时返回 ASC
+func normalizeOrderDirection(direction string) (string, error) {
+	switch dir := strings.ToUpper(strings.TrimSpace(direction)); dir {
+	case "":
+		return "ASC", nil
+	case "ASC", "DESC":
+		return dir, nil
+	default:
+		return "", fmt.Errorf("eorm: invalid order direction '%s', expected ASC or DESC", direction)
+	}
+}
+
+// OrderByCol 追加一个按列排序项，列名必须是合法标识符（可带表名前缀），direction 为 ASC 或 DESC（为空时 ASC）
+// 多次调用按调用顺序组成多列排序；也可以一次传入多对 列名, 方向
+// 示例:
+//
+//	eorm.Table("users").OrderByCol("status", "ASC").OrderByCol("created_at", "DESC").Find()
+//	eorm.Table("users").OrderByCol("status", "ASC", "created_at", "DESC").Find()
+//	// ORDER BY status ASC, created_at DESC
+func (qb *QueryBuilder) OrderByCol(column, direction string, more ...string) *QueryBuilder {
+	if qb.lastErr != nil {
+		return qb
+	}
+	if len(more)%2 != 0 {
+		qb.lastErr = fmt.Errorf("eorm: OrderByCol expects column, direction pairs")
+		return qb
+	}
+	pairs := append([]string{column, direction}, more...)
+	for i := 0; i < len(pairs); i += 2 {
+		if err := validateIdentifier(pairs[i]); err != nil {
+			qb.lastErr = err
+			return qb
+		}
+		dir, err := normalizeOrderDirection(pairs[i+1])
+		if err != nil {
+			qb.lastErr = err
+			return qb
+		}
+		qb.orderTerms = append(qb.orderTerms, orderTerm{expr: pairs[i], dir: dir})
+	}
+	qb.renderOrderBy()
+	return qb
+}
+
+// OrderByExpr 追加一个按表达式排序的项，direction 可省略（ASC/DESC）
+// 表达式原样写入 SQL，只做分号和注释检查，不要拼接用户输入
+// 示例:
+//
+//	eorm.Table("orders").OrderByExpr("FIELD(status, 'PENDING', 'COMPLETED')").OrderByCol("id", "DESC").Find()
+//	eorm.Table("orders").OrderByExpr("COALESCE(updated_at, created_at)", "DESC").Find()
+func (qb *QueryBuilder) OrderByExpr(expr string, direction ...string) *QueryBuilder {
+	if qb.lastErr != nil {
+		return qb
+	}
+	if strings.TrimSpace(expr) == "" {
+		qb.lastErr = fmt.Errorf("eorm: OrderByExpr requires an expression")
+		return qb
+	}
+	if err := validateSafeSQL(expr); err != nil {
+		qb.lastErr = err
+		return qb
+	}
+	term := orderTerm{expr: expr}
+	if len(direction) > 0 {
+		dir, err := normalizeOrderDirection(direction[0])
+		if err != nil {
+			qb.lastErr = err
+			return qb
+		}
+		term.dir = dir
+	}
+	return qb.appendOrderTerm(term)
+}
+
+// NullsLast 让上一个排序项的 NULL 值排在最后，须紧跟 OrderByCol 或 OrderByExpr 调用
+// PostgreSQL、Oracle、SQLite 生成 NULLS LAST，MySQL 和 SQL Server 生成 CASE WHEN col IS NULL THEN 1 ELSE 0 END, col
+// 示例:
+//
+//	eorm.Table("tasks").OrderByCol("due_date", "ASC").NullsLast().Find()
+func (qb *QueryBuilder) NullsLast() *QueryBuilder {
+	return qb.setNullsOrder("NullsLast", "LAST")
+}
+
+// NullsFirst 让上一个排序项的 NULL 值排在最前，须紧跟 OrderByCol 或 OrderByExpr 调用
+func (qb *QueryBuilder) NullsFirst() *QueryBuilder {
+	return qb.setNullsOrder("NullsFirst", "FIRST")
+}
+
+// setNullsOrder 设置最后一个排序项的 NULL 顺序
+func (qb *QueryBuilder) setNullsOrder(method, nulls string) *QueryBuilder {
+	if qb.lastErr != nil {
+		return qb
+	}
+	n := len(qb.orderTerms)
+	if n == 0 || qb.orderTerms[n-1].raw || qb.orderTerms[n-1].random {
+		qb.lastErr = fmt.Errorf("eorm: %s must follow OrderByCol or OrderByExpr", method)
+		return qb
+	}
+	qb.orderTerms[n-1].nulls = nulls
+	qb.renderOrderBy()
+	return qb
+}
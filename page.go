@@ -37,6 +37,69 @@ func (p *Page[T]) IsLastPage() bool {
 	return p.PageNumber >= p.TotalPage
 }
 
+// HasNext returns true if there is a page after the current page.
+func (p *Page[T]) HasNext() bool {
+	return p.PageNumber < p.TotalPage
+}
+
+// HasPrev returns true if there is a non-empty page before the current page.
+func (p *Page[T]) HasPrev() bool {
+	return p.PageNumber > 1 && p.TotalPage > 0
+}
+
+// FirstRow returns the 1-based position of the first row of this page in the whole result, or 0 if the page is empty.
+func (p *Page[T]) FirstRow() int64 {
+	if len(p.List) == 0 || p.PageNumber < 1 {
+		return 0
+	}
+	return int64(p.PageNumber-1)*int64(p.PageSize) + 1
+}
+
+// LastRow returns the 1-based position of the last row of this page in the whole result, or 0 if the page is empty.
+func (p *Page[T]) LastRow() int64 {
+	if first := p.FirstRow(); first > 0 {
+		return first + int64(len(p.List)) - 1
+	}
+	return 0
+}
+
+// PageMeta is the pagination metadata of a Page without its rows, for embedding in API responses.
+type PageMeta struct {
+	PageNumber int   `json:"pageNumber"`
+	PageSize   int   `json:"pageSize"`
+	TotalPage  int   `json:"totalPage"`
+	TotalRow   int64 `json:"totalRow"`
+	HasNext    bool  `json:"hasNext"`
+	HasPrev    bool  `json:"hasPrev"`
+	FirstRow   int64 `json:"firstRow"`
+	LastRow    int64 `json:"lastRow"`
+}
+
+// Meta returns the pagination metadata of the page.
+func (p *Page[T]) Meta() PageMeta {
+	return PageMeta{
+		PageNumber: p.PageNumber,
+		PageSize:   p.PageSize,
+		TotalPage:  p.TotalPage,
+		TotalRow:   p.TotalRow,
+		HasNext:    p.HasNext(),
+		HasPrev:    p.HasPrev(),
+		FirstRow:   p.FirstRow(),
+		LastRow:    p.LastRow(),
+	}
+}
+
+// LinkBuilder returns a builder for the first/prev/next/last URLs of the page, based on baseURL.
+// Existing query parameters of baseURL are kept; the page and page size parameters are replaced.
+func (p *Page[T]) LinkBuilder(baseURL string) *PageLinkBuilder {
+	return &PageLinkBuilder{
+		meta:      p.Meta(),
+		baseURL:   baseURL,
+		pageParam: "page",
+		sizeParam: "pageSize",
+	}
+}
+
 // ToJson returns a JSON string representation of the Page instance.
 func (p *Page[T]) ToJson() string {
 	b, err := json.Marshal(p)
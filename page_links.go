@@ -0,0 +1,126 @@
+package eorm
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// PageLinkBuilder builds pagination URLs and an RFC 5988 Link header for a page.
+// Example:
+//
+//	page, _ := eorm.Table("users").Paginate(pageNumber, 20)
+//	links := page.LinkBuilder("https://api.example.com/users?status=active")
+//	w.Header().Set("Link", links.Header())
+//	// <https://api.example.com/users?page=1&pageSize=20&status=active>; rel="first", <...page=3...>; rel="next", ...
+type PageLinkBuilder struct {
+	meta      PageMeta
+	baseURL   string
+	pageParam string
+	sizeParam string
+	omitSize  bool
+}
+
+// PageParam sets the query parameter name for the page number (default "page").
+func (b *PageLinkBuilder) PageParam(name string) *PageLinkBuilder {
+	if name != "" {
+		b.pageParam = name
+	}
+	return b
+}
+
+// SizeParam sets the query parameter name for the page size (default "pageSize"); an empty name leaves the page size out of the URLs.
+func (b *PageLinkBuilder) SizeParam(name string) *PageLinkBuilder {
+	b.sizeParam = name
+	b.omitSize = name == ""
+	return b
+}
+
+// URL returns the URL of the given page number.
+func (b *PageLinkBuilder) URL(pageNumber int) string {
+	u, err := url.Parse(b.baseURL)
+	if err != nil {
+		return ""
+	}
+	query := u.Query()
+	query.Set(b.pageParam, strconv.Itoa(pageNumber))
+	if !b.omitSize {
+		query.Set(b.sizeParam, strconv.Itoa(b.meta.PageSize))
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// First returns the URL of the first page.
+func (b *PageLinkBuilder) First() string {
+	return b.URL(1)
+}
+
+// Last returns the URL of the last page, or "" if the result is empty.
+func (b *PageLinkBuilder) Last() string {
+	if b.meta.TotalPage < 1 {
+		return ""
+	}
+	return b.URL(b.meta.TotalPage)
+}
+
+// Next returns the URL of the next page, or "" if this is the last page.
+func (b *PageLinkBuilder) Next() string {
+	if !b.meta.HasNext {
+		return ""
+	}
+	return b.URL(b.meta.PageNumber + 1)
+}
+
+// Prev returns the URL of the previous page, or "" if this is the first page.
+// When the current page is past the end of the result, the previous link points to the last page.
+func (b *PageLinkBuilder) Prev() string {
+	if !b.meta.HasPrev {
+		return ""
+	}
+	prev := b.meta.PageNumber - 1
+	if prev > b.meta.TotalPage {
+		prev = b.meta.TotalPage
+	}
+	return b.URL(prev)
+}
+
+// Links returns the non-empty links keyed by relation: first, prev, next, last.
+func (b *PageLinkBuilder) Links() map[string]string {
+	links := make(map[string]string, 4)
+	for _, rel := range b.rels() {
+		links[rel.name] = rel.url
+	}
+	return links
+}
+
+// Header returns the value for an RFC 5988 Link header in first, prev, next, last order.
+func (b *PageLinkBuilder) Header() string {
+	var parts []string
+	for _, rel := range b.rels() {
+		parts = append(parts, "<"+rel.url+`>; rel="`+rel.name+`"`)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// pageLink is one relation of the Link header
+type pageLink struct {
+	name string
+	url  string
+}
+
+// rels returns the non-empty links in first, prev, next, last order
+func (b *PageLinkBuilder) rels() []pageLink {
+	var links []pageLink
+	for _, link := range []pageLink{
+		{"first", b.First()},
+		{"prev", b.Prev()},
+		{"next", b.Next()},
+		{"last", b.Last()},
+	} {
+		if link.url != "" {
+			links = append(links, link)
+		}
+	}
+	return links
+}
@@ -117,7 +117,7 @@ func (mgr *ConcurrentPaginationManager) SafePaginate(
 	defer mgr.PutAdapterFactory(factory)
 
 	// 获取对应的分页适配器
-	adapter := factory.CreateAdapter(string(db.dbMgr.config.Driver))
+	adapter := factory.CreateAdapter(string(db.dbMgr.config.Load().Driver))
 
 	// 执行分页查询
 	return mgr.executePaginationQuery(db, adapter, parsedSQL, page, pageSize, args...)
@@ -162,7 +162,7 @@ func (mgr *ConcurrentPaginationManager) SafePaginateTx(
 	defer mgr.PutAdapterFactory(factory)
 
 	// 获取对应的分页适配器
-	adapter := factory.CreateAdapter(string(tx.dbMgr.config.Driver))
+	adapter := factory.CreateAdapter(string(tx.dbMgr.config.Load().Driver))
 
 	// 执行事务分页查询
 	return mgr.executePaginationQueryTx(tx, adapter, parsedSQL, page, pageSize, args...)
@@ -186,8 +186,8 @@ func (mgr *ConcurrentPaginationManager) executePaginationQuery(
 	var totalRow int64
 	if db.cacheRepositoryName != "" {
 		// 使用线程安全的缓存键生成
-		countKey := GenerateCountCacheKey(db.dbMgr.name, parsedSQL, args...)
-		if val, ok := GetCache().CacheGet(db.cacheRepositoryName, countKey); ok {
+		countKey := generateCountCacheKey(db.ctx, db.dbMgr.name, parsedSQL, args...)
+		if val, ok := cacheGet(GetCache(), db.cacheRepositoryName, countKey); ok {
 			if convertCacheValue(val, &totalRow) {
 				// 缓存命中，继续执行分页查询
 			} else {
@@ -215,7 +215,7 @@ func (mgr *ConcurrentPaginationManager) executePaginationQuery(
 						break
 					}
 				}
-				GetCache().CacheSet(db.cacheRepositoryName, countKey, totalRow, getEffectiveTTL(db.cacheRepositoryName, db.cacheTTL))
+				cacheSet(GetCache(), db.cacheRepositoryName, countKey, totalRow, getEffectiveTTL(db.cacheRepositoryName, db.cacheTTL))
 			}
 		} else {
 			// 缓存未命中，执行查询
@@ -242,7 +242,7 @@ func (mgr *ConcurrentPaginationManager) executePaginationQuery(
 					break
 				}
 			}
-			GetCache().CacheSet(db.cacheRepositoryName, countKey, totalRow, getEffectiveTTL(db.cacheRepositoryName, db.cacheTTL))
+			cacheSet(GetCache(), db.cacheRepositoryName, countKey, totalRow, getEffectiveTTL(db.cacheRepositoryName, db.cacheTTL))
 		}
 	} else {
 		// 不使用缓存
@@ -283,8 +283,8 @@ func (mgr *ConcurrentPaginationManager) executePaginationQuery(
 	var list []*Record
 	if db.cacheRepositoryName != "" {
 		// 使用线程安全的缓存键生成
-		paginationKey := GeneratePaginationCacheKey(db.dbMgr.name, parsedSQL, page, pageSize, args...)
-		if val, ok := GetCache().CacheGet(db.cacheRepositoryName, paginationKey); ok {
+		paginationKey := generatePaginationCacheKey(db.ctx, db.dbMgr.name, parsedSQL, page, pageSize, args...)
+		if val, ok := cacheGet(GetCache(), db.cacheRepositoryName, paginationKey); ok {
 			if convertCacheValue(val, &list) {
 				// 缓存命中，直接返回结果
 				return NewPage(list, page, pageSize, totalRow), nil
@@ -302,7 +302,7 @@ func (mgr *ConcurrentPaginationManager) executePaginationQuery(
 		}
 
 		// 将结果存入缓存
-		GetCache().CacheSet(db.cacheRepositoryName, paginationKey, list, getEffectiveTTL(db.cacheRepositoryName, db.cacheTTL))
+		cacheSet(GetCache(), db.cacheRepositoryName, paginationKey, list, getEffectiveTTL(db.cacheRepositoryName, db.cacheTTL))
 		return NewPage(list, page, pageSize, totalRow), nil
 	} else {
 		// 不使用缓存
@@ -336,8 +336,8 @@ func (mgr *ConcurrentPaginationManager) executePaginationQueryTx(
 	var totalRow int64
 	if tx.cacheRepositoryName != "" {
 		// 使用线程安全的缓存键生成
-		countKey := GenerateCountCacheKey(tx.dbMgr.name, parsedSQL, args...)
-		if val, ok := GetCache().CacheGet(tx.cacheRepositoryName, countKey); ok {
+		countKey := generateCountCacheKey(tx.ctx, tx.dbMgr.name, parsedSQL, args...)
+		if val, ok := cacheGet(GetCache(), tx.cacheRepositoryName, countKey); ok {
 			if convertCacheValue(val, &totalRow) {
 				// 缓存命中，继续执行分页查询
 			} else {
@@ -365,7 +365,7 @@ func (mgr *ConcurrentPaginationManager) executePaginationQueryTx(
 						break
 					}
 				}
-				GetCache().CacheSet(tx.cacheRepositoryName, countKey, totalRow, getEffectiveTTL(tx.cacheRepositoryName, tx.cacheTTL))
+				cacheSet(GetCache(), tx.cacheRepositoryName, countKey, totalRow, getEffectiveTTL(tx.cacheRepositoryName, tx.cacheTTL))
 			}
 		} else {
 			// 缓存未命中，执行查询
@@ -392,7 +392,7 @@ func (mgr *ConcurrentPaginationManager) executePaginationQueryTx(
 					break
 				}
 			}
-			GetCache().CacheSet(tx.cacheRepositoryName, countKey, totalRow, getEffectiveTTL(tx.cacheRepositoryName, tx.cacheTTL))
+			cacheSet(GetCache(), tx.cacheRepositoryName, countKey, totalRow, getEffectiveTTL(tx.cacheRepositoryName, tx.cacheTTL))
 		}
 	} else {
 		// 不使用缓存
@@ -433,8 +433,8 @@ func (mgr *ConcurrentPaginationManager) executePaginationQueryTx(
 	var list []*Record
 	if tx.cacheRepositoryName != "" {
 		// 使用线程安全的缓存键生成
-		paginationKey := GeneratePaginationCacheKey(tx.dbMgr.name, parsedSQL, page, pageSize, args...)
-		if val, ok := GetCache().CacheGet(tx.cacheRepositoryName, paginationKey); ok {
+		paginationKey := generatePaginationCacheKey(tx.ctx, tx.dbMgr.name, parsedSQL, page, pageSize, args...)
+		if val, ok := cacheGet(GetCache(), tx.cacheRepositoryName, paginationKey); ok {
 			if convertCacheValue(val, &list) {
 				// 缓存命中，直接返回结果
 				return NewPage(list, page, pageSize, totalRow), nil
@@ -448,7 +448,7 @@ func (mgr *ConcurrentPaginationManager) executePaginationQueryTx(
 		}
 
 		// 将结果存入缓存
-		GetCache().CacheSet(tx.cacheRepositoryName, paginationKey, list, getEffectiveTTL(tx.cacheRepositoryName, tx.cacheTTL))
+		cacheSet(GetCache(), tx.cacheRepositoryName, paginationKey, list, getEffectiveTTL(tx.cacheRepositoryName, tx.cacheTTL))
 		return NewPage(list, page, pageSize, totalRow), nil
 	} else {
 		// 不使用缓存
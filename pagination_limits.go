@@ -0,0 +1,183 @@
+package eorm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrPaginationLimit 分页参数超过 PaginationLimits 限制，可用 errors.Is 判断后转换为 HTTP 400
+var ErrPaginationLimit = errors.New("eorm: pagination parameter exceeds limit")
+
+// PaginationLimitError 分页参数超限的详细信息，可用 errors.As 取出具体的参数名和上限
+// 示例:
+//
+//	var limitErr *eorm.PaginationLimitError
+//	if errors.As(err, &limitErr) {
+//	    c.JSON(400, gin.H{"error": limitErr.Error(), "param": limitErr.Param, "max": limitErr.Max})
+//	}
+type PaginationLimitError struct {
+	Param string // 超限的参数：page_size、page、limit 或 offset
+	Value int    // 客户端传入的值
+	Max   int    // 允许的最大值
+}
+
+func (e *PaginationLimitError) Error() string {
+	return fmt.Sprintf("eorm: %s %d exceeds maximum %d", e.Param, e.Value, e.Max)
+}
+
+func (e *PaginationLimitError) Unwrap() error {
+	return ErrPaginationLimit
+}
+
+// PaginationLimits 分页参数的上限，用于在查询到达数据库之前拦截客户端传入的异常参数
+type PaginationLimits struct {
+	MaxPageSize int  // 每页最大行数（Paginate 的 pageSize、QueryBuilder 的 Limit），<= 0 时使用 MaxPageSize 常量
+	MaxOffset   int  // 最大偏移量（(page-1)*pageSize、QueryBuilder 的 Offset），<= 0 表示不限制
+	Clamp       bool // true 时把超限参数收紧到上限（深分页返回最后允许的一页），false 时返回 *PaginationLimitError
+}
+
+// paginationLimitsKey 上下文中保存单次查询分页限制的键
+type paginationLimitsKey struct{}
+
+// SetPaginationLimits 为默认数据库设置分页参数上限，limits 为 nil 时恢复默认行为（pageSize 收紧到 MaxPageSize）
+// 示例: eorm.SetPaginationLimits(&eorm.PaginationLimits{MaxPageSize: 200, MaxOffset: 100000})
+func SetPaginationLimits(limits *PaginationLimits) {
+	db, err := defaultDB()
+	if err != nil {
+		return
+	}
+	db.SetPaginationLimits(limits)
+}
+
+// SetPaginationLimits 为当前数据库设置分页参数上限，见 SetPaginationLimits
+func (db *DB) SetPaginationLimits(limits *PaginationLimits) *DB {
+	if db.lastErr != nil {
+		return db
+	}
+	db.dbMgr.mu.Lock()
+	defer db.dbMgr.mu.Unlock()
+	if limits == nil {
+		db.dbMgr.pageLimits = nil
+		return db
+	}
+	copied := *limits
+	db.dbMgr.pageLimits = &copied
+	return db
+}
+
+// WithPaginationLimits 返回携带单次查询分页限制的上下文，优先于数据库级别的 SetPaginationLimits
+// 示例: eorm.WithContext(eorm.WithPaginationLimits(ctx, &eorm.PaginationLimits{MaxPageSize: 50})).Paginate(page, size, sql)
+func WithPaginationLimits(ctx context.Context, limits *PaginationLimits) context.Context {
+	var value PaginationLimits
+	if limits != nil {
+		value = *limits
+	}
+	return context.WithValue(contextOrBackground(ctx), paginationLimitsKey{}, value)
+}
+
+// PaginationLimits 设置本次查询的分页参数上限，优先于数据库级别的 SetPaginationLimits
+// 示例: eorm.Table("orders").PaginationLimits(&eorm.PaginationLimits{MaxPageSize: 100, Clamp: true}).Paginate(page, size)
+func (qb *QueryBuilder) PaginationLimits(limits *PaginationLimits) *QueryBuilder {
+	if qb.tx != nil {
+		tx := *qb.tx
+		tx.ctx = WithPaginationLimits(tx.ctx, limits)
+		qb.tx = &tx
+	} else if qb.db != nil {
+		db := *qb.db
+		db.ctx = WithPaginationLimits(db.ctx, limits)
+		qb.db = &db
+	}
+	return qb
+}
+
+// getPaginationLimits 返回查询适用的分页限制：上下文优先，其次为数据库配置
+// 均未配置时 configured 为 false，返回默认限制（pageSize 收紧到 MaxPageSize）
+func (mgr *dbManager) getPaginationLimits(ctx context.Context) (limits PaginationLimits, configured bool) {
+	if ctx != nil {
+		limits, configured = ctx.Value(paginationLimitsKey{}).(PaginationLimits)
+	}
+	if !configured {
+		mgr.mu.RLock()
+		if mgr.pageLimits != nil {
+			limits, configured = *mgr.pageLimits, true
+		} else {
+			limits = PaginationLimits{Clamp: true}
+		}
+		mgr.mu.RUnlock()
+	}
+	if limits.MaxPageSize <= 0 || limits.MaxPageSize > MaxPageSize {
+		limits.MaxPageSize = MaxPageSize
+	}
+	return limits, configured
+}
+
+// checkPagination 规范化并校验页码和每页行数，返回实际使用的 page 和 pageSize
+func (mgr *dbManager) checkPagination(ctx context.Context, page, pageSize int) (int, int, error) {
+	if page < 1 {
+		page = DefaultPage
+	}
+	if pageSize < 1 {
+		pageSize = DefaultPageSize
+	}
+	limits, _ := mgr.getPaginationLimits(ctx)
+	if pageSize > limits.MaxPageSize {
+		if !limits.Clamp {
+			return 0, 0, &PaginationLimitError{Param: "page_size", Value: pageSize, Max: limits.MaxPageSize}
+		}
+		pageSize = limits.MaxPageSize
+	}
+	// 按页数比较，避免 (page-1)*pageSize 溢出
+	if limits.MaxOffset > 0 && page-1 > limits.MaxOffset/pageSize {
+		maxPage := limits.MaxOffset/pageSize + 1
+		if !limits.Clamp {
+			return 0, 0, &PaginationLimitError{Param: "page", Value: page, Max: maxPage}
+		}
+		page = maxPage
+	}
+	return page, pageSize, nil
+}
+
+// checkLimitOffset 校验 QueryBuilder 的 Limit 和 Offset，返回实际使用的值；未配置限制时原样返回
+func (mgr *dbManager) checkLimitOffset(ctx context.Context, limit, offset int) (int, int, error) {
+	limits, configured := mgr.getPaginationLimits(ctx)
+	if !configured {
+		return limit, offset, nil
+	}
+	if limit > limits.MaxPageSize {
+		if !limits.Clamp {
+			return 0, 0, &PaginationLimitError{Param: "limit", Value: limit, Max: limits.MaxPageSize}
+		}
+		limit = limits.MaxPageSize
+	}
+	if limits.MaxOffset > 0 && offset > limits.MaxOffset {
+		if !limits.Clamp {
+			return 0, 0, &PaginationLimitError{Param: "offset", Value: offset, Max: limits.MaxOffset}
+		}
+		offset = limits.MaxOffset
+	}
+	return limit, offset, nil
+}
+
+// applyPaginationLimits 按分页限制收紧或拒绝构建器的 Limit 和 Offset
+func (qb *QueryBuilder) applyPaginationLimits() error {
+	if qb.limit <= 0 && qb.offset <= 0 {
+		return nil
+	}
+	var mgr *dbManager
+	var ctx context.Context
+	if qb.tx != nil {
+		mgr, ctx = qb.tx.dbMgr, qb.tx.ctx
+	} else if qb.db != nil {
+		mgr, ctx = qb.db.dbMgr, qb.db.ctx
+	}
+	if mgr == nil {
+		return nil
+	}
+	limit, offset, err := mgr.checkLimitOffset(ctx, qb.limit, qb.offset)
+	if err != nil {
+		return err
+	}
+	qb.limit, qb.offset = limit, offset
+	return nil
+}
@@ -0,0 +1,164 @@
+package eorm
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// paginationRowNumColumn 分页改写时附加的行号列，扫描结果后移除
+const paginationRowNumColumn = "eorm_rn"
+
+// serverVersionRe 提取版本号中的主版本
+var serverVersionRe = regexp.MustCompile(`^\s*(\d+)`)
+
+// splitTopLevelOrderBy 将 SQL 拆分为主体和最外层 ORDER BY 子句（不含 ORDER BY 关键字），
+// 子查询、窗口函数 OVER(...)、字符串和注释中的 ORDER BY 不会被拆分
+func splitTopLevelOrderBy(querySQL string) (body, orderBy string) {
+	idx := -1
+	for start := 0; ; {
+		pos := findTopLevelKeywordIndex(querySQL, "ORDER BY", start)
+		if pos == -1 {
+			break
+		}
+		idx = pos
+		start = pos + len("ORDER BY")
+	}
+	if idx == -1 {
+		return strings.TrimSpace(querySQL), ""
+	}
+	return strings.TrimSpace(querySQL[:idx]), strings.TrimSpace(querySQL[idx+len("ORDER BY"):])
+}
+
+// unqualifyOrderBy 去掉排序表达式中的表别名前缀（u.name -> name），用于在派生表外层排序
+func unqualifyOrderBy(orderBy string) string {
+	parts := strings.Split(orderBy, ",")
+	for i, part := range parts {
+		fields := strings.Fields(part)
+		if len(fields) == 0 {
+			continue
+		}
+		if dot := strings.LastIndex(fields[0], "."); dot != -1 && !strings.ContainsAny(fields[0], "()") {
+			fields[0] = fields[0][dot+1:]
+		}
+		parts[i] = strings.Join(fields, " ")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// canInjectRowNumber 判断能否把 ROW_NUMBER() 直接加入最外层 SELECT 列表（保持排序表达式的原有作用域）
+func canInjectRowNumber(body string) bool {
+	if findTopLevelKeywordIndex(body, "SELECT", 0) != 0 {
+		return false
+	}
+	for _, kw := range []string{"UNION", "INTERSECT", "EXCEPT", "DISTINCT", "TOP"} {
+		if findTopLevelKeywordIndex(body, kw, 0) != -1 {
+			return false
+		}
+	}
+	return true
+}
+
+// buildPaginatedSQL 为不支持 LIMIT/OFFSET 的数据库改写分页 SQL
+//   - SQL Server 2012+：最外层 ORDER BY 后追加 OFFSET ... FETCH NEXT（无排序时使用 ORDER BY (SELECT NULL)）
+//   - SQL Server 2012 之前：ROW_NUMBER() OVER (ORDER BY ...) 包装
+//   - Oracle 12c+：OFFSET ... ROWS FETCH NEXT ... ROWS ONLY
+//   - Oracle 11g 及之前：ROWNUM 双层包装
+//
+// 返回的 SQL 是否附加了行号列 eorm_rn
+func (mgr *dbManager) buildPaginatedSQL(querySQL string, offset, pageSize int) (string, bool) {
+	driver := mgr.config.Load().Driver
+	if driver != SQLServer && driver != Oracle {
+		return fmt.Sprintf("%s LIMIT %d OFFSET %d", querySQL, pageSize, offset), false
+	}
+	body, orderBy := splitTopLevelOrderBy(querySQL)
+	modern := mgr.serverMajorVersion() >= modernPaginationVersion(driver)
+
+	if driver == Oracle {
+		if modern {
+			return fmt.Sprintf("%s OFFSET %d ROWS FETCH NEXT %d ROWS ONLY", strings.TrimSpace(querySQL), offset, pageSize), false
+		}
+		ordered := strings.TrimSpace(querySQL)
+		if orderBy == "" {
+			// ROWNUM 分页需要稳定的顺序
+			ordered += " ORDER BY 1"
+		}
+		return fmt.Sprintf("SELECT * FROM (SELECT eorm_inner.*, ROWNUM %s FROM (%s) eorm_inner WHERE ROWNUM <= %d) WHERE %s > %d",
+			paginationRowNumColumn, ordered, offset+pageSize, paginationRowNumColumn, offset), true
+	}
+
+	if modern {
+		if orderBy == "" {
+			orderBy = "(SELECT NULL)"
+		}
+		return fmt.Sprintf("%s ORDER BY %s OFFSET %d ROWS FETCH NEXT %d ROWS ONLY", body, orderBy, offset, pageSize), false
+	}
+
+	var inner string
+	if canInjectRowNumber(body) {
+		if orderBy == "" {
+			orderBy = "(SELECT NULL)"
+		}
+		inner = fmt.Sprintf("SELECT ROW_NUMBER() OVER (ORDER BY %s) AS %s, %s", orderBy, paginationRowNumColumn, strings.TrimSpace(body[len("SELECT"):]))
+	} else {
+		if orderBy == "" {
+			orderBy = "(SELECT NULL)"
+		} else {
+			orderBy = unqualifyOrderBy(orderBy)
+		}
+		inner = fmt.Sprintf("SELECT eorm_inner.*, ROW_NUMBER() OVER (ORDER BY %s) AS %s FROM (%s) AS eorm_inner", orderBy, paginationRowNumColumn, body)
+	}
+	return fmt.Sprintf("SELECT * FROM (%s) AS eorm_page WHERE %s > %d AND %s <= %d ORDER BY %s",
+		inner, paginationRowNumColumn, offset, paginationRowNumColumn, offset+pageSize, paginationRowNumColumn), true
+}
+
+// modernPaginationVersion 支持 OFFSET ... FETCH 的最低主版本（SQL Server 2012 为 11，Oracle 12c 为 12）
+func modernPaginationVersion(driver DriverType) int {
+	if driver == Oracle {
+		return 12
+	}
+	return 11
+}
+
+// serverMajorVersion 查询并缓存数据库主版本号（SQL Server、Oracle、MySQL/MariaDB），查询失败时返回 0（按旧版本语法处理，兼容所有版本）
+func (mgr *dbManager) serverMajorVersion() int {
+	mgr.versionOnce.Do(func() {
+		var query string
+		switch mgr.config.Load().Driver {
+		case SQLServer:
+			query = "SELECT CAST(SERVERPROPERTY('ProductVersion') AS VARCHAR(32))"
+		case Oracle:
+			query = "SELECT version FROM product_component_version WHERE product LIKE 'Oracle%' AND ROWNUM = 1"
+		case MySQL:
+			query = "SELECT VERSION()"
+		default:
+			return
+		}
+		db, err := mgr.getDB()
+		if err != nil {
+			return
+		}
+		var version string
+		start := time.Now()
+		err = db.QueryRow(query).Scan(&version)
+		mgr.logTrace(start, query, nil, err)
+		if err != nil {
+			return
+		}
+		if m := serverVersionRe.FindStringSubmatch(version); len(m) == 2 {
+			mgr.serverVersion, _ = strconv.Atoi(m[1])
+		}
+	})
+	return mgr.serverVersion
+}
+
+// removePaginationRowNum 从分页结果中移除附加的行号列
+func removePaginationRowNum(records []*Record) {
+	for _, r := range records {
+		if r != nil {
+			r.Remove(paginationRowNumColumn)
+		}
+	}
+}
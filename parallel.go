@@ -0,0 +1,189 @@
+package eorm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// QueryRange 半开区间 [From, To)，From 或 To 为 nil 表示该侧不限
+type QueryRange struct {
+	From interface{}
+	To   interface{}
+}
+
+// SplitRange 将整数区间 [min, max] 均分为 n 个半开区间，最后一个区间包含 max
+// 示例: eorm.SplitRange(1, 1000000, 8)
+func SplitRange(min, max int64, n int) []QueryRange {
+	if n <= 0 || max < min {
+		return nil
+	}
+	total := max - min + 1
+	if int64(n) > total {
+		n = int(total)
+	}
+	step := total / int64(n)
+	ranges := make([]QueryRange, n)
+	from := min
+	for i := 0; i < n; i++ {
+		to := from + step
+		if i == n-1 {
+			to = max + 1
+		}
+		ranges[i] = QueryRange{From: from, To: to}
+		from = to
+	}
+	return ranges
+}
+
+// Parallel 使用默认数据库的并发度并发执行多个查询函数，等待全部完成后返回所有错误（errors.Join）
+// 并发数受连接池容量限制（MaxOpen 的一半，至少为 1；未限制连接数时为 CPU 数），
+// ctx 取消后不再启动尚未开始的函数
+// 示例:
+//
+//	var users, orders []*eorm.Record
+//	err := eorm.Parallel(ctx,
+//	    func() (err error) { users, err = eorm.Table("users").Find(); return },
+//	    func() (err error) { orders, err = eorm.Table("orders").Find(); return },
+//	)
+func Parallel(ctx context.Context, queries ...func() error) error {
+	var mgr *dbManager
+	if db, err := defaultDB(); err == nil {
+		mgr = db.dbMgr
+	}
+	return runParallel(ctx, parallelWorkers(mgr), queries)
+}
+
+// Parallel 使用当前数据库的并发度并发执行多个查询函数，见 Parallel
+func (db *DB) Parallel(ctx context.Context, queries ...func() error) error {
+	if db.lastErr != nil {
+		return db.lastErr
+	}
+	return runParallel(ctx, parallelWorkers(db.dbMgr), queries)
+}
+
+// FindParallelByRanges 将大范围扫描按 column 拆分为多个区间并发查询，按区间顺序合并结果
+// 每个区间追加 column >= From AND column < To 条件，其余条件、排序、LIMIT 作用于每个区间内；
+// 并发数受连接池容量限制，任一区间失败时返回错误；事务中的构建器不支持并发查询
+// 示例:
+//
+//	rows, err := eorm.Table("orders").Where("status = ?", "paid").OrderBy("id").
+//	    FindParallelByRanges("id", eorm.SplitRange(1, 10000000, 8))
+func (qb *QueryBuilder) FindParallelByRanges(column string, ranges []QueryRange) ([]*Record, error) {
+	if qb.lastErr != nil {
+		return nil, qb.lastErr
+	}
+	if err := validateIdentifier(column); err != nil {
+		return nil, err
+	}
+	if qb.tx != nil {
+		return nil, fmt.Errorf("eorm: FindParallelByRanges is not supported inside a transaction")
+	}
+	if err := qb.validateQueryBuilderState(); err != nil {
+		return nil, err
+	}
+	if len(ranges) == 0 {
+		return qb.Find()
+	}
+
+	results := make([][]*Record, len(ranges))
+	queries := make([]func() error, len(ranges))
+	for i, r := range ranges {
+		queries[i] = func() error {
+			c := qb.Clone()
+			if r.From != nil {
+				c.Where(column+" >= ?", r.From)
+			}
+			if r.To != nil {
+				c.Where(column+" < ?", r.To)
+			}
+			records, err := c.Find()
+			if err != nil {
+				return fmt.Errorf("eorm: range [%v, %v) failed: %v", r.From, r.To, err)
+			}
+			results[i] = records
+			return nil
+		}
+	}
+
+	if err := runParallel(qb.db.ctx, parallelWorkers(qb.db.dbMgr), queries); err != nil {
+		return nil, err
+	}
+
+	total := 0
+	for _, records := range results {
+		total += len(records)
+	}
+	merged := make([]*Record, 0, total)
+	for _, records := range results {
+		merged = append(merged, records...)
+	}
+	return merged, nil
+}
+
+// parallelWorkers 根据连接池容量计算并发数
+func parallelWorkers(mgr *dbManager) int {
+	if mgr != nil && mgr.config.Load() != nil && mgr.config.Load().MaxOpen > 0 {
+		if n := mgr.config.Load().MaxOpen / 2; n > 0 {
+			return n
+		}
+		return 1
+	}
+	return runtime.NumCPU()
+}
+
+// runParallel 以最多 workers 个 goroutine 执行函数，返回所有错误
+func runParallel(ctx context.Context, workers int, fns []func() error) error {
+	ctx = contextOrBackground(ctx)
+	if workers <= 0 {
+		workers = 1
+	}
+	if workers > len(fns) {
+		workers = len(fns)
+	}
+
+	errs := make([]error, len(fns))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				errs[i] = runParallelFunc(fns[i])
+			}
+		}()
+	}
+
+dispatch:
+	for i, fn := range fns {
+		if fn == nil {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			for j := i; j < len(fns); j++ {
+				if fns[j] != nil {
+					errs[j] = ctx.Err()
+				}
+			}
+			break dispatch
+		case next <- i:
+		}
+	}
+	close(next)
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// runParallelFunc 执行单个函数并将 panic 转换为错误
+func runParallelFunc(fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("eorm: parallel query panic: %v", r)
+		}
+	}()
+	return fn()
+}
@@ -0,0 +1,410 @@
+package eorm
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// pgLiteral PostgreSQL 数组、hstore、复合类型的文本字面量，作为字符串参数绑定，由服务端按列类型转换
+type pgLiteral string
+
+// Value 实现 driver.Valuer
+func (l pgLiteral) Value() (driver.Value, error) {
+	return string(l), nil
+}
+
+// PgArray 将 Go 切片编码为 PostgreSQL 数组参数（如 []string{"a","b"} → '{"a","b"}'），支持多维切片
+// Record 中的切片值写入 PostgreSQL 时会自动编码，原始 SQL 中的切片参数默认展开为 IN 列表，需要数组时使用 PgArray
+// 示例:
+//
+//	eorm.Exec("UPDATE posts SET tags = ? WHERE id = ?", eorm.PgArray([]string{"go", "orm"}), 1)
+func PgArray(v interface{}) driver.Valuer {
+	if v == nil {
+		return pgLiteral("{}")
+	}
+	return pgLiteral(encodePgArray(reflect.ValueOf(v)))
+}
+
+// PgHstore 将 map 编码为 PostgreSQL hstore 参数，值为 nil 时写入 NULL
+func PgHstore(m map[string]interface{}) driver.Valuer {
+	return pgLiteral(encodePgHstore(reflect.ValueOf(m)))
+}
+
+// PgRow 将字段值编码为 PostgreSQL 复合类型（ROW）参数，nil 字段写入 NULL
+// 示例: eorm.Exec("UPDATE users SET address = ? WHERE id = ?", eorm.PgRow("Main St", "Springfield", 12345), 1)
+func PgRow(fields ...interface{}) driver.Valuer {
+	var sb strings.Builder
+	sb.WriteByte('(')
+	for i, f := range fields {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		if f == nil {
+			continue
+		}
+		sb.WriteString(quotePgElement(pgElementText(f), "(),\"\\ "))
+	}
+	sb.WriteByte(')')
+	return pgLiteral(sb.String())
+}
+
+// encodePgValue 将 Record 写入时的切片和 map 编码为 PostgreSQL 字面量，[]byte 和 driver.Valuer 保持原样
+func encodePgValue(arg interface{}) (interface{}, bool) {
+	switch arg.(type) {
+	case []byte, driver.Valuer:
+		return nil, false
+	}
+	rv := reflect.ValueOf(arg)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		return pgLiteral(encodePgArray(rv)), true
+	case reflect.Map:
+		if rv.Type().Key().Kind() == reflect.String {
+			return pgLiteral(encodePgHstore(rv)), true
+		}
+	}
+	return nil, false
+}
+
+// encodePgArray 编码数组字面量
+func encodePgArray(rv reflect.Value) string {
+	for rv.Kind() == reflect.Interface || rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return "NULL"
+		}
+		rv = rv.Elem()
+	}
+	var sb strings.Builder
+	sb.WriteByte('{')
+	for i := 0; i < rv.Len(); i++ {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		elem := rv.Index(i)
+		for elem.Kind() == reflect.Interface || elem.Kind() == reflect.Ptr {
+			if elem.IsNil() {
+				break
+			}
+			elem = elem.Elem()
+		}
+		switch {
+		case (elem.Kind() == reflect.Interface || elem.Kind() == reflect.Ptr) && elem.IsNil():
+			sb.WriteString("NULL")
+		case (elem.Kind() == reflect.Slice || elem.Kind() == reflect.Array) && elem.Type().Elem().Kind() != reflect.Uint8:
+			sb.WriteString(encodePgArray(elem))
+		default:
+			sb.WriteString(quotePgElement(pgElementText(elem.Interface()), "{},\"\\ "))
+		}
+	}
+	sb.WriteByte('}')
+	return sb.String()
+}
+
+// encodePgHstore 编码 hstore 字面量，键按字典序输出
+func encodePgHstore(rv reflect.Value) string {
+	if !rv.IsValid() || rv.Len() == 0 {
+		return ""
+	}
+	pairs := make([]string, 0, rv.Len())
+	for _, key := range rv.MapKeys() {
+		k := `"` + escapePgQuoted(key.String()) + `"`
+		val := rv.MapIndex(key)
+		for val.Kind() == reflect.Interface || val.Kind() == reflect.Ptr {
+			if val.IsNil() {
+				break
+			}
+			val = val.Elem()
+		}
+		if (val.Kind() == reflect.Interface || val.Kind() == reflect.Ptr) && val.IsNil() {
+			pairs = append(pairs, k+"=>NULL")
+			continue
+		}
+		pairs = append(pairs, k+`=>"`+escapePgQuoted(pgElementText(val.Interface()))+`"`)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ", ")
+}
+
+// pgElementText 返回元素的文本表示
+func pgElementText(v interface{}) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case []byte:
+		return `\x` + fmt.Sprintf("%x", val)
+	case bool:
+		if val {
+			return "t"
+		}
+		return "f"
+	case time.Time:
+		return val.Format("2006-01-02 15:04:05.999999999Z07:00")
+	case float32:
+		return strconv.FormatFloat(float64(val), 'g', -1, 32)
+	case float64:
+		return strconv.FormatFloat(val, 'g', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// quotePgElement 元素为空、为 NULL 或包含特殊字符时加双引号
+func quotePgElement(s, special string) string {
+	if s == "" || strings.EqualFold(s, "NULL") || strings.ContainsAny(s, special) {
+		return `"` + escapePgQuoted(s) + `"`
+	}
+	return s
+}
+
+// escapePgQuoted 转义双引号和反斜杠
+func escapePgQuoted(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s)
+}
+
+// parsePgArray 解析 PostgreSQL 数组文本（如 {1,2,NULL} 或 {{a,b},{c,d}}），元素为 string、nil 或嵌套的 []interface{}
+func parsePgArray(s string) ([]interface{}, bool) {
+	s = strings.TrimSpace(s)
+	// 去掉维度前缀，如 [1:2]={a,b}
+	if strings.HasPrefix(s, "[") {
+		if eq := strings.Index(s, "="); eq != -1 {
+			s = s[eq+1:]
+		}
+	}
+	if len(s) < 2 || s[0] != '{' || s[len(s)-1] != '}' {
+		return nil, false
+	}
+	result, rest, ok := parsePgArrayLevel(s)
+	if !ok || strings.TrimSpace(rest) != "" {
+		return nil, false
+	}
+	return result, true
+}
+
+// parsePgArrayLevel 解析一层 {...}，返回剩余文本
+func parsePgArrayLevel(s string) ([]interface{}, string, bool) {
+	s = s[1:]
+	result := make([]interface{}, 0)
+	for {
+		if s == "" {
+			return nil, "", false
+		}
+		if s[0] == '}' {
+			return result, s[1:], true
+		}
+		var elem interface{}
+		switch s[0] {
+		case '{':
+			nested, rest, ok := parsePgArrayLevel(s)
+			if !ok {
+				return nil, "", false
+			}
+			elem, s = nested, rest
+		case '"':
+			text, rest, ok := readPgQuoted(s)
+			if !ok {
+				return nil, "", false
+			}
+			elem, s = text, rest
+		default:
+			end := strings.IndexAny(s, ",}")
+			if end == -1 {
+				return nil, "", false
+			}
+			text := strings.TrimSpace(s[:end])
+			if strings.EqualFold(text, "NULL") {
+				elem = nil
+			} else {
+				elem = text
+			}
+			s = s[end:]
+		}
+		result = append(result, elem)
+		if s != "" && s[0] == ',' {
+			s = s[1:]
+		}
+	}
+}
+
+// readPgQuoted 读取以双引号开头的元素，返回去转义后的文本和剩余部分
+func readPgQuoted(s string) (string, string, bool) {
+	var sb strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if i+1 < len(s) {
+				i++
+				sb.WriteByte(s[i])
+			}
+		case '"':
+			return sb.String(), s[i+1:], true
+		default:
+			sb.WriteByte(s[i])
+		}
+	}
+	return "", "", false
+}
+
+// parsePgHstore 解析 hstore 文本（"a"=>"1", "b"=>NULL）
+func parsePgHstore(s string) (map[string]interface{}, bool) {
+	result := make(map[string]interface{})
+	s = strings.TrimSpace(s)
+	for s != "" {
+		if s[0] != '"' {
+			return nil, false
+		}
+		key, rest, ok := readPgQuoted(s)
+		if !ok {
+			return nil, false
+		}
+		rest = strings.TrimSpace(rest)
+		if !strings.HasPrefix(rest, "=>") {
+			return nil, false
+		}
+		rest = strings.TrimSpace(rest[2:])
+		if strings.HasPrefix(rest, `"`) {
+			var val string
+			if val, rest, ok = readPgQuoted(rest); !ok {
+				return nil, false
+			}
+			result[key] = val
+		} else if len(rest) >= 4 && strings.EqualFold(rest[:4], "NULL") {
+			result[key] = nil
+			rest = rest[4:]
+		} else {
+			return nil, false
+		}
+		rest = strings.TrimSpace(rest)
+		if strings.HasPrefix(rest, ",") {
+			rest = strings.TrimSpace(rest[1:])
+		} else if rest != "" {
+			return nil, false
+		}
+		s = rest
+	}
+	return result, true
+}
+
+// parsePgRow 解析复合类型文本（如 (a,"b c",)），空字段为 nil
+func parsePgRow(s string) ([]interface{}, bool) {
+	s = strings.TrimSpace(s)
+	if len(s) < 2 || s[0] != '(' || s[len(s)-1] != ')' {
+		return nil, false
+	}
+	s = s[1 : len(s)-1]
+	var fields []interface{}
+	for {
+		if s == "" || s[0] == ',' {
+			fields = append(fields, nil)
+		} else if s[0] == '"' {
+			var sb strings.Builder
+			i := 1
+			for ; i < len(s); i++ {
+				if s[i] == '\\' && i+1 < len(s) {
+					i++
+					sb.WriteByte(s[i])
+				} else if s[i] == '"' {
+					// 复合类型中 "" 表示一个双引号
+					if i+1 < len(s) && s[i+1] == '"' {
+						sb.WriteByte('"')
+						i++
+						continue
+					}
+					break
+				} else {
+					sb.WriteByte(s[i])
+				}
+			}
+			if i >= len(s) {
+				return nil, false
+			}
+			fields = append(fields, sb.String())
+			s = s[i+1:]
+		} else {
+			end := strings.IndexByte(s, ',')
+			if end == -1 {
+				end = len(s)
+			}
+			fields = append(fields, s[:end])
+			s = s[end:]
+		}
+		if s == "" {
+			return fields, true
+		}
+		if s[0] != ',' {
+			return nil, false
+		}
+		s = s[1:]
+		if s == "" {
+			return append(fields, nil), true
+		}
+	}
+}
+
+// pgTextValue 返回数据库返回的文本值（string 或 []byte）
+func pgTextValue(v interface{}) (string, bool) {
+	switch val := v.(type) {
+	case string:
+		return val, true
+	case []byte:
+		return string(val), true
+	}
+	return "", false
+}
+
+// GetInt64Slice 获取 int64 切片，支持 Go 切片、PostgreSQL 数组文本（{1,2,3}）和逗号分隔的字符串
+func (r *Record) GetInt64Slice(column string) ([]int64, error) {
+	slice, err := r.GetSlice(column)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]int64, len(slice))
+	for i, v := range slice {
+		if result[i], err = Convert.ToInt64WithError(v); err != nil {
+			return nil, fmt.Errorf("column '%s' element %d: %v", column, i, err)
+		}
+	}
+	return result, nil
+}
+
+// GetHstore 获取 PostgreSQL hstore 列，NULL 值为 nil；列值已是 map 时直接转换
+func (r *Record) GetHstore(column string) (map[string]interface{}, error) {
+	val := r.Get(column)
+	if val == nil {
+		return nil, fmt.Errorf("column '%s' not found", column)
+	}
+	switch m := val.(type) {
+	case map[string]interface{}:
+		return m, nil
+	case map[string]string:
+		result := make(map[string]interface{}, len(m))
+		for k, v := range m {
+			result[k] = v
+		}
+		return result, nil
+	}
+	if text, ok := pgTextValue(val); ok {
+		if m, ok := parsePgHstore(text); ok {
+			return m, nil
+		}
+	}
+	return nil, fmt.Errorf("column '%s' cannot be converted to hstore", column)
+}
+
+// GetPgRow 获取 PostgreSQL 复合类型列的字段（文本形式），空字段为 nil
+func (r *Record) GetPgRow(column string) ([]interface{}, error) {
+	val := r.Get(column)
+	if val == nil {
+		return nil, fmt.Errorf("column '%s' not found", column)
+	}
+	if text, ok := pgTextValue(val); ok {
+		if fields, ok := parsePgRow(text); ok {
+			return fields, nil
+		}
+	}
+	return nil, fmt.Errorf("column '%s' cannot be converted to composite fields", column)
+}
@@ -0,0 +1,172 @@
+package eorm
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// PrefixMapping JOIN 查询结果的列前缀映射：结构体字段路径 -> 列前缀
+// 字段路径为 Go 字段名，多级嵌套用 "." 分隔；空字符串表示根结构体
+// 示例: eorm.PrefixMapping{"": "u_", "Order": "o_", "Order.Product": "p_"}
+// 表示 u_* 列映射到 User 本身，o_* 列映射到 User.Order，p_* 列映射到 User.Order.Product
+// 指针类型的嵌套字段在对应列全部为 NULL 时（例如 LEFT JOIN 未匹配）保持为 nil
+type PrefixMapping map[string]string
+
+// MapPrefix 为 FindToDbModel/QueryToDbModel 添加列前缀映射，可多次调用
+// 示例:
+//
+//	var users []User
+//	err := eorm.Table("users u").
+//	    Select("u.id AS u_id, u.name AS u_name, o.id AS o_id, o.amount AS o_amount").
+//	    Join("orders o", "o.user_id = u.id").
+//	    MapPrefix("", "u_").
+//	    MapPrefix("Order", "o_").
+//	    FindToDbModel(&users)
+func (qb *QueryBuilder) MapPrefix(fieldPath, prefix string) *QueryBuilder {
+	if qb.prefixMapping == nil {
+		qb.prefixMapping = make(PrefixMapping)
+	}
+	qb.prefixMapping[fieldPath] = prefix
+	return qb
+}
+
+// ToStructsWithPrefix 按列前缀映射将 JOIN 查询结果转换为嵌套结构体切片
+// dest 必须是结构体切片或结构体指针切片的指针
+func ToStructsWithPrefix(records []*Record, dest interface{}, mapping PrefixMapping) error {
+	if len(mapping) == 0 {
+		return ToStructs(records, dest)
+	}
+	if dest == nil {
+		return fmt.Errorf("eorm: dest cannot be nil")
+	}
+
+	val := reflect.ValueOf(dest)
+	if val.Kind() != reflect.Ptr || val.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("eorm: dest must be a pointer to a slice")
+	}
+
+	sliceVal := val.Elem()
+	sliceVal.Set(reflect.MakeSlice(sliceVal.Type(), 0, len(records)))
+	elemType := sliceVal.Type().Elem()
+	isPtr := elemType.Kind() == reflect.Ptr
+	baseType := elemType
+	if isPtr {
+		baseType = elemType.Elem()
+	}
+	if baseType.Kind() != reflect.Struct {
+		return fmt.Errorf("eorm: dest must be a slice of structs or struct pointers")
+	}
+
+	// 按路径层级排序，保证父字段先于子字段填充
+	paths := make([]string, 0, len(mapping))
+	for path := range mapping {
+		paths = append(paths, path)
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		di, dj := strings.Count(paths[i], "."), strings.Count(paths[j], ".")
+		if paths[i] == "" || paths[j] == "" {
+			return paths[i] == ""
+		}
+		if di != dj {
+			return di < dj
+		}
+		return paths[i] < paths[j]
+	})
+
+	for _, record := range records {
+		newElem := reflect.New(baseType)
+		if err := setStructFromPrefixedRecord(newElem.Elem(), record, paths, mapping); err != nil {
+			return err
+		}
+		if isPtr {
+			sliceVal.Set(reflect.Append(sliceVal, newElem))
+		} else {
+			sliceVal.Set(reflect.Append(sliceVal, newElem.Elem()))
+		}
+	}
+	return nil
+}
+
+// setStructFromPrefixedRecord 按前缀拆分记录并填充根结构体及其嵌套字段
+func setStructFromPrefixedRecord(root reflect.Value, r *Record, paths []string, mapping PrefixMapping) error {
+	if r == nil {
+		return fmt.Errorf("eorm: record is nil")
+	}
+	if _, ok := mapping[""]; !ok {
+		// 未指定根前缀时，根结构体使用未加前缀的列
+		if err := setStructFromRecord(root, r, defaultNamingStrategy()); err != nil {
+			return err
+		}
+	}
+
+	for _, path := range paths {
+		sub, allNull := extractPrefixedRecord(r, mapping[path])
+		target, err := resolvePrefixField(root, path, !allNull)
+		if err != nil {
+			return err
+		}
+		if !target.IsValid() {
+			// 指针字段且对应列全部为 NULL，保持 nil
+			continue
+		}
+		if err := setStructFromRecord(target, sub, defaultNamingStrategy()); err != nil {
+			if path == "" {
+				return err
+			}
+			return fmt.Errorf("%s.%v", path, err)
+		}
+	}
+	return nil
+}
+
+// extractPrefixedRecord 提取指定前缀的列（去掉前缀），并返回这些列是否全部为 NULL
+func extractPrefixedRecord(r *Record, prefix string) (*Record, bool) {
+	sub := NewRecord()
+	allNull := true
+	lowerPrefix := strings.ToLower(prefix)
+	for _, key := range r.Keys() {
+		if !strings.HasPrefix(strings.ToLower(key), lowerPrefix) || len(key) == len(prefix) {
+			continue
+		}
+		val := r.Get(key)
+		if val != nil {
+			allNull = false
+		}
+		sub.Set(key[len(prefix):], val)
+	}
+	return sub, allNull
+}
+
+// resolvePrefixField 按字段路径定位嵌套结构体，必要时分配指针
+// alloc 为 false 时遇到 nil 指针不分配，返回无效值
+func resolvePrefixField(root reflect.Value, path string, alloc bool) (reflect.Value, error) {
+	if path == "" {
+		return root, nil
+	}
+	current := root
+	for _, name := range strings.Split(path, ".") {
+		field := current.FieldByName(name)
+		if !field.IsValid() {
+			return reflect.Value{}, fmt.Errorf("eorm: field %s not found in %s", name, current.Type())
+		}
+		if !field.CanSet() {
+			return reflect.Value{}, fmt.Errorf("eorm: field %s in %s is not exported", name, current.Type())
+		}
+		if field.Kind() == reflect.Ptr {
+			if field.IsNil() {
+				if !alloc {
+					return reflect.Value{}, nil
+				}
+				field.Set(reflect.New(field.Type().Elem()))
+			}
+			field = field.Elem()
+		}
+		if field.Kind() != reflect.Struct {
+			return reflect.Value{}, fmt.Errorf("eorm: field %s in %s must be a struct or struct pointer", name, current.Type())
+		}
+		current = field
+	}
+	return current, nil
+}
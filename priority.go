@@ -0,0 +1,146 @@
+package eorm
+
+import (
+	"context"
+	"sync"
+)
+
+// Priority 连接池使用优先级
+type Priority int
+
+const (
+	PriorityHigh Priority = iota // 高优先级（默认），如在线 API 请求
+	PriorityLow                  // 低优先级，如批处理、报表等后台任务
+)
+
+// priorityKey 上下文中保存优先级的键
+type priorityKey struct{}
+
+// WithPriority 返回使用指定优先级的默认数据库
+// 连接池饱和（使用中的语句数达到 MaxOpen）时，低优先级操作排在所有等待中的高优先级操作之后，
+// 保证批处理任务运行期间在线请求的延迟稳定；未设置 MaxOpen 时不做准入控制
+// 示例: eorm.WithPriority(eorm.PriorityLow).Query("SELECT * FROM orders WHERE created_at < ?", t)
+func WithPriority(p Priority) *DB {
+	db, err := defaultDB()
+	if err != nil {
+		return &DB{lastErr: err}
+	}
+	return db.WithPriority(p)
+}
+
+// WithPriority 设置当前数据库句柄的优先级，见 WithPriority
+func (db *DB) WithPriority(p Priority) *DB {
+	db.ctx = ContextWithPriority(db.ctx, p)
+	return db
+}
+
+// ContextWithPriority 返回携带优先级的上下文，配合 WithContext 使用
+func ContextWithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(contextOrBackground(ctx), priorityKey{}, p)
+}
+
+// PriorityFromContext 返回上下文中的优先级，未设置时为 PriorityHigh
+func PriorityFromContext(ctx context.Context) Priority {
+	if ctx == nil {
+		return PriorityHigh
+	}
+	p, _ := ctx.Value(priorityKey{}).(Priority)
+	return p
+}
+
+// poolAdmission 基于信号量的连接池准入控制，等待者中高优先级先于低优先级获得许可
+type poolAdmission struct {
+	mu       sync.Mutex
+	capacity int
+	inUse    int
+	high     []chan struct{}
+	low      []chan struct{}
+}
+
+// getAdmission 返回数据库的准入控制器，未设置 MaxOpen 时返回 nil
+func (mgr *dbManager) getAdmission() *poolAdmission {
+	mgr.admissionOnce.Do(func() {
+		if mgr.config.Load() != nil && mgr.config.Load().MaxOpen > 0 {
+			mgr.admission = &poolAdmission{capacity: mgr.config.Load().MaxOpen}
+		}
+	})
+	return mgr.admission
+}
+
+// admit 为连接池上的语句获取执行许可，返回释放函数
+// 事务中的语句使用事务自身的连接，不参与准入控制
+func (mgr *dbManager) admit(ctx context.Context, executor sqlExecutor) (func(), error) {
+	if db := poolDB(executor); db == nil || db != mgr.db.Load() {
+		return func() {}, nil
+	}
+	a := mgr.getAdmission()
+	if a == nil {
+		return func() {}, nil
+	}
+	if err := a.acquire(contextOrBackground(ctx), PriorityFromContext(ctx)); err != nil {
+		return nil, err
+	}
+	return a.release, nil
+}
+
+// acquire 获取许可：有空闲且没有更高优先级的等待者时立即返回，否则排队等待
+func (a *poolAdmission) acquire(ctx context.Context, p Priority) error {
+	a.mu.Lock()
+	if a.inUse < a.capacity && len(a.high) == 0 && (p == PriorityHigh || len(a.low) == 0) {
+		a.inUse++
+		a.mu.Unlock()
+		return nil
+	}
+	ch := make(chan struct{})
+	if p == PriorityHigh {
+		a.high = append(a.high, ch)
+	} else {
+		a.low = append(a.low, ch)
+	}
+	a.mu.Unlock()
+
+	select {
+	case <-ch:
+		return nil
+	case <-ctx.Done():
+		a.mu.Lock()
+		if a.removeWaiter(ch) {
+			a.mu.Unlock()
+			return ctx.Err()
+		}
+		a.mu.Unlock()
+		// 取消的同时已获得许可，归还给下一个等待者
+		a.release()
+		return ctx.Err()
+	}
+}
+
+// release 归还许可，优先唤醒高优先级等待者
+func (a *poolAdmission) release() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.inUse--
+	var ch chan struct{}
+	if len(a.high) > 0 {
+		ch, a.high = a.high[0], a.high[1:]
+	} else if len(a.low) > 0 {
+		ch, a.low = a.low[0], a.low[1:]
+	} else {
+		return
+	}
+	a.inUse++
+	close(ch)
+}
+
+// removeWaiter 从等待队列中移除，返回是否仍在队列中（调用方持有 a.mu）
+func (a *poolAdmission) removeWaiter(ch chan struct{}) bool {
+	for _, q := range []*[]chan struct{}{&a.high, &a.low} {
+		for i, c := range *q {
+			if c == ch {
+				*q = append((*q)[:i], (*q)[i+1:]...)
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+package eorm
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAdmissionCoversRecordWrites(t *testing.T) {
+	db := openTestDB(t, "CREATE TABLE pa_items (id INTEGER PRIMARY KEY, name TEXT)")
+	// 预热表结构缓存，之后的写操作只剩写语句本身需要准入许可
+	if _, err := db.InsertRecord("pa_items", NewRecord().Set("id", 1).Set("name", "a")); err != nil {
+		t.Fatal(err)
+	}
+
+	admission := db.dbMgr.getAdmission()
+	if admission == nil {
+		t.Fatal("admission control is not enabled, want MaxOpen > 0")
+	}
+	for i := 0; i < admission.capacity; i++ {
+		if err := admission.acquire(context.Background(), PriorityHigh); err != nil {
+			t.Fatal(err)
+		}
+	}
+	released := false
+	releaseAll := func() {
+		if released {
+			return
+		}
+		released = true
+		for i := 0; i < admission.capacity; i++ {
+			admission.release()
+		}
+	}
+	t.Cleanup(releaseAll)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := Use(db.dbMgr.name).WithContext(ctx).Update("pa_items", NewRecord().Set("name", "x"), "id = ?", 1)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Update on a saturated pool: err = %v, want context.DeadlineExceeded", err)
+	}
+	if n := countRowsDirect(t, db, "pa_items", "name = 'x'"); n != 0 {
+		t.Fatalf("updated rows = %d, want 0: the update must wait for admission", n)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := Use(db.dbMgr.name).WithPriority(PriorityLow).Delete("pa_items", "id = ?", 1)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		t.Fatalf("Delete finished on a saturated pool: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+	releaseAll()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Delete still waiting after permits were released")
+	}
+	if n := countRowsDirect(t, db, "pa_items", ""); n != 0 {
+		t.Fatalf("rows = %d, want 0 after the delete", n)
+	}
+}
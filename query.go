@@ -27,6 +27,10 @@ func getDBForModel(model IDbModel) (*DB, error) {
 	return Use(dbName), nil
 }
 
+// Query 执行查询，返回记录列表
+// SQL 中统一使用 ? 占位符，会自动转换为各数据库的格式（PostgreSQL: $1，Oracle: :1，SQL Server: @p1）
+// 切片参数自动展开，如 Query("SELECT * FROM users WHERE id IN (?)", []int64{1, 2, 3})
+// 字面量问号写作 ??，如 PostgreSQL 的 jsonb 操作符: Query("SELECT * FROM t WHERE data ?? ?", "key")
 func Query(querySQL string, args ...interface{}) ([]*Record, error) {
 	db, err := defaultDB()
 	if err != nil {
@@ -91,6 +95,7 @@ func QueryFirstWithOutTrashed(querySQL string, args ...interface{}) (*Record, er
 	return db.QueryFirstWithOutTrashed(querySQL, args...)
 }
 
+// Exec 执行 SQL 语句，占位符和参数的处理规则与 Query 相同
 func Exec(querySQL string, args ...interface{}) (sql.Result, error) {
 	db, err := defaultDB()
 	if err != nil {
@@ -392,11 +397,13 @@ func BeginTransaction() (*Tx, error) {
 	if err != nil {
 		return nil, err
 	}
-	tx, err := sdb.Begin()
+	tx, watchdog, err := dbMgr.beginTx(sdb)
 	if err != nil {
 		return nil, err
 	}
-	return &Tx{tx: tx, dbMgr: dbMgr}, nil
+	dbtx := &Tx{tx: tx, dbMgr: dbMgr, watchdog: watchdog}
+	trackTxLeak(dbtx)
+	return dbtx, nil
 }
 
 func ExecTx(tx *Tx, querySQL string, args ...interface{}) (sql.Result, error) {
@@ -518,6 +525,14 @@ func (db *DB) RedisCache(cacheRepositoryName string, ttl ...time.Duration) *DB {
 	return db
 }
 
+// CacheEmpty 设置空结果（空列表、未找到记录）的缓存时间，需配合 Cache/LocalCache/RedisCache 使用
+// ttl > 0 表示以该时间缓存空结果，NoStoreEmpty 表示不缓存空结果；未设置时使用 SetCacheEmptyTTL 的仓库配置
+// 示例: eorm.Cache("user_cache", time.Minute).CacheEmpty(10*time.Second).QueryFirst(sql, id)
+func (db *DB) CacheEmpty(ttl time.Duration) *DB {
+	db.cacheEmptyTTL = ttl
+	return db
+}
+
 // Timeout sets the query timeout for this DB instance
 func (db *DB) Timeout(d time.Duration) *DB {
 	db.timeout = d
@@ -545,8 +560,8 @@ func (db *DB) Query(querySQL string, args ...interface{}) ([]*Record, error) {
 	}
 	if db.cacheRepositoryName != "" {
 		cache := db.getEffectiveCache()
-		key := GenerateCacheKey(db.dbMgr.name, querySQL, args...)
-		if val, ok := cache.CacheGet(db.cacheRepositoryName, key); ok {
+		key := GenerateCacheKeyContext(db.ctx, db.dbMgr.name, querySQL, args...)
+		if val, ok := cacheGet(cache, db.cacheRepositoryName, key); ok {
 			var results []*Record
 			if convertCacheValue(val, &results) {
 				return results, nil
@@ -555,7 +570,7 @@ func (db *DB) Query(querySQL string, args ...interface{}) ([]*Record, error) {
 
 		results, err := db.dbMgr.queryWithContext(ctx, executor, querySQL, args...)
 		if err == nil {
-			cache.CacheSet(db.cacheRepositoryName, key, results, getEffectiveTTL(db.cacheRepositoryName, db.cacheTTL))
+			storeCacheResult(cache, db.cacheRepositoryName, key, results, len(results) == 0, getEffectiveTTL(db.cacheRepositoryName, db.cacheTTL), db.cacheEmptyTTL)
 		}
 		return results, err
 	}
@@ -575,16 +590,19 @@ func (db *DB) QueryFirst(querySQL string, args ...interface{}) (*Record, error)
 	}
 	if db.cacheRepositoryName != "" {
 		cache := db.getEffectiveCache()
-		key := GenerateCacheKey(db.dbMgr.name, querySQL, args...)
-		if val, ok := cache.CacheGet(db.cacheRepositoryName, key); ok {
+		key := GenerateCacheKeyContext(db.ctx, db.dbMgr.name, querySQL, args...)
+		if val, ok := cacheGet(cache, db.cacheRepositoryName, key); ok {
+			if isCacheEmptyMarker(val) {
+				return nil, nil
+			}
 			var result *Record
 			if convertCacheValue(val, &result) {
 				return result, nil
 			}
 		}
 		result, err := db.dbMgr.queryFirstWithContext(ctx, executor, querySQL, args...)
-		if err == nil && result != nil {
-			cache.CacheSet(db.cacheRepositoryName, key, result, getEffectiveTTL(db.cacheRepositoryName, db.cacheTTL))
+		if err == nil {
+			storeCacheResult(cache, db.cacheRepositoryName, key, result, result == nil, getEffectiveTTL(db.cacheRepositoryName, db.cacheTTL), db.cacheEmptyTTL)
 		}
 		return result, err
 	}
@@ -596,7 +614,7 @@ func (db *DB) QueryToDbModel(dest interface{}, querySQL string, args ...interfac
 	if err != nil {
 		return err
 	}
-	return ToStructs(records, dest)
+	return db.ToStructs(records, dest)
 }
 
 func (db *DB) QueryFirstToDbModel(dest interface{}, querySQL string, args ...interface{}) error {
@@ -607,7 +625,7 @@ func (db *DB) QueryFirstToDbModel(dest interface{}, querySQL string, args ...int
 	if record == nil {
 		return fmt.Errorf("eorm: no record found")
 	}
-	return ToStruct(record, dest)
+	return db.ToStruct(record, dest)
 }
 
 func (db *DB) QueryMap(querySQL string, args ...interface{}) ([]map[string]interface{}, error) {
@@ -622,8 +640,8 @@ func (db *DB) QueryMap(querySQL string, args ...interface{}) ([]map[string]inter
 	}
 	if db.cacheRepositoryName != "" {
 		cache := db.getEffectiveCache()
-		key := GenerateCacheKey(db.dbMgr.name, querySQL, args...)
-		if val, ok := cache.CacheGet(db.cacheRepositoryName, key); ok {
+		key := GenerateCacheKeyContext(db.ctx, db.dbMgr.name, querySQL, args...)
+		if val, ok := cacheGet(cache, db.cacheRepositoryName, key); ok {
 			var results []map[string]interface{}
 			if convertCacheValue(val, &results) {
 				return results, nil
@@ -631,7 +649,7 @@ func (db *DB) QueryMap(querySQL string, args ...interface{}) ([]map[string]inter
 		}
 		results, err := db.dbMgr.queryMapWithContext(ctx, executor, querySQL, args...)
 		if err == nil {
-			cache.CacheSet(db.cacheRepositoryName, key, results, getEffectiveTTL(db.cacheRepositoryName, db.cacheTTL))
+			storeCacheResult(cache, db.cacheRepositoryName, key, results, len(results) == 0, getEffectiveTTL(db.cacheRepositoryName, db.cacheTTL), db.cacheEmptyTTL)
 		}
 		return results, err
 	}
@@ -731,7 +749,7 @@ func (db *DB) BatchExec(sqls []string, args ...[]interface{}) ([]StatementResult
 	}
 
 	// 调用 dbMgr.batchExecWithContext
-	return db.dbMgr.batchExecWithContext(ctx, executor, sqls, actualArgs)
+	return db.dbMgr.batchExecWithContext(ctx, executor, sqls, actualArgs, false)
 }
 
 // 如果是单一 int64 主键且数据库返回了 ID，会自动回填到 record 中
@@ -743,7 +761,7 @@ func (db *DB) SaveRecord(table string, record *Record) (int64, error) {
 	if err != nil {
 		return 0, err
 	}
-	id, err := db.dbMgr.saveRecord(executor, table, record)
+	id, err := db.dbMgr.saveRecord(db.ctx, executor, table, record)
 	if err == nil && db.cacheRepositoryName != "" {
 		db.ClearCache(db.cacheRepositoryName)
 	}
@@ -766,7 +784,7 @@ func (db *DB) InsertRecord(table string, record *Record) (int64, error) {
 	if err != nil {
 		return 0, err
 	}
-	id, err := db.dbMgr.insertRecord(executor, table, record)
+	id, err := db.dbMgr.insertRecord(db.ctx, executor, table, record)
 	if err == nil && db.cacheRepositoryName != "" {
 		db.ClearCache(db.cacheRepositoryName)
 	}
@@ -788,7 +806,7 @@ func (db *DB) UpdateRecord(table string, record *Record) (int64, error) {
 	if err != nil {
 		return 0, err
 	}
-	id, err := db.dbMgr.updateRecord(executor, table, record)
+	id, err := db.dbMgr.updateRecord(db.ctx, executor, table, record)
 
 	return id, err
 }
@@ -801,13 +819,14 @@ func (db *DB) insertWithOptions(table string, record *Record, skipTimestamps boo
 	if err != nil {
 		return 0, err
 	}
-	return db.dbMgr.insertRecordWithOptions(executor, table, record, skipTimestamps)
+	return db.dbMgr.insertRecordWithOptions(db.ctx, executor, table, record, skipTimestamps)
 }
 
 func (db *DB) Update(table string, record *Record, whereSql string, whereArgs ...interface{}) (int64, error) {
 	if db.lastErr != nil {
 		return 0, db.lastErr
 	}
+	whereSql, whereArgs = applyRowFilter(db.ctx, table, whereSql, whereArgs, true)
 	executor, err := db.getExecutor()
 	if err != nil {
 		return 0, err
@@ -816,9 +835,9 @@ func (db *DB) Update(table string, record *Record, whereSql string, whereArgs ..
 	var rows int64
 	// If both feature checks are disabled, use fast path directly
 	if !db.dbMgr.enableTimestampCheck && !db.dbMgr.enableOptimisticLockCheck {
-		rows, err = db.dbMgr.updateRecordFast(executor, table, record, whereSql, whereArgs...)
+		rows, err = db.dbMgr.updateRecordFast(db.ctx, executor, table, record, whereSql, whereArgs...)
 	} else {
-		rows, err = db.dbMgr.update(executor, table, record, whereSql, whereArgs...)
+		rows, err = db.dbMgr.update(db.ctx, executor, table, record, whereSql, whereArgs...)
 	}
 
 	if err == nil && db.cacheRepositoryName != "" {
@@ -832,33 +851,36 @@ func (db *DB) UpdateFast(table string, record *Record, whereSql string, whereArg
 	if db.lastErr != nil {
 		return 0, db.lastErr
 	}
+	whereSql, whereArgs = applyRowFilter(db.ctx, table, whereSql, whereArgs, true)
 	executor, err := db.getExecutor()
 	if err != nil {
 		return 0, err
 	}
-	return db.dbMgr.updateRecordFast(executor, table, record, whereSql, whereArgs...)
+	return db.dbMgr.updateRecordFast(db.ctx, executor, table, record, whereSql, whereArgs...)
 }
 
 func (db *DB) updateWithOptions(table string, record *Record, whereSql string, skipTimestamps bool, whereArgs ...interface{}) (int64, error) {
 	if db.lastErr != nil {
 		return 0, db.lastErr
 	}
+	whereSql, whereArgs = applyRowFilter(db.ctx, table, whereSql, whereArgs, true)
 	executor, err := db.getExecutor()
 	if err != nil {
 		return 0, err
 	}
-	return db.dbMgr.updateRecordWithOptions(executor, table, record, whereSql, skipTimestamps, whereArgs...)
+	return db.dbMgr.updateRecordWithOptions(db.ctx, executor, table, record, whereSql, skipTimestamps, whereArgs...)
 }
 
 func (db *DB) Delete(table string, whereSql string, whereArgs ...interface{}) (int64, error) {
 	if db.lastErr != nil {
 		return 0, db.lastErr
 	}
+	whereSql, whereArgs = applyRowFilter(db.ctx, table, whereSql, whereArgs, true)
 	executor, err := db.getExecutor()
 	if err != nil {
 		return 0, err
 	}
-	rows, err := db.dbMgr.delete(executor, table, whereSql, whereArgs...)
+	rows, err := db.dbMgr.delete(db.ctx, executor, table, whereSql, whereArgs...)
 	if err == nil && db.cacheRepositoryName != "" {
 		db.ClearCache(db.cacheRepositoryName)
 	}
@@ -873,7 +895,7 @@ func (db *DB) DeleteRecord(table string, record *Record) (int64, error) {
 	if err != nil {
 		return 0, err
 	}
-	return db.dbMgr.deleteRecord(executor, table, record)
+	return db.dbMgr.deleteRecord(db.ctx, executor, table, record)
 }
 
 func (db *DB) BatchInsertRecord(table string, records []*Record, batchSize ...int) (int64, error) {
@@ -889,7 +911,7 @@ func (db *DB) BatchInsertRecord(table string, records []*Record, batchSize ...in
 	if len(batchSize) > 0 && batchSize[0] > 0 {
 		size = batchSize[0]
 	}
-	return db.dbMgr.batchInsertRecord(executor, table, records, size)
+	return db.dbMgr.batchInsertRecord(db.ctx, executor, table, records, size)
 }
 
 // BatchUpdateRecord updates multiple records by primary key
@@ -906,7 +928,7 @@ func (db *DB) BatchUpdateRecord(table string, records []*Record, batchSize ...in
 	if len(batchSize) > 0 && batchSize[0] > 0 {
 		size = batchSize[0]
 	}
-	return db.dbMgr.batchUpdateRecord(executor, table, records, size)
+	return db.dbMgr.batchUpdateRecord(db.ctx, executor, table, records, size)
 }
 
 // BatchDeleteRecord deletes multiple records by primary key
@@ -923,7 +945,7 @@ func (db *DB) BatchDeleteRecord(table string, records []*Record, batchSize ...in
 	if len(batchSize) > 0 && batchSize[0] > 0 {
 		size = batchSize[0]
 	}
-	return db.dbMgr.batchDeleteRecord(executor, table, records, size)
+	return db.dbMgr.batchDeleteRecord(db.ctx, executor, table, records, size)
 }
 
 // BatchDeleteByIds deletes records by primary key IDs
@@ -940,21 +962,22 @@ func (db *DB) BatchDeleteByIds(table string, ids []interface{}, batchSize ...int
 	if len(batchSize) > 0 && batchSize[0] > 0 {
 		size = batchSize[0]
 	}
-	return db.dbMgr.batchDeleteByIds(executor, table, ids, size)
+	return db.dbMgr.batchDeleteByIds(db.ctx, executor, table, ids, size)
 }
 
 func (db *DB) Count(table string, whereSql string, whereArgs ...interface{}) (int64, error) {
 	if db.lastErr != nil {
 		return 0, db.lastErr
 	}
+	whereSql, whereArgs = applyRowFilter(db.ctx, table, whereSql, whereArgs, false)
 	executor, err := db.getExecutor()
 	if err != nil {
 		return 0, err
 	}
 	if db.cacheRepositoryName != "" {
 		cache := db.getEffectiveCache()
-		key := GenerateCacheKey(db.dbMgr.name, "COUNT:"+table+":"+whereSql, whereArgs...)
-		if val, ok := cache.CacheGet(db.cacheRepositoryName, key); ok {
+		key := GenerateCacheKeyContext(db.ctx, db.dbMgr.name, "COUNT:"+table+":"+whereSql, whereArgs...)
+		if val, ok := cacheGet(cache, db.cacheRepositoryName, key); ok {
 			var count int64
 			if convertCacheValue(val, &count) {
 				return count, nil
@@ -962,7 +985,7 @@ func (db *DB) Count(table string, whereSql string, whereArgs ...interface{}) (in
 		}
 		count, err := db.dbMgr.count(executor, table, whereSql, whereArgs...)
 		if err == nil {
-			cache.CacheSet(db.cacheRepositoryName, key, count, getEffectiveTTL(db.cacheRepositoryName, db.cacheTTL))
+			cacheSet(cache, db.cacheRepositoryName, key, count, getEffectiveTTL(db.cacheRepositoryName, db.cacheTTL))
 		}
 		return count, err
 	}
@@ -980,6 +1003,7 @@ func (db *DB) Exists(table string, whereSql string, whereArgs ...interface{}) (b
 	if db.lastErr != nil {
 		return false, db.lastErr
 	}
+	whereSql, whereArgs = applyRowFilter(db.ctx, table, whereSql, whereArgs, false)
 	executor, err := db.getExecutor()
 	if err != nil {
 		return false, err
@@ -991,6 +1015,11 @@ func (db *DB) PaginateBuilder(page int, pageSize int, selectSql string, table st
 	if db.lastErr != nil {
 		return nil, db.lastErr
 	}
+	page, pageSize, err := db.dbMgr.checkPagination(db.ctx, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	whereSql, args = applyRowFilter(db.ctx, table, whereSql, args, false)
 	sdb, err := db.dbMgr.getDB()
 	if err != nil {
 		return nil, err
@@ -1018,23 +1047,23 @@ func (db *DB) PaginateBuilder(page int, pageSize int, selectSql string, table st
 	if db.cacheRepositoryName != "" {
 		cache := db.getEffectiveCache()
 		// 缓存键包含 page 和 pageSize，确保不同页码使用不同的缓存
-		key := GenerateCacheKey(db.dbMgr.name, fmt.Sprintf("PAGINATE:p%d_s%d:%s", page, pageSize, querySQL), args...)
-		if val, ok := cache.CacheGet(db.cacheRepositoryName, key); ok {
+		key := GenerateCacheKeyContext(db.ctx, db.dbMgr.name, fmt.Sprintf("PAGINATE:p%d_s%d:%s", page, pageSize, querySQL), args...)
+		if val, ok := cacheGet(cache, db.cacheRepositoryName, key); ok {
 			var pageObj *Page[*Record]
 			if convertCacheValue(val, &pageObj) {
 				return pageObj, nil
 			}
 		}
-		list, totalRow, err := db.dbMgr.paginate(sdb, querySQL, page, pageSize, db.countCacheTTL, args...)
+		list, totalRow, err := db.dbMgr.paginate(db.ctx, sdb, querySQL, page, pageSize, db.countCacheTTL, args...)
 		if err == nil {
 			pageObj := NewPage(list, page, pageSize, totalRow)
-			cache.CacheSet(db.cacheRepositoryName, key, pageObj, getEffectiveTTL(db.cacheRepositoryName, db.cacheTTL))
+			cacheSet(cache, db.cacheRepositoryName, key, pageObj, getEffectiveTTL(db.cacheRepositoryName, db.cacheTTL))
 			return pageObj, nil
 		}
 		return nil, err
 	}
 
-	list, totalRow, err := db.dbMgr.paginate(sdb, querySQL, page, pageSize, db.countCacheTTL, args...)
+	list, totalRow, err := db.dbMgr.paginate(db.ctx, sdb, querySQL, page, pageSize, db.countCacheTTL, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -1047,6 +1076,10 @@ func (db *DB) Paginate(page int, pageSize int, querySQL string, args ...interfac
 	if db.lastErr != nil {
 		return nil, db.lastErr
 	}
+	page, pageSize, err := db.dbMgr.checkPagination(db.ctx, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
 	sdb, err := db.dbMgr.getDB()
 	if err != nil {
 		return nil, err
@@ -1054,23 +1087,23 @@ func (db *DB) Paginate(page int, pageSize int, querySQL string, args ...interfac
 	if db.cacheRepositoryName != "" {
 		cache := db.getEffectiveCache()
 		// 缓存键包含 page 和 pageSize，确保不同页码使用不同的缓存
-		key := GenerateCacheKey(db.dbMgr.name, fmt.Sprintf("PAGINATE_SQL:p%d_s%d:%s", page, pageSize, querySQL), args...)
-		if val, ok := cache.CacheGet(db.cacheRepositoryName, key); ok {
+		key := GenerateCacheKeyContext(db.ctx, db.dbMgr.name, fmt.Sprintf("PAGINATE_SQL:p%d_s%d:%s", page, pageSize, querySQL), args...)
+		if val, ok := cacheGet(cache, db.cacheRepositoryName, key); ok {
 			var pageObj *Page[*Record]
 			if convertCacheValue(val, &pageObj) {
 				return pageObj, nil
 			}
 		}
-		list, totalRow, err := db.dbMgr.paginate(sdb, querySQL, page, pageSize, db.countCacheTTL, args...)
+		list, totalRow, err := db.dbMgr.paginate(db.ctx, sdb, querySQL, page, pageSize, db.countCacheTTL, args...)
 		if err == nil {
 			pageObj := NewPage(list, page, pageSize, totalRow)
-			cache.CacheSet(db.cacheRepositoryName, key, pageObj, getEffectiveTTL(db.cacheRepositoryName, db.cacheTTL))
+			cacheSet(cache, db.cacheRepositoryName, key, pageObj, getEffectiveTTL(db.cacheRepositoryName, db.cacheTTL))
 			return pageObj, nil
 		}
 		return nil, err
 	}
 
-	list, totalRow, err := db.dbMgr.paginate(sdb, querySQL, page, pageSize, db.countCacheTTL, args...)
+	list, totalRow, err := db.dbMgr.paginate(db.ctx, sdb, querySQL, page, pageSize, db.countCacheTTL, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -1084,6 +1117,9 @@ func (db *DB) FindAll(table string) ([]*Record, error) {
 	if err := ValidateTableName(table); err != nil {
 		return nil, err
 	}
+	if condition, args := getRowFilterCondition(db.ctx, table, false); condition != "" {
+		return db.Query(fmt.Sprintf("SELECT * FROM %s WHERE %s", table, condition), args...)
+	}
 	return db.Query(fmt.Sprintf("SELECT * FROM %s", table))
 }
 
@@ -1096,7 +1132,7 @@ func (db *DB) SaveDbModel(model IDbModel) (int64, error) {
 	if err != nil {
 		return 0, err
 	}
-	record := ToRecord(model)
+	record := db.ToRecord(model)
 	// For Save, we also want to handle auto-increment PKs if they are 0
 	pks, _ := db.dbMgr.getPrimaryKeys(sdb, model.TableName())
 	for _, pk := range pks {
@@ -1106,7 +1142,7 @@ func (db *DB) SaveDbModel(model IDbModel) (int64, error) {
 	}
 	id, err := db.SaveRecord(model.TableName(), record)
 
-	record.ToStruct(model)
+	db.ToStruct(record, model)
 	return id, err
 }
 
@@ -1118,7 +1154,7 @@ func (db *DB) InsertDbModel(model IDbModel) (int64, error) {
 	if err != nil {
 		return 0, err
 	}
-	record := ToRecord(model)
+	record := db.ToRecord(model)
 	// Remove primary key if it's 0 to let DB auto-increment
 	pks, _ := db.dbMgr.getPrimaryKeys(sdb, model.TableName())
 	for _, pk := range pks {
@@ -1129,7 +1165,7 @@ func (db *DB) InsertDbModel(model IDbModel) (int64, error) {
 
 	id, err := db.InsertRecord(model.TableName(), record)
 
-	record.ToStruct(model)
+	db.ToStruct(record, model)
 
 	return id, err
 }
@@ -1138,7 +1174,7 @@ func (db *DB) UpdateDbModel(model IDbModel) (int64, error) {
 	if db.lastErr != nil {
 		return 0, db.lastErr
 	}
-	record := ToRecord(model)
+	record := db.ToRecord(model)
 	return db.UpdateRecord(model.TableName(), record)
 }
 
@@ -1146,7 +1182,7 @@ func (db *DB) DeleteDbModel(model IDbModel) (int64, error) {
 	if db.lastErr != nil {
 		return 0, db.lastErr
 	}
-	record := ToRecord(model)
+	record := db.ToRecord(model)
 	return db.DeleteRecord(model.TableName(), record)
 }
 
@@ -1184,17 +1220,17 @@ func (db *DB) Transaction(fn func(*Tx) error) (err error) {
 	if err != nil {
 		return err
 	}
-	tx, err := sdb.Begin()
+	tx, watchdog, err := db.dbMgr.beginTx(sdb)
 	if err != nil {
 		return err
 	}
 
-	dbtx := &Tx{tx: tx, dbMgr: db.dbMgr}
+	dbtx := &Tx{tx: tx, dbMgr: db.dbMgr, ctx: db.ctx, watchdog: watchdog}
 
 	defer func() {
 		if p := recover(); p != nil {
 			// 发生 Panic 时强制回滚
-			if rbErr := tx.Rollback(); rbErr != nil {
+			if rbErr := dbtx.Rollback(); rbErr != nil {
 				LogError("transaction rollback failed on panic", NewRecord().Set("rollback_error", rbErr.Error()).Set("panic", p))
 			}
 			// 重新抛出 Panic 以保留堆栈信息，防止静默失败
@@ -1203,13 +1239,13 @@ func (db *DB) Transaction(fn func(*Tx) error) (err error) {
 	}()
 
 	if err = fn(dbtx); err != nil {
-		if rbErr := tx.Rollback(); rbErr != nil {
+		if rbErr := dbtx.Rollback(); rbErr != nil {
 			LogError("transaction rollback failed", NewRecord().Set("original_error", err.Error()).Set("rollback_error", rbErr.Error()))
 		}
 		return err
 	}
 
-	return tx.Commit()
+	return dbtx.Commit()
 }
 
 // --- Tx Methods (Operation within a transaction) ---
@@ -1257,6 +1293,12 @@ func (tx *Tx) RedisCache(cacheRepositoryName string, ttl ...time.Duration) *Tx {
 	return tx
 }
 
+// CacheEmpty 设置空结果的缓存时间，需配合 Cache/LocalCache/RedisCache 使用
+func (tx *Tx) CacheEmpty(ttl time.Duration) *Tx {
+	tx.cacheEmptyTTL = ttl
+	return tx
+}
+
 // Timeout sets the query timeout for this transaction
 func (tx *Tx) Timeout(d time.Duration) *Tx {
 	tx.timeout = d
@@ -1277,19 +1319,20 @@ func (tx *Tx) getTimeout() time.Duration {
 	if tx.timeout > 0 {
 		return tx.timeout
 	}
-	if tx.dbMgr != nil && tx.dbMgr.config != nil && tx.dbMgr.config.QueryTimeout > 0 {
-		return tx.dbMgr.config.QueryTimeout
+	if tx.dbMgr != nil && tx.dbMgr.config.Load() != nil && tx.dbMgr.config.Load().QueryTimeout > 0 {
+		return tx.dbMgr.config.Load().QueryTimeout
 	}
 	return 0
 }
 
 // getContext returns a context with timeout if configured
 func (tx *Tx) getContext() (context.Context, context.CancelFunc) {
+	ctx := contextOrBackground(tx.ctx)
 	timeout := tx.getTimeout()
 	if timeout > 0 {
-		return context.WithTimeout(context.Background(), timeout)
+		return context.WithTimeout(ctx, timeout)
 	}
-	return context.Background(), func() {}
+	return ctx, func() {}
 }
 
 func (tx *Tx) Query(querySQL string, args ...interface{}) ([]*Record, error) {
@@ -1298,8 +1341,8 @@ func (tx *Tx) Query(querySQL string, args ...interface{}) ([]*Record, error) {
 
 	if tx.cacheRepositoryName != "" {
 		cache := tx.getEffectiveCache()
-		key := GenerateCacheKey(tx.dbMgr.name, querySQL, args...)
-		if val, ok := cache.CacheGet(tx.cacheRepositoryName, key); ok {
+		key := GenerateCacheKeyContext(tx.ctx, tx.dbMgr.name, querySQL, args...)
+		if val, ok := cacheGet(cache, tx.cacheRepositoryName, key); ok {
 			var results []*Record
 			if convertCacheValue(val, &results) {
 				return results, nil
@@ -1307,7 +1350,7 @@ func (tx *Tx) Query(querySQL string, args ...interface{}) ([]*Record, error) {
 		}
 		results, err := tx.dbMgr.queryWithContext(ctx, tx.tx, querySQL, args...)
 		if err == nil {
-			cache.CacheSet(tx.cacheRepositoryName, key, results, getEffectiveTTL(tx.cacheRepositoryName, tx.cacheTTL))
+			storeCacheResult(cache, tx.cacheRepositoryName, key, results, len(results) == 0, getEffectiveTTL(tx.cacheRepositoryName, tx.cacheTTL), tx.cacheEmptyTTL)
 		}
 		return results, err
 	}
@@ -1320,16 +1363,19 @@ func (tx *Tx) QueryFirst(querySQL string, args ...interface{}) (*Record, error)
 
 	if tx.cacheRepositoryName != "" {
 		cache := tx.getEffectiveCache()
-		key := GenerateCacheKey(tx.dbMgr.name, querySQL, args...)
-		if val, ok := cache.CacheGet(tx.cacheRepositoryName, key); ok {
+		key := GenerateCacheKeyContext(tx.ctx, tx.dbMgr.name, querySQL, args...)
+		if val, ok := cacheGet(cache, tx.cacheRepositoryName, key); ok {
+			if isCacheEmptyMarker(val) {
+				return nil, nil
+			}
 			var result *Record
 			if convertCacheValue(val, &result) {
 				return result, nil
 			}
 		}
 		result, err := tx.dbMgr.queryFirstWithContext(ctx, tx.tx, querySQL, args...)
-		if err == nil && result != nil {
-			cache.CacheSet(tx.cacheRepositoryName, key, result, getEffectiveTTL(tx.cacheRepositoryName, tx.cacheTTL))
+		if err == nil {
+			storeCacheResult(cache, tx.cacheRepositoryName, key, result, result == nil, getEffectiveTTL(tx.cacheRepositoryName, tx.cacheTTL), tx.cacheEmptyTTL)
 		}
 		return result, err
 	}
@@ -1341,7 +1387,7 @@ func (tx *Tx) QueryToDbModel(dest interface{}, querySQL string, args ...interfac
 	if err != nil {
 		return err
 	}
-	return ToStructs(records, dest)
+	return tx.ToStructs(records, dest)
 }
 
 func (tx *Tx) QueryFirstToDbModel(dest interface{}, querySQL string, args ...interface{}) error {
@@ -1352,7 +1398,7 @@ func (tx *Tx) QueryFirstToDbModel(dest interface{}, querySQL string, args ...int
 	if record == nil {
 		return fmt.Errorf("eorm: no record found")
 	}
-	return ToStruct(record, dest)
+	return tx.ToStruct(record, dest)
 }
 
 func (tx *Tx) QueryMap(querySQL string, args ...interface{}) ([]map[string]interface{}, error) {
@@ -1361,8 +1407,8 @@ func (tx *Tx) QueryMap(querySQL string, args ...interface{}) ([]map[string]inter
 
 	if tx.cacheRepositoryName != "" {
 		cache := tx.getEffectiveCache()
-		key := GenerateCacheKey(tx.dbMgr.name, querySQL, args...)
-		if val, ok := cache.CacheGet(tx.cacheRepositoryName, key); ok {
+		key := GenerateCacheKeyContext(tx.ctx, tx.dbMgr.name, querySQL, args...)
+		if val, ok := cacheGet(cache, tx.cacheRepositoryName, key); ok {
 			var results []map[string]interface{}
 			if convertCacheValue(val, &results) {
 				return results, nil
@@ -1370,7 +1416,7 @@ func (tx *Tx) QueryMap(querySQL string, args ...interface{}) ([]map[string]inter
 		}
 		results, err := tx.dbMgr.queryMapWithContext(ctx, tx.tx, querySQL, args...)
 		if err == nil {
-			cache.CacheSet(tx.cacheRepositoryName, key, results, getEffectiveTTL(tx.cacheRepositoryName, tx.cacheTTL))
+			storeCacheResult(cache, tx.cacheRepositoryName, key, results, len(results) == 0, getEffectiveTTL(tx.cacheRepositoryName, tx.cacheTTL), tx.cacheEmptyTTL)
 		}
 		return results, err
 	}
@@ -1430,7 +1476,7 @@ func (tx *Tx) Exec(querySQL string, args ...interface{}) (sql.Result, error) {
 }
 
 func (tx *Tx) SaveRecord(table string, record *Record) (int64, error) {
-	id, err := tx.dbMgr.saveRecord(tx.tx, table, record)
+	id, err := tx.dbMgr.saveRecord(tx.ctx, tx.tx, table, record)
 	if err == nil && tx.cacheRepositoryName != "" {
 		tx.ClearCache(tx.cacheRepositoryName)
 	}
@@ -1438,7 +1484,7 @@ func (tx *Tx) SaveRecord(table string, record *Record) (int64, error) {
 }
 
 func (tx *Tx) InsertRecord(table string, record *Record) (int64, error) {
-	id, err := tx.dbMgr.insertRecord(tx.tx, table, record)
+	id, err := tx.dbMgr.insertRecord(tx.ctx, tx.tx, table, record)
 	if err == nil && tx.cacheRepositoryName != "" {
 		tx.ClearCache(tx.cacheRepositoryName)
 	}
@@ -1446,11 +1492,22 @@ func (tx *Tx) InsertRecord(table string, record *Record) (int64, error) {
 }
 
 func (tx *Tx) insertWithOptions(table string, record *Record, skipTimestamps bool) (int64, error) {
-	return tx.dbMgr.insertRecordWithOptions(tx.tx, table, record, skipTimestamps)
+	return tx.dbMgr.insertRecordWithOptions(tx.ctx, tx.tx, table, record, skipTimestamps)
 }
 
 func (tx *Tx) Update(table string, record *Record, whereSql string, whereArgs ...interface{}) (int64, error) {
-	rows, err := tx.dbMgr.update(tx.tx, table, record, whereSql, whereArgs...)
+	whereSql, whereArgs = applyRowFilter(tx.ctx, table, whereSql, whereArgs, true)
+	rows, err := tx.dbMgr.update(tx.ctx, tx.tx, table, record, whereSql, whereArgs...)
+	if err == nil && tx.cacheRepositoryName != "" {
+		tx.ClearCache(tx.cacheRepositoryName)
+	}
+	return rows, err
+}
+
+// UpdateFast 在事务中执行轻量级更新，跳过时间戳和乐观锁检查
+func (tx *Tx) UpdateFast(table string, record *Record, whereSql string, whereArgs ...interface{}) (int64, error) {
+	whereSql, whereArgs = applyRowFilter(tx.ctx, table, whereSql, whereArgs, true)
+	rows, err := tx.dbMgr.updateRecordFast(tx.ctx, tx.tx, table, record, whereSql, whereArgs...)
 	if err == nil && tx.cacheRepositoryName != "" {
 		tx.ClearCache(tx.cacheRepositoryName)
 	}
@@ -1458,15 +1515,17 @@ func (tx *Tx) Update(table string, record *Record, whereSql string, whereArgs ..
 }
 
 func (tx *Tx) updateWithOptions(table string, record *Record, whereSql string, skipTimestamps bool, whereArgs ...interface{}) (int64, error) {
-	return tx.dbMgr.updateRecordWithOptions(tx.tx, table, record, whereSql, skipTimestamps, whereArgs...)
+	whereSql, whereArgs = applyRowFilter(tx.ctx, table, whereSql, whereArgs, true)
+	return tx.dbMgr.updateRecordWithOptions(tx.ctx, tx.tx, table, record, whereSql, skipTimestamps, whereArgs...)
 }
 
 func (tx *Tx) UpdateRecord(table string, record *Record) (int64, error) {
-	return tx.dbMgr.updateRecord(tx.tx, table, record)
+	return tx.dbMgr.updateRecord(tx.ctx, tx.tx, table, record)
 }
 
 func (tx *Tx) Delete(table string, whereSql string, whereArgs ...interface{}) (int64, error) {
-	rows, err := tx.dbMgr.delete(tx.tx, table, whereSql, whereArgs...)
+	whereSql, whereArgs = applyRowFilter(tx.ctx, table, whereSql, whereArgs, true)
+	rows, err := tx.dbMgr.delete(tx.ctx, tx.tx, table, whereSql, whereArgs...)
 	if err == nil && tx.cacheRepositoryName != "" {
 		tx.ClearCache(tx.cacheRepositoryName)
 	}
@@ -1474,7 +1533,7 @@ func (tx *Tx) Delete(table string, whereSql string, whereArgs ...interface{}) (i
 }
 
 func (tx *Tx) DeleteRecord(table string, record *Record) (int64, error) {
-	return tx.dbMgr.deleteRecord(tx.tx, table, record)
+	return tx.dbMgr.deleteRecord(tx.ctx, tx.tx, table, record)
 }
 
 func (tx *Tx) BatchInsertRecord(table string, records []*Record, batchSize ...int) (int64, error) {
@@ -1483,7 +1542,7 @@ func (tx *Tx) BatchInsertRecord(table string, records []*Record, batchSize ...in
 	if len(batchSize) > 0 && batchSize[0] > 0 {
 		size = batchSize[0]
 	}
-	return tx.dbMgr.batchInsertRecord(tx.tx, table, records, size)
+	return tx.dbMgr.batchInsertRecord(tx.ctx, tx.tx, table, records, size)
 }
 
 // BatchUpdateRecord updates multiple records by primary key within transaction
@@ -1493,7 +1552,7 @@ func (tx *Tx) BatchUpdateRecord(table string, records []*Record, batchSize ...in
 	if len(batchSize) > 0 && batchSize[0] > 0 {
 		size = batchSize[0]
 	}
-	return tx.dbMgr.batchUpdateRecord(tx.tx, table, records, size)
+	return tx.dbMgr.batchUpdateRecord(tx.ctx, tx.tx, table, records, size)
 }
 
 // BatchDeleteRecord deletes multiple records by primary key within transaction
@@ -1503,7 +1562,7 @@ func (tx *Tx) BatchDeleteRecord(table string, records []*Record, batchSize ...in
 	if len(batchSize) > 0 && batchSize[0] > 0 {
 		size = batchSize[0]
 	}
-	return tx.dbMgr.batchDeleteRecord(tx.tx, table, records, size)
+	return tx.dbMgr.batchDeleteRecord(tx.ctx, tx.tx, table, records, size)
 }
 
 // BatchDeleteByIds deletes records by primary key IDs within transaction
@@ -1513,14 +1572,15 @@ func (tx *Tx) BatchDeleteByIds(table string, ids []interface{}, batchSize ...int
 	if len(batchSize) > 0 && batchSize[0] > 0 {
 		size = batchSize[0]
 	}
-	return tx.dbMgr.batchDeleteByIds(tx.tx, table, ids, size)
+	return tx.dbMgr.batchDeleteByIds(tx.ctx, tx.tx, table, ids, size)
 }
 
 func (tx *Tx) Count(table string, whereSql string, whereArgs ...interface{}) (int64, error) {
+	whereSql, whereArgs = applyRowFilter(tx.ctx, table, whereSql, whereArgs, false)
 	if tx.cacheRepositoryName != "" {
 		cache := tx.getEffectiveCache()
-		key := GenerateCacheKey(tx.dbMgr.name, "COUNT:"+table+":"+whereSql, whereArgs...)
-		if val, ok := cache.CacheGet(tx.cacheRepositoryName, key); ok {
+		key := GenerateCacheKeyContext(tx.ctx, tx.dbMgr.name, "COUNT:"+table+":"+whereSql, whereArgs...)
+		if val, ok := cacheGet(cache, tx.cacheRepositoryName, key); ok {
 			var count int64
 			if convertCacheValue(val, &count) {
 				return count, nil
@@ -1528,7 +1588,7 @@ func (tx *Tx) Count(table string, whereSql string, whereArgs ...interface{}) (in
 		}
 		count, err := tx.dbMgr.count(tx.tx, table, whereSql, whereArgs...)
 		if err == nil {
-			cache.CacheSet(tx.cacheRepositoryName, key, count, getEffectiveTTL(tx.cacheRepositoryName, tx.cacheTTL))
+			cacheSet(cache, tx.cacheRepositoryName, key, count, getEffectiveTTL(tx.cacheRepositoryName, tx.cacheTTL))
 		}
 		return count, err
 	}
@@ -1536,10 +1596,16 @@ func (tx *Tx) Count(table string, whereSql string, whereArgs ...interface{}) (in
 }
 
 func (tx *Tx) Exists(table string, whereSql string, whereArgs ...interface{}) (bool, error) {
+	whereSql, whereArgs = applyRowFilter(tx.ctx, table, whereSql, whereArgs, false)
 	return tx.dbMgr.exists(tx.tx, table, whereSql, whereArgs...)
 }
 
 func (tx *Tx) PaginateBuilder(page int, pageSize int, selectSql string, table string, whereSql string, orderBySql string, args ...interface{}) (*Page[*Record], error) {
+	page, pageSize, err := tx.dbMgr.checkPagination(tx.ctx, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	whereSql, args = applyRowFilter(tx.ctx, table, whereSql, args, false)
 	if table != "" {
 		if err := ValidateTableName(table); err != nil {
 			return nil, err
@@ -1563,23 +1629,23 @@ func (tx *Tx) PaginateBuilder(page int, pageSize int, selectSql string, table st
 	if tx.cacheRepositoryName != "" {
 		cache := tx.getEffectiveCache()
 		// 缓存键包含 page 和 pageSize，确保不同页码使用不同的缓存
-		key := GenerateCacheKey(tx.dbMgr.name, fmt.Sprintf("PAGINATE:p%d_s%d:%s", page, pageSize, querySQL), args...)
-		if val, ok := cache.CacheGet(tx.cacheRepositoryName, key); ok {
+		key := GenerateCacheKeyContext(tx.ctx, tx.dbMgr.name, fmt.Sprintf("PAGINATE:p%d_s%d:%s", page, pageSize, querySQL), args...)
+		if val, ok := cacheGet(cache, tx.cacheRepositoryName, key); ok {
 			var pageObj *Page[*Record]
 			if convertCacheValue(val, &pageObj) {
 				return pageObj, nil
 			}
 		}
-		list, totalRow, err := tx.dbMgr.paginate(tx.tx, querySQL, page, pageSize, tx.countCacheTTL, args...)
+		list, totalRow, err := tx.dbMgr.paginate(tx.ctx, tx.tx, querySQL, page, pageSize, tx.countCacheTTL, args...)
 		if err == nil {
 			pageObj := NewPage(list, page, pageSize, totalRow)
-			cache.CacheSet(tx.cacheRepositoryName, key, pageObj, getEffectiveTTL(tx.cacheRepositoryName, tx.cacheTTL))
+			cacheSet(cache, tx.cacheRepositoryName, key, pageObj, getEffectiveTTL(tx.cacheRepositoryName, tx.cacheTTL))
 			return pageObj, nil
 		}
 		return nil, err
 	}
 
-	list, totalRow, err := tx.dbMgr.paginate(tx.tx, querySQL, page, pageSize, tx.countCacheTTL, args...)
+	list, totalRow, err := tx.dbMgr.paginate(tx.ctx, tx.tx, querySQL, page, pageSize, tx.countCacheTTL, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -1589,26 +1655,30 @@ func (tx *Tx) PaginateBuilder(page int, pageSize int, selectSql string, table st
 // Paginate 事务分页方法，使用完整SQL语句进行分页查询
 // 在事务上下文中自动解析SQL并根据数据库类型生成相应的分页语句
 func (tx *Tx) Paginate(page int, pageSize int, querySQL string, args ...interface{}) (*Page[*Record], error) {
+	page, pageSize, err := tx.dbMgr.checkPagination(tx.ctx, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
 	if tx.cacheRepositoryName != "" {
 		cache := tx.getEffectiveCache()
 		// 缓存键包含 page 和 pageSize，确保不同页码使用不同的缓存
-		key := GenerateCacheKey(tx.dbMgr.name, fmt.Sprintf("PAGINATE_SQL:p%d_s%d:%s", page, pageSize, querySQL), args...)
-		if val, ok := cache.CacheGet(tx.cacheRepositoryName, key); ok {
+		key := GenerateCacheKeyContext(tx.ctx, tx.dbMgr.name, fmt.Sprintf("PAGINATE_SQL:p%d_s%d:%s", page, pageSize, querySQL), args...)
+		if val, ok := cacheGet(cache, tx.cacheRepositoryName, key); ok {
 			var pageObj *Page[*Record]
 			if convertCacheValue(val, &pageObj) {
 				return pageObj, nil
 			}
 		}
-		list, totalRow, err := tx.dbMgr.paginate(tx.tx, querySQL, page, pageSize, tx.countCacheTTL, args...)
+		list, totalRow, err := tx.dbMgr.paginate(tx.ctx, tx.tx, querySQL, page, pageSize, tx.countCacheTTL, args...)
 		if err == nil {
 			pageObj := NewPage(list, page, pageSize, totalRow)
-			cache.CacheSet(tx.cacheRepositoryName, key, pageObj, getEffectiveTTL(tx.cacheRepositoryName, tx.cacheTTL))
+			cacheSet(cache, tx.cacheRepositoryName, key, pageObj, getEffectiveTTL(tx.cacheRepositoryName, tx.cacheTTL))
 			return pageObj, nil
 		}
 		return nil, err
 	}
 
-	list, totalRow, err := tx.dbMgr.paginate(tx.tx, querySQL, page, pageSize, tx.countCacheTTL, args...)
+	list, totalRow, err := tx.dbMgr.paginate(tx.ctx, tx.tx, querySQL, page, pageSize, tx.countCacheTTL, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -1624,22 +1694,22 @@ func (tx *Tx) FindAll(table string) ([]*Record, error) {
 
 // Struct methods for Tx
 func (tx *Tx) SaveDbModel(model IDbModel) (int64, error) {
-	record := ToRecord(model)
+	record := tx.ToRecord(model)
 	return tx.SaveRecord(model.TableName(), record)
 }
 
 func (tx *Tx) InsertDbModel(model IDbModel) (int64, error) {
-	record := ToRecord(model)
+	record := tx.ToRecord(model)
 	return tx.InsertRecord(model.TableName(), record)
 }
 
 func (tx *Tx) UpdateDbModel(model IDbModel) (int64, error) {
-	record := ToRecord(model)
+	record := tx.ToRecord(model)
 	return tx.UpdateRecord(model.TableName(), record)
 }
 
 func (tx *Tx) DeleteDbModel(model IDbModel) (int64, error) {
-	record := ToRecord(model)
+	record := tx.ToRecord(model)
 	return tx.DeleteRecord(model.TableName(), record)
 }
 
@@ -1662,18 +1732,50 @@ func (tx *Tx) FindToDbModel(dest interface{}, table string, whereSql string, ord
 	return builder.FindToDbModel(dest)
 }
 
+// Commit 提交事务，成功后执行 AfterCommit 回调，提交失败时事务已被回滚，执行 AfterRollback 回调
 func (tx *Tx) Commit() error {
-	return tx.tx.Commit()
+	err := tx.tx.Commit()
+	cancelled := tx.stopWatchdog()
+	releaseLeak(tx.leakID)
+	if err != nil {
+		if cancelled {
+			err = fmt.Errorf("eorm: transaction cancelled by watchdog: %v", err)
+		}
+		tx.dbMgr.finishChanges(tx.tx, false)
+		if err != sql.ErrTxDone {
+			tx.runTxHooks(false)
+		} else {
+			tx.dbMgr.releaseTxHooks(tx.tx)
+		}
+		return err
+	}
+	tx.dbMgr.finishChanges(tx.tx, true)
+	tx.runTxHooks(true)
+	return nil
 }
 
+// Rollback 回滚事务，成功后执行 AfterRollback 回调
 func (tx *Tx) Rollback() error {
-	return tx.tx.Rollback()
+	err := tx.tx.Rollback()
+	releaseLeak(tx.leakID)
+	if cancelled := tx.stopWatchdog(); cancelled && err == sql.ErrTxDone {
+		// 看门狗取消时驱动已回滚事务
+		err = nil
+	}
+	tx.dbMgr.finishChanges(tx.tx, false)
+	if err != nil {
+		tx.dbMgr.releaseTxHooks(tx.tx)
+		return err
+	}
+	tx.runTxHooks(false)
+	return nil
 }
 
 // BatchExec 批量执行多个 SQL 语句（Tx 方法）
 // sqls: SQL 语句列表
 // args: 每个 SQL 语句对应的参数列表（可选，传 nil 或不传表示所有语句都不带参数）
 // 返回: 每个语句的执行结果列表和错误（如果有失败的语句，err 不为 nil）
+// 默认遇到第一条失败语句即停止；调用 ContinueOnError() 后通过保存点跳过失败语句继续执行
 func (tx *Tx) BatchExec(sqls []string, args ...[]interface{}) ([]StatementResult, error) {
 	// 获取超时上下文
 	ctx, cancel := tx.getContext()
@@ -1686,7 +1788,7 @@ func (tx *Tx) BatchExec(sqls []string, args ...[]interface{}) ([]StatementResult
 	}
 
 	// 调用 dbMgr.batchExecWithContext，传入 tx.tx 作为 executor
-	return tx.dbMgr.batchExecWithContext(ctx, tx.tx, sqls, actualArgs)
+	return tx.dbMgr.batchExecWithContext(ctx, tx.tx, sqls, actualArgs, tx.continueOnError)
 }
 
 // convertCacheValue 将缓存值转换为目标类型
@@ -1781,13 +1883,18 @@ func convertCacheValue(val interface{}, dest interface{}) bool {
 		}
 	}
 
-	// 2. 处理 RedisCache 返回的 JSON 字节数组（优化路径）
+	// 2. 处理使用非 JSON 编解码器的缓存提供者返回的延迟解码值
+	if decoder, ok := val.(CacheValueDecoder); ok {
+		return decoder.DecodeInto(dest) == nil
+	}
+
+	// 3. 处理 RedisCache 返回的 JSON 字节数组（优化路径）
 	if jsonBytes, ok := val.([]byte); ok {
 		// 直接从字节数组反序列化，避免字符串转换
 		return json.Unmarshal(jsonBytes, dest) == nil
 	}
 
-	// 3. 降级到 JSON 转换（用于其他复杂类型）
+	// 4. 降级到 JSON 转换（用于其他复杂类型）
 	// 注意：这是最后的手段，性能较差
 	data, err := json.Marshal(val)
 	if err != nil {
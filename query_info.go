@@ -0,0 +1,64 @@
+package eorm
+
+import "time"
+
+// QueryInfo 查询的执行信息，用于输出 X-Cache 等响应头和排查缓存问题
+type QueryInfo struct {
+	FromCache  bool          // 结果来自缓存
+	Stale      bool          // 命中的是 StaleWhileRevalidate 的过期结果（后台正在刷新）
+	Repository string        // 缓存仓库名称，未使用缓存时为空
+	Key        string        // 缓存键，未使用缓存时为空
+	Duration   time.Duration // 查询耗时（包括缓存读取）
+	RowCount   int           // 返回的行数
+}
+
+// FindWithInfo 执行查询并返回执行信息
+// 示例:
+//
+//	records, info, err := eorm.Table("products").Cache("products", time.Minute).FindWithInfo()
+//	if info.FromCache {
+//	    w.Header().Set("X-Cache", "HIT")
+//	}
+func (qb *QueryBuilder) FindWithInfo() ([]*Record, QueryInfo, error) {
+	info := &QueryInfo{}
+	qb.queryInfo = info
+	defer func() { qb.queryInfo = nil }()
+
+	start := time.Now()
+	records, err := qb.Query()
+	info.Duration = time.Since(start)
+	info.RowCount = len(records)
+	return records, *info, err
+}
+
+// FindFirstWithInfo 查询第一条记录并返回执行信息，见 FindWithInfo
+func (qb *QueryBuilder) FindFirstWithInfo() (*Record, QueryInfo, error) {
+	info := &QueryInfo{}
+	qb.queryInfo = info
+	defer func() { qb.queryInfo = nil }()
+
+	start := time.Now()
+	record, err := qb.QueryFirst()
+	info.Duration = time.Since(start)
+	if record != nil {
+		info.RowCount = 1
+	}
+	return record, *info, err
+}
+
+// noteCacheResult 记录缓存命中情况，仅在 FindWithInfo/FindFirstWithInfo 中生效
+func (qb *QueryBuilder) noteCacheResult(cache CacheProvider, key string, hit bool) {
+	info := qb.queryInfo
+	if info == nil {
+		return
+	}
+	info.FromCache = hit
+	info.Repository = qb.cacheRepositoryName
+	info.Key = key
+	if hit {
+		if _, _, swr := qb.swrTTL(); swr {
+			_, fresh := cache.CacheGet(qb.cacheRepositoryName, key+cacheFreshSuffix)
+			info.Stale = !fresh
+		}
+	}
+}
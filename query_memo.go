@@ -0,0 +1,163 @@
+package eorm
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// queryMemoKey context 中查询备忘录的键
+type queryMemoKey struct{}
+
+// queryMemo 单个请求内的查询结果备忘录，相同的 SELECT 只执行一次
+type queryMemo struct {
+	mu      sync.Mutex
+	entries map[string]*queryMemoEntry
+}
+
+// queryMemoEntry 一次查询的结果，done 关闭前其他相同查询等待其完成
+type queryMemoEntry struct {
+	done     chan struct{}
+	writeGen uint64
+	records  []*Record
+	maps     []map[string]interface{}
+	err      error
+}
+
+// WithQueryMemo 返回带查询备忘录的 context：在该 context 下执行的相同 SELECT（相同数据库、SQL 和参数）只查询一次，
+// 后续调用（包括并发调用）共享结果的副本；备忘录随 context 一起丢弃，与 TTL 缓存相互独立
+// 通过 eorm 执行任何写操作后，之前备忘的结果不再使用；事务和 FOR UPDATE 查询不参与备忘
+// 示例:
+//
+//	func handler(w http.ResponseWriter, r *http.Request) {
+//	    ctx := eorm.WithQueryMemo(r.Context())
+//	    user, _ := eorm.WithContext(ctx).QueryFirst("SELECT * FROM users WHERE id = ?", uid)
+//	    // 辅助函数中再次查询同一用户时直接返回备忘的结果
+//	}
+func WithQueryMemo(ctx context.Context) context.Context {
+	ctx = contextOrBackground(ctx)
+	if _, ok := ctx.Value(queryMemoKey{}).(*queryMemo); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, queryMemoKey{}, &queryMemo{entries: make(map[string]*queryMemoEntry)})
+}
+
+// queryMemoFor 返回可用于本次查询的备忘录和键，不适用时返回 nil
+func (mgr *dbManager) queryMemoFor(ctx context.Context, executor sqlExecutor, kind, querySQL string, args []interface{}) (*queryMemo, string) {
+	if ctx == nil {
+		return nil, ""
+	}
+	memo, ok := ctx.Value(queryMemoKey{}).(*queryMemo)
+	if !ok {
+		return nil, ""
+	}
+	// 事务（包括外部执行器）内的查询可能读到未提交的修改，只备忘连接池上的查询
+	switch executor.(type) {
+	case *sql.DB, *serialWriteExecutor:
+	default:
+		return nil, ""
+	}
+	if !isMemoizableQuery(querySQL) {
+		return nil, ""
+	}
+	var sb strings.Builder
+	sb.WriteString(mgr.name)
+	sb.WriteByte(0)
+	sb.WriteString(kind)
+	sb.WriteByte(0)
+	sb.WriteString(querySQL)
+	for _, arg := range args {
+		fmt.Fprintf(&sb, "\x00%T:%v", arg, arg)
+	}
+	return memo, sb.String()
+}
+
+// isMemoizableQuery 只备忘普通 SELECT，排除加锁读取
+func isMemoizableQuery(querySQL string) bool {
+	trimmed := strings.TrimSpace(querySQL)
+	if len(trimmed) < 6 {
+		return false
+	}
+	head := strings.ToUpper(trimmed[:6])
+	if head != "SELECT" && !strings.HasPrefix(head, "WITH") {
+		return false
+	}
+	upper := strings.ToUpper(trimmed)
+	return !strings.Contains(upper, " FOR UPDATE") && !strings.Contains(upper, " FOR SHARE") &&
+		!strings.Contains(upper, " LOCK IN SHARE MODE") && !strings.Contains(upper, "UPDLOCK")
+}
+
+// acquire 返回已有的备忘结果（hit 为 true），或登记一个新的待填充条目由调用方执行查询
+func (m *queryMemo) acquire(ctx context.Context, mgr *dbManager, key string) (entry *queryMemoEntry, hit bool, err error) {
+	gen := atomic.LoadUint64(&mgr.writeGen)
+	for {
+		m.mu.Lock()
+		existing, ok := m.entries[key]
+		if !ok || existing.writeGen != gen {
+			entry = &queryMemoEntry{done: make(chan struct{}), writeGen: gen}
+			m.entries[key] = entry
+			m.mu.Unlock()
+			return entry, false, nil
+		}
+		m.mu.Unlock()
+
+		select {
+		case <-existing.done:
+		case <-ctx.Done():
+			return nil, false, ctx.Err()
+		}
+		if existing.err == nil {
+			return existing, true, nil
+		}
+		// 失败的查询不备忘，由当前调用方重新执行
+		m.mu.Lock()
+		if m.entries[key] == existing {
+			delete(m.entries, key)
+		}
+		m.mu.Unlock()
+	}
+}
+
+// complete 填充条目结果并唤醒等待者，失败时移除条目
+func (m *queryMemo) complete(key string, entry *queryMemoEntry, err error) {
+	entry.err = err
+	if err != nil {
+		m.mu.Lock()
+		if m.entries[key] == entry {
+			delete(m.entries, key)
+		}
+		m.mu.Unlock()
+	}
+	close(entry.done)
+}
+
+// cloneMemoRecords 复制备忘的记录，避免调用方修改相互影响
+func cloneMemoRecords(records []*Record) []*Record {
+	if records == nil {
+		return nil
+	}
+	result := make([]*Record, len(records))
+	for i, r := range records {
+		result[i] = r.Clone()
+	}
+	return result
+}
+
+// cloneMemoMaps 复制备忘的 map 结果
+func cloneMemoMaps(maps []map[string]interface{}) []map[string]interface{} {
+	if maps == nil {
+		return nil
+	}
+	result := make([]map[string]interface{}, len(maps))
+	for i, m := range maps {
+		c := make(map[string]interface{}, len(m))
+		for k, v := range m {
+			c[k] = cloneValue(v)
+		}
+		result[i] = c
+	}
+	return result
+}
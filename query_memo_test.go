@@ -0,0 +1,76 @@
+package eorm
+
+import (
+	"context"
+	"testing"
+)
+
+func TestQueryMemoReusesResultsUntilWrite(t *testing.T) {
+	db := openTestDB(t, "CREATE TABLE qm_items (id INTEGER PRIMARY KEY, name TEXT)")
+	mustExec(t, db, "INSERT INTO qm_items (id, name) VALUES (1, 'v1')")
+	ctx := WithQueryMemo(context.Background())
+	if WithQueryMemo(ctx) != ctx {
+		t.Fatal("WithQueryMemo on a memo context must return it unchanged")
+	}
+	memoDB := Use(db.dbMgr.name).WithContext(ctx)
+	const querySQL = "SELECT name FROM qm_items WHERE id = ?"
+	name := func(run func() (*Record, error)) string {
+		t.Helper()
+		record, err := run()
+		if err != nil || record == nil {
+			t.Fatalf("query: %v, %v", record, err)
+		}
+		return record.GetString("name")
+	}
+	memoQuery := func() (*Record, error) { return memoDB.QueryFirst(querySQL, 1) }
+
+	first, err := memoQuery()
+	if err != nil || first.GetString("name") != "v1" {
+		t.Fatalf("first query = %v, %v; want v1", first, err)
+	}
+	first.Set("name", "mutated")
+	rawExec(t, db, "UPDATE qm_items SET name = 'v2' WHERE id = 1")
+	if got := name(memoQuery); got != "v1" {
+		t.Fatalf("memoized query = %s, want the unmodified memoized v1", got)
+	}
+	maps, err := memoDB.QueryMap(querySQL, 1)
+	if err != nil || len(maps) != 1 || maps[0]["name"] != "v2" {
+		t.Fatalf("QueryMap = %v, %v; want v2 memoized separately from records", maps, err)
+	}
+	if got := name(func() (*Record, error) { return db.QueryFirst(querySQL, 1) }); got != "v2" {
+		t.Fatalf("query without memo = %s, want v2", got)
+	}
+
+	// 事务内的查询不使用备忘录
+	if err := memoDB.Transaction(func(tx *Tx) error {
+		if got := name(func() (*Record, error) { return tx.QueryFirst(querySQL, 1) }); got != "v2" {
+			t.Errorf("query in a transaction = %s, want v2", got)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// 通过 eorm 写入后备忘的结果失效
+	if _, err := db.Update("qm_items", NewRecord().Set("name", "v3"), "id = ?", 1); err != nil {
+		t.Fatal(err)
+	}
+	if got := name(memoQuery); got != "v3" {
+		t.Fatalf("query after a write = %s, want v3", got)
+	}
+}
+
+func TestQueryMemoSkipsLockingReads(t *testing.T) {
+	for querySQL, want := range map[string]bool{
+		"SELECT * FROM t":                           true,
+		"  with x AS (SELECT 1) SELECT * FROM x":    true,
+		"SELECT * FROM t WHERE id = 1 FOR UPDATE":   false,
+		"SELECT * FROM t LOCK IN SHARE MODE":        false,
+		"SELECT * FROM t WITH (UPDLOCK) WHERE id=1": false,
+		"UPDATE t SET a = 1":                        false,
+	} {
+		if got := isMemoizableQuery(querySQL); got != want {
+			t.Errorf("isMemoizableQuery(%q) = %v, want %v", querySQL, got, want)
+		}
+	}
+}
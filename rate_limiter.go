@@ -0,0 +1,85 @@
+package eorm
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+)
+
+// Limiter 数据库操作限流器，在执行查询/更新前调用
+// db 为数据库名称，key 为通过 WithRateLimitKey 放入上下文的业务键（未设置时为空字符串）
+// Wait 阻塞直到允许执行，返回错误时放弃本次操作（如 ctx 已取消或超出配额）
+type Limiter interface {
+	Wait(ctx context.Context, db, key string) error
+}
+
+// LimiterFunc 函数形式的 Limiter
+type LimiterFunc func(ctx context.Context, db, key string) error
+
+// Wait 实现 Limiter 接口
+func (f LimiterFunc) Wait(ctx context.Context, db, key string) error {
+	return f(ctx, db, key)
+}
+
+// rateLimiterHolder 包装 Limiter 以便原子存取
+type rateLimiterHolder struct {
+	limiter Limiter
+}
+
+// rateLimiter 全局限流器
+var rateLimiter atomic.Value
+
+// rateLimitKey 上下文中保存限流键的键
+type rateLimitKey struct{}
+
+// SetRateLimiter 设置全局限流器，nil 取消限流
+// 可按数据库和业务键（用户、租户、任务类型等）分配配额，避免后台任务占满共享连接池影响在线请求
+// 示例:
+//
+//	limiters := map[string]*rate.Limiter{"batch": rate.NewLimiter(50, 10)}
+//	eorm.SetRateLimiter(eorm.LimiterFunc(func(ctx context.Context, db, key string) error {
+//	    if l, ok := limiters[key]; ok {
+//	        return l.Wait(ctx)
+//	    }
+//	    return nil
+//	}))
+//	eorm.WithContext(eorm.WithRateLimitKey(ctx, "batch")).Query("SELECT * FROM orders")
+func SetRateLimiter(l Limiter) {
+	rateLimiter.Store(rateLimiterHolder{limiter: l})
+}
+
+// GetRateLimiter 返回当前的全局限流器，未设置时返回 nil
+func GetRateLimiter() Limiter {
+	if h, ok := rateLimiter.Load().(rateLimiterHolder); ok {
+		return h.limiter
+	}
+	return nil
+}
+
+// WithRateLimitKey 返回携带限流键的上下文，配合 WithContext 使用
+func WithRateLimitKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(contextOrBackground(ctx), rateLimitKey{}, key)
+}
+
+// RateLimitKeyFromContext 返回上下文中的限流键，未设置时返回空字符串
+func RateLimitKeyFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	key, _ := ctx.Value(rateLimitKey{}).(string)
+	return key
+}
+
+// waitRateLimit 执行语句前咨询限流器
+func (mgr *dbManager) waitRateLimit(ctx context.Context) error {
+	limiter := GetRateLimiter()
+	if limiter == nil {
+		return nil
+	}
+	ctx = contextOrBackground(ctx)
+	key := RateLimitKeyFromContext(ctx)
+	if err := limiter.Wait(ctx, mgr.name, key); err != nil {
+		return fmt.Errorf("eorm: rate limited (db: %s, key: %s): %w", mgr.name, key, err)
+	}
+	return nil
+}
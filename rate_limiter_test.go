@@ -0,0 +1,75 @@
+package eorm
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestRateLimiterCoversRecordWrites(t *testing.T) {
+	db := openTestDB(t, "CREATE TABLE rl_items (id INTEGER PRIMARY KEY, name TEXT)")
+	var mu sync.Mutex
+	var calls int
+	var keys []string
+	errDenied := errors.New("denied")
+	deny := false
+	SetRateLimiter(LimiterFunc(func(ctx context.Context, dbName, key string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		keys = append(keys, key)
+		if deny {
+			return errDenied
+		}
+		return nil
+	}))
+	t.Cleanup(func() { SetRateLimiter(nil) })
+
+	limited := Use(db.dbMgr.name).WithContext(WithRateLimitKey(context.Background(), "batch"))
+	writes := []struct {
+		name string
+		run  func() error
+	}{
+		{"InsertRecord", func() error {
+			_, err := limited.InsertRecord("rl_items", NewRecord().Set("id", 1).Set("name", "a"))
+			return err
+		}},
+		{"BatchInsertRecord", func() error {
+			_, err := limited.BatchInsertRecord("rl_items", []*Record{NewRecord().Set("id", 2).Set("name", "b"), NewRecord().Set("id", 3).Set("name", "c")})
+			return err
+		}},
+		{"Update", func() error {
+			_, err := limited.Update("rl_items", NewRecord().Set("name", "x"), "id = ?", 1)
+			return err
+		}},
+		{"Delete", func() error {
+			_, err := limited.Delete("rl_items", "id = ?", 2)
+			return err
+		}},
+	}
+	for _, w := range writes {
+		mu.Lock()
+		calls, keys = 0, nil
+		mu.Unlock()
+		if err := w.run(); err != nil {
+			t.Fatalf("%s: %v", w.name, err)
+		}
+		mu.Lock()
+		if calls == 0 || keys[len(keys)-1] != "batch" {
+			t.Errorf("%s: limiter calls = %d, keys = %v; want the write statement limited with key batch", w.name, calls, keys)
+		}
+		mu.Unlock()
+	}
+
+	mu.Lock()
+	deny = true
+	mu.Unlock()
+	if _, err := limited.Delete("rl_items", "id = ?", 3); !errors.Is(err, errDenied) {
+		t.Fatalf("Delete with a denying limiter: err = %v, want %v", err, errDenied)
+	}
+	SetRateLimiter(nil)
+	if n := countRows(t, db, "rl_items", "id = 3"); n != 1 {
+		t.Fatalf("row 3 count = %d, want 1: a denied delete must not run", n)
+	}
+}
@@ -43,6 +43,7 @@ type Record struct {
 	columns     map[string]interface{} // 原始键名 -> 值
 	lowerKeyMap map[string]string      // 小写键名 -> 原始键名（用于快速查找）
 	keys        []string               // 保存字段插入顺序
+	jsonKeys    *JsonKeyOptions        // 序列化为 JSON 时的键名映射（nil 表示原样输出）
 	noCopy      noCopy                 // 辅助工具，防止 Record 被按值拷贝
 }
 
@@ -487,6 +488,7 @@ func (r *Record) Clear() {
 		delete(r.lowerKeyMap, k)
 	}
 	r.keys = r.keys[:0]
+	r.jsonKeys = nil
 }
 
 // ToMap converts the Record to a map (returns a copy)
@@ -592,6 +594,11 @@ func (r *Record) marshalToBuffer(buf *bytes.Buffer, visited map[uintptr]bool, de
 
 	buf.WriteByte('{')
 
+	var keyNames map[string]string
+	if r.jsonKeys != nil {
+		keyNames = r.jsonKeyNames()
+	}
+
 	for i, k := range r.keys {
 		if v, ok := r.columns[k]; ok {
 			if i > 0 {
@@ -600,7 +607,11 @@ func (r *Record) marshalToBuffer(buf *bytes.Buffer, visited map[uintptr]bool, de
 
 			// 写入键：使用自定义的高性能写入，避免 json.Marshal 的分配
 			buf.WriteByte('"')
-			writeJSONString(buf, k)
+			if keyNames != nil {
+				writeJSONString(buf, keyNames[k])
+			} else {
+				writeJSONString(buf, k)
+			}
 			buf.WriteString("\":")
 
 			// 写入值
@@ -629,6 +640,8 @@ func (r *Record) marshalToBuffer(buf *bytes.Buffer, visited map[uintptr]bool, de
 				}
 			case nil:
 				buf.WriteString("null")
+			case []byte:
+				writeBinaryJSON(buf, val)
 			case []interface{}:
 				buf.WriteByte('[')
 				for i, item := range val {
@@ -648,6 +661,8 @@ func (r *Record) marshalToBuffer(buf *bytes.Buffer, visited map[uintptr]bool, de
 						if err := (&itemVal).marshalToBuffer(buf, visited, depth+1); err != nil {
 							return err
 						}
+					case []byte:
+						writeBinaryJSON(buf, itemVal)
 					default:
 						itemJSON, err := json.Marshal(item)
 						if err != nil {
@@ -769,6 +784,7 @@ func (r *Record) Clone() *Record {
 	// 复制 keys 顺序
 	newRecord.keys = make([]string, len(r.keys))
 	copy(newRecord.keys, r.keys)
+	newRecord.jsonKeys = r.jsonKeys
 
 	return newRecord
 }
@@ -1421,6 +1437,7 @@ func (r *Record) deepCloneWithTracker(tracker *recursionTracker) *Record {
 	// 4. 拷贝基本属性
 	newRecord.keys = make([]string, len(r.keys))
 	copy(newRecord.keys, r.keys)
+	newRecord.jsonKeys = r.jsonKeys
 
 	newRecord.lowerKeyMap = make(map[string]string, len(r.lowerKeyMap))
 	for k, v := range r.lowerKeyMap {
@@ -1933,16 +1950,14 @@ func wrapAsSlice(value interface{}) []interface{} {
 		return nil
 	}
 
-	// 如果是字符串，尝试分割
-	if str, ok := value.(string); ok {
+	// 如果是字符串，PostgreSQL 数组文本（{a,b}）按数组解析，否则尝试分割
+	if str, ok := pgTextValue(value); ok {
+		if arr, ok := parsePgArray(str); ok {
+			return arr
+		}
 		return splitString(str)
 	}
 
-	// 如果是 []byte，转换为字符串再处理
-	if bytes, ok := value.([]byte); ok {
-		return splitString(string(bytes))
-	}
-
 	// 其他类型包装为单元素切片
 	return []interface{}{value}
 }
@@ -0,0 +1,297 @@
+package eorm
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+	"unicode/utf8"
+)
+
+// BinaryJsonEncoding ToJson 中二进制（[]byte）列的输出格式
+type BinaryJsonEncoding int32
+
+const (
+	BinaryJsonBase64      BinaryJsonEncoding = iota // 标准 base64 字符串（默认，与 encoding/json 一致）
+	BinaryJsonHex                                   // 十六进制字符串
+	BinaryJsonPlaceholder                           // 仅输出 "<binary N bytes>"，适合日志，不可还原
+)
+
+// binaryJsonEncoding 当前的二进制 JSON 输出格式
+var binaryJsonEncoding int32
+
+// SetBinaryJsonEncoding 设置 ToJson/MarshalJSON 中 []byte 列的输出格式
+// 示例: eorm.SetBinaryJsonEncoding(eorm.BinaryJsonHex)
+func SetBinaryJsonEncoding(enc BinaryJsonEncoding) {
+	atomic.StoreInt32(&binaryJsonEncoding, int32(enc))
+}
+
+// GetBinaryJsonEncoding 返回当前 []byte 列的 JSON 输出格式
+func GetBinaryJsonEncoding() BinaryJsonEncoding {
+	return BinaryJsonEncoding(atomic.LoadInt32(&binaryJsonEncoding))
+}
+
+// writeBinaryJSON 按当前格式写入二进制值
+func writeBinaryJSON(buf *bytes.Buffer, b []byte) {
+	if b == nil {
+		buf.WriteString("null")
+		return
+	}
+	buf.WriteByte('"')
+	switch GetBinaryJsonEncoding() {
+	case BinaryJsonHex:
+		buf.WriteString(hex.EncodeToString(b))
+	case BinaryJsonPlaceholder:
+		fmt.Fprintf(buf, "<binary %d bytes>", len(b))
+	default:
+		buf.WriteString(base64.StdEncoding.EncodeToString(b))
+	}
+	buf.WriteByte('"')
+}
+
+// GetBlob 获取二进制列的值：[]byte 原样返回，字符串（如 FromJson 解析的 ToJson 输出）按当前 BinaryJsonEncoding 解码
+// 与 GetBytes 不同，字符串不会按原文转为字节，可用于 ToJson → FromJson 往返后的二进制数据
+func (r *Record) GetBlob(column string) ([]byte, error) {
+	switch v := r.getValue(column).(type) {
+	case nil:
+		return nil, nil
+	case []byte:
+		return v, nil
+	case string:
+		var (
+			b   []byte
+			err error
+		)
+		switch GetBinaryJsonEncoding() {
+		case BinaryJsonHex:
+			b, err = hex.DecodeString(v)
+		case BinaryJsonPlaceholder:
+			err = fmt.Errorf("binary json placeholder cannot be decoded")
+		default:
+			b, err = base64.StdEncoding.DecodeString(v)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("eorm: column '%s' is not encoded binary: %v", column, err)
+		}
+		return b, nil
+	default:
+		return nil, fmt.Errorf("eorm: column '%s' of type %T is not binary", column, v)
+	}
+}
+
+// BlobReader 以 io.Reader 读取二进制列，列不存在或为 NULL 时返回空 Reader
+func (r *Record) BlobReader(column string) (io.Reader, error) {
+	b, err := r.GetBlob(column)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(b), nil
+}
+
+// blobChunkSize 流式写入的分块大小，Oracle PL/SQL 的 RAW/VARCHAR2 绑定变量不能超过 32767 字节
+const (
+	blobChunkSize       = 1 << 20
+	oracleBlobChunkSize = 32000
+)
+
+// WriteBlob 从 reader 流式写入大字段（BLOB/CLOB/BYTEA/VARBINARY(MAX)/TEXT），返回写入的字节数
+// 先将匹配行的列置为空，再分块追加，整个过程在一个事务中完成，失败时回滚；大文件无需整体读入内存
+//   - Oracle 使用 DBMS_LOB.WRITEAPPEND 按 32000 字节分块写入 BLOB/CLOB，where 必须只匹配一行
+//   - SQL Server 使用 column.WRITE 追加，MySQL 使用 CONCAT，PostgreSQL 使用 ||
+//   - SQLite 为进程内数据库，整体读取后一次写入
+//
+// 文本列按 UTF-8 字符边界分块
+// 示例:
+//
+//	f, _ := os.Open("report.pdf")
+//	defer f.Close()
+//	n, err := eorm.WriteBlob("documents", "content", f, "id = ?", 42)
+func WriteBlob(table, column string, reader io.Reader, where string, whereArgs ...interface{}) (int64, error) {
+	db, err := defaultDB()
+	if err != nil {
+		return 0, err
+	}
+	return db.WriteBlob(table, column, reader, where, whereArgs...)
+}
+
+// WriteBlob 从 reader 流式写入大字段，见 WriteBlob
+func (db *DB) WriteBlob(table, column string, reader io.Reader, where string, whereArgs ...interface{}) (int64, error) {
+	if db.lastErr != nil {
+		return 0, db.lastErr
+	}
+	var written int64
+	err := db.Transaction(func(tx *Tx) error {
+		var err error
+		written, err = tx.WriteBlob(table, column, reader, where, whereArgs...)
+		return err
+	})
+	return written, err
+}
+
+// WriteBlob 在事务中流式写入大字段，失败时由调用方回滚
+func (tx *Tx) WriteBlob(table, column string, reader io.Reader, where string, whereArgs ...interface{}) (int64, error) {
+	if err := validateIdentifier(table); err != nil {
+		return 0, err
+	}
+	if err := validateIdentifier(column); err != nil {
+		return 0, err
+	}
+	if strings.TrimSpace(where) == "" {
+		return 0, fmt.Errorf("eorm: WriteBlob requires a where clause")
+	}
+	if reader == nil {
+		return 0, fmt.Errorf("eorm: WriteBlob reader is nil")
+	}
+
+	mgr := tx.dbMgr
+	driver := mgr.config.Load().Driver
+	isText, lobType := blobColumnKind(mgr, table, column)
+
+	if driver == SQLite3 {
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			return 0, fmt.Errorf("eorm: failed to read blob: %v", err)
+		}
+		var value interface{} = data
+		if isText {
+			value = string(data)
+		}
+		args := append([]interface{}{value}, whereArgs...)
+		if err := execBlobUpdate(tx, fmt.Sprintf("UPDATE %s SET %s = ? WHERE %s", table, column, where), args); err != nil {
+			return 0, err
+		}
+		return int64(len(data)), nil
+	}
+
+	// 先置空，确保后续追加从空值开始
+	var emptySQL string
+	var emptyArgs []interface{}
+	if driver == Oracle {
+		emptySQL = fmt.Sprintf("UPDATE %s SET %s = EMPTY_%s() WHERE %s", table, column, lobType, where)
+		emptyArgs = whereArgs
+	} else {
+		var empty interface{} = []byte{}
+		if isText {
+			empty = ""
+		}
+		emptySQL = fmt.Sprintf("UPDATE %s SET %s = ? WHERE %s", table, column, where)
+		emptyArgs = append([]interface{}{empty}, whereArgs...)
+	}
+	if err := execBlobUpdate(tx, emptySQL, emptyArgs); err != nil {
+		return 0, err
+	}
+
+	var appendSQL string
+	chunkSize := blobChunkSize
+	switch driver {
+	case Oracle:
+		appendSQL = fmt.Sprintf("DECLARE l_lob %s; BEGIN SELECT %s INTO l_lob FROM %s WHERE %s FOR UPDATE; DBMS_LOB.WRITEAPPEND(l_lob, ?, ?); END;",
+			lobType, column, table, where)
+		chunkSize = oracleBlobChunkSize
+	case SQLServer:
+		appendSQL = fmt.Sprintf("UPDATE %s SET %s.WRITE(?, NULL, NULL) WHERE %s", table, column, where)
+	case MySQL:
+		appendSQL = fmt.Sprintf("UPDATE %s SET %s = CONCAT(%s, ?) WHERE %s", table, column, column, where)
+	default:
+		appendSQL = fmt.Sprintf("UPDATE %s SET %s = %s || ? WHERE %s", table, column, column, where)
+	}
+
+	var written int64
+	buf := make([]byte, chunkSize)
+	carry := 0
+	for {
+		n, readErr := io.ReadFull(reader, buf[carry:])
+		n += carry
+		carry = 0
+		if readErr != nil && readErr != io.EOF && readErr != io.ErrUnexpectedEOF {
+			return written, fmt.Errorf("eorm: failed to read blob: %v", readErr)
+		}
+		last := readErr != nil
+		chunk := buf[:n]
+		if isText && !last {
+			// 不在多字节字符中间切分，剩余字节留到下一块
+			end := lastRuneBoundary(chunk)
+			carry = n - end
+			chunk = chunk[:end]
+		}
+		if len(chunk) > 0 {
+			var value interface{} = append([]byte(nil), chunk...)
+			if isText {
+				value = string(chunk)
+			}
+			var args []interface{}
+			if driver == Oracle {
+				amount := len(chunk)
+				if isText {
+					amount = utf8.RuneCount(chunk)
+				}
+				args = append(append(args, whereArgs...), amount, value)
+			} else {
+				args = append(append(args, value), whereArgs...)
+			}
+			if err := execBlobUpdate(tx, appendSQL, args); err != nil {
+				return written, err
+			}
+			written += int64(len(chunk))
+		}
+		if carry > 0 {
+			copy(buf, buf[n-carry:n])
+		}
+		if last {
+			return written, nil
+		}
+	}
+}
+
+// execBlobUpdate 执行写入语句，没有匹配行时返回错误
+func execBlobUpdate(tx *Tx, querySQL string, args []interface{}) error {
+	result, err := tx.Exec(querySQL, args...)
+	if err != nil {
+		return err
+	}
+	// PL/SQL 块不返回受影响行数
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 && !strings.HasPrefix(querySQL, "DECLARE") {
+		return fmt.Errorf("eorm: WriteBlob matched no rows")
+	}
+	return nil
+}
+
+// blobColumnKind 根据列类型判断是否为文本列，并返回 Oracle 的 LOB 类型（BLOB/CLOB/NCLOB）
+// 无法获取列信息时按二进制处理
+func blobColumnKind(mgr *dbManager, table, column string) (isText bool, lobType string) {
+	lobType = "BLOB"
+	columns, err := mgr.getTableColumns(table)
+	if err != nil {
+		return false, lobType
+	}
+	for _, col := range columns {
+		if !strings.EqualFold(col.Name, column) {
+			continue
+		}
+		dbType := strings.ToUpper(col.Type)
+		if strings.Contains(dbType, "NCLOB") {
+			return true, "NCLOB"
+		}
+		if strings.Contains(dbType, "CLOB") {
+			return true, "CLOB"
+		}
+		return !isBinaryType(dbType), lobType
+	}
+	return false, lobType
+}
+
+// lastRuneBoundary 返回 b 中最后一个完整 UTF-8 字符之后的位置
+func lastRuneBoundary(b []byte) int {
+	for i := len(b) - 1; i >= 0 && i >= len(b)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(b[i]) {
+			if utf8.FullRune(b[i:]) {
+				return len(b)
+			}
+			return i
+		}
+	}
+	return len(b)
+}
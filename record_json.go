@@ -0,0 +1,194 @@
+package eorm
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// UnknownKeyPolicy 严格解析 JSON 时对未知字段的处理方式
+type UnknownKeyPolicy int
+
+const (
+	UnknownKeyKeep   UnknownKeyPolicy = iota // 保留未知字段（默认）
+	UnknownKeyIgnore                         // 丢弃未知字段
+	UnknownKeyError                          // 遇到未知字段返回错误
+)
+
+// JsonDecodeOptions FromJsonStrict 的解析选项
+type JsonDecodeOptions struct {
+	KnownKeys   []string         // 已知字段（大小写不敏感），为空时以解析前 Record 中已有的列为准
+	UnknownKeys UnknownKeyPolicy // 未知字段的处理方式
+}
+
+// FromJsonStrict 严格模式解析 JSON 为新的 Record，见 Record.FromJsonStrict
+func FromJsonStrict(jsonStr string, opts *JsonDecodeOptions) (*Record, error) {
+	r := NewRecord()
+	if err := r.FromJsonStrict(jsonStr, opts); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// FromJsonStrict 严格模式解析 JSON 到 Record，与 FromJson 不同：
+//   - 数字按原文解析，整数转为 int64（超出 int64 时为 uint64，仍超出时保留为字符串），小数转为 float64，大 ID 不会丢失精度
+//   - 解析失败返回错误而不是静默忽略
+//   - 可通过 opts.UnknownKeys 控制未知字段保留、丢弃或报错
+//
+// 示例:
+//
+//	r, err := eorm.FromJsonStrict(`{"id": 9007199254740993, "name": "alice"}`, nil)
+//	r.GetInt64("id") // 9007199254740993
+//
+//	// 以表结构为准，拒绝多余字段
+//	tpl := eorm.NewRecord().Set("id", nil).Set("name", nil)
+//	err = tpl.FromJsonStrict(body, &eorm.JsonDecodeOptions{UnknownKeys: eorm.UnknownKeyError})
+func (r *Record) FromJsonStrict(jsonStr string, opts *JsonDecodeOptions) error {
+	if opts == nil {
+		opts = &JsonDecodeOptions{}
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(jsonStr))
+	decoder.UseNumber()
+	var data map[string]interface{}
+	if err := decoder.Decode(&data); err != nil {
+		return fmt.Errorf("eorm: failed to parse json: %v", err)
+	}
+	if decoder.More() {
+		return fmt.Errorf("eorm: failed to parse json: unexpected data after top-level object")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	known := make(map[string]bool)
+	if len(opts.KnownKeys) > 0 {
+		for _, k := range opts.KnownKeys {
+			known[strings.ToLower(k)] = true
+		}
+	} else {
+		for lower := range r.lowerKeyMap {
+			known[lower] = true
+		}
+	}
+
+	// 保持 JSON 中的字段顺序
+	keys, err := jsonObjectKeys(jsonStr)
+	if err != nil {
+		return fmt.Errorf("eorm: failed to parse json: %v", err)
+	}
+	columns := make(map[string]interface{}, len(data))
+	lowerKeyMap := make(map[string]string, len(data))
+	ordered := make([]string, 0, len(data))
+	for _, k := range keys {
+		v, ok := data[k]
+		if !ok {
+			continue
+		}
+		lower := strings.ToLower(k)
+		if len(known) > 0 && !known[lower] {
+			switch opts.UnknownKeys {
+			case UnknownKeyIgnore:
+				continue
+			case UnknownKeyError:
+				return fmt.Errorf("eorm: unknown json key '%s'", k)
+			}
+		}
+		if old, exists := lowerKeyMap[lower]; exists {
+			delete(columns, old)
+			for i, key := range ordered {
+				if key == old {
+					ordered = append(ordered[:i], ordered[i+1:]...)
+					break
+				}
+			}
+		}
+		columns[k] = convertStrictJsonValue(v)
+		lowerKeyMap[lower] = k
+		ordered = append(ordered, k)
+		// 重复的字段名只取一次（值为最后一次出现的值）
+		delete(data, k)
+	}
+
+	r.columns = columns
+	r.lowerKeyMap = lowerKeyMap
+	r.keys = ordered
+	return nil
+}
+
+// jsonObjectKeys 按出现顺序返回顶层 JSON 对象的字段名
+func jsonObjectKeys(jsonStr string) ([]string, error) {
+	decoder := json.NewDecoder(strings.NewReader(jsonStr))
+	decoder.UseNumber()
+	if _, err := decoder.Token(); err != nil {
+		return nil, err
+	}
+	var keys []string
+	for decoder.More() {
+		tok, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, _ := tok.(string)
+		keys = append(keys, key)
+		var skip json.RawMessage
+		if err := decoder.Decode(&skip); err != nil {
+			return nil, err
+		}
+	}
+	return keys, nil
+}
+
+// convertStrictJsonValue 转换 UseNumber 解析出的值，嵌套对象转为 Record，对象数组转为 []*Record
+func convertStrictJsonValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		return convertJsonNumber(v)
+	case map[string]interface{}:
+		record := NewRecord()
+		for k, item := range v {
+			record.Set(k, convertStrictJsonValue(item))
+		}
+		return record
+	case []interface{}:
+		if len(v) > 0 {
+			if _, ok := v[0].(map[string]interface{}); ok {
+				records := make([]*Record, len(v))
+				for i, item := range v {
+					if rec, ok := convertStrictJsonValue(item).(*Record); ok {
+						records[i] = rec
+					}
+				}
+				return records
+			}
+		}
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			result[i] = convertStrictJsonValue(item)
+		}
+		return result
+	default:
+		return value
+	}
+}
+
+// convertJsonNumber 整数转为 int64/uint64，小数转为 float64，无法精确表示的整数保留为字符串
+func convertJsonNumber(n json.Number) interface{} {
+	s := n.String()
+	if !strings.ContainsAny(s, ".eE") {
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return i
+		}
+		if u, err := strconv.ParseUint(s, 10, 64); err == nil {
+			return u
+		}
+		return s
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsInf(f, 0) {
+		return s
+	}
+	return f
+}
@@ -0,0 +1,192 @@
+package eorm
+
+import (
+	"sort"
+	"strings"
+)
+
+// JsonKeyOptions ToJson/MarshalJSON 输出时的键名映射，只影响序列化，不改变 Record 中的列名
+// 映射后的键与已输出的键重复时保留原列名，避免丢失数据
+type JsonKeyOptions struct {
+	KeyMap        map[string]string // 列名（大小写不敏感）-> JSON 键名，优先于 StripPrefixes
+	StripPrefixes []string          // 去掉的列名前缀（大小写不敏感），如 "u_"、"users."，按最长前缀匹配
+}
+
+// SetJsonKeys 设置 Record 序列化为 JSON 时的键名映射，opts 为 nil 时恢复原样输出
+// 示例:
+//
+//	record, _ := eorm.QueryFirst("SELECT u.username AS u_username, o.total AS o_total FROM users u JOIN orders o ON ...")
+//	record.SetJsonKeys(&eorm.JsonKeyOptions{StripPrefixes: []string{"u_", "o_"}})
+//	record.ToJson() // {"username":"tom","total":100}
+func (r *Record) SetJsonKeys(opts *JsonKeyOptions) *Record {
+	if r == nil {
+		return r
+	}
+	var resolved *JsonKeyOptions
+	if opts != nil && (len(opts.KeyMap) > 0 || len(opts.StripPrefixes) > 0) {
+		resolved = opts.normalize()
+	}
+	r.mu.Lock()
+	r.jsonKeys = resolved
+	r.mu.Unlock()
+	return r
+}
+
+// JsonKeyMap 为查询结果设置 JSON 键名映射（列名大小写不敏感），只影响 ToJson/MarshalJSON 的输出
+// 示例:
+//
+//	eorm.Table("users u").Select("u.username AS u_username, o.total AS o_total").
+//	    Join("orders o", "o.user_id = u.id").
+//	    JsonKeyMap(map[string]string{"u_username": "username", "o_total": "orderTotal"}).
+//	    Find()
+func (qb *QueryBuilder) JsonKeyMap(keyMap map[string]string) *QueryBuilder {
+	if qb.jsonKeys == nil {
+		qb.jsonKeys = &JsonKeyOptions{}
+	}
+	if qb.jsonKeys.KeyMap == nil {
+		qb.jsonKeys.KeyMap = make(map[string]string, len(keyMap))
+	}
+	for col, key := range keyMap {
+		qb.jsonKeys.KeyMap[col] = key
+	}
+	return qb
+}
+
+// JsonStripPrefix 序列化查询结果时去掉列名前缀；不传参数时根据查询的表名和 JOIN 别名自动生成
+// "<表名或别名>_" 和 "<表名或别名>." 前缀
+// 示例:
+//
+//	eorm.Table("users u").Select("u.username AS u_username, o.total AS o_total").
+//	    Join("orders o", "o.user_id = u.id").
+//	    JsonStripPrefix().
+//	    Find() // ToJson: {"username":"tom","total":100}
+func (qb *QueryBuilder) JsonStripPrefix(prefixes ...string) *QueryBuilder {
+	if qb.jsonKeys == nil {
+		qb.jsonKeys = &JsonKeyOptions{}
+	}
+	if len(prefixes) == 0 {
+		qb.jsonAutoPrefix = true
+		return qb
+	}
+	qb.jsonKeys.StripPrefixes = append(qb.jsonKeys.StripPrefixes, prefixes...)
+	return qb
+}
+
+// resolveJsonKeys 合并手动配置和自动生成的前缀
+func (qb *QueryBuilder) resolveJsonKeys() *JsonKeyOptions {
+	opts := &JsonKeyOptions{
+		KeyMap:        qb.jsonKeys.KeyMap,
+		StripPrefixes: append([]string(nil), qb.jsonKeys.StripPrefixes...),
+	}
+	if qb.jsonAutoPrefix {
+		sources := []string{qb.table}
+		for _, join := range qb.joins {
+			sources = append(sources, join.table)
+		}
+		for _, source := range sources {
+			for _, name := range tableNameAndAlias(source) {
+				opts.StripPrefixes = append(opts.StripPrefixes, name+"_", name+".")
+			}
+		}
+	}
+	return opts.normalize()
+}
+
+// withJsonKeys 为查询结果附加键名映射；使用查询缓存时结果可能被其他查询共享，先复制再附加
+func (qb *QueryBuilder) withJsonKeys(records []*Record) []*Record {
+	if qb.jsonKeys == nil || len(records) == 0 {
+		return records
+	}
+	opts := qb.resolveJsonKeys()
+	if qb.cacheRepositoryName != "" {
+		cloned := make([]*Record, len(records))
+		for i, record := range records {
+			cloned[i] = record.Clone()
+		}
+		records = cloned
+	}
+	for _, record := range records {
+		if record != nil {
+			record.mu.Lock()
+			record.jsonKeys = opts
+			record.mu.Unlock()
+		}
+	}
+	return records
+}
+
+// normalize 把映射的列名转为小写，前缀按长度降序排列
+func (o *JsonKeyOptions) normalize() *JsonKeyOptions {
+	normalized := &JsonKeyOptions{}
+	if len(o.KeyMap) > 0 {
+		normalized.KeyMap = make(map[string]string, len(o.KeyMap))
+		for col, key := range o.KeyMap {
+			normalized.KeyMap[strings.ToLower(col)] = key
+		}
+	}
+	seen := make(map[string]bool, len(o.StripPrefixes))
+	for _, prefix := range o.StripPrefixes {
+		prefix = strings.ToLower(prefix)
+		if prefix == "" || seen[prefix] {
+			continue
+		}
+		seen[prefix] = true
+		normalized.StripPrefixes = append(normalized.StripPrefixes, prefix)
+	}
+	sort.SliceStable(normalized.StripPrefixes, func(i, j int) bool {
+		return len(normalized.StripPrefixes[i]) > len(normalized.StripPrefixes[j])
+	})
+	return normalized
+}
+
+// jsonKeyNames 按列顺序计算每列输出的键名，映射后重复的键保留原列名（调用方持有 r.mu）
+func (r *Record) jsonKeyNames() map[string]string {
+	names := make(map[string]string, len(r.keys))
+	used := make(map[string]bool, len(r.keys))
+	for _, col := range r.keys {
+		key := r.jsonKeys.mapKey(col)
+		if used[key] || (key != col && r.hasOtherColumn(key, col)) {
+			key = col
+		}
+		used[key] = true
+		names[col] = key
+	}
+	return names
+}
+
+// hasOtherColumn 是否存在与 key 同名（大小写不敏感）且不是 col 的列（调用方持有 r.mu）
+func (r *Record) hasOtherColumn(key, col string) bool {
+	other, ok := r.lowerKeyMap[strings.ToLower(key)]
+	return ok && other != col
+}
+
+// mapKey 返回单个列映射后的键名
+func (o *JsonKeyOptions) mapKey(col string) string {
+	lower := strings.ToLower(col)
+	if key, ok := o.KeyMap[lower]; ok {
+		return key
+	}
+	for _, prefix := range o.StripPrefixes {
+		if len(lower) > len(prefix) && strings.HasPrefix(lower, prefix) {
+			return col[len(prefix):]
+		}
+	}
+	return col
+}
+
+// tableNameAndAlias 从 "users"、"users u"、"users AS u"、"db.users" 中提取表名和别名
+func tableNameAndAlias(source string) []string {
+	fields := strings.Fields(source)
+	if len(fields) == 0 {
+		return nil
+	}
+	name := normalizeDDLTable(fields[0])
+	names := []string{name}
+	if len(fields) > 1 {
+		alias := normalizeDDLTable(fields[len(fields)-1])
+		if alias != "" && alias != name && !strings.EqualFold(alias, "as") {
+			names = append(names, alias)
+		}
+	}
+	return names
+}
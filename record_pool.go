@@ -0,0 +1,81 @@
+package eorm
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+// recordPooling 查询扫描是否从对象池获取 Record
+var recordPooling int32
+
+// EnableRecordPooling 开启或关闭查询结果 Record 的对象池复用（默认关闭）
+// 开启后 Query/Find 等扫描结果时从对象池获取 Record，配合 ReleaseRecords 归还，可降低读多写少场景下的 GC 压力
+// 只应释放调用方独占的结果：来自缓存（Cache/LocalCache）、工作单元（UnitOfWork）的记录或仍被其他地方引用的记录不能释放
+// 示例:
+//
+//	eorm.EnableRecordPooling(true)
+//	records, _ := eorm.Query("SELECT id, name FROM users WHERE status = ?", 1)
+//	defer eorm.ReleaseRecords(records)
+func EnableRecordPooling(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&recordPooling, v)
+}
+
+// IsRecordPoolingEnabled 返回是否开启了 Record 对象池复用
+func IsRecordPoolingEnabled() bool {
+	return atomic.LoadInt32(&recordPooling) == 1
+}
+
+// ReleaseRecords 将查询结果中的 Record 归还到对象池，并把切片中的元素置为 nil
+// 归还后不能再使用这些 Record，未开启 EnableRecordPooling 时同样可以调用
+func ReleaseRecords(records []*Record) {
+	for i, r := range records {
+		if r != nil {
+			r.Release()
+			records[i] = nil
+		}
+	}
+}
+
+// newScanRecord 为扫描一行结果创建 Record，开启对象池时从池中获取
+func newScanRecord(numCols int) *Record {
+	if IsRecordPoolingEnabled() {
+		return NewRecordFromPool()
+	}
+	return &Record{
+		columns:     make(map[string]interface{}, numCols),
+		lowerKeyMap: make(map[string]string, numCols),
+		keys:        make([]string, 0, numCols),
+	}
+}
+
+// scanColumns 每条语句只计算一次的列元数据：列名、小写列名、数据库类型
+// 同一语句的所有行共享这些字符串，避免逐行逐列重复 ToLower/ToUpper 分配
+type scanColumns struct {
+	names   []string
+	lowers  []string
+	dbTypes []string
+}
+
+// newScanColumns 根据结果集列信息构建列元数据
+func newScanColumns(columns []string, dbTypes []string) *scanColumns {
+	sc := &scanColumns{
+		names:   columns,
+		lowers:  make([]string, len(columns)),
+		dbTypes: dbTypes,
+	}
+	for i, col := range columns {
+		sc.lowers[i] = strings.ToLower(col)
+	}
+	return sc
+}
+
+// setDirectLower 使用预先计算的小写键设置值（调用方保证 Record 未被共享）
+func (r *Record) setDirectLower(column, lowerKey string, value interface{}) {
+	r.columns[column] = value
+	r.lowerKeyMap[lowerKey] = column
+	r.keys = append(r.keys, column)
+}
@@ -2,7 +2,6 @@ package redis
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"time"
 
@@ -13,6 +12,7 @@ import (
 type redisCache struct {
 	client *redis.Client
 	ctx    context.Context
+	codec  Codec // 缓存值编解码器，默认 JSONCodec
 }
 
 // NewRedisCache 创建一个新的 Redis 缓存提供者
@@ -75,6 +75,7 @@ func NewRedisCacheWithOptions(opts *redis.Options) (*redisCache, error) {
 	rc := &redisCache{
 		client: client,
 		ctx:    context.Background(),
+		codec:  JSONCodec,
 	}
 
 	// 测试连接
@@ -85,8 +86,21 @@ func NewRedisCacheWithOptions(opts *redis.Options) (*redisCache, error) {
 	return rc, nil
 }
 
+// SetCodec 设置缓存值编解码器（默认 JSONCodec），返回自身便于链式调用
+// 切换编解码器后，旧编解码器写入的缓存值仍可正常读取
+// 示例: cache.SetCodec(redis.MsgpackCodec)
+func (r *redisCache) SetCodec(codec Codec) *redisCache {
+	if codec == nil {
+		codec = JSONCodec
+	}
+	RegisterCodec(codec)
+	r.codec = codec
+	return r
+}
+
 // CacheGet 从 Redis 获取缓存值
-// 优化：直接返回 JSON 字节数组，避免字符串转换开销
+// 优化：JSON 编码的值直接返回字节数组，避免字符串转换开销
+// 其他编解码器写入的值返回延迟解码对象，由 eorm 直接解码到目标类型
 func (r *redisCache) CacheGet(cacheRepositoryName, key string) (interface{}, bool) {
 	fullKey := fmt.Sprintf("eorm:%s:%s", cacheRepositoryName, key)
 
@@ -98,29 +112,76 @@ func (r *redisCache) CacheGet(cacheRepositoryName, key string) (interface{}, boo
 		return nil, false
 	}
 
-	// 直接返回字节数组，让 convertCacheValue 处理反序列化
+	// 直接返回字节数组（或延迟解码对象），让 convertCacheValue 处理反序列化
 	// 这样可以避免双重序列化：string → []byte → object
-	return val, true
+	return decodeValue(val), true
 }
 
 func (r *redisCache) CacheSet(cacheRepositoryName, key string, value interface{}, ttl time.Duration) {
 	fullKey := fmt.Sprintf("eorm:%s:%s", cacheRepositoryName, key)
 
-	var data interface{} = value
+	data, ok := r.encodeCacheValue(fullKey, value)
+	if !ok {
+		return
+	}
+	r.client.Set(r.ctx, fullKey, data, ttl)
+}
+
+// encodeCacheValue 序列化缓存值，字符串和字节数组原样存储
+func (r *redisCache) encodeCacheValue(fullKey string, value interface{}) (interface{}, bool) {
 	switch value.(type) {
 	case string, []byte:
-		data = value
-	default:
-		jsonData, err := json.Marshal(value)
-		if err != nil {
-			// 序列化失败，记录日志并跳过存储
-			fmt.Printf("eorm: redis cache marshal failed, key=%s, error=%v\n", fullKey, err)
-			return
+		return value, true
+	}
+	encoded, err := encodeValue(r.codec, value)
+	if err != nil {
+		// 序列化失败，记录日志并跳过存储
+		fmt.Printf("eorm: redis cache marshal failed, key=%s, codec=%s, error=%v\n", fullKey, r.codec.Name(), err)
+		return nil, false
+	}
+	return encoded, true
+}
+
+// CacheGetMulti 使用 MGET 批量获取缓存值，只返回命中的键
+func (r *redisCache) CacheGetMulti(cacheRepositoryName string, keys []string) map[string]interface{} {
+	result := make(map[string]interface{}, len(keys))
+	if len(keys) == 0 {
+		return result
+	}
+	fullKeys := make([]string, len(keys))
+	for i, key := range keys {
+		fullKeys[i] = fmt.Sprintf("eorm:%s:%s", cacheRepositoryName, key)
+	}
+
+	vals, err := r.client.MGet(r.ctx, fullKeys...).Result()
+	if err != nil {
+		return result
+	}
+	for i, val := range vals {
+		if s, ok := val.(string); ok {
+			result[keys[i]] = decodeValue([]byte(s))
 		}
-		data = jsonData
 	}
+	return result
+}
 
-	r.client.Set(r.ctx, fullKey, data, ttl)
+// CacheSetMulti 使用 pipeline 批量写入缓存值，只需一次网络往返
+func (r *redisCache) CacheSetMulti(cacheRepositoryName string, items map[string]interface{}, ttl time.Duration) {
+	if len(items) == 0 {
+		return
+	}
+	pipe := r.client.Pipeline()
+	for key, value := range items {
+		fullKey := fmt.Sprintf("eorm:%s:%s", cacheRepositoryName, key)
+		data, ok := r.encodeCacheValue(fullKey, value)
+		if !ok {
+			continue
+		}
+		pipe.Set(r.ctx, fullKey, data, ttl)
+	}
+	if _, err := pipe.Exec(r.ctx); err != nil {
+		fmt.Printf("eorm: redis cache pipeline set failed, repository=%s, error=%v\n", cacheRepositoryName, err)
+	}
 }
 
 func (r *redisCache) CacheDelete(cacheRepositoryName, key string) {
@@ -156,6 +217,7 @@ func (r *redisCache) Status() map[string]interface{} {
 	stats := make(map[string]interface{})
 	stats["type"] = "RedisCache"
 	stats["address"] = r.client.Options().Addr
+	stats["codec"] = r.codec.Name()
 
 	// 获取连接池统计信息（重要：用于监控高并发场景）
 	poolStats := r.client.PoolStats()
@@ -0,0 +1,287 @@
+package eorm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrRestoreConflict 恢复的记录与未删除的记录存在唯一性冲突
+var ErrRestoreConflict = errors.New("eorm: restore conflicts with active rows")
+
+// RestoreStrategy 恢复时遇到唯一性冲突的处理方式
+type RestoreStrategy int
+
+const (
+	// RestoreFail 存在任何冲突时不恢复任何记录，返回冲突报告和 ErrRestoreConflict
+	RestoreFail RestoreStrategy = iota
+	// RestoreRename 调用 RestoreOptions.Rename 修改冲突记录的列值后再恢复
+	RestoreRename
+	// RestoreMerge 不恢复冲突记录，把它合并到占用唯一值的未删除记录上（见 RestoreOptions.Merge）
+	RestoreMerge
+)
+
+// RestoreOptions RestoreWithCheck 的选项
+type RestoreOptions struct {
+	Strategy RestoreStrategy
+	// Columns 需要检查的唯一列组，为空时使用 ConfigActiveUnique 配置的列组
+	Columns [][]string
+	// Rename 返回冲突记录恢复前要修改的列值，RestoreRename 策略必须设置
+	Rename func(row *Record, columns []string) (*Record, error)
+	// Merge 返回合并时要更新到未删除记录上的列值，返回 nil 表示不更新；
+	// 为空时用已删除记录的值填充未删除记录中为 NULL 的列（主键和软删除字段除外）
+	Merge func(deleted, active *Record) (*Record, error)
+}
+
+// RestoreConflict 一条冲突记录及其处理结果
+type RestoreConflict struct {
+	Row        *Record  // 要恢复的已删除记录
+	Active     *Record  // 占用唯一值的未删除记录（同批恢复的记录之间冲突时为先恢复的记录）
+	Columns    []string // 冲突的唯一列组
+	Resolution string   // 处理结果：failed、renamed、merged
+	Changes    *Record  // 改名写入的列值或合并写入的列值
+}
+
+// RestoreReport RestoreWithCheck 的结果报告
+type RestoreReport struct {
+	Restored  int64             // 实际恢复的行数（包括改名后恢复的行）
+	Merged    int64             // 合并到未删除记录的行数
+	Conflicts []RestoreConflict // 检测到的全部冲突
+}
+
+// RestoreWithCheck 恢复软删除的记录，恢复前检查唯一列组是否已被新记录占用，并按策略处理冲突
+// 在事务中执行，RestoreFail 策略下存在冲突时不做任何修改
+// 示例:
+//
+//	report, err := eorm.RestoreWithCheck("users", "id = ?", &eorm.RestoreOptions{
+//	    Strategy: eorm.RestoreRename,
+//	    Columns:  [][]string{{"username"}},
+//	    Rename: func(row *eorm.Record, columns []string) (*eorm.Record, error) {
+//	        return eorm.NewRecord().Set("username", row.GetString("username")+"_restored"), nil
+//	    },
+//	})
+func RestoreWithCheck(table string, whereSql string, opts *RestoreOptions, whereArgs ...interface{}) (*RestoreReport, error) {
+	db, err := defaultDB()
+	if err != nil {
+		return nil, err
+	}
+	return db.RestoreWithCheck(table, whereSql, opts, whereArgs...)
+}
+
+// RestoreWithCheck 恢复软删除的记录并处理唯一性冲突，见 RestoreWithCheck
+func (db *DB) RestoreWithCheck(table string, whereSql string, opts *RestoreOptions, whereArgs ...interface{}) (*RestoreReport, error) {
+	if db.lastErr != nil {
+		return nil, db.lastErr
+	}
+	var report *RestoreReport
+	err := db.Transaction(func(tx *Tx) error {
+		var err error
+		report, err = tx.RestoreWithCheck(table, whereSql, opts, whereArgs...)
+		return err
+	})
+	return report, err
+}
+
+// RestoreWithCheck 在事务中恢复软删除的记录并处理唯一性冲突
+func (tx *Tx) RestoreWithCheck(table string, whereSql string, opts *RestoreOptions, whereArgs ...interface{}) (*RestoreReport, error) {
+	whereSql, whereArgs = applyRowFilter(tx.ctx, table, whereSql, whereArgs, true)
+	return tx.dbMgr.restoreWithCheck(tx.ctx, tx.tx, table, whereSql, opts, whereArgs...)
+}
+
+// deletedCondition builds the condition matching soft-deleted rows, independent of enableSoftDeleteCheck
+func (mgr *dbManager) deletedCondition(config *SoftDeleteConfig) string {
+	if config.Type == SoftDeleteBool {
+		if mgr.config.Load().Driver == PostgreSQL {
+			return fmt.Sprintf("%s = true", config.Field)
+		}
+		return fmt.Sprintf("%s = 1", config.Field)
+	}
+	return fmt.Sprintf("%s IS NOT NULL", config.Field)
+}
+
+// restoreWithCheck restores soft-deleted rows one by one after resolving unique conflicts
+func (mgr *dbManager) restoreWithCheck(ctx context.Context, executor sqlExecutor, table, where string, opts *RestoreOptions, whereArgs ...interface{}) (*RestoreReport, error) {
+	if err := validateIdentifier(table); err != nil {
+		return nil, err
+	}
+	config := mgr.getSoftDeleteConfig(table)
+	if config == nil {
+		return nil, fmt.Errorf("eorm: soft delete not configured for table %s", table)
+	}
+	if opts == nil {
+		opts = &RestoreOptions{}
+	}
+	if opts.Strategy == RestoreRename && opts.Rename == nil {
+		return nil, fmt.Errorf("eorm: RestoreRename requires a Rename callback")
+	}
+	groups := opts.Columns
+	if len(groups) == 0 && mgr.activeUniques != nil {
+		groups = mgr.activeUniques.get(table)
+	}
+	for _, group := range groups {
+		for _, col := range group {
+			if err := validateIdentifier(col); err != nil {
+				return nil, err
+			}
+		}
+	}
+	pks, err := mgr.getPrimaryKeys(executor, table)
+	if err != nil {
+		return nil, err
+	}
+	if len(pks) == 0 {
+		return nil, fmt.Errorf("eorm: table %s has no primary key", table)
+	}
+
+	deletedWhere := mgr.deletedCondition(config)
+	if where != "" {
+		deletedWhere = "(" + where + ") AND " + deletedWhere
+	}
+	rows, err := mgr.queryInternal(executor, fmt.Sprintf("SELECT * FROM %s WHERE %s", table, deletedWhere), whereArgs...)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &RestoreReport{}
+	type restoreAction struct {
+		row      *Record
+		conflict *RestoreConflict
+	}
+	actions := make([]restoreAction, 0, len(rows))
+	claimed := make(map[string]*Record) // 本批次恢复的行占用的唯一值
+	for _, row := range rows {
+		var conflict *RestoreConflict
+		for _, group := range groups {
+			key, values := uniqueGroupKey(row, group)
+			if values == nil {
+				continue
+			}
+			if owner, ok := claimed[key]; ok {
+				conflict = &RestoreConflict{Row: row, Active: owner, Columns: group}
+				break
+			}
+			active, err := mgr.findActiveDuplicate(executor, table, config, group, values)
+			if err != nil {
+				return nil, err
+			}
+			if active != nil {
+				conflict = &RestoreConflict{Row: row, Active: active, Columns: group}
+				break
+			}
+		}
+		if conflict == nil {
+			for _, group := range groups {
+				if key, values := uniqueGroupKey(row, group); values != nil {
+					claimed[key] = row
+				}
+			}
+		}
+		actions = append(actions, restoreAction{row: row, conflict: conflict})
+	}
+
+	for _, action := range actions {
+		if action.conflict != nil {
+			report.Conflicts = append(report.Conflicts, *action.conflict)
+		}
+	}
+	if len(report.Conflicts) > 0 && opts.Strategy == RestoreFail {
+		for i := range report.Conflicts {
+			report.Conflicts[i].Resolution = "failed"
+		}
+		return report, fmt.Errorf("%w: %d conflict(s) in %s", ErrRestoreConflict, len(report.Conflicts), table)
+	}
+
+	n := 0
+	for _, action := range actions {
+		row, conflict := action.row, action.conflict
+		pkWhere, pkArgs := recordKeyConditions(pks, []*Record{row})
+		if conflict != nil {
+			c := &report.Conflicts[n]
+			n++
+			if opts.Strategy == RestoreMerge {
+				changes, err := mgr.mergeRestoreConflict(ctx, executor, table, pks, config, opts, row, conflict.Active)
+				if err != nil {
+					return report, err
+				}
+				c.Resolution, c.Changes = "merged", changes
+				report.Merged++
+				continue
+			}
+			changes, err := opts.Rename(row, conflict.Columns)
+			if err != nil {
+				return report, err
+			}
+			if changes != nil && len(changes.columns) > 0 {
+				if _, err := mgr.update(ctx, executor, table, changes, pkWhere, pkArgs...); err != nil {
+					return report, err
+				}
+			}
+			c.Resolution, c.Changes = "renamed", changes
+		}
+		restored, err := mgr.restore(ctx, executor, table, pkWhere, pkArgs...)
+		if err != nil {
+			return report, err
+		}
+		report.Restored += restored
+	}
+	return report, nil
+}
+
+// findActiveDuplicate returns the non-deleted row holding the unique values, or nil
+func (mgr *dbManager) findActiveDuplicate(executor sqlExecutor, table string, config *SoftDeleteConfig, group []string, values []interface{}) (*Record, error) {
+	conditions := make([]string, len(group))
+	for i, col := range group {
+		conditions[i] = col + " = ?"
+	}
+	querySQL := fmt.Sprintf("SELECT * FROM %s WHERE %s AND %s LIMIT 1", table, strings.Join(conditions, " AND "), mgr.activeCondition(config))
+	records, err := mgr.queryInternal(executor, querySQL, values...)
+	if err != nil || len(records) == 0 {
+		return nil, err
+	}
+	return records[0], nil
+}
+
+// mergeRestoreConflict applies the deleted row onto the active row, the deleted row stays deleted
+func (mgr *dbManager) mergeRestoreConflict(ctx context.Context, executor sqlExecutor, table string, pks []string, config *SoftDeleteConfig, opts *RestoreOptions, deleted, active *Record) (*Record, error) {
+	var changes *Record
+	if opts.Merge != nil {
+		var err error
+		if changes, err = opts.Merge(deleted, active); err != nil {
+			return nil, err
+		}
+	} else {
+		skip := map[string]bool{strings.ToLower(config.Field): true}
+		for _, pk := range pks {
+			skip[strings.ToLower(pk)] = true
+		}
+		changes = NewRecord()
+		for _, col := range deleted.Keys() {
+			if skip[strings.ToLower(col)] || deleted.Get(col) == nil || active.Get(col) != nil {
+				continue
+			}
+			changes.Set(col, deleted.Get(col))
+		}
+	}
+	if changes == nil || len(changes.columns) == 0 {
+		return changes, nil
+	}
+	activeWhere, activeArgs := recordKeyConditions(pks, []*Record{active})
+	if _, err := mgr.update(ctx, executor, table, changes, activeWhere, activeArgs...); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// uniqueGroupKey returns a comparable key and values of a unique column group, values is nil when any column is NULL
+func uniqueGroupKey(row *Record, group []string) (string, []interface{}) {
+	values := make([]interface{}, 0, len(group))
+	for _, col := range group {
+		v := row.Get(col)
+		if v == nil {
+			return "", nil
+		}
+		values = append(values, v)
+	}
+	return strings.ToLower(strings.Join(group, ",")) + "=" + fmt.Sprintf("%#v", values), values
+}
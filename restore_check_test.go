@@ -0,0 +1,69 @@
+package eorm
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRestoreWithCheck(t *testing.T) {
+	const ddl = "CREATE TABLE rc_users (id INTEGER PRIMARY KEY, username TEXT, email TEXT, deleted_at DATETIME)"
+	setup := func(t *testing.T) *DB {
+		db := openTestDB(t, ddl)
+		db.ConfigSoftDelete("rc_users", "deleted_at")
+		db.ConfigActiveUnique("rc_users", "username")
+		mustExec(t, db, "INSERT INTO rc_users (id, username, email, deleted_at) VALUES (1, 'tom', 'tom@old', CURRENT_TIMESTAMP)")
+		mustExec(t, db, "INSERT INTO rc_users (id, username, deleted_at) VALUES (2, 'amy', CURRENT_TIMESTAMP)")
+		mustExec(t, db, "INSERT INTO rc_users (id, username) VALUES (3, 'tom')")
+		return db
+	}
+
+	t.Run("Fail", func(t *testing.T) {
+		db := setup(t)
+		report, err := db.RestoreWithCheck("rc_users", "", nil)
+		if !errors.Is(err, ErrRestoreConflict) {
+			t.Fatalf("err = %v, want ErrRestoreConflict", err)
+		}
+		if len(report.Conflicts) != 1 || report.Conflicts[0].Row.GetInt64("id") != 1 || report.Conflicts[0].Resolution != "failed" {
+			t.Fatalf("conflicts = %+v, want row 1 failed", report.Conflicts)
+		}
+		if n := countRows(t, db, "rc_users", "deleted_at IS NULL"); n != 1 {
+			t.Fatalf("active rows = %d, want 1: nothing may be restored", n)
+		}
+	})
+
+	t.Run("Rename", func(t *testing.T) {
+		db := setup(t)
+		report, err := db.RestoreWithCheck("rc_users", "", &RestoreOptions{
+			Strategy: RestoreRename,
+			Rename: func(row *Record, columns []string) (*Record, error) {
+				return NewRecord().Set("username", row.GetString("username")+"_restored"), nil
+			},
+		})
+		if err != nil {
+			t.Fatalf("RestoreWithCheck: %v", err)
+		}
+		if report.Restored != 2 || len(report.Conflicts) != 1 || report.Conflicts[0].Resolution != "renamed" {
+			t.Fatalf("report = %+v, want 2 restored and 1 renamed", report)
+		}
+		if n := countRows(t, db, "rc_users", "deleted_at IS NULL AND username = ?", "tom_restored"); n != 1 {
+			t.Fatalf("renamed active rows = %d, want 1", n)
+		}
+	})
+
+	t.Run("Merge", func(t *testing.T) {
+		db := setup(t)
+		report, err := db.RestoreWithCheck("rc_users", "id = ?", &RestoreOptions{Strategy: RestoreMerge}, 1)
+		if err != nil {
+			t.Fatalf("RestoreWithCheck: %v", err)
+		}
+		if report.Restored != 0 || report.Merged != 1 {
+			t.Fatalf("report = %+v, want 1 merged", report)
+		}
+		if n := countRows(t, db, "rc_users", "id = 3 AND email = ?", "tom@old"); n != 1 {
+			t.Fatal("merge did not fill the NULL email of the active row")
+		}
+		if n := countRows(t, db, "rc_users", "id = 1 AND deleted_at IS NOT NULL"); n != 1 {
+			t.Fatal("merged row must stay deleted")
+		}
+	})
+}
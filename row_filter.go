@@ -0,0 +1,231 @@
+package eorm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// RowFilterFunc 行级过滤函数，根据上下文（如当前用户、租户）返回追加到 WHERE 的条件和参数
+// 返回空条件表示本次不过滤
+type RowFilterFunc func(ctx context.Context) (condition string, args []interface{})
+
+// rowFilter 表级行过滤器
+type rowFilter struct {
+	fn     RowFilterFunc
+	writes bool // 同时作用于 Update/Delete
+}
+
+// rowFilterRegistry 行级过滤器注册表 (table -> filter)
+var rowFilterRegistry = struct {
+	sync.RWMutex
+	filters map[string]rowFilter
+}{filters: make(map[string]rowFilter)}
+
+// RegisterRowFilter 为表注册行级过滤器，实现类似数据库行级安全（RLS）的数据可见性控制
+// 过滤条件作用于该表的结构化读取：Table(table) 构建的查询、Count/Exists/FindAll/PaginateBuilder，
+// 以及 SqlTemplate 中引用该表的 SELECT 语句；原生 SQL 的 Query/QueryFirst/Paginate 等不会注入过滤条件
+// applyToWrites 为 true 时同时作用于 Update/Delete/ForceDelete/Restore、UpdateRecord/DeleteRecord、
+// BatchUpdateRecord/BatchDeleteRecord/BatchDeleteByIds 以及 DeleteLimit/BatchDeleteWhere
+// 上下文通过 WithContext(ctx) 传入，同一张表重复注册会覆盖
+// 示例:
+//
+//	eorm.RegisterRowFilter("orders", func(ctx context.Context) (string, []interface{}) {
+//	    if uid, ok := ctx.Value(userKey).(int64); ok {
+//	        return "owner_id = ?", []interface{}{uid}
+//	    }
+//	    return "1 = 0", nil // 未登录时不可见任何数据
+//	}, true)
+//	eorm.WithContext(ctx).Table("orders").Find()
+func RegisterRowFilter(table string, fn RowFilterFunc, applyToWrites ...bool) {
+	if table == "" || fn == nil {
+		return
+	}
+	rowFilterRegistry.Lock()
+	defer rowFilterRegistry.Unlock()
+	rowFilterRegistry.filters[strings.ToLower(table)] = rowFilter{
+		fn:     fn,
+		writes: len(applyToWrites) > 0 && applyToWrites[0],
+	}
+}
+
+// RemoveRowFilter 移除表的行级过滤器
+func RemoveRowFilter(table string) {
+	rowFilterRegistry.Lock()
+	defer rowFilterRegistry.Unlock()
+	delete(rowFilterRegistry.filters, strings.ToLower(table))
+}
+
+// WithContext 返回携带上下文的默认数据库实例，上下文用于行级过滤器及查询超时控制
+func WithContext(ctx context.Context) *DB {
+	db, err := defaultDB()
+	if err != nil {
+		return &DB{lastErr: err}
+	}
+	return db.WithContext(ctx)
+}
+
+// WithContext 设置上下文，上下文用于行级过滤器及查询超时控制
+func (db *DB) WithContext(ctx context.Context) *DB {
+	db.ctx = ctx
+	return db
+}
+
+// WithContext 设置事务的上下文
+func (tx *Tx) WithContext(ctx context.Context) *Tx {
+	tx.ctx = ctx
+	return tx
+}
+
+// WithContext 设置 SQL 模板的上下文
+func (b *SqlTemplateBuilder) WithContext(ctx context.Context) *SqlTemplateBuilder {
+	b.ctx = ctx
+	return b
+}
+
+// contextOrBackground 返回非 nil 的上下文
+func contextOrBackground(ctx context.Context) context.Context {
+	if ctx == nil {
+		return context.Background()
+	}
+	return ctx
+}
+
+// getRowFilterCondition 返回表的行级过滤条件，write 表示用于写操作
+func getRowFilterCondition(ctx context.Context, table string, write bool) (string, []interface{}) {
+	if table == "" {
+		return "", nil
+	}
+	rowFilterRegistry.RLock()
+	filter, ok := rowFilterRegistry.filters[strings.ToLower(table)]
+	rowFilterRegistry.RUnlock()
+	if !ok || (write && !filter.writes) {
+		return "", nil
+	}
+	return filter.fn(contextOrBackground(ctx))
+}
+
+// applyRowFilter 将表的行级过滤条件以 AND 追加到 where 条件
+func applyRowFilter(ctx context.Context, table, where string, args []interface{}, write bool) (string, []interface{}) {
+	condition, filterArgs := getRowFilterCondition(ctx, table, write)
+	return rowCondition{sql: condition, args: filterArgs}.and(where, args)
+}
+
+// rowCondition 已求值的行级过滤条件，批量写操作对每条语句复用同一条件
+type rowCondition struct {
+	sql  string
+	args []interface{}
+}
+
+// writeRowCondition 返回表在上下文中用于写操作的行级过滤条件
+func writeRowCondition(ctx context.Context, table string) rowCondition {
+	condition, args := getRowFilterCondition(ctx, table, true)
+	return rowCondition{sql: condition, args: args}
+}
+
+// and 将条件以 AND 追加到 where，条件参数追加在 args 之后；条件为空时原样返回
+func (c rowCondition) and(where string, args []interface{}) (string, []interface{}) {
+	if c.sql == "" {
+		return where, args
+	}
+	if strings.TrimSpace(where) == "" {
+		return c.sql, c.args
+	}
+	combined := make([]interface{}, 0, len(args)+len(c.args))
+	combined = append(combined, args...)
+	combined = append(combined, c.args...)
+	return fmt.Sprintf("(%s) AND (%s)", where, c.sql), combined
+}
+
+// getRowFilterCondition 返回构建器所查询表的行级过滤条件
+func (qb *QueryBuilder) getRowFilterCondition() (string, []interface{}) {
+	if qb.table == "" || qb.subqueryTable != nil {
+		return "", nil
+	}
+	var ctx context.Context
+	if qb.tx != nil {
+		ctx = qb.tx.ctx
+	} else if qb.db != nil {
+		ctx = qb.db.ctx
+	}
+	return getRowFilterCondition(ctx, qb.table, false)
+}
+
+// rowFilterSentinel 注入条件时的占位标记，用于计算条件参数在参数列表中的位置
+const rowFilterSentinel = "/*eorm:row_filter*/"
+
+// injectRowFilters 向原始 SELECT 语句注入所引用表的行级过滤条件
+// 条件参数按注入位置之前的 ? 占位符数量插入参数列表
+func (mgr *dbManager) injectRowFilters(ctx context.Context, querySQL string, args []interface{}) (string, []interface{}) {
+	if !strings.HasPrefix(strings.ToUpper(strings.TrimSpace(querySQL)), "SELECT") {
+		return querySQL, args
+	}
+	rowFilterRegistry.RLock()
+	empty := len(rowFilterRegistry.filters) == 0
+	rowFilterRegistry.RUnlock()
+	if empty {
+		return querySQL, args
+	}
+
+	tables, _ := mgr.extractTablesFromSQLWithAliases(querySQL)
+	var conditions []string
+	var filterArgs []interface{}
+	for _, table := range tables {
+		if condition, cArgs := getRowFilterCondition(ctx, table, false); condition != "" {
+			conditions = append(conditions, "("+condition+")")
+			filterArgs = append(filterArgs, cArgs...)
+		}
+	}
+	if len(conditions) == 0 {
+		return querySQL, args
+	}
+
+	var marked string
+	if mgr.hasWhereClause(querySQL) {
+		marked = mgr.appendToWhereClause(querySQL, rowFilterSentinel)
+	} else {
+		marked = mgr.insertWhereClause(querySQL, rowFilterSentinel)
+	}
+	pos := strings.Index(marked, rowFilterSentinel)
+	if pos == -1 {
+		return querySQL, args
+	}
+
+	argPos := countPlaceholders(marked[:pos])
+	if argPos > len(args) {
+		argPos = len(args)
+	}
+	newArgs := make([]interface{}, 0, len(args)+len(filterArgs))
+	newArgs = append(newArgs, args[:argPos]...)
+	newArgs = append(newArgs, filterArgs...)
+	newArgs = append(newArgs, args[argPos:]...)
+
+	return strings.Replace(marked, rowFilterSentinel, strings.Join(conditions, " AND "), 1), newArgs
+}
+
+// countPlaceholders 统计 SQL 中 ? 占位符的数量（跳过引号内的内容和 ?? 转义）
+func countPlaceholders(querySQL string) int {
+	count := 0
+	var quote byte
+	for i := 0; i < len(querySQL); i++ {
+		c := querySQL[i]
+		if quote != 0 {
+			if c == quote {
+				quote = 0
+			}
+			continue
+		}
+		switch c {
+		case '\'', '"', '`':
+			quote = c
+		case '?':
+			if i+1 < len(querySQL) && querySQL[i+1] == '?' {
+				i++
+				continue
+			}
+			count++
+		}
+	}
+	return count
+}
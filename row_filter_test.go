@@ -0,0 +1,80 @@
+package eorm
+
+import (
+	"context"
+	"testing"
+)
+
+type rowFilterUserKey struct{}
+
+// registerOwnerFilter 为表注册按 owner_id 过滤的写行级过滤器
+func registerOwnerFilter(t *testing.T, table string) {
+	t.Helper()
+	RegisterRowFilter(table, func(ctx context.Context) (string, []interface{}) {
+		if uid, ok := ctx.Value(rowFilterUserKey{}).(int64); ok {
+			return "owner_id = ?", []interface{}{uid}
+		}
+		return "1 = 0", nil
+	}, true)
+	t.Cleanup(func() { RemoveRowFilter(table) })
+}
+
+func TestRowFilterAppliesToPrimaryKeyWrites(t *testing.T) {
+	const ddl = "CREATE TABLE rf_orders (id INTEGER PRIMARY KEY, owner_id INTEGER, amount INTEGER)"
+	ctx := context.WithValue(context.Background(), rowFilterUserKey{}, int64(1))
+
+	tests := []struct {
+		name string
+		run  func(db *DB) (int64, error)
+	}{
+		{"UpdateRecord", func(db *DB) (int64, error) {
+			return db.WithContext(ctx).UpdateRecord("rf_orders", NewRecord().Set("id", 2).Set("amount", 0))
+		}},
+		{"DeleteRecord", func(db *DB) (int64, error) {
+			return db.WithContext(ctx).DeleteRecord("rf_orders", NewRecord().Set("id", 2))
+		}},
+		{"BatchUpdateRecord", func(db *DB) (int64, error) {
+			return db.WithContext(ctx).BatchUpdateRecord("rf_orders", []*Record{
+				NewRecord().Set("id", 1).Set("amount", 0),
+				NewRecord().Set("id", 2).Set("amount", 0),
+			})
+		}},
+		{"BatchDeleteRecord", func(db *DB) (int64, error) {
+			return db.WithContext(ctx).BatchDeleteRecord("rf_orders", []*Record{
+				NewRecord().Set("id", 1), NewRecord().Set("id", 2),
+			})
+		}},
+		{"BatchDeleteByIds", func(db *DB) (int64, error) {
+			return db.WithContext(ctx).BatchDeleteByIds("rf_orders", []interface{}{1, 2})
+		}},
+		{"BatchDeleteWhere", func(db *DB) (int64, error) {
+			return db.WithContext(ctx).BatchDeleteWhere("rf_orders", "amount > ?", []interface{}{0}, 10)
+		}},
+		{"DeleteLimit", func(db *DB) (int64, error) {
+			return db.WithContext(ctx).Table("rf_orders").Where("amount > ?", 0).DeleteLimit(10)
+		}},
+		{"TxDeleteRecord", func(db *DB) (int64, error) {
+			var n int64
+			err := db.Transaction(func(tx *Tx) (err error) {
+				n, err = tx.WithContext(ctx).DeleteRecord("rf_orders", NewRecord().Set("id", 2))
+				return err
+			})
+			return n, err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openTestDB(t, ddl)
+			mustExec(t, db, "INSERT INTO rf_orders (id, owner_id, amount) VALUES (1, 1, 10), (2, 2, 20)")
+			registerOwnerFilter(t, "rf_orders")
+
+			if _, err := tt.run(db); err != nil {
+				t.Fatalf("write failed: %v", err)
+			}
+			if n := countRows(t, db, "rf_orders", "id = 2 AND amount = 20"); n != 1 {
+				t.Fatalf("row owned by another user was modified")
+			}
+		})
+	}
+}
@@ -0,0 +1,267 @@
+package eorm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultRowTTLReapInterval is the default interval between two reaper runs
+	DefaultRowTTLReapInterval = time.Minute
+	// DefaultRowTTLBatchSize is the default number of expired rows deleted per statement
+	DefaultRowTTLBatchSize = 1000
+)
+
+// RowTTLConfig holds the row TTL configuration for a table
+type RowTTLConfig struct {
+	Field        string        // Expiry time column, e.g., "expires_at"; NULL means never expires
+	ReapInterval time.Duration // Interval between reaper runs, <0 disables the background reaper
+	BatchSize    int           // Rows deleted per statement
+}
+
+// rowTTLRegistry stores row TTL configurations and running reapers per database
+type rowTTLRegistry struct {
+	configs map[string]*RowTTLConfig // table -> config
+	reapers map[string]chan struct{} // table -> reaper stop channel
+	mu      sync.RWMutex
+}
+
+// newRowTTLRegistry creates a new row TTL registry
+func newRowTTLRegistry() *rowTTLRegistry {
+	return &rowTTLRegistry{
+		configs: make(map[string]*RowTTLConfig),
+		reapers: make(map[string]chan struct{}),
+	}
+}
+
+// get returns the row TTL config for a table
+func (r *rowTTLRegistry) get(table string) *RowTTLConfig {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.configs[strings.ToLower(table)]
+}
+
+// --- Global Functions (for default database) ---
+
+// ConfigRowTTL configures auto-expiring rows for a table (sessions, tokens, ...).
+// Reads built with Table(table) only return rows where the field is NULL or later than now,
+// and a background reaper physically deletes expired rows in batches.
+// reapInterval is optional and defaults to DefaultRowTTLReapInterval; pass a negative value to disable the reaper.
+// Example: eorm.ConfigRowTTL("sessions", "expires_at")
+func ConfigRowTTL(table, field string, reapInterval ...time.Duration) {
+	db, err := defaultDB()
+	if err != nil {
+		return
+	}
+	db.ConfigRowTTL(table, field, reapInterval...)
+}
+
+// RemoveRowTTL removes row TTL configuration for a table and stops its reaper
+func RemoveRowTTL(table string) {
+	db, err := defaultDB()
+	if err != nil {
+		return
+	}
+	db.RemoveRowTTL(table)
+}
+
+// ReapExpiredRows deletes all expired rows of a table immediately and returns the number deleted
+func ReapExpiredRows(table string) (int64, error) {
+	db, err := defaultDB()
+	if err != nil {
+		return 0, err
+	}
+	return db.ReapExpiredRows(table)
+}
+
+// --- DB Methods ---
+
+// ConfigRowTTL configures auto-expiring rows for a table
+func (db *DB) ConfigRowTTL(table, field string, reapInterval ...time.Duration) *DB {
+	if db.lastErr != nil || db.dbMgr == nil {
+		return db
+	}
+	if err := validateIdentifier(table); err != nil {
+		db.lastErr = err
+		return db
+	}
+	if err := validateIdentifier(field); err != nil {
+		db.lastErr = err
+		return db
+	}
+	if !db.dbMgr.checkTableColumn(table, field) {
+		LogWarn(fmt.Sprintf("行过期配置警告: 表 '%s' 中不存在字段 '%s'", table, field), NewRecord().
+			Set("db", db.dbMgr.name).
+			Set("table", table).
+			Set("field", field))
+	}
+
+	config := &RowTTLConfig{
+		Field:        field,
+		ReapInterval: DefaultRowTTLReapInterval,
+		BatchSize:    DefaultRowTTLBatchSize,
+	}
+	if len(reapInterval) > 0 && reapInterval[0] != 0 {
+		config.ReapInterval = reapInterval[0]
+	}
+	db.dbMgr.setRowTTLConfig(table, config)
+	return db
+}
+
+// RemoveRowTTL removes row TTL configuration for a table and stops its reaper
+func (db *DB) RemoveRowTTL(table string) *DB {
+	if db.lastErr != nil || db.dbMgr == nil {
+		return db
+	}
+	db.dbMgr.removeRowTTLConfig(table)
+	return db
+}
+
+// ReapExpiredRows deletes all expired rows of a table immediately
+func (db *DB) ReapExpiredRows(table string) (int64, error) {
+	if db.lastErr != nil {
+		return 0, db.lastErr
+	}
+	return db.dbMgr.reapExpiredRows(db.ctx, table)
+}
+
+// --- QueryBuilder Methods ---
+
+// WithExpired includes expired rows of a table configured with ConfigRowTTL
+func (qb *QueryBuilder) WithExpired() *QueryBuilder {
+	qb.withExpired = true
+	return qb
+}
+
+// getRowTTLCondition returns the expiry filter condition and its argument
+func (qb *QueryBuilder) getRowTTLCondition() (string, []interface{}) {
+	if qb.withExpired || qb.table == "" || qb.subqueryTable != nil {
+		return "", nil
+	}
+	var mgr *dbManager
+	if qb.db != nil && qb.db.dbMgr != nil {
+		mgr = qb.db.dbMgr
+	} else if qb.tx != nil && qb.tx.dbMgr != nil {
+		mgr = qb.tx.dbMgr
+	}
+	if mgr == nil || mgr.rowTTLs == nil {
+		return "", nil
+	}
+	config := mgr.rowTTLs.get(qb.table)
+	if config == nil {
+		return "", nil
+	}
+	return fmt.Sprintf("(%s IS NULL OR %s > ?)", config.Field, config.Field), []interface{}{time.Now()}
+}
+
+// --- dbManager Methods ---
+
+// setRowTTLConfig sets row TTL config for a table and (re)starts its reaper
+func (mgr *dbManager) setRowTTLConfig(table string, config *RowTTLConfig) {
+	if mgr.rowTTLs == nil {
+		mgr.rowTTLs = newRowTTLRegistry()
+	}
+	key := strings.ToLower(table)
+
+	r := mgr.rowTTLs
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if stop, ok := r.reapers[key]; ok {
+		close(stop)
+		delete(r.reapers, key)
+	}
+	r.configs[key] = config
+	if config.ReapInterval > 0 {
+		stop := make(chan struct{})
+		r.reapers[key] = stop
+		go mgr.runRowTTLReaper(table, config.ReapInterval, stop)
+	}
+}
+
+// removeRowTTLConfig removes row TTL config for a table and stops its reaper
+func (mgr *dbManager) removeRowTTLConfig(table string) {
+	if mgr.rowTTLs == nil {
+		return
+	}
+	key := strings.ToLower(table)
+
+	r := mgr.rowTTLs
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if stop, ok := r.reapers[key]; ok {
+		close(stop)
+		delete(r.reapers, key)
+	}
+	delete(r.configs, key)
+}
+
+// stopRowTTLReapers stops all background reapers of the database
+func (mgr *dbManager) stopRowTTLReapers() {
+	if mgr.rowTTLs == nil {
+		return
+	}
+	r := mgr.rowTTLs
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for key, stop := range r.reapers {
+		close(stop)
+		delete(r.reapers, key)
+	}
+}
+
+// runRowTTLReaper periodically deletes expired rows until stopped
+func (mgr *dbManager) runRowTTLReaper(table string, interval time.Duration, stop chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if _, err := mgr.reapExpiredRows(context.Background(), table); err != nil {
+				LogWarn("行过期清理失败", NewRecord().
+					Set("db", mgr.name).
+					Set("table", table).
+					Set("error", err.Error()))
+			}
+		}
+	}
+}
+
+// reapExpiredRows deletes expired rows in batches of config.BatchSize
+func (mgr *dbManager) reapExpiredRows(ctx context.Context, table string) (int64, error) {
+	if mgr.rowTTLs == nil {
+		return 0, fmt.Errorf("eorm: row TTL not configured for table %s", table)
+	}
+	config := mgr.rowTTLs.get(table)
+	if config == nil {
+		return 0, fmt.Errorf("eorm: row TTL not configured for table %s", table)
+	}
+	sdb, err := mgr.getDB()
+	if err != nil {
+		return 0, err
+	}
+	executor := mgr.executorFor(sdb)
+
+	batchSize := config.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultRowTTLBatchSize
+	}
+	where := fmt.Sprintf("%s <= ?", config.Field)
+	now := time.Now()
+
+	var total int64
+	for {
+		affected, err := mgr.deleteLimit(ctx, executor, table, where, "", batchSize, now)
+		total += affected
+		if err != nil {
+			return total, err
+		}
+		if affected < int64(batchSize) {
+			return total, nil
+		}
+	}
+}
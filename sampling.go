@@ -0,0 +1,91 @@
+package eorm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// randomOrderExpr 返回数据库的随机排序表达式
+func randomOrderExpr(driver DriverType) string {
+	switch driver {
+	case MySQL:
+		return "RAND()"
+	case SQLServer:
+		return "NEWID()"
+	case Oracle:
+		return "DBMS_RANDOM.VALUE"
+	default:
+		return "RANDOM()"
+	}
+}
+
+// InRandomOrder 按随机顺序返回结果，可配合 Limit 随机取若干行
+// MySQL 生成 ORDER BY RAND()，PostgreSQL、SQLite 生成 RANDOM()，SQL Server 生成 NEWID()，Oracle 生成 DBMS_RANDOM.VALUE
+// 注意：需要对全部匹配行排序，大表上代价较高，可先用 Sample 缩小范围
+// 示例:
+//
+//	eorm.Table("products").Where("stock > ?", 0).InRandomOrder().Limit(5).Find()
+func (qb *QueryBuilder) InRandomOrder() *QueryBuilder {
+	if qb.lastErr != nil {
+		return qb
+	}
+	return qb.appendOrderTerm(orderTerm{random: true})
+}
+
+// Sample 按百分比（0~100）随机抽取表中的行，用于数据抽检、统计估算等场景
+// PostgreSQL 使用 TABLESAMPLE BERNOULLI，SQL Server 使用 TABLESAMPLE (p PERCENT)（按数据页抽样，行数误差较大），
+// Oracle 使用 SAMPLE (p)；MySQL、SQLite 不支持表抽样，改为逐行随机过滤
+// FROM 子查询时统一使用逐行随机过滤；每次执行抽取的行不同，不要与查询缓存同时使用
+// 示例:
+//
+//	eorm.Table("orders").Sample(1).Where("status = ?", "PAID").Find()
+func (qb *QueryBuilder) Sample(percent float64) *QueryBuilder {
+	if qb.lastErr != nil {
+		return qb
+	}
+	if percent <= 0 || percent > 100 {
+		qb.lastErr = fmt.Errorf("eorm: Sample percent must be in (0, 100], got %v", percent)
+		return qb
+	}
+	driver := qb.getDriverType()
+	switch {
+	case qb.subqueryTable == nil && (driver == PostgreSQL || driver == SQLServer || driver == Oracle):
+		qb.samplePercent = percent
+	case driver == SQLite3:
+		// RANDOM() 返回 64 位有符号整数，取模后按万分比比较
+		qb.whereSql = append(qb.whereSql, "(ABS(RANDOM()) % 1000000) < ?")
+		qb.whereArgs = append(qb.whereArgs, int64(percent*10000))
+	case driver == SQLServer:
+		// RAND() 在一条语句中只计算一次，逐行随机需要 NEWID()
+		qb.whereSql = append(qb.whereSql, "(ABS(CHECKSUM(NEWID())) % 1000000) < ?")
+		qb.whereArgs = append(qb.whereArgs, int64(percent*10000))
+	default:
+		qb.whereSql = append(qb.whereSql, randomOrderExpr(driver)+" < ?")
+		qb.whereArgs = append(qb.whereArgs, percent/100)
+	}
+	return qb
+}
+
+// sampledFrom 为 FROM 中的表追加表抽样子句，未设置 Sample 时原样返回
+// table 可带别名（如 "orders o"）：PostgreSQL、SQL Server 的抽样子句在别名之后，Oracle 的 SAMPLE 在别名之前
+func (qb *QueryBuilder) sampledFrom(table string) string {
+	if qb.samplePercent <= 0 {
+		return table
+	}
+	percent := strconv.FormatFloat(qb.samplePercent, 'f', -1, 64)
+	switch qb.getDriverType() {
+	case PostgreSQL:
+		return fmt.Sprintf("%s TABLESAMPLE BERNOULLI (%s)", table, percent)
+	case SQLServer:
+		return fmt.Sprintf("%s TABLESAMPLE (%s PERCENT)", table, percent)
+	case Oracle:
+		name, alias, hasAlias := strings.Cut(strings.TrimSpace(table), " ")
+		if hasAlias {
+			return fmt.Sprintf("%s SAMPLE (%s) %s", name, percent, strings.TrimSpace(alias))
+		}
+		return fmt.Sprintf("%s SAMPLE (%s)", name, percent)
+	default:
+		return table
+	}
+}
@@ -0,0 +1,112 @@
+package eorm
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// savepointAction 保存点操作类型
+type savepointAction int
+
+const (
+	savepointCreate   savepointAction = iota // 创建保存点
+	savepointRollback                        // 回滚到保存点
+	savepointRelease                         // 释放保存点
+)
+
+// savepointSQL 按数据库类型生成保存点语句
+// Oracle 和 SQL Server 没有释放保存点的语句，返回空字符串
+func (mgr *dbManager) savepointSQL(action savepointAction, name string) string {
+	if mgr.config.Load().Driver == SQLServer {
+		switch action {
+		case savepointCreate:
+			return "SAVE TRANSACTION " + name
+		case savepointRollback:
+			return "ROLLBACK TRANSACTION " + name
+		default:
+			return ""
+		}
+	}
+	switch action {
+	case savepointCreate:
+		return "SAVEPOINT " + name
+	case savepointRollback:
+		return "ROLLBACK TO SAVEPOINT " + name
+	default:
+		if mgr.config.Load().Driver == Oracle {
+			return ""
+		}
+		return "RELEASE SAVEPOINT " + name
+	}
+}
+
+// execSavepoint 在事务中执行保存点操作
+func (mgr *dbManager) execSavepoint(ctx context.Context, executor sqlExecutor, action savepointAction, name string) error {
+	if err := validateIdentifier(name); err != nil {
+		return err
+	}
+	querySQL := mgr.savepointSQL(action, name)
+	if querySQL == "" {
+		return nil
+	}
+	start := time.Now()
+	var err error
+	if execCtx, ok := executor.(sqlExecutorContext); ok {
+		_, err = execCtx.ExecContext(ctx, querySQL)
+	} else {
+		_, err = executor.Exec(querySQL)
+	}
+	mgr.logTrace(start, querySQL, nil, err)
+	if err != nil {
+		return fmt.Errorf("eorm: savepoint %s failed: %w", name, err)
+	}
+	mgr.savepointChanges(executor, action, name)
+	return nil
+}
+
+// Savepoint 在事务中创建保存点，之后可以通过 RollbackTo 撤销保存点之后的修改而不回滚整个事务
+// SQL Server 使用 SAVE TRANSACTION，其他数据库使用 SAVEPOINT（MySQL 需要 InnoDB）
+// 示例:
+//
+//	tx.Savepoint("before_items")
+//	if _, err := tx.BatchInsertRecord("items", items); err != nil {
+//	    tx.RollbackTo("before_items")
+//	}
+func (tx *Tx) Savepoint(name string) error {
+	ctx, cancel := tx.getContext()
+	defer cancel()
+	return tx.dbMgr.execSavepoint(ctx, tx.tx, savepointCreate, name)
+}
+
+// RollbackTo 回滚到指定保存点，保存点之后的修改被撤销，事务仍然有效
+func (tx *Tx) RollbackTo(name string) error {
+	ctx, cancel := tx.getContext()
+	defer cancel()
+	err := tx.dbMgr.execSavepoint(ctx, tx.tx, savepointRollback, name)
+	if err == nil && tx.cacheRepositoryName != "" {
+		tx.ClearCache(tx.cacheRepositoryName)
+	}
+	return err
+}
+
+// ReleaseSavepoint 释放保存点（Oracle 和 SQL Server 不支持释放，调用时直接返回）
+func (tx *Tx) ReleaseSavepoint(name string) error {
+	ctx, cancel := tx.getContext()
+	defer cancel()
+	return tx.dbMgr.execSavepoint(ctx, tx.tx, savepointRelease, name)
+}
+
+// ContinueOnError 设置事务中的 BatchExec 遇到失败语句时继续执行
+// 每条语句都包裹在保存点中，失败的语句回滚到其保存点后跳过，其余语句的修改在提交时生效
+// 示例:
+//
+//	eorm.Transaction(func(tx *eorm.Tx) error {
+//	    results, err := tx.ContinueOnError().BatchExec(sqls, args...)
+//	    // err 为 *BatchExecError 时表示部分语句失败，results 中保留每条语句的结果
+//	    return nil
+//	})
+func (tx *Tx) ContinueOnError() *Tx {
+	tx.continueOnError = true
+	return tx
+}
@@ -0,0 +1,227 @@
+package eorm
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SchemaSnapshot 数据库结构快照（表、列、索引），可序列化为 JSON 保存后用于跨环境比对
+type SchemaSnapshot struct {
+	Fingerprint string                 `json:"fingerprint"`
+	Tables      map[string]TableSchema `json:"tables"` // 小写表名 -> 表结构
+}
+
+// TableSchema 单表结构
+type TableSchema struct {
+	Name    string       `json:"name"`
+	Columns []ColumnInfo `json:"columns"`
+	Indexes []IndexInfo  `json:"indexes"`
+}
+
+// SchemaDifference 两个快照之间的一处差异
+type SchemaDifference struct {
+	Kind   string // table_missing / table_extra / column_missing / column_extra / column_changed / index_missing / index_extra / index_changed
+	Table  string
+	Name   string // 列名或索引名，表级差异为空
+	Detail string
+}
+
+// String 返回差异的可读描述
+func (d SchemaDifference) String() string {
+	if d.Name == "" {
+		return fmt.Sprintf("%s: %s %s", d.Kind, d.Table, d.Detail)
+	}
+	return fmt.Sprintf("%s: %s.%s %s", d.Kind, d.Table, d.Name, d.Detail)
+}
+
+// SchemaFingerprint 计算数据库结构指纹（表、列类型/可空/主键/自增、索引的 SHA-256），db 为 nil 时使用默认数据库
+// 列注释不参与计算；可在 CI 或服务启动时与期望值比对，迁移未执行时快速失败
+// 示例:
+//
+//	fp, err := eorm.SchemaFingerprint(eorm.Use("main"))
+//	if err == nil && fp != expectedFingerprint {
+//	    log.Fatal("schema drift detected, run migrations first")
+//	}
+func SchemaFingerprint(db *DB) (string, error) {
+	snapshot, err := GetSchemaSnapshot(db)
+	if err != nil {
+		return "", err
+	}
+	return snapshot.Fingerprint, nil
+}
+
+// GetSchemaSnapshot 读取数据库结构快照，db 为 nil 时使用默认数据库
+func GetSchemaSnapshot(db *DB) (*SchemaSnapshot, error) {
+	if db == nil {
+		var err error
+		if db, err = defaultDB(); err != nil {
+			return nil, err
+		}
+	}
+	return db.SchemaSnapshot()
+}
+
+// SchemaSnapshot 读取当前数据库结构快照，表结构直接从数据库读取，不使用元数据缓存
+func (db *DB) SchemaSnapshot() (*SchemaSnapshot, error) {
+	if db.lastErr != nil {
+		return nil, db.lastErr
+	}
+	mgr := db.dbMgr
+	tables, err := mgr.getAllTables()
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &SchemaSnapshot{Tables: make(map[string]TableSchema, len(tables))}
+	for _, table := range tables {
+		mgr.mu.Lock()
+		delete(mgr.columnCache, table)
+		mgr.mu.Unlock()
+
+		columns, err := mgr.getTableColumns(table)
+		if err != nil {
+			return nil, fmt.Errorf("eorm: failed to read columns of %s: %v", table, err)
+		}
+		indexes, err := mgr.getTableIndexes(table)
+		if err != nil {
+			return nil, fmt.Errorf("eorm: failed to read indexes of %s: %v", table, err)
+		}
+		snapshot.Tables[strings.ToLower(table)] = TableSchema{Name: table, Columns: columns, Indexes: indexes}
+	}
+	snapshot.Fingerprint = snapshot.computeFingerprint()
+	return snapshot, nil
+}
+
+// computeFingerprint 按表名、列名、索引名排序后拼接规范化描述并计算哈希
+func (s *SchemaSnapshot) computeFingerprint() string {
+	h := sha256.New()
+	for _, name := range sortedTableNames(s.Tables) {
+		t := s.Tables[name]
+		fmt.Fprintf(h, "T %s\n", name)
+		for _, c := range sortedColumns(t.Columns) {
+			fmt.Fprintf(h, "C %s\n", columnSignature(c))
+		}
+		for _, idx := range sortedIndexes(t.Indexes) {
+			fmt.Fprintf(h, "I %s\n", indexSignature(idx))
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// CompareSchemas 比较期望结构 expected 与实际结构 actual，返回全部差异，结构一致时返回空切片
+// missing 表示 expected 中存在而 actual 中缺失，extra 表示 actual 中多出
+func CompareSchemas(expected, actual *SchemaSnapshot) []SchemaDifference {
+	var diffs []SchemaDifference
+	if expected == nil || actual == nil {
+		return diffs
+	}
+	for _, name := range sortedTableNames(expected.Tables) {
+		et := expected.Tables[name]
+		at, ok := actual.Tables[name]
+		if !ok {
+			diffs = append(diffs, SchemaDifference{Kind: "table_missing", Table: et.Name})
+			continue
+		}
+		diffs = append(diffs, compareTableSchemas(et, at)...)
+	}
+	for _, name := range sortedTableNames(actual.Tables) {
+		if _, ok := expected.Tables[name]; !ok {
+			diffs = append(diffs, SchemaDifference{Kind: "table_extra", Table: actual.Tables[name].Name})
+		}
+	}
+	return diffs
+}
+
+// compareTableSchemas 比较同一张表的列和索引
+func compareTableSchemas(expected, actual TableSchema) []SchemaDifference {
+	var diffs []SchemaDifference
+	actualCols := make(map[string]ColumnInfo, len(actual.Columns))
+	for _, c := range actual.Columns {
+		actualCols[strings.ToLower(c.Name)] = c
+	}
+	for _, c := range sortedColumns(expected.Columns) {
+		ac, ok := actualCols[strings.ToLower(c.Name)]
+		if !ok {
+			diffs = append(diffs, SchemaDifference{Kind: "column_missing", Table: expected.Name, Name: c.Name})
+			continue
+		}
+		delete(actualCols, strings.ToLower(c.Name))
+		if want, got := columnSignature(c), columnSignature(ac); want != got {
+			diffs = append(diffs, SchemaDifference{Kind: "column_changed", Table: expected.Name, Name: c.Name,
+				Detail: fmt.Sprintf("expected %q, got %q", want, got)})
+		}
+	}
+	for _, c := range sortedColumns(actual.Columns) {
+		if _, ok := actualCols[strings.ToLower(c.Name)]; ok {
+			diffs = append(diffs, SchemaDifference{Kind: "column_extra", Table: expected.Name, Name: c.Name})
+		}
+	}
+
+	actualIdx := make(map[string]IndexInfo, len(actual.Indexes))
+	for _, idx := range actual.Indexes {
+		actualIdx[strings.ToLower(idx.Name)] = idx
+	}
+	for _, idx := range sortedIndexes(expected.Indexes) {
+		ai, ok := actualIdx[strings.ToLower(idx.Name)]
+		if !ok {
+			diffs = append(diffs, SchemaDifference{Kind: "index_missing", Table: expected.Name, Name: idx.Name})
+			continue
+		}
+		delete(actualIdx, strings.ToLower(idx.Name))
+		if want, got := indexSignature(idx), indexSignature(ai); want != got {
+			diffs = append(diffs, SchemaDifference{Kind: "index_changed", Table: expected.Name, Name: idx.Name,
+				Detail: fmt.Sprintf("expected %q, got %q", want, got)})
+		}
+	}
+	for _, idx := range sortedIndexes(actual.Indexes) {
+		if _, ok := actualIdx[strings.ToLower(idx.Name)]; ok {
+			diffs = append(diffs, SchemaDifference{Kind: "index_extra", Table: expected.Name, Name: idx.Name})
+		}
+	}
+	return diffs
+}
+
+// columnSignature 列的规范化描述（不含注释）
+func columnSignature(c ColumnInfo) string {
+	return fmt.Sprintf("%s %s null=%t pk=%t auto=%t", strings.ToLower(c.Name), strings.ToLower(c.Type), c.Nullable, c.IsPK, c.IsAutoIncr)
+}
+
+// indexSignature 索引的规范化描述
+func indexSignature(idx IndexInfo) string {
+	cols := make([]string, len(idx.Columns))
+	for i, c := range idx.Columns {
+		cols[i] = strings.ToLower(c)
+	}
+	return fmt.Sprintf("%s unique=%t (%s)", strings.ToLower(idx.Name), idx.Unique, strings.Join(cols, ","))
+}
+
+// sortedTableNames 返回按字典序排序的表名
+func sortedTableNames(tables map[string]TableSchema) []string {
+	keys := make([]string, 0, len(tables))
+	for k := range tables {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// sortedColumns 返回按列名排序的副本
+func sortedColumns(columns []ColumnInfo) []ColumnInfo {
+	sorted := append([]ColumnInfo(nil), columns...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return strings.ToLower(sorted[i].Name) < strings.ToLower(sorted[j].Name)
+	})
+	return sorted
+}
+
+// sortedIndexes 返回按索引名排序的副本
+func sortedIndexes(indexes []IndexInfo) []IndexInfo {
+	sorted := append([]IndexInfo(nil), indexes...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return strings.ToLower(sorted[i].Name) < strings.ToLower(sorted[j].Name)
+	})
+	return sorted
+}
@@ -0,0 +1,140 @@
+package eorm
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ddlVerbRe 匹配会改变表结构的 DDL 语句
+var ddlVerbRe = regexp.MustCompile(`(?i)^\s*(?:CREATE|ALTER|DROP|RENAME|COMMENT\s+ON)\b`)
+
+// ddlTableRes 从 DDL 语句中提取受影响的表名
+var ddlTableRes = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)^\s*(?:CREATE(?:\s+(?:GLOBAL|LOCAL))?(?:\s+(?:TEMPORARY|TEMP))?|ALTER|DROP)\s+TABLE\s+(?:IF\s+(?:NOT\s+)?EXISTS\s+)?([\w.` + "`" + `"\[\]]+)`),
+	regexp.MustCompile(`(?i)^\s*(?:CREATE|DROP)\s+(?:UNIQUE\s+)?INDEX\b.*?\bON\s+([\w.` + "`" + `"\[\]]+)`),
+	regexp.MustCompile(`(?i)^\s*RENAME\s+TABLE\s+([\w.` + "`" + `"\[\]]+)`),
+}
+
+// extractDDLTable 解析 DDL 语句，返回是否为 DDL 以及受影响的表名（无法确定时为空字符串）
+func extractDDLTable(querySQL string) (isDDL bool, table string) {
+	if !ddlVerbRe.MatchString(querySQL) {
+		return false, ""
+	}
+	for _, re := range ddlTableRes {
+		if matches := re.FindStringSubmatch(querySQL); len(matches) == 2 {
+			return true, normalizeDDLTable(matches[1])
+		}
+	}
+	return true, ""
+}
+
+// normalizeDDLTable 去掉引号和 schema 前缀，返回小写表名
+func normalizeDDLTable(name string) string {
+	name = strings.NewReplacer("`", "", `"`, "", "[", "", "]", "").Replace(name)
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	return strings.ToLower(name)
+}
+
+// afterSQLDDL DDL 执行成功后，失效所有指向同一数据库的句柄上该表的预编译语句和表结构缓存，
+// 避免继续使用旧语句导致 "unknown column" 等错误；无法确定表名时失效全部缓存
+func (mgr *dbManager) afterSQLDDL(querySQL string, err error) {
+	if err != nil {
+		return
+	}
+	isDDL, table := extractDDLTable(querySQL)
+	if !isDDL {
+		return
+	}
+	for _, m := range mgr.sameDatabaseManagers() {
+		m.invalidateSchema(table)
+	}
+	LogDebug("DDL 执行后已失效表结构缓存", NewRecord().
+		Set("db", mgr.name).
+		Set("table", table))
+}
+
+// InvalidateSchemaCache 手动失效表的预编译语句和表结构缓存（例如表结构被 eorm 之外的程序修改后），不传表名时失效全部
+func InvalidateSchemaCache(tables ...string) {
+	db, err := defaultDB()
+	if err != nil {
+		return
+	}
+	db.InvalidateSchemaCache(tables...)
+}
+
+// InvalidateSchemaCache 手动失效当前数据库的表结构缓存，见 InvalidateSchemaCache
+func (db *DB) InvalidateSchemaCache(tables ...string) *DB {
+	if db.lastErr != nil {
+		return db
+	}
+	if len(tables) == 0 {
+		tables = []string{""}
+	}
+	for _, m := range db.dbMgr.sameDatabaseManagers() {
+		for _, table := range tables {
+			m.invalidateSchema(normalizeDDLTable(table))
+		}
+	}
+	return db
+}
+
+// sameDatabaseManagers 返回与当前句柄共享连接池或连接串的全部已注册句柄（包括自身）
+func (mgr *dbManager) sameDatabaseManagers() []*dbManager {
+	managers := []*dbManager{mgr}
+	multiMgr.mu.RLock()
+	defer multiMgr.mu.RUnlock()
+	for _, m := range multiMgr.databases {
+		if m == mgr {
+			continue
+		}
+		mConfig, config := m.config.Load(), mgr.config.Load()
+		if mConfig == nil || config == nil {
+			continue
+		}
+		if mdb := m.db.Load(); (mdb != nil && mdb == mgr.db.Load()) || (mConfig.Driver == config.Driver && mConfig.DSN == config.DSN) {
+			managers = append(managers, m)
+		}
+	}
+	return managers
+}
+
+// invalidateSchema 失效表（小写，空字符串表示全部）的表结构缓存和引用该表的预编译语句
+func (mgr *dbManager) invalidateSchema(table string) {
+	mgr.mu.Lock()
+	if table == "" {
+		mgr.pkCache = make(map[string][]string)
+		mgr.identityCache = make(map[string]string)
+		mgr.columnCache = make(map[string][]ColumnInfo)
+	} else {
+		for k := range mgr.pkCache {
+			if normalizeDDLTable(k) == table {
+				delete(mgr.pkCache, k)
+			}
+		}
+		for k := range mgr.identityCache {
+			if normalizeDDLTable(k) == table {
+				delete(mgr.identityCache, k)
+			}
+		}
+		for k := range mgr.columnCache {
+			if normalizeDDLTable(k) == table {
+				delete(mgr.columnCache, k)
+			}
+		}
+	}
+	mgr.mu.Unlock()
+
+	cache := mgr.stmtCache.Load()
+	if cache == nil {
+		return
+	}
+	if table == "" {
+		cache.Clear()
+		return
+	}
+	cache.DeleteMatching(func(query string) bool {
+		return strings.Contains(strings.ToLower(query), table)
+	})
+}
@@ -0,0 +1,67 @@
+package eorm
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EnableSchemaValidation enables Record column validation on write.
+// When enabled, Insert/Update/Save/BatchInsert/BatchUpdate check Record keys against the
+// cached table schema and return "eorm: unknown column 'x' on table t" before any SQL is sent.
+// Default is false (disabled); the schema is fetched once per table and cached.
+func EnableSchemaValidation() {
+	mgr, err := defaultDB()
+	if err != nil {
+		return
+	}
+	mgr.dbMgr.mu.Lock()
+	defer mgr.dbMgr.mu.Unlock()
+	mgr.dbMgr.enableSchemaValidation = true
+}
+
+// EnableSchemaValidation enables Record column validation for this database instance.
+func (db *DB) EnableSchemaValidation() *DB {
+	if db.lastErr != nil {
+		return db
+	}
+	db.dbMgr.mu.Lock()
+	defer db.dbMgr.mu.Unlock()
+	db.dbMgr.enableSchemaValidation = true
+	return db
+}
+
+// validateRecordColumns checks that every key of the record is a column of the table.
+// If the schema cannot be introspected, validation is skipped and the database reports errors as usual.
+func (mgr *dbManager) validateRecordColumns(table string, record *Record) error {
+	if !mgr.enableSchemaValidation || record == nil {
+		return nil
+	}
+	columns, err := mgr.getTableColumns(table)
+	if err != nil || len(columns) == 0 {
+		return nil
+	}
+
+	known := make(map[string]struct{}, len(columns))
+	for _, col := range columns {
+		known[strings.ToLower(col.Name)] = struct{}{}
+	}
+	for _, key := range record.Keys() {
+		if _, ok := known[strings.ToLower(key)]; !ok {
+			return fmt.Errorf("eorm: unknown column '%s' on table %s", key, table)
+		}
+	}
+	return nil
+}
+
+// validateRecordsColumns validates a batch of records, see validateRecordColumns
+func (mgr *dbManager) validateRecordsColumns(table string, records []*Record) error {
+	if !mgr.enableSchemaValidation {
+		return nil
+	}
+	for _, record := range records {
+		if err := mgr.validateRecordColumns(table, record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package eorm
+
+import (
+	"fmt"
+	"sync"
+)
+
+// ScopeFunc 命名作用域函数，向构建器追加公共条件后返回构建器
+// args 为调用 qb.Scope 时传入的参数
+type ScopeFunc func(qb *QueryBuilder, args ...interface{}) *QueryBuilder
+
+// scopeRegistry 全局命名作用域注册表 (name -> ScopeFunc)
+var scopeRegistry sync.Map
+
+// RegisterScope 注册命名作用域，同名作用域会被覆盖
+// 示例:
+//
+//	eorm.RegisterScope("active_users", func(qb *eorm.QueryBuilder, args ...interface{}) *eorm.QueryBuilder {
+//	    return qb.Where("status = ?", 1)
+//	})
+//	eorm.RegisterScope("tenant", func(qb *eorm.QueryBuilder, args ...interface{}) *eorm.QueryBuilder {
+//	    return qb.Where("tenant_id = ?", args...)
+//	})
+//	eorm.Table("users").Scope("active_users").Scope("tenant", tid).Find()
+func RegisterScope(name string, fn ScopeFunc) {
+	if name == "" || fn == nil {
+		return
+	}
+	scopeRegistry.Store(name, fn)
+}
+
+// UnregisterScope 移除命名作用域
+func UnregisterScope(name string) {
+	scopeRegistry.Delete(name)
+}
+
+// HasScope 检查命名作用域是否已注册
+func HasScope(name string) bool {
+	_, ok := scopeRegistry.Load(name)
+	return ok
+}
+
+// Scope 应用已注册的命名作用域
+// 作用域未注册时记录错误，在执行查询时返回
+func (qb *QueryBuilder) Scope(name string, args ...interface{}) *QueryBuilder {
+	if qb.lastErr != nil {
+		return qb
+	}
+	val, ok := scopeRegistry.Load(name)
+	if !ok {
+		qb.lastErr = fmt.Errorf("eorm: scope %q is not registered", name)
+		return qb
+	}
+	if result := val.(ScopeFunc)(qb, args...); result != nil {
+		return result
+	}
+	return qb
+}
@@ -0,0 +1,244 @@
+package eorm
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// SplitSQLScript 将 SQL 脚本拆分为独立的语句
+// 支持的语法：
+//   - 单引号、双引号、反引号中的分隔符不会拆分语句
+//   - -- 行注释、# 行注释（MySQL）和 /* */ 块注释会被去除，但 MySQL 的可执行注释 /*! */ 和优化器提示 /*+ */ 原样保留
+//   - MySQL 的 DELIMITER 命令（如 DELIMITER $$ ... $$ DELIMITER ;）
+//   - PostgreSQL 的美元引用函数体（$$ ... $$ 或 $tag$ ... $tag$）
+//   - 单独一行的 / （Oracle）和 GO（SQL Server）作为额外的语句结束符
+//
+// 包含内部分号的 Oracle PL/SQL 块或 SQL Server 存储过程需要先用 DELIMITER 切换分隔符
+func SplitSQLScript(script string) []string {
+	var statements []string
+	var current strings.Builder
+	delimiter := ";"
+
+	flush := func() {
+		stmt := strings.TrimSpace(current.String())
+		if stmt != "" {
+			statements = append(statements, stmt)
+		}
+		current.Reset()
+	}
+
+	n := len(script)
+	for i := 0; i < n; {
+		// 行首命令：DELIMITER、/、GO
+		if i == 0 || script[i-1] == '\n' {
+			lineEnd := strings.IndexByte(script[i:], '\n')
+			if lineEnd < 0 {
+				lineEnd = n - i
+			}
+			line := strings.TrimSpace(script[i : i+lineEnd])
+			upper := strings.ToUpper(line)
+			if strings.HasPrefix(upper, "DELIMITER ") || upper == "DELIMITER" {
+				flush()
+				if fields := strings.Fields(line); len(fields) > 1 {
+					delimiter = fields[1]
+				}
+				i += lineEnd
+				continue
+			}
+			if (line == "/" || upper == "GO") && strings.TrimSpace(current.String()) != "" {
+				flush()
+				i += lineEnd
+				continue
+			}
+		}
+
+		c := script[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			end := skipQuoted(script, i, c)
+			current.WriteString(script[i:end])
+			i = end
+			continue
+		case c == '-' && i+1 < n && script[i+1] == '-', c == '#' && isLineStart(script, i):
+			for i < n && script[i] != '\n' {
+				i++
+			}
+			continue
+		case c == '/' && i+1 < n && script[i+1] == '*':
+			stop := n
+			if end := strings.Index(script[i+2:], "*/"); end >= 0 {
+				stop = i + end + 4
+			}
+			if i+2 < n && (script[i+2] == '!' || script[i+2] == '+') {
+				// 可执行注释和优化器提示是语句的一部分
+				current.WriteString(script[i:stop])
+			} else {
+				current.WriteByte(' ')
+			}
+			i = stop
+			continue
+		case c == '$' && delimiter == ";":
+			if tag, ok := dollarQuoteTag(script, i); ok {
+				end := strings.Index(script[i+len(tag):], tag)
+				if end < 0 {
+					current.WriteString(script[i:])
+					i = n
+				} else {
+					stop := i + len(tag) + end + len(tag)
+					current.WriteString(script[i:stop])
+					i = stop
+				}
+				continue
+			}
+		}
+
+		if strings.HasPrefix(script[i:], delimiter) {
+			flush()
+			i += len(delimiter)
+			continue
+		}
+		current.WriteByte(c)
+		i++
+	}
+	flush()
+	return statements
+}
+
+// skipQuoted 返回从 start 处的引号开始、到匹配的结束引号之后的位置
+// 连续两个引号视为转义，单引号字符串中的反斜杠转义也会被跳过
+func skipQuoted(script string, start int, quote byte) int {
+	for i := start + 1; i < len(script); i++ {
+		switch script[i] {
+		case '\\':
+			if quote == '\'' {
+				i++
+			}
+		case quote:
+			if i+1 < len(script) && script[i+1] == quote {
+				i++
+				continue
+			}
+			return i + 1
+		}
+	}
+	return len(script)
+}
+
+// dollarQuoteTag 识别 PostgreSQL 美元引用的起始标记（$$ 或 $tag$）
+func dollarQuoteTag(script string, start int) (string, bool) {
+	// $1 这样的位置参数不是美元引用
+	if start > 0 && isIdentChar(script[start-1]) {
+		return "", false
+	}
+	for i := start + 1; i < len(script); i++ {
+		c := script[i]
+		if c == '$' {
+			return script[start : i+1], true
+		}
+		if !isIdentChar(c) || (i == start+1 && c >= '0' && c <= '9') {
+			return "", false
+		}
+	}
+	return "", false
+}
+
+func isIdentChar(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// isLineStart 判断位置 i 之前的同一行内容是否全为空白
+func isLineStart(script string, i int) bool {
+	for j := i - 1; j >= 0 && script[j] != '\n'; j-- {
+		if script[j] != ' ' && script[j] != '\t' && script[j] != '\r' {
+			return false
+		}
+	}
+	return true
+}
+
+// readSQLScript 读取脚本内容
+func readSQLScript(r io.Reader) ([]string, error) {
+	if r == nil {
+		return nil, fmt.Errorf("eorm: script reader is nil")
+	}
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("eorm: failed to read sql script: %w", err)
+	}
+	statements := SplitSQLScript(string(content))
+	if len(statements) == 0 {
+		return nil, fmt.Errorf("eorm: sql script contains no statements")
+	}
+	return statements, nil
+}
+
+// ExecScript 执行 SQL 脚本（建表、初始化数据、迁移等），脚本按 SplitSQLScript 的规则拆分后通过 BatchExec 执行
+// 示例:
+//
+//	f, _ := os.Open("schema.sql")
+//	defer f.Close()
+//	results, err := eorm.ExecScript(f)
+func ExecScript(r io.Reader) ([]StatementResult, error) {
+	db, err := defaultDB()
+	if err != nil {
+		return nil, err
+	}
+	return db.ExecScript(r)
+}
+
+// ExecScriptFile 执行 SQL 脚本文件
+// 示例: eorm.ExecScriptFile("migrations/001_init.sql")
+func ExecScriptFile(path string) ([]StatementResult, error) {
+	db, err := defaultDB()
+	if err != nil {
+		return nil, err
+	}
+	return db.ExecScriptFile(path)
+}
+
+// ExecScript 执行 SQL 脚本
+func (db *DB) ExecScript(r io.Reader) ([]StatementResult, error) {
+	if db.lastErr != nil {
+		return nil, db.lastErr
+	}
+	statements, err := readSQLScript(r)
+	if err != nil {
+		return nil, err
+	}
+	return db.BatchExec(statements)
+}
+
+// ExecScriptFile 执行 SQL 脚本文件
+func (db *DB) ExecScriptFile(path string) ([]StatementResult, error) {
+	if db.lastErr != nil {
+		return nil, db.lastErr
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("eorm: failed to open sql script: %w", err)
+	}
+	defer f.Close()
+	return db.ExecScript(f)
+}
+
+// ExecScript 在事务中执行 SQL 脚本，遇到失败语句时停止（可配合 ContinueOnError 使用）
+// 注意：MySQL/Oracle 的 DDL 会隐式提交事务
+func (tx *Tx) ExecScript(r io.Reader) ([]StatementResult, error) {
+	statements, err := readSQLScript(r)
+	if err != nil {
+		return nil, err
+	}
+	return tx.BatchExec(statements)
+}
+
+// ExecScriptFile 在事务中执行 SQL 脚本文件
+func (tx *Tx) ExecScriptFile(path string) ([]StatementResult, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("eorm: failed to open sql script: %w", err)
+	}
+	defer f.Close()
+	return tx.ExecScript(f)
+}
@@ -0,0 +1,28 @@
+package eorm
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitSQLScriptComments(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   []string
+	}{
+		{"StripsComments", "-- header\nSELECT 1; /* block; comment */ SELECT 2;\n# mysql\nSELECT 3",
+			[]string{"SELECT 1", "SELECT 2", "SELECT 3"}},
+		{"KeepsExecutableComments", "/*!40101 SET NAMES utf8mb4 */;\n/*!50003 CREATE TRIGGER t BEFORE INSERT ON a FOR EACH ROW SET NEW.x = 1; */;",
+			[]string{"/*!40101 SET NAMES utf8mb4 */", "/*!50003 CREATE TRIGGER t BEFORE INSERT ON a FOR EACH ROW SET NEW.x = 1; */"}},
+		{"KeepsOptimizerHints", "SELECT /*+ MAX_EXECUTION_TIME(1000) */ * FROM users; DELETE /*+ INDEX(a idx) */ FROM a",
+			[]string{"SELECT /*+ MAX_EXECUTION_TIME(1000) */ * FROM users", "DELETE /*+ INDEX(a idx) */ FROM a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SplitSQLScript(tt.script); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("SplitSQLScript = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
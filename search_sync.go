@@ -0,0 +1,578 @@
+package eorm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SearchDocument 推送到搜索引擎的一个文档
+type SearchDocument struct {
+	ID     string                 // 文档 ID（由主键或 IDColumn 生成）
+	Fields map[string]interface{} // 文档字段
+}
+
+// SearchIndexer 搜索引擎适配器，内置 Elasticsearch 和 Meilisearch，其他引擎（如 Bleve）实现该接口即可
+type SearchIndexer interface {
+	Upsert(ctx context.Context, index string, docs []SearchDocument) error // 写入或覆盖文档
+	Delete(ctx context.Context, index string, ids []string) error          // 删除文档，不存在的 ID 应忽略
+}
+
+// SearchIndexConfig 一个表到搜索索引的映射
+type SearchIndexConfig struct {
+	Table     string                                            // 表名
+	Index     string                                            // 索引名（默认与表名相同）
+	IDColumn  string                                            // 文档 ID 列（默认使用主键，复合主键用 "-" 连接）
+	Columns   map[string]string                                 // 列名 -> 字段名，为空表示所有列、字段名与列名相同
+	Transform func(row *Record) (map[string]interface{}, error) // 自定义文档内容，设置后忽略 Columns；返回 nil 表示从索引中删除该行
+}
+
+// SearchSyncOptions 搜索索引同步的批量和重试选项
+type SearchSyncOptions struct {
+	BatchSize     int             // 累计多少个变更后立即推送（默认 100）
+	FlushInterval time.Duration   // 未达到 BatchSize 时的推送间隔（默认 1 秒）
+	MaxRetries    int             // 推送失败后的重试次数（默认 3，<0 表示不重试）
+	RetryBackoff  time.Duration   // 首次重试的等待时间，之后每次翻倍（默认 500ms）
+	Timeout       time.Duration   // 单次推送的超时时间（默认 30 秒）
+	OnError       func(err error) // 重试耗尽后的回调，该批变更被丢弃
+}
+
+// searchOp 一个待推送的文档变更，doc 为 nil 表示删除
+type searchOp struct {
+	id  string
+	doc map[string]interface{}
+}
+
+// searchPending 一个索引待推送的变更，同一文档只保留最后一次变更
+type searchPending struct {
+	ops   map[string]searchOp
+	order []string
+}
+
+// SearchSync 将表的变更同步到搜索引擎，由 StartSearchSync 创建
+type SearchSync struct {
+	mgr         *dbManager
+	indexer     SearchIndexer
+	opts        SearchSyncOptions
+	configs     map[string]*SearchIndexConfig // table(lower) -> config
+	unsubscribe []func()
+
+	mu      sync.Mutex
+	pending map[string]*searchPending // index -> 待推送的变更
+	count   int
+	flushMu sync.Mutex // 串行化推送，保证同一文档的变更按顺序到达
+	kick    chan struct{}
+	stop    chan struct{}
+	done    chan struct{}
+	closed  bool
+}
+
+// StartSearchSync 订阅表的变更事件（见 Subscribe），按配置映射为文档后批量推送到搜索引擎，失败时按指数退避重试
+// 插入和更新写入文档，删除（包括软删除）删除文档；同一批次中同一文档只推送最后一次变更
+// 只同步通过 eorm 执行的写操作，已有数据可以用 Reindex 全量导入
+// 示例:
+//
+//	syncer, err := eorm.StartSearchSync(eorm.NewElasticsearchIndexer("http://localhost:9200"), []eorm.SearchIndexConfig{
+//	    {Table: "products", Index: "products", Columns: map[string]string{"name": "title", "price": "price"}},
+//	}, &eorm.SearchSyncOptions{BatchSize: 500, FlushInterval: 2 * time.Second})
+//	if err != nil {
+//	    return err
+//	}
+//	defer syncer.Close()
+//	syncer.Reindex("products") // 首次启动时全量导入
+func StartSearchSync(indexer SearchIndexer, configs []SearchIndexConfig, opts *SearchSyncOptions) (*SearchSync, error) {
+	db, err := defaultDB()
+	if err != nil {
+		return nil, err
+	}
+	return db.StartSearchSync(indexer, configs, opts)
+}
+
+// StartSearchSync 同步当前数据库中表的变更到搜索引擎，见 StartSearchSync
+func (db *DB) StartSearchSync(indexer SearchIndexer, configs []SearchIndexConfig, opts *SearchSyncOptions) (*SearchSync, error) {
+	if db.lastErr != nil {
+		return nil, db.lastErr
+	}
+	if indexer == nil {
+		return nil, fmt.Errorf("eorm: StartSearchSync requires an indexer")
+	}
+	if len(configs) == 0 {
+		return nil, fmt.Errorf("eorm: StartSearchSync requires at least one table")
+	}
+	s := &SearchSync{
+		mgr:     db.dbMgr,
+		indexer: indexer,
+		configs: make(map[string]*SearchIndexConfig, len(configs)),
+		pending: make(map[string]*searchPending),
+		kick:    make(chan struct{}, 1),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	if opts != nil {
+		s.opts = *opts
+	}
+	if s.opts.BatchSize <= 0 {
+		s.opts.BatchSize = DefaultBatchSize
+	}
+	if s.opts.FlushInterval <= 0 {
+		s.opts.FlushInterval = time.Second
+	}
+	if s.opts.MaxRetries == 0 {
+		s.opts.MaxRetries = 3
+	}
+	if s.opts.RetryBackoff <= 0 {
+		s.opts.RetryBackoff = 500 * time.Millisecond
+	}
+	if s.opts.Timeout <= 0 {
+		s.opts.Timeout = 30 * time.Second
+	}
+	for i := range configs {
+		c := configs[i]
+		if err := validateIdentifier(c.Table); err != nil {
+			return nil, err
+		}
+		if c.Index == "" {
+			c.Index = c.Table
+		}
+		s.configs[strings.ToLower(c.Table)] = &c
+	}
+
+	for _, c := range s.configs {
+		s.unsubscribe = append(s.unsubscribe, db.Subscribe(c.Table, s.onChange))
+	}
+	go s.run()
+	return s, nil
+}
+
+// Flush 立即推送所有待推送的变更
+func (s *SearchSync) Flush() error {
+	s.flushMu.Lock()
+	defer s.flushMu.Unlock()
+	s.mu.Lock()
+	pending := s.pending
+	s.pending = make(map[string]*searchPending)
+	s.count = 0
+	s.mu.Unlock()
+
+	var firstErr error
+	for index, p := range pending {
+		var docs []SearchDocument
+		var deletes []string
+		for _, id := range p.order {
+			op := p.ops[id]
+			if op.doc == nil {
+				deletes = append(deletes, id)
+			} else {
+				docs = append(docs, SearchDocument{ID: id, Fields: op.doc})
+			}
+		}
+		if len(docs) > 0 {
+			if err := s.push(func(ctx context.Context) error { return s.indexer.Upsert(ctx, index, docs) }); err != nil {
+				s.reportError(fmt.Errorf("eorm: search index %s upsert of %d documents failed: %w", index, len(docs), err))
+				if firstErr == nil {
+					firstErr = err
+				}
+			}
+		}
+		if len(deletes) > 0 {
+			if err := s.push(func(ctx context.Context) error { return s.indexer.Delete(ctx, index, deletes) }); err != nil {
+				s.reportError(fmt.Errorf("eorm: search index %s delete of %d documents failed: %w", index, len(deletes), err))
+				if firstErr == nil {
+					firstErr = err
+				}
+			}
+		}
+	}
+	return firstErr
+}
+
+// Reindex 读取表中的全部行并按批次写入搜索索引，用于首次同步或修复索引（不会删除索引中多余的文档）
+func (s *SearchSync) Reindex(table string) error {
+	c := s.configs[strings.ToLower(table)]
+	if c == nil {
+		return fmt.Errorf("eorm: table %s is not configured for search sync", table)
+	}
+	sqlDB, err := s.mgr.getDB()
+	if err != nil {
+		return err
+	}
+	pks, err := s.mgr.getPrimaryKeys(sqlDB, c.Table)
+	if err != nil {
+		return err
+	}
+	var docs []SearchDocument
+	var deletes []string
+	flush := func() error {
+		s.flushMu.Lock()
+		defer s.flushMu.Unlock()
+		if len(docs) > 0 {
+			if err := s.push(func(ctx context.Context) error { return s.indexer.Upsert(ctx, c.Index, docs) }); err != nil {
+				return err
+			}
+		}
+		if len(deletes) > 0 {
+			if err := s.push(func(ctx context.Context) error { return s.indexer.Delete(ctx, c.Index, deletes) }); err != nil {
+				return err
+			}
+		}
+		docs, deletes = nil, nil
+		return nil
+	}
+	err = s.mgr.dumpRows(c.Table, func(columns []string, values []interface{}) error {
+		row := NewRecord()
+		for i, col := range columns {
+			row.Set(col, values[i])
+		}
+		id := searchDocumentID(c, pks, row)
+		if id == "" {
+			return nil
+		}
+		doc, err := searchDocumentFields(c, row)
+		if err != nil {
+			return err
+		}
+		if doc == nil {
+			deletes = append(deletes, id)
+		} else {
+			docs = append(docs, SearchDocument{ID: id, Fields: doc})
+		}
+		if len(docs)+len(deletes) >= s.opts.BatchSize {
+			return flush()
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("eorm: reindex of %s failed: %w", table, err)
+	}
+	if err := flush(); err != nil {
+		return fmt.Errorf("eorm: reindex of %s failed: %w", table, err)
+	}
+	return nil
+}
+
+// Close 取消订阅，推送剩余的变更后停止
+func (s *SearchSync) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	s.mu.Unlock()
+	for _, unsubscribe := range s.unsubscribe {
+		unsubscribe()
+	}
+	close(s.stop)
+	<-s.done
+	return s.Flush()
+}
+
+// onChange 把变更事件转换为待推送的文档变更
+func (s *SearchSync) onChange(evt ChangeEvent) {
+	c := s.configs[strings.ToLower(evt.Table)]
+	if c == nil {
+		return
+	}
+	row := evt.New
+	if evt.Op == ChangeDelete || row == nil {
+		row = evt.Old
+	}
+	var pks []string
+	if evt.PrimaryKey != nil {
+		pks = evt.PrimaryKey.Keys()
+	}
+	id := searchDocumentID(c, pks, row)
+	if id == "" {
+		return
+	}
+	op := searchOp{id: id}
+	if evt.Op != ChangeDelete && evt.New != nil {
+		doc, err := searchDocumentFields(c, evt.New)
+		if err != nil {
+			s.reportError(fmt.Errorf("eorm: search document for %s %s failed: %w", evt.Table, id, err))
+			return
+		}
+		op.doc = doc
+	}
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	p := s.pending[c.Index]
+	if p == nil {
+		p = &searchPending{ops: make(map[string]searchOp)}
+		s.pending[c.Index] = p
+	}
+	if _, exists := p.ops[id]; !exists {
+		p.order = append(p.order, id)
+		s.count++
+	}
+	p.ops[id] = op
+	full := s.count >= s.opts.BatchSize
+	s.mu.Unlock()
+
+	if full {
+		select {
+		case s.kick <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// run 定时或在达到批量大小时推送
+func (s *SearchSync) run() {
+	defer close(s.done)
+	ticker := time.NewTicker(s.opts.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		case <-s.kick:
+		}
+		s.Flush()
+	}
+}
+
+// push 执行一次推送，失败时按指数退避重试
+func (s *SearchSync) push(fn func(ctx context.Context) error) error {
+	backoff := s.opts.RetryBackoff
+	var err error
+	for attempt := 0; ; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), s.opts.Timeout)
+		err = fn(ctx)
+		cancel()
+		if err == nil || attempt >= s.opts.MaxRetries {
+			return err
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// reportError 记录推送失败
+func (s *SearchSync) reportError(err error) {
+	LogError("搜索索引同步失败", NewRecord().Set("db", s.mgr.name).Set("error", err.Error()))
+	if s.opts.OnError != nil {
+		s.opts.OnError(err)
+	}
+}
+
+// searchDocumentID 生成文档 ID，取不到时返回空字符串
+func searchDocumentID(c *SearchIndexConfig, pks []string, row *Record) string {
+	if row == nil {
+		return ""
+	}
+	if c.IDColumn != "" {
+		pks = []string{c.IDColumn}
+	}
+	if len(pks) == 0 {
+		return ""
+	}
+	parts := make([]string, len(pks))
+	for i, pk := range pks {
+		v := row.Get(pk)
+		if v == nil {
+			return ""
+		}
+		parts[i] = fmt.Sprint(v)
+	}
+	return strings.Join(parts, "-")
+}
+
+// searchDocumentFields 按映射生成文档字段
+func searchDocumentFields(c *SearchIndexConfig, row *Record) (map[string]interface{}, error) {
+	if c.Transform != nil {
+		return c.Transform(row)
+	}
+	if len(c.Columns) == 0 {
+		doc := make(map[string]interface{})
+		for _, col := range row.Keys() {
+			doc[col] = row.Get(col)
+		}
+		return doc, nil
+	}
+	doc := make(map[string]interface{}, len(c.Columns))
+	for col, field := range c.Columns {
+		if field == "" {
+			field = col
+		}
+		doc[field] = row.Get(col)
+	}
+	return doc, nil
+}
+
+// --- Elasticsearch ---
+
+// ElasticsearchIndexer 通过 Bulk API 写入 Elasticsearch（或 OpenSearch）
+type ElasticsearchIndexer struct {
+	URL      string       // 集群地址，如 http://localhost:9200
+	Username string       // Basic 认证用户名（可选）
+	Password string       // Basic 认证密码（可选）
+	APIKey   string       // API Key 认证（可选，优先于 Basic 认证）
+	Refresh  string       // 写入后的 refresh 参数，如 "wait_for"（默认不设置）
+	Client   *http.Client // HTTP 客户端（默认 http.DefaultClient）
+}
+
+// NewElasticsearchIndexer 创建 Elasticsearch 适配器
+func NewElasticsearchIndexer(baseURL string) *ElasticsearchIndexer {
+	return &ElasticsearchIndexer{URL: baseURL}
+}
+
+// Upsert 写入或覆盖文档
+func (e *ElasticsearchIndexer) Upsert(ctx context.Context, index string, docs []SearchDocument) error {
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	for _, doc := range docs {
+		enc.Encode(map[string]interface{}{"index": map[string]string{"_index": index, "_id": doc.ID}})
+		if err := enc.Encode(doc.Fields); err != nil {
+			return fmt.Errorf("document %s: %w", doc.ID, err)
+		}
+	}
+	return e.bulk(ctx, &body)
+}
+
+// Delete 删除文档
+func (e *ElasticsearchIndexer) Delete(ctx context.Context, index string, ids []string) error {
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	for _, id := range ids {
+		enc.Encode(map[string]interface{}{"delete": map[string]string{"_index": index, "_id": id}})
+	}
+	return e.bulk(ctx, &body)
+}
+
+// bulk 发送 Bulk 请求，检查每个条目的结果（删除不存在的文档不视为错误）
+func (e *ElasticsearchIndexer) bulk(ctx context.Context, body *bytes.Buffer) error {
+	endpoint := strings.TrimRight(e.URL, "/") + "/_bulk"
+	if e.Refresh != "" {
+		endpoint += "?refresh=" + url.QueryEscape(e.Refresh)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	if e.APIKey != "" {
+		req.Header.Set("Authorization", "ApiKey "+e.APIKey)
+	} else if e.Username != "" {
+		req.SetBasicAuth(e.Username, e.Password)
+	}
+	respBody, err := doSearchRequest(e.Client, req)
+	if err != nil {
+		return err
+	}
+	var result struct {
+		Errors bool `json:"errors"`
+		Items  []map[string]struct {
+			ID     string          `json:"_id"`
+			Status int             `json:"status"`
+			Error  json.RawMessage `json:"error"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return fmt.Errorf("invalid bulk response: %w", err)
+	}
+	if !result.Errors {
+		return nil
+	}
+	for _, item := range result.Items {
+		for action, r := range item {
+			if r.Status < 300 || (action == "delete" && r.Status == http.StatusNotFound) {
+				continue
+			}
+			return fmt.Errorf("%s %s failed with status %d: %s", action, r.ID, r.Status, r.Error)
+		}
+	}
+	return nil
+}
+
+// --- Meilisearch ---
+
+// MeilisearchIndexer 通过 Documents API 写入 Meilisearch，写入是异步任务，接口返回成功表示任务已入队
+type MeilisearchIndexer struct {
+	URL        string       // 服务地址，如 http://localhost:7700
+	APIKey     string       // API Key（可选）
+	PrimaryKey string       // 文档主键字段名（默认 "id"），文档 ID 写入该字段
+	Client     *http.Client // HTTP 客户端（默认 http.DefaultClient）
+}
+
+// NewMeilisearchIndexer 创建 Meilisearch 适配器
+func NewMeilisearchIndexer(baseURL, apiKey string) *MeilisearchIndexer {
+	return &MeilisearchIndexer{URL: baseURL, APIKey: apiKey}
+}
+
+// Upsert 写入或覆盖文档
+func (m *MeilisearchIndexer) Upsert(ctx context.Context, index string, docs []SearchDocument) error {
+	primaryKey := m.PrimaryKey
+	if primaryKey == "" {
+		primaryKey = "id"
+	}
+	payload := make([]map[string]interface{}, len(docs))
+	for i, doc := range docs {
+		fields := make(map[string]interface{}, len(doc.Fields)+1)
+		for k, v := range doc.Fields {
+			fields[k] = v
+		}
+		fields[primaryKey] = doc.ID
+		payload[i] = fields
+	}
+	return m.post(ctx, "/indexes/"+url.PathEscape(index)+"/documents?primaryKey="+url.QueryEscape(primaryKey), payload)
+}
+
+// Delete 删除文档
+func (m *MeilisearchIndexer) Delete(ctx context.Context, index string, ids []string) error {
+	return m.post(ctx, "/indexes/"+url.PathEscape(index)+"/documents/delete-batch", ids)
+}
+
+// post 发送 JSON 请求
+func (m *MeilisearchIndexer) post(ctx context.Context, path string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(m.URL, "/")+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if m.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+m.APIKey)
+	}
+	_, err = doSearchRequest(m.Client, req)
+	return err
+}
+
+// doSearchRequest 发送请求并读取响应，非 2xx 状态返回错误
+func doSearchRequest(client *http.Client, req *http.Request) ([]byte, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		msg := strings.TrimSpace(string(body))
+		if len(msg) > 512 {
+			msg = msg[:512]
+		}
+		return nil, fmt.Errorf("%s %s: status %d: %s", req.Method, req.URL.Path, resp.StatusCode, msg)
+	}
+	return body, nil
+}
@@ -0,0 +1,179 @@
+package eorm
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SequenceConfig holds the sequence configuration for a table
+type SequenceConfig struct {
+	SequenceName string // Sequence name, e.g., "DEMO_SEQ"
+	Field        string // Column filled from the sequence, empty means the single primary key
+}
+
+// sequenceRegistry stores sequence configurations per database
+type sequenceRegistry struct {
+	configs map[string]*SequenceConfig // table -> config
+	mu      sync.RWMutex
+}
+
+// newSequenceRegistry creates a new sequence registry
+func newSequenceRegistry() *sequenceRegistry {
+	return &sequenceRegistry{
+		configs: make(map[string]*SequenceConfig),
+	}
+}
+
+// set configures a sequence for a table
+func (r *sequenceRegistry) set(table string, config *SequenceConfig) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.configs[strings.ToLower(table)] = config
+}
+
+// get returns the sequence config for a table
+func (r *sequenceRegistry) get(table string) *SequenceConfig {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.configs[strings.ToLower(table)]
+}
+
+// remove removes sequence config for a table
+func (r *sequenceRegistry) remove(table string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.configs, strings.ToLower(table))
+}
+
+// --- Global Functions (for default database) ---
+
+// ConfigSequence configures a sequence for a table so InsertRecord fetches NEXTVAL
+// when the record has no primary key value and returns the generated ID.
+// field is optional and defaults to the table's single primary key.
+// Supported by Oracle, PostgreSQL and SQL Server.
+// Example: eorm.ConfigSequence("DEMO", "DEMO_SEQ")
+func ConfigSequence(table, sequenceName string, field ...string) {
+	db, err := defaultDB()
+	if err != nil {
+		return
+	}
+	db.ConfigSequence(table, sequenceName, field...)
+}
+
+// RemoveSequence removes sequence configuration for a table
+func RemoveSequence(table string) {
+	db, err := defaultDB()
+	if err != nil {
+		return
+	}
+	db.RemoveSequence(table)
+}
+
+// --- DB Methods ---
+
+// ConfigSequence configures a sequence for a table
+func (db *DB) ConfigSequence(table, sequenceName string, field ...string) *DB {
+	if db.lastErr != nil || db.dbMgr == nil {
+		return db
+	}
+	if err := validateIdentifier(sequenceName); err != nil {
+		db.lastErr = err
+		return db
+	}
+
+	config := &SequenceConfig{SequenceName: sequenceName}
+	if len(field) > 0 && field[0] != "" {
+		config.Field = field[0]
+		if !db.dbMgr.checkTableColumn(table, config.Field) {
+			LogWarn(fmt.Sprintf("序列配置警告: 表 '%s' 中不存在字段 '%s'", table, config.Field), NewRecord().
+				Set("db", db.dbMgr.name).
+				Set("table", table).
+				Set("field", config.Field))
+		}
+	}
+	db.dbMgr.setSequenceConfig(table, config)
+	return db
+}
+
+// RemoveSequence removes sequence configuration for a table
+func (db *DB) RemoveSequence(table string) *DB {
+	if db.lastErr != nil || db.dbMgr == nil {
+		return db
+	}
+	db.dbMgr.removeSequenceConfig(table)
+	return db
+}
+
+// --- dbManager Methods ---
+
+// setSequenceConfig sets sequence config for a table
+func (mgr *dbManager) setSequenceConfig(table string, config *SequenceConfig) {
+	if mgr.sequences == nil {
+		mgr.sequences = newSequenceRegistry()
+	}
+	mgr.sequences.set(table, config)
+}
+
+// getSequenceConfig gets sequence config for a table
+func (mgr *dbManager) getSequenceConfig(table string) *SequenceConfig {
+	if mgr.sequences == nil {
+		return nil
+	}
+	return mgr.sequences.get(table)
+}
+
+// removeSequenceConfig removes sequence config for a table
+func (mgr *dbManager) removeSequenceConfig(table string) {
+	if mgr.sequences == nil {
+		return
+	}
+	mgr.sequences.remove(table)
+}
+
+// applySequence fills the sequence column from NEXTVAL if configured and the record has no value.
+// The record then carries the primary key, so the insert paths return it as the generated ID.
+func (mgr *dbManager) applySequence(executor sqlExecutor, table string, record *Record) error {
+	config := mgr.getSequenceConfig(table)
+	if config == nil {
+		return nil
+	}
+
+	field := config.Field
+	if field == "" {
+		pks, err := mgr.getPrimaryKeys(executor, table)
+		if err != nil {
+			return err
+		}
+		if len(pks) != 1 {
+			return fmt.Errorf("eorm: sequence on table %s requires a single primary key or an explicit field", table)
+		}
+		field = pks[0]
+	}
+	if record.Get(field) != nil {
+		return nil
+	}
+
+	var querySQL string
+	switch mgr.config.Load().Driver {
+	case Oracle:
+		querySQL = fmt.Sprintf("SELECT %s.NEXTVAL FROM DUAL", config.SequenceName)
+	case PostgreSQL:
+		querySQL = fmt.Sprintf("SELECT nextval('%s')", config.SequenceName)
+	case SQLServer:
+		querySQL = fmt.Sprintf("SELECT NEXT VALUE FOR %s", config.SequenceName)
+	default:
+		return fmt.Errorf("eorm: sequences are not supported for driver %s", mgr.config.Load().Driver)
+	}
+
+	var id int64
+	start := time.Now()
+	err := executor.QueryRow(querySQL).Scan(&id)
+	mgr.logTrace(start, querySQL, nil, err)
+	if err != nil {
+		return fmt.Errorf("eorm: failed to get next value of sequence %s: %w", config.SequenceName, err)
+	}
+	record.Set(field, id)
+	return nil
+}
@@ -0,0 +1,66 @@
+package eorm
+
+import "database/sql"
+
+// Session 数据访问接口，*DB 和 *Tx 都实现了该接口
+// 服务层代码接收 Session 参数即可在事务内外不加修改地运行，也便于在测试中 mock
+// 示例:
+//
+//	func CreateOrder(s eorm.Session, order *eorm.Record) error {
+//	    _, err := s.InsertRecord("orders", order)
+//	    return err
+//	}
+//
+//	CreateOrder(eorm.Use("default"), order)          // 非事务
+//	eorm.Transaction(func(tx *eorm.Tx) error {       // 事务
+//	    return CreateOrder(tx, order)
+//	})
+type Session interface {
+	// 查询
+	Query(querySQL string, args ...interface{}) ([]*Record, error)
+	QueryFirst(querySQL string, args ...interface{}) (*Record, error)
+	QueryMap(querySQL string, args ...interface{}) ([]map[string]interface{}, error)
+	QueryToDbModel(dest interface{}, querySQL string, args ...interface{}) error
+	QueryFirstToDbModel(dest interface{}, querySQL string, args ...interface{}) error
+	QueryWithOutTrashed(querySQL string, args ...interface{}) ([]*Record, error)
+	QueryFirstWithOutTrashed(querySQL string, args ...interface{}) (*Record, error)
+	FindAll(table string) ([]*Record, error)
+	FindToDbModel(dest interface{}, table string, whereSql string, orderBySql string, whereArgs ...interface{}) error
+	FindFirstToDbModel(model IDbModel, whereSql string, whereArgs ...interface{}) error
+	Count(table string, whereSql string, whereArgs ...interface{}) (int64, error)
+	Exists(table string, whereSql string, whereArgs ...interface{}) (bool, error)
+	Paginate(page int, pageSize int, querySQL string, args ...interface{}) (*Page[*Record], error)
+	PaginateBuilder(page int, pageSize int, selectSql string, table string, whereSql string, orderBySql string, args ...interface{}) (*Page[*Record], error)
+	Table(name string) *QueryBuilder
+	SqlTemplate(name string, params ...interface{}) *SqlTemplateBuilder
+
+	// 执行
+	Exec(querySQL string, args ...interface{}) (sql.Result, error)
+	BatchExec(sqls []string, args ...[]interface{}) ([]StatementResult, error)
+
+	// 写入
+	InsertRecord(table string, record *Record) (int64, error)
+	InsertRecordIgnore(table string, record *Record) (bool, error)
+	SaveRecord(table string, record *Record) (int64, error)
+	Update(table string, record *Record, whereSql string, whereArgs ...interface{}) (int64, error)
+	UpdateRecord(table string, record *Record) (int64, error)
+	Delete(table string, whereSql string, whereArgs ...interface{}) (int64, error)
+	DeleteRecord(table string, record *Record) (int64, error)
+	ForceDelete(table string, whereSql string, whereArgs ...interface{}) (int64, error)
+	Restore(table string, whereSql string, whereArgs ...interface{}) (int64, error)
+	BatchInsertRecord(table string, records []*Record, batchSize ...int) (int64, error)
+	BatchUpdateRecord(table string, records []*Record, batchSize ...int) (int64, error)
+	BatchDeleteRecord(table string, records []*Record, batchSize ...int) (int64, error)
+	BatchDeleteByIds(table string, ids []interface{}, batchSize ...int) (int64, error)
+
+	// DbModel
+	InsertDbModel(model IDbModel) (int64, error)
+	SaveDbModel(model IDbModel) (int64, error)
+	UpdateDbModel(model IDbModel) (int64, error)
+	DeleteDbModel(model IDbModel) (int64, error)
+}
+
+var (
+	_ Session = (*DB)(nil)
+	_ Session = (*Tx)(nil)
+)
@@ -0,0 +1,98 @@
+package eorm
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"strings"
+)
+
+// isExpandableSlice 判断参数是否为需要展开为多个占位符的切片
+// []byte 作为二进制值绑定，实现了 driver.Valuer 的类型（如 pq.Array）交给驱动处理，都不展开
+func isExpandableSlice(arg interface{}) bool {
+	if arg == nil {
+		return false
+	}
+	switch arg.(type) {
+	case []byte, driver.Valuer:
+		return false
+	}
+	kind := reflect.TypeOf(arg).Kind()
+	return kind == reflect.Slice || kind == reflect.Array
+}
+
+// hasSliceArg 判断参数中是否包含需要展开的切片
+func hasSliceArg(args []interface{}) bool {
+	for _, arg := range args {
+		if isExpandableSlice(arg) {
+			return true
+		}
+	}
+	return false
+}
+
+// expandSliceArgs 将切片参数展开为对应数量的 ? 占位符
+// 例如 "id IN (?)" + []int64{1, 2, 3} 展开为 "id IN (?, ?, ?)"，空切片展开为 NULL（不匹配任何行）
+// 只处理 ? 风格的占位符，必须在 convertPlaceholder 之前调用，PostgreSQL 的 $n 等编号由 convertPlaceholder 统一生成
+func expandSliceArgs(querySQL string, args []interface{}) (string, []interface{}) {
+	if !hasSliceArg(args) {
+		return querySQL, args
+	}
+
+	var builder strings.Builder
+	builder.Grow(len(querySQL) + 32)
+	newArgs := make([]interface{}, 0, len(args)+8)
+	argIndex := 0
+	inSingleQuote, inDoubleQuote, inBacktick := false, false, false
+
+	for i := 0; i < len(querySQL); i++ {
+		char := querySQL[i]
+		if i+1 < len(querySQL) && char == '\\' {
+			builder.WriteByte(char)
+			builder.WriteByte(querySQL[i+1])
+			i++
+			continue
+		}
+		switch {
+		case char == '\'' && !inDoubleQuote && !inBacktick:
+			inSingleQuote = !inSingleQuote
+		case char == '"' && !inSingleQuote && !inBacktick:
+			inDoubleQuote = !inDoubleQuote
+		case char == '`' && !inSingleQuote && !inDoubleQuote:
+			inBacktick = !inBacktick
+		case char == '?' && !inSingleQuote && !inDoubleQuote && !inBacktick:
+			// ?? 是转义的字面量 ?，不对应参数
+			if i+1 < len(querySQL) && querySQL[i+1] == '?' {
+				builder.WriteString("??")
+				i++
+				continue
+			}
+			if argIndex < len(args) {
+				arg := args[argIndex]
+				argIndex++
+				if isExpandableSlice(arg) {
+					rv := reflect.ValueOf(arg)
+					if rv.Len() == 0 {
+						builder.WriteString("NULL")
+						continue
+					}
+					for j := 0; j < rv.Len(); j++ {
+						if j > 0 {
+							builder.WriteString(", ")
+						}
+						builder.WriteByte('?')
+						newArgs = append(newArgs, rv.Index(j).Interface())
+					}
+					continue
+				}
+				newArgs = append(newArgs, arg)
+			}
+		}
+		builder.WriteByte(char)
+	}
+
+	// 多余的参数原样保留，交给 sanitizeArgs 处理
+	if argIndex < len(args) {
+		newArgs = append(newArgs, args[argIndex:]...)
+	}
+	return builder.String(), newArgs
+}
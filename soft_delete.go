@@ -1,6 +1,7 @@
 package eorm
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"strings"
@@ -217,16 +218,22 @@ func (db *DB) HasSoftDelete(table string) bool {
 	return db.dbMgr.hasSoftDelete(table)
 }
 
+// HasSoftDelete checks if a table has soft delete configured
+func (tx *Tx) HasSoftDelete(table string) bool {
+	return tx.dbMgr.hasSoftDelete(table)
+}
+
 // ForceDelete performs a physical delete, bypassing soft delete
 func (db *DB) ForceDelete(table string, whereSql string, whereArgs ...interface{}) (int64, error) {
 	if db.lastErr != nil {
 		return 0, db.lastErr
 	}
+	whereSql, whereArgs = applyRowFilter(db.ctx, table, whereSql, whereArgs, true)
 	sdb, err := db.dbMgr.getDB()
 	if err != nil {
 		return 0, err
 	}
-	return db.dbMgr.forceDelete(sdb, table, whereSql, whereArgs...)
+	return db.dbMgr.forceDelete(db.ctx, sdb, table, whereSql, whereArgs...)
 }
 
 // Restore restores soft-deleted records
@@ -234,23 +241,26 @@ func (db *DB) Restore(table string, whereSql string, whereArgs ...interface{}) (
 	if db.lastErr != nil {
 		return 0, db.lastErr
 	}
+	whereSql, whereArgs = applyRowFilter(db.ctx, table, whereSql, whereArgs, true)
 	sdb, err := db.dbMgr.getDB()
 	if err != nil {
 		return 0, err
 	}
-	return db.dbMgr.restore(sdb, table, whereSql, whereArgs...)
+	return db.dbMgr.restore(db.ctx, sdb, table, whereSql, whereArgs...)
 }
 
 // --- Tx Methods ---
 
 // ForceDelete performs a physical delete within a transaction
 func (tx *Tx) ForceDelete(table string, whereSql string, whereArgs ...interface{}) (int64, error) {
-	return tx.dbMgr.forceDelete(tx.tx, table, whereSql, whereArgs...)
+	whereSql, whereArgs = applyRowFilter(tx.ctx, table, whereSql, whereArgs, true)
+	return tx.dbMgr.forceDelete(tx.ctx, tx.tx, table, whereSql, whereArgs...)
 }
 
 // Restore restores soft-deleted records within a transaction
 func (tx *Tx) Restore(table string, whereSql string, whereArgs ...interface{}) (int64, error) {
-	return tx.dbMgr.restore(tx.tx, table, whereSql, whereArgs...)
+	whereSql, whereArgs = applyRowFilter(tx.ctx, table, whereSql, whereArgs, true)
+	return tx.dbMgr.restore(tx.ctx, tx.tx, table, whereSql, whereArgs...)
 }
 
 // --- dbManager Methods ---
@@ -288,7 +298,8 @@ func (mgr *dbManager) hasSoftDelete(table string) bool {
 }
 
 // softDelete performs a soft delete (UPDATE instead of DELETE)
-func (mgr *dbManager) softDelete(executor sqlExecutor, table string, where string, whereArgs ...interface{}) (int64, error) {
+func (mgr *dbManager) softDelete(ctx context.Context, executor sqlExecutor, table string, where string, whereArgs ...interface{}) (_ int64, err error) {
+	defer func() { mgr.afterKeyedWrite(executor, table, where, whereArgs, err) }()
 	config := mgr.getSoftDeleteConfig(table)
 	if config == nil {
 		return 0, fmt.Errorf("soft delete not configured for table %s", table)
@@ -310,11 +321,11 @@ func (mgr *dbManager) softDelete(executor sqlExecutor, table string, where strin
 	querySQL := fmt.Sprintf("UPDATE %s SET %s WHERE %s", table, setValue, where)
 	allArgs := append(setArgs, whereArgs...)
 
-	querySQL = mgr.convertPlaceholder(querySQL, mgr.config.Driver)
+	querySQL = mgr.convertPlaceholder(querySQL, mgr.config.Load().Driver)
 	allArgs = mgr.sanitizeArgs(querySQL, allArgs)
 
 	start := time.Now()
-	result, err := executor.Exec(querySQL, allArgs...)
+	result, err := mgr.execWrite(ctx, executor, querySQL, allArgs...)
 	mgr.logTrace(start, querySQL, allArgs, err)
 	if err != nil {
 		return 0, err
@@ -323,7 +334,8 @@ func (mgr *dbManager) softDelete(executor sqlExecutor, table string, where strin
 }
 
 // forceDelete performs a physical delete, bypassing soft delete
-func (mgr *dbManager) forceDelete(executor sqlExecutor, table string, where string, whereArgs ...interface{}) (int64, error) {
+func (mgr *dbManager) forceDelete(ctx context.Context, executor sqlExecutor, table string, where string, whereArgs ...interface{}) (_ int64, err error) {
+	defer func() { mgr.afterKeyedWrite(executor, table, where, whereArgs, err) }()
 	if err := validateIdentifier(table); err != nil {
 		return 0, err
 	}
@@ -331,20 +343,52 @@ func (mgr *dbManager) forceDelete(executor sqlExecutor, table string, where stri
 		return 0, fmt.Errorf("where condition is required for delete")
 	}
 
+	if n, handled, txErr := withCounterCacheTx(mgr, executor, table, func(tx sqlExecutor) (int64, error) {
+		return mgr.forceDelete(ctx, tx, table, where, whereArgs...)
+	}); handled {
+		return n, txErr
+	}
+	if n, handled, txErr := withHistoryTx(mgr, executor, table, func(tx sqlExecutor) (int64, error) {
+		return mgr.forceDelete(ctx, tx, table, where, whereArgs...)
+	}); handled {
+		return n, txErr
+	}
+	if err := mgr.archiveHistory(executor, table, where, whereArgs); err != nil {
+		return 0, err
+	}
+	changes, err := mgr.captureChanges(executor, table, ChangeDelete, nil, where, whereArgs)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err == nil {
+			mgr.publishChanges(executor, changes)
+		}
+	}()
+	counterDeltas, err := mgr.countCounterCacheChildren(executor, table, where, whereArgs)
+	if err != nil {
+		return 0, err
+	}
+
 	querySQL := fmt.Sprintf("DELETE FROM %s WHERE %s", table, where)
 	querySQL, whereArgs = mgr.prepareQuerySQL(querySQL, whereArgs...)
 
 	start := time.Now()
-	result, err := executor.Exec(querySQL, whereArgs...)
+	result, err := mgr.execWrite(ctx, executor, querySQL, whereArgs...)
 	mgr.logTrace(start, querySQL, whereArgs, err)
 	if err != nil {
 		return 0, err
 	}
-	return result.RowsAffected()
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return affected, mgr.decrementCounterCaches(executor, counterDeltas)
 }
 
 // restore restores soft-deleted records
-func (mgr *dbManager) restore(executor sqlExecutor, table string, where string, whereArgs ...interface{}) (int64, error) {
+func (mgr *dbManager) restore(ctx context.Context, executor sqlExecutor, table string, where string, whereArgs ...interface{}) (_ int64, err error) {
+	defer func() { mgr.afterKeyedWrite(executor, table, where, whereArgs, err) }()
 	if err := validateIdentifier(table); err != nil {
 		return 0, err
 	}
@@ -353,6 +397,15 @@ func (mgr *dbManager) restore(executor sqlExecutor, table string, where string,
 	if config == nil {
 		return 0, fmt.Errorf("soft delete not configured for table %s", table)
 	}
+	changes, err := mgr.captureChanges(executor, table, ChangeUpdate, nil, where, whereArgs)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err == nil {
+			mgr.publishChanges(executor, changes)
+		}
+	}()
 
 	var setValue string
 	var setArgs []interface{}
@@ -372,11 +425,11 @@ func (mgr *dbManager) restore(executor sqlExecutor, table string, where string,
 	}
 
 	allArgs := append(setArgs, whereArgs...)
-	querySQL = mgr.convertPlaceholder(querySQL, mgr.config.Driver)
+	querySQL = mgr.convertPlaceholder(querySQL, mgr.config.Load().Driver)
 	allArgs = mgr.sanitizeArgs(querySQL, allArgs)
 
 	start := time.Now()
-	result, err := executor.Exec(querySQL, allArgs...)
+	result, err := mgr.execWrite(ctx, executor, querySQL, allArgs...)
 	mgr.logTrace(start, querySQL, allArgs, err)
 	if err != nil {
 		return 0, err
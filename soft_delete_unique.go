@@ -0,0 +1,326 @@
+package eorm
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ErrActiveUniqueViolation 插入的记录与未删除的记录违反了 ConfigActiveUnique 配置的唯一约束
+var ErrActiveUniqueViolation = errors.New("eorm: active unique constraint violated")
+
+// ActiveUniqueError 插入时唯一性检查失败的详细信息，可用 errors.As 取出冲突的列
+type ActiveUniqueError struct {
+	Table   string
+	Columns []string
+	Values  []interface{}
+}
+
+func (e *ActiveUniqueError) Error() string {
+	return fmt.Sprintf("eorm: duplicate active row in %s for (%s) = %v", e.Table, strings.Join(e.Columns, ", "), e.Values)
+}
+
+func (e *ActiveUniqueError) Unwrap() error {
+	return ErrActiveUniqueViolation
+}
+
+// activeUniqueRegistry stores insert-time unique column groups per table
+type activeUniqueRegistry struct {
+	groups map[string][][]string // table -> unique column groups
+	mu     sync.RWMutex
+}
+
+// newActiveUniqueRegistry creates a new active unique registry
+func newActiveUniqueRegistry() *activeUniqueRegistry {
+	return &activeUniqueRegistry{
+		groups: make(map[string][][]string),
+	}
+}
+
+// add appends a unique column group for a table, ignoring duplicates
+func (r *activeUniqueRegistry) add(table string, columns []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := strings.ToLower(table)
+	signature := strings.ToLower(strings.Join(columns, ","))
+	for _, group := range r.groups[key] {
+		if strings.ToLower(strings.Join(group, ",")) == signature {
+			return
+		}
+	}
+	r.groups[key] = append(r.groups[key], append([]string(nil), columns...))
+}
+
+// get returns the unique column groups for a table (read-only)
+func (r *activeUniqueRegistry) get(table string) [][]string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.groups[strings.ToLower(table)]
+}
+
+// remove removes all unique column groups for a table
+func (r *activeUniqueRegistry) remove(table string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.groups, strings.ToLower(table))
+}
+
+// --- Global Functions (for default database) ---
+
+// ActiveUniqueIndexSQL 生成只约束未删除记录的唯一索引语句，表必须已通过 ConfigSoftDelete 配置软删除
+// PostgreSQL、SQLite、SQL Server 生成部分索引（WHERE deleted_at IS NULL），Oracle 生成基于 CASE 表达式的函数索引；
+// MySQL 不支持部分索引，返回错误，请改用 ConfigActiveUnique 在插入时检查
+// 示例: sql, err := eorm.ActiveUniqueIndexSQL("users", "uk_users_username", "username")
+func ActiveUniqueIndexSQL(table, indexName string, columns ...string) (string, error) {
+	db, err := defaultDB()
+	if err != nil {
+		return "", err
+	}
+	return db.ActiveUniqueIndexSQL(table, indexName, columns...)
+}
+
+// CreateActiveUniqueIndex 创建只约束未删除记录的唯一索引，删除后的记录不再占用唯一值
+// 示例: eorm.CreateActiveUniqueIndex("users", "uk_users_username", "username")
+func CreateActiveUniqueIndex(table, indexName string, columns ...string) error {
+	db, err := defaultDB()
+	if err != nil {
+		return err
+	}
+	return db.CreateActiveUniqueIndex(table, indexName, columns...)
+}
+
+// ConfigActiveUnique 为不支持部分索引的数据库（如 MySQL）配置插入时的唯一性检查：
+// InsertRecord、BatchInsertRecord 以及 SaveRecord 插入新行前查询未删除的记录中是否已有相同的列值，存在时返回 *ActiveUniqueError；
+// InsertRecordIgnore 与数据库唯一约束冲突时的行为一致，忽略本次插入并返回 false
+// 检查与插入之间没有加锁，并发插入仍可能产生重复；对一致性要求高的场景请在事务中配合 SELECT ... FOR UPDATE 使用
+// 示例:
+//
+//	eorm.ConfigSoftDelete("users", "deleted_at")
+//	eorm.ConfigActiveUnique("users", "username")
+//	eorm.ConfigActiveUnique("users", "tenant_id", "email") // 组合唯一
+func ConfigActiveUnique(table string, columns ...string) {
+	db, err := defaultDB()
+	if err != nil {
+		return
+	}
+	db.ConfigActiveUnique(table, columns...)
+}
+
+// RemoveActiveUnique 移除表的插入时唯一性检查
+func RemoveActiveUnique(table string) {
+	db, err := defaultDB()
+	if err != nil {
+		return
+	}
+	db.RemoveActiveUnique(table)
+}
+
+// ExistsActive 检查未删除的记录中是否存在满足条件的记录，不受 WithTrashed 和全局软删除检查开关影响
+// 示例: taken, err := eorm.ExistsActive("users", "username = ?", "tom")
+func ExistsActive(table string, whereSql string, whereArgs ...interface{}) (bool, error) {
+	db, err := defaultDB()
+	if err != nil {
+		return false, err
+	}
+	return db.ExistsActive(table, whereSql, whereArgs...)
+}
+
+// --- DB Methods ---
+
+// ActiveUniqueIndexSQL 生成只约束未删除记录的唯一索引语句，见 ActiveUniqueIndexSQL
+func (db *DB) ActiveUniqueIndexSQL(table, indexName string, columns ...string) (string, error) {
+	if db.lastErr != nil {
+		return "", db.lastErr
+	}
+	return db.dbMgr.activeUniqueIndexSQL(table, indexName, columns)
+}
+
+// CreateActiveUniqueIndex 创建只约束未删除记录的唯一索引，见 CreateActiveUniqueIndex
+func (db *DB) CreateActiveUniqueIndex(table, indexName string, columns ...string) error {
+	querySQL, err := db.ActiveUniqueIndexSQL(table, indexName, columns...)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec(querySQL)
+	return err
+}
+
+// ConfigActiveUnique 配置插入时的唯一性检查，见 ConfigActiveUnique
+func (db *DB) ConfigActiveUnique(table string, columns ...string) *DB {
+	if db.lastErr != nil || db.dbMgr == nil {
+		return db
+	}
+	if err := validateIdentifier(table); err != nil {
+		db.lastErr = err
+		return db
+	}
+	if len(columns) == 0 {
+		db.lastErr = fmt.Errorf("eorm: active unique columns cannot be empty")
+		return db
+	}
+	for _, col := range columns {
+		if err := validateIdentifier(col); err != nil {
+			db.lastErr = err
+			return db
+		}
+	}
+	db.dbMgr.addActiveUnique(table, columns)
+	return db
+}
+
+// RemoveActiveUnique 移除表的插入时唯一性检查
+func (db *DB) RemoveActiveUnique(table string) *DB {
+	if db.lastErr != nil || db.dbMgr == nil {
+		return db
+	}
+	if db.dbMgr.activeUniques != nil {
+		db.dbMgr.activeUniques.remove(table)
+	}
+	return db
+}
+
+// ExistsActive 检查未删除的记录中是否存在满足条件的记录，见 ExistsActive
+func (db *DB) ExistsActive(table string, whereSql string, whereArgs ...interface{}) (bool, error) {
+	if db.lastErr != nil {
+		return false, db.lastErr
+	}
+	sdb, err := db.dbMgr.getDB()
+	if err != nil {
+		return false, err
+	}
+	return db.dbMgr.existsActive(sdb, table, whereSql, whereArgs...)
+}
+
+// ExistsActive 在事务中检查未删除的记录中是否存在满足条件的记录
+func (tx *Tx) ExistsActive(table string, whereSql string, whereArgs ...interface{}) (bool, error) {
+	return tx.dbMgr.existsActive(tx.tx, table, whereSql, whereArgs...)
+}
+
+// --- dbManager Methods ---
+
+// addActiveUnique registers an insert-time unique column group for a table
+func (mgr *dbManager) addActiveUnique(table string, columns []string) {
+	if mgr.activeUniques == nil {
+		mgr.activeUniques = newActiveUniqueRegistry()
+	}
+	mgr.activeUniques.add(table, columns)
+}
+
+// activeCondition builds the condition matching non-deleted rows, independent of enableSoftDeleteCheck
+func (mgr *dbManager) activeCondition(config *SoftDeleteConfig) string {
+	if config.Type == SoftDeleteBool {
+		if mgr.config.Load().Driver == PostgreSQL {
+			return fmt.Sprintf("%s = false", config.Field)
+		}
+		return fmt.Sprintf("%s = 0", config.Field)
+	}
+	return fmt.Sprintf("%s IS NULL", config.Field)
+}
+
+// existsActive checks for matching rows that are not soft deleted
+func (mgr *dbManager) existsActive(executor sqlExecutor, table, where string, whereArgs ...interface{}) (bool, error) {
+	if err := validateIdentifier(table); err != nil {
+		return false, err
+	}
+	if config := mgr.getSoftDeleteConfig(table); config != nil {
+		if where != "" {
+			where = "(" + where + ") AND " + mgr.activeCondition(config)
+		} else {
+			where = mgr.activeCondition(config)
+		}
+	}
+	return mgr.exists(executor, table, where, whereArgs...)
+}
+
+// activeUniqueIndexSQL builds the dialect-specific DDL for a unique index over non-deleted rows
+func (mgr *dbManager) activeUniqueIndexSQL(table, indexName string, columns []string) (string, error) {
+	if err := validateIdentifier(table); err != nil {
+		return "", err
+	}
+	if err := validateIdentifier(indexName); err != nil {
+		return "", err
+	}
+	if len(columns) == 0 {
+		return "", fmt.Errorf("eorm: active unique columns cannot be empty")
+	}
+	for _, col := range columns {
+		if err := validateIdentifier(col); err != nil {
+			return "", err
+		}
+	}
+	config := mgr.getSoftDeleteConfig(table)
+	if config == nil {
+		return "", fmt.Errorf("eorm: soft delete not configured for table %s", table)
+	}
+	active := mgr.activeCondition(config)
+
+	switch mgr.config.Load().Driver {
+	case PostgreSQL, SQLite3, SQLServer:
+		return fmt.Sprintf("CREATE UNIQUE INDEX %s ON %s (%s) WHERE %s",
+			indexName, table, strings.Join(columns, ", "), active), nil
+	case Oracle:
+		// Oracle 不为全部键为 NULL 的行建立索引项，已删除的行不参与唯一性校验
+		exprs := make([]string, len(columns))
+		for i, col := range columns {
+			exprs[i] = fmt.Sprintf("CASE WHEN %s THEN %s END", active, col)
+		}
+		return fmt.Sprintf("CREATE UNIQUE INDEX %s ON %s (%s)", indexName, table, strings.Join(exprs, ", ")), nil
+	default:
+		return "", fmt.Errorf("eorm: %s does not support partial unique indexes, use ConfigActiveUnique instead", mgr.config.Load().Driver)
+	}
+}
+
+// hasActiveUniques reports whether the table has insert-time unique column groups
+func (mgr *dbManager) hasActiveUniques(table string) bool {
+	return mgr.activeUniques != nil && len(mgr.activeUniques.get(table)) > 0
+}
+
+// checkActiveUnique rejects records that duplicate a non-deleted row on a configured unique column group
+func (mgr *dbManager) checkActiveUnique(executor sqlExecutor, table string, records ...*Record) error {
+	if mgr.activeUniques == nil {
+		return nil
+	}
+	groups := mgr.activeUniques.get(table)
+	if len(groups) == 0 {
+		return nil
+	}
+	for _, group := range groups {
+		seen := make(map[string]bool)
+		for _, record := range records {
+			if record == nil {
+				continue
+			}
+			values := make([]interface{}, 0, len(group))
+			conditions := make([]string, 0, len(group))
+			for _, col := range group {
+				v := record.Get(col)
+				if v == nil {
+					// 与数据库唯一索引一致，含 NULL 的组合不参与唯一性校验
+					values = nil
+					break
+				}
+				values = append(values, v)
+				conditions = append(conditions, col+" = ?")
+			}
+			if values == nil {
+				continue
+			}
+			// 同一批次内的重复
+			key := fmt.Sprintf("%#v", values)
+			if seen[key] {
+				return &ActiveUniqueError{Table: table, Columns: group, Values: values}
+			}
+			seen[key] = true
+
+			exists, err := mgr.existsActive(executor, table, strings.Join(conditions, " AND "), values...)
+			if err != nil {
+				return err
+			}
+			if exists {
+				return &ActiveUniqueError{Table: table, Columns: group, Values: values}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package eorm
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestActiveUniqueOnInsertPaths(t *testing.T) {
+	const ddl = "CREATE TABLE au_users (id INTEGER PRIMARY KEY, username TEXT, deleted_at DATETIME)"
+	setup := func(t *testing.T) *DB {
+		db := openTestDB(t, ddl)
+		db.ConfigSoftDelete("au_users", "deleted_at")
+		db.ConfigActiveUnique("au_users", "username")
+		mustExec(t, db, "INSERT INTO au_users (id, username) VALUES (1, 'tom')")
+		mustExec(t, db, "INSERT INTO au_users (id, username, deleted_at) VALUES (2, 'amy', CURRENT_TIMESTAMP)")
+		return db
+	}
+
+	t.Run("InsertRecordIgnore", func(t *testing.T) {
+		db := setup(t)
+		inserted, err := db.InsertRecordIgnore("au_users", NewRecord().Set("id", 3).Set("username", "tom"))
+		if err != nil || inserted {
+			t.Fatalf("inserted=%v err=%v, want ignored duplicate", inserted, err)
+		}
+		inserted, err = db.InsertRecordIgnore("au_users", NewRecord().Set("id", 4).Set("username", "amy"))
+		if err != nil || !inserted {
+			t.Fatalf("inserted=%v err=%v, a soft deleted row must not block the insert", inserted, err)
+		}
+	})
+
+	t.Run("SaveRecord", func(t *testing.T) {
+		db := setup(t)
+		_, err := db.SaveRecord("au_users", NewRecord().Set("id", 3).Set("username", "tom"))
+		if !errors.Is(err, ErrActiveUniqueViolation) {
+			t.Fatalf("err = %v, want ErrActiveUniqueViolation", err)
+		}
+		if _, err := db.SaveRecord("au_users", NewRecord().Set("id", 1).Set("username", "tom")); err != nil {
+			t.Fatalf("updating the existing row must not be rejected: %v", err)
+		}
+	})
+}
@@ -1,6 +1,7 @@
 package eorm
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
@@ -24,15 +25,18 @@ type SqlConfig struct {
 
 // SqlItem represents a single SQL statement configuration
 type SqlItem struct {
-	Name        string      `json:"name"`
-	Description string      `json:"description,omitempty"`
-	SQL         string      `json:"sql"`
-	Type        string      `json:"type,omitempty"` // select, update, insert, delete
-	Namespace   string      `json:"namespace,omitempty"`
-	Order       string      `json:"order,omitempty"`
-	InParam     []ParamItem `json:"inparam,omitempty"`
-	FilePath    string      // 来源配置文件路径 (运行时添加)
-	FullName    string      // 完整名称: namespace.name 或 name (运行时生成)
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	SQL         string            `json:"sql"`
+	Type        string            `json:"type,omitempty"` // select, update, insert, delete
+	Namespace   string            `json:"namespace,omitempty"`
+	Order       string            `json:"order,omitempty"`
+	InParam     []ParamItem       `json:"inparam,omitempty"`
+	TagTables   []string          `json:"tagTables,omitempty"` // 依赖的表，通过 eorm 写这些表时自动失效该模板的缓存
+	Dialect     string            `json:"dialect,omitempty"`   // 仅用于这些驱动（逗号分隔，如 "oracle" 或 "mysql,sqlite3"），同名模板可按方言各写一份
+	Dialects    map[string]string `json:"dialects,omitempty"`  // 按驱动切换的 SQL 变体（mysql/postgres/sqlite3/oracle/sqlserver），未列出的驱动使用 sql
+	FilePath    string            // 来源配置文件路径 (运行时添加)
+	FullName    string            // 完整名称: namespace.name 或 name (运行时生成)
 }
 
 // ParamItem represents a dynamic SQL parameter configuration
@@ -67,10 +71,12 @@ type SqlTemplateBuilder struct {
 	dbName              string // 用于多数据库支持
 	tx                  *Tx    // 用于事务支持
 	configMgr           *SqlConfigManager
-	cacheRepositoryName string        // 缓存仓库名称
-	cacheTTL            time.Duration // 缓存过期时间
-	cacheProvider       CacheProvider // 指定的缓存提供者（nil 表示使用默认缓存）
-	countCacheTTL       time.Duration // 分页计数缓存时间
+	cacheRepositoryName string          // 缓存仓库名称
+	cacheTTL            time.Duration   // 缓存过期时间
+	cacheProvider       CacheProvider   // 指定的缓存提供者（nil 表示使用默认缓存）
+	cacheEmptyTTL       time.Duration   // 空结果缓存时间（0 表示使用仓库配置，<0 表示不缓存空结果）
+	countCacheTTL       time.Duration   // 分页计数缓存时间
+	ctx                 context.Context // 调用方上下文（行级过滤器）
 }
 
 // SqlTemplateEngine handles SQL template processing and parameter substitution
@@ -265,6 +271,37 @@ func (mgr *SqlConfigManager) processSqlItems(config *SqlConfig) error {
 			item.FullName = item.Name
 		}
 
+		if err := normalizeItemDialects(item); err != nil {
+			return &SqlConfigError{
+				Type:    "ValidationError",
+				Message: fmt.Sprintf("invalid dialects of SQL '%s' in %s: %s", item.FullName, config.FilePath, err.Error()),
+				SqlName: item.FullName,
+				Cause:   err,
+			}
+		}
+
+		// Dialect-specific items are indexed per driver and resolved before the generic item
+		if dialects := itemDialects(item); len(dialects) > 0 {
+			for _, dialect := range dialects {
+				key := dialectKey(item.FullName, dialect)
+				if existingItem, exists := mgr.sqlItems[key]; exists {
+					return &SqlConfigError{
+						Type: "DuplicateError",
+						Message: fmt.Sprintf("duplicate SQL identifier '%s' for dialect %s found in %s (previously defined in %s)",
+							item.FullName, dialect, config.FilePath, existingItem.FilePath),
+						SqlName: item.FullName,
+					}
+				}
+				mgr.sqlItems[key] = item
+				if namespace != "" {
+					if _, exists := mgr.sqlItems[dialectKey(item.Name, dialect)]; !exists {
+						mgr.sqlItems[dialectKey(item.Name, dialect)] = item
+					}
+				}
+			}
+			continue
+		}
+
 		// Check for duplicate SQL identifiers
 		if existingItem, exists := mgr.sqlItems[item.FullName]; exists {
 			return &SqlConfigError{
@@ -374,6 +411,15 @@ func (mgr *SqlConfigManager) ReloadConfig(configPath string) error {
 	if config, exists := mgr.configs[configPath]; exists {
 		// Remove SQL items from global index
 		for _, item := range config.Sqls {
+			if dialects := itemDialects(&item); len(dialects) > 0 {
+				for _, dialect := range dialects {
+					delete(mgr.sqlItems, dialectKey(item.FullName, dialect))
+					if item.Namespace != "" {
+						delete(mgr.sqlItems, dialectKey(item.Name, dialect))
+					}
+				}
+				continue
+			}
 			delete(mgr.sqlItems, item.FullName)
 			if item.Namespace != "" {
 				delete(mgr.sqlItems, item.Name)
@@ -499,6 +545,8 @@ func (db *DB) SqlTemplate(name string, params ...interface{}) *SqlTemplateBuilde
 		cacheRepositoryName: db.cacheRepositoryName, // 继承 DB 的缓存仓库名
 		cacheTTL:            db.cacheTTL,            // 继承 DB 的缓存 TTL
 		cacheProvider:       db.cacheProvider,       // 继承 DB 的缓存提供者
+		cacheEmptyTTL:       db.cacheEmptyTTL,
+		ctx:                 db.ctx,
 	}
 
 	return builder
@@ -531,6 +579,8 @@ func (tx *Tx) SqlTemplate(name string, params ...interface{}) *SqlTemplateBuilde
 		cacheRepositoryName: tx.cacheRepositoryName, // 继承 Tx 的缓存仓库名
 		cacheTTL:            tx.cacheTTL,            // 继承 Tx 的缓存 TTL
 		cacheProvider:       tx.cacheProvider,       // 继承 Tx 的缓存提供者
+		cacheEmptyTTL:       tx.cacheEmptyTTL,
+		ctx:                 tx.ctx,
 	}
 
 	return builder
@@ -587,6 +637,12 @@ func (b *SqlTemplateBuilder) RedisCache(cacheRepositoryName string, ttl ...time.
 	return b
 }
 
+// CacheEmpty 设置空结果的缓存时间，需配合 Cache/LocalCache/RedisCache 使用
+func (b *SqlTemplateBuilder) CacheEmpty(ttl time.Duration) *SqlTemplateBuilder {
+	b.cacheEmptyTTL = ttl
+	return b
+}
+
 // WithCountCache 启用分页计数缓存
 // 用于在分页查询时缓存 COUNT 查询结果，避免重复执行 COUNT 语句
 // ttl: 缓存时间，如果为 0 则不缓存，如果大于 0 则缓存指定时间
@@ -604,6 +660,15 @@ func (b *SqlTemplateBuilder) getEffectiveCache() CacheProvider {
 	return GetCache()
 }
 
+// registerCacheDependency 登记模板缓存对 tagTables 中各表的依赖
+func (b *SqlTemplateBuilder) registerCacheDependency(cache CacheProvider, key string) {
+	sqlItem, err := b.resolveSqlItem()
+	if err != nil || len(sqlItem.TagTables) == 0 {
+		return
+	}
+	tableCacheDependencies.register(sqlItem.TagTables, cache, b.cacheRepositoryName, key)
+}
+
 // getDbName 获取数据库名称
 func (b *SqlTemplateBuilder) getDbName() string {
 	if b.tx != nil && b.tx.dbMgr != nil {
@@ -630,10 +695,10 @@ func (b *SqlTemplateBuilder) Query() ([]*Record, error) {
 	if b.cacheRepositoryName != "" {
 		cache := b.getEffectiveCache()
 		dbName := b.getDbName()
-		key := GenerateCacheKey(dbName, finalSQL, args...)
+		key := GenerateCacheKeyContext(b.ctx, dbName, finalSQL, args...)
 
 		// 尝试从缓存读取
-		if val, ok := cache.CacheGet(b.cacheRepositoryName, key); ok {
+		if val, ok := cacheGet(cache, b.cacheRepositoryName, key); ok {
 			var results []*Record
 			if convertCacheValue(val, &results) {
 				return results, nil
@@ -670,8 +735,8 @@ func (b *SqlTemplateBuilder) Query() ([]*Record, error) {
 		}
 
 		// 如果查询成功，写入缓存
-		if err == nil {
-			cache.CacheSet(b.cacheRepositoryName, key, results, getEffectiveTTL(b.cacheRepositoryName, b.cacheTTL))
+		if err == nil && storeCacheResult(cache, b.cacheRepositoryName, key, results, len(results) == 0, getEffectiveTTL(b.cacheRepositoryName, b.cacheTTL), b.cacheEmptyTTL) {
+			b.registerCacheDependency(cache, key)
 		}
 		return results, err
 	}
@@ -713,10 +778,10 @@ func (b *SqlTemplateBuilder) Paginate(page int, pageSize int) (*Page[*Record], e
 	if b.cacheRepositoryName != "" {
 		cache := b.getEffectiveCache()
 		dbName := b.getDbName()
-		key := GenerateCacheKey(dbName, "PAGINATE_TEMPLATE:"+finalSQL, args...)
+		key := GenerateCacheKeyContext(b.ctx, dbName, "PAGINATE_TEMPLATE:"+finalSQL, args...)
 
 		// 尝试从缓存读取
-		if val, ok := cache.CacheGet(b.cacheRepositoryName, key); ok {
+		if val, ok := cacheGet(cache, b.cacheRepositoryName, key); ok {
 			var pageObj *Page[*Record]
 			if convertCacheValue(val, &pageObj) {
 				return pageObj, nil
@@ -772,7 +837,8 @@ func (b *SqlTemplateBuilder) Paginate(page int, pageSize int) (*Page[*Record], e
 
 		// 如果查询成功，写入缓存
 		if err == nil {
-			cache.CacheSet(b.cacheRepositoryName, key, pageObj, getEffectiveTTL(b.cacheRepositoryName, b.cacheTTL))
+			cacheSet(cache, b.cacheRepositoryName, key, pageObj, getEffectiveTTL(b.cacheRepositoryName, b.cacheTTL))
+			b.registerCacheDependency(cache, key)
 		}
 		return pageObj, err
 	}
@@ -835,10 +901,13 @@ func (b *SqlTemplateBuilder) QueryFirst() (*Record, error) {
 	if b.cacheRepositoryName != "" {
 		cache := b.getEffectiveCache()
 		dbName := b.getDbName()
-		key := GenerateCacheKey(dbName, finalSQL, args...) + "_first"
+		key := GenerateCacheKeyContext(b.ctx, dbName, finalSQL, args...) + "_first"
 
 		// 尝试从缓存读取
-		if val, ok := cache.CacheGet(b.cacheRepositoryName, key); ok {
+		if val, ok := cacheGet(cache, b.cacheRepositoryName, key); ok {
+			if isCacheEmptyMarker(val) {
+				return nil, nil
+			}
 			var result *Record
 			if convertCacheValue(val, &result) {
 				return result, nil
@@ -875,8 +944,8 @@ func (b *SqlTemplateBuilder) QueryFirst() (*Record, error) {
 		}
 
 		// 如果查询成功且有结果，写入缓存
-		if err == nil && result != nil {
-			cache.CacheSet(b.cacheRepositoryName, key, result, getEffectiveTTL(b.cacheRepositoryName, b.cacheTTL))
+		if err == nil && storeCacheResult(cache, b.cacheRepositoryName, key, result, result == nil, getEffectiveTTL(b.cacheRepositoryName, b.cacheTTL), b.cacheEmptyTTL) {
+			b.registerCacheDependency(cache, key)
 		}
 		return result, err
 	}
@@ -924,6 +993,11 @@ func (b *SqlTemplateBuilder) Exec() (sql.Result, error) {
 	// 	"hasTx":      b.tx != nil,
 	// })
 
+	return b.execSQL(finalSQL, args)
+}
+
+// execSQL executes the built SQL on the transaction, the named database or the default database
+func (b *SqlTemplateBuilder) execSQL(finalSQL string, args []interface{}) (sql.Result, error) {
 	if b.tx != nil {
 		// Execute in transaction context
 		if b.timeout > 0 {
@@ -952,14 +1026,40 @@ func (b *SqlTemplateBuilder) Exec() (sql.Result, error) {
 // buildFinalSQL builds the final SQL statement with parameter substitution
 func (b *SqlTemplateBuilder) buildFinalSQL() (string, []interface{}, error) {
 	// Get SQL item from configuration
-	sqlItem, err := b.configMgr.GetSqlItem(b.sqlName)
+	sqlItem, err := b.resolveSqlItem()
 	if err != nil {
 		return "", nil, err
 	}
 
 	// Process parameters and build dynamic SQL
 	engine := getGlobalTemplateEngine()
-	return engine.ProcessTemplate(sqlItem, b.params)
+	finalSQL, args, err := engine.ProcessTemplate(sqlItem, b.params)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// Apply row-level filters to SELECT statements
+	if mgr := b.getDbManager(); mgr != nil {
+		finalSQL, args = mgr.injectRowFilters(b.ctx, finalSQL, args)
+	}
+	return finalSQL, args, nil
+}
+
+// getDbManager returns the database manager the template executes on
+func (b *SqlTemplateBuilder) getDbManager() *dbManager {
+	if b.tx != nil {
+		return b.tx.dbMgr
+	}
+	if b.dbName != "" {
+		if db := Use(b.dbName); db.lastErr == nil {
+			return db.dbMgr
+		}
+		return nil
+	}
+	if db, err := defaultDB(); err == nil {
+		return db.dbMgr
+	}
+	return nil
 }
 
 // ProcessTemplate processes a SQL template with parameters
@@ -0,0 +1,232 @@
+package eorm
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SQLCaptureOptions CaptureSQL 的文件轮转和内容选项
+type SQLCaptureOptions struct {
+	MaxSize     int64         // 单个文件的最大字节数，超过后轮转（默认 100MB）
+	RotateEvery time.Duration // 按时间轮转的间隔，如 24 * time.Hour（0 表示只按大小轮转）
+	MaxBackups  int           // 保留的历史文件数，超出的最旧文件被删除（默认 5，<0 表示全部保留）
+	OmitArgs    bool          // 不记录参数值（参数可能包含敏感数据）
+	Databases   []string      // 只记录这些数据库的 SQL（为空表示全部）
+}
+
+// SQLCaptureEntry 捕获文件中的一行（JSONL）
+type SQLCaptureEntry struct {
+	Time       time.Time     `json:"ts"`
+	Database   string        `json:"db"`
+	SQL        string        `json:"sql"`
+	Args       []interface{} `json:"args,omitempty"`
+	DurationMs float64       `json:"duration_ms"`
+	Error      string        `json:"error,omitempty"`
+}
+
+// sqlCapture 正在写入的捕获文件
+type sqlCapture struct {
+	mu        sync.Mutex
+	path      string
+	opts      SQLCaptureOptions
+	databases map[string]bool
+	file      *os.File
+	size      int64
+	openedAt  time.Time
+}
+
+var (
+	sqlCaptureMu     sync.Mutex                 // 串行化 CaptureSQL/StopCaptureSQL
+	sqlCaptureActive atomic.Pointer[sqlCapture] // 当前的捕获，未开启时为 nil
+)
+
+// CaptureSQL 开始把执行的 SQL、参数和耗时以 JSONL 格式写入文件，用于离线分析或回放
+// 与 SetDebugMode 相互独立，不依赖日志级别；可在运行时随时开启或用 StopCaptureSQL 关闭，再次调用会切换到新的文件
+// 文件超过 MaxSize 或到达 RotateEvery 时重命名为 path.20060102-150405 并新建文件
+// 示例:
+//
+//	eorm.CaptureSQL("/var/log/app/sql.jsonl", &eorm.SQLCaptureOptions{MaxSize: 50 << 20, MaxBackups: 10})
+//	defer eorm.StopCaptureSQL()
+//	// {"ts":"2026-01-02T15:04:05.123+08:00","db":"default","sql":"SELECT * FROM users WHERE id = ?","args":[1],"duration_ms":0.84}
+func CaptureSQL(path string, opts *SQLCaptureOptions) error {
+	if path == "" {
+		return fmt.Errorf("eorm: CaptureSQL requires a file path")
+	}
+	c := &sqlCapture{path: path}
+	if opts != nil {
+		c.opts = *opts
+	}
+	if c.opts.MaxSize <= 0 {
+		c.opts.MaxSize = 100 << 20
+	}
+	if c.opts.MaxBackups == 0 {
+		c.opts.MaxBackups = 5
+	}
+	if len(c.opts.Databases) > 0 {
+		c.databases = make(map[string]bool, len(c.opts.Databases))
+		for _, name := range c.opts.Databases {
+			c.databases[name] = true
+		}
+	}
+	if err := c.open(); err != nil {
+		return err
+	}
+
+	sqlCaptureMu.Lock()
+	defer sqlCaptureMu.Unlock()
+	if old := sqlCaptureActive.Swap(c); old != nil {
+		old.close()
+	}
+	return nil
+}
+
+// StopCaptureSQL 停止捕获 SQL 并关闭文件
+func StopCaptureSQL() error {
+	sqlCaptureMu.Lock()
+	defer sqlCaptureMu.Unlock()
+	if old := sqlCaptureActive.Swap(nil); old != nil {
+		return old.close()
+	}
+	return nil
+}
+
+// IsCapturingSQL 是否正在捕获 SQL
+func IsCapturingSQL() bool {
+	return sqlCaptureActive.Load() != nil
+}
+
+// captureSQL 在开启捕获时写入一条记录
+func captureSQL(dbName, sql string, args []interface{}, duration time.Duration, err error) {
+	c := sqlCaptureActive.Load()
+	if c == nil || (c.databases != nil && !c.databases[dbName]) {
+		return
+	}
+	entry := SQLCaptureEntry{
+		Time:       time.Now(),
+		Database:   dbName,
+		SQL:        cleanSQL(sql),
+		DurationMs: float64(duration.Microseconds()) / 1000,
+	}
+	if !c.opts.OmitArgs {
+		entry.Args = captureArgs(args)
+	}
+	if err != nil {
+		entry.Error = fixStringEncoding(err.Error())
+	}
+	line, mErr := json.Marshal(entry)
+	if mErr != nil {
+		return
+	}
+	if wErr := c.write(append(line, '\n')); wErr != nil {
+		LogWarn("SQL 捕获文件写入失败", NewRecord().Set("path", c.path).Set("error", wErr.Error()))
+	}
+}
+
+// captureArgs 转换参数使其可以编码为 JSON，无法编码的值记录为文本
+func captureArgs(args []interface{}) []interface{} {
+	if len(args) == 0 {
+		return nil
+	}
+	out := make([]interface{}, len(args))
+	for i, arg := range args {
+		if _, err := json.Marshal(arg); err != nil {
+			out[i] = fmt.Sprint(arg)
+		} else {
+			out[i] = arg
+		}
+	}
+	return out
+}
+
+// open 打开（追加）捕获文件
+func (c *sqlCapture) open() error {
+	if dir := filepath.Dir(c.path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("eorm: cannot create SQL capture directory: %w", err)
+		}
+	}
+	file, err := os.OpenFile(c.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("eorm: cannot open SQL capture file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("eorm: cannot open SQL capture file: %w", err)
+	}
+	c.file = file
+	c.size = info.Size()
+	c.openedAt = time.Now()
+	return nil
+}
+
+// write 写入一行，需要时先轮转文件
+func (c *sqlCapture) write(line []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.file == nil {
+		return nil
+	}
+	if c.size+int64(len(line)) > c.opts.MaxSize ||
+		(c.opts.RotateEvery > 0 && time.Since(c.openedAt) >= c.opts.RotateEvery) {
+		if err := c.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := c.file.Write(line)
+	c.size += int64(n)
+	return err
+}
+
+// rotate 将当前文件重命名为带时间戳的备份并新建文件，删除超出 MaxBackups 的旧备份
+func (c *sqlCapture) rotate() error {
+	if c.size == 0 {
+		c.openedAt = time.Now()
+		return nil
+	}
+	if err := c.file.Close(); err != nil {
+		return err
+	}
+	c.file = nil
+	backup := c.path + "." + time.Now().Format("20060102-150405")
+	if _, err := os.Stat(backup); err == nil {
+		backup = c.path + "." + time.Now().Format("20060102-150405.000000")
+	}
+	if err := os.Rename(c.path, backup); err != nil {
+		// 重命名失败时继续写入原文件
+		if openErr := c.open(); openErr != nil {
+			return openErr
+		}
+		return err
+	}
+	if err := c.open(); err != nil {
+		return err
+	}
+	if c.opts.MaxBackups > 0 {
+		backups, _ := filepath.Glob(c.path + ".*")
+		sort.Strings(backups)
+		for len(backups) > c.opts.MaxBackups {
+			os.Remove(backups[0])
+			backups = backups[1:]
+		}
+	}
+	return nil
+}
+
+// close 关闭捕获文件
+func (c *sqlCapture) close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.file == nil {
+		return nil
+	}
+	err := c.file.Close()
+	c.file = nil
+	return err
+}
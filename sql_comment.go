@@ -0,0 +1,78 @@
+package eorm
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// QueryCommentHook 全局 SQL 注释钩子
+// 返回的内容会以 /* ... */ 的形式追加到该数据库执行的每条 SQL 末尾，返回空字符串表示不追加
+// 常用于写入应用名、版本号、trace id 等信息，便于 DBA 在慢查询日志中定位 SQL 来源
+// ctx 为执行该 SQL 的上下文（WithContext 传入的上下文，未指定时为 context.Background()），可从中读取 trace id 等请求级信息
+// 注意：启用预编译语句缓存时，注释内容会成为缓存键的一部分，每个不同的注释都会占用一个缓存条目，
+// 注释随请求变化时建议关闭语句缓存
+type QueryCommentHook func(ctx context.Context, dbName string) string
+
+var (
+	queryCommentHook   QueryCommentHook
+	queryCommentHookMu sync.RWMutex
+)
+
+// SetQueryCommentHook 设置全局 SQL 注释钩子（传 nil 表示关闭）
+// 示例:
+//
+//	eorm.SetQueryCommentHook(func(ctx context.Context, dbName string) string {
+//	    if traceID, ok := ctx.Value(traceIDKey{}).(string); ok {
+//	        return "app:order-service trace:" + traceID
+//	    }
+//	    return "app:order-service"
+//	})
+func SetQueryCommentHook(hook QueryCommentHook) {
+	queryCommentHookMu.Lock()
+	defer queryCommentHookMu.Unlock()
+	queryCommentHook = hook
+}
+
+// getQueryCommentHook 获取当前全局 SQL 注释钩子
+func getQueryCommentHook() QueryCommentHook {
+	queryCommentHookMu.RLock()
+	defer queryCommentHookMu.RUnlock()
+	return queryCommentHook
+}
+
+// sqlCommentReplacer 清理注释内容中可能破坏 SQL 结构或占位符解析的字符
+var sqlCommentReplacer = strings.NewReplacer(
+	"/*", "",
+	"*/", "",
+	"?", "",
+	"'", "",
+	"\"", "",
+	"`", "",
+	"\\", "",
+	"\r", " ",
+	"\n", " ",
+)
+
+// sanitizeSQLComment 清理注释内容，防止注释提前闭合导致 SQL 注入
+func sanitizeSQLComment(comment string) string {
+	return strings.TrimSpace(sqlCommentReplacer.Replace(comment))
+}
+
+// appendSQLComment 将注释以 /* ... */ 的形式追加到 SQL 末尾
+func appendSQLComment(querySQL, comment string) string {
+	comment = sanitizeSQLComment(comment)
+	if comment == "" {
+		return querySQL
+	}
+	return querySQL + " /* " + comment + " */"
+}
+
+// applyQueryCommentHook 为即将执行的 SQL 追加全局钩子生成的注释
+func (mgr *dbManager) applyQueryCommentHook(ctx context.Context, querySQL string) string {
+	hook := getQueryCommentHook()
+	if hook == nil {
+		return querySQL
+	}
+	return appendSQLComment(querySQL, hook(contextOrBackground(ctx), mgr.name))
+}
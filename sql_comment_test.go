@@ -0,0 +1,49 @@
+package eorm
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+type commentTraceKey struct{}
+
+func TestQueryCommentHookReceivesContext(t *testing.T) {
+	db := openTestDB(t, "CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT)")
+	var mu sync.Mutex
+	var traces []string
+	SetQueryCommentHook(func(ctx context.Context, dbName string) string {
+		trace, _ := ctx.Value(commentTraceKey{}).(string)
+		mu.Lock()
+		traces = append(traces, trace)
+		mu.Unlock()
+		return "trace:" + trace
+	})
+	t.Cleanup(func() { SetQueryCommentHook(nil) })
+
+	ctx := context.WithValue(context.Background(), commentTraceKey{}, "t-1")
+	if _, err := db.WithContext(ctx).Exec("INSERT INTO users (id, name) VALUES (1, 'alice')"); err != nil {
+		t.Fatalf("Exec: %v", err)
+	}
+	if _, err := db.WithContext(ctx).Query("SELECT * FROM users"); err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	err := db.WithContext(ctx).Transaction(func(tx *Tx) error {
+		_, err := tx.Query("SELECT * FROM users")
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Transaction: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(traces) < 3 {
+		t.Fatalf("hook called %d times, want at least 3", len(traces))
+	}
+	for i, trace := range traces {
+		if trace != "t-1" {
+			t.Fatalf("call %d saw trace %q, want t-1", i, trace)
+		}
+	}
+}
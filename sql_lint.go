@@ -0,0 +1,218 @@
+package eorm
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// LintIssue SQL 兼容性检查发现的一处方言相关写法
+type LintIssue struct {
+	Rule        string       // 规则名称
+	Construct   string       // 匹配到的 SQL 片段
+	Position    int          // 片段在 SQL 中的字节偏移
+	Unsupported []DriverType // 目标方言中不支持该写法的数据库
+	Message     string       // 问题说明
+	Suggestion  string       // 可移植的替代写法
+	Source      string       // 来源：SQL 模板名称或配置文件路径，LintSQL 中为空
+}
+
+// String 返回问题的可读描述
+func (i LintIssue) String() string {
+	dialects := make([]string, len(i.Unsupported))
+	for n, d := range i.Unsupported {
+		dialects[n] = string(d)
+	}
+	prefix := ""
+	if i.Source != "" {
+		prefix = i.Source + ": "
+	}
+	return fmt.Sprintf("%s[%s] %q at %d: %s (unsupported: %s); %s",
+		prefix, i.Rule, i.Construct, i.Position, i.Message, strings.Join(dialects, ", "), i.Suggestion)
+}
+
+// lintRule 方言写法规则，supported 为支持该写法的数据库
+type lintRule struct {
+	name       string
+	re         *regexp.Regexp
+	supported  []DriverType
+	message    string
+	suggestion string
+}
+
+// allDialects 未指定目标方言时检查的全部数据库
+var allDialects = []DriverType{MySQL, PostgreSQL, SQLite3, Oracle, SQLServer}
+
+// lintRules SQL 兼容性规则
+var lintRules = []lintRule{
+	{"limit", regexp.MustCompile(`(?i)\bLIMIT\s+(?:\d+|\?|\$\d+)`), []DriverType{MySQL, PostgreSQL, SQLite3},
+		"LIMIT is not supported by Oracle and SQL Server (eorm only rewrites a single trailing LIMIT)", "use QueryBuilder.Limit/Offset or Paginate"},
+	{"top", regexp.MustCompile(`(?i)\bSELECT\s+(?:DISTINCT\s+)?TOP\s*\(?\s*\d+`), []DriverType{SQLServer},
+		"SELECT TOP is SQL Server specific", "use QueryBuilder.Limit or Paginate"},
+	{"rownum", regexp.MustCompile(`(?i)\bROWNUM\b`), []DriverType{Oracle},
+		"ROWNUM is Oracle specific", "use QueryBuilder.Limit or Paginate"},
+	{"backtick-quote", regexp.MustCompile("`[^`]*`"), []DriverType{MySQL, SQLite3},
+		"backtick identifier quoting is MySQL specific", "use unquoted identifiers or double quotes with ANSI_QUOTES"},
+	{"bracket-quote", regexp.MustCompile(`\[[A-Za-z_][\w ]*\]`), []DriverType{SQLServer, SQLite3},
+		"square bracket identifier quoting is SQL Server specific", "use unquoted identifiers"},
+	{"now", regexp.MustCompile(`(?i)\bNOW\s*\(\s*\)`), []DriverType{MySQL, PostgreSQL},
+		"NOW() is not available in SQLite, Oracle and SQL Server", "pass time.Now() as a parameter or use CURRENT_TIMESTAMP"},
+	{"sysdate", regexp.MustCompile(`(?i)\bSYSDATE\b(?:\s*\(\s*\))?`), []DriverType{Oracle, MySQL},
+		"SYSDATE is Oracle/MySQL specific", "pass time.Now() as a parameter or use CURRENT_TIMESTAMP"},
+	{"getdate", regexp.MustCompile(`(?i)\bGETDATE\s*\(\s*\)`), []DriverType{SQLServer},
+		"GETDATE() is SQL Server specific", "pass time.Now() as a parameter or use CURRENT_TIMESTAMP"},
+	{"ifnull", regexp.MustCompile(`(?i)\bIFNULL\s*\(`), []DriverType{MySQL, SQLite3},
+		"IFNULL is MySQL/SQLite specific", "use COALESCE"},
+	{"nvl", regexp.MustCompile(`(?i)\bNVL\s*\(`), []DriverType{Oracle},
+		"NVL is Oracle specific", "use COALESCE"},
+	{"isnull", regexp.MustCompile(`(?i)\bISNULL\s*\(`), []DriverType{SQLServer, MySQL},
+		"ISNULL() has different meanings across databases", "use COALESCE or IS NULL"},
+	{"concat-operator", regexp.MustCompile(`\|\|`), []DriverType{PostgreSQL, SQLite3, Oracle},
+		"|| means logical OR in MySQL and is not supported by SQL Server", "concatenate in application code or use CONCAT where available"},
+	{"ilike", regexp.MustCompile(`(?i)\bILIKE\b`), []DriverType{PostgreSQL},
+		"ILIKE is PostgreSQL specific", "use LOWER(column) LIKE LOWER(?)"},
+	{"cast-operator", regexp.MustCompile(`::\s*[A-Za-z_]\w*`), []DriverType{PostgreSQL},
+		":: casts are PostgreSQL specific", "use CAST(expr AS type)"},
+	{"on-duplicate-key", regexp.MustCompile(`(?i)\bON\s+DUPLICATE\s+KEY\s+UPDATE\b`), []DriverType{MySQL},
+		"ON DUPLICATE KEY UPDATE is MySQL specific", "use SaveRecord"},
+	{"on-conflict", regexp.MustCompile(`(?i)\bON\s+CONFLICT\b`), []DriverType{PostgreSQL, SQLite3},
+		"ON CONFLICT is PostgreSQL/SQLite specific", "use SaveRecord"},
+	{"insert-ignore", regexp.MustCompile(`(?i)\bINSERT\s+IGNORE\b`), []DriverType{MySQL},
+		"INSERT IGNORE is MySQL specific", "use SaveRecord or check existence first"},
+	{"replace-into", regexp.MustCompile(`(?i)\bREPLACE\s+INTO\b`), []DriverType{MySQL, SQLite3},
+		"REPLACE INTO is MySQL/SQLite specific", "use SaveRecord"},
+	{"returning", regexp.MustCompile(`(?i)\bRETURNING\b`), []DriverType{PostgreSQL, SQLite3},
+		"RETURNING is not portable", "read generated keys via LastInsertId or a follow-up query"},
+	{"from-dual", regexp.MustCompile(`(?i)\bFROM\s+DUAL\b`), []DriverType{Oracle, MySQL},
+		"FROM DUAL is Oracle/MySQL specific", "omit the FROM clause where supported or select from a real table"},
+	{"auto-increment", regexp.MustCompile(`(?i)\bAUTO_INCREMENT\b`), []DriverType{MySQL},
+		"AUTO_INCREMENT is MySQL specific", "keep DDL in per-dialect migrations"},
+}
+
+// lintStringLiteralRe 匹配字符串字面量和注释，检查前替换为空格以避免误报
+var lintStringLiteralRe = regexp.MustCompile(`'(?:[^']|'')*'|--[^\n]*|/\*[\s\S]*?\*/`)
+
+// LintSQL 检查原始 SQL 中目标方言不支持的写法（LIMIT 与 TOP、反引号引用、NOW() 与 SYSDATE 等），
+// targetDialects 为空时检查全部支持的数据库；字符串字面量和注释中的内容不参与检查
+// 示例:
+//
+//	for _, issue := range eorm.LintSQL("SELECT * FROM `users` WHERE created_at < NOW() LIMIT 10", eorm.MySQL, eorm.PostgreSQL) {
+//	    fmt.Println(issue)
+//	}
+func LintSQL(sql string, targetDialects ...DriverType) []LintIssue {
+	if len(targetDialects) == 0 {
+		targetDialects = allDialects
+	}
+	masked := lintStringLiteralRe.ReplaceAllStringFunc(sql, func(s string) string {
+		return strings.Repeat(" ", len(s))
+	})
+
+	var issues []LintIssue
+	for _, rule := range lintRules {
+		unsupported := unsupportedDialects(rule.supported, targetDialects)
+		if len(unsupported) == 0 {
+			continue
+		}
+		for _, loc := range rule.re.FindAllStringIndex(masked, -1) {
+			issues = append(issues, LintIssue{
+				Rule:        rule.name,
+				Construct:   sql[loc[0]:loc[1]],
+				Position:    loc[0],
+				Unsupported: unsupported,
+				Message:     rule.message,
+				Suggestion:  rule.suggestion,
+			})
+		}
+	}
+	sort.SliceStable(issues, func(i, j int) bool { return issues[i].Position < issues[j].Position })
+	return issues
+}
+
+// LintSqlTemplates 检查已加载的全部 SQL 模板（包括动态参数片段），Source 为模板完整名称
+func LintSqlTemplates(targetDialects ...DriverType) []LintIssue {
+	items := ListSqlItems()
+	names := make([]string, 0, len(items))
+	for name := range items {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var issues []LintIssue
+	for _, name := range names {
+		issues = append(issues, lintSqlItem(items[name], name, targetDialects)...)
+	}
+	return issues
+}
+
+// LintSqlConfigFile 检查 SQL 配置文件中的全部语句（不加载到模板管理器），Source 为 文件路径:模板名称
+// 可在 CI 中对模板目录逐个调用
+func LintSqlConfigFile(configPath string, targetDialects ...DriverType) ([]LintIssue, error) {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("eorm: failed to read sql config %s: %v", configPath, err)
+	}
+	var config SqlConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("eorm: failed to parse sql config %s: %v", configPath, err)
+	}
+	var issues []LintIssue
+	for i := range config.Sqls {
+		item := &config.Sqls[i]
+		if err := normalizeItemDialects(item); err != nil {
+			return nil, fmt.Errorf("eorm: invalid dialects of sql %s in %s: %v", item.Name, configPath, err)
+		}
+		issues = append(issues, lintSqlItem(item, configPath+":"+item.Name, targetDialects)...)
+	}
+	return issues, nil
+}
+
+// lintSqlItem 检查单个模板的主语句和动态参数片段，dialects 中的变体只按其所属数据库检查，
+// 主语句不再检查已提供变体的数据库
+func lintSqlItem(item *SqlItem, source string, targetDialects []DriverType) []LintIssue {
+	if item == nil {
+		return nil
+	}
+	if len(targetDialects) == 0 {
+		targetDialects = allDialects
+	}
+	var issues []LintIssue
+	var generic []DriverType
+	for _, d := range targetDialects {
+		if variant, ok := item.Dialects[string(d)]; ok {
+			issues = append(issues, LintSQL(variant, d)...)
+		} else {
+			generic = append(generic, d)
+		}
+	}
+	if len(generic) > 0 && strings.TrimSpace(item.SQL) != "" {
+		issues = append(issues, LintSQL(item.SQL, generic...)...)
+	}
+	for _, param := range item.InParam {
+		issues = append(issues, LintSQL(param.SQL, targetDialects...)...)
+	}
+	for i := range issues {
+		issues[i].Source = source
+	}
+	return issues
+}
+
+// unsupportedDialects 返回 targets 中不在 supported 内的数据库
+func unsupportedDialects(supported, targets []DriverType) []DriverType {
+	var result []DriverType
+	for _, t := range targets {
+		ok := false
+		for _, s := range supported {
+			if s == t {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			result = append(result, t)
+		}
+	}
+	return result
+}
@@ -0,0 +1,179 @@
+package eorm
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ReplayOptions Replay 的选项
+type ReplayOptions struct {
+	ReadOnly      bool               // 只重放 SELECT/WITH 查询，跳过写操作
+	Speed         float64            // 按原始时间间隔重放的速度倍数，1 为原速、2 为两倍速（0 表示不等待，尽快执行）
+	Databases     []string           // 只重放这些数据库捕获的语句（为空表示全部）
+	IncludeFailed bool               // 同时重放捕获时执行失败的语句（默认跳过）
+	MaxEntries    int                // 最多重放的语句数（0 表示不限制）
+	SlowestN      int                // 报告中保留变慢最多的语句数（默认 10）
+	OnResult      func(ReplayResult) // 每条语句执行后回调
+}
+
+// ReplayResult 一条语句的重放结果
+type ReplayResult struct {
+	Line     int             // 在捕获文件中的行号
+	Entry    SQLCaptureEntry // 捕获的记录
+	Duration time.Duration   // 重放耗时
+	Error    error           // 重放错误
+}
+
+// Ratio 重放耗时与捕获耗时之比，捕获耗时为 0 时返回 0
+func (r ReplayResult) Ratio() float64 {
+	if r.Entry.DurationMs <= 0 {
+		return 0
+	}
+	return float64(r.Duration.Microseconds()) / 1000 / r.Entry.DurationMs
+}
+
+// ReplayReport Replay 的汇总结果
+type ReplayReport struct {
+	Total            int            // 读取的记录数
+	Executed         int            // 执行的语句数
+	Skipped          int            // 跳过的记录数（过滤条件、只读模式或无法解析的行）
+	Failed           []ReplayResult // 重放失败的语句
+	OriginalDuration time.Duration  // 已执行语句的捕获耗时合计
+	ReplayDuration   time.Duration  // 已执行语句的重放耗时合计
+	Slowest          []ReplayResult // 相对捕获耗时变慢最多的语句
+}
+
+// Replay 读取 CaptureSQL 生成的 JSONL 文件，在目标数据库上按顺序重新执行捕获的语句，并比较耗时
+// 用于在预发布环境验证表结构变更、索引调整对真实负载的影响；写操作会真实执行，不要对生产库重放
+// 参数从 JSON 还原：整数值还原为 int64，时间为 RFC3339 文本
+// 示例:
+//
+//	report, err := eorm.Replay("/var/log/app/sql.jsonl", eorm.Use("staging"), &eorm.ReplayOptions{ReadOnly: true})
+//	log.Printf("executed=%d failed=%d original=%v replay=%v",
+//	    report.Executed, len(report.Failed), report.OriginalDuration, report.ReplayDuration)
+//	for _, r := range report.Slowest {
+//	    log.Printf("%.1fx %s", r.Ratio(), r.Entry.SQL)
+//	}
+func Replay(path string, target *DB, opts *ReplayOptions) (*ReplayReport, error) {
+	if target == nil || target.dbMgr == nil {
+		return nil, fmt.Errorf("eorm: Replay requires a target database")
+	}
+	if target.lastErr != nil {
+		return nil, target.lastErr
+	}
+	if opts == nil {
+		opts = &ReplayOptions{}
+	}
+	slowestN := opts.SlowestN
+	if slowestN <= 0 {
+		slowestN = 10
+	}
+	var databases map[string]bool
+	if len(opts.Databases) > 0 {
+		databases = make(map[string]bool, len(opts.Databases))
+		for _, name := range opts.Databases {
+			databases[name] = true
+		}
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("eorm: cannot open replay file: %w", err)
+	}
+	defer file.Close()
+
+	report := &ReplayReport{}
+	var results []ReplayResult
+	var firstCaptured, started time.Time
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64<<20)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		report.Total++
+		var entry SQLCaptureEntry
+		if err := json.Unmarshal([]byte(text), &entry); err != nil || entry.SQL == "" {
+			report.Skipped++
+			continue
+		}
+		if (databases != nil && !databases[entry.Database]) ||
+			(entry.Error != "" && !opts.IncludeFailed) ||
+			(opts.ReadOnly && !isReadOnlySQL(entry.SQL)) {
+			report.Skipped++
+			continue
+		}
+		if opts.MaxEntries > 0 && report.Executed >= opts.MaxEntries {
+			break
+		}
+
+		// 按捕获时的时间间隔等待
+		if opts.Speed > 0 {
+			if firstCaptured.IsZero() {
+				firstCaptured, started = entry.Time, time.Now()
+			} else if offset := entry.Time.Sub(firstCaptured); offset > 0 {
+				if wait := time.Duration(float64(offset)/opts.Speed) - time.Since(started); wait > 0 {
+					time.Sleep(wait)
+				}
+			}
+		}
+
+		result := ReplayResult{Line: line, Entry: entry}
+		args := replayArgs(entry.Args)
+		start := time.Now()
+		if isReadOnlySQL(entry.SQL) {
+			_, result.Error = target.Query(entry.SQL, args...)
+		} else {
+			_, result.Error = target.Exec(entry.SQL, args...)
+		}
+		result.Duration = time.Since(start)
+
+		report.Executed++
+		report.OriginalDuration += time.Duration(entry.DurationMs * float64(time.Millisecond))
+		report.ReplayDuration += result.Duration
+		if result.Error != nil {
+			report.Failed = append(report.Failed, result)
+		} else {
+			results = append(results, result)
+		}
+		if opts.OnResult != nil {
+			opts.OnResult(result)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return report, fmt.Errorf("eorm: failed to read replay file: %w", err)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Ratio() > results[j].Ratio() })
+	if len(results) > slowestN {
+		results = results[:slowestN]
+	}
+	report.Slowest = results
+	return report, nil
+}
+
+// isReadOnlySQL 判断语句是否为查询（SELECT 或 WITH 开头）
+func isReadOnlySQL(sql string) bool {
+	upper := strings.ToUpper(strings.TrimLeft(sql, " \t\r\n("))
+	return strings.HasPrefix(upper, "SELECT") || strings.HasPrefix(upper, "WITH")
+}
+
+// replayArgs 还原 JSON 解码后的参数：整数值的 float64 还原为 int64
+func replayArgs(args []interface{}) []interface{} {
+	for i, arg := range args {
+		if f, ok := arg.(float64); ok && f == math.Trunc(f) && math.Abs(f) < 1<<53 {
+			args[i] = int64(f)
+		}
+	}
+	return args
+}
@@ -0,0 +1,150 @@
+package eorm
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// stmtPreparer 可预编译语句的执行器（*sql.DB 或 *sql.Tx）
+type stmtPreparer interface {
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+}
+
+// ExecBatch 使用多组参数批量执行同一个写模板：模板只解析一次，相同的最终 SQL 只预编译一次并在各组参数间复用
+// inTransaction 为 true 时在一个事务中执行，任一组失败即停止并回滚；否则逐组执行，失败的组不影响后续组；
+// 通过 tx.SqlTemplate 创建时在该事务中执行，失败即停止（由调用方决定提交或回滚）
+// 返回每组参数的执行结果，存在失败时错误为 *BatchExecError
+// 示例:
+//
+//	results, err := eorm.SqlTemplate("user_service.insertUser").ExecBatch([]map[string]interface{}{
+//	    {"name": "alice", "age": 20},
+//	    {"name": "bob", "age": 22},
+//	}, true)
+func (b *SqlTemplateBuilder) ExecBatch(paramSets []map[string]interface{}, inTransaction ...bool) ([]StatementResult, error) {
+	sqlItem, err := b.resolveSqlItem()
+	if err != nil {
+		return nil, b.wrapTemplateError(err)
+	}
+	mgr := b.getDbManager()
+	if mgr == nil {
+		if b.dbName != "" {
+			return nil, fmt.Errorf("eorm: database '%s' not found", b.dbName)
+		}
+		_, err := defaultDB()
+		return nil, err
+	}
+	if len(paramSets) == 0 {
+		return nil, nil
+	}
+
+	ctx := contextOrBackground(b.ctx)
+	if b.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, b.timeout)
+		defer cancel()
+	}
+	if err := mgr.waitRateLimit(ctx); err != nil {
+		return nil, err
+	}
+
+	if b.tx != nil {
+		return b.execBatchOn(ctx, mgr, b.tx.tx, sqlItem, paramSets, true)
+	}
+	if len(inTransaction) > 0 && inTransaction[0] {
+		var results []StatementResult
+		db := &DB{dbMgr: mgr, ctx: b.ctx}
+		err := db.Transaction(func(tx *Tx) error {
+			var batchErr error
+			results, batchErr = b.execBatchOn(ctx, mgr, tx.tx, sqlItem, paramSets, true)
+			return batchErr
+		})
+		return results, err
+	}
+	sdb, err := mgr.getDB()
+	if err != nil {
+		return nil, err
+	}
+	return b.execBatchOn(ctx, mgr, sdb, sqlItem, paramSets, false)
+}
+
+// execBatchOn 在执行器上逐组执行模板，stopOnError 为 true 时遇到错误立即停止
+func (b *SqlTemplateBuilder) execBatchOn(ctx context.Context, mgr *dbManager, preparer stmtPreparer, sqlItem *SqlItem, paramSets []map[string]interface{}, stopOnError bool) ([]StatementResult, error) {
+	engine := getGlobalTemplateEngine()
+	stmts := make(map[string]*sql.Stmt)
+	defer func() {
+		for _, stmt := range stmts {
+			stmt.Close()
+		}
+	}()
+	// 非事务执行时遵循 SQLiteSerializeWrites 的串行写约束
+	executor, _ := preparer.(sqlExecutor)
+	_, serialize := preparer.(*sql.DB)
+	serialize = serialize && mgr.config.Load().Driver == SQLite3 && mgr.config.Load().SQLiteSerializeWrites
+
+	batchStart := time.Now()
+	results := make([]StatementResult, 0, len(paramSets))
+	failedCount := 0
+	for i, params := range paramSets {
+		finalSQL, args, err := engine.ProcessTemplate(sqlItem, params)
+		if err != nil {
+			results = append(results, StatementResult{Index: i, Error: b.wrapTemplateError(err)})
+			failedCount++
+			if stopOnError {
+				break
+			}
+			continue
+		}
+		if err = mgr.checkQuestionEscape(finalSQL); err != nil {
+			results = append(results, StatementResult{Index: i, SQL: finalSQL, Args: args, Error: err})
+			failedCount++
+			if stopOnError {
+				break
+			}
+			continue
+		}
+		finalSQL, args = mgr.injectRowFilters(ctx, finalSQL, args)
+		finalSQL, args = mgr.prepareExecSQL(ctx, finalSQL, args)
+
+		var result sql.Result
+		stmt, ok := stmts[finalSQL]
+		if !ok {
+			if stmt, err = preparer.PrepareContext(ctx, finalSQL); err == nil {
+				stmts[finalSQL] = stmt
+			}
+		}
+		if err == nil {
+			start := time.Now()
+			if serialize {
+				mgr.writeMu.Lock()
+			}
+			result, err = stmt.ExecContext(ctx, args...)
+			if serialize {
+				mgr.writeMu.Unlock()
+			}
+			mgr.logTrace(start, finalSQL, args, err)
+			mgr.afterSQLWrite(executor, finalSQL, err)
+		}
+
+		if err != nil {
+			results = append(results, StatementResult{Index: i, SQL: finalSQL, Args: args, Error: b.wrapExecError(err)})
+			failedCount++
+			if stopOnError {
+				break
+			}
+			continue
+		}
+		results = append(results, StatementResult{Index: i, SQL: finalSQL, Args: args, Result: result})
+	}
+
+	mgr.logTrace(batchStart, fmt.Sprintf("SqlTemplate ExecBatch[%s]: completed %d/%d parameter sets, %d failed, %d prepared statement(s)",
+		b.sqlName, len(results), len(paramSets), failedCount, len(stmts)), nil, nil)
+	if failedCount > 0 {
+		return results, &BatchExecError{
+			FailedCount: failedCount,
+			Message:     fmt.Sprintf("sql template %s batch exec completed with %d error(s)", b.sqlName, failedCount),
+		}
+	}
+	return results, nil
+}
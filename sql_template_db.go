@@ -0,0 +1,118 @@
+package eorm
+
+import (
+	"strings"
+	"sync"
+)
+
+// dbConfigManagers 按数据库名称或驱动名称隔离的 SQL 模板配置
+var dbConfigManagers = struct {
+	mu       sync.RWMutex
+	managers map[string]*SqlConfigManager
+}{managers: make(map[string]*SqlConfigManager)}
+
+// getDBConfigManager 返回数据库（或驱动）专属的配置管理器，create 为 true 时不存在则创建
+func getDBConfigManager(key string, create bool) *SqlConfigManager {
+	dbConfigManagers.mu.RLock()
+	mgr := dbConfigManagers.managers[key]
+	dbConfigManagers.mu.RUnlock()
+	if mgr != nil || !create {
+		return mgr
+	}
+	dbConfigManagers.mu.Lock()
+	defer dbConfigManagers.mu.Unlock()
+	if mgr = dbConfigManagers.managers[key]; mgr == nil {
+		mgr = NewSqlConfigManager()
+		dbConfigManagers.managers[key] = mgr
+	}
+	return mgr
+}
+
+// LoadSqlConfigForDB 为指定数据库加载 SQL 配置文件，同名模板覆盖全局模板
+// dbName 可以是 OpenDatabase 时的数据库名称，也可以是驱动名称（mysql/postgres/sqlite3/oracle/sqlserver），
+// 执行模板时按 数据库名称 → 驱动名称 → 全局 的顺序查找
+// 示例:
+//
+//	eorm.LoadSqlConfig("sql/user_service.json")                 // 全局模板
+//	eorm.LoadSqlConfigForDB("oracle", "sql/oracle/user_service.json") // Oracle 专用覆盖
+//	eorm.Use("report").SqlTemplate("user_service.findById", 1).QueryFirst()
+func LoadSqlConfigForDB(dbName, configPath string) error {
+	_, err := getDBConfigManager(dbName, true).LoadConfig(configPath)
+	return err
+}
+
+// LoadSqlConfigDirForDB 为指定数据库加载目录下的全部 JSON 配置文件，见 LoadSqlConfigForDB
+func LoadSqlConfigDirForDB(dbName, dirPath string) error {
+	return getDBConfigManager(dbName, true).LoadConfigDir(dirPath)
+}
+
+// ReloadAllSqlConfigsForDB 重新加载指定数据库的全部配置文件
+func ReloadAllSqlConfigsForDB(dbName string) error {
+	if mgr := getDBConfigManager(dbName, false); mgr != nil {
+		return mgr.ReloadAllConfigs()
+	}
+	return nil
+}
+
+// ListSqlItemsForDB 返回指定数据库专属的模板（不含全局模板）
+func ListSqlItemsForDB(dbName string) map[string]*SqlItem {
+	if mgr := getDBConfigManager(dbName, false); mgr != nil {
+		return mgr.ListSqlItems()
+	}
+	return map[string]*SqlItem{}
+}
+
+// dialectKey 方言专属模板在索引中的键
+func dialectKey(fullName, dialect string) string {
+	return fullName + "@" + strings.ToLower(strings.TrimSpace(dialect))
+}
+
+// itemDialects 解析模板的 dialect 字段（逗号分隔）
+func itemDialects(item *SqlItem) []string {
+	if strings.TrimSpace(item.Dialect) == "" {
+		return nil
+	}
+	var dialects []string
+	for _, d := range strings.Split(item.Dialect, ",") {
+		if d = strings.ToLower(strings.TrimSpace(d)); d != "" {
+			dialects = append(dialects, d)
+		}
+	}
+	return dialects
+}
+
+// getSqlItemForDriver 按驱动查找模板：先查找方言专属模板，再查找通用模板
+func (mgr *SqlConfigManager) getSqlItemForDriver(name string, driver DriverType) (*SqlItem, bool) {
+	mgr.mu.RLock()
+	defer mgr.mu.RUnlock()
+	if driver != "" {
+		if item, ok := mgr.sqlItems[dialectKey(name, string(driver))]; ok {
+			return item, true
+		}
+	}
+	item, ok := mgr.sqlItems[name]
+	return item, ok
+}
+
+// resolveSqlItem 按 数据库名称 → 驱动名称 → 全局 的顺序查找模板，并按驱动选择 dialects 中的 SQL 变体
+func (b *SqlTemplateBuilder) resolveSqlItem() (*SqlItem, error) {
+	var driver DriverType
+	if dbMgr := b.getDbManager(); dbMgr != nil {
+		driver = dbMgr.config.Load().Driver
+		for _, key := range []string{dbMgr.name, string(driver)} {
+			if mgr := getDBConfigManager(key, false); mgr != nil {
+				if item, ok := mgr.getSqlItemForDriver(b.sqlName, driver); ok {
+					return item.forDriver(driver)
+				}
+			}
+		}
+	}
+	if item, ok := b.configMgr.getSqlItemForDriver(b.sqlName, driver); ok {
+		return item.forDriver(driver)
+	}
+	item, err := b.configMgr.GetSqlItem(b.sqlName)
+	if err != nil {
+		return nil, err
+	}
+	return item.forDriver(driver)
+}
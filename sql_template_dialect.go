@@ -0,0 +1,72 @@
+package eorm
+
+import (
+	"fmt"
+	"strings"
+)
+
+// dialectAliases dialects 中允许的驱动名称别名
+var dialectAliases = map[string]DriverType{
+	"mysql":      MySQL,
+	"postgres":   PostgreSQL,
+	"postgresql": PostgreSQL,
+	"pg":         PostgreSQL,
+	"sqlite":     SQLite3,
+	"sqlite3":    SQLite3,
+	"oracle":     Oracle,
+	"sqlserver":  SQLServer,
+	"mssql":      SQLServer,
+}
+
+// normalizeItemDialects 将 dialects 的键规范化为驱动名称，并校验变体
+// 配置示例（同一个 id 下按数据库提供不同写法，未列出的数据库使用 sql）:
+//
+//	{
+//	  "name": "latestOrders",
+//	  "sql": "SELECT * FROM orders ORDER BY id DESC LIMIT ?",
+//	  "dialects": {
+//	    "oracle": "SELECT * FROM (SELECT * FROM orders ORDER BY id DESC) WHERE ROWNUM <= ?",
+//	    "sqlserver": "SELECT TOP (?) * FROM orders ORDER BY id DESC"
+//	  }
+//	}
+func normalizeItemDialects(item *SqlItem) error {
+	if len(item.Dialects) == 0 {
+		return nil
+	}
+	normalized := make(map[string]string, len(item.Dialects))
+	for key, variant := range item.Dialects {
+		driver, ok := dialectAliases[strings.ToLower(strings.TrimSpace(key))]
+		if !ok {
+			return fmt.Errorf("unknown dialect '%s'", key)
+		}
+		if strings.TrimSpace(variant) == "" {
+			return fmt.Errorf("empty sql for dialect '%s'", key)
+		}
+		if _, exists := normalized[string(driver)]; exists {
+			return fmt.Errorf("duplicate dialect '%s'", driver)
+		}
+		normalized[string(driver)] = variant
+	}
+	item.Dialects = normalized
+	return nil
+}
+
+// forDriver 返回按驱动选择 SQL 变体后的模板副本，没有对应变体时返回原模板
+func (item *SqlItem) forDriver(driver DriverType) (*SqlItem, error) {
+	if item == nil || len(item.Dialects) == 0 {
+		return item, nil
+	}
+	if variant, ok := item.Dialects[string(driver)]; ok {
+		selected := *item
+		selected.SQL = variant
+		return &selected, nil
+	}
+	if strings.TrimSpace(item.SQL) == "" {
+		return nil, &SqlConfigError{
+			Type:    "NotFoundError",
+			Message: fmt.Sprintf("SQL '%s' has no variant for dialect '%s'", item.FullName, driver),
+			SqlName: item.FullName,
+		}
+	}
+	return item, nil
+}
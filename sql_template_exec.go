@@ -0,0 +1,226 @@
+package eorm
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ErrorKind 数据库错误分类
+type ErrorKind int
+
+const (
+	ErrorKindUnknown      ErrorKind = iota // 未识别的错误
+	ErrorKindTemplate                      // SQL 模板不存在或参数错误
+	ErrorKindDuplicateKey                  // 唯一键/主键冲突
+	ErrorKindForeignKey                    // 外键约束冲突
+	ErrorKindNotNull                       // 非空约束冲突
+	ErrorKindCheck                         // CHECK 约束冲突
+	ErrorKindTransient                     // 可重试的瞬时错误（连接断开、死锁、锁等待超时等）
+	ErrorKindNoRows                        // 没有影响或返回任何行
+)
+
+// String 返回错误分类名称
+func (k ErrorKind) String() string {
+	switch k {
+	case ErrorKindTemplate:
+		return "template"
+	case ErrorKindDuplicateKey:
+		return "duplicate_key"
+	case ErrorKindForeignKey:
+		return "foreign_key"
+	case ErrorKindNotNull:
+		return "not_null"
+	case ErrorKindCheck:
+		return "check"
+	case ErrorKindTransient:
+		return "transient"
+	case ErrorKindNoRows:
+		return "no_rows"
+	default:
+		return "unknown"
+	}
+}
+
+// errorKindPatterns 各数据库约束错误信息中的关键字（小写）
+var errorKindPatterns = []struct {
+	kind     ErrorKind
+	patterns []string
+}{
+	{ErrorKindDuplicateKey, []string{"duplicate entry", "duplicate key", "unique constraint", "unique_violation", "ora-00001", "cannot insert duplicate key"}},
+	{ErrorKindForeignKey, []string{"foreign key constraint", "foreign_key_violation", "ora-02291", "ora-02292", "reference constraint"}},
+	{ErrorKindNotNull, []string{"cannot be null", "not null constraint", "not-null constraint", "null value in column", "ora-01400", "cannot insert the value null"}},
+	{ErrorKindCheck, []string{"check constraint", "ora-02290"}},
+}
+
+// ClassifyError 将数据库错误归类，便于业务代码区分唯一键冲突、外键冲突、可重试错误等
+// 示例:
+//
+//	if _, err := eorm.SqlTemplate("user.insert", params).ExecAffected(); eorm.ClassifyError(err) == eorm.ErrorKindDuplicateKey {
+//	    return ErrUserExists
+//	}
+func ClassifyError(err error) ErrorKind {
+	if err == nil {
+		return ErrorKindUnknown
+	}
+	var tplErr *SqlTemplateExecError
+	if errors.As(err, &tplErr) {
+		return tplErr.Kind
+	}
+	var cfgErr *SqlConfigError
+	if errors.As(err, &cfgErr) {
+		return ErrorKindTemplate
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrorKindNoRows
+	}
+	msg := strings.ToLower(err.Error())
+	for _, p := range errorKindPatterns {
+		for _, s := range p.patterns {
+			if strings.Contains(msg, s) {
+				return p.kind
+			}
+		}
+	}
+	if isTransientError(err) {
+		return ErrorKindTransient
+	}
+	return ErrorKindUnknown
+}
+
+// SqlTemplateExecError SQL 模板写操作失败的错误，包含模板名称和错误分类
+type SqlTemplateExecError struct {
+	SqlName string
+	Kind    ErrorKind
+	Err     error
+}
+
+func (e *SqlTemplateExecError) Error() string {
+	return fmt.Sprintf("eorm: sql template %s failed (%s): %v", e.SqlName, e.Kind, e.Err)
+}
+
+// Unwrap 返回原始错误
+func (e *SqlTemplateExecError) Unwrap() error {
+	return e.Err
+}
+
+// wrapExecError 包装模板执行错误并分类
+func (b *SqlTemplateBuilder) wrapExecError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var tplErr *SqlTemplateExecError
+	if errors.As(err, &tplErr) {
+		return err
+	}
+	return &SqlTemplateExecError{SqlName: b.sqlName, Kind: ClassifyError(err), Err: err}
+}
+
+// wrapTemplateError 包装模板解析/参数错误
+func (b *SqlTemplateBuilder) wrapTemplateError(err error) error {
+	return &SqlTemplateExecError{SqlName: b.sqlName, Kind: ErrorKindTemplate, Err: err}
+}
+
+// execWrapped 构建并执行模板，错误按阶段分类
+func (b *SqlTemplateBuilder) execWrapped() (sql.Result, error) {
+	finalSQL, args, err := b.buildFinalSQL()
+	if err != nil {
+		return nil, b.wrapTemplateError(err)
+	}
+	result, err := b.execSQL(finalSQL, args)
+	if err != nil {
+		return nil, b.wrapExecError(err)
+	}
+	return result, nil
+}
+
+// ExecAffected 执行 SQL 模板并返回受影响的行数，错误为 *SqlTemplateExecError
+// 示例: n, err := eorm.SqlTemplate("user.disable", id).ExecAffected()
+func (b *SqlTemplateBuilder) ExecAffected() (int64, error) {
+	result, err := b.execWrapped()
+	if err != nil {
+		return 0, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, b.wrapExecError(err)
+	}
+	return affected, nil
+}
+
+// ExecInsertedID 执行插入模板并返回自增主键（依赖驱动的 LastInsertId，MySQL/SQLite 可用），
+// PostgreSQL、Oracle、SQL Server 请在模板中使用 RETURNING/OUTPUT 并调用 ExecReturning
+func (b *SqlTemplateBuilder) ExecInsertedID() (int64, error) {
+	result, err := b.execWrapped()
+	if err != nil {
+		return 0, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, b.wrapExecError(fmt.Errorf("last insert id is not supported by this driver, use ExecReturning: %v", err))
+	}
+	return id, nil
+}
+
+// ExecReturning 执行带 RETURNING（PostgreSQL/SQLite）或 OUTPUT（SQL Server）子句的写模板，并把返回的行映射到 dest
+// dest 为结构体指针时映射第一行（没有返回行时错误分类为 ErrorKindNoRows），为切片指针时映射全部行
+// 示例:
+//
+//	var user User
+//	err := eorm.SqlTemplate("user.insertReturning", params).ExecReturning(&user)
+func (b *SqlTemplateBuilder) ExecReturning(dest interface{}) error {
+	finalSQL, args, err := b.buildFinalSQL()
+	if err != nil {
+		return b.wrapTemplateError(err)
+	}
+
+	var records []*Record
+	if b.tx != nil {
+		if b.timeout > 0 {
+			records, err = b.tx.Timeout(b.timeout).Query(finalSQL, args...)
+		} else {
+			records, err = b.tx.Query(finalSQL, args...)
+		}
+	} else if b.dbName != "" {
+		db := Use(b.dbName)
+		if db.lastErr != nil {
+			return b.wrapExecError(db.lastErr)
+		}
+		if b.timeout > 0 {
+			records, err = db.Timeout(b.timeout).Query(finalSQL, args...)
+		} else {
+			records, err = db.Query(finalSQL, args...)
+		}
+	} else {
+		if b.timeout > 0 {
+			records, err = Timeout(b.timeout).Query(finalSQL, args...)
+		} else {
+			records, err = Query(finalSQL, args...)
+		}
+	}
+	if err != nil {
+		return b.wrapExecError(err)
+	}
+	// 通过查询路径执行的写操作同样需要失效依赖该表的缓存
+	if mgr := b.getDbManager(); mgr != nil {
+		var executor sqlExecutor
+		if b.tx != nil {
+			executor = b.tx.tx
+		}
+		mgr.afterSQLWrite(executor, finalSQL, nil)
+	}
+
+	if dest == nil {
+		return nil
+	}
+	val := reflect.ValueOf(dest)
+	if val.Kind() == reflect.Ptr && val.Elem().Kind() == reflect.Slice {
+		return b.wrapExecError(ToStructs(records, dest))
+	}
+	if len(records) == 0 {
+		return b.wrapExecError(sql.ErrNoRows)
+	}
+	return b.wrapExecError(ToStruct(records[0], dest))
+}
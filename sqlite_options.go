@@ -0,0 +1,121 @@
+package eorm
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// sqliteDSN 将 Config 中的 SQLite 选项追加到 DSN 参数中（github.com/mattn/go-sqlite3 的参数格式）
+// 这些 PRAGMA 是连接级别的，通过 DSN 设置才能作用于连接池中的每个连接
+// DSN 中已显式设置的参数优先，不会被覆盖
+func sqliteDSN(config *Config) string {
+	dsn := config.DSN
+	var params []string
+	add := func(key, value string, aliases ...string) {
+		for _, k := range append([]string{key}, aliases...) {
+			if strings.Contains(dsn, k+"=") {
+				return
+			}
+		}
+		params = append(params, key+"="+value)
+	}
+
+	if config.SQLiteBusyTimeout > 0 {
+		add("_busy_timeout", fmt.Sprintf("%d", config.SQLiteBusyTimeout.Milliseconds()), "_timeout")
+	}
+	if config.SQLiteJournalMode != "" {
+		add("_journal_mode", strings.ToUpper(config.SQLiteJournalMode), "_journal")
+	}
+	if config.SQLiteForeignKeys {
+		add("_foreign_keys", "1", "_fk")
+	}
+	if config.SQLiteSerializeWrites {
+		// 事务开始时立即获取写锁，避免读事务升级为写事务时出现 SQLITE_BUSY
+		add("_txlock", "immediate")
+	}
+
+	if len(params) == 0 {
+		return dsn
+	}
+	sep := "?"
+	if strings.Contains(dsn, "?") {
+		sep = "&"
+	}
+	return dsn + sep + strings.Join(params, "&")
+}
+
+// serialWriteExecutor 串行化写操作的执行器（SQLiteSerializeWrites）
+// SQLite 同一时间只允许一个写入者，多个 goroutine 并发写入时由进程内互斥锁排队，而不是依赖 busy 重试
+// 查询直接透传，在 WAL 模式下读写可以并发
+// 事务不经过互斥锁：Begin 开启的事务依靠 _txlock=immediate 在开始时等待数据库写锁，
+// 因此事务与非事务写之间仍由 SQLite 的 busy_timeout 排队（建议同时设置 SQLiteBusyTimeout）
+type serialWriteExecutor struct {
+	db *sql.DB
+	mu *sync.Mutex
+}
+
+func (e *serialWriteExecutor) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return e.db.Query(query, args...)
+}
+
+func (e *serialWriteExecutor) QueryRow(query string, args ...interface{}) *sql.Row {
+	return e.db.QueryRow(query, args...)
+}
+
+func (e *serialWriteExecutor) Exec(query string, args ...interface{}) (sql.Result, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.db.Exec(query, args...)
+}
+
+func (e *serialWriteExecutor) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return e.db.QueryContext(ctx, query, args...)
+}
+
+func (e *serialWriteExecutor) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return e.db.QueryRowContext(ctx, query, args...)
+}
+
+func (e *serialWriteExecutor) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.db.ExecContext(ctx, query, args...)
+}
+
+// Begin 开始事务（配合 _txlock=immediate，事务在开始时等待写锁，不持有互斥锁）
+func (e *serialWriteExecutor) Begin() (*sql.Tx, error) {
+	return e.db.Begin()
+}
+
+// executorFor 返回非事务操作使用的执行器，启用 SQLiteSerializeWrites 时包装为串行写执行器
+func (mgr *dbManager) executorFor(sdb *sql.DB) SqlExecutor {
+	if mgr.config.Load().Driver == SQLite3 && mgr.config.Load().SQLiteSerializeWrites {
+		return &serialWriteExecutor{db: sdb, mu: &mgr.writeMu}
+	}
+	return sdb
+}
+
+// poolDB 返回执行器对应的连接池：*sql.DB 直接返回，串行写执行器返回其包装的 *sql.DB，事务等其他执行器返回 nil
+// 预编译语句缓存和准入控制按连接池判断，串行写执行器不应让它们失效
+func poolDB(executor sqlExecutor) *sql.DB {
+	switch e := executor.(type) {
+	case *sql.DB:
+		return e
+	case *serialWriteExecutor:
+		return e.db
+	}
+	return nil
+}
+
+// lockWrites 执行器为串行写执行器时获取写互斥锁，返回释放函数
+func lockWrites(executor sqlExecutor) func() {
+	e, ok := executor.(*serialWriteExecutor)
+	if !ok {
+		return func() {}
+	}
+	e.mu.Lock()
+	return e.mu.Unlock
+}
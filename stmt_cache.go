@@ -166,6 +166,21 @@ func (c *stmtCache) Delete(key string) {
 	}
 }
 
+// DeleteMatching 删除 SQL 满足 match 的缓存，返回删除数量
+func (c *stmtCache) DeleteMatching(match func(query string) bool) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	removed := 0
+	for key, entry := range c.items {
+		if match(entry.sql) {
+			c.removeEntry(key, entry)
+			removed++
+		}
+	}
+	return removed
+}
+
 // Clear 清空所有缓存
 func (c *stmtCache) Clear() {
 	c.mu.Lock()
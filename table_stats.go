@@ -0,0 +1,294 @@
+package eorm
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultTableStatsTable 采样写入的内部表
+const defaultTableStatsTable = "eorm_table_stats"
+
+// TableStatsSample 一张表在某一时刻的行数和占用空间
+type TableStatsSample struct {
+	Database  string    // 数据库名称
+	Table     string    // 表名
+	Rows      int64     // 行数
+	SizeBytes int64     // 数据和索引占用的字节数（SQLite 未编译 dbstat 时为 0）
+	Estimated bool      // 行数是否为统计信息中的估计值
+	SampledAt time.Time // 采样时间
+}
+
+// TableTrend 一张表的采样历史和增长速度
+type TableTrend struct {
+	Samples     []TableStatsSample // 按时间顺序的样本
+	RowsPerDay  float64            // 首尾样本之间平均每天增长的行数
+	BytesPerDay float64            // 首尾样本之间平均每天增长的字节数
+}
+
+// TableStatsOptions 表统计采集任务的选项
+type TableStatsOptions struct {
+	Tables      map[string][]string          // 数据库名 -> 需要采样的表
+	Interval    time.Duration                // 采样间隔（默认 1 小时）
+	ExactCount  bool                         // 使用 COUNT(*) 统计精确行数（默认读取数据库统计信息中的估计值，代价更低）
+	Sink        func(TableStatsSample) error // 自定义存储，如写入监控系统
+	StoreDB     string                       // 将样本写入该数据库的内部表（为空表示不写）
+	StoreTable  string                       // 内部表名（默认 eorm_table_stats，不存在时自动创建）
+	KeepSamples int                          // 内存中每张表保留的样本数，供 TrendFor 使用（默认 720）
+}
+
+// tableStatsCollector 全局的表统计采集任务
+var tableStatsCollector struct {
+	mu      sync.Mutex
+	stop    chan struct{}
+	opts    TableStatsOptions
+	samples map[string][]TableStatsSample // "数据库名.表名" -> 样本
+}
+
+// StartTableStats 启动表统计采集任务：立即采样一次，之后每隔 Interval 对配置的表采样行数和占用空间，
+// 样本交给 Sink、写入 StoreDB 的内部表，并在内存中保留最近的样本供 TrendFor 计算增长趋势
+// 再次调用会替换之前的任务
+// 示例:
+//
+//	eorm.StartTableStats(&eorm.TableStatsOptions{
+//	    Tables:   map[string][]string{"default": {"orders", "events"}},
+//	    Interval: time.Hour,
+//	    StoreDB:  "default",
+//	})
+//	trend := eorm.TrendFor("orders")
+//	log.Printf("orders grows %.0f rows/day", trend.RowsPerDay)
+func StartTableStats(opts *TableStatsOptions) error {
+	if opts == nil || len(opts.Tables) == 0 {
+		return fmt.Errorf("eorm: StartTableStats requires at least one table")
+	}
+	for dbName, tables := range opts.Tables {
+		for _, table := range tables {
+			if err := validateIdentifier(table); err != nil {
+				return fmt.Errorf("eorm: invalid table for database '%s': %w", dbName, err)
+			}
+		}
+	}
+	o := *opts
+	if o.Interval <= 0 {
+		o.Interval = time.Hour
+	}
+	if o.StoreTable == "" {
+		o.StoreTable = defaultTableStatsTable
+	}
+	if err := validateIdentifier(o.StoreTable); err != nil {
+		return err
+	}
+	if o.KeepSamples <= 0 {
+		o.KeepSamples = 720
+	}
+
+	StopTableStats()
+	c := &tableStatsCollector
+	c.mu.Lock()
+	stop := make(chan struct{})
+	c.stop = stop
+	c.opts = o
+	if c.samples == nil {
+		c.samples = make(map[string][]TableStatsSample)
+	}
+	c.mu.Unlock()
+
+	go runTableStats(o, stop)
+	return nil
+}
+
+// StopTableStats 停止表统计采集任务，内存中的样本保留
+func StopTableStats() {
+	c := &tableStatsCollector
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.stop != nil {
+		close(c.stop)
+		c.stop = nil
+	}
+}
+
+// TrendFor 返回表在内存中的采样历史和增长速度，dbName 省略时使用默认数据库
+func TrendFor(table string, dbName ...string) TableTrend {
+	name := GetCurrentDBName()
+	if len(dbName) > 0 && dbName[0] != "" {
+		name = dbName[0]
+	}
+	c := &tableStatsCollector
+	c.mu.Lock()
+	samples := append([]TableStatsSample(nil), c.samples[name+"."+strings.ToLower(table)]...)
+	c.mu.Unlock()
+
+	trend := TableTrend{Samples: samples}
+	if len(samples) >= 2 {
+		first, last := samples[0], samples[len(samples)-1]
+		if days := last.SampledAt.Sub(first.SampledAt).Hours() / 24; days > 0 {
+			trend.RowsPerDay = float64(last.Rows-first.Rows) / days
+			trend.BytesPerDay = float64(last.SizeBytes-first.SizeBytes) / days
+		}
+	}
+	return trend
+}
+
+// runTableStats 采集任务主循环
+func runTableStats(opts TableStatsOptions, stop chan struct{}) {
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+	for {
+		collectTableStats(opts)
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// collectTableStats 对所有配置的表采样一次
+func collectTableStats(opts TableStatsOptions) {
+	for dbName, tables := range opts.Tables {
+		db, err := UseWithError(dbName)
+		if err != nil {
+			LogWarn("表统计采样失败", NewRecord().Set("database", dbName).Set("error", err.Error()))
+			continue
+		}
+		for _, table := range tables {
+			sample, err := db.dbMgr.sampleTableStats(table, opts.ExactCount)
+			if err != nil {
+				LogWarn("表统计采样失败", NewRecord().
+					Set("database", dbName).
+					Set("table", table).
+					Set("error", err.Error()))
+				continue
+			}
+			recordTableStatsSample(sample, opts)
+		}
+	}
+}
+
+// recordTableStatsSample 保存样本到内存、Sink 和内部表
+func recordTableStatsSample(sample TableStatsSample, opts TableStatsOptions) {
+	c := &tableStatsCollector
+	key := sample.Database + "." + strings.ToLower(sample.Table)
+	c.mu.Lock()
+	samples := append(c.samples[key], sample)
+	if len(samples) > opts.KeepSamples {
+		samples = samples[len(samples)-opts.KeepSamples:]
+	}
+	c.samples[key] = samples
+	c.mu.Unlock()
+
+	if opts.Sink != nil {
+		if err := opts.Sink(sample); err != nil {
+			LogWarn("表统计样本写入失败", NewRecord().Set("table", sample.Table).Set("error", err.Error()))
+		}
+	}
+	if opts.StoreDB != "" {
+		if err := storeTableStatsSample(opts.StoreDB, opts.StoreTable, sample); err != nil {
+			LogWarn("表统计样本写入失败", NewRecord().Set("table", sample.Table).Set("error", err.Error()))
+		}
+	}
+}
+
+// storeTableStatsSample 将样本插入内部表，表不存在时创建后重试
+func storeTableStatsSample(dbName, storeTable string, sample TableStatsSample) error {
+	db, err := UseWithError(dbName)
+	if err != nil {
+		return err
+	}
+	record := NewRecord().
+		Set("db_name", sample.Database).
+		Set("table_name", sample.Table).
+		Set("row_count", sample.Rows).
+		Set("size_bytes", sample.SizeBytes).
+		Set("sampled_at", sample.SampledAt)
+	if _, err := db.InsertRecord(storeTable, record); err == nil {
+		return nil
+	}
+	var ddl string
+	switch db.dbMgr.config.Load().Driver {
+	case SQLServer:
+		ddl = "CREATE TABLE %s (db_name NVARCHAR(128), table_name NVARCHAR(128), row_count BIGINT, size_bytes BIGINT, sampled_at DATETIME2)"
+	case Oracle:
+		ddl = "CREATE TABLE %s (db_name VARCHAR2(128), table_name VARCHAR2(128), row_count NUMBER(19), size_bytes NUMBER(19), sampled_at TIMESTAMP)"
+	case PostgreSQL:
+		ddl = "CREATE TABLE IF NOT EXISTS %s (db_name VARCHAR(128), table_name VARCHAR(128), row_count BIGINT, size_bytes BIGINT, sampled_at TIMESTAMP)"
+	default:
+		ddl = "CREATE TABLE IF NOT EXISTS %s (db_name VARCHAR(128), table_name VARCHAR(128), row_count BIGINT, size_bytes BIGINT, sampled_at DATETIME)"
+	}
+	if _, err := db.Exec(fmt.Sprintf(ddl, storeTable)); err != nil {
+		return err
+	}
+	_, err = db.InsertRecord(storeTable, record)
+	return err
+}
+
+// SampleTableStats 立即采样默认数据库中一张表的行数和占用空间
+func SampleTableStats(table string, exactCount bool) (TableStatsSample, error) {
+	db, err := defaultDB()
+	if err != nil {
+		return TableStatsSample{}, err
+	}
+	return db.SampleTableStats(table, exactCount)
+}
+
+// SampleTableStats 立即采样一张表的行数和占用空间
+func (db *DB) SampleTableStats(table string, exactCount bool) (TableStatsSample, error) {
+	if db.lastErr != nil {
+		return TableStatsSample{}, db.lastErr
+	}
+	return db.dbMgr.sampleTableStats(table, exactCount)
+}
+
+// sampleTableStats 从数据库目录读取估计行数和占用空间；估计值不可用或 exactCount 为 true 时使用 COUNT(*)
+func (mgr *dbManager) sampleTableStats(table string, exactCount bool) (TableStatsSample, error) {
+	sample := TableStatsSample{Database: mgr.name, Table: table, SampledAt: time.Now()}
+	if err := validateIdentifier(table); err != nil {
+		return sample, err
+	}
+	db, err := mgr.getDB()
+	if err != nil {
+		return sample, err
+	}
+
+	var statsSQL string
+	switch mgr.config.Load().Driver {
+	case MySQL:
+		statsSQL = "SELECT TABLE_ROWS AS row_count, DATA_LENGTH + INDEX_LENGTH AS size_bytes FROM information_schema.TABLES WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ?"
+	case PostgreSQL:
+		statsSQL = "SELECT CAST(c.reltuples AS BIGINT) AS row_count, pg_total_relation_size(c.oid) AS size_bytes FROM pg_class c WHERE c.oid = to_regclass(?)"
+	case SQLServer:
+		statsSQL = "SELECT SUM(CASE WHEN index_id IN (0, 1) THEN row_count ELSE 0 END) AS row_count, SUM(reserved_page_count) * 8192 AS size_bytes FROM sys.dm_db_partition_stats WHERE object_id = OBJECT_ID(?)"
+	case Oracle:
+		statsSQL = "SELECT t.NUM_ROWS AS row_count, (SELECT SUM(s.BYTES) FROM USER_SEGMENTS s WHERE s.SEGMENT_NAME = t.TABLE_NAME) AS size_bytes FROM USER_TABLES t WHERE t.TABLE_NAME = UPPER(?)"
+	case SQLite3:
+		// dbstat 虚拟表需要 SQLITE_ENABLE_DBSTAT_VTAB，不可用时只统计行数
+		statsSQL = "SELECT NULL AS row_count, SUM(pgsize) AS size_bytes FROM dbstat WHERE name = ?"
+	}
+
+	estimated := int64(-1)
+	if statsSQL != "" {
+		if records, err := mgr.queryInternal(db, statsSQL, table); err == nil && len(records) > 0 {
+			sample.SizeBytes = records[0].GetInt64("size_bytes")
+			if records[0].Get("row_count") != nil {
+				estimated = records[0].GetInt64("row_count")
+			}
+		}
+	}
+
+	// PostgreSQL 未 ANALYZE 的表 reltuples 为 -1，Oracle 未收集统计信息时 NUM_ROWS 为 NULL
+	if !exactCount && estimated >= 0 {
+		sample.Rows = estimated
+		sample.Estimated = true
+		return sample, nil
+	}
+	records, err := mgr.queryInternal(db, fmt.Sprintf("SELECT COUNT(*) AS row_count FROM %s", table))
+	if err != nil {
+		return sample, err
+	}
+	if len(records) > 0 {
+		sample.Rows = records[0].GetInt64("row_count")
+	}
+	return sample, nil
+}
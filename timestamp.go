@@ -200,6 +200,11 @@ func (db *DB) HasTimestamps(table string) bool {
 	return db.dbMgr.hasTimestamps(table)
 }
 
+// HasTimestamps checks if a table has timestamps configured
+func (tx *Tx) HasTimestamps(table string) bool {
+	return tx.dbMgr.hasTimestamps(table)
+}
+
 // --- dbManager Methods ---
 
 // setTimestampConfig sets timestamp config for a table
@@ -0,0 +1,131 @@
+package eorm
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// singleOrderColumnRe 匹配单列排序（column [ASC|DESC]），用于不支持窗口函数时的相关子查询回退
+var singleOrderColumnRe = regexp.MustCompile(`(?i)^\s*([a-zA-Z_][a-zA-Z0-9_]*)\s*(ASC|DESC)?\s*$`)
+
+// supportsWindowFunctions MySQL 8.0 / MariaDB 10.2 之前不支持 ROW_NUMBER()，其他数据库均支持
+func (mgr *dbManager) supportsWindowFunctions() bool {
+	if mgr.config.Load().Driver != MySQL {
+		return true
+	}
+	return mgr.serverMajorVersion() >= 8
+}
+
+// topNPerGroup 查询每个分组的前 n 行
+func (mgr *dbManager) topNPerGroup(executor sqlExecutor, table, partitionColumn, orderBy string, n int, where string, whereArgs ...interface{}) (map[interface{}][]*Record, error) {
+	if err := validateIdentifier(table); err != nil {
+		return nil, err
+	}
+	if err := validateIdentifier(partitionColumn); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(orderBy) == "" {
+		return nil, fmt.Errorf("eorm: TopNPerGroup requires an order by clause")
+	}
+	if err := validateSafeSQL(orderBy); err != nil {
+		return nil, err
+	}
+	if n <= 0 {
+		return nil, fmt.Errorf("eorm: TopNPerGroup requires n > 0, got %d", n)
+	}
+
+	keyColumn := partitionColumn
+	if dot := strings.LastIndex(keyColumn, "."); dot != -1 {
+		keyColumn = keyColumn[dot+1:]
+	}
+
+	var querySQL string
+	var args []interface{}
+	switch m := singleOrderColumnRe.FindStringSubmatch(orderBy); {
+	case mgr.supportsWindowFunctions():
+		querySQL = fmt.Sprintf("SELECT eorm_t.*, ROW_NUMBER() OVER (PARTITION BY %s ORDER BY %s) AS %s FROM %s eorm_t",
+			partitionColumn, orderBy, paginationRowNumColumn, table)
+		if where != "" {
+			querySQL += " WHERE " + where
+		}
+		querySQL = fmt.Sprintf("SELECT * FROM (%s) eorm_ranked WHERE %s <= %d ORDER BY %s, %s",
+			querySQL, paginationRowNumColumn, n, partitionColumn, paginationRowNumColumn)
+		args = whereArgs
+	case m != nil:
+		// 相关子查询：排在当前行之前的同组行数小于 n（子查询中未限定的列属于内层表）
+		col, cmp := m[1], ">"
+		if !strings.EqualFold(m[2], "DESC") {
+			cmp = "<"
+		}
+		inner := fmt.Sprintf("SELECT COUNT(*) FROM %s eorm_t2 WHERE eorm_t2.%s = eorm_t.%s AND eorm_t2.%s %s eorm_t.%s",
+			table, keyColumn, keyColumn, col, cmp, col)
+		outerWhere := fmt.Sprintf("(%s) < %d", inner, n)
+		if where != "" {
+			inner += " AND (" + where + ")"
+			outerWhere = fmt.Sprintf("(%s) AND (%s) < %d", where, inner, n)
+			args = append(append([]interface{}{}, whereArgs...), whereArgs...)
+		}
+		querySQL = fmt.Sprintf("SELECT eorm_t.* FROM %s eorm_t WHERE %s ORDER BY eorm_t.%s, eorm_t.%s",
+			table, outerWhere, keyColumn, strings.TrimSpace(orderBy))
+	default:
+		// 多列排序：按分组和排序读取全部匹配行，在内存中截取
+		querySQL = fmt.Sprintf("SELECT * FROM %s", table)
+		if where != "" {
+			querySQL += " WHERE " + where
+		}
+		querySQL += fmt.Sprintf(" ORDER BY %s, %s", partitionColumn, orderBy)
+		args = whereArgs
+	}
+
+	records, err := mgr.query(executor, querySQL, args...)
+	if err != nil {
+		return nil, err
+	}
+	removePaginationRowNum(records)
+
+	result := make(map[interface{}][]*Record)
+	for _, r := range records {
+		key := r.Get(keyColumn)
+		// 并列的行可能使回退查询多返回几行，统一截取为 n 行
+		if len(result[key]) < n {
+			result[key] = append(result[key], r)
+		}
+	}
+	return result, nil
+}
+
+// TopNPerGroup 查询每个分组（partitionColumn）按 orderBy 排序的前 n 行，返回按分组值归类的记录
+// 支持窗口函数的数据库使用 ROW_NUMBER() OVER (PARTITION BY ...)；MySQL 5.7 等旧版本在单列排序时使用相关子查询，
+// 多列排序时读取全部匹配行后在内存中截取
+// 示例:
+//
+//	// 每个用户最近的 3 个订单
+//	latest, err := eorm.TopNPerGroup("orders", "user_id", "created_at DESC", 3, "status = ?", "paid")
+//	for userID, orders := range latest { ... }
+func TopNPerGroup(table, partitionColumn, orderBy string, n int, whereSql string, whereArgs ...interface{}) (map[interface{}][]*Record, error) {
+	db, err := defaultDB()
+	if err != nil {
+		return nil, err
+	}
+	return db.TopNPerGroup(table, partitionColumn, orderBy, n, whereSql, whereArgs...)
+}
+
+// TopNPerGroup 查询每个分组的前 n 行，见 TopNPerGroup
+func (db *DB) TopNPerGroup(table, partitionColumn, orderBy string, n int, whereSql string, whereArgs ...interface{}) (map[interface{}][]*Record, error) {
+	if db.lastErr != nil {
+		return nil, db.lastErr
+	}
+	whereSql, whereArgs = applyRowFilter(db.ctx, table, whereSql, whereArgs, false)
+	executor, err := db.getExecutor()
+	if err != nil {
+		return nil, err
+	}
+	return db.dbMgr.topNPerGroup(executor, table, partitionColumn, orderBy, n, whereSql, whereArgs...)
+}
+
+// TopNPerGroup 在事务中查询每个分组的前 n 行
+func (tx *Tx) TopNPerGroup(table, partitionColumn, orderBy string, n int, whereSql string, whereArgs ...interface{}) (map[interface{}][]*Record, error) {
+	whereSql, whereArgs = applyRowFilter(tx.ctx, table, whereSql, whereArgs, false)
+	return tx.dbMgr.topNPerGroup(tx.tx, table, partitionColumn, orderBy, n, whereSql, whereArgs...)
+}
@@ -0,0 +1,156 @@
+package eorm
+
+import "fmt"
+
+// treeFallbackChunkSize 逐层查询时每条 IN 语句最多携带的父节点数
+const treeFallbackChunkSize = 1000
+
+// WhereDescendantsOf 限定结果为邻接表（idCol/parentCol）中 rootID 的所有后代节点（不含 rootID 本身）
+// MySQL 8+/PostgreSQL/SQLite 使用 WITH RECURSIVE 子查询，Oracle 使用 CONNECT BY，
+// SQL Server 不支持在子查询中使用 CTE，改为构建时逐层查询后代 ID 再追加 IN 条件
+// 示例:
+//
+//	eorm.Table("categories").WhereDescendantsOf("categories", "id", "parent_id", 1).OrderBy("id").Find()
+func (qb *QueryBuilder) WhereDescendantsOf(table, idCol, parentCol string, rootID interface{}) *QueryBuilder {
+	if qb.lastErr != nil {
+		return qb
+	}
+	for _, name := range []string{table, idCol, parentCol} {
+		if err := validateIdentifier(name); err != nil {
+			qb.lastErr = err
+			return qb
+		}
+	}
+	if err := qb.validateQueryBuilderState(); err != nil {
+		qb.lastErr = err
+		return qb
+	}
+
+	var mgr *dbManager
+	var executor sqlExecutor
+	if qb.tx != nil {
+		mgr, executor = qb.tx.dbMgr, qb.tx.tx
+	} else {
+		exec, err := qb.db.getExecutor()
+		if err != nil {
+			qb.lastErr = err
+			return qb
+		}
+		mgr, executor = qb.db.dbMgr, exec
+	}
+
+	switch mgr.config.Load().Driver {
+	case MySQL, PostgreSQL, SQLite3:
+		// UNION 去重，数据中存在环时递归也能结束
+		qb.whereSql = append(qb.whereSql, fmt.Sprintf(
+			"%s IN (WITH RECURSIVE eorm_tree (eorm_id) AS (SELECT %s FROM %s WHERE %s = ? UNION SELECT c.%s FROM %s c INNER JOIN eorm_tree t ON c.%s = t.eorm_id) SELECT eorm_id FROM eorm_tree)",
+			idCol, idCol, table, parentCol, idCol, table, parentCol))
+		qb.whereArgs = append(qb.whereArgs, rootID)
+	case Oracle:
+		qb.whereSql = append(qb.whereSql, fmt.Sprintf(
+			"%s IN (SELECT %s FROM %s START WITH %s = ? CONNECT BY NOCYCLE PRIOR %s = %s)",
+			idCol, idCol, table, parentCol, idCol, parentCol))
+		qb.whereArgs = append(qb.whereArgs, rootID)
+	default:
+		ids, err := mgr.descendantIDs(executor, table, idCol, parentCol, rootID)
+		if err != nil {
+			qb.lastErr = err
+			return qb
+		}
+		if len(ids) == 0 {
+			qb.whereSql = append(qb.whereSql, "1 = 0")
+			return qb
+		}
+		qb.WhereInValues(idCol, ids)
+	}
+	return qb
+}
+
+// descendantIDs 逐层查询 rootID 的所有后代 ID（不支持递归子查询时的回退实现）
+func (mgr *dbManager) descendantIDs(executor sqlExecutor, table, idCol, parentCol string, rootID interface{}) ([]interface{}, error) {
+	visited := map[string]bool{treeKey(rootID): true}
+	frontier := []interface{}{rootID}
+	var ids []interface{}
+
+	for len(frontier) > 0 {
+		var next []interface{}
+		for start := 0; start < len(frontier); start += treeFallbackChunkSize {
+			end := start + treeFallbackChunkSize
+			if end > len(frontier) {
+				end = len(frontier)
+			}
+			querySQL := fmt.Sprintf("SELECT %s FROM %s WHERE %s IN (?)", idCol, table, parentCol)
+			records, err := mgr.query(executor, querySQL, frontier[start:end])
+			if err != nil {
+				return nil, err
+			}
+			for _, r := range records {
+				id := r.Get(idCol)
+				key := treeKey(id)
+				if id == nil || visited[key] {
+					continue
+				}
+				visited[key] = true
+				ids = append(ids, id)
+				next = append(next, id)
+			}
+		}
+		frontier = next
+	}
+	return ids, nil
+}
+
+// BuildTree 将邻接表的扁平查询结果组装为嵌套的 Record 树
+// 每个节点的子节点以 []*Record 存放在 childrenKey 字段（无子节点时为空切片）
+// parentCol 为 NULL 或父节点不在结果集中的记录作为根节点返回，顺序与输入一致
+// 示例:
+//
+//	rows, _ := eorm.Table("categories").OrderBy("sort").Find()
+//	roots := eorm.BuildTree(rows, "id", "parent_id", "children")
+func BuildTree(records []*Record, idCol, parentCol, childrenKey string) []*Record {
+	byID := make(map[string]bool, len(records))
+	for _, r := range records {
+		if r == nil {
+			continue
+		}
+		if id := r.Get(idCol); id != nil {
+			byID[treeKey(id)] = true
+		}
+	}
+
+	children := make(map[string][]*Record, len(records))
+	var roots []*Record
+	for _, r := range records {
+		if r == nil {
+			continue
+		}
+		parent := r.Get(parentCol)
+		if parent == nil || !byID[treeKey(parent)] {
+			roots = append(roots, r)
+			continue
+		}
+		key := treeKey(parent)
+		children[key] = append(children[key], r)
+	}
+
+	// 分组完成后再写入子节点，避免 Set 保存的切片在后续 append 中失效
+	for _, r := range records {
+		if r == nil {
+			continue
+		}
+		nodes := children[treeKey(r.Get(idCol))]
+		if nodes == nil {
+			nodes = []*Record{}
+		}
+		r.Set(childrenKey, nodes)
+	}
+	return roots
+}
+
+// treeKey 统一不同驱动返回的 ID 类型（int64/int32/[]byte/string 等）用于比较
+func treeKey(v interface{}) string {
+	if b, ok := v.([]byte); ok {
+		return string(b)
+	}
+	return fmt.Sprint(v)
+}
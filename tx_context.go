@@ -0,0 +1,73 @@
+package eorm
+
+import "context"
+
+// txContextKey 上下文中保存事务的键，按数据库区分
+type txContextKey struct {
+	mgr *dbManager
+}
+
+// ContextWithTx 返回携带事务的上下文，之后在该上下文中调用 RunInTransaction 会加入此事务
+func ContextWithTx(ctx context.Context, tx *Tx) context.Context {
+	if tx == nil {
+		return contextOrBackground(ctx)
+	}
+	return context.WithValue(contextOrBackground(ctx), txContextKey{mgr: tx.dbMgr}, tx)
+}
+
+// TxFromContext 返回上下文中默认数据库的事务，没有时返回 nil
+func TxFromContext(ctx context.Context) *Tx {
+	db, err := defaultDB()
+	if err != nil {
+		return nil
+	}
+	return db.TxFromContext(ctx)
+}
+
+// TxFromContext 返回上下文中当前数据库的事务，没有时返回 nil
+func (db *DB) TxFromContext(ctx context.Context) *Tx {
+	if ctx == nil || db.dbMgr == nil {
+		return nil
+	}
+	tx, _ := ctx.Value(txContextKey{mgr: db.dbMgr}).(*Tx)
+	return tx
+}
+
+// RunInTransaction 在默认数据库的事务中执行 fn：上下文中已有该数据库的事务时直接加入（由最外层决定提交或回滚），
+// 否则开启新事务，fn 返回错误或 panic 时回滚，成功时提交
+// fn 收到的上下文携带事务，传给其他服务方法即可组合，无需在函数签名中传递 *Tx
+// 示例:
+//
+//	func (s *OrderService) PlaceOrder(ctx context.Context, order *eorm.Record) error {
+//	    return eorm.RunInTransaction(ctx, func(ctx context.Context, tx *eorm.Tx) error {
+//	        if _, err := tx.InsertRecord("orders", order); err != nil {
+//	            return err
+//	        }
+//	        return s.inventory.Reserve(ctx, order) // 内部的 RunInTransaction 加入同一事务
+//	    })
+//	}
+func RunInTransaction(ctx context.Context, fn func(ctx context.Context, tx *Tx) error) error {
+	db, err := defaultDB()
+	if err != nil {
+		return err
+	}
+	return db.RunInTransaction(ctx, fn)
+}
+
+// RunInTransaction 在当前数据库的事务中执行 fn，见 RunInTransaction
+func (db *DB) RunInTransaction(ctx context.Context, fn func(ctx context.Context, tx *Tx) error) error {
+	if db.lastErr != nil {
+		return db.lastErr
+	}
+	ctx = contextOrBackground(ctx)
+	if tx := db.TxFromContext(ctx); tx != nil {
+		return fn(ctx, tx)
+	}
+	txDB := *db
+	txDB.ctx = ctx
+	return txDB.Transaction(func(tx *Tx) error {
+		txCtx := ContextWithTx(ctx, tx)
+		tx.ctx = txCtx
+		return fn(txCtx, tx)
+	})
+}
@@ -0,0 +1,138 @@
+package eorm
+
+import (
+	"database/sql"
+	"fmt"
+	"sync"
+)
+
+// txHooks 事务结束后执行的回调
+type txHooks struct {
+	mu            sync.Mutex
+	afterCommit   []func()
+	afterRollback []func()
+}
+
+// AfterCommit 注册事务成功提交后执行的回调，按注册顺序执行
+// 用于缓存失效、消息发布、WebSocket 推送等副作用，事务回滚时不会执行，避免回滚的数据产生“幽灵通知”
+// 回调在 Commit 返回前同步执行，回调中的 panic 会被记录日志而不影响提交结果
+// 示例:
+//
+//	eorm.Transaction(func(tx *eorm.Tx) error {
+//	    if _, err := tx.InsertRecord("orders", order); err != nil {
+//	        return err
+//	    }
+//	    tx.AfterCommit(func() { publisher.Publish("order.created", order) })
+//	    return nil
+//	})
+func (tx *Tx) AfterCommit(fn func()) *Tx {
+	if fn == nil {
+		return tx
+	}
+	h := tx.getHooks()
+	h.mu.Lock()
+	h.afterCommit = append(h.afterCommit, fn)
+	h.mu.Unlock()
+	return tx
+}
+
+// AfterRollback 注册事务回滚后执行的回调（包括 Transaction 中函数返回错误或 panic、提交失败），按注册顺序执行
+func (tx *Tx) AfterRollback(fn func()) *Tx {
+	if fn == nil {
+		return tx
+	}
+	h := tx.getHooks()
+	h.mu.Lock()
+	h.afterRollback = append(h.afterRollback, fn)
+	h.mu.Unlock()
+	return tx
+}
+
+// getHooks 返回事务的回调集合，首次使用时创建
+// eorm 开启的事务使用按 *sql.Tx 登记的集合，同一事务的多个 Tx 包装共享回调
+func (tx *Tx) getHooks() *txHooks {
+	if tx.hooks == nil {
+		if h := tx.dbMgr.lookupTxHooks(tx.tx); h != nil {
+			tx.hooks = h
+		} else {
+			tx.hooks = &txHooks{}
+		}
+	}
+	return tx.hooks
+}
+
+// runTxHooks 执行并清空事务结束回调，committed 表示事务已提交
+// 两组回调都会被清空，保证每个回调最多执行一次
+func (tx *Tx) runTxHooks(committed bool) {
+	h := tx.hooks
+	if shared := tx.dbMgr.releaseTxHooks(tx.tx); shared != nil {
+		h = shared
+	}
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	fns := h.afterRollback
+	if committed {
+		fns = h.afterCommit
+	}
+	h.afterCommit = nil
+	h.afterRollback = nil
+	h.mu.Unlock()
+
+	for _, fn := range fns {
+		runTxHook(fn, committed)
+	}
+}
+
+// runTxHook 执行单个回调并捕获 panic
+func runTxHook(fn func(), committed bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			LogError("事务回调异常", NewRecord().
+				Set("committed", committed).
+				Set("panic", fmt.Sprint(r)))
+		}
+	}()
+	fn()
+}
+
+// beginTxHooks 为 eorm 开启的事务登记回调集合，内部写操作可据此注册提交后的处理
+func (mgr *dbManager) beginTxHooks(tx *sql.Tx) {
+	mgr.txHooks.Store(tx, &txHooks{})
+}
+
+// lookupTxHooks 返回事务登记的回调集合，外部事务返回 nil
+func (mgr *dbManager) lookupTxHooks(tx *sql.Tx) *txHooks {
+	if mgr == nil || tx == nil {
+		return nil
+	}
+	if h, ok := mgr.txHooks.Load(tx); ok {
+		return h.(*txHooks)
+	}
+	return nil
+}
+
+// releaseTxHooks 事务结束时移除并返回登记的回调集合
+func (mgr *dbManager) releaseTxHooks(tx *sql.Tx) *txHooks {
+	if mgr == nil || tx == nil {
+		return nil
+	}
+	if h, ok := mgr.txHooks.LoadAndDelete(tx); ok {
+		return h.(*txHooks)
+	}
+	return nil
+}
+
+// afterCommit 在 eorm 开启的事务中执行时注册提交后的回调，事务外或外部事务中不做处理
+func (mgr *dbManager) afterCommit(executor sqlExecutor, fn func()) {
+	tx, ok := executor.(*sql.Tx)
+	if !ok {
+		return
+	}
+	if h := mgr.lookupTxHooks(tx); h != nil {
+		h.mu.Lock()
+		h.afterCommit = append(h.afterCommit, fn)
+		h.mu.Unlock()
+	}
+}
@@ -0,0 +1,19 @@
+package eorm
+
+import "testing"
+
+func TestTxHooksSharedAcrossWrappers(t *testing.T) {
+	db := openTestDB(t)
+	var ran int
+	err := db.Transaction(func(tx *Tx) error {
+		copied := &Tx{tx: tx.tx, dbMgr: tx.dbMgr}
+		copied.AfterCommit(func() { ran++ })
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Transaction: %v", err)
+	}
+	if ran != 1 {
+		t.Fatalf("AfterCommit registered on a copied Tx ran %d times, want 1", ran)
+	}
+}
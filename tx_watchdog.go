@@ -0,0 +1,123 @@
+package eorm
+
+import (
+	"context"
+	"database/sql"
+	"runtime"
+	"sync/atomic"
+	"time"
+)
+
+// TxWatchdogConfig 长事务看门狗配置
+type TxWatchdogConfig struct {
+	Threshold time.Duration // 事务持续时间超过该值时触发
+	Cancel    bool          // true 表示触发时取消（回滚）事务，false 只记录日志
+}
+
+// txWatchdog 单个事务的看门狗
+type txWatchdog struct {
+	timer     *time.Timer
+	cancel    context.CancelFunc
+	cancelled int32
+}
+
+// SetTransactionWatchdog 为默认数据库开启长事务看门狗
+// 事务（Transaction / BeginTransaction）从开启起超过 threshold 仍未提交或回滚时，以 Warn 级别记录日志，
+// 日志包含开启事务时的调用栈，用于发现在事务中等待外部慢调用的代码；cancel 为 true 时同时取消事务，
+// 数据库驱动会回滚该事务，之后的操作和 Commit 返回错误
+// threshold <= 0 关闭看门狗
+// 示例: eorm.SetTransactionWatchdog(5*time.Second)        // 只记录日志
+// 示例: eorm.SetTransactionWatchdog(30*time.Second, true) // 超时回滚
+func SetTransactionWatchdog(threshold time.Duration, cancel ...bool) {
+	db, err := defaultDB()
+	if err != nil {
+		return
+	}
+	db.SetTransactionWatchdog(threshold, cancel...)
+}
+
+// SetTransactionWatchdog 为当前数据库开启长事务看门狗，见 SetTransactionWatchdog
+func (db *DB) SetTransactionWatchdog(threshold time.Duration, cancel ...bool) *DB {
+	if db.lastErr != nil {
+		return db
+	}
+	db.dbMgr.mu.Lock()
+	defer db.dbMgr.mu.Unlock()
+	if threshold <= 0 {
+		db.dbMgr.txWatchdog = nil
+		return db
+	}
+	db.dbMgr.txWatchdog = &TxWatchdogConfig{
+		Threshold: threshold,
+		Cancel:    len(cancel) > 0 && cancel[0],
+	}
+	return db
+}
+
+// beginTx 开启事务并登记到变更事件总线，配置了看门狗时同时启动计时
+func (mgr *dbManager) beginTx(sdb *sql.DB) (*sql.Tx, *txWatchdog, error) {
+	mgr.mu.RLock()
+	config := mgr.txWatchdog
+	mgr.mu.RUnlock()
+	if config == nil {
+		tx, err := sdb.Begin()
+		if err != nil {
+			return nil, nil, err
+		}
+		mgr.beginChanges(tx)
+		mgr.beginTxHooks(tx)
+		return tx, nil, nil
+	}
+
+	stack := captureStack()
+	ctx, cancel := context.WithCancel(context.Background())
+	tx, err := sdb.BeginTx(ctx, nil)
+	if err != nil {
+		cancel()
+		return nil, nil, err
+	}
+
+	mgr.beginChanges(tx)
+	mgr.beginTxHooks(tx)
+	w := &txWatchdog{cancel: cancel}
+	started := time.Now()
+	w.timer = time.AfterFunc(config.Threshold, func() {
+		fields := NewRecord().
+			Set("db", mgr.name).
+			Set("threshold", config.Threshold.String()).
+			Set("elapsed", time.Since(started).String()).
+			Set("cancel", config.Cancel).
+			Set("stack", stack)
+		if config.Cancel {
+			atomic.StoreInt32(&w.cancelled, 1)
+			cancel()
+			LogWarn("长事务超时，已取消并回滚", fields)
+			return
+		}
+		LogWarn("长事务超时", fields)
+	})
+	return tx, w, nil
+}
+
+// stopWatchdog 停止事务的看门狗，返回事务是否已被看门狗取消
+func (tx *Tx) stopWatchdog() bool {
+	w := tx.watchdog
+	if w == nil {
+		return false
+	}
+	w.timer.Stop()
+	w.cancel()
+	return atomic.LoadInt32(&w.cancelled) == 1
+}
+
+// captureStack 返回当前 goroutine 的调用栈
+func captureStack() string {
+	buf := make([]byte, 4096)
+	for {
+		n := runtime.Stack(buf, false)
+		if n < len(buf) {
+			return string(buf[:n])
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
@@ -0,0 +1,49 @@
+package eorm
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTransactionWatchdog(t *testing.T) {
+	db := openTestDB(t, "CREATE TABLE wd_items (id INTEGER PRIMARY KEY)")
+	t.Cleanup(func() { db.SetTransactionWatchdog(0) })
+	slowInsert := func(id int) error {
+		return db.Transaction(func(tx *Tx) error {
+			if _, err := tx.Exec("INSERT INTO wd_items (id) VALUES (?)", id); err != nil {
+				return err
+			}
+			time.Sleep(100 * time.Millisecond)
+			return nil
+		})
+	}
+
+	db.SetTransactionWatchdog(20*time.Millisecond, true)
+	if err := slowInsert(1); err == nil || !strings.Contains(err.Error(), "watchdog") {
+		t.Fatalf("slow transaction with cancel: err = %v, want cancelled by watchdog", err)
+	}
+	if n := countRows(t, db, "wd_items", ""); n != 0 {
+		t.Fatalf("rows = %d, want 0: the cancelled transaction must be rolled back", n)
+	}
+
+	// 只记录日志时事务照常提交
+	db.SetTransactionWatchdog(20 * time.Millisecond)
+	if err := slowInsert(2); err != nil {
+		t.Fatalf("slow transaction without cancel: %v", err)
+	}
+	if n := countRows(t, db, "wd_items", ""); n != 1 {
+		t.Fatalf("rows = %d, want 1", n)
+	}
+
+	db.SetTransactionWatchdog(time.Minute, true)
+	if err := db.Transaction(func(tx *Tx) error {
+		_, err := tx.Exec("INSERT INTO wd_items (id) VALUES (?)", 3)
+		return err
+	}); err != nil {
+		t.Fatalf("fast transaction: %v", err)
+	}
+	if n := countRows(t, db, "wd_items", ""); n != 2 {
+		t.Fatalf("rows = %d, want 2", n)
+	}
+}
@@ -0,0 +1,121 @@
+package eorm
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// EnableTypeCoercion enables Record value coercion on write based on column types.
+// When enabled, Insert/Update/Save/BatchInsert/BatchUpdate convert basic values to the column type
+// using the cached table schema, e.g. Set("age", "25") is written as int64(25) and
+// Set("birthday", "2024-01-01") as time.Time, so every driver receives the same Go type.
+// Values that cannot be converted are written unchanged; pass strict=true to return an error instead.
+// Default is false (disabled).
+func EnableTypeCoercion(strict ...bool) {
+	mgr, err := defaultDB()
+	if err != nil {
+		return
+	}
+	mgr.EnableTypeCoercion(strict...)
+}
+
+// EnableTypeCoercion enables Record value coercion for this database instance.
+func (db *DB) EnableTypeCoercion(strict ...bool) *DB {
+	if db.lastErr != nil {
+		return db
+	}
+	db.dbMgr.mu.Lock()
+	defer db.dbMgr.mu.Unlock()
+	db.dbMgr.enableTypeCoercion = true
+	db.dbMgr.strictTypeCoercion = len(strict) > 0 && strict[0]
+	return db
+}
+
+// coerceRecordTypes converts record values to the Go type of their columns in place.
+// Only basic values (string, numbers, bool, time.Time) are converted; nil, Expr and custom types are left as-is.
+func (mgr *dbManager) coerceRecordTypes(table string, record *Record) error {
+	if !mgr.enableTypeCoercion || record == nil {
+		return nil
+	}
+	columns, err := mgr.getTableColumns(table)
+	if err != nil || len(columns) == 0 {
+		return nil
+	}
+
+	goTypes := make(map[string]string, len(columns))
+	for _, col := range columns {
+		goTypes[strings.ToLower(col.Name)] = dbTypeToGoType(col.Type, false, false)
+	}
+
+	for _, key := range record.Keys() {
+		goType, ok := goTypes[strings.ToLower(key)]
+		if !ok {
+			continue
+		}
+		value := record.Get(key)
+		if value == nil || !isBasicType(value) {
+			continue
+		}
+		converted, err := coerceValue(value, goType)
+		if err != nil {
+			if mgr.strictTypeCoercion {
+				return fmt.Errorf("eorm: cannot convert value %v (%T) for column '%s' on table %s to %s: %v", value, value, key, table, goType, err)
+			}
+			continue
+		}
+		record.Set(key, converted)
+	}
+	return nil
+}
+
+// coerceRecordsTypes coerces a batch of records, see coerceRecordTypes
+func (mgr *dbManager) coerceRecordsTypes(table string, records []*Record) error {
+	if !mgr.enableTypeCoercion {
+		return nil
+	}
+	for _, record := range records {
+		if err := mgr.coerceRecordTypes(table, record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// coerceValue converts a basic value to the Go type returned by dbTypeToGoType
+func coerceValue(value interface{}, goType string) (interface{}, error) {
+	switch goType {
+	case "int64":
+		switch value.(type) {
+		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32:
+			return value, nil
+		}
+		return Convert.ToInt64WithError(value)
+	case "float64":
+		switch value.(type) {
+		case float32, float64, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+			return value, nil
+		}
+		return Convert.ToFloat64WithError(value)
+	case "bool":
+		if _, ok := value.(bool); ok {
+			return value, nil
+		}
+		return Convert.ToBoolWithError(value)
+	case "time.Time":
+		if _, ok := value.(time.Time); ok {
+			return value, nil
+		}
+		if _, ok := value.(string); !ok {
+			return nil, fmt.Errorf("unsupported type %T", value)
+		}
+		return Convert.ToTimeWithError(value)
+	case "string":
+		switch value.(type) {
+		case string, time.Time:
+			return value, nil
+		}
+		return Convert.ToStringWithError(value)
+	}
+	return value, nil
+}
@@ -0,0 +1,392 @@
+package eorm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// unitOfWorkKey 上下文中保存 UnitOfWork 的键
+type unitOfWorkKey struct{}
+
+// ErrUnitOfWorkRowNotFound Flush 按主键更新或删除时没有匹配到行：行已被删除，或被写行级过滤器排除
+var ErrUnitOfWorkRowNotFound = errors.New("eorm: unit of work row not found")
+
+// UnitOfWork 请求级工作单元：维护身份映射（identity map）并累积待持久化的修改
+// 同一工作单元内，按主键指向同一行的 FindFirst 查询始终返回同一个 *Record 实例，重复的查询不会再访问数据库；
+// 对这些记录调用 Set 修改后，Flush 在一个事务中只更新发生变化的列
+// UnitOfWork 可在多个 goroutine 中使用，但返回的 *Record 是共享实例，并发修改需由调用方协调
+type UnitOfWork struct {
+	mu      sync.Mutex
+	queries map[string]string    // 查询键 -> 身份键
+	entries map[string]*uowEntry // 身份键 -> 记录
+	order   []string             // 身份键的登记顺序，Flush 按此顺序更新
+	inserts []uowChange          // 待插入记录
+	deletes []uowChange          // 待删除记录
+	pkCache map[string][]string  // table -> 主键列
+	ctx     context.Context      // 创建工作单元的上下文，Flush 在其中执行写操作（行级过滤器、限流键等）
+}
+
+// uowEntry 身份映射中的一条记录及其加载时的快照
+type uowEntry struct {
+	mgr      *dbManager
+	table    string
+	pks      []string
+	record   *Record
+	snapshot *Record
+}
+
+// uowChange 待插入或删除的记录
+type uowChange struct {
+	mgr    *dbManager
+	table  string
+	record *Record
+}
+
+// WithUnitOfWork 返回携带新工作单元的上下文，通常在每个请求开始时调用
+// 通过 WithContext(ctx) 执行的单表 FindFirst/QueryFirst 查询会使用该工作单元的身份映射
+// 示例:
+//
+//	ctx = eorm.WithUnitOfWork(ctx)
+//	u1, _ := eorm.WithContext(ctx).Table("users").Where("id = ?", 1).FindFirst()
+//	u2, _ := eorm.WithContext(ctx).Table("users").Where("id = ?", 1).FindFirst() // u2 == u1，不再查询
+//	u1.Set("name", "Tom")
+//	err := eorm.UnitOfWorkFromContext(ctx).Flush()
+func WithUnitOfWork(ctx context.Context) context.Context {
+	u := NewUnitOfWork()
+	ctx = context.WithValue(contextOrBackground(ctx), unitOfWorkKey{}, u)
+	u.ctx = ctx
+	return ctx
+}
+
+// UnitOfWorkFromContext 返回上下文中的工作单元，不存在时返回 nil
+func UnitOfWorkFromContext(ctx context.Context) *UnitOfWork {
+	if ctx == nil {
+		return nil
+	}
+	uow, _ := ctx.Value(unitOfWorkKey{}).(*UnitOfWork)
+	return uow
+}
+
+// NewUnitOfWork 创建一个空的工作单元，Flush 使用 context.Background()；需要行级过滤器等上下文时使用 WithUnitOfWork
+func NewUnitOfWork() *UnitOfWork {
+	return &UnitOfWork{
+		queries: make(map[string]string),
+		entries: make(map[string]*uowEntry),
+		pkCache: make(map[string][]string),
+	}
+}
+
+// RegisterNew 登记一条待插入的记录，Flush 时插入到默认数据库
+func (u *UnitOfWork) RegisterNew(table string, record *Record) *UnitOfWork {
+	return u.registerChange(&u.inserts, table, record)
+}
+
+// RegisterDeleted 登记一条待删除的记录（按主键删除，配置了软删除的表执行软删除），Flush 时删除
+func (u *UnitOfWork) RegisterDeleted(table string, record *Record) *UnitOfWork {
+	return u.registerChange(&u.deletes, table, record)
+}
+
+// registerChange 登记待插入或删除的记录，优先使用该记录在身份映射中所属的数据库
+func (u *UnitOfWork) registerChange(list *[]uowChange, table string, record *Record) *UnitOfWork {
+	if table == "" || record == nil {
+		return u
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	var mgr *dbManager
+	for _, e := range u.entries {
+		if e.record == record {
+			mgr = e.mgr
+			break
+		}
+	}
+	if mgr == nil {
+		mgr, _ = safeGetCurrentDB()
+	}
+	*list = append(*list, uowChange{mgr: mgr, table: table, record: record})
+	return u
+}
+
+// Clear 清空身份映射和所有待持久化的修改
+func (u *UnitOfWork) Clear() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.queries = make(map[string]string)
+	u.entries = make(map[string]*uowEntry)
+	u.order = nil
+	u.inserts = nil
+	u.deletes = nil
+}
+
+// Flush 在一个事务中持久化累积的修改：依次插入登记的新记录、更新身份映射中发生变化的列、删除登记的记录
+// 写操作使用创建工作单元时的上下文（见 WithUnitOfWork），按主键的更新或删除没有匹配到行时返回 ErrUnitOfWorkRowNotFound
+// 成功后快照更新为当前值，待插入和待删除列表被清空；失败时事务回滚，工作单元保持原状可重试
+// 涉及多个数据库时每个数据库各使用一个事务，某个数据库失败时，此前已提交的数据库的修改会从工作单元中移除，
+// 重试只会持久化失败及尚未处理的数据库的修改
+func (u *UnitOfWork) Flush() error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	var mgrs []*dbManager
+	seen := make(map[*dbManager]bool)
+	addMgr := func(mgr *dbManager) {
+		if mgr != nil && !seen[mgr] {
+			seen[mgr] = true
+			mgrs = append(mgrs, mgr)
+		}
+	}
+	for _, c := range u.inserts {
+		if c.mgr == nil {
+			return fmt.Errorf("eorm: unit of work has no database for table %s", c.table)
+		}
+		addMgr(c.mgr)
+	}
+	for _, key := range u.order {
+		addMgr(u.entries[key].mgr)
+	}
+	for _, c := range u.deletes {
+		if c.mgr == nil {
+			return fmt.Errorf("eorm: unit of work has no database for table %s", c.table)
+		}
+		addMgr(c.mgr)
+	}
+
+	for _, mgr := range mgrs {
+		if err := u.flushDB(mgr); err != nil {
+			return err
+		}
+		u.markFlushed(mgr)
+	}
+	return nil
+}
+
+// markFlushed 移除已提交到指定数据库的修改：更新快照、清除待插入和待删除记录（调用方持有 u.mu）
+func (u *UnitOfWork) markFlushed(mgr *dbManager) {
+	deleted := make(map[*Record]bool)
+	deletes := u.deletes[:0]
+	for _, c := range u.deletes {
+		if c.mgr == mgr {
+			deleted[c.record] = true
+			continue
+		}
+		deletes = append(deletes, c)
+	}
+	u.deletes = deletes
+
+	inserts := u.inserts[:0]
+	for _, c := range u.inserts {
+		if c.mgr != mgr {
+			inserts = append(inserts, c)
+		}
+	}
+	u.inserts = inserts
+
+	order := u.order[:0]
+	for _, key := range u.order {
+		e := u.entries[key]
+		if e.mgr == mgr {
+			if deleted[e.record] {
+				delete(u.entries, key)
+				continue
+			}
+			e.snapshot = e.record.Clone()
+		}
+		order = append(order, key)
+	}
+	u.order = order
+}
+
+// flushDB 在一个事务中持久化属于同一数据库的修改
+func (u *UnitOfWork) flushDB(mgr *dbManager) error {
+	db := &DB{dbMgr: mgr, ctx: u.ctx}
+	return db.Transaction(func(tx *Tx) error {
+		for _, c := range u.inserts {
+			if c.mgr != mgr {
+				continue
+			}
+			if _, err := tx.InsertRecord(c.table, c.record); err != nil {
+				return fmt.Errorf("eorm: unit of work insert into %s failed: %v", c.table, err)
+			}
+		}
+
+		for _, key := range u.order {
+			e := u.entries[key]
+			if e.mgr != mgr {
+				continue
+			}
+			changes := uowChangedColumns(e)
+			if changes.IsEmpty() {
+				continue
+			}
+			where, args := uowPrimaryKeyWhere(e.pks, e.snapshot)
+			n, err := tx.Update(e.table, changes, where, args...)
+			if err != nil {
+				return fmt.Errorf("eorm: unit of work update %s failed: %v", e.table, err)
+			}
+			if n == 0 {
+				return fmt.Errorf("%w: update %s where %s %v", ErrUnitOfWorkRowNotFound, e.table, where, args)
+			}
+		}
+
+		for _, c := range u.deletes {
+			if c.mgr != mgr {
+				continue
+			}
+			pks, err := u.primaryKeys(mgr, c.table)
+			if err != nil {
+				return err
+			}
+			where, args := uowPrimaryKeyWhere(pks, c.record)
+			n, err := tx.Delete(c.table, where, args...)
+			if err != nil {
+				return fmt.Errorf("eorm: unit of work delete from %s failed: %v", c.table, err)
+			}
+			if n == 0 {
+				return fmt.Errorf("%w: delete from %s where %s %v", ErrUnitOfWorkRowNotFound, c.table, where, args)
+			}
+		}
+		return nil
+	})
+}
+
+// uowChangedColumns 返回记录相对快照发生变化的列（主键列除外）
+func uowChangedColumns(e *uowEntry) *Record {
+	changes := NewRecord()
+	for _, col := range e.record.Keys() {
+		isPK := false
+		for _, pk := range e.pks {
+			if strings.EqualFold(pk, col) {
+				isPK = true
+				break
+			}
+		}
+		if isPK {
+			continue
+		}
+		value := e.record.Get(col)
+		if e.snapshot.Has(col) && reflect.DeepEqual(e.snapshot.Get(col), value) {
+			continue
+		}
+		changes.Set(col, value)
+	}
+	return changes
+}
+
+// uowPrimaryKeyWhere 构建按主键定位记录的条件
+func uowPrimaryKeyWhere(pks []string, record *Record) (string, []interface{}) {
+	conditions := make([]string, len(pks))
+	args := make([]interface{}, len(pks))
+	for i, pk := range pks {
+		conditions[i] = pk + " = ?"
+		args[i] = record.Get(pk)
+	}
+	return strings.Join(conditions, " AND "), args
+}
+
+// primaryKeys 返回表的主键列（调用方持有 u.mu）
+func (u *UnitOfWork) primaryKeys(mgr *dbManager, table string) ([]string, error) {
+	cacheKey := mgr.name + "\x00" + strings.ToLower(table)
+	if pks, ok := u.pkCache[cacheKey]; ok {
+		return pks, nil
+	}
+	sdb, err := mgr.getDB()
+	if err != nil {
+		return nil, err
+	}
+	pks, err := mgr.getPrimaryKeys(sdb, table)
+	if err != nil {
+		return nil, err
+	}
+	if len(pks) == 0 {
+		return nil, fmt.Errorf("eorm: unit of work requires a primary key on table %s", table)
+	}
+	u.pkCache[cacheKey] = pks
+	return pks, nil
+}
+
+// identityKey 返回记录的身份键，记录缺少主键列时返回空字符串
+func identityKey(mgr *dbManager, table string, pks []string, record *Record) string {
+	var sb strings.Builder
+	sb.WriteString(mgr.name)
+	sb.WriteByte(0)
+	sb.WriteString(strings.ToLower(table))
+	for _, pk := range pks {
+		if !record.Has(pk) || record.Get(pk) == nil {
+			return ""
+		}
+		sb.WriteByte(0)
+		sb.WriteString(treeKey(record.Get(pk)))
+	}
+	return sb.String()
+}
+
+// --- QueryBuilder integration ---
+
+// unitOfWork 返回查询可使用的工作单元：仅限事务外、通过 WithContext 携带工作单元的单表 SELECT * 查询
+func (qb *QueryBuilder) unitOfWork() *UnitOfWork {
+	if qb.tx != nil || qb.db == nil || qb.db.ctx == nil {
+		return nil
+	}
+	if qb.table == "" || qb.subqueryTable != nil || qb.asOfSQL != "" || len(qb.joins) > 0 || len(qb.selectSubqueries) > 0 || qb.groupBy != "" {
+		return nil
+	}
+	// 只查询部分列的记录不是完整实体，纳入身份映射会让后续完整查询拿到缺列的实例
+	if qb.selectSql != "" && qb.selectSql != "*" {
+		return nil
+	}
+	return UnitOfWorkFromContext(qb.db.ctx)
+}
+
+// queryFirst 通过身份映射执行 QueryFirst：相同查询直接返回已加载的实例，
+// 新加载的记录若主键已在映射中，返回映射中的实例
+func (u *UnitOfWork) queryFirst(qb *QueryBuilder, querySQL string, args []interface{}) (*Record, error) {
+	mgr := qb.db.dbMgr
+	queryKey := mgr.name + "\x00" + querySQL + "\x00" + fmt.Sprintf("%v", args)
+
+	u.mu.Lock()
+	if key, ok := u.queries[queryKey]; ok {
+		if e, ok := u.entries[key]; ok {
+			u.mu.Unlock()
+			return e.record, nil
+		}
+	}
+	u.mu.Unlock()
+
+	db := qb.db
+	if qb.timeout > 0 {
+		db = &DB{dbMgr: mgr, timeout: qb.timeout, ctx: qb.db.ctx}
+	}
+	record, err := db.QueryFirst(querySQL, args...)
+	if err != nil || record == nil {
+		return record, err
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	pks, err := u.primaryKeys(mgr, qb.table)
+	if err != nil {
+		// 无法确定主键时不纳入身份映射
+		return record, nil
+	}
+	key := identityKey(mgr, qb.table, pks, record)
+	if key == "" {
+		return record, nil
+	}
+	u.queries[queryKey] = key
+	if e, ok := u.entries[key]; ok {
+		return e.record, nil
+	}
+	u.entries[key] = &uowEntry{
+		mgr:      mgr,
+		table:    qb.table,
+		pks:      pks,
+		record:   record,
+		snapshot: record.Clone(),
+	}
+	u.order = append(u.order, key)
+	return record, nil
+}
@@ -0,0 +1,99 @@
+package eorm
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestUnitOfWorkFlushDropsCommittedChanges(t *testing.T) {
+	itemsDB := openNamedTestDB(t, t.Name()+"_items", "CREATE TABLE items (id INTEGER PRIMARY KEY, name TEXT)")
+	usersDB := openNamedTestDB(t, t.Name()+"_users", "CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT)")
+	mustExec(t, usersDB, "INSERT INTO users (id, name) VALUES (1, 'alice')")
+	if err := SetCurrentDB(itemsDB.dbMgr.name); err != nil {
+		t.Fatalf("SetCurrentDB: %v", err)
+	}
+
+	ctx := WithUnitOfWork(context.Background())
+	u := UnitOfWorkFromContext(ctx)
+	user, err := usersDB.WithContext(ctx).Table("users").Where("id = ?", 1).FindFirst()
+	if err != nil || user == nil {
+		t.Fatalf("FindFirst = %v, %v", user, err)
+	}
+	u.RegisterNew("items", NewRecord().Set("id", 1).Set("name", "pen"))
+	user.Set("missing_column", "x")
+
+	// items 所在数据库先提交，users 更新失败
+	if err := u.Flush(); err == nil {
+		t.Fatal("Flush succeeded, want update error")
+	}
+	if n := countRows(t, itemsDB, "items", ""); n != 1 {
+		t.Fatalf("items after failed flush = %d, want 1", n)
+	}
+
+	user.Remove("missing_column")
+	user.Set("name", "bob")
+	if err := u.Flush(); err != nil {
+		t.Fatalf("retry Flush: %v", err)
+	}
+	if n := countRows(t, itemsDB, "items", ""); n != 1 {
+		t.Fatalf("items after retry = %d, want 1", n)
+	}
+	if n := countRows(t, usersDB, "users", "name = ?", "bob"); n != 1 {
+		t.Fatalf("users named bob = %d, want 1", n)
+	}
+}
+
+func TestUnitOfWorkSkipsProjections(t *testing.T) {
+	db := openTestDB(t, "CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT)")
+	mustExec(t, db, "INSERT INTO users (id, name) VALUES (1, 'alice')")
+
+	ctx := WithUnitOfWork(context.Background())
+	partial, err := db.WithContext(ctx).Table("users").Select("id").Where("id = ?", 1).FindFirst()
+	if err != nil || partial == nil {
+		t.Fatalf("projected FindFirst = %v, %v", partial, err)
+	}
+	full, err := db.WithContext(ctx).Table("users").Where("id = ?", 1).FindFirst()
+	if err != nil || full == nil {
+		t.Fatalf("FindFirst = %v, %v", full, err)
+	}
+	if full == partial {
+		t.Fatal("full query returned the projected instance")
+	}
+	if full.GetString("name") != "alice" {
+		t.Fatalf("name = %q, want alice", full.GetString("name"))
+	}
+	again, err := db.WithContext(ctx).Table("users").Where("id = ?", 1).FindFirst()
+	if err != nil || again != full {
+		t.Fatalf("second FindFirst = %p, %v; want identity-mapped %p", again, err, full)
+	}
+}
+
+func TestUnitOfWorkFlushUsesContext(t *testing.T) {
+	db := openTestDB(t, "CREATE TABLE uow_orders (id INTEGER PRIMARY KEY, owner_id INTEGER, amount INTEGER)")
+	mustExec(t, db, "INSERT INTO uow_orders (id, owner_id, amount) VALUES (1, 1, 10), (2, 2, 20)")
+	registerOwnerFilter(t, "uow_orders")
+
+	ctx := WithUnitOfWork(context.WithValue(context.Background(), rowFilterUserKey{}, int64(1)))
+	u := UnitOfWorkFromContext(ctx)
+	order, err := Use(db.dbMgr.name).WithContext(ctx).Table("uow_orders").Where("id = ?", 1).FindFirst()
+	if err != nil || order == nil {
+		t.Fatalf("FindFirst = %v, %v", order, err)
+	}
+	order.Set("amount", 11)
+	if err := u.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if n := countRows(t, db, "uow_orders", "id = 1 AND amount = 11"); n != 1 {
+		t.Fatalf("updated rows = %d, want 1: Flush must run with the unit of work's context", n)
+	}
+
+	// 行级过滤器排除的行不会被删除，Flush 返回错误而不是静默成功
+	u.RegisterDeleted("uow_orders", NewRecord().Set("id", 2))
+	if err := u.Flush(); !errors.Is(err, ErrUnitOfWorkRowNotFound) {
+		t.Fatalf("Flush deleting a filtered row: err = %v, want ErrUnitOfWorkRowNotFound", err)
+	}
+	if n := countRows(t, db, "uow_orders", "id = 2"); n != 1 {
+		t.Fatalf("row 2 count = %d, want 1", n)
+	}
+}
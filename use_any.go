@@ -0,0 +1,59 @@
+package eorm
+
+import (
+	"fmt"
+	"strings"
+)
+
+// UseAny 按顺序返回第一个健康的数据库，用于读路径在主库抖动时自动降级到从库
+// 健康状态来自连接监控器（最近一次检查成功且没有连续失败）；未启用监控的数据库视为健康
+// 全部不健康时返回第一个已配置的数据库，都未配置时错误保存在返回的 DB 中
+// 示例:
+//
+//	records, err := eorm.UseAny("orders_primary", "orders_replica").Query("SELECT * FROM orders WHERE user_id = ?", userID)
+func UseAny(dbnames ...string) *DB {
+	db, err := UseAnyWithError(dbnames...)
+	if err != nil {
+		return &DB{lastErr: err}
+	}
+	return db
+}
+
+// UseAnyWithError 按顺序返回第一个健康的数据库，见 UseAny
+func UseAnyWithError(dbnames ...string) (*DB, error) {
+	if len(dbnames) == 0 {
+		return nil, fmt.Errorf("eorm: UseAny requires at least one database name")
+	}
+
+	var fallback *dbManager
+	for _, dbname := range dbnames {
+		multiMgr.mu.RLock()
+		dbMgr, exists := multiMgr.databases[dbname]
+		multiMgr.mu.RUnlock()
+		if !exists {
+			continue
+		}
+		if isMonitorHealthy(dbname) {
+			return &DB{dbMgr: dbMgr}, nil
+		}
+		if fallback == nil {
+			fallback = dbMgr
+		}
+	}
+
+	if fallback == nil {
+		return nil, fmt.Errorf("database '%s' not found", strings.Join(dbnames, "', '"))
+	}
+	return &DB{dbMgr: fallback}, nil
+}
+
+// isMonitorHealthy reports the monitor state of a database, databases without a monitor are treated as healthy
+func isMonitorHealthy(dbName string) bool {
+	monitor, err := lookupMonitor(dbName)
+	if err != nil {
+		return true
+	}
+	monitor.mu.RLock()
+	defer monitor.mu.RUnlock()
+	return monitor.lastHealthy && monitor.failures == 0
+}
@@ -0,0 +1,53 @@
+package eorm
+
+import "testing"
+
+// setMonitorHealth 为数据库注册一个不运行的监控器并设置其健康状态，测试结束时移除
+func setMonitorHealth(t *testing.T, dbName string, healthy bool) {
+	t.Helper()
+	monitor := &ConnectionMonitor{dbName: dbName, lastHealthy: healthy, stopCh: make(chan struct{})}
+	if !healthy {
+		monitor.failures = 1
+	}
+	monitorsMu.Lock()
+	monitors[dbName] = monitor
+	monitorsMu.Unlock()
+	t.Cleanup(func() {
+		monitorsMu.Lock()
+		if monitors[dbName] == monitor {
+			delete(monitors, dbName)
+		}
+		monitorsMu.Unlock()
+	})
+}
+
+func TestUseAnyPicksFirstHealthyDatabase(t *testing.T) {
+	primary := openNamedTestDB(t, "use_any_primary")
+	replica := openNamedTestDB(t, "use_any_replica")
+
+	// 未启用监控的数据库视为健康
+	if got := UseAny("use_any_missing", primary.dbMgr.name, replica.dbMgr.name); got.lastErr != nil || got.dbMgr != primary.dbMgr {
+		t.Fatalf("UseAny without monitors = %v (err %v), want the primary", got.dbMgr, got.lastErr)
+	}
+
+	setMonitorHealth(t, primary.dbMgr.name, false)
+	setMonitorHealth(t, replica.dbMgr.name, true)
+	if got := UseAny(primary.dbMgr.name, replica.dbMgr.name); got.dbMgr != replica.dbMgr {
+		t.Fatalf("UseAny with an unhealthy primary = %s, want the replica", got.dbMgr.name)
+	}
+
+	setMonitorHealth(t, replica.dbMgr.name, false)
+	if got := UseAny(primary.dbMgr.name, replica.dbMgr.name); got.dbMgr != primary.dbMgr {
+		t.Fatalf("UseAny with no healthy database = %s, want the first configured one", got.dbMgr.name)
+	}
+
+	if _, err := UseAnyWithError("use_any_missing", "use_any_other"); err == nil {
+		t.Fatal("UseAnyWithError with unknown databases: err = nil, want not found")
+	}
+	if _, err := UseAnyWithError(); err == nil {
+		t.Fatal("UseAnyWithError(): err = nil, want an error")
+	}
+	if got := UseAny("use_any_missing"); got.lastErr == nil {
+		t.Fatal("UseAny with an unknown database: lastErr = nil, want not found")
+	}
+}
@@ -67,6 +67,9 @@ func validateIdentifier(name string) error {
 	if name == "" {
 		return &ErrInvalidTableName{Name: name, Reason: "name cannot be empty"}
 	}
+	if identifierValidated(name) {
+		return nil
+	}
 
 	if len(name) > maxIdentifierLength {
 		return &ErrInvalidTableName{Name: name, Reason: fmt.Sprintf("name exceeds maximum length of %d characters", maxIdentifierLength)}
@@ -76,5 +79,6 @@ func validateIdentifier(name string) error {
 		return &ErrInvalidTableName{Name: name, Reason: "name contains invalid characters or format (only letters, numbers, underscores allowed; must start with letter or underscore; optional schema.table format)"}
 	}
 
+	rememberIdentifier(name)
 	return nil
 }
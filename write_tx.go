@@ -0,0 +1,26 @@
+package eorm
+
+// withWriteTx runs fn in a transaction opened by eorm when executor is a connection pool,
+// so that a write and its side effects (history rows, counter caches) commit together;
+// handled reports whether fn was run, executors that are already transactions are left to the caller.
+// The transaction is started through beginTx and tracked like a BeginTransaction transaction,
+// so the watchdog and leak detector cover it, and change events recorded in it are delivered on commit
+func withWriteTx[T any](mgr *dbManager, executor sqlExecutor, fn func(executor sqlExecutor) (T, error)) (result T, handled bool, err error) {
+	sdb := poolDB(executor)
+	if sdb == nil {
+		return result, false, nil
+	}
+	sqlTx, watchdog, err := mgr.beginTx(sdb)
+	if err != nil {
+		return result, true, err
+	}
+	tx := &Tx{tx: sqlTx, dbMgr: mgr, watchdog: watchdog}
+	trackTxLeak(tx)
+
+	result, err = fn(sqlTx)
+	if err != nil {
+		tx.Rollback()
+		return result, true, err
+	}
+	return result, true, tx.Commit()
+}
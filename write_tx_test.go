@@ -0,0 +1,48 @@
+package eorm
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWithWriteTx(t *testing.T) {
+	db := openTestDB(t, "CREATE TABLE wt_items (id INTEGER PRIMARY KEY, name TEXT)")
+	sdb, err := db.dbMgr.getDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	insert := func(executor sqlExecutor) (int64, error) {
+		_, err := executor.Exec("INSERT INTO wt_items (name) VALUES ('a')")
+		return 1, err
+	}
+
+	if _, handled, err := withWriteTx(db.dbMgr, sdb, insert); !handled || err != nil {
+		t.Fatalf("handled=%v err=%v", handled, err)
+	}
+	if n := countRows(t, db, "wt_items", ""); n != 1 {
+		t.Fatalf("committed rows = %d, want 1", n)
+	}
+
+	failure := errors.New("boom")
+	_, handled, err := withWriteTx(db.dbMgr, db.dbMgr.executorFor(sdb), func(executor sqlExecutor) (int64, error) {
+		insert(executor)
+		return 0, failure
+	})
+	if !handled || !errors.Is(err, failure) {
+		t.Fatalf("handled=%v err=%v", handled, err)
+	}
+	if n := countRows(t, db, "wt_items", ""); n != 1 {
+		t.Fatalf("rows after rollback = %d, want 1", n)
+	}
+
+	err = db.Transaction(func(tx *Tx) error {
+		_, handled, _ := withWriteTx(db.dbMgr, tx.tx, insert)
+		if handled {
+			t.Fatalf("a transaction executor must be left to the caller")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}